@@ -14,6 +14,7 @@ import (
 	log "github.com/annymsMthd/industry-tool/internal/logging"
 	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/annymsMthd/industry-tool/internal/runners"
+	"github.com/annymsMthd/industry-tool/internal/services"
 	"github.com/annymsMthd/industry-tool/internal/updaters"
 	"github.com/annymsMthd/industry-tool/internal/web"
 
@@ -60,16 +61,23 @@ var rootCmd = &cobra.Command{
 		constellationsRepository := repositories.NewConstellations(db)
 		systemRepository := repositories.NewSolarSystems(db)
 		stationsRepository := repositories.NewStations(db)
+		stargatesRepository := repositories.NewStargates(db)
 		usersRepository := repositories.NewUserRepository(db)
 		assetsRepository := repositories.NewAssets(db)
 		playerCorporationRepostiory := repositories.NewPlayerCorporations(db)
 		playerCorporationAssetsRepository := repositories.NewCorporationAssets(db)
 		stockpileMarkersRepository := repositories.NewStockpileMarkers(db)
+		stockpileTemplatesRepository := repositories.NewStockpileTemplates(db)
 		marketPricesRepository := repositories.NewMarketPrices(db)
 		contactsRepository := repositories.NewContacts(db)
 		contactPermissionsRepository := repositories.NewContactPermissions(db)
 		forSaleItemsRepository := repositories.NewForSaleItems(db)
 		purchaseTransactionsRepository := repositories.NewPurchaseTransactions(db)
+		purchaseCartRepository := repositories.NewPurchaseCart(db)
+		purchaseOrdersRepository := repositories.NewPurchaseOrders(db)
+		purchaseSlaSettingsRepository := repositories.NewPurchaseSlaSettings(db)
+		purchaseOffersRepository := repositories.NewPurchaseOffers(db)
+		tradeTrustRepository := repositories.NewTradeTrust(db)
 		buyOrdersRepository := repositories.NewBuyOrders(db)
 		salesAnalyticsRepository := repositories.NewSalesAnalytics(db)
 		sdeDataRepository := repositories.NewSdeDataRepository(db)
@@ -83,6 +91,7 @@ var rootCmd = &cobra.Command{
 		jobQueueRepository := repositories.NewJobQueue(db)
 		productionPlansRepository := repositories.NewProductionPlans(db)
 		planRunsRepository := repositories.NewPlanRuns(db)
+		materialReservationsRepository := repositories.NewMaterialReservations(db)
 
 		var esiClient *client.EsiClient
 		if settings.EsiBaseURL != "" {
@@ -93,28 +102,38 @@ var rootCmd = &cobra.Command{
 
 		sdeClient := client.NewSdeClient(&http.Client{})
 
+		// Gate routes come from the SDE stargate graph; ESI only answers until stargates are imported
+		gateRouter := services.NewGateRouter(stargatesRepository, esiClient)
+
 		contactRulesRepository := repositories.NewContactRules(db)
+		contactGroupsRepository := repositories.NewContactGroups(db)
 		autoSellContainersRepository := repositories.NewAutoSellContainers(db)
 		discordNotificationsRepository := repositories.NewDiscordNotifications(db)
 
 		assetUpdater := updaters.NewAssets(charactersAssetRepository, charactersRepository, stationsRepository, playerCorporationRepostiory, playerCorporationAssetsRepository, esiClient, usersRepository, settings.AssetUpdateConcurrency)
-		sdeUpdater := updaters.NewSde(sdeClient, esiClient, sdeDataRepository, itemTypesRepository, regionsRepository, constellationsRepository, systemRepository, stationsRepository)
+		sdeUpdater := updaters.NewSde(sdeClient, esiClient, sdeDataRepository, itemTypesRepository, regionsRepository, constellationsRepository, systemRepository, stationsRepository, stargatesRepository)
 		marketPricesUpdater := updaters.NewMarketPrices(marketPricesRepository, esiClient)
 		ccpPricesUpdater := updaters.NewCcpPrices(esiClient, marketPricesRepository)
 		costIndicesUpdater := updaters.NewIndustryCostIndices(esiClient, industryCostIndicesRepository)
 		autoSellUpdater := updaters.NewAutoSell(autoSellContainersRepository, forSaleItemsRepository, marketPricesRepository, stockpileMarkersRepository, purchaseTransactionsRepository)
+		autoSellUpdater.WithReservationsRepository(materialReservationsRepository)
+		autoSellUpdater.WithTradeSkillsRepository(characterSkillsRepository)
 		contactRulesUpdater := updaters.NewContactRules(contactsRepository, contactRulesRepository, contactPermissionsRepository, db)
 
 		// Discord integration (optional — only enabled when DISCORD_BOT_TOKEN is set)
 		var discordClient *client.DiscordClient
 		var purchaseNotifier controllers.PurchaseNotifierInterface
 		var contractCreatedNotifier controllers.ContractCreatedNotifierInterface
+		var purchaseOrderNotifier controllers.PurchaseOrderNotifierInterface
+		var purchaseOfferNotifier controllers.PurchaseOfferNotifierInterface
 		var notificationsUpdater *updaters.NotificationsUpdater
 		if settings.DiscordBotToken != "" {
 			discordClient = client.NewDiscordClient(settings.DiscordBotToken)
 			notificationsUpdater = updaters.NewNotifications(discordNotificationsRepository, discordClient, settings.FrontendURL)
 			purchaseNotifier = notificationsUpdater
 			contractCreatedNotifier = notificationsUpdater
+			purchaseOrderNotifier = notificationsUpdater
+			purchaseOfferNotifier = notificationsUpdater
 			log.Info("discord notifications enabled")
 		} else {
 			log.Info("discord notifications disabled (no DISCORD_BOT_TOKEN)")
@@ -123,6 +142,8 @@ var rootCmd = &cobra.Command{
 		autoBuyConfigsRepository := repositories.NewAutoBuyConfigs(db)
 		autoBuyUpdater := updaters.NewAutoBuy(autoBuyConfigsRepository, buyOrdersRepository, marketPricesRepository, purchaseTransactionsRepository)
 		autoFulfillUpdater := updaters.NewAutoFulfill(db, buyOrdersRepository, forSaleItemsRepository, purchaseTransactionsRepository, contactPermissionsRepository, usersRepository, purchaseNotifier)
+		autoFulfillUpdater.WithReservationsRepository(materialReservationsRepository)
+		autoFulfillUpdater.WithTrustRepository(tradeTrustRepository)
 
 		characterSkillsUpdater := updaters.NewCharacterSkillsUpdater(usersRepository, charactersRepository, characterSkillsRepository, esiClient)
 		characterBlueprintsUpdater := updaters.NewCharacterBlueprintsUpdater(usersRepository, charactersRepository, playerCorporationRepostiory, characterBlueprintsRepository, esiClient)
@@ -139,27 +160,35 @@ var rootCmd = &cobra.Command{
 		marketPricesUpdater.WithAutoBuyUpdater(autoBuyUpdater)
 		assetUpdater.WithAutoFulfillUpdater(autoFulfillUpdater)
 		marketPricesUpdater.WithAutoFulfillUpdater(autoFulfillUpdater)
+		assetUpdater.WithAssetSettingsRepository(usersRepository)
 
 		controllers.NewStatic(router, sdeUpdater)
 		controllers.NewCharacters(router, charactersRepository, assetUpdater, esiClient, contactRulesUpdater)
-		controllers.NewUsers(router, usersRepository, usersRepository)
+		controllers.NewUsers(router, usersRepository, usersRepository, usersRepository)
 		controllers.NewAssets(router, assetsRepository)
 		controllers.NewCorporations(router, esiClient, playerCorporationRepostiory, assetUpdater, contactRulesUpdater)
 		controllers.NewStockpileMarkers(router, stockpileMarkersRepository)
+		controllers.NewStockpileConsumption(router, stockpileMarkersRepository)
+		controllers.NewStockpileTemplates(router, stockpileTemplatesRepository)
 		controllers.NewStockpiles(router, assetsRepository)
 		controllers.NewMarketPrices(router, marketPricesUpdater)
 		controllers.NewJanice(router)
 		controllers.NewContacts(router, contactsRepository, contactPermissionsRepository, db)
 		controllers.NewContactPermissions(router, contactPermissionsRepository)
-		controllers.NewForSaleItems(router, forSaleItemsRepository, contactPermissionsRepository)
+		controllers.NewForSaleItems(router, forSaleItemsRepository, contactPermissionsRepository, tradeTrustRepository, characterBlueprintsRepository)
 		controllers.NewPurchases(router, db, purchaseTransactionsRepository, forSaleItemsRepository, contactPermissionsRepository, usersRepository, purchaseNotifier, contractCreatedNotifier)
-		controllers.NewBuyOrders(router, buyOrdersRepository, contactPermissionsRepository, autoFulfillUpdater)
+		controllers.NewPurchaseCart(router, db, purchaseCartRepository, purchaseOrdersRepository, purchaseTransactionsRepository, forSaleItemsRepository, contactPermissionsRepository, usersRepository, purchaseOrderNotifier)
+		controllers.NewPurchaseSla(router, purchaseSlaSettingsRepository)
+		controllers.NewTradeTrust(router, tradeTrustRepository, purchaseTransactionsRepository, contactGroupsRepository)
+		controllers.NewPurchaseOffers(router, db, purchaseOffersRepository, purchaseTransactionsRepository, forSaleItemsRepository, buyOrdersRepository, contactPermissionsRepository, purchaseOfferNotifier)
+		controllers.NewBuyOrders(router, buyOrdersRepository, contactPermissionsRepository, autoFulfillUpdater, tradeTrustRepository)
 		controllers.NewItemTypes(router, itemTypesRepository)
 		controllers.NewAnalytics(router, salesAnalyticsRepository)
 		controllers.NewAutoSellContainers(router, autoSellContainersRepository, autoSellUpdater, forSaleItemsRepository)
 		controllers.NewAutoBuyConfigs(router, autoBuyConfigsRepository, autoBuyUpdater, buyOrdersRepository, autoFulfillUpdater)
 		controllers.NewReactions(router, sdeDataRepository, marketPricesRepository, industryCostIndicesRepository)
 		controllers.NewContactRules(router, contactRulesRepository, contactRulesUpdater)
+		controllers.NewContactGroups(router, contactGroupsRepository)
 		if discordClient != nil {
 			controllers.NewDiscordNotifications(router, discordNotificationsRepository, discordClient, notificationsUpdater)
 		}
@@ -170,16 +199,20 @@ var rootCmd = &cobra.Command{
 		jfRoutesRepo := repositories.NewJFRoutes(db)
 		transportJobsRepo := repositories.NewTransportJobs(db)
 		triggerConfigRepo := repositories.NewTransportTriggerConfig(db)
-		controllers.NewProductionPlans(router, productionPlansRepository, sdeDataRepository, jobQueueRepository, marketPricesRepository, industryCostIndicesRepository, charactersRepository, playerCorporationRepostiory, userStationsRepository, planRunsRepository, transportJobsRepo, transportProfilesRepo, jfRoutesRepo, esiClient, characterSkillsRepository)
+		controllers.NewProductionPlans(router, productionPlansRepository, sdeDataRepository, jobQueueRepository, marketPricesRepository, industryCostIndicesRepository, charactersRepository, playerCorporationRepostiory, userStationsRepository, planRunsRepository, transportJobsRepo, transportProfilesRepo, jfRoutesRepo, gateRouter, characterSkillsRepository)
 		controllers.NewUserStations(router, userStationsRepository)
 
-		controllers.NewTransportation(router, transportProfilesRepo, jfRoutesRepo, transportJobsRepo, triggerConfigRepo, jobQueueRepository, marketPricesRepository, systemRepository, esiClient)
+		controllers.NewTransportation(router, transportProfilesRepo, jfRoutesRepo, transportJobsRepo, triggerConfigRepo, jobQueueRepository, marketPricesRepository, systemRepository, gateRouter)
+		controllers.NewStockpileRebalance(router, assetsRepository, transportProfilesRepo, jfRoutesRepo, marketPricesRepository, transportJobsRepo, jobQueueRepository, gateRouter)
 
 		jobSlotRentalsRepository := repositories.NewJobSlotRentals(db)
 		controllers.NewJobSlotRentals(router, jobSlotRentalsRepository, contactPermissionsRepository)
+		invoicesRepository := repositories.NewInvoices(db)
+		controllers.NewInvoices(router, invoicesRepository, jobSlotRentalsRepository, contactsRepository)
 
 		haulingRunsRepo := repositories.NewHaulingRuns(db)
 		haulingRunItemsRepo := repositories.NewHaulingRunItems(db)
+		haulingRunStopsRepo := repositories.NewHaulingRunStops(db)
 		haulingMarketRepo := repositories.NewHaulingMarket(db)
 		haulingStructuresRepo := repositories.NewHaulingStructures(db)
 		haulingCombinedRepo := repositories.NewHaulingMarketCombined(haulingMarketRepo, haulingStructuresRepo)
@@ -201,8 +234,10 @@ var rootCmd = &cobra.Command{
 
 		tradingStationsRepo := repositories.NewTradingStations(db)
 		userTradingStructuresRepo := repositories.NewUserTradingStructures(db)
-		controllers.NewHaulingRuns(router, haulingRunsRepo, haulingRunItemsRepo, haulingMarketRepo, haulingStructuresRepo, haulingMarketUpdater, haulingPnlRepo, haulingNotifier, haulingAnalyticsRepo)
+		haulingRunsController := controllers.NewHaulingRuns(router, haulingRunsRepo, haulingRunItemsRepo, haulingRunStopsRepo, haulingMarketRepo, haulingStructuresRepo, haulingMarketUpdater, haulingPnlRepo, haulingNotifier, haulingAnalyticsRepo, gateRouter, systemRepository, transportProfilesRepo)
+		haulingRunsController.WithTradeFees(characterSkillsRepository, userTradingStructuresRepo)
 		controllers.NewTradingStructures(router, tradingStationsRepo, userTradingStructuresRepo, haulingMarketUpdater, charactersRepository, esiClient, systemRepository, charactersAssetRepository)
+		controllers.NewTradeFees(router, charactersRepository, characterSkillsRepository, userTradingStructuresRepo)
 
 		// Corp orders runner (15 min) — runs even without Discord
 		haulingCorpOrdersUpdater := updaters.NewHaulingCorpOrders(usersRepository, playerCorporationRepostiory, haulingRunsRepo, haulingRunItemsRepo, haulingRunItemsRepo, esiClient)
@@ -259,11 +294,44 @@ var rootCmd = &cobra.Command{
 
 		// Start contract sync scheduler (15 minutes)
 		contractSyncUpdater := updaters.NewContractSync(purchaseTransactionsRepository, charactersRepository, playerCorporationRepostiory, esiClient)
+		if notificationsUpdater != nil {
+			contractSyncUpdater.WithDisputeNotifier(notificationsUpdater)
+		}
 		contractSyncRunner := runners.NewContractSyncRunner(contractSyncUpdater, 15*time.Minute)
 		group.Go(func() error {
 			return contractSyncRunner.Run(ctx)
 		})
 
+		// Start courier contract sync scheduler (15 minutes)
+		courierContractSyncUpdater := updaters.NewCourierContractSync(transportJobsRepo, charactersRepository, playerCorporationRepostiory, esiClient)
+		if notificationsUpdater != nil {
+			courierContractSyncUpdater.WithNotifier(notificationsUpdater)
+		}
+		courierContractSyncRunner := runners.NewCourierContractSyncRunner(courierContractSyncUpdater, 15*time.Minute)
+		group.Go(func() error {
+			return courierContractSyncRunner.Run(ctx)
+		})
+
+		// Start purchase expiry scheduler (15 minutes)
+		purchaseExpiryUpdater := updaters.NewPurchaseExpiry(db, purchaseTransactionsRepository, forSaleItemsRepository)
+		if notificationsUpdater != nil {
+			purchaseExpiryUpdater.WithNotifier(notificationsUpdater)
+		}
+		purchaseExpiryRunner := runners.NewPurchaseExpiryRunner(purchaseExpiryUpdater, 15*time.Minute)
+		group.Go(func() error {
+			return purchaseExpiryRunner.Run(ctx)
+		})
+
+		// Start invoice payment matching scheduler (15 minutes)
+		invoicesUpdater := updaters.NewInvoices(invoicesRepository, charactersRepository, esiClient)
+		if notificationsUpdater != nil {
+			invoicesUpdater.WithNotifier(notificationsUpdater)
+		}
+		invoicesRunner := runners.NewInvoicesRunner(invoicesUpdater, 15*time.Minute)
+		group.Go(func() error {
+			return invoicesRunner.Run(ctx)
+		})
+
 		// Start character skills update scheduler (configurable, default 6h)
 		skillsRunner := runners.NewCharacterSkillsRunner(characterSkillsUpdater, time.Duration(settings.SkillsUpdateIntervalSec)*time.Second)
 		group.Go(func() error {
@@ -299,6 +367,13 @@ var rootCmd = &cobra.Command{
 			return autoProductionRunner.Run(ctx)
 		})
 
+		// Start stockpile consumption runner (1h): snapshots markers and auto-adjusts desired quantities
+		stockpileConsumptionUpdater := updaters.NewStockpileConsumptionUpdater(stockpileMarkersRepository)
+		stockpileConsumptionRunner := runners.NewStockpileConsumptionRunner(stockpileConsumptionUpdater, 1*time.Hour)
+		group.Go(func() error {
+			return stockpileConsumptionRunner.Run(ctx)
+		})
+
 		log.Info("services started")
 
 		eventChan := make(chan os.Signal, 1)
@@ -147,6 +147,18 @@ type walletTransaction struct {
 	JournalRefID  int64   `json:"journal_ref_id"`
 }
 
+type walletJournalEntry struct {
+	ID            int64    `json:"id"`
+	Date          string   `json:"date"`
+	RefType       string   `json:"ref_type"`
+	Amount        *float64 `json:"amount,omitempty"`
+	Balance       *float64 `json:"balance,omitempty"`
+	FirstPartyID  *int64   `json:"first_party_id,omitempty"`
+	SecondPartyID *int64   `json:"second_party_id,omitempty"`
+	Reason        string   `json:"reason,omitempty"`
+	Description   string   `json:"description"`
+}
+
 // PI types
 
 type piPlanet struct {
@@ -233,6 +245,7 @@ type State struct {
 	characterForce401      map[int64]bool
 	characterOrders        map[int64][]characterOrder
 	characterWalletTx      map[int64][]walletTransaction
+	characterWalletJournal map[int64][]walletJournalEntry
 	// Station market: structure info and market orders keyed by structureID
 	structureInfo         map[int64]*structureInfo
 	structureMarketOrders map[int64][]marketOrder
@@ -428,8 +441,9 @@ func newDefaultState() *State {
 		planetDetails:     map[string]piColony{},
 		characterForce401: map[int64]bool{},
 		// Phase 3: character orders and wallet transactions — empty by default; tests inject via admin API
-		characterOrders:   map[int64][]characterOrder{},
-		characterWalletTx: map[int64][]walletTransaction{},
+		characterOrders:        map[int64][]characterOrder{},
+		characterWalletTx:      map[int64][]walletTransaction{},
+		characterWalletJournal: map[int64][]walletJournalEntry{},
 		// Station markets: structure info and market orders
 		// Structure 1234567890123 = "Perimeter - Test Trading Hub" in Jita system (30000142 = The Forge)
 		// Structure 9999999999999 = access denied (403)
@@ -921,6 +935,7 @@ func main() {
 
 	// GET /latest/characters/{id}/orders/
 	// GET /latest/characters/{id}/wallet/transactions/
+	// GET /latest/characters/{id}/wallet/journal/
 	mux.HandleFunc("/latest/characters/", func(w http.ResponseWriter, r *http.Request) {
 		path := r.URL.Path
 
@@ -959,6 +974,23 @@ func main() {
 			return
 		}
 
+		// GET /latest/characters/{id}/wallet/journal/
+		if strings.Contains(path, "/wallet/journal") && r.Method == "GET" {
+			charID, ok := extractID(strings.TrimPrefix(path, "/latest"), "/characters/", "/wallet/journal")
+			if !ok {
+				http.Error(w, "invalid character id", 400)
+				return
+			}
+			state.mu.RLock()
+			entries, ok := state.characterWalletJournal[charID]
+			state.mu.RUnlock()
+			if !ok {
+				entries = []walletJournalEntry{}
+			}
+			writeJSON(w, entries)
+			return
+		}
+
 		http.Error(w, "not found", 404)
 	})
 
@@ -1039,6 +1071,7 @@ func main() {
 			state.characterForce401 = fresh.characterForce401
 			state.characterOrders = fresh.characterOrders
 			state.characterWalletTx = fresh.characterWalletTx
+			state.characterWalletJournal = fresh.characterWalletJournal
 			state.structureInfo = fresh.structureInfo
 			state.structureMarketOrders = fresh.structureMarketOrders
 			state.mu.Unlock()
@@ -1277,6 +1310,28 @@ func main() {
 			writeAdminOK(w)
 		})
 
+		// PUT /_admin/character-wallet-journal/{charID}
+		mux.HandleFunc("/_admin/character-wallet-journal/", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "PUT" {
+				writeAdminError(w, http.StatusMethodNotAllowed, "method not allowed")
+				return
+			}
+			charID, ok := extractID(r.URL.Path, "/_admin/character-wallet-journal/", "")
+			if !ok {
+				writeAdminError(w, http.StatusBadRequest, "invalid character id")
+				return
+			}
+			var entries []walletJournalEntry
+			if err := json.NewDecoder(r.Body).Decode(&entries); err != nil {
+				writeAdminError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+				return
+			}
+			state.mu.Lock()
+			state.characterWalletJournal[charID] = entries
+			state.mu.Unlock()
+			writeAdminOK(w)
+		})
+
 		// PUT /_admin/planet-details/{charID}/{planetID}
 		mux.HandleFunc("/_admin/planet-details/", func(w http.ResponseWriter, r *http.Request) {
 			if r.Method != "PUT" {
@@ -463,6 +463,8 @@ export type ManufacturingCalcResult = {
   jobCost: number;
   totalCost: number;
   outputValue: number;
+  salesTax: number;
+  brokerFee: number;
   profit: number;
   margin: number;
   materials: ManufacturingMaterial[];
@@ -723,9 +725,19 @@ export type UserTradingStructure = {
   characterId: number;
   accessOk: boolean;
   lastScannedAt?: string;
+  brokerFeePct?: number;
   createdAt: string;
 };
 
+export type TradeFees = {
+  characterId: number;
+  characterName: string;
+  accountingLevel: number;
+  brokerRelationsLevel: number;
+  salesTaxPct: number;
+  brokerFeePct: number;
+};
+
 export type LocationType = 'region' | 'station' | 'structure';
 
 export type ScannerLocation = {
@@ -760,6 +772,33 @@ export type HaulingRun = {
   updatedAt: string;
   completedAt?: string;
   items?: HaulingRunItem[];
+  stops?: HaulingRunStop[];
+};
+
+export type HaulingRunStop = {
+  id: number;
+  runId: number;
+  seq: number;
+  regionId: number;
+  systemId?: number;
+  stationId?: number;
+  name?: string;
+  createdAt: string;
+};
+
+export type HaulingRunLeg = {
+  seq: number;
+  fromStop: HaulingRunStop;
+  toStop: HaulingRunStop;
+  loadM3: number;
+  capacityM3?: number;
+  overCapacity: boolean;
+  itemsAboard: number;
+  itemsDelivered: number;
+  plannedProfitIsk: number;
+  revenueIsk: number;
+  costIsk: number;
+  netProfitIsk: number;
 };
 
 export type HaulingRunItem = {
@@ -783,6 +822,8 @@ export type HaulingRunItem = {
   actualSellPriceIsk?: number;
   sellFillPercent: number;
   actualRevenueIsk?: number;
+  buyStopId?: number;
+  sellStopId?: number;
 };
 
 export type HaulingArbitrageRow = {
@@ -797,6 +838,19 @@ export type HaulingArbitrageRow = {
   daysToSell?: number;
   indicator: 'gap' | 'markup' | 'thin';
   updatedAt: string;
+  jumps?: number;
+  minSecurity?: number;
+  iskPerM3PerJump?: number;
+  iskPerHour?: number;
+  destAvgDailyVolume?: number;
+};
+
+export type HaulingCargoPlan = {
+  items: HaulingRunItem[];
+  totalVolumeM3: number;
+  totalCostIsk: number;
+  expectedProfitIsk: number;
+  remainingVolumeM3: number;
 };
 
 export type HaulingRunPnlEntry = {
@@ -816,6 +870,8 @@ export type HaulingRunPnlEntry = {
 export type HaulingRunPnlSummary = {
   totalRevenueIsk: number;
   totalCostIsk: number;
+  salesTaxIsk: number;
+  brokerFeesIsk: number;
   netProfitIsk: number;
   marginPct: number;
   itemsSold: number;
@@ -836,6 +892,16 @@ export type HaulingRouteAnalytics = {
   worstRunProfitIsk: number;
 };
 
+export type HaulingLegAnalytics = {
+  fromRegionId: number;
+  toRegionId: number;
+  totalLegs: number;
+  totalRuns: number;
+  totalProfitIsk: number;
+  avgProfitIsk: number;
+  avgMarginPct: number;
+};
+
 export type HaulingItemAnalytics = {
   typeId: number;
   typeName: string;
@@ -65,8 +65,28 @@ type ForSaleListing = {
   pricePerUnit: number;
   notes?: string;
   autoSellContainerId?: number;
+  blueprint?: ListingBlueprint;
 };
 
+type ListingBlueprint = {
+  itemId: number;
+  isCopy: boolean;
+  runs: number;
+  materialEfficiency: number;
+  timeEfficiency: number;
+};
+
+type ListableBlueprint = {
+  itemId: number;
+  quantity: number;
+  runs: number;
+  materialEfficiency: number;
+  timeEfficiency: number;
+};
+
+const describeBlueprint = (bp: { isCopy: boolean; runs: number; materialEfficiency: number; timeEfficiency: number }) =>
+  `${bp.isCopy ? `Copy · ${bp.runs} runs` : 'Original'} · ME ${bp.materialEfficiency} / TE ${bp.timeEfficiency}`;
+
 type AutoSellConfig = {
   id: number;
   userId: number;
@@ -100,12 +120,18 @@ const PRICE_SOURCE_OPTIONS = [
   { value: 'jita_split', label: 'Jita Split (Buy+Sell avg)', abbrev: 'JSplit' },
 ] as const;
 
+// Auto-sell can also price off what the seller keeps after sales tax and broker fee
+const AUTO_SELL_PRICE_SOURCE_OPTIONS = [
+  ...PRICE_SOURCE_OPTIONS,
+  { value: 'jita_sell_net', label: 'Jita Sell after Fees', abbrev: 'JSNet' },
+] as const;
+
 const getPriceSourceAbbrev = (source: string): string => {
-  return PRICE_SOURCE_OPTIONS.find(o => o.value === source)?.abbrev || 'JBV';
+  return AUTO_SELL_PRICE_SOURCE_OPTIONS.find(o => o.value === source)?.abbrev || 'JBV';
 };
 
 const getPriceSourceLabel = (source: string): string => {
-  return PRICE_SOURCE_OPTIONS.find(o => o.value === source)?.label || 'Jita Buy (Best Bid)';
+  return AUTO_SELL_PRICE_SOURCE_OPTIONS.find(o => o.value === source)?.label || 'Jita Buy (Best Bid)';
 };
 
 function formatRelativeTime(date: Date): string {
@@ -213,6 +239,8 @@ export default function AssetsList(props: AssetsListProps) {
   const listingPriceRef = useRef<HTMLInputElement>(null);
   const listingNotesRef = useRef<HTMLInputElement>(null);
   const [listingTotalValue, setListingTotalValue] = useState<string>('');
+  const [listableBlueprints, setListableBlueprints] = useState<ListableBlueprint[]>([]);
+  const [listingBlueprintItemId, setListingBlueprintItemId] = useState<number | null>(null);
 
   // Track active for-sale listings
   const [forSaleListings, setForSaleListings] = useState<ForSaleListing[]>([]);
@@ -1132,8 +1160,41 @@ export default function AssetsList(props: AssetsListProps) {
     }));
   };
 
+  const fetchListableBlueprints = async (asset: Asset) => {
+    setListableBlueprints([]);
+    try {
+      const params = new URLSearchParams({
+        typeId: String(asset.typeId),
+        ownerType: asset.ownerType,
+        ownerId: String(asset.ownerId),
+      });
+      const response = await fetch(`/api/for-sale/blueprints?${params}`);
+      if (response.ok) {
+        const data = await response.json();
+        setListableBlueprints(data || []);
+      }
+    } catch (error) {
+      console.error('Failed to fetch listable blueprints:', error);
+    }
+  };
+
+  const editingListing = forSaleListings.find(l => l.id === editingListingId);
+
+  const handleSelectListingBlueprint = (value: string) => {
+    const itemId = value === 'none' ? null : Number(value);
+    setListingBlueprintItemId(itemId);
+    // A blueprint listing is for one item, or a stack of originals
+    const bp = listableBlueprints.find(b => b.itemId === itemId);
+    if (bp && listingQuantityRef.current) {
+      listingQuantityRef.current.value = (bp.quantity > 0 ? bp.quantity : 1).toLocaleString();
+      updateTotalValue();
+    }
+  };
+
   const handleOpenListingDialog = (asset: Asset, locationId: number, containerId?: number, divisionNumber?: number, existingListing?: ForSaleListing) => {
     setListingAsset({ asset, locationId, containerId, divisionNumber });
+    setListingBlueprintItemId(null);
+    setListableBlueprints([]);
 
     if (existingListing) {
       // Editing existing listing
@@ -1154,6 +1215,7 @@ export default function AssetsList(props: AssetsListProps) {
     } else {
       // Creating new listing
       setEditingListingId(null);
+      fetchListableBlueprints(asset);
       // Set initial values via refs after dialog opens
       setTimeout(() => {
         if (listingQuantityRef.current) {
@@ -1232,6 +1294,7 @@ export default function AssetsList(props: AssetsListProps) {
           quantityAvailable: quantity,
           pricePerUnit: price,
           notes: notes || undefined,
+          blueprintItemId: editingListingId ? undefined : listingBlueprintItemId ?? undefined,
         }),
       });
 
@@ -2113,6 +2176,28 @@ export default function AssetsList(props: AssetsListProps) {
               <p className="text-sm text-text-emphasis"><strong>Item:</strong> {listingAsset?.asset.name}</p>
               <p className="text-sm text-text-emphasis"><strong>Owner:</strong> {listingAsset?.asset.ownerName}</p>
               <p className="text-sm text-text-emphasis mb-1"><strong>Available Quantity:</strong> {listingAsset?.asset.quantity.toLocaleString()}</p>
+              {editingListing?.blueprint && (
+                <p className="text-sm text-text-emphasis"><strong>Blueprint:</strong> {describeBlueprint(editingListing.blueprint)}</p>
+              )}
+              {!editingListingId && listableBlueprints.length > 0 && (
+                <div className="flex flex-col gap-1.5">
+                  <Label className="text-xs text-text-secondary">Blueprint Item</Label>
+                  <Select value={listingBlueprintItemId ? String(listingBlueprintItemId) : 'none'} onValueChange={handleSelectListingBlueprint}>
+                    <SelectTrigger className="bg-background-void border-overlay-strong text-text-emphasis">
+                      <SelectValue />
+                    </SelectTrigger>
+                    <SelectContent>
+                      <SelectItem value="none">Any (no blueprint attributes)</SelectItem>
+                      {listableBlueprints.map(bp => (
+                        <SelectItem key={bp.itemId} value={String(bp.itemId)}>
+                          {describeBlueprint({ ...bp, isCopy: bp.quantity === -2 })}
+                        </SelectItem>
+                      ))}
+                    </SelectContent>
+                  </Select>
+                  <p className="text-xs text-text-muted">List one specific copy or original so buyers see its runs and research.</p>
+                </div>
+              )}
               <div className="flex flex-col gap-1.5">
                 <Label htmlFor="listing-qty" className="text-xs text-text-secondary">Quantity to List *</Label>
                 <Input
@@ -2204,7 +2289,7 @@ export default function AssetsList(props: AssetsListProps) {
                     <SelectValue />
                   </SelectTrigger>
                   <SelectContent className="bg-background-elevated border-overlay-medium">
-                    {PRICE_SOURCE_OPTIONS.map((option) => (
+                    {AUTO_SELL_PRICE_SOURCE_OPTIONS.map((option) => (
                       <SelectItem key={option.value} value={option.value} className="text-text-emphasis focus:bg-interactive-hover">
                         {option.label}
                       </SelectItem>
@@ -0,0 +1,412 @@
+import { useState, useEffect } from 'react';
+import { Trash2, Pencil, Users, Tags, Plus, FolderOpen } from 'lucide-react';
+import { Button } from '@/components/ui/button';
+import { Badge } from '@/components/ui/badge';
+import { Input } from '@/components/ui/input';
+import { Label } from '@/components/ui/label';
+import { Checkbox } from '@/components/ui/checkbox';
+import {
+  Table, TableHeader, TableBody, TableRow, TableHead, TableCell,
+} from '@/components/ui/table';
+import {
+  Dialog, DialogContent, DialogHeader, DialogTitle, DialogFooter, DialogDescription,
+} from '@/components/ui/dialog';
+import { toast } from '@/components/ui/sonner';
+import { formatISK } from '@industry-tool/utils/formatting';
+import type { Contact } from './ContactsList';
+
+export type ContactGroup = {
+  id: number;
+  userId: number;
+  name: string;
+  permissions: string[];
+  members: { userId: number; name: string; addedAt: string }[];
+  createdAt: string;
+  updatedAt: string;
+};
+
+type GroupPrice = {
+  groupId: number;
+  forSaleItemId: number;
+  typeName: string;
+  listPrice: number;
+  pricePerUnit: number;
+};
+
+type Listing = {
+  id: number;
+  typeName: string;
+  locationName: string;
+  quantityAvailable: number;
+  pricePerUnit: number;
+  isActive: boolean;
+};
+
+const GROUP_PERMISSIONS = [
+  { type: 'for_sale_browse', label: 'Browse For-Sale Items' },
+  { type: 'job_slot_browse', label: 'Browse Job Slot Listings' },
+];
+
+type Props = {
+  contacts: Contact[];
+  currentUserId: number | null;
+};
+
+export default function ContactGroups({ contacts, currentUserId }: Props) {
+  const [groups, setGroups] = useState<ContactGroup[]>([]);
+
+  // Create / edit dialog
+  const [editOpen, setEditOpen] = useState(false);
+  const [editingGroup, setEditingGroup] = useState<ContactGroup | null>(null);
+  const [groupName, setGroupName] = useState('');
+  const [groupPermissions, setGroupPermissions] = useState<string[]>(['for_sale_browse']);
+
+  // Members dialog
+  const [membersGroup, setMembersGroup] = useState<ContactGroup | null>(null);
+  const [selectedMembers, setSelectedMembers] = useState<number[]>([]);
+
+  // Prices dialog
+  const [pricesGroup, setPricesGroup] = useState<ContactGroup | null>(null);
+  const [listings, setListings] = useState<Listing[]>([]);
+  const [priceInputs, setPriceInputs] = useState<Record<number, string>>({});
+  const [savedPrices, setSavedPrices] = useState<Record<number, number>>({});
+
+  useEffect(() => {
+    fetchGroups();
+  }, []);
+
+  const fetchGroups = async () => {
+    try {
+      const response = await fetch('/api/contact-groups');
+      if (response.ok) {
+        const data: ContactGroup[] = await response.json();
+        setGroups(data || []);
+      }
+    } catch {
+      // Silently fail
+    }
+  };
+
+  const contactOptions = contacts.map((contact) => {
+    const isRequester = contact.requesterUserId === currentUserId;
+    return {
+      userId: isRequester ? contact.recipientUserId : contact.requesterUserId,
+      name: isRequester ? contact.recipientName : contact.requesterName,
+    };
+  });
+
+  const openCreate = () => {
+    setEditingGroup(null);
+    setGroupName('');
+    setGroupPermissions(['for_sale_browse']);
+    setEditOpen(true);
+  };
+
+  const openEdit = (group: ContactGroup) => {
+    setEditingGroup(group);
+    setGroupName(group.name);
+    setGroupPermissions(group.permissions);
+    setEditOpen(true);
+  };
+
+  const handleSaveGroup = async () => {
+    try {
+      const response = await fetch(editingGroup ? `/api/contact-groups/${editingGroup.id}` : '/api/contact-groups', {
+        method: editingGroup ? 'PUT' : 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ name: groupName, permissions: groupPermissions }),
+      });
+
+      if (response.ok) {
+        toast.success(editingGroup ? 'Group updated' : 'Group created');
+        setEditOpen(false);
+        await fetchGroups();
+      } else {
+        const error = await response.json();
+        toast.error(error.error || 'Failed to save group');
+      }
+    } catch {
+      toast.error('Failed to save group');
+    }
+  };
+
+  const handleDeleteGroup = async (group: ContactGroup) => {
+    if (!window.confirm(`Delete group "${group.name}"? Members lose its permissions and prices.`)) return;
+
+    try {
+      const response = await fetch(`/api/contact-groups/${group.id}`, { method: 'DELETE' });
+      if (response.ok) {
+        toast.success('Group deleted');
+        await fetchGroups();
+      } else {
+        const error = await response.json();
+        toast.error(error.error || 'Failed to delete group');
+      }
+    } catch {
+      toast.error('Failed to delete group');
+    }
+  };
+
+  const openMembers = (group: ContactGroup) => {
+    setMembersGroup(group);
+    setSelectedMembers(group.members.map(m => m.userId));
+  };
+
+  const handleSaveMembers = async () => {
+    if (!membersGroup) return;
+
+    const current = membersGroup.members.map(m => m.userId);
+    const add = selectedMembers.filter(id => !current.includes(id));
+    const remove = current.filter(id => !selectedMembers.includes(id));
+
+    try {
+      const response = await fetch(`/api/contact-groups/${membersGroup.id}/members`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ add, remove }),
+      });
+
+      if (response.ok) {
+        toast.success('Members updated');
+        setMembersGroup(null);
+        await fetchGroups();
+      } else {
+        const error = await response.json();
+        toast.error(error.error || 'Failed to update members');
+      }
+    } catch {
+      toast.error('Failed to update members');
+    }
+  };
+
+  const openPrices = async (group: ContactGroup) => {
+    setPricesGroup(group);
+    setPriceInputs({});
+    setSavedPrices({});
+
+    try {
+      const [listingsResponse, pricesResponse] = await Promise.all([
+        fetch('/api/for-sale'),
+        fetch(`/api/contact-groups/${group.id}/prices`),
+      ]);
+
+      if (listingsResponse.ok) {
+        const data: Listing[] = await listingsResponse.json();
+        setListings((data || []).filter(l => l.isActive));
+      }
+      if (pricesResponse.ok) {
+        const data: GroupPrice[] = await pricesResponse.json();
+        const saved: Record<number, number> = {};
+        const inputs: Record<number, string> = {};
+        for (const price of data || []) {
+          saved[price.forSaleItemId] = price.pricePerUnit;
+          inputs[price.forSaleItemId] = String(price.pricePerUnit);
+        }
+        setSavedPrices(saved);
+        setPriceInputs(inputs);
+      }
+    } catch {
+      toast.error('Failed to load price list');
+    }
+  };
+
+  const handleSavePrice = async (listing: Listing) => {
+    if (!pricesGroup) return;
+
+    const raw = (priceInputs[listing.id] || '').replace(/,/g, '').trim();
+    const clearing = raw === '';
+
+    try {
+      const response = await fetch(`/api/contact-groups/${pricesGroup.id}/prices/${listing.id}`, {
+        method: clearing ? 'DELETE' : 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: clearing ? undefined : JSON.stringify({ pricePerUnit: parseFloat(raw) }),
+      });
+
+      if (response.ok) {
+        const next = { ...savedPrices };
+        if (clearing) {
+          delete next[listing.id];
+        } else {
+          next[listing.id] = parseFloat(raw);
+        }
+        setSavedPrices(next);
+        toast.success(clearing ? 'Group price removed' : 'Group price saved');
+      } else {
+        const error = await response.json();
+        toast.error(error.error || 'Failed to save price');
+      }
+    } catch {
+      toast.error('Failed to save price');
+    }
+  };
+
+  const permissionLabel = (perm: string) => GROUP_PERMISSIONS.find(p => p.type === perm)?.label || perm;
+
+  return (
+    <>
+      <div className="flex justify-end p-3 border-b border-[var(--color-border-dim)]">
+        <Button size="sm" onClick={openCreate}>
+          <Plus className="h-4 w-4 mr-2" />
+          New Group
+        </Button>
+      </div>
+
+      {groups.length === 0 ? (
+        <div className="empty-state">
+          <FolderOpen className="empty-state-icon" />
+          <p className="empty-state-title">No groups yet. Group contacts to grant permissions and set prices for all of them at once.</p>
+        </div>
+      ) : (
+        <Table>
+          <TableHeader>
+            <TableRow>
+              <TableHead>Group</TableHead><TableHead>Permissions</TableHead><TableHead>Members</TableHead><TableHead className="text-right">Actions</TableHead>
+            </TableRow>
+          </TableHeader>
+          <TableBody>
+            {groups.map((group) => (
+              <TableRow key={group.id}>
+                <TableCell className="text-[var(--color-text-primary)]">{group.name}</TableCell>
+                <TableCell>
+                  <div className="flex gap-1 flex-wrap">
+                    {group.permissions.length === 0 && <span className="text-xs text-[var(--color-text-muted)]">None</span>}
+                    {group.permissions.map((perm) => (
+                      <Badge key={perm} variant="outline" className="text-[10px]">{permissionLabel(perm)}</Badge>
+                    ))}
+                  </div>
+                </TableCell>
+                <TableCell className="text-[var(--color-text-secondary)]" title={group.members.map(m => m.name).join(', ')}>
+                  {group.members.length}
+                </TableCell>
+                <TableCell className="text-right">
+                  <Button variant="ghost" size="icon" onClick={() => openMembers(group)} title="Manage Members"><Users className="h-4 w-4" /></Button>
+                  <Button variant="ghost" size="icon" onClick={() => openPrices(group)} title="Price List"><Tags className="h-4 w-4" /></Button>
+                  <Button variant="ghost" size="icon" onClick={() => openEdit(group)} title="Edit Group"><Pencil className="h-4 w-4" /></Button>
+                  <Button variant="ghost" size="icon" onClick={() => handleDeleteGroup(group)} title="Delete Group" className="text-[var(--color-danger-rose)] hover:text-[var(--color-danger-rose)]"><Trash2 className="h-4 w-4" /></Button>
+                </TableCell>
+              </TableRow>
+            ))}
+          </TableBody>
+        </Table>
+      )}
+
+      {/* Create / Edit Group Dialog */}
+      <Dialog open={editOpen} onOpenChange={setEditOpen}>
+        <DialogContent className="max-w-sm">
+          <DialogHeader>
+            <DialogTitle>{editingGroup ? 'Edit Group' : 'New Group'}</DialogTitle>
+            <DialogDescription>Members get every permission the group grants.</DialogDescription>
+          </DialogHeader>
+          <div className="space-y-4">
+            <div>
+              <Label htmlFor="groupName">Name</Label>
+              <Input id="groupName" value={groupName} onChange={(e) => setGroupName(e.target.value)} placeholder="e.g. Inner circle" maxLength={100} autoFocus />
+            </div>
+            <div>
+              <Label className="mb-2 block">Permissions</Label>
+              {GROUP_PERMISSIONS.map((perm) => (
+                <div key={perm.type} className="flex items-center gap-2 py-1">
+                  <Checkbox id={`group-perm-${perm.type}`} checked={groupPermissions.includes(perm.type)}
+                    onCheckedChange={(checked) => {
+                      if (checked) { setGroupPermissions([...groupPermissions, perm.type]); }
+                      else { setGroupPermissions(groupPermissions.filter(p => p !== perm.type)); }
+                    }} />
+                  <Label htmlFor={`group-perm-${perm.type}`} className="cursor-pointer">{perm.label}</Label>
+                </div>
+              ))}
+            </div>
+          </div>
+          <DialogFooter>
+            <Button variant="ghost" onClick={() => setEditOpen(false)}>Cancel</Button>
+            <Button onClick={handleSaveGroup} disabled={groupName.trim() === ''}>Save</Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+
+      {/* Members Dialog */}
+      <Dialog open={membersGroup !== null} onOpenChange={(open) => { if (!open) setMembersGroup(null); }}>
+        <DialogContent className="max-w-sm">
+          <DialogHeader>
+            <DialogTitle>Members of {membersGroup?.name}</DialogTitle>
+            <DialogDescription>Only accepted contacts can be added.</DialogDescription>
+          </DialogHeader>
+          <div className="flex gap-2 mb-2">
+            <Button variant="outline" size="sm" onClick={() => setSelectedMembers(contactOptions.map(c => c.userId))}>Select All</Button>
+            <Button variant="outline" size="sm" onClick={() => setSelectedMembers([])}>Clear</Button>
+          </div>
+          <div className="max-h-72 overflow-y-auto border border-[var(--color-border-dim)] rounded-sm">
+            {contactOptions.length === 0 && (
+              <p className="text-xs text-[var(--color-text-muted)] p-3">No contacts yet.</p>
+            )}
+            {contactOptions.map((contact) => (
+              <div key={contact.userId} className="flex items-center gap-2 px-3 py-1.5">
+                <Checkbox id={`member-${contact.userId}`} checked={selectedMembers.includes(contact.userId)}
+                  onCheckedChange={(checked) => {
+                    if (checked) { setSelectedMembers([...selectedMembers, contact.userId]); }
+                    else { setSelectedMembers(selectedMembers.filter(id => id !== contact.userId)); }
+                  }} />
+                <Label htmlFor={`member-${contact.userId}`} className="cursor-pointer">{contact.name}</Label>
+              </div>
+            ))}
+          </div>
+          <DialogFooter>
+            <Button variant="ghost" onClick={() => setMembersGroup(null)}>Cancel</Button>
+            <Button onClick={handleSaveMembers}>Save Members</Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+
+      {/* Price List Dialog */}
+      <Dialog open={pricesGroup !== null} onOpenChange={(open) => { if (!open) setPricesGroup(null); }}>
+        <DialogContent className="max-w-3xl">
+          <DialogHeader>
+            <DialogTitle>Price List for {pricesGroup?.name}</DialogTitle>
+            <DialogDescription>
+              Members pay the group price instead of the listed price. Leave a price empty to charge the listed price.
+            </DialogDescription>
+          </DialogHeader>
+          <div className="max-h-96 overflow-y-auto">
+            {listings.length === 0 ? (
+              <p className="text-xs text-[var(--color-text-muted)] p-3">You have no active listings.</p>
+            ) : (
+              <Table>
+                <TableHeader>
+                  <TableRow>
+                    <TableHead>Item</TableHead><TableHead>Location</TableHead><TableHead className="text-right">Listed</TableHead><TableHead className="text-right">Group Price</TableHead><TableHead />
+                  </TableRow>
+                </TableHeader>
+                <TableBody>
+                  {listings.map((listing) => (
+                    <TableRow key={listing.id}>
+                      <TableCell className="text-[var(--color-text-primary)]">{listing.typeName}</TableCell>
+                      <TableCell className="text-[var(--color-text-secondary)]">{listing.locationName}</TableCell>
+                      <TableCell className="text-right">{formatISK(listing.pricePerUnit)}</TableCell>
+                      <TableCell className="text-right">
+                        <Input
+                          className="h-8 w-32 ml-auto text-right"
+                          value={priceInputs[listing.id] ?? ''}
+                          onChange={(e) => setPriceInputs({ ...priceInputs, [listing.id]: e.target.value })}
+                          placeholder="Listed"
+                        />
+                      </TableCell>
+                      <TableCell className="text-right">
+                        <Button variant="outline" size="sm" onClick={() => handleSavePrice(listing)}
+                          disabled={(priceInputs[listing.id] ?? '') === (savedPrices[listing.id] !== undefined ? String(savedPrices[listing.id]) : '')}>
+                          Save
+                        </Button>
+                      </TableCell>
+                    </TableRow>
+                  ))}
+                </TableBody>
+              </Table>
+            )}
+          </div>
+          <DialogFooter>
+            <Button variant="ghost" onClick={() => setPricesGroup(null)}>Close</Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+    </>
+  );
+}
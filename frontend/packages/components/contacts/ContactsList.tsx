@@ -22,6 +22,7 @@ import {
 import { Alert, AlertDescription } from '@/components/ui/alert';
 import { toast } from '@/components/ui/sonner';
 import PermissionsDialog from './PermissionsDialog';
+import ContactGroups from './ContactGroups';
 
 export type Contact = {
   id: number;
@@ -354,6 +355,9 @@ export default function ContactsList() {
               <TabsTrigger value="rules" className="rounded-none border-b-2 border-transparent data-[state=active]:border-[var(--color-primary-cyan)] data-[state=active]:shadow-none">
                 Rules ({contactRules.length})
               </TabsTrigger>
+              <TabsTrigger value="groups" className="rounded-none border-b-2 border-transparent data-[state=active]:border-[var(--color-primary-cyan)] data-[state=active]:shadow-none">
+                Groups
+              </TabsTrigger>
             </TabsList>
 
             <TabsContent value="contacts">
@@ -492,6 +496,12 @@ export default function ContactsList() {
                 )}
               </CardContent>
             </TabsContent>
+
+            <TabsContent value="groups">
+              <CardContent className="p-0">
+                <ContactGroups contacts={myContacts} currentUserId={currentUserId} />
+              </CardContent>
+            </TabsContent>
           </Tabs>
         </Card>
       </div>
@@ -17,6 +17,7 @@ import Image from 'next/image';
 import Loading from '@industry-tool/components/loading';
 import {
   HaulingRouteAnalytics,
+  HaulingLegAnalytics,
   HaulingItemAnalytics,
   HaulingProfitDataPoint,
   HaulingRunDurationSummary,
@@ -170,6 +171,47 @@ function RouteTable({ routes }: RouteTableProps) {
   );
 }
 
+interface LegTableProps {
+  legs: HaulingLegAnalytics[];
+}
+
+function LegTable({ legs }: LegTableProps) {
+  return (
+    <div className="overflow-x-auto">
+      <Table>
+        <TableHeader>
+          <TableRow className="bg-background-void border-overlay-subtle">
+            <TableHead className="font-bold text-text-emphasis">Leg</TableHead>
+            <TableHead className="font-bold text-text-emphasis text-right">Legs</TableHead>
+            <TableHead className="font-bold text-text-emphasis text-right">Runs</TableHead>
+            <TableHead className="font-bold text-text-emphasis text-right">Total Profit</TableHead>
+            <TableHead className="font-bold text-text-emphasis text-right">Avg Profit/Leg</TableHead>
+            <TableHead className="font-bold text-text-emphasis text-right">Avg Margin%</TableHead>
+          </TableRow>
+        </TableHeader>
+        <TableBody>
+          {legs.map((l, idx) => (
+            <TableRow
+              key={`${l.fromRegionId}-${l.toRegionId}`}
+              className="border-overlay-subtle"
+              style={{ backgroundColor: idx % 2 === 0 ? undefined : 'var(--color-overlay-subtle)' }}
+            >
+              <TableCell className="font-medium text-text-emphasis">
+                {regionName(l.fromRegionId)} &rarr; {regionName(l.toRegionId)}
+              </TableCell>
+              <TableCell className="text-right text-text-secondary">{l.totalLegs}</TableCell>
+              <TableCell className="text-right text-text-secondary">{l.totalRuns}</TableCell>
+              <TableCell className="text-right text-text-data-value font-semibold">{formatISK(l.totalProfitIsk)}</TableCell>
+              <TableCell className="text-right text-text-secondary">{formatISK(l.avgProfitIsk)}</TableCell>
+              <TableCell className="text-right text-text-secondary">{l.avgMarginPct.toFixed(1)}%</TableCell>
+            </TableRow>
+          ))}
+        </TableBody>
+      </Table>
+    </div>
+  );
+}
+
 interface ProfitChartProps {
   timeseries: HaulingProfitDataPoint[];
   routes: HaulingRouteAnalytics[];
@@ -422,6 +464,7 @@ export default function HaulingAnalytics() {
   const { data: session } = useSession();
   const [loading, setLoading] = useState(true);
   const [routes, setRoutes] = useState<HaulingRouteAnalytics[]>([]);
+  const [legs, setLegs] = useState<HaulingLegAnalytics[]>([]);
   const [items, setItems] = useState<HaulingItemAnalytics[]>([]);
   const [timeseries, setTimeseries] = useState<HaulingProfitDataPoint[]>([]);
   const [summary, setSummary] = useState<HaulingRunDurationSummary | null>(null);
@@ -429,17 +472,19 @@ export default function HaulingAnalytics() {
   const fetchAll = useCallback(async () => {
     setLoading(true);
     try {
-      const [routesRes, itemsRes, tsRes, summaryRes] = await Promise.all([
+      const [routesRes, itemsRes, tsRes, summaryRes, legsRes] = await Promise.all([
         fetch('/api/hauling/analytics/routes'),
         fetch('/api/hauling/analytics/items'),
         fetch('/api/hauling/analytics/timeseries'),
         fetch('/api/hauling/analytics/summary'),
+        fetch('/api/hauling/analytics/legs'),
       ]);
 
       if (routesRes.ok) setRoutes(await routesRes.json());
       if (itemsRes.ok) setItems(await itemsRes.json());
       if (tsRes.ok) setTimeseries(await tsRes.json());
       if (summaryRes.ok) setSummary(await summaryRes.json());
+      if (legsRes.ok) setLegs(await legsRes.json());
     } catch (error) {
       console.error('Failed to fetch analytics:', error);
     } finally {
@@ -497,6 +542,15 @@ export default function HaulingAnalytics() {
           <RouteTable routes={routes} />
         </Card>
 
+        {legs.length > 0 && (
+          <>
+            <h3 className="text-sm font-semibold text-text-secondary mb-3">Profit by Leg</h3>
+            <Card className="bg-background-panel border-overlay-subtle mb-4">
+              <LegTable legs={legs} />
+            </Card>
+          </>
+        )}
+
         <h3 className="text-sm font-semibold text-text-secondary mb-3">Profit Over Time by Route</h3>
         <Card className="bg-background-panel border-overlay-subtle">
           <CardContent className="pt-4">
@@ -13,7 +13,7 @@ import { Table, TableHeader, TableBody, TableRow, TableHead, TableCell } from '@
 import { Tooltip, TooltipContent, TooltipProvider, TooltipTrigger } from '@/components/ui/tooltip';
 import { Separator } from '@/components/ui/separator';
 import { ArrowLeft, Plus, Trash2, Pencil, ExternalLink } from 'lucide-react';
-import { HaulingRun, HaulingRunItem, HaulingArbitrageRow, HaulingRunPnlEntry, HaulingRunPnlSummary } from '@industry-tool/client/data/models';
+import { HaulingRun, HaulingRunItem, HaulingRunStop, HaulingRunLeg, HaulingArbitrageRow, HaulingRunPnlEntry, HaulingRunPnlSummary } from '@industry-tool/client/data/models';
 import { formatISK, formatNumber } from '@industry-tool/utils/formatting';
 import { getItemIconUrl } from '@industry-tool/utils/eveImages';
 
@@ -70,6 +70,10 @@ function StatusBadge({ status }: { status: string }) {
   );
 }
 
+function stopLabel(stop: HaulingRunStop): string {
+  return stop.name || EVE_REGIONS[stop.regionId] || `Region ${stop.regionId}`;
+}
+
 function getRowBgColor(fillPercent: number): string | undefined {
   if (fillPercent >= 100) return 'var(--color-success-tint)';
   if (fillPercent < 50) return 'var(--color-warning-tint)';
@@ -97,6 +101,12 @@ interface PnlEntryForm {
   totalCostIsk: string;
 }
 
+interface StopForm {
+  id?: number;
+  regionId: string;
+  name: string;
+}
+
 interface RouteSafetyData {
   jumps: number | null;
   fromName: string;
@@ -128,6 +138,12 @@ export default function HaulingRunDetail({ runId }: HaulingRunDetailProps) {
     totalCostIsk: '',
   });
 
+  // Multi-stop state
+  const [legs, setLegs] = useState<HaulingRunLeg[]>([]);
+  const [stopsDialogOpen, setStopsDialogOpen] = useState(false);
+  const [stopsForm, setStopsForm] = useState<StopForm[]>([]);
+  const [stopsError, setStopsError] = useState<string | null>(null);
+
   // Route safety state
   const [routeSafety, setRouteSafety] = useState<RouteSafetyData>({
     jumps: null,
@@ -161,6 +177,17 @@ export default function HaulingRunDetail({ runId }: HaulingRunDetailProps) {
         const data: HaulingRun = await response.json();
         setRun(data);
 
+        // Fetch per-leg cargo and P&L for multi-stop runs
+        if (data.stops && data.stops.length >= 2) {
+          const legsRes = await fetch(`/api/hauling/runs/${runId}/legs`);
+          if (legsRes.ok) {
+            const legsData = await legsRes.json();
+            setLegs(Array.isArray(legsData) ? legsData : []);
+          }
+        } else {
+          setLegs([]);
+        }
+
         // Fetch scanner suggestions for fill remaining capacity
         if (data.fromRegionId && data.toRegionId) {
           const params = new URLSearchParams({
@@ -220,7 +247,7 @@ export default function HaulingRunDetail({ runId }: HaulingRunDetailProps) {
           }
         }
       } catch {
-        // ESI route unavailable — leave jumps as null
+        // Route unavailable — leave jumps as null
       }
     }
 
@@ -331,6 +358,67 @@ export default function HaulingRunDetail({ runId }: HaulingRunDetailProps) {
     }
   };
 
+  const handleOpenStopsDialog = () => {
+    const stops = run?.stops || [];
+    setStopsForm(
+      stops.length > 0
+        ? stops.map((stop) => ({ id: stop.id, regionId: String(stop.regionId), name: stop.name || '' }))
+        : [
+            { regionId: String(run?.fromRegionId || ''), name: '' },
+            { regionId: String(run?.toRegionId || ''), name: '' },
+          ],
+    );
+    setStopsError(null);
+    setStopsDialogOpen(true);
+  };
+
+  const handleSaveStops = async (stops: StopForm[]) => {
+    setSubmitting(true);
+    setStopsError(null);
+    try {
+      const body = stops.map((stop) => ({
+        id: stop.id,
+        regionId: Number(stop.regionId),
+        name: stop.name || undefined,
+      }));
+      const response = await fetch(`/api/hauling/runs/${runId}/stops`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(body),
+      });
+      if (response.ok) {
+        setStopsDialogOpen(false);
+        await fetchRun();
+      } else {
+        const data = await response.json();
+        setStopsError(data.error || 'Failed to save stops');
+      }
+    } catch (error) {
+      console.error('Failed to save stops:', error);
+    } finally {
+      setSubmitting(false);
+    }
+  };
+
+  const handleItemStopsChange = async (item: HaulingRunItem, buyStopId?: number, sellStopId?: number) => {
+    setStopsError(null);
+    try {
+      const response = await fetch(`/api/hauling/runs/${runId}/items/${item.id}/stops`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ buyStopId, sellStopId }),
+      });
+      if (response.ok) {
+        await fetchRun();
+      } else {
+        const data = await response.json();
+        setStopsError(data.error || 'Failed to update item stops');
+      }
+    } catch (error) {
+      console.error('Failed to update item stops:', error);
+    }
+  };
+
   const handleAddScannerItem = async (row: HaulingArbitrageRow) => {
     if (!run) return;
     const remainingM3 = (run.maxVolumeM3 || 0) - totalUsedVolume;
@@ -435,6 +523,8 @@ export default function HaulingRunDetail({ runId }: HaulingRunDetailProps) {
 
   const fromName = EVE_REGIONS[run.fromRegionId] || `Region ${run.fromRegionId}`;
   const toName = EVE_REGIONS[run.toRegionId] || `Region ${run.toRegionId}`;
+  const stops = run.stops || [];
+  const isMultiStop = stops.length >= 2;
 
   // Top 3 scanner suggestions that fit remaining capacity
   const remainingM3 = maxVol > 0 ? maxVol - totalUsedVolume : Infinity;
@@ -486,7 +576,12 @@ export default function HaulingRunDetail({ runId }: HaulingRunDetailProps) {
           <Card className="flex-1 min-w-[200px] bg-background-panel border-overlay-subtle">
             <CardContent className="pt-4">
               <p className="text-sm text-text-secondary mb-1">Route</p>
-              <p className="text-lg font-semibold text-text-emphasis">{fromName} &rarr; {toName}</p>
+              <p className="text-lg font-semibold text-text-emphasis">
+                {isMultiStop ? stops.map(stopLabel).join(' → ') : <>{fromName} &rarr; {toName}</>}
+              </p>
+              <Button variant="ghost" size="sm" onClick={handleOpenStopsDialog}>
+                Edit Stops
+              </Button>
             </CardContent>
           </Card>
           {maxVol > 0 && (
@@ -556,6 +651,57 @@ export default function HaulingRunDetail({ runId }: HaulingRunDetailProps) {
           </CardContent>
         </Card>
 
+        {/* Legs — multi-stop runs only */}
+        {isMultiStop && (
+          <Card className="mb-6 bg-background-panel border-overlay-subtle">
+            <CardContent className="pt-4">
+              <h2 className="text-lg font-semibold text-text-emphasis mb-3">Legs</h2>
+              {stopsError && <p className="text-sm text-rose-danger mb-3">{stopsError}</p>}
+              <div className="overflow-x-auto">
+                <Table>
+                  <TableHeader>
+                    <TableRow className="bg-background-void border-overlay-subtle">
+                      <TableHead className="font-bold text-text-emphasis">Leg</TableHead>
+                      <TableHead className="font-bold text-text-emphasis text-right">Cargo</TableHead>
+                      <TableHead className="font-bold text-text-emphasis text-right">Items Aboard</TableHead>
+                      <TableHead className="font-bold text-text-emphasis text-right">Delivered</TableHead>
+                      <TableHead className="font-bold text-text-emphasis text-right">Planned Profit</TableHead>
+                      <TableHead className="font-bold text-text-emphasis text-right">Revenue</TableHead>
+                      <TableHead className="font-bold text-text-emphasis text-right">Net Profit</TableHead>
+                    </TableRow>
+                  </TableHeader>
+                  <TableBody>
+                    {legs.map((leg) => (
+                      <TableRow key={leg.seq} className="border-overlay-subtle">
+                        <TableCell className="text-sm text-text-emphasis">
+                          {stopLabel(leg.fromStop)} &rarr; {stopLabel(leg.toStop)}
+                        </TableCell>
+                        <TableCell
+                          className="text-right text-sm"
+                          style={{ color: leg.overCapacity ? 'var(--color-danger-rose)' : 'var(--color-text-secondary)' }}
+                        >
+                          {formatNumber(leg.loadM3, 1)}
+                          {leg.capacityM3 !== undefined ? ` / ${formatNumber(leg.capacityM3)}` : ''} m³
+                        </TableCell>
+                        <TableCell className="text-right text-sm text-text-secondary">{leg.itemsAboard}</TableCell>
+                        <TableCell className="text-right text-sm text-text-secondary">{leg.itemsDelivered}</TableCell>
+                        <TableCell className="text-right text-sm text-text-secondary">{formatISK(leg.plannedProfitIsk)}</TableCell>
+                        <TableCell className="text-right text-sm text-text-secondary">{formatISK(leg.revenueIsk)}</TableCell>
+                        <TableCell
+                          className="text-right text-sm font-semibold"
+                          style={{ color: leg.netProfitIsk >= 0 ? 'var(--color-success-teal)' : 'var(--color-danger-rose)' }}
+                        >
+                          {formatISK(leg.netProfitIsk)}
+                        </TableCell>
+                      </TableRow>
+                    ))}
+                  </TableBody>
+                </Table>
+              </div>
+            </CardContent>
+          </Card>
+        )}
+
         {/* Items Table */}
         <div className="flex items-center justify-between mb-3">
           <h2 className="text-lg font-semibold text-text-emphasis">Items</h2>
@@ -586,6 +732,9 @@ export default function HaulingRunDetail({ runId }: HaulingRunDetailProps) {
                       <TableHead className="font-bold text-text-emphasis text-right">Acquired</TableHead>
                       <TableHead className="font-bold text-text-emphasis w-36">Fill %</TableHead>
                       <TableHead className="font-bold text-text-emphasis text-right">Volume</TableHead>
+                      {isMultiStop && (
+                        <TableHead className="font-bold text-text-emphasis">Buy At / Sell At</TableHead>
+                      )}
                       {run.status === 'SELLING' && (
                         <>
                           <TableHead className="font-bold text-text-emphasis text-right">Qty Sold</TableHead>
@@ -675,6 +824,44 @@ export default function HaulingRunDetail({ runId }: HaulingRunDetailProps) {
                               ? `${formatNumber(item.volumeM3 * item.quantityPlanned, 1)} m³`
                               : '—'}
                           </TableCell>
+                          {isMultiStop && (
+                            <TableCell>
+                              <div className="flex gap-1">
+                                <Select
+                                  value={item.buyStopId ? String(item.buyStopId) : 'default'}
+                                  onValueChange={(v) => handleItemStopsChange(item, v === 'default' ? undefined : Number(v), item.sellStopId)}
+                                >
+                                  <SelectTrigger className="bg-background-void border-overlay-strong text-text-secondary text-xs h-7 min-w-[110px]">
+                                    <SelectValue />
+                                  </SelectTrigger>
+                                  <SelectContent className="bg-background-panel border-overlay-medium">
+                                    <SelectItem value="default" className="text-text-emphasis">First stop</SelectItem>
+                                    {stops.slice(0, -1).map((stop) => (
+                                      <SelectItem key={stop.id} value={String(stop.id)} className="text-text-emphasis">
+                                        {stopLabel(stop)}
+                                      </SelectItem>
+                                    ))}
+                                  </SelectContent>
+                                </Select>
+                                <Select
+                                  value={item.sellStopId ? String(item.sellStopId) : 'default'}
+                                  onValueChange={(v) => handleItemStopsChange(item, item.buyStopId, v === 'default' ? undefined : Number(v))}
+                                >
+                                  <SelectTrigger className="bg-background-void border-overlay-strong text-text-secondary text-xs h-7 min-w-[110px]">
+                                    <SelectValue />
+                                  </SelectTrigger>
+                                  <SelectContent className="bg-background-panel border-overlay-medium">
+                                    <SelectItem value="default" className="text-text-emphasis">Last stop</SelectItem>
+                                    {stops.slice(1).map((stop) => (
+                                      <SelectItem key={stop.id} value={String(stop.id)} className="text-text-emphasis">
+                                        {stopLabel(stop)}
+                                      </SelectItem>
+                                    ))}
+                                  </SelectContent>
+                                </Select>
+                              </div>
+                            </TableCell>
+                          )}
                           {run.status === 'SELLING' && (
                             <>
                               <TableCell className="text-right text-sm text-text-secondary">
@@ -988,6 +1175,18 @@ export default function HaulingRunDetail({ runId }: HaulingRunDetailProps) {
                       {formatISK(pnlSummary.totalCostIsk)}
                     </p>
                   </div>
+                  {pnlSummary.salesTaxIsk + pnlSummary.brokerFeesIsk > 0 && (
+                    <div>
+                      <p className="text-sm text-text-secondary">Tax + Broker Fees</p>
+                      <p
+                        className="text-lg font-semibold"
+                        style={{ color: 'var(--color-danger-rose)' }}
+                        title={`Sales tax ${formatISK(pnlSummary.salesTaxIsk)} · Broker fees ${formatISK(pnlSummary.brokerFeesIsk)}`}
+                      >
+                        {formatISK(pnlSummary.salesTaxIsk + pnlSummary.brokerFeesIsk)}
+                      </p>
+                    </div>
+                  )}
                   <div>
                     <p className="text-sm text-text-secondary">Net Profit</p>
                     <p
@@ -1081,6 +1280,80 @@ export default function HaulingRunDetail({ runId }: HaulingRunDetailProps) {
         </Card>
       </div>
 
+      {/* Stops Dialog */}
+      <Dialog open={stopsDialogOpen} onOpenChange={setStopsDialogOpen}>
+        <DialogContent className="max-w-lg bg-background-panel border-overlay-medium">
+          <DialogHeader>
+            <DialogTitle className="text-text-emphasis">Route Stops</DialogTitle>
+          </DialogHeader>
+          <p className="text-xs text-text-secondary">
+            Stops in route order. Items are bought at the first stop and sold at the last unless assigned otherwise.
+          </p>
+          <div className="flex flex-col gap-2 mt-2">
+            {stopsForm.map((stop, index) => (
+              <div key={stop.id ?? `new-${index}`} className="flex items-center gap-2">
+                <span className="text-xs text-text-secondary w-6">{index + 1}.</span>
+                <Select
+                  value={stop.regionId}
+                  onValueChange={(v) => setStopsForm(stopsForm.map((s, i) => (i === index ? { ...s, regionId: v } : s)))}
+                >
+                  <SelectTrigger className="bg-background-void border-overlay-strong text-text-emphasis text-xs h-8 w-[160px]">
+                    <SelectValue placeholder="Region" />
+                  </SelectTrigger>
+                  <SelectContent className="bg-background-panel border-overlay-medium">
+                    {Object.entries(EVE_REGIONS).map(([id, name]) => (
+                      <SelectItem key={id} value={id} className="text-text-emphasis">
+                        {name}
+                      </SelectItem>
+                    ))}
+                  </SelectContent>
+                </Select>
+                <Input
+                  value={stop.name}
+                  placeholder="Station (optional)"
+                  onChange={(e) => setStopsForm(stopsForm.map((s, i) => (i === index ? { ...s, name: e.target.value } : s)))}
+                  className="flex-1 h-8 bg-background-void border-overlay-strong text-text-emphasis"
+                />
+                <Button
+                  variant="ghost"
+                  size="icon"
+                  className="h-7 w-7 text-rose-danger hover:text-rose-danger hover:bg-rose-danger/10"
+                  onClick={() => setStopsForm(stopsForm.filter((_, i) => i !== index))}
+                >
+                  <Trash2 className="h-3.5 w-3.5" />
+                </Button>
+              </div>
+            ))}
+            <Button
+              variant="ghost"
+              size="sm"
+              className="self-start"
+              onClick={() => setStopsForm([...stopsForm, { regionId: '', name: '' }])}
+            >
+              <Plus className="h-4 w-4 mr-1" />
+              Add Stop
+            </Button>
+            {stopsError && <p className="text-sm text-rose-danger">{stopsError}</p>}
+          </div>
+          <DialogFooter className="mt-4">
+            {stops.length > 0 && (
+              <Button variant="ghost" onClick={() => handleSaveStops([])} className="text-text-secondary mr-auto">
+                Single Leg
+              </Button>
+            )}
+            <Button variant="ghost" onClick={() => setStopsDialogOpen(false)} className="text-text-secondary">
+              Cancel
+            </Button>
+            <Button
+              onClick={() => handleSaveStops(stopsForm)}
+              disabled={stopsForm.length < 2 || stopsForm.some((s) => !s.regionId) || submitting}
+            >
+              {submitting ? 'Saving...' : 'Save'}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+
       {/* Add Item Dialog */}
       <Dialog open={addItemOpen} onOpenChange={setAddItemOpen}>
         <DialogContent className="max-w-md bg-background-panel border-overlay-medium">
@@ -7,12 +7,14 @@ import { Button } from '@/components/ui/button';
 import { Card, CardContent } from '@/components/ui/card';
 import { Dialog, DialogContent, DialogHeader, DialogTitle, DialogFooter } from '@/components/ui/dialog';
 import { Input } from '@/components/ui/input';
+import { Checkbox } from '@/components/ui/checkbox';
 import { Select, SelectTrigger, SelectValue, SelectContent, SelectItem } from '@/components/ui/select';
 import { Table, TableHeader, TableBody, TableRow, TableHead, TableCell } from '@/components/ui/table';
 import { Skeleton } from '@/components/ui/skeleton';
-import { ScanSearch, ShoppingCart, Settings, AlertTriangle, Loader2 } from 'lucide-react';
+import { ScanSearch, ShoppingCart, Settings, AlertTriangle, Loader2, PackageCheck } from 'lucide-react';
 import {
   HaulingArbitrageRow,
+  HaulingCargoPlan,
   HaulingRun,
   ScannerLocation,
   TradingStation,
@@ -20,6 +22,7 @@ import {
 } from '@industry-tool/client/data/models';
 import { formatISK, formatNumber } from '@industry-tool/utils/formatting';
 import { getItemIconUrl } from '@industry-tool/utils/eveImages';
+import { TransportProfile } from '../../pages/transport';
 import UserStructuresDialog from './UserStructuresDialog';
 
 const EVE_REGIONS: Record<number, string> = {
@@ -129,6 +132,28 @@ interface AddToRunState {
   quantity: string;
 }
 
+interface OptimizeState {
+  open: boolean;
+  selectedRunId: number | '';
+  capitalIsk: string;
+  maxUnitsPerType: string;
+  maxDaysToSell: string;
+  salesTaxPct: string;
+  plan: HaulingCargoPlan | null;
+  error: string | null;
+}
+
+const EMPTY_OPTIMIZE: OptimizeState = {
+  open: false,
+  selectedRunId: '',
+  capitalIsk: '',
+  maxUnitsPerType: '',
+  maxDaysToSell: '7',
+  salesTaxPct: '7.5',
+  plan: null,
+  error: null,
+};
+
 interface MarketScannerProps {
   initialSourceRegion?: number;
   initialDestRegion?: number;
@@ -172,6 +197,12 @@ export default function MarketScanner({ initialSourceRegion, initialDestRegion }
   const [selectedRowIndex, setSelectedRowIndex] = useState<number>(-1);
   const [structuresDialogOpen, setStructuresDialogOpen] = useState(false);
 
+  // Route filters and ship profile for ISK/hour ranking
+  const [profiles, setProfiles] = useState<TransportProfile[]>([]);
+  const [profileId, setProfileId] = useState<string>('none');
+  const [highsecOnly, setHighsecOnly] = useState(false);
+  const [maxJumps, setMaxJumps] = useState('');
+
   const [addToRun, setAddToRun] = useState<AddToRunState>({
     row: null,
     open: false,
@@ -179,6 +210,8 @@ export default function MarketScanner({ initialSourceRegion, initialDestRegion }
     quantity: '1',
   });
 
+  const [optimize, setOptimize] = useState<OptimizeState>(EMPTY_OPTIMIZE);
+
   const tableRef = useRef<HTMLDivElement>(null);
 
   const fetchStations = useCallback(async () => {
@@ -205,12 +238,25 @@ export default function MarketScanner({ initialSourceRegion, initialDestRegion }
     }
   }, []);
 
+  const fetchProfiles = useCallback(async () => {
+    try {
+      const res = await fetch('/api/transport/profiles');
+      if (res.ok) {
+        const data = await res.json();
+        setProfiles(Array.isArray(data) ? data : []);
+      }
+    } catch (err) {
+      console.error('Failed to fetch transport profiles:', err);
+    }
+  }, []);
+
   useEffect(() => {
     if (session) {
       fetchStations();
       fetchStructures();
+      fetchProfiles();
     }
-  }, [session, fetchStations, fetchStructures]);
+  }, [session, fetchStations, fetchStructures, fetchProfiles]);
 
   // Build location lists
   const regionLocs = buildRegionLocations();
@@ -221,6 +267,8 @@ export default function MarketScanner({ initialSourceRegion, initialDestRegion }
     (loc: ScannerLocation, prefix: 'source' | 'dest', params: URLSearchParams) => {
       if (loc.type === 'structure' && loc.structureId) {
         params.set(`${prefix}_structure_id`, String(loc.structureId));
+        // The structure's system is the route endpoint
+        if (loc.systemId > 0) params.set(`${prefix}_system_id`, String(loc.systemId));
       } else {
         if (prefix === 'source') {
           params.set('source_region_id', String(loc.regionId));
@@ -241,13 +289,15 @@ export default function MarketScanner({ initialSourceRegion, initialDestRegion }
         const params = new URLSearchParams();
         buildScannerParams(srcLoc, 'source', params);
         buildScannerParams(dstLoc, 'dest', params);
+        if (profileId !== 'none') params.set('transport_profile_id', profileId);
+        if (highsecOnly) params.set('highsec_only', 'true');
+        if (Number(maxJumps) > 0) params.set('max_jumps', String(Number(maxJumps)));
 
         const response = await fetch(`/api/hauling/scanner?${params.toString()}`);
         if (response.ok) {
           const data = await response.json();
+          // Rows come ranked by ISK/hour (with a ship profile) or ISK/m³/jump
           const rows: HaulingArbitrageRow[] = Array.isArray(data) ? data : [];
-          // Sort by net profit descending
-          rows.sort((a, b) => (b.netProfitIsk || 0) - (a.netProfitIsk || 0));
           setResults(rows);
           if (rows.length > 0) {
             setLastUpdated(rows[0].updatedAt);
@@ -259,7 +309,7 @@ export default function MarketScanner({ initialSourceRegion, initialDestRegion }
         setLoading(false);
       }
     },
-    [buildScannerParams],
+    [buildScannerParams, profileId, highsecOnly, maxJumps],
   );
 
   const fetchRuns = useCallback(async () => {
@@ -370,6 +420,33 @@ export default function MarketScanner({ initialSourceRegion, initialDestRegion }
     }
   };
 
+  const handleOptimize = async () => {
+    if (optimize.selectedRunId === '') return;
+    try {
+      const response = await fetch(`/api/hauling/runs/${optimize.selectedRunId}/optimize`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({
+          rows: results,
+          capitalIsk: Number(optimize.capitalIsk) || 0,
+          maxUnitsPerType: Number(optimize.maxUnitsPerType) || 0,
+          maxDaysToSell: Number(optimize.maxDaysToSell) || 0,
+          fees: { salesTaxPct: Number(optimize.salesTaxPct) || 0, buyBrokerFeePct: 0, sellBrokerFeePct: 0 },
+        }),
+      });
+      if (response.ok) {
+        const plan: HaulingCargoPlan = await response.json();
+        setOptimize({ ...optimize, plan, error: null });
+      } else {
+        const error = await response.json();
+        setOptimize({ ...optimize, plan: null, error: error.error || 'Failed to optimize cargo' });
+      }
+    } catch (error) {
+      console.error('Failed to optimize cargo:', error);
+      setOptimize({ ...optimize, plan: null, error: 'Failed to optimize cargo' });
+    }
+  };
+
   // Keyboard navigation
   const handleKeyDown = useCallback(
     (e: React.KeyboardEvent) => {
@@ -539,6 +616,47 @@ export default function MarketScanner({ initialSourceRegion, initialDestRegion }
                 </Select>
               </div>
 
+              {/* Ship Profile */}
+              <div className="min-w-[180px]">
+                <label className="text-xs text-text-secondary mb-1 block">Ship Profile</label>
+                <Select value={profileId} onValueChange={setProfileId}>
+                  <SelectTrigger className="bg-background-void border-overlay-strong text-text-emphasis">
+                    <SelectValue />
+                  </SelectTrigger>
+                  <SelectContent className="bg-background-panel border-overlay-medium">
+                    <SelectItem value="none" className="text-text-emphasis">No profile</SelectItem>
+                    {profiles.map((p) => (
+                      <SelectItem key={p.id} value={String(p.id)} className="text-text-emphasis">
+                        {p.name} ({formatNumber(p.cargoM3, 0)} m³)
+                      </SelectItem>
+                    ))}
+                  </SelectContent>
+                </Select>
+              </div>
+
+              {/* Route Filters */}
+              <div className="w-[100px]">
+                <label className="text-xs text-text-secondary mb-1 block">Max Jumps</label>
+                <Input
+                  type="number"
+                  min={0}
+                  value={maxJumps}
+                  onChange={(e) => setMaxJumps(e.target.value)}
+                  placeholder="Any"
+                  className="bg-background-void border-overlay-strong text-text-emphasis"
+                />
+              </div>
+              <div className="flex items-center gap-2 h-9">
+                <Checkbox
+                  id="highsecOnly"
+                  checked={highsecOnly}
+                  onCheckedChange={(checked) => setHighsecOnly(Boolean(checked))}
+                />
+                <label htmlFor="highsecOnly" className="text-sm text-text-secondary cursor-pointer">
+                  Highsec only
+                </label>
+              </div>
+
               {/* Action Buttons */}
               <div className="flex items-center gap-2">
                 <Button variant="outline" onClick={handleFetchResults} disabled={loading}>
@@ -548,6 +666,15 @@ export default function MarketScanner({ initialSourceRegion, initialDestRegion }
                   <ScanSearch className="h-4 w-4 mr-2" />
                   {scanning ? 'Scanning...' : 'Scan'}
                 </Button>
+                <Button
+                  variant="outline"
+                  onClick={() => setOptimize({ ...EMPTY_OPTIMIZE, open: true, selectedRunId: runs[0]?.id || '' })}
+                  disabled={results.length === 0 || runs.length === 0}
+                  title="Fill a run from these results"
+                >
+                  <PackageCheck className="h-4 w-4 mr-2" />
+                  Optimize into Run
+                </Button>
                 <Button
                   variant="ghost"
                   size="icon"
@@ -613,7 +740,7 @@ export default function MarketScanner({ initialSourceRegion, initialDestRegion }
               <Table>
                 <TableHeader>
                   <TableRow className="bg-background-void border-overlay-subtle">
-                    {['Item', 'Indicator', 'Net Profit/unit', 'm³', 'Days to Sell', 'Buy Price', 'Sell Price', 'Volume Available', 'Add to Run'].map((h) => (
+                    {['Item', 'Indicator', 'Net Profit/unit', 'ISK/m³/jump', 'ISK/hr', 'Jumps', 'm³', 'Days to Sell', 'Buy Price', 'Sell Price', 'Volume Available', 'Add to Run'].map((h) => (
                       <TableHead key={h} className="font-bold text-text-emphasis">{h}</TableHead>
                     ))}
                   </TableRow>
@@ -621,7 +748,7 @@ export default function MarketScanner({ initialSourceRegion, initialDestRegion }
                 <TableBody>
                   {Array.from({ length: 8 }).map((_, i) => (
                     <TableRow key={i} className="border-overlay-subtle">
-                      {Array.from({ length: 9 }).map((__, j) => (
+                      {Array.from({ length: 12 }).map((__, j) => (
                         <TableCell key={j}>
                           <Skeleton className="h-4 w-full bg-background-elevated" />
                         </TableCell>
@@ -654,6 +781,9 @@ export default function MarketScanner({ initialSourceRegion, initialDestRegion }
                     <TableHead className="font-bold text-text-emphasis">Item</TableHead>
                     <TableHead className="font-bold text-text-emphasis">Indicator</TableHead>
                     <TableHead className="font-bold text-text-emphasis text-right">Net Profit/unit</TableHead>
+                    <TableHead className="font-bold text-text-emphasis text-right">ISK/m³/jump</TableHead>
+                    <TableHead className="font-bold text-text-emphasis text-right">ISK/hr</TableHead>
+                    <TableHead className="font-bold text-text-emphasis text-right">Jumps</TableHead>
                     <TableHead className="font-bold text-text-emphasis text-right">m³</TableHead>
                     <TableHead className="font-bold text-text-emphasis text-right">Days to Sell</TableHead>
                     <TableHead className="font-bold text-text-emphasis text-right">Buy Price</TableHead>
@@ -711,6 +841,22 @@ export default function MarketScanner({ initialSourceRegion, initialDestRegion }
                             </span>
                           ) : '—'}
                         </TableCell>
+                        <TableCell className="text-right text-sm text-text-secondary">
+                          {row.iskPerM3PerJump !== undefined ? formatISK(row.iskPerM3PerJump) : '—'}
+                        </TableCell>
+                        <TableCell className="text-right text-sm text-text-secondary">
+                          {row.iskPerHour !== undefined ? formatISK(row.iskPerHour) : '—'}
+                        </TableCell>
+                        <TableCell className="text-right text-sm text-text-secondary">
+                          {row.jumps !== undefined ? (
+                            <span title={row.minSecurity !== undefined ? `Lowest security ${row.minSecurity.toFixed(1)}` : undefined}>
+                              {row.jumps}
+                              {row.minSecurity !== undefined && row.minSecurity < 0.45 && (
+                                <AlertTriangle className="inline h-3.5 w-3.5 ml-1 text-amber-500" />
+                              )}
+                            </span>
+                          ) : '—'}
+                        </TableCell>
                         <TableCell className="text-right text-sm text-text-secondary">
                           {row.volumeM3 !== undefined ? formatNumber(row.volumeM3, 2) : '—'}
                         </TableCell>
@@ -808,6 +954,128 @@ export default function MarketScanner({ initialSourceRegion, initialDestRegion }
           </DialogContent>
         </Dialog>
 
+        {/* Optimize into Run Dialog */}
+        <Dialog
+          open={optimize.open}
+          onOpenChange={(open) => {
+            if (!open) setOptimize(EMPTY_OPTIMIZE);
+          }}
+        >
+          <DialogContent className="max-w-sm bg-background-panel border-overlay-medium">
+            <DialogHeader>
+              <DialogTitle className="text-text-emphasis">Optimize into Run</DialogTitle>
+            </DialogHeader>
+            {optimize.plan ? (
+              <div className="flex flex-col gap-2 mt-2 text-sm">
+                <p className="text-text-emphasis">
+                  Added {optimize.plan.items.length} item{optimize.plan.items.length === 1 ? '' : 's'} to the run.
+                </p>
+                {optimize.plan.items.map((item) => (
+                  <div key={item.id} className="flex justify-between text-text-secondary">
+                    <span>{item.typeName}</span>
+                    <span>{formatNumber(item.quantityPlanned, 0)}</span>
+                  </div>
+                ))}
+                <div className="flex justify-between text-text-secondary border-t border-overlay-subtle pt-2">
+                  <span>Cargo</span>
+                  <span>{formatNumber(optimize.plan.totalVolumeM3, 0)} m³ ({formatNumber(optimize.plan.remainingVolumeM3, 0)} m³ free)</span>
+                </div>
+                <div className="flex justify-between text-text-secondary">
+                  <span>Cost</span>
+                  <span>{formatISK(optimize.plan.totalCostIsk)}</span>
+                </div>
+                <div className="flex justify-between font-semibold" style={{ color: 'var(--color-success-teal)' }}>
+                  <span>Expected profit</span>
+                  <span>{formatISK(optimize.plan.expectedProfitIsk)}</span>
+                </div>
+              </div>
+            ) : (
+              <div className="flex flex-col gap-4 mt-2">
+                <div>
+                  <label className="text-xs text-text-secondary mb-1 block">Hauling Run</label>
+                  <Select
+                    value={optimize.selectedRunId !== '' ? String(optimize.selectedRunId) : ''}
+                    onValueChange={(v) => setOptimize({ ...optimize, selectedRunId: Number(v) })}
+                  >
+                    <SelectTrigger className="bg-background-void border-overlay-strong text-text-emphasis">
+                      <SelectValue placeholder="Select run" />
+                    </SelectTrigger>
+                    <SelectContent className="bg-background-panel border-overlay-medium">
+                      {runs.map((run) => (
+                        <SelectItem key={run.id} value={String(run.id)} className="text-text-emphasis">
+                          {run.name}{run.maxVolumeM3 ? ` (${formatNumber(run.maxVolumeM3, 0)} m³)` : ''}
+                        </SelectItem>
+                      ))}
+                    </SelectContent>
+                  </Select>
+                </div>
+                <div>
+                  <label className="text-xs text-text-secondary mb-1 block">Available Capital (ISK, blank = no limit)</label>
+                  <Input
+                    type="number"
+                    min={0}
+                    value={optimize.capitalIsk}
+                    onChange={(e) => setOptimize({ ...optimize, capitalIsk: e.target.value })}
+                    className="bg-background-void border-overlay-strong text-text-emphasis"
+                  />
+                </div>
+                <div className="flex gap-2">
+                  <div className="flex-1">
+                    <label className="text-xs text-text-secondary mb-1 block">Max Units/Type</label>
+                    <Input
+                      type="number"
+                      min={0}
+                      value={optimize.maxUnitsPerType}
+                      onChange={(e) => setOptimize({ ...optimize, maxUnitsPerType: e.target.value })}
+                      placeholder="Any"
+                      className="bg-background-void border-overlay-strong text-text-emphasis"
+                    />
+                  </div>
+                  <div className="flex-1">
+                    <label className="text-xs text-text-secondary mb-1 block">Max Days to Sell</label>
+                    <Input
+                      type="number"
+                      min={0}
+                      value={optimize.maxDaysToSell}
+                      onChange={(e) => setOptimize({ ...optimize, maxDaysToSell: e.target.value })}
+                      className="bg-background-void border-overlay-strong text-text-emphasis"
+                    />
+                  </div>
+                </div>
+                <div>
+                  <label className="text-xs text-text-secondary mb-1 block">Sales Tax %</label>
+                  <Input
+                    type="number"
+                    min={0}
+                    step={0.1}
+                    value={optimize.salesTaxPct}
+                    onChange={(e) => setOptimize({ ...optimize, salesTaxPct: e.target.value })}
+                    className="bg-background-void border-overlay-strong text-text-emphasis"
+                  />
+                </div>
+                {optimize.error && (
+                  <p className="text-sm" style={{ color: 'var(--color-danger-rose)' }}>{optimize.error}</p>
+                )}
+              </div>
+            )}
+            <DialogFooter className="mt-4">
+              {optimize.plan ? (
+                <Button onClick={() => setOptimize(EMPTY_OPTIMIZE)} className="w-full">
+                  Done
+                </Button>
+              ) : (
+                <Button
+                  onClick={handleOptimize}
+                  disabled={optimize.selectedRunId === ''}
+                  className="w-full"
+                >
+                  Optimize
+                </Button>
+              )}
+            </DialogFooter>
+          </DialogContent>
+        </Dialog>
+
         {/* Structures Manager Dialog */}
         <UserStructuresDialog
           open={structuresDialogOpen}
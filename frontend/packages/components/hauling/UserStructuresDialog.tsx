@@ -1,6 +1,7 @@
 import { useState, useEffect, useCallback } from 'react';
 import { Dialog, DialogContent, DialogHeader, DialogTitle } from '@/components/ui/dialog';
 import { Button } from '@/components/ui/button';
+import { Input } from '@/components/ui/input';
 import { Label } from '@/components/ui/label';
 import { Table, TableHeader, TableBody, TableRow, TableHead, TableCell } from '@/components/ui/table';
 import { Badge } from '@/components/ui/badge';
@@ -32,6 +33,7 @@ export default function UserStructuresDialog({
   const [addError, setAddError] = useState<string | null>(null);
   const [scanningId, setScanningId] = useState<number | null>(null);
   const [deletingId, setDeletingId] = useState<number | null>(null);
+  const [brokerFeeDrafts, setBrokerFeeDrafts] = useState<Record<number, string>>({});
 
   const fetchStructures = useCallback(async () => {
     setLoading(true);
@@ -156,6 +158,41 @@ export default function UserStructuresDialog({
     }
   };
 
+  // Saves the owner-set broker rate; an empty value clears it back to the NPC-style rate.
+  const handleBrokerFeeSave = async (structure: UserTradingStructure) => {
+    const draft = brokerFeeDrafts[structure.id];
+    if (draft === undefined) return;
+    const value = draft.trim() === '' ? null : Number(draft);
+    if (value !== null && (isNaN(value) || value < 0 || value > 100)) {
+      toast.error('Broker fee must be between 0 and 100%.');
+      return;
+    }
+    try {
+      const res = await fetch(`/api/hauling/structures?id=${structure.id}`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ brokerFeePct: value }),
+      });
+      if (res.ok) {
+        setStructures((prev) =>
+          prev.map((s) => (s.id === structure.id ? { ...s, brokerFeePct: value ?? undefined } : s)),
+        );
+        setBrokerFeeDrafts((prev) => {
+          const next = { ...prev };
+          delete next[structure.id];
+          return next;
+        });
+        onStructuresChanged();
+        toast.success('Broker fee saved.');
+      } else {
+        toast.error('Failed to save broker fee.');
+      }
+    } catch (err) {
+      console.error('Failed to save broker fee:', err);
+      toast.error('An unexpected error occurred.');
+    }
+  };
+
   const handleScan = async (structure: UserTradingStructure) => {
     setScanningId(structure.id);
     try {
@@ -294,6 +331,7 @@ export default function UserStructuresDialog({
                   <TableHead className="font-bold text-text-emphasis">Name</TableHead>
                   <TableHead className="font-bold text-text-emphasis">Status</TableHead>
                   <TableHead className="font-bold text-text-emphasis">Last Scanned</TableHead>
+                  <TableHead className="font-bold text-text-emphasis">Broker Fee %</TableHead>
                   <TableHead className="font-bold text-text-emphasis">Actions</TableHead>
                 </TableRow>
               </TableHeader>
@@ -333,6 +371,22 @@ export default function UserStructuresDialog({
                         ? new Date(structure.lastScannedAt).toLocaleString()
                         : 'Never'}
                     </TableCell>
+                    <TableCell>
+                      <Input
+                        type="number"
+                        step="0.01"
+                        min={0}
+                        max={100}
+                        placeholder="NPC"
+                        aria-label={`Broker fee for ${structure.name}`}
+                        value={brokerFeeDrafts[structure.id] ?? (structure.brokerFeePct != null ? String(structure.brokerFeePct) : '')}
+                        onChange={(e) =>
+                          setBrokerFeeDrafts((prev) => ({ ...prev, [structure.id]: e.target.value }))
+                        }
+                        onBlur={() => handleBrokerFeeSave(structure)}
+                        className="w-20 h-8 bg-background-elevated border-overlay-strong text-text-emphasis"
+                      />
+                    </TableCell>
                     <TableCell>
                       <div className="flex items-center gap-2">
                         <Button
@@ -4,6 +4,7 @@ import { useSession } from 'next-auth/react';
 import HaulingAnalytics from '../HaulingAnalytics';
 import {
   HaulingRouteAnalytics,
+  HaulingLegAnalytics,
   HaulingItemAnalytics,
   HaulingProfitDataPoint,
   HaulingRunDurationSummary,
@@ -90,12 +91,14 @@ function setupMocks(
   items: HaulingItemAnalytics[] = mockItems,
   timeseries: HaulingProfitDataPoint[] = mockTimeseries,
   summary: HaulingRunDurationSummary = mockSummary,
+  legs: HaulingLegAnalytics[] = [],
 ) {
   mockFetch
     .mockResolvedValueOnce({ ok: true, json: async () => routes } as Response)
     .mockResolvedValueOnce({ ok: true, json: async () => items } as Response)
     .mockResolvedValueOnce({ ok: true, json: async () => timeseries } as Response)
-    .mockResolvedValueOnce({ ok: true, json: async () => summary } as Response);
+    .mockResolvedValueOnce({ ok: true, json: async () => summary } as Response)
+    .mockResolvedValueOnce({ ok: true, json: async () => legs } as Response);
 }
 
 describe('HaulingAnalytics Component', () => {
@@ -215,6 +218,37 @@ describe('HaulingAnalytics Component', () => {
     });
   });
 
+  it('should show leg performance when legs exist', async () => {
+    const legs: HaulingLegAnalytics[] = [
+      {
+        fromRegionId: 10000043,
+        toRegionId: 10000032,
+        totalLegs: 2,
+        totalRuns: 2,
+        totalProfitIsk: 80000000,
+        avgProfitIsk: 40000000,
+        avgMarginPct: 9.5,
+      },
+    ];
+    setupMocks(mockRoutes, mockItems, mockTimeseries, mockSummary, legs);
+    render(<HaulingAnalytics />);
+
+    await waitFor(() => {
+      expect(screen.getByText('Profit by Leg')).toBeInTheDocument();
+    });
+    expect(screen.getByText('9.5%')).toBeInTheDocument();
+  });
+
+  it('should not show leg performance without legs', async () => {
+    setupMocks();
+    render(<HaulingAnalytics />);
+
+    await waitFor(() => {
+      expect(screen.getByText('Route Performance')).toBeInTheDocument();
+    });
+    expect(screen.queryByText('Profit by Leg')).not.toBeInTheDocument();
+  });
+
   it('should match snapshot with data loaded', async () => {
     setupMocks();
     const { container } = render(<HaulingAnalytics />);
@@ -2,7 +2,7 @@ import React from 'react';
 import { render, screen, waitFor } from '@testing-library/react';
 import { useSession } from 'next-auth/react';
 import HaulingRunDetail from '../HaulingRunDetail';
-import { HaulingRun, HaulingRunPnlEntry, HaulingRunPnlSummary } from '@industry-tool/client/data/models';
+import { HaulingRun, HaulingRunLeg, HaulingRunPnlEntry, HaulingRunPnlSummary } from '@industry-tool/client/data/models';
 
 jest.mock('next-auth/react');
 jest.mock('next/link', () => {
@@ -101,16 +101,67 @@ const mockPnlEntries: HaulingRunPnlEntry[] = [
 const mockPnlSummary: HaulingRunPnlSummary = {
   totalRevenueIsk: 36000,
   totalCostIsk: 27500,
+  salesTaxIsk: 0,
+  brokerFeesIsk: 0,
   netProfitIsk: 8500,
   marginPct: 23.6,
   itemsSold: 1,
   itemsPending: 0,
 };
 
+const mockMultiStopRun: HaulingRun = {
+  ...mockRun,
+  id: 4,
+  name: 'Three Hub Loop',
+  toRegionId: 10000032,
+  stops: [
+    { id: 11, runId: 4, seq: 0, regionId: 10000002, name: 'Jita 4-4', createdAt: '2026-02-22T12:00:00Z' },
+    { id: 12, runId: 4, seq: 1, regionId: 10000043, createdAt: '2026-02-22T12:00:00Z' },
+    { id: 13, runId: 4, seq: 2, regionId: 10000032, createdAt: '2026-02-22T12:00:00Z' },
+  ],
+};
+
+const mockLegs: HaulingRunLeg[] = [
+  {
+    seq: 0,
+    fromStop: mockMultiStopRun.stops![0],
+    toStop: mockMultiStopRun.stops![1],
+    loadM3: 100,
+    capacityM3: 300000,
+    overCapacity: false,
+    itemsAboard: 1,
+    itemsDelivered: 0,
+    plannedProfitIsk: 0,
+    revenueIsk: 0,
+    costIsk: 0,
+    netProfitIsk: 0,
+  },
+  {
+    seq: 1,
+    fromStop: mockMultiStopRun.stops![1],
+    toStop: mockMultiStopRun.stops![2],
+    loadM3: 100,
+    capacityM3: 300000,
+    overCapacity: false,
+    itemsAboard: 1,
+    itemsDelivered: 1,
+    plannedProfitIsk: 15000,
+    revenueIsk: 0,
+    costIsk: 0,
+    netProfitIsk: 0,
+  },
+];
+
 // Helper to set up fetch mocks for a given run
 function setupFetchMocks(run: HaulingRun, pnlEntries?: HaulingRunPnlEntry[], pnlSummary?: HaulingRunPnlSummary) {
   mockFetch.mockImplementation((url: string) => {
     if (typeof url === 'string') {
+      if (url.includes('/legs')) {
+        return Promise.resolve({
+          ok: true,
+          json: async () => mockLegs,
+        });
+      }
       if (url.includes('/api/hauling/runs/') && !url.includes('/items') && !url.includes('/status')) {
         return Promise.resolve({
           ok: true,
@@ -335,6 +386,36 @@ describe('HaulingRunDetail Component', () => {
     });
   });
 
+  it('should not show legs for single-leg runs', async () => {
+    mockUseSession.mockReturnValue(mockSession);
+    setupFetchMocks(mockRun);
+
+    render(<HaulingRunDetail runId={1} />);
+
+    await waitFor(() => {
+      expect(screen.getByText('Jita to Amarr Run')).toBeInTheDocument();
+    });
+
+    expect(screen.getByText('Edit Stops')).toBeInTheDocument();
+    expect(screen.queryByText('Legs')).not.toBeInTheDocument();
+    expect(mockFetch).not.toHaveBeenCalledWith('/api/hauling/runs/1/legs');
+  });
+
+  it('should show stops and legs for multi-stop runs', async () => {
+    mockUseSession.mockReturnValue(mockSession);
+    setupFetchMocks(mockMultiStopRun);
+
+    render(<HaulingRunDetail runId={4} />);
+
+    await waitFor(() => {
+      expect(screen.getByText('Legs')).toBeInTheDocument();
+    });
+
+    expect(screen.getByText('Jita 4-4 → Domain → Sinq Laison')).toBeInTheDocument();
+    expect(screen.getByText('Buy At / Sell At')).toBeInTheDocument();
+    expect(mockFetch).toHaveBeenCalledWith('/api/hauling/runs/4/legs');
+  });
+
   it('should show run not found when fetch returns null', async () => {
     mockUseSession.mockReturnValue(mockSession);
     mockFetch.mockImplementation((url: string) => {
@@ -140,9 +140,10 @@ afterEach(() => {
 describe('MarketScanner', () => {
   function setupDefaultFetches() {
     mockFetch
-      .mockResolvedValueOnce({ ok: true, json: async () => mockRuns })      // /api/hauling/runs
       .mockResolvedValueOnce({ ok: true, json: async () => mockStations })  // /api/hauling/stations
-      .mockResolvedValueOnce({ ok: true, json: async () => mockStructures }); // /api/hauling/structures
+      .mockResolvedValueOnce({ ok: true, json: async () => mockStructures }) // /api/hauling/structures
+      .mockResolvedValueOnce({ ok: true, json: async () => [] })            // /api/transport/profiles
+      .mockResolvedValueOnce({ ok: true, json: async () => mockRuns });     // /api/hauling/runs
   }
 
   it('renders loading skeleton while fetching results', async () => {
@@ -266,4 +266,37 @@ describe('UserStructuresDialog', () => {
     // Verify characters were loaded (fetch called twice: structures + characters)
     expect(mockFetch).toHaveBeenCalledWith('/api/characters');
   });
+
+  it('saves a structure broker fee on blur', async () => {
+    mockFetch.mockResolvedValueOnce({
+      ok: true,
+      json: async () => mockStructures,
+    });
+    mockFetch.mockResolvedValueOnce({
+      ok: true,
+      json: async () => mockCharacters,
+    });
+    mockFetch.mockResolvedValueOnce({
+      ok: true,
+      json: async () => ({}),
+    });
+
+    render(<UserStructuresDialog {...defaultProps} />);
+
+    await waitFor(() => {
+      expect(screen.getByText('Perimeter - Tranquility Trading Tower')).toBeTruthy();
+    });
+
+    const input = screen.getByLabelText('Broker fee for Perimeter - Tranquility Trading Tower');
+    fireEvent.change(input, { target: { value: '1.5' } });
+    fireEvent.blur(input);
+
+    await waitFor(() => {
+      expect(mockFetch).toHaveBeenCalledWith('/api/hauling/structures?id=1', {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ brokerFeePct: 1.5 }),
+      });
+    });
+  });
 });
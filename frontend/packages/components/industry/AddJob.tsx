@@ -4,6 +4,7 @@ import {
   BlueprintLevel,
   ManufacturingCalcResult,
   ReactionSystem,
+  TradeFees,
 } from "@industry-tool/client/data/models";
 import { formatISK, formatNumber, formatDuration } from "@industry-tool/utils/formatting";
 import { Loader2, Plus, AlertTriangle } from "lucide-react";
@@ -38,6 +39,8 @@ export default function AddJob({ onJobAdded }: Props) {
   const [facilityTax, setFacilityTax] = useState(1.0);
   const [systemId, setSystemId] = useState<number>(0);
   const [notes, setNotes] = useState("");
+  const [tradeFees, setTradeFees] = useState<TradeFees[]>([]);
+  const [sellCharacterId, setSellCharacterId] = useState("none");
 
   const [detectedLevel, setDetectedLevel] = useState<BlueprintLevel | null>(null);
   const [detectedForBlueprintId, setDetectedForBlueprintId] = useState<number | null>(null);
@@ -56,6 +59,11 @@ export default function AddJob({ onJobAdded }: Props) {
       .then((res) => res.json())
       .then((data) => setSystems(data))
       .catch((err) => console.error("Failed to fetch systems:", err));
+
+    fetch("/api/trade-fees")
+      .then((res) => (res.ok ? res.json() : []))
+      .then((data) => setTradeFees(Array.isArray(data) ? data : []))
+      .catch((err) => console.error("Failed to fetch trade fees:", err));
   }, []);
 
   useEffect(() => {
@@ -112,6 +120,7 @@ export default function AddJob({ onJobAdded }: Props) {
           structure,
           rig,
           security,
+          character_id: sellCharacterId !== "none" ? Number(sellCharacterId) : undefined,
         }),
       });
       if (res.ok) {
@@ -123,7 +132,7 @@ export default function AddJob({ onJobAdded }: Props) {
     } finally {
       setCalcLoading(false);
     }
-  }, [selectedBlueprint, runs, meLevel, teLevel, industrySkill, advIndustrySkill, systemId, facilityTax, structure, rig, security, activity]);
+  }, [selectedBlueprint, runs, meLevel, teLevel, industrySkill, advIndustrySkill, systemId, facilityTax, structure, rig, security, activity, sellCharacterId]);
 
   useEffect(() => {
     calculate();
@@ -382,6 +391,23 @@ export default function AddJob({ onJobAdded }: Props) {
             onChange={(e) => setFacilityTax(parseFloat(e.target.value) || 0)}
           />
         </div>
+
+        {tradeFees.length > 0 && (
+          <div className="min-w-[200px]">
+            <Label className="text-xs text-text-secondary mb-1 block">Sell As</Label>
+            <Select value={sellCharacterId} onValueChange={setSellCharacterId}>
+              <SelectTrigger><SelectValue /></SelectTrigger>
+              <SelectContent>
+                <SelectItem value="none">Ignore sell fees</SelectItem>
+                {tradeFees.map((f) => (
+                  <SelectItem key={f.characterId} value={String(f.characterId)}>
+                    {f.characterName} ({(f.salesTaxPct + f.brokerFeePct).toFixed(2)}%)
+                  </SelectItem>
+                ))}
+              </SelectContent>
+            </Select>
+          </div>
+        )}
       </div>
 
       <div className="flex gap-2 flex-wrap mb-3 items-end">
@@ -474,6 +500,12 @@ export default function AddJob({ onJobAdded }: Props) {
               <span className="text-xs text-text-muted block">Output Value</span>
               <span className="text-sm text-text-emphasis">{formatISK(calcResult.outputValue)}</span>
             </div>
+            {calcResult.salesTax + calcResult.brokerFee > 0 && (
+              <div>
+                <span className="text-xs text-text-muted block">Tax + Broker</span>
+                <span className="text-sm text-text-emphasis">{formatISK(calcResult.salesTax + calcResult.brokerFee)}</span>
+              </div>
+            )}
             <div>
               <span className="text-xs text-text-muted block">Profit</span>
               <span className={`text-sm ${calcResult.profit >= 0 ? "text-teal-success" : "text-rose-danger"}`}>
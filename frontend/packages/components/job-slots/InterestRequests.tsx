@@ -108,6 +108,41 @@ export default function InterestRequests() {
     }
   };
 
+  const handleStartRental = async (request: JobSlotInterestRequest) => {
+    const body: Record<string, unknown> = { interestId: request.id };
+    if (!request.durationDays) {
+      const days = prompt('Rental length in days');
+      if (!days) return;
+      const parsed = parseInt(days, 10);
+      if (!(parsed > 0)) {
+        toast.error('Rental length must be a positive number of days');
+        return;
+      }
+      const endsAt = new Date();
+      endsAt.setDate(endsAt.getDate() + parsed);
+      body.endsAt = endsAt.toISOString();
+    }
+
+    try {
+      const response = await fetch('/api/job-slots/agreements', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(body),
+      });
+
+      if (response.ok) {
+        toast.success('Rental agreement started — slots are now reserved');
+        fetchRequests();
+      } else {
+        const error = await response.json();
+        toast.error(error.error || 'Failed to start rental');
+      }
+    } catch (error) {
+      console.error('Start rental failed:', error);
+      toast.error('Failed to start rental');
+    }
+  };
+
   if (loading) {
     return (
       <div className="flex justify-center items-center min-h-[400px]">
@@ -241,11 +276,22 @@ export default function InterestRequests() {
                       </TableCell>
                       <TableCell className="text-text-secondary text-sm">{new Date(request.createdAt).toLocaleDateString()}</TableCell>
                       <TableCell className="text-center">
-                        {request.status === 'pending' && (
+                        {(request.status === 'pending' || request.status === 'accepted') && (
                           <div className="flex gap-1 justify-center">
                             <Button
                               size="sm"
                               className="bg-teal-success hover:bg-teal-success/80"
+                              onClick={() => handleStartRental(request)}
+                            >
+                              Start Rental
+                            </Button>
+                          </div>
+                        )}
+                        {request.status === 'pending' && (
+                          <div className="flex gap-1 justify-center mt-1">
+                            <Button
+                              size="sm"
+                              variant="outline"
                               onClick={() => handleStatusUpdate(request.id, 'accepted', 'Accept')}
                             >
                               Accept
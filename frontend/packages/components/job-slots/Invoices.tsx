@@ -0,0 +1,314 @@
+import { useState, useEffect } from 'react';
+import { useSession } from 'next-auth/react';
+import { Loader2, Plus, Copy } from 'lucide-react';
+import { Button } from '@/components/ui/button';
+import { Input } from '@/components/ui/input';
+import { Label } from '@/components/ui/label';
+import { Badge } from '@/components/ui/badge';
+import { Table, TableHeader, TableBody, TableRow, TableHead, TableCell } from '@/components/ui/table';
+import { Dialog, DialogContent, DialogHeader, DialogTitle, DialogFooter } from '@/components/ui/dialog';
+import { Select, SelectTrigger, SelectValue, SelectContent, SelectItem } from '@/components/ui/select';
+import { toast } from '@/components/ui/sonner';
+import { formatISK } from '@industry-tool/utils/formatting';
+import { Contact } from '@industry-tool/components/contacts/ContactsList';
+
+type InvoicePayment = {
+  id: number;
+  invoiceId: number;
+  journalRefId: number;
+  characterId: number;
+  amount: number;
+  paidAt: string;
+};
+
+type Invoice = {
+  id: number;
+  issuerUserId: number;
+  issuerName: string;
+  payerUserId: number;
+  payerName: string;
+  agreementId: number | null;
+  referenceCode: string;
+  description: string;
+  amount: number;
+  amountPaid: number;
+  dueAt: string;
+  status: string;
+  paidAt: string | null;
+  createdAt: string;
+  payments: InvoicePayment[];
+};
+
+const STATUS_CLASSES: Record<string, string> = {
+  unpaid: 'bg-overlay-subtle border-overlay-strong text-text-secondary',
+  partial: 'bg-blue-science/10 border-blue-science/30 text-blue-science',
+  paid: 'bg-teal-success/10 border-teal-success/30 text-teal-success',
+  overdue: 'bg-amber-manufacturing/10 border-amber-manufacturing/30 text-amber-manufacturing',
+  cancelled: 'bg-rose-danger/10 border-rose-danger/30 text-rose-danger',
+};
+
+const OPEN_STATUSES = ['unpaid', 'partial', 'overdue'];
+
+export default function Invoices() {
+  const { data: session } = useSession();
+  const currentUserId = session?.providerAccountId ? parseInt(session.providerAccountId) : null;
+  const [invoices, setInvoices] = useState<Invoice[]>([]);
+  const [contacts, setContacts] = useState<Contact[]>([]);
+  const [loading, setLoading] = useState(true);
+
+  const [dialogOpen, setDialogOpen] = useState(false);
+  const [formData, setFormData] = useState({ payerUserId: 0, amount: 0, description: '', dueDate: '' });
+
+  useEffect(() => {
+    if (session) {
+      fetchInvoices();
+      fetchContacts();
+    }
+  }, [session]);
+
+  const fetchInvoices = async () => {
+    setLoading(true);
+    try {
+      const response = await fetch('/api/invoices');
+      if (response.ok) {
+        const data = await response.json();
+        setInvoices(data || []);
+      }
+    } catch (error) {
+      console.error('Failed to fetch invoices:', error);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const fetchContacts = async () => {
+    try {
+      const response = await fetch('/api/contacts');
+      if (response.ok) {
+        const data: Contact[] = await response.json();
+        setContacts((data || []).filter((c) => c.status === 'accepted'));
+      }
+    } catch {
+      // Silently fail
+    }
+  };
+
+  const contactOptions = contacts.map((contact) => {
+    const isRequester = contact.requesterUserId === currentUserId;
+    return {
+      userId: isRequester ? contact.recipientUserId : contact.requesterUserId,
+      name: isRequester ? contact.recipientName : contact.requesterName,
+    };
+  });
+
+  const openCreate = () => {
+    setFormData({ payerUserId: 0, amount: 0, description: '', dueDate: '' });
+    setDialogOpen(true);
+  };
+
+  const handleCreate = async () => {
+    if (!formData.payerUserId || formData.amount <= 0 || !formData.description.trim()) {
+      toast.error('Pick a contact and enter an amount and description');
+      return;
+    }
+
+    try {
+      const response = await fetch('/api/invoices', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({
+          payerUserId: formData.payerUserId,
+          amount: formData.amount,
+          description: formData.description,
+          dueAt: formData.dueDate ? new Date(formData.dueDate + 'T23:59:59Z').toISOString() : undefined,
+        }),
+      });
+      if (response.ok) {
+        toast.success('Invoice sent');
+        setDialogOpen(false);
+        fetchInvoices();
+      } else {
+        const error = await response.json();
+        toast.error(error.error || 'Failed to create invoice');
+      }
+    } catch (error) {
+      console.error('Create invoice failed:', error);
+      toast.error('Failed to create invoice');
+    }
+  };
+
+  const handleCancel = async (id: number) => {
+    if (!confirm('Cancel this invoice? Payments already received are kept.')) return;
+
+    try {
+      const response = await fetch(`/api/invoices/${id}/cancel`, { method: 'PUT' });
+      if (response.ok) {
+        toast.success('Invoice cancelled');
+        fetchInvoices();
+      } else {
+        const error = await response.json();
+        toast.error(error.error || 'Failed to cancel invoice');
+      }
+    } catch (error) {
+      console.error('Cancel invoice failed:', error);
+      toast.error('Failed to cancel invoice');
+    }
+  };
+
+  const copyReference = (code: string) => {
+    navigator.clipboard.writeText(code);
+    toast.success(`Copied ${code}`);
+  };
+
+  if (loading) {
+    return (
+      <div className="flex justify-center items-center min-h-[400px]">
+        <Loader2 className="h-8 w-8 animate-spin text-primary" />
+      </div>
+    );
+  }
+
+  return (
+    <div>
+      <div className="flex justify-between items-center mb-3">
+        <p className="text-sm text-text-muted">
+          Pay an invoice by giving ISK to the issuer with the reference code in the transfer reason.
+          Payments are matched from the issuer&apos;s wallet journal.
+        </p>
+        <Button onClick={openCreate}>
+          <Plus className="h-4 w-4 mr-1" />
+          New Invoice
+        </Button>
+      </div>
+
+      {invoices.length === 0 ? (
+        <div className="bg-background-panel rounded-sm border border-overlay-subtle p-8 text-center">
+          <h3 className="text-lg font-semibold text-text-secondary">No invoices</h3>
+          <p className="text-sm text-text-muted mt-1">
+            Invoice a renter from the Rental Agreements tab, or bill a contact for any other service.
+          </p>
+        </div>
+      ) : (
+        <div className="overflow-x-auto rounded-sm border border-overlay-subtle">
+          <Table>
+            <TableHeader>
+              <TableRow className="bg-background-void">
+                <TableHead>Reference</TableHead>
+                <TableHead>From</TableHead>
+                <TableHead>To</TableHead>
+                <TableHead>For</TableHead>
+                <TableHead className="text-right">Amount</TableHead>
+                <TableHead className="text-right">Paid</TableHead>
+                <TableHead>Due</TableHead>
+                <TableHead>Status</TableHead>
+                <TableHead className="text-center">Action</TableHead>
+              </TableRow>
+            </TableHeader>
+            <TableBody>
+              {invoices.map((invoice) => (
+                <TableRow key={invoice.id} className="hover:bg-interactive-hover">
+                  <TableCell className="font-mono text-text-emphasis whitespace-nowrap">
+                    {invoice.referenceCode}
+                    <button
+                      className="ml-1 text-text-muted hover:text-text-emphasis align-middle"
+                      onClick={() => copyReference(invoice.referenceCode)}
+                      aria-label="Copy reference code"
+                    >
+                      <Copy className="h-3.5 w-3.5" />
+                    </button>
+                  </TableCell>
+                  <TableCell className="text-text-emphasis">{invoice.issuerName}</TableCell>
+                  <TableCell className="text-text-emphasis">{invoice.payerName}</TableCell>
+                  <TableCell className="text-text-secondary text-sm">{invoice.description}</TableCell>
+                  <TableCell className="text-right text-text-emphasis">{formatISK(invoice.amount)}</TableCell>
+                  <TableCell className="text-right" title={`${invoice.payments.length} payment(s)`}>
+                    {formatISK(invoice.amountPaid)}
+                  </TableCell>
+                  <TableCell className="text-text-secondary text-sm whitespace-nowrap">
+                    {new Date(invoice.dueAt).toLocaleDateString()}
+                  </TableCell>
+                  <TableCell>
+                    <Badge className={`border capitalize cursor-default ${STATUS_CLASSES[invoice.status] || STATUS_CLASSES.unpaid}`}>
+                      {invoice.status}
+                    </Badge>
+                  </TableCell>
+                  <TableCell className="text-center">
+                    {invoice.issuerUserId === currentUserId && OPEN_STATUSES.includes(invoice.status) && (
+                      <Button
+                        variant="outline"
+                        size="sm"
+                        className="text-rose-danger border-rose-danger hover:bg-rose-danger/10"
+                        onClick={() => handleCancel(invoice.id)}
+                      >
+                        Cancel
+                      </Button>
+                    )}
+                  </TableCell>
+                </TableRow>
+              ))}
+            </TableBody>
+          </Table>
+        </div>
+      )}
+
+      <Dialog open={dialogOpen} onOpenChange={setDialogOpen}>
+        <DialogContent className="max-w-md bg-background-panel border-overlay-medium">
+          <DialogHeader>
+            <DialogTitle className="text-text-emphasis">New Invoice</DialogTitle>
+          </DialogHeader>
+          <div className="flex flex-col gap-3 pt-1">
+            <div>
+              <Label className="text-sm text-text-secondary mb-1 block">Bill To</Label>
+              <Select
+                value={formData.payerUserId ? String(formData.payerUserId) : ""}
+                onValueChange={(val) => setFormData({ ...formData, payerUserId: parseInt(val) })}
+              >
+                <SelectTrigger><SelectValue placeholder="Select a contact" /></SelectTrigger>
+                <SelectContent>
+                  {contactOptions.map((option) => (
+                    <SelectItem key={option.userId} value={String(option.userId)}>
+                      {option.name}
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            </div>
+
+            <div>
+              <Label className="text-sm text-text-secondary mb-1 block">Amount (ISK)</Label>
+              <Input
+                type="number"
+                value={formData.amount}
+                onChange={(e) => setFormData({ ...formData, amount: parseFloat(e.target.value) || 0 })}
+                min={0}
+              />
+            </div>
+
+            <div>
+              <Label className="text-sm text-text-secondary mb-1 block">Description</Label>
+              <Input
+                value={formData.description}
+                onChange={(e) => setFormData({ ...formData, description: e.target.value })}
+                placeholder="e.g., Freighter run Jita → 1DQ"
+              />
+            </div>
+
+            <div>
+              <Label className="text-sm text-text-secondary mb-1 block">Due Date (optional)</Label>
+              <Input
+                type="date"
+                value={formData.dueDate}
+                onChange={(e) => setFormData({ ...formData, dueDate: e.target.value })}
+              />
+              <span className="text-xs text-text-muted mt-0.5 block">Defaults to 7 days from now</span>
+            </div>
+          </div>
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setDialogOpen(false)}>Cancel</Button>
+            <Button onClick={handleCreate}>Send Invoice</Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+    </div>
+  );
+}
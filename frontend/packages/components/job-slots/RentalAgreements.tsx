@@ -0,0 +1,213 @@
+import { useState, useEffect } from 'react';
+import { useSession } from 'next-auth/react';
+import { Loader2 } from 'lucide-react';
+import { Button } from '@/components/ui/button';
+import { Badge } from '@/components/ui/badge';
+import { Table, TableHeader, TableBody, TableRow, TableHead, TableCell } from '@/components/ui/table';
+import { toast } from '@/components/ui/sonner';
+
+type JobSlotRentalAgreement = {
+  id: number;
+  listingId: number;
+  interestId: number | null;
+  ownerUserId: number;
+  ownerName: string;
+  renterUserId: number;
+  renterName: string;
+  characterId: number;
+  characterName: string;
+  activityType: string;
+  slots: number;
+  startsAt: string;
+  endsAt: string;
+  status: string;
+  createdAt: string;
+  updatedAt: string;
+  jobsRun: number;
+  jobsActive: number;
+  runsTotal: number;
+};
+
+const ACTIVITY_LABELS: Record<string, string> = {
+  manufacturing: 'Manufacturing',
+  reaction: 'Reactions',
+  copying: 'Copying',
+  invention: 'Invention',
+  me_research: 'ME Research',
+  te_research: 'TE Research',
+};
+
+const STATUS_CLASSES: Record<string, string> = {
+  scheduled: 'bg-amber-manufacturing/10 border-amber-manufacturing/30 text-amber-manufacturing',
+  active: 'bg-teal-success/10 border-teal-success/30 text-teal-success',
+  ended: 'bg-overlay-subtle border-overlay-strong text-text-muted',
+  cancelled: 'bg-rose-danger/10 border-rose-danger/30 text-rose-danger',
+};
+
+// displayStatus folds the agreement window into the stored status so expired
+// and not-yet-started agreements read correctly.
+const displayStatus = (agreement: JobSlotRentalAgreement): string => {
+  if (agreement.status !== 'active') return agreement.status;
+  const now = Date.now();
+  if (new Date(agreement.startsAt).getTime() > now) return 'scheduled';
+  if (new Date(agreement.endsAt).getTime() <= now) return 'ended';
+  return 'active';
+};
+
+export default function RentalAgreements() {
+  const { data: session } = useSession();
+  const currentUserId = session?.providerAccountId ? parseInt(session.providerAccountId) : null;
+  const [agreements, setAgreements] = useState<JobSlotRentalAgreement[]>([]);
+  const [loading, setLoading] = useState(true);
+
+  useEffect(() => {
+    if (session) {
+      fetchAgreements();
+    }
+  }, [session]);
+
+  const fetchAgreements = async () => {
+    setLoading(true);
+    try {
+      const response = await fetch('/api/job-slots/agreements');
+      if (response.ok) {
+        const data = await response.json();
+        setAgreements(data || []);
+      }
+    } catch (error) {
+      console.error('Failed to fetch rental agreements:', error);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleCancel = async (id: number) => {
+    if (!confirm('Cancel this rental agreement? The reserved slots will be released.')) return;
+
+    try {
+      const response = await fetch(`/api/job-slots/agreements/${id}/cancel`, { method: 'PUT' });
+      if (response.ok) {
+        toast.success('Agreement cancelled');
+        fetchAgreements();
+      } else {
+        const error = await response.json();
+        toast.error(error.error || 'Failed to cancel agreement');
+      }
+    } catch (error) {
+      console.error('Cancel agreement failed:', error);
+      toast.error('Failed to cancel agreement');
+    }
+  };
+
+  const handleInvoice = async (id: number) => {
+    try {
+      const response = await fetch('/api/invoices', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ agreementId: id }),
+      });
+      if (response.ok) {
+        const invoice = await response.json();
+        toast.success(`Invoice ${invoice.referenceCode} sent to the renter`);
+      } else {
+        const error = await response.json();
+        toast.error(error.error || 'Failed to create invoice');
+      }
+    } catch (error) {
+      console.error('Create invoice failed:', error);
+      toast.error('Failed to create invoice');
+    }
+  };
+
+  if (loading) {
+    return (
+      <div className="flex justify-center items-center min-h-[400px]">
+        <Loader2 className="h-8 w-8 animate-spin text-primary" />
+      </div>
+    );
+  }
+
+  if (agreements.length === 0) {
+    return (
+      <div className="bg-background-panel rounded-sm border border-overlay-subtle p-8 text-center">
+        <h3 className="text-lg font-semibold text-text-secondary">No rental agreements</h3>
+        <p className="text-sm text-text-muted mt-1">
+          Start a rental from a received interest request to reserve slots for a renter.
+        </p>
+      </div>
+    );
+  }
+
+  return (
+    <div className="overflow-x-auto rounded-sm border border-overlay-subtle">
+      <Table>
+        <TableHeader>
+          <TableRow className="bg-background-void">
+            <TableHead>Owner</TableHead>
+            <TableHead>Renter</TableHead>
+            <TableHead>Character</TableHead>
+            <TableHead>Activity</TableHead>
+            <TableHead className="text-right">Slots</TableHead>
+            <TableHead>Period</TableHead>
+            <TableHead className="text-right">Jobs Run</TableHead>
+            <TableHead className="text-right">Running</TableHead>
+            <TableHead className="text-right">Runs</TableHead>
+            <TableHead>Status</TableHead>
+            <TableHead className="text-center">Action</TableHead>
+          </TableRow>
+        </TableHeader>
+        <TableBody>
+          {agreements.map((agreement) => {
+            const status = displayStatus(agreement);
+            return (
+              <TableRow key={agreement.id} className="hover:bg-interactive-hover">
+                <TableCell className="text-text-emphasis">{agreement.ownerName}</TableCell>
+                <TableCell className="text-text-emphasis">{agreement.renterName}</TableCell>
+                <TableCell className="text-text-emphasis">{agreement.characterName || agreement.characterId}</TableCell>
+                <TableCell>
+                  <Badge className="bg-interactive-selected border border-border-active text-blue-science hover:bg-interactive-active cursor-default">
+                    {ACTIVITY_LABELS[agreement.activityType] || agreement.activityType}
+                  </Badge>
+                </TableCell>
+                <TableCell className="text-right text-text-emphasis">{agreement.slots}</TableCell>
+                <TableCell className="text-text-secondary text-sm whitespace-nowrap">
+                  {new Date(agreement.startsAt).toLocaleDateString()} – {new Date(agreement.endsAt).toLocaleDateString()}
+                </TableCell>
+                <TableCell className="text-right">{agreement.jobsRun}</TableCell>
+                <TableCell className="text-right">{agreement.jobsActive}</TableCell>
+                <TableCell className="text-right">{agreement.runsTotal}</TableCell>
+                <TableCell>
+                  <Badge className={`border capitalize cursor-default ${STATUS_CLASSES[status] || STATUS_CLASSES.ended}`}>
+                    {status}
+                  </Badge>
+                </TableCell>
+                <TableCell className="text-center whitespace-nowrap">
+                  {agreement.ownerUserId === currentUserId && status !== 'cancelled' && (
+                    <Button
+                      variant="outline"
+                      size="sm"
+                      className="mr-1"
+                      onClick={() => handleInvoice(agreement.id)}
+                    >
+                      Invoice
+                    </Button>
+                  )}
+                  {(status === 'active' || status === 'scheduled') && (
+                    <Button
+                      variant="outline"
+                      size="sm"
+                      className="text-rose-danger border-rose-danger hover:bg-rose-danger/10"
+                      onClick={() => handleCancel(agreement.id)}
+                    >
+                      Cancel
+                    </Button>
+                  )}
+                </TableCell>
+              </TableRow>
+            );
+          })}
+        </TableBody>
+      </Table>
+    </div>
+  );
+}
@@ -11,6 +11,7 @@ type ActivitySlotInfo = {
   slotsReserved: number;
   slotsAvailable: number;
   slotsListed: number;
+  slotsRented: number;
 };
 
 type CharacterSlotInventory = {
@@ -84,6 +85,7 @@ export default function SlotInventoryPanel() {
             <TableHead className="text-right">In Use</TableHead>
             <TableHead className="text-right">Reserved</TableHead>
             <TableHead className="text-right">Listed</TableHead>
+            <TableHead className="text-right">Rented</TableHead>
             <TableHead className="text-right">Available</TableHead>
           </TableRow>
         </TableHeader>
@@ -110,6 +112,7 @@ export default function SlotInventoryPanel() {
                 <TableCell className="text-right">{slotInfo.slotsInUse}</TableCell>
                 <TableCell className="text-right">{slotInfo.slotsReserved}</TableCell>
                 <TableCell className="text-right">{slotInfo.slotsListed}</TableCell>
+                <TableCell className="text-right">{slotInfo.slotsRented}</TableCell>
                 <TableCell className="text-right">
                   <span className={`font-semibold ${slotInfo.slotsAvailable > 0 ? 'text-teal-success' : 'text-rose-danger'}`}>
                     {slotInfo.slotsAvailable}
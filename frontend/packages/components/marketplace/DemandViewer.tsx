@@ -7,6 +7,7 @@ import { Badge } from '@/components/ui/badge';
 import { Table, TableHeader, TableBody, TableRow, TableHead, TableCell } from '@/components/ui/table';
 import { toast } from '@/components/ui/sonner';
 import Loading from "@industry-tool/components/loading";
+import TrustBadge, { TradeTrustStats } from './TrustBadge';
 
 export type BuyOrder = {
   id: number;
@@ -21,6 +22,7 @@ export type BuyOrder = {
   isActive: boolean;
   createdAt: string;
   updatedAt: string;
+  trust?: TradeTrustStats;
 };
 
 export default function DemandViewer() {
@@ -169,6 +171,7 @@ export default function DemandViewer() {
                   <TableHeader>
                     <TableRow className="bg-background-void">
                       <TableHead>Item</TableHead>
+                      <TableHead>Buyer Trust</TableHead>
                       <TableHead>Location</TableHead>
                       <TableHead className="text-right">Quantity</TableHead>
                       <TableHead className="text-right">Min Price/Unit</TableHead>
@@ -181,6 +184,7 @@ export default function DemandViewer() {
                     {filteredDemand.map((order) => (
                       <TableRow key={order.id} className="bg-background-panel hover:bg-interactive-hover">
                         <TableCell className="text-text-emphasis">{order.typeName}</TableCell>
+                        <TableCell><TrustBadge trust={order.trust} /></TableCell>
                         <TableCell className="text-text-secondary">{order.locationName || '-'}</TableCell>
                         <TableCell className="text-right text-text-emphasis">{formatNumber(order.quantityDesired)}</TableCell>
                         <TableCell className="text-right text-text-emphasis">{formatISK(order.minPricePerUnit)}</TableCell>
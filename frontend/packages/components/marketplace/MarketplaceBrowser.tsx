@@ -6,8 +6,10 @@ import { Input } from '@/components/ui/input';
 import { Dialog, DialogContent, DialogHeader, DialogTitle, DialogFooter } from '@/components/ui/dialog';
 import { Table, TableHeader, TableBody, TableRow, TableHead, TableCell } from '@/components/ui/table';
 import { Badge } from '@/components/ui/badge';
+import { Select, SelectTrigger, SelectValue, SelectContent, SelectItem } from '@/components/ui/select';
 import { toast } from '@/components/ui/sonner';
 import { formatISK, formatNumber } from '@industry-tool/utils/formatting';
+import TrustBadge, { TradeTrustStats } from './TrustBadge';
 
 type ForSaleListing = {
   id: number;
@@ -23,9 +25,27 @@ type ForSaleListing = {
   divisionNumber?: number;
   quantityAvailable: number;
   pricePerUnit: number;
+  listPricePerUnit?: number;
   notes?: string;
+  trust?: TradeTrustStats;
+  blueprint?: {
+    itemId: number;
+    isCopy: boolean;
+    runs: number;
+    materialEfficiency: number;
+    timeEfficiency: number;
+  };
+};
+
+type BlueprintFilter = {
+  kind: 'all' | 'copy' | 'original';
+  minRuns: string;
+  minMe: string;
+  minTe: string;
 };
 
+const noBlueprintFilter: BlueprintFilter = { kind: 'all', minRuns: '', minMe: '', minTe: '' };
+
 export default function MarketplaceBrowser() {
   const { data: session } = useSession();
   const [listings, setListings] = useState<ForSaleListing[]>([]);
@@ -35,17 +55,25 @@ export default function MarketplaceBrowser() {
   const [selectedListing, setSelectedListing] = useState<ForSaleListing | null>(null);
   const [purchaseQuantity, setPurchaseQuantity] = useState('');
   const [submittingPurchase, setSubmittingPurchase] = useState(false);
+  const [blueprintFilter, setBlueprintFilter] = useState<BlueprintFilter>(noBlueprintFilter);
 
   useEffect(() => {
     if (session) {
       fetchListings();
     }
-  }, [session]);
+  }, [session, blueprintFilter]);
 
+  // Only the first load shows the spinner, so refetching on a filter change keeps the filter inputs
   const fetchListings = async () => {
-    setLoading(true);
     try {
-      const response = await fetch('/api/for-sale/browse');
+      // Blueprint filters are applied by the backend
+      const params = new URLSearchParams();
+      if (blueprintFilter.kind !== 'all') params.set('blueprint', blueprintFilter.kind);
+      if (blueprintFilter.minRuns) params.set('minRuns', blueprintFilter.minRuns);
+      if (blueprintFilter.minMe) params.set('minMe', blueprintFilter.minMe);
+      if (blueprintFilter.minTe) params.set('minTe', blueprintFilter.minTe);
+      const query = params.toString();
+      const response = await fetch(`/api/for-sale/browse${query ? `?${query}` : ''}`);
       if (response.ok) {
         const data = await response.json();
         setListings(data || []);
@@ -111,6 +139,10 @@ export default function MarketplaceBrowser() {
     listing.locationName.toLowerCase().includes(searchQuery.toLowerCase())
   );
 
+  const setMinimum = (field: 'minRuns' | 'minMe' | 'minTe', value: string) => {
+    setBlueprintFilter({ ...blueprintFilter, [field]: value.replace(/\D/g, '') });
+  };
+
   if (loading) {
     return (
       <div className="flex justify-center items-center min-h-[400px]">
@@ -121,12 +153,29 @@ export default function MarketplaceBrowser() {
 
   return (
     <div>
-      <div className="mb-4">
+      <div className="mb-4 flex flex-wrap gap-2">
         <Input
+          className="flex-1 min-w-[240px]"
           placeholder="Search by item name, seller, or location"
           value={searchQuery}
           onChange={(e) => setSearchQuery(e.target.value)}
         />
+        <Select
+          value={blueprintFilter.kind}
+          onValueChange={(kind) => setBlueprintFilter({ ...blueprintFilter, kind: kind as BlueprintFilter['kind'] })}
+        >
+          <SelectTrigger className="w-[160px]">
+            <SelectValue />
+          </SelectTrigger>
+          <SelectContent>
+            <SelectItem value="all">All listings</SelectItem>
+            <SelectItem value="copy">Blueprint copies</SelectItem>
+            <SelectItem value="original">Blueprint originals</SelectItem>
+          </SelectContent>
+        </Select>
+        <Input className="w-[100px]" placeholder="Min runs" value={blueprintFilter.minRuns} onChange={(e) => setMinimum('minRuns', e.target.value)} />
+        <Input className="w-[90px]" placeholder="Min ME" value={blueprintFilter.minMe} onChange={(e) => setMinimum('minMe', e.target.value)} />
+        <Input className="w-[90px]" placeholder="Min TE" value={blueprintFilter.minTe} onChange={(e) => setMinimum('minTe', e.target.value)} />
       </div>
 
       {filteredListings.length === 0 ? (
@@ -158,11 +207,20 @@ export default function MarketplaceBrowser() {
                 <TableRow key={listing.id} className="bg-background-panel hover:bg-interactive-hover">
                   <TableCell>
                     <span className="font-medium text-text-emphasis">{listing.typeName}</span>
+                    {listing.blueprint && (
+                      <div className="text-xs text-text-secondary">
+                        {listing.blueprint.isCopy ? `Copy · ${listing.blueprint.runs} runs` : 'Original'}
+                        {` · ME ${listing.blueprint.materialEfficiency} / TE ${listing.blueprint.timeEfficiency}`}
+                      </div>
+                    )}
                   </TableCell>
                   <TableCell>
-                    <Badge className="bg-interactive-selected border border-border-active text-blue-science hover:bg-interactive-active cursor-default">
-                      {listing.ownerName}
-                    </Badge>
+                    <div className="flex items-center gap-1.5">
+                      <Badge className="bg-interactive-selected border border-border-active text-blue-science hover:bg-interactive-active cursor-default">
+                        {listing.ownerName}
+                      </Badge>
+                      <TrustBadge trust={listing.trust} />
+                    </div>
                   </TableCell>
                   <TableCell>
                     <span className="text-sm text-text-secondary">{listing.locationName}</span>
@@ -172,6 +230,11 @@ export default function MarketplaceBrowser() {
                   </TableCell>
                   <TableCell className="text-right">
                     <span className="text-sm font-medium text-text-emphasis">{formatISK(listing.pricePerUnit)}</span>
+                    {listing.listPricePerUnit !== undefined && (
+                      <div className="text-xs text-text-secondary line-through" title="Listed price; you get your contact group's price">
+                        {formatISK(listing.listPricePerUnit)}
+                      </div>
+                    )}
                   </TableCell>
                   <TableCell className="text-right">
                     <span className="text-sm font-semibold text-teal-success">
@@ -210,6 +273,13 @@ export default function MarketplaceBrowser() {
               <p className="text-sm text-text-emphasis">
                 <strong>Item:</strong> {selectedListing.typeName}
               </p>
+              {selectedListing.blueprint && (
+                <p className="text-sm text-text-emphasis">
+                  <strong>Blueprint:</strong>{' '}
+                  {selectedListing.blueprint.isCopy ? `Copy, ${selectedListing.blueprint.runs} runs` : 'Original'},
+                  {` ME ${selectedListing.blueprint.materialEfficiency} / TE ${selectedListing.blueprint.timeEfficiency}`}
+                </p>
+              )}
               <p className="text-sm text-text-emphasis">
                 <strong>Seller:</strong> {selectedListing.ownerName}
               </p>
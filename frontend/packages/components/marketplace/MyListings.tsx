@@ -38,6 +38,13 @@ export type ForSaleItem = {
   isActive: boolean;
   createdAt: string;
   updatedAt: string;
+  blueprint?: {
+    itemId: number;
+    isCopy: boolean;
+    runs: number;
+    materialEfficiency: number;
+    timeEfficiency: number;
+  };
 };
 
 type ListingFormData = {
@@ -237,6 +244,12 @@ export default function MyListings() {
                   <TableCell className="font-semibold text-text-emphasis">
                     <div className="flex items-center gap-2">
                       {item.typeName}
+                      {item.blueprint && (
+                        <span className="text-xs font-normal text-text-secondary">
+                          {item.blueprint.isCopy ? `Copy · ${item.blueprint.runs} runs` : 'Original'}
+                          {` · ME ${item.blueprint.materialEfficiency} / TE ${item.blueprint.timeEfficiency}`}
+                        </span>
+                      )}
                       {item.autoSellContainerId && (
                         <TooltipProvider>
                           <Tooltip>
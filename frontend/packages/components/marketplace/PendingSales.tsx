@@ -28,9 +28,17 @@ type PendingSale = {
   buyOrderId?: number;
   isAutoFulfilled: boolean;
   purchasedAt: string;
+  blueprint?: {
+    itemId: number;
+    isCopy: boolean;
+    runs: number;
+    materialEfficiency: number;
+    timeEfficiency: number;
+  };
 };
 
 type AggregatedItem = {
+  key: string;
   typeId: number;
   typeName: string;
   totalQuantity: number;
@@ -40,6 +48,13 @@ type AggregatedItem = {
   notes: string[];
   purchaseIds: number[];
   latestPurchasedAt: string;
+  blueprint?: {
+    itemId: number;
+    isCopy: boolean;
+    runs: number;
+    materialEfficiency: number;
+    timeEfficiency: number;
+  };
 };
 
 type GroupedSale = {
@@ -114,11 +129,12 @@ export default function PendingSales() {
       group.totalValue += sale.totalPrice;
     });
 
-    // Aggregate items by typeId within each group
+    // Aggregate items by typeId within each group, keeping each listed blueprint item apart
     for (const group of groups.values()) {
-      const byType = new Map<number, AggregatedItem>();
+      const byType = new Map<string, AggregatedItem>();
       for (const sale of group.items) {
-        const existing = byType.get(sale.typeId);
+        const key = sale.blueprint ? `${sale.typeId}-${sale.blueprint.itemId}` : `${sale.typeId}`;
+        const existing = byType.get(key);
         if (existing) {
           existing.totalQuantity += sale.quantityPurchased;
           existing.totalPrice += sale.totalPrice;
@@ -130,7 +146,8 @@ export default function PendingSales() {
             existing.latestPurchasedAt = sale.purchasedAt;
           }
         } else {
-          byType.set(sale.typeId, {
+          byType.set(key, {
+            key,
             typeId: sale.typeId,
             typeName: sale.typeName,
             totalQuantity: sale.quantityPurchased,
@@ -140,6 +157,7 @@ export default function PendingSales() {
             notes: sale.transactionNotes ? [sale.transactionNotes] : [],
             purchaseIds: [sale.id],
             latestPurchasedAt: sale.purchasedAt,
+            blueprint: sale.blueprint,
           });
         }
       }
@@ -390,10 +408,16 @@ export default function PendingSales() {
                     </TableHeader>
                     <TableBody>
                       {group.aggregatedItems.map((agg) => (
-                        <TableRow key={agg.typeId} className="bg-background-panel hover:bg-interactive-hover">
+                        <TableRow key={agg.key} className="bg-background-panel hover:bg-interactive-hover">
                           <TableCell className="text-text-emphasis">
                             <div className="flex items-center gap-1.5">
                               {agg.typeName}
+                              {agg.blueprint && (
+                                <span className="text-xs text-text-secondary">
+                                  {agg.blueprint.isCopy ? `Copy · ${agg.blueprint.runs} runs` : 'Original'}
+                                  {` · ME ${agg.blueprint.materialEfficiency} / TE ${agg.blueprint.timeEfficiency}`}
+                                </span>
+                              )}
                               {agg.hasAutoFulfilled && (
                                 <Badge className="text-[0.65rem] font-semibold h-5 bg-teal-success/15 text-teal-success border border-teal-success/30 hover:bg-teal-success/20 cursor-default">
                                   Auto
@@ -1,7 +1,9 @@
 import { useState, useEffect } from 'react';
 import { useSession } from 'next-auth/react';
-import { CheckCircle, XCircle, ClipboardList, Loader2 } from 'lucide-react';
+import { CheckCircle, XCircle, ClipboardList, Loader2, Star } from 'lucide-react';
 import { Button } from '@/components/ui/button';
+import { Input } from '@/components/ui/input';
+import { Dialog, DialogContent, DialogHeader, DialogTitle, DialogFooter } from '@/components/ui/dialog';
 import { Badge } from '@/components/ui/badge';
 import { Table, TableHeader, TableBody, TableRow, TableHead, TableCell } from '@/components/ui/table';
 import { Tabs, TabsList, TabsTrigger, TabsContent } from '@/components/ui/tabs';
@@ -22,6 +24,7 @@ type PurchaseTransaction = {
   transactionNotes?: string;
   buyOrderId?: number;
   isAutoFulfilled: boolean;
+  disputeReason?: string;
   purchasedAt: string;
 };
 
@@ -31,6 +34,10 @@ export default function PurchaseHistory() {
   const [buyerHistory, setBuyerHistory] = useState<PurchaseTransaction[]>([]);
   const [sellerHistory, setSellerHistory] = useState<PurchaseTransaction[]>([]);
   const [loading, setLoading] = useState(true);
+  const [ratingTransaction, setRatingTransaction] = useState<PurchaseTransaction | null>(null);
+  const [rating, setRating] = useState(0);
+  const [ratingNote, setRatingNote] = useState('');
+  const [submittingRating, setSubmittingRating] = useState(false);
 
   useEffect(() => {
     if (session) {
@@ -82,7 +89,10 @@ export default function PurchaseHistory() {
       case 'completed':
         return 'bg-teal-success/15 text-teal-success border border-teal-success/30';
       case 'cancelled':
+      case 'expired':
         return 'bg-rose-danger/15 text-rose-danger border border-rose-danger/30';
+      case 'disputed':
+        return 'bg-rose-danger/15 text-rose-danger border border-rose-danger/60';
       default:
         return 'bg-overlay-subtle text-text-muted border border-overlay-strong';
     }
@@ -149,6 +159,38 @@ export default function PurchaseHistory() {
     }
   };
 
+  const handleOpenRatingDialog = (transaction: PurchaseTransaction) => {
+    setRatingTransaction(transaction);
+    setRating(0);
+    setRatingNote('');
+  };
+
+  const handleSubmitRating = async () => {
+    if (!ratingTransaction || rating === 0) return;
+
+    setSubmittingRating(true);
+    try {
+      const response = await fetch(`/api/purchases/${ratingTransaction.id}/rating`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ rating, note: ratingNote }),
+      });
+
+      if (response.ok) {
+        setRatingTransaction(null);
+        toast.success('Rating saved');
+      } else {
+        const error = await response.json();
+        toast.error(error.error || 'Failed to save rating');
+      }
+    } catch (error) {
+      console.error('Failed to save rating:', error);
+      toast.error('Failed to save rating');
+    } finally {
+      setSubmittingRating(false);
+    }
+  };
+
   const renderTransactionsTable = (transactions: PurchaseTransaction[], isBuyer: boolean) => {
     if (transactions.length === 0) {
       return (
@@ -204,6 +246,9 @@ export default function PurchaseHistory() {
                   <Badge className={cn("text-xs hover:opacity-90 cursor-default", getStatusBadgeClass(transaction.status))}>
                     {transaction.status.replace('_', ' ')}
                   </Badge>
+                  {transaction.disputeReason && (
+                    <div className="text-xs text-rose-danger mt-1">{transaction.disputeReason}</div>
+                  )}
                 </TableCell>
                 {transactions.some(t => t.transactionNotes) && (
                   <TableCell>
@@ -215,7 +260,7 @@ export default function PurchaseHistory() {
                 <TableCell className="text-center">
                   <div className="flex items-center justify-center gap-1">
                     {/* Buyer actions */}
-                    {isBuyer && transaction.status === 'contract_created' && (
+                    {isBuyer && (transaction.status === 'contract_created' || transaction.status === 'disputed') && (
                       <Button
                         variant="outline"
                         size="sm"
@@ -253,8 +298,20 @@ export default function PurchaseHistory() {
                       </Button>
                     )}
 
-                    {/* No actions for completed/cancelled */}
-                    {(transaction.status === 'completed' || transaction.status === 'cancelled') && (
+                    {/* Rate the other side once completed */}
+                    {transaction.status === 'completed' && (
+                      <Button
+                        variant="outline"
+                        size="sm"
+                        onClick={() => handleOpenRatingDialog(transaction)}
+                      >
+                        <Star className="h-4 w-4 mr-1" />
+                        Rate {isBuyer ? 'Seller' : 'Buyer'}
+                      </Button>
+                    )}
+
+                    {/* No actions for cancelled/expired */}
+                    {(transaction.status === 'cancelled' || transaction.status === 'expired') && (
                       <span className="text-xs text-text-muted">-</span>
                     )}
                   </div>
@@ -296,6 +353,53 @@ export default function PurchaseHistory() {
         <TabsContent value="purchases">{renderTransactionsTable(buyerHistory, true)}</TabsContent>
         <TabsContent value="sales">{renderTransactionsTable(sellerHistory, false)}</TabsContent>
       </Tabs>
+
+      {/* Rating Dialog */}
+      <Dialog open={ratingTransaction !== null} onOpenChange={(open) => !open && setRatingTransaction(null)}>
+        <DialogContent className="max-w-sm bg-background-panel border-overlay-medium">
+          <DialogHeader>
+            <DialogTitle className="text-text-emphasis">Rate Trade</DialogTitle>
+          </DialogHeader>
+          {ratingTransaction && (
+            <div className="flex flex-col gap-3 pt-1">
+              <p className="text-sm text-text-emphasis">
+                <strong>Item:</strong> {ratingTransaction.typeName} × {ratingTransaction.quantityPurchased.toLocaleString()}
+              </p>
+              <div className="flex gap-1">
+                {[1, 2, 3, 4, 5].map((value) => (
+                  <button
+                    key={value}
+                    type="button"
+                    aria-label={`${value} star${value > 1 ? 's' : ''}`}
+                    onClick={() => setRating(value)}
+                  >
+                    <Star
+                      className={cn(
+                        'h-6 w-6',
+                        value <= rating ? 'fill-amber-manufacturing text-amber-manufacturing' : 'text-text-muted'
+                      )}
+                    />
+                  </button>
+                ))}
+              </div>
+              <div>
+                <label className="text-sm text-text-secondary mb-1 block">Note (optional)</label>
+                <Input
+                  value={ratingNote}
+                  onChange={(e) => setRatingNote(e.target.value)}
+                  placeholder="How did the trade go?"
+                />
+              </div>
+            </div>
+          )}
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setRatingTransaction(null)}>Cancel</Button>
+            <Button onClick={handleSubmitRating} disabled={rating === 0 || submittingRating}>
+              {submittingRating ? 'Saving...' : 'Save Rating'}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
     </div>
   );
 }
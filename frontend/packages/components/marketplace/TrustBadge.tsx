@@ -0,0 +1,53 @@
+import { Badge } from '@/components/ui/badge';
+import { formatISK } from '@industry-tool/utils/formatting';
+
+export type TradeTrustStats = {
+  userId: number;
+  completedCount: number;
+  cancelledCount: number;
+  expiredCount: number;
+  disputedCount: number;
+  cancellationRate: number;
+  medianContractHours?: number;
+  totalVolume: number;
+  ratingCount: number;
+  averageRating?: number;
+  trustScore?: number;
+};
+
+function scoreClass(score: number) {
+  if (score >= 80) return 'bg-teal-success/10 border-teal-success/30 text-teal-success';
+  if (score >= 50) return 'bg-amber-manufacturing/10 border-amber-manufacturing/30 text-amber-manufacturing';
+  return 'bg-rose-danger/10 border-rose-danger/30 text-rose-danger';
+}
+
+function describe(trust: TradeTrustStats) {
+  const lines = [
+    `${trust.completedCount} completed, ${trust.cancelledCount} cancelled, ${trust.expiredCount} expired`,
+    `Cancellation rate: ${(trust.cancellationRate * 100).toFixed(0)}%`,
+    `Volume: ${formatISK(trust.totalVolume)}`,
+  ];
+  if (trust.medianContractHours !== undefined) {
+    lines.push(`Median time to contract: ${trust.medianContractHours.toFixed(1)}h`);
+  }
+  if (trust.averageRating !== undefined) {
+    lines.push(`Rating: ${trust.averageRating.toFixed(1)} / 5 (${trust.ratingCount})`);
+  }
+  return lines.join('\n');
+}
+
+export default function TrustBadge({ trust }: { trust?: TradeTrustStats }) {
+  if (!trust || trust.trustScore === undefined) {
+    return (
+      <Badge className="bg-overlay-subtle border border-overlay-strong text-text-secondary cursor-default" title="No finished trades yet">
+        New
+      </Badge>
+    );
+  }
+
+  return (
+    <Badge className={`border cursor-default ${scoreClass(trust.trustScore)}`} title={describe(trust)}>
+      Trust {trust.trustScore}
+    </Badge>
+  );
+}
@@ -55,7 +55,14 @@ type Channel = {
 const EVENT_TYPES = [
   { value: 'purchase_created', label: 'New Purchase' },
   { value: 'contract_created', label: 'Contract Created' },
+  { value: 'contract_disputed', label: 'Contract Disputed' },
+  { value: 'purchase_expired', label: 'Purchase Expired' },
+  { value: 'purchase_offer', label: 'Purchase Offers' },
   { value: 'pi_stall', label: 'PI Stall Alert' },
+  { value: 'invoice_reminder', label: 'Invoice Reminder' },
+  { value: 'invoice_paid', label: 'Invoice Paid' },
+  { value: 'invoice_overdue', label: 'Invoice Overdue' },
+  { value: 'courier_contract_alert', label: 'Courier Contract Failed / Expired' },
 ];
 
 const DISCORD_ERROR_MESSAGES: Record<string, string> = {
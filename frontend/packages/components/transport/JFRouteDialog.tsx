@@ -1,5 +1,5 @@
 import React, { useCallback, useEffect, useRef, useState } from "react";
-import { Plus, Trash2, Loader2 } from "lucide-react";
+import { Plus, Trash2, Loader2, Route } from "lucide-react";
 import {
   Dialog,
   DialogContent,
@@ -9,9 +9,17 @@ import {
 } from "@/components/ui/dialog";
 import { Button } from "@/components/ui/button";
 import { Input } from "@/components/ui/input";
-import { JFRoute } from "../../pages/transport";
-
-interface SolarSystemOption {
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from "@/components/ui/select";
+import { JFRoute, JFRoutePlan, TransportProfile } from "../../pages/transport";
+import { formatNumber } from "../../utils/formatting";
+
+export interface SolarSystemOption {
   id: number;
   name: string;
   security: number;
@@ -26,9 +34,10 @@ interface Props {
   open: boolean;
   onClose: (saved: boolean) => void;
   route: JFRoute | null;
+  profiles?: TransportProfile[];
 }
 
-const getSecurityColor = (sec: number) => {
+export const getSecurityColor = (sec: number) => {
   if (sec >= 0.5) return "var(--color-success-teal)";
   if (sec > 0.0) return "var(--color-manufacturing-amber)";
   return "var(--color-danger-rose)";
@@ -45,7 +54,7 @@ interface SystemSearchDropdownProps {
   setDisplayValue: (v: string) => void;
 }
 
-function SystemSearchDropdown({
+export function SystemSearchDropdown({
   label,
   value,
   onSelect,
@@ -129,9 +138,15 @@ function SystemSearchDropdown({
   );
 }
 
-export function JFRouteDialog({ open, onClose, route }: Props) {
+export function JFRouteDialog({ open, onClose, route, profiles = [] }: Props) {
   const isEdit = !!route;
+  const jfProfiles = profiles.filter((p) => p.transportMethod === "jump_freighter");
   const [saving, setSaving] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+  const [profileId, setProfileId] = useState("none");
+  const [optimize, setOptimize] = useState("jumps");
+  const [planning, setPlanning] = useState(false);
+  const [plan, setPlan] = useState<JFRoutePlan | null>(null);
   const [name, setName] = useState("");
   const [originSystem, setOriginSystem] = useState<SolarSystemOption | null>(null);
   const [destinationSystem, setDestinationSystem] = useState<SolarSystemOption | null>(null);
@@ -232,8 +247,63 @@ export function JFRouteDialog({ open, onClose, route }: Props) {
     setDestOptions([]);
     setWaypointOptions({});
     setWaypointLoading({});
+    setError(null);
+    setPlan(null);
   }, [open, route]);
 
+  useEffect(() => {
+    if (open) {
+      const defaultProfile = jfProfiles.find((p) => p.isDefault) || jfProfiles[0];
+      setProfileId(defaultProfile ? String(defaultProfile.id) : "none");
+    }
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [open]);
+
+  const handlePlan = async () => {
+    if (!originSystem || !destinationSystem) return;
+    setPlanning(true);
+    setError(null);
+    try {
+      const res = await fetch("/api/transport/jf-routes/plan", {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify({
+          originSystemId: originSystem.id,
+          destinationSystemId: destinationSystem.id,
+          transportProfileId: profileId === "none" ? undefined : Number(profileId),
+          optimize,
+        }),
+      });
+      const data = await res.json();
+      if (!res.ok) {
+        setError(data.error || "Failed to plan route");
+        setPlan(null);
+        return;
+      }
+      const result = data as JFRoutePlan;
+      setPlan(result);
+      const wps = result.waypoints.map((wp) => ({
+        sequence: wp.sequence,
+        system: {
+          id: wp.systemId,
+          name: wp.systemName || String(wp.systemId),
+          security: wp.security ?? 0,
+        },
+      }));
+      setWaypoints(wps);
+      const displays: Record<number, string> = {};
+      wps.forEach((wp, index) => {
+        displays[index] = wp.system.name;
+      });
+      setWaypointDisplays(displays);
+    } catch (err) {
+      console.error("Failed to plan JF route:", err);
+      setError("Failed to plan route");
+    } finally {
+      setPlanning(false);
+    }
+  };
+
   const handleAddWaypoint = () => {
     setWaypoints([...waypoints, { sequence: waypoints.length, system: null }]);
   };
@@ -267,6 +337,7 @@ export function JFRouteDialog({ open, onClose, route }: Props) {
           sequence: wp.sequence,
           systemId: wp.system?.id,
         })),
+        transportProfileId: profileId === "none" ? undefined : Number(profileId),
       };
 
       const url = isEdit ? `/api/transport/jf-routes/${route!.id}` : "/api/transport/jf-routes";
@@ -280,6 +351,9 @@ export function JFRouteDialog({ open, onClose, route }: Props) {
 
       if (res.ok) {
         onClose(true);
+      } else {
+        const data = await res.json().catch(() => ({}));
+        setError(data.error || "Failed to save route");
       }
     } catch (error) {
       console.error("Failed to save JF route:", error);
@@ -329,6 +403,56 @@ export function JFRouteDialog({ open, onClose, route }: Props) {
             setDisplayValue={setDestDisplay}
           />
 
+          <div className="flex flex-col gap-1">
+            <label className="text-xs text-text-secondary">Jump Profile</label>
+            <Select value={profileId} onValueChange={setProfileId}>
+              <SelectTrigger>
+                <SelectValue placeholder="Jump Profile" />
+              </SelectTrigger>
+              <SelectContent>
+                <SelectItem value="none">Default (JF, JDC V)</SelectItem>
+                {jfProfiles.map((p) => (
+                  <SelectItem key={p.id} value={String(p.id)}>
+                    {p.name}
+                  </SelectItem>
+                ))}
+              </SelectContent>
+            </Select>
+          </div>
+
+          <div className="flex gap-2 items-end">
+            <div className="flex flex-col gap-1 flex-1">
+              <label className="text-xs text-text-secondary">Optimize For</label>
+              <Select value={optimize} onValueChange={setOptimize}>
+                <SelectTrigger>
+                  <SelectValue placeholder="Optimize For" />
+                </SelectTrigger>
+                <SelectContent>
+                  <SelectItem value="jumps">Fewest Jumps</SelectItem>
+                  <SelectItem value="fuel">Least Fuel</SelectItem>
+                </SelectContent>
+              </Select>
+            </div>
+            <Button
+              variant="outline"
+              onClick={handlePlan}
+              disabled={planning || !originSystem || !destinationSystem}
+            >
+              {planning ? <Loader2 className="h-4 w-4 animate-spin" /> : <Route className="h-4 w-4" />}
+              Plan Route
+            </Button>
+          </div>
+
+          {plan && (
+            <div className="text-xs text-text-secondary">
+              {plan.jumps} jumps · {plan.totalDistanceLy.toFixed(2)} LY · {formatNumber(plan.totalFuel)} isotopes
+              {plan.fuelCost > 0 && <> · {formatNumber(plan.fuelCost)} ISK</>}
+              {" "}(range {plan.jumpRangeLy.toFixed(1)} LY)
+            </div>
+          )}
+
+          {error && <div className="text-xs text-rose-danger">{error}</div>}
+
           <div>
             <div className="flex items-center justify-between mb-2">
               <span className="text-sm text-text-secondary">Waypoints (cyno systems in order)</span>
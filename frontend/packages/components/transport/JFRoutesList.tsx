@@ -9,16 +9,17 @@ import {
   TableHeader,
   TableRow,
 } from "@/components/ui/table";
-import { JFRoute } from "../../pages/transport";
+import { JFRoute, TransportProfile } from "../../pages/transport";
 import { JFRouteDialog } from "./JFRouteDialog";
 
 interface Props {
   routes: JFRoute[];
   loading: boolean;
   onRefresh: () => void;
+  profiles?: TransportProfile[];
 }
 
-export function JFRoutesList({ routes, loading, onRefresh }: Props) {
+export function JFRoutesList({ routes, loading, onRefresh, profiles = [] }: Props) {
   const [dialogOpen, setDialogOpen] = useState(false);
   const [editRoute, setEditRoute] = useState<JFRoute | null>(null);
 
@@ -134,6 +135,7 @@ export function JFRoutesList({ routes, loading, onRefresh }: Props) {
         open={dialogOpen}
         onClose={handleDialogClose}
         route={editRoute}
+        profiles={profiles}
       />
     </>
   );
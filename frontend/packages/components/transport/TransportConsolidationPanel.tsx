@@ -0,0 +1,84 @@
+import React, { useEffect, useState } from "react";
+import { Combine } from "lucide-react";
+import { Button } from "@/components/ui/button";
+import { TransportJob, TransportConsolidation } from "../../pages/transport";
+import { formatISK, formatNumber } from "../../utils/formatting";
+
+interface Props {
+  jobs: TransportJob[];
+  onRefresh: () => void;
+}
+
+export function TransportConsolidationPanel({ jobs, onRefresh }: Props) {
+  const [proposals, setProposals] = useState<TransportConsolidation[]>([]);
+  const [applying, setApplying] = useState<number | null>(null);
+
+  useEffect(() => {
+    const fetchProposals = async () => {
+      try {
+        const res = await fetch("/api/transport/jobs/consolidation");
+        if (res.ok) {
+          const data = await res.json();
+          setProposals(data || []);
+        }
+      } catch (error) {
+        console.error("Failed to fetch consolidation proposals:", error);
+      }
+    };
+    fetchProposals();
+  }, [jobs]);
+
+  const handleConsolidate = async (proposal: TransportConsolidation) => {
+    try {
+      setApplying(proposal.hostJobId);
+      const res = await fetch("/api/transport/jobs/consolidate", {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify({ jobIds: proposal.jobIds }),
+      });
+      if (res.ok) onRefresh();
+    } catch (error) {
+      console.error("Failed to consolidate jobs:", error);
+    } finally {
+      setApplying(null);
+    }
+  };
+
+  if (proposals.length === 0) return null;
+
+  return (
+    <div className="mb-4 rounded border border-overlay-subtle bg-background-panel p-3">
+      <div className="flex items-center gap-2 mb-2">
+        <Combine className="h-4 w-4 text-primary" />
+        <p className="text-sm font-medium text-text-emphasis">Consolidation opportunities</p>
+      </div>
+      <div className="flex flex-col gap-2">
+        {proposals.map((proposal) => (
+          <div key={proposal.hostJobId} className="flex items-center justify-between gap-4 text-sm">
+            <div>
+              <span className="text-text-emphasis">
+                {proposal.originSystemName || "?"} → {proposal.destinationSystemName || "?"}
+              </span>
+              <span className="text-text-secondary ml-2">
+                {proposal.jobIds.length} jobs · {formatNumber(proposal.totalVolumeM3)} m3 ·{" "}
+                {proposal.separateTrips} → {proposal.consolidatedTrips} trips
+              </span>
+              {proposal.iskSaved > 0 && (
+                <span className="text-teal-success ml-2">saves {formatISK(proposal.iskSaved)}</span>
+              )}
+            </div>
+            <Button
+              size="sm"
+              variant="outline"
+              className="text-[0.65rem] py-0 h-6"
+              disabled={applying !== null}
+              onClick={() => handleConsolidate(proposal)}
+            >
+              Consolidate
+            </Button>
+          </div>
+        ))}
+      </div>
+    </div>
+  );
+}
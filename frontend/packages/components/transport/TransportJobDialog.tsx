@@ -39,8 +39,12 @@ interface ItemTypeOption {
   TypeID: number;
   TypeName: string;
   Volume: number;
+  PackagedVolume?: number | null;
 }
 
+// Hauled items are packaged, so ships and containers use their packaged volume.
+const haulVolume = (itemType: ItemTypeOption) => itemType.PackagedVolume ?? itemType.Volume;
+
 interface JobItemEntry {
   itemType: ItemTypeOption;
   quantity: number;
@@ -220,7 +224,7 @@ function ItemTypeSearchDropdown({
                 />
                 <div>
                   <p className="text-sm text-text-emphasis">{opt.TypeName}</p>
-                  <p className="text-xs text-text-secondary">{opt.Volume.toLocaleString()} m³</p>
+                  <p className="text-xs text-text-secondary">{haulVolume(opt).toLocaleString()} m³</p>
                 </div>
               </div>
             </button>
@@ -377,7 +381,7 @@ export function TransportJobDialog({ open, onClose, profiles, jfRoutes }: Props)
   };
 
   const totalVolume = items.reduce(
-    (sum, i) => sum + i.itemType.Volume * i.quantity,
+    (sum, i) => sum + haulVolume(i.itemType) * i.quantity,
     0,
   );
 
@@ -395,7 +399,7 @@ export function TransportJobDialog({ open, onClose, profiles, jfRoutes }: Props)
         items: items.map((i) => ({
           typeId: i.itemType.TypeID,
           quantity: i.quantity,
-          volumeM3: i.itemType.Volume * i.quantity,
+          volumeM3: haulVolume(i.itemType) * i.quantity,
           estimatedValue: 0,
         })),
       };
@@ -547,7 +551,7 @@ export function TransportJobDialog({ open, onClose, profiles, jfRoutes }: Props)
                       {formatNumber(item.quantity)}
                     </TableCell>
                     <TableCell className="text-right">
-                      {formatNumber(item.itemType.Volume * item.quantity)}
+                      {formatNumber(haulVolume(item.itemType) * item.quantity)}
                     </TableCell>
                     <TableCell className="text-right">
                       <Button
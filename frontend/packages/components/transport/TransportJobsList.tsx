@@ -27,6 +27,7 @@ const getStatusColor = (status: string) => {
     in_transit: "var(--color-manufacturing-amber)",
     delivered: "var(--color-success-teal)",
     cancelled: "var(--color-danger-rose)",
+    consolidated: "var(--color-text-secondary)",
   };
   return colors[status] || "var(--color-text-secondary)";
 };
@@ -37,6 +38,7 @@ const getStatusBgColor = (status: string) => {
     in_transit: "var(--color-warning-tint)",
     delivered: "var(--color-success-tint)",
     cancelled: "var(--color-error-tint)",
+    consolidated: "var(--color-neutral-tint)",
   };
   return colors[status] || "var(--color-neutral-tint)";
 };
@@ -47,6 +49,7 @@ const getStatusLabel = (status: string) => {
     in_transit: "In Transit",
     delivered: "Delivered",
     cancelled: "Cancelled",
+    consolidated: "Consolidated",
   };
   return labels[status] || status;
 };
@@ -70,6 +73,24 @@ const getFulfillmentLabel = (type: string) => {
   return labels[type] || type;
 };
 
+const getContractStatusLabel = (status: string) => {
+  const labels: Record<string, string> = {
+    outstanding: "Contract Outstanding",
+    in_progress: "Contract Accepted",
+    finished: "Contract Delivered",
+    finished_issuer: "Contract Delivered",
+    finished_contractor: "Contract Delivered",
+    failed: "Contract Failed",
+    expired: "Contract Expired",
+    rejected: "Contract Rejected",
+    deleted: "Contract Deleted",
+  };
+  return labels[status] || `Contract ${status}`;
+};
+
+const isContractProblem = (status: string) =>
+  ["failed", "expired", "rejected", "deleted"].includes(status);
+
 export function TransportJobsList({ jobs, loading, profiles, jfRoutes, onRefresh }: Props) {
   const [dialogOpen, setDialogOpen] = useState(false);
   const [expandedJobId, setExpandedJobId] = useState<number | null>(null);
@@ -176,6 +197,9 @@ export function TransportJobsList({ jobs, loading, profiles, jfRoutes, onRefresh
                       >
                         {getStatusLabel(job.status)}
                       </span>
+                      {job.consolidatedIntoId && (
+                        <div className="text-xs text-text-secondary">Into job #{job.consolidatedIntoId}</div>
+                      )}
                     </TableCell>
                     <TableCell>
                       <p className="text-sm font-medium text-text-emphasis">
@@ -186,7 +210,24 @@ export function TransportJobsList({ jobs, loading, profiles, jfRoutes, onRefresh
                       </span>
                     </TableCell>
                     <TableCell className="text-text-secondary">{getMethodLabel(job.transportMethod)}</TableCell>
-                    <TableCell className="text-text-secondary">{getFulfillmentLabel(job.fulfillmentType)}</TableCell>
+                    <TableCell className="text-text-secondary">
+                      {getFulfillmentLabel(job.fulfillmentType)}
+                      {job.fulfillmentType === "courier_contract" && (
+                        <div className="text-xs">
+                          {job.contractStatus ? (
+                            <span className={isContractProblem(job.contractStatus) ? "text-rose-danger" : "text-text-secondary"}>
+                              {getContractStatusLabel(job.contractStatus)}
+                            </span>
+                          ) : (
+                            job.contractKey && (
+                              <span title="Put this reference in the courier contract title to link it">
+                                Title: <code>{job.contractKey}</code>
+                              </span>
+                            )
+                          )}
+                        </div>
+                      )}
+                    </TableCell>
                     <TableCell className="text-right">{formatNumber(job.totalVolumeM3)}</TableCell>
                     <TableCell className="text-right">{formatISK(job.totalCollateral)}</TableCell>
                     <TableCell className="text-right text-rose-danger">
@@ -1,4 +1,5 @@
-import React, { useEffect, useState } from "react";
+import React, { useCallback, useEffect, useRef, useState } from "react";
+import { X } from "lucide-react";
 import {
   Dialog,
   DialogContent,
@@ -17,6 +18,20 @@ import {
   SelectValue,
 } from "@/components/ui/select";
 import { TransportProfile } from "../../pages/transport";
+import { SolarSystemOption, SystemSearchDropdown } from "./JFRouteDialog";
+
+const jumpShipClasses: { value: string; label: string }[] = [
+  { value: "jump_freighter", label: "Jump Freighter (5 LY base)" },
+  { value: "rorqual", label: "Rorqual (5 LY base)" },
+  { value: "black_ops", label: "Black Ops (4 LY base)" },
+  { value: "capital", label: "Carrier / Dread / FAX (3.5 LY base)" },
+  { value: "supercapital", label: "Supercarrier / Titan (3 LY base)" },
+];
+
+interface CynoSystem {
+  id: number;
+  name: string;
+}
 
 interface Props {
   open: boolean;
@@ -37,6 +52,13 @@ export function TransportProfileDialog({ open, onClose, profile }: Props) {
   const [fuelConservationLevel, setFuelConservationLevel] = useState<number>(0);
   const [routePreference, setRoutePreference] = useState("shortest");
   const [isDefault, setIsDefault] = useState(false);
+  const [jumpShipClass, setJumpShipClass] = useState("jump_freighter");
+  const [jumpDriveCalibrationLevel, setJumpDriveCalibrationLevel] = useState<number>(5);
+  const [cynoSystems, setCynoSystems] = useState<CynoSystem[]>([]);
+  const [cynoOptions, setCynoOptions] = useState<SolarSystemOption[]>([]);
+  const [cynoLoading, setCynoLoading] = useState(false);
+  const [cynoDisplay, setCynoDisplay] = useState("");
+  const searchTimeoutRef = useRef<ReturnType<typeof setTimeout> | null>(null);
 
   useEffect(() => {
     if (open && profile) {
@@ -50,6 +72,14 @@ export function TransportProfileDialog({ open, onClose, profile }: Props) {
       setFuelConservationLevel(profile.fuelConservationLevel);
       setRoutePreference(profile.routePreference || "shortest");
       setIsDefault(profile.isDefault);
+      setJumpShipClass(profile.jumpShipClass || "jump_freighter");
+      setJumpDriveCalibrationLevel(profile.jumpDriveCalibrationLevel ?? 5);
+      setCynoSystems(
+        (profile.preferredCynoSystemIds || []).map((id, i) => ({
+          id,
+          name: profile.preferredCynoSystemNames?.[i] || String(id),
+        })),
+      );
     } else if (open) {
       setName("");
       setTransportMethod("freighter");
@@ -61,9 +91,46 @@ export function TransportProfileDialog({ open, onClose, profile }: Props) {
       setFuelConservationLevel(0);
       setRoutePreference("shortest");
       setIsDefault(false);
+      setJumpShipClass("jump_freighter");
+      setJumpDriveCalibrationLevel(5);
+      setCynoSystems([]);
     }
+    setCynoOptions([]);
+    setCynoDisplay("");
   }, [open, profile]);
 
+  const searchCynoSystems = useCallback((query: string) => {
+    if (searchTimeoutRef.current) clearTimeout(searchTimeoutRef.current);
+    searchTimeoutRef.current = setTimeout(async () => {
+      if (!query || query.length < 2) {
+        setCynoOptions([]);
+        return;
+      }
+      setCynoLoading(true);
+      try {
+        const res = await fetch(`/api/transport/systems/search?q=${encodeURIComponent(query)}`);
+        if (res.ok) {
+          const data: SolarSystemOption[] = await res.json();
+          // Cynos cannot be lit in highsec
+          setCynoOptions((data || []).filter((s) => s.security < 0.45));
+        }
+      } catch (err) {
+        console.error("Failed to search systems:", err);
+      } finally {
+        setCynoLoading(false);
+      }
+    }, 300);
+  }, []);
+
+  const handleAddCyno = (system: SolarSystemOption | null) => {
+    if (!system) return;
+    if (!cynoSystems.some((s) => s.id === system.id)) {
+      setCynoSystems([...cynoSystems, { id: system.id, name: system.name }]);
+    }
+    setCynoDisplay("");
+    setCynoOptions([]);
+  };
+
   const handleSave = async () => {
     setSaving(true);
     try {
@@ -74,10 +141,14 @@ export function TransportProfileDialog({ open, onClose, profile }: Props) {
         ratePerM3PerJump,
         collateralRate,
         collateralPriceBasis,
-        fuelPerLy: transportMethod === "jump_freighter" ? fuelPerLy : undefined,
+        // 0 leaves fuel to the ship class default
+        fuelPerLy: transportMethod === "jump_freighter" && fuelPerLy > 0 ? fuelPerLy : undefined,
         fuelConservationLevel: transportMethod === "jump_freighter" ? fuelConservationLevel : 0,
         routePreference,
         isDefault,
+        jumpShipClass,
+        jumpDriveCalibrationLevel,
+        preferredCynoSystemIds: cynoSystems.map((s) => s.id),
       };
 
       const url = isEdit
@@ -110,7 +181,7 @@ export function TransportProfileDialog({ open, onClose, profile }: Props) {
           <DialogTitle>{isEdit ? "Edit Transport Profile" : "Add Transport Profile"}</DialogTitle>
         </DialogHeader>
 
-        <div className="flex flex-col gap-3 pt-1">
+        <div className="flex flex-col gap-3 pt-1 max-h-[70vh] overflow-y-auto pr-1">
           <div className="flex flex-col gap-1">
             <label className="text-xs text-text-secondary">Profile Name</label>
             <Input
@@ -188,6 +259,41 @@ export function TransportProfileDialog({ open, onClose, profile }: Props) {
 
           {isJF && (
             <>
+              <div className="flex flex-col gap-1">
+                <label className="text-xs text-text-secondary">Jump Ship Class</label>
+                <Select value={jumpShipClass} onValueChange={(v) => setJumpShipClass(v)}>
+                  <SelectTrigger>
+                    <SelectValue placeholder="Jump Ship Class" />
+                  </SelectTrigger>
+                  <SelectContent>
+                    {jumpShipClasses.map((c) => (
+                      <SelectItem key={c.value} value={c.value}>
+                        {c.label}
+                      </SelectItem>
+                    ))}
+                  </SelectContent>
+                </Select>
+              </div>
+
+              <div className="flex flex-col gap-1">
+                <label className="text-xs text-text-secondary">Jump Drive Calibration Level</label>
+                <Select
+                  value={String(jumpDriveCalibrationLevel)}
+                  onValueChange={(v) => setJumpDriveCalibrationLevel(Number(v))}
+                >
+                  <SelectTrigger>
+                    <SelectValue placeholder="Jump Drive Calibration Level" />
+                  </SelectTrigger>
+                  <SelectContent>
+                    {[0, 1, 2, 3, 4, 5].map((level) => (
+                      <SelectItem key={level} value={String(level)}>
+                        Level {level} (+{level * 20}% range)
+                      </SelectItem>
+                    ))}
+                  </SelectContent>
+                </Select>
+              </div>
+
               <div className="flex flex-col gap-1">
                 <label className="text-xs text-text-secondary">Fuel per Light Year</label>
                 <Input
@@ -195,6 +301,7 @@ export function TransportProfileDialog({ open, onClose, profile }: Props) {
                   value={fuelPerLy}
                   onChange={(e) => setFuelPerLy(Number(e.target.value))}
                 />
+                <span className="text-xs text-text-muted">0 = ship class default</span>
               </div>
 
               <div className="flex flex-col gap-1">
@@ -215,6 +322,41 @@ export function TransportProfileDialog({ open, onClose, profile }: Props) {
                   </SelectContent>
                 </Select>
               </div>
+
+              <div className="flex flex-col gap-1">
+                <SystemSearchDropdown
+                  label="Preferred Cyno Systems"
+                  value={null}
+                  onSelect={handleAddCyno}
+                  options={cynoOptions}
+                  loading={cynoLoading}
+                  onSearch={searchCynoSystems}
+                  displayValue={cynoDisplay}
+                  setDisplayValue={setCynoDisplay}
+                />
+                {cynoSystems.length > 0 && (
+                  <div className="flex flex-wrap gap-1">
+                    {cynoSystems.map((s) => (
+                      <span
+                        key={s.id}
+                        className="inline-flex items-center gap-1 rounded-sm bg-background-elevated px-2 py-0.5 text-xs text-text-emphasis"
+                      >
+                        {s.name}
+                        <button
+                          type="button"
+                          aria-label={`Remove ${s.name}`}
+                          onClick={() => setCynoSystems(cynoSystems.filter((c) => c.id !== s.id))}
+                        >
+                          <X className="h-3 w-3" />
+                        </button>
+                      </span>
+                    ))}
+                  </div>
+                )}
+                <span className="text-xs text-text-muted">
+                  Route planning only uses these when set; otherwise any lowsec or nullsec system
+                </span>
+              </div>
             </>
           )}
 
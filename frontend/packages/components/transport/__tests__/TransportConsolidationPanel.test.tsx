@@ -0,0 +1,66 @@
+import React from 'react';
+import { render, screen, fireEvent, waitFor } from '@testing-library/react';
+import { TransportConsolidationPanel } from '../TransportConsolidationPanel';
+import { TransportConsolidation } from '@industry-tool/pages/transport';
+
+const mockProposals: TransportConsolidation[] = [
+  {
+    hostJobId: 1,
+    jobIds: [1, 3, 2],
+    transportMethod: 'jump_freighter',
+    transportProfileId: 20,
+    originSystemName: 'Jita',
+    destinationSystemName: 'Amarr',
+    totalVolumeM3: 250000,
+    totalCollateral: 2500000000,
+    separateTrips: 3,
+    consolidatedTrips: 1,
+    separateCost: 25000000,
+    consolidatedCost: 10000000,
+    iskSaved: 15000000,
+  },
+];
+
+describe('TransportConsolidationPanel', () => {
+  beforeEach(() => {
+    (global.fetch as jest.Mock).mockClear();
+  });
+
+  it('should render nothing without proposals', async () => {
+    (global.fetch as jest.Mock).mockResolvedValueOnce({ ok: true, json: async () => [] });
+
+    const { container } = render(<TransportConsolidationPanel jobs={[]} onRefresh={jest.fn()} />);
+
+    await waitFor(() => expect(global.fetch).toHaveBeenCalledWith('/api/transport/jobs/consolidation'));
+    expect(container).toBeEmptyDOMElement();
+  });
+
+  it('should show trips and ISK saved for each proposal', async () => {
+    (global.fetch as jest.Mock).mockResolvedValueOnce({ ok: true, json: async () => mockProposals });
+
+    render(<TransportConsolidationPanel jobs={[]} onRefresh={jest.fn()} />);
+
+    expect(await screen.findByText('Consolidation opportunities')).toBeInTheDocument();
+    expect(screen.getByText(/Jita → Amarr/)).toBeInTheDocument();
+    expect(screen.getByText(/3 → 1 trips/)).toBeInTheDocument();
+    expect(screen.getByText(/saves/)).toBeInTheDocument();
+  });
+
+  it('should post the proposal job ids and refresh on consolidate', async () => {
+    const onRefresh = jest.fn();
+    (global.fetch as jest.Mock)
+      .mockResolvedValueOnce({ ok: true, json: async () => mockProposals })
+      .mockResolvedValueOnce({ ok: true, json: async () => mockProposals[0] });
+
+    render(<TransportConsolidationPanel jobs={[]} onRefresh={onRefresh} />);
+
+    fireEvent.click(await screen.findByText('Consolidate'));
+
+    await waitFor(() => expect(onRefresh).toHaveBeenCalled());
+    expect(global.fetch).toHaveBeenCalledWith('/api/transport/jobs/consolidate', {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ jobIds: [1, 3, 2] }),
+    });
+  });
+});
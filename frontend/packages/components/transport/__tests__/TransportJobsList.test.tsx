@@ -144,4 +144,26 @@ describe('TransportJobsList', () => {
     expect(screen.getByTestId('job-dialog')).toBeInTheDocument();
     expect(screen.getByText('Create Job Dialog')).toBeInTheDocument();
   });
+
+  it('should show the contract key and contract status for courier jobs', () => {
+    const courierJobs: TransportJob[] = [
+      { ...mockJobs[0], id: 3, fulfillmentType: 'courier_contract', contractKey: 'TJ-3' },
+      {
+        ...mockJobs[1],
+        id: 4,
+        fulfillmentType: 'courier_contract',
+        contractKey: 'TJ-4',
+        eveContractId: 9001,
+        contractStatus: 'failed',
+      },
+    ];
+
+    render(
+      <TransportJobsList jobs={courierJobs} loading={false} profiles={mockProfiles} jfRoutes={mockJFRoutes} onRefresh={jest.fn()} />,
+    );
+
+    expect(screen.getByText('TJ-3')).toBeInTheDocument();
+    expect(screen.queryByText('TJ-4')).not.toBeInTheDocument();
+    expect(screen.getByText('Contract Failed')).toBeInTheDocument();
+  });
 });
@@ -28,6 +28,9 @@ const mockProfiles: TransportProfile[] = [
     fuelConservationLevel: 0,
     routePreference: 'shortest',
     isDefault: true,
+    jumpShipClass: 'jump_freighter',
+    jumpDriveCalibrationLevel: 5,
+    preferredCynoSystemIds: [],
     createdAt: '2026-02-24T12:00:00Z',
   },
   {
@@ -45,6 +48,9 @@ const mockProfiles: TransportProfile[] = [
     fuelConservationLevel: 5,
     routePreference: 'shortest',
     isDefault: false,
+    jumpShipClass: 'jump_freighter',
+    jumpDriveCalibrationLevel: 5,
+    preferredCynoSystemIds: [],
     createdAt: '2026-02-24T12:00:00Z',
   },
 ];
@@ -9,6 +9,8 @@ import SlotInventoryPanel from "@industry-tool/components/job-slots/SlotInventor
 import MyListings from "@industry-tool/components/job-slots/MyListings";
 import ListingsBrowser from "@industry-tool/components/job-slots/ListingsBrowser";
 import InterestRequests from "@industry-tool/components/job-slots/InterestRequests";
+import RentalAgreements from "@industry-tool/components/job-slots/RentalAgreements";
+import Invoices from "@industry-tool/components/job-slots/Invoices";
 
 export default function JobSlotExchangePage() {
   const { status } = useSession();
@@ -42,11 +44,15 @@ export default function JobSlotExchangePage() {
             <TabsTrigger value="my-listings">My Listings</TabsTrigger>
             <TabsTrigger value="browse">Browse Listings</TabsTrigger>
             <TabsTrigger value="interest">Interest Requests</TabsTrigger>
+            <TabsTrigger value="agreements">Rental Agreements</TabsTrigger>
+            <TabsTrigger value="invoices">Invoices</TabsTrigger>
           </TabsList>
           <TabsContent value="inventory"><SlotInventoryPanel /></TabsContent>
           <TabsContent value="my-listings"><MyListings /></TabsContent>
           <TabsContent value="browse"><ListingsBrowser /></TabsContent>
           <TabsContent value="interest"><InterestRequests /></TabsContent>
+          <TabsContent value="agreements"><RentalAgreements /></TabsContent>
+          <TabsContent value="invoices"><Invoices /></TabsContent>
         </Tabs>
       </div>
     </>
@@ -8,6 +8,7 @@ import Navbar from "@industry-tool/components/Navbar";
 import { TransportProfilesList } from "../components/transport/TransportProfilesList";
 import { JFRoutesList } from "../components/transport/JFRoutesList";
 import { TransportJobsList } from "../components/transport/TransportJobsList";
+import { TransportConsolidationPanel } from "../components/transport/TransportConsolidationPanel";
 
 export interface TransportProfile {
   id: number;
@@ -26,6 +27,10 @@ export interface TransportProfile {
   fuelConservationLevel: number;
   routePreference: string;
   isDefault: boolean;
+  jumpShipClass: string;
+  jumpDriveCalibrationLevel: number;
+  preferredCynoSystemIds: number[];
+  preferredCynoSystemNames?: string[];
   createdAt: string;
 }
 
@@ -35,9 +40,22 @@ export interface JFRouteWaypoint {
   sequence: number;
   systemId: number;
   systemName?: string;
+  security?: number;
   distanceLy: number;
 }
 
+export interface JFRoutePlan {
+  originSystemId: number;
+  destinationSystemId: number;
+  optimize: string;
+  jumpRangeLy: number;
+  jumps: number;
+  totalDistanceLy: number;
+  totalFuel: number;
+  fuelCost: number;
+  waypoints: JFRouteWaypoint[];
+}
+
 export interface JFRoute {
   id: number;
   userId: number;
@@ -87,10 +105,31 @@ export interface TransportJob {
   status: string;
   notes?: string;
   queueEntryId?: number;
+  eveContractId?: number;
+  contractStatus?: string;
+  contractUpdatedAt?: string;
+  contractKey?: string;
+  consolidatedIntoId?: number;
   items: TransportJobItem[];
   createdAt: string;
 }
 
+export interface TransportConsolidation {
+  hostJobId: number;
+  jobIds: number[];
+  transportMethod: string;
+  transportProfileId?: number;
+  originSystemName?: string;
+  destinationSystemName?: string;
+  totalVolumeM3: number;
+  totalCollateral: number;
+  separateTrips: number;
+  consolidatedTrips: number;
+  separateCost: number;
+  consolidatedCost: number;
+  iskSaved: number;
+}
+
 export default function TransportPage() {
   const { data: session, status } = useSession();
   const [profiles, setProfiles] = useState<TransportProfile[]>([]);
@@ -186,6 +225,7 @@ export default function TransportPage() {
           </TabsList>
 
           <TabsContent value="jobs" className="mt-4">
+            <TransportConsolidationPanel jobs={jobs} onRefresh={fetchJobs} />
             <TransportJobsList
               jobs={jobs}
               loading={loadingJobs}
@@ -207,6 +247,7 @@ export default function TransportPage() {
             <JFRoutesList
               routes={jfRoutes}
               loading={loadingRoutes}
+              profiles={profiles}
               onRefresh={fetchJFRoutes}
             />
           </TabsContent>
@@ -22,8 +22,9 @@ export default async function handler(
   }
 
   if (req.method === "GET") {
-    // Browse for-sale items from contacts
-    const response = await fetch(backend + "v1/for-sale/browse", {
+    // Browse for-sale items from contacts, passing blueprint filters through
+    const queryString = new URLSearchParams(req.query as Record<string, string>).toString();
+    const response = await fetch(backend + "v1/for-sale/browse" + (queryString ? `?${queryString}` : ""), {
       method: "GET",
       headers: getHeaders(session.providerAccountId),
     });
@@ -29,6 +29,10 @@ export default async function handler(
         source_structure_id,
         dest_structure_id,
         dest_system_id,
+        transport_profile_id,
+        highsec_only,
+        max_jumps,
+        sort,
       } = req.query;
       const params = new URLSearchParams();
       if (source_region_id) params.set("source_region_id", String(source_region_id));
@@ -37,6 +41,10 @@ export default async function handler(
       if (source_structure_id) params.set("source_structure_id", String(source_structure_id));
       if (dest_structure_id) params.set("dest_structure_id", String(dest_structure_id));
       if (dest_system_id) params.set("dest_system_id", String(dest_system_id));
+      if (transport_profile_id) params.set("transport_profile_id", String(transport_profile_id));
+      if (highsec_only) params.set("highsec_only", String(highsec_only));
+      if (max_jumps) params.set("max_jumps", String(max_jumps));
+      if (sort) params.set("sort", String(sort));
 
       const response = await fetch(`${backend}v1/hauling/scanner?${params.toString()}`, {
         method: "GET",
@@ -65,6 +65,24 @@ export default async function handler(
       }
 
       return res.status(204).end();
+    } else if (req.method === "PUT") {
+      const { id } = req.query;
+      if (!id) {
+        return res.status(400).json({ error: "Missing structure id" });
+      }
+
+      const response = await fetch(`${backend}v1/hauling/structures/${id}/broker-fee`, {
+        method: "PUT",
+        headers: getHeaders(session.providerAccountId),
+        body: JSON.stringify(req.body),
+      });
+
+      if (!response.ok) {
+        const errorText = await response.text();
+        return res.status(response.status).json({ error: errorText });
+      }
+
+      return res.status(200).json({});
     } else {
       return res.status(405).json({ error: "Method not allowed" });
     }
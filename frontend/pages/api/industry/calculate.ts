@@ -1,4 +1,6 @@
 import type { NextApiRequest, NextApiResponse } from "next";
+import { getServerSession } from "next-auth/next";
+import { authOptions } from "../auth/[...nextauth]";
 
 const backend = process.env.BACKEND_URL as string;
 const backendKey = process.env.BACKEND_KEY as string;
@@ -11,11 +13,17 @@ export default async function handler(
     return res.status(405).json({ error: "Method not allowed" });
   }
 
+  const session = await getServerSession(req, res, authOptions);
+  if (!session) {
+    return res.status(401).json({ error: "Unauthorized" });
+  }
+
   try {
     const response = await fetch(`${backend}v1/industry/calculate`, {
       method: "POST",
       headers: {
         "Content-Type": "application/json",
+        "USER-ID": session.providerAccountId,
         "BACKEND-KEY": backendKey,
       },
       body: JSON.stringify(req.body),
@@ -0,0 +1,51 @@
+import type { NextApiRequest, NextApiResponse } from "next";
+import { getServerSession } from "next-auth/next";
+import { authOptions } from "./auth/[...nextauth]";
+
+const backend = process.env.BACKEND_URL as string;
+const backendKey = process.env.BACKEND_KEY as string;
+
+export default async function handler(
+  req: NextApiRequest,
+  res: NextApiResponse,
+) {
+  if (req.method !== "GET") {
+    return res.status(405).json({ error: "Method not allowed" });
+  }
+
+  const session = await getServerSession(req, res, authOptions);
+  if (!session) {
+    return res.status(401).json({ error: "Unauthorized" });
+  }
+
+  try {
+    const params = new URLSearchParams();
+    for (const key of ["location_id", "faction_standing", "corporation_standing"]) {
+      const value = req.query[key];
+      if (typeof value === "string" && value !== "") {
+        params.set(key, value);
+      }
+    }
+    const qs = params.toString();
+
+    const response = await fetch(`${backend}v1/trade-fees${qs ? `?${qs}` : ""}`, {
+      method: "GET",
+      headers: {
+        "Content-Type": "application/json",
+        "USER-ID": session.providerAccountId,
+        "BACKEND-KEY": backendKey,
+      },
+    });
+
+    if (!response.ok) {
+      const errorText = await response.text();
+      return res.status(response.status).json({ error: errorText });
+    }
+
+    const data = await response.json();
+    return res.status(200).json(data);
+  } catch (error) {
+    console.error("Trade fees API error:", error);
+    return res.status(500).json({ error: "Failed to fetch trade fees" });
+  }
+}
@@ -22,11 +22,14 @@ export default async function handler(
 
   try {
     if (req.method === "GET") {
-      const { origin, destination, flag } = req.query;
+      const { origin, destination, flag, avoidSystems, avoidRegions, minSecurity } = req.query;
       const params = new URLSearchParams();
       if (origin) params.set("origin", String(origin));
       if (destination) params.set("destination", String(destination));
       if (flag) params.set("flag", String(flag));
+      if (avoidSystems) params.set("avoidSystems", String(avoidSystems));
+      if (avoidRegions) params.set("avoidRegions", String(avoidRegions));
+      if (minSecurity) params.set("minSecurity", String(minSecurity));
 
       const response = await fetch(`${backend}v1/transport/route?${params.toString()}`, {
         method: "GET",
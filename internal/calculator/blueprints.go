@@ -0,0 +1,176 @@
+package calculator
+
+import (
+	"math"
+	"sort"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+)
+
+const (
+	// MaxBlueprintME is the highest material efficiency a blueprint can be researched to.
+	MaxBlueprintME = 10
+	// MaxBlueprintTE is the highest time efficiency a blueprint can be researched to.
+	MaxBlueprintTE = 20
+)
+
+// BlueprintValuationParams are the facility settings used to value blueprints in the library.
+// They deliberately describe a neutral setup (NPC station, no rigs, max skills, no cost index)
+// so groups can be compared against each other regardless of where they are built.
+var BlueprintValuationParams = ManufacturingParams{
+	Runs:             1,
+	Structure:        "station",
+	Rig:              "none",
+	Security:         "high",
+	IndustrySkill:    5,
+	AdvIndustrySkill: 5,
+}
+
+// BuildBlueprintLibrary groups blueprints by blueprint type (and therefore product) and sets the
+// research, duplicate and plan-usage flags on both the groups and the individual blueprints.
+//
+// A BPO is unresearched when it has ME 0 and TE 0. Within a group, every BPO except the best
+// researched one is flagged as a duplicate. A group has unused BPCs when it holds copies but no
+// production plan step uses the blueprint type.
+func BuildBlueprintLibrary(items []*models.BlueprintLibraryItem) *models.BlueprintLibrary {
+	library := &models.BlueprintLibrary{
+		Groups: []*models.BlueprintLibraryGroup{},
+	}
+
+	groups := map[int64]*models.BlueprintLibraryGroup{}
+	for _, item := range items {
+		group, ok := groups[item.BlueprintTypeID]
+		if !ok {
+			group = &models.BlueprintLibraryGroup{
+				BlueprintTypeID: item.BlueprintTypeID,
+				BlueprintName:   item.BlueprintName,
+				ProductTypeID:   item.ProductTypeID,
+				ProductName:     item.ProductName,
+				Activity:        item.Activity,
+				Blueprints:      []*models.BlueprintLibraryItem{},
+			}
+			groups[item.BlueprintTypeID] = group
+			library.Groups = append(library.Groups, group)
+		}
+
+		group.Blueprints = append(group.Blueprints, item)
+		group.UsedByPlan = group.UsedByPlan || item.UsedByPlan
+
+		if item.IsCopy {
+			group.BPCCount++
+			group.TotalBPCRuns += item.Runs
+			library.Summary.TotalBPCs++
+		} else {
+			item.IsUnresearched = item.MaterialEfficiency == 0 && item.TimeEfficiency == 0
+			if item.IsUnresearched {
+				group.HasUnresearchedBPO = true
+				library.Summary.UnresearchedBPOs++
+			}
+			group.BPOCount += bpoStackSize(item)
+			library.Summary.TotalBPOs += bpoStackSize(item)
+		}
+
+		item.IsFullyResearched = item.MaterialEfficiency >= MaxBlueprintME && item.TimeEfficiency >= MaxBlueprintTE
+
+		if item.MaterialEfficiency > group.BestME ||
+			(item.MaterialEfficiency == group.BestME && item.TimeEfficiency > group.BestTE) {
+			group.BestME = item.MaterialEfficiency
+			group.BestTE = item.TimeEfficiency
+		}
+	}
+
+	for _, group := range library.Groups {
+		markDuplicateBPOs(group)
+		if group.HasDuplicateBPOs {
+			library.Summary.DuplicateBPOs += group.BPOCount - 1
+		}
+
+		group.HasUnusedBPCs = group.BPCCount > 0 && !group.UsedByPlan
+		if group.HasUnusedBPCs {
+			library.Summary.UnusedBPCGroups++
+		}
+
+		if group.ProductTypeID != nil {
+			library.Summary.ProductsCovered++
+		}
+	}
+
+	sort.SliceStable(library.Groups, func(i, j int) bool {
+		return library.Groups[i].BlueprintName < library.Groups[j].BlueprintName
+	})
+
+	return library
+}
+
+// bpoStackSize returns how many originals an ESI blueprint row represents.
+// Singleton originals report quantity -1; stacked (packaged) originals report the stack size.
+func bpoStackSize(item *models.BlueprintLibraryItem) int {
+	if item.Quantity > 0 {
+		return item.Quantity
+	}
+	return 1
+}
+
+// markDuplicateBPOs flags every BPO in the group except the best researched one.
+func markDuplicateBPOs(group *models.BlueprintLibraryGroup) {
+	if group.BPOCount < 2 {
+		return
+	}
+	group.HasDuplicateBPOs = true
+
+	var best *models.BlueprintLibraryItem
+	for _, item := range group.Blueprints {
+		if item.IsCopy {
+			continue
+		}
+		if best == nil ||
+			item.MaterialEfficiency > best.MaterialEfficiency ||
+			(item.MaterialEfficiency == best.MaterialEfficiency && item.TimeEfficiency > best.TimeEfficiency) {
+			best = item
+		}
+	}
+
+	for _, item := range group.Blueprints {
+		if !item.IsCopy && item != best {
+			item.IsDuplicate = true
+		}
+	}
+	// A single stacked row of originals is still a duplicate holding.
+	if best != nil && best.Quantity > 1 {
+		best.IsDuplicate = true
+	}
+}
+
+// ValueBlueprintGroup sets the profit fields of a manufacturing group using BlueprintValuationParams.
+// ProfitPerRun uses the group's best ME/TE, MaxMEProfitPerRun assumes a fully researched blueprint,
+// and ResearchGainPerRun is the difference — only set when the group holds an original that can
+// still be researched. Profit is net of fees when fees is set.
+func ValueBlueprintGroup(group *models.BlueprintLibraryGroup, data *ManufacturingData, fees *models.TradeFees) {
+	params := BlueprintValuationParams
+	if fees != nil {
+		params.SalesTaxPct = fees.SalesTaxPct
+		params.BrokerFeePct = fees.BrokerFeePct
+	}
+	params.BlueprintME = group.BestME
+	params.BlueprintTE = group.BestTE
+	current := CalculateManufacturingJob(&params, data)
+
+	params.BlueprintME = MaxBlueprintME
+	params.BlueprintTE = MaxBlueprintTE
+	maxed := CalculateManufacturingJob(&params, data)
+
+	profitPerRun := current.Profit
+	maxProfitPerRun := maxed.Profit
+	group.ProfitPerRun = &profitPerRun
+	group.MaxMEProfitPerRun = &maxProfitPerRun
+
+	if current.SecsPerRun > 0 {
+		profitPerDay := math.Round(profitPerRun*(86400.0/float64(current.SecsPerRun))*100) / 100
+		group.ProfitPerDay = &profitPerDay
+	}
+
+	if group.BPOCount > 0 && (group.BestME < MaxBlueprintME || group.BestTE < MaxBlueprintTE) {
+		gain := math.Round((maxProfitPerRun-profitPerRun)*100) / 100
+		group.ResearchGainPerRun = &gain
+	}
+}
@@ -0,0 +1,168 @@
+package calculator
+
+import (
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func libraryItem(itemID, bpTypeID int64, name string, quantity, me, te, runs int, usedByPlan bool) *models.BlueprintLibraryItem {
+	productID := bpTypeID - 200
+	activity := "manufacturing"
+	return &models.BlueprintLibraryItem{
+		ItemID:             itemID,
+		BlueprintTypeID:    bpTypeID,
+		BlueprintName:      name,
+		ProductTypeID:      &productID,
+		Activity:           &activity,
+		Quantity:           quantity,
+		IsCopy:             quantity == -2,
+		MaterialEfficiency: me,
+		TimeEfficiency:     te,
+		Runs:               runs,
+		UsedByPlan:         usedByPlan,
+	}
+}
+
+func TestBuildBlueprintLibrary_GroupsAndFlags(t *testing.T) {
+	items := []*models.BlueprintLibraryItem{
+		libraryItem(1, 787, "Rifter Blueprint", -1, 10, 20, -1, true),
+		libraryItem(2, 787, "Rifter Blueprint", -1, 0, 0, -1, true),
+		libraryItem(3, 787, "Rifter Blueprint", -2, 8, 16, 10, true),
+		libraryItem(4, 688, "Merlin Blueprint", -2, 10, 20, 5, false),
+		libraryItem(5, 688, "Merlin Blueprint", -2, 10, 20, 3, false),
+	}
+
+	library := BuildBlueprintLibrary(items)
+
+	assert.Len(t, library.Groups, 2)
+
+	merlin := library.Groups[0]
+	assert.Equal(t, "Merlin Blueprint", merlin.BlueprintName)
+	assert.Equal(t, 0, merlin.BPOCount)
+	assert.Equal(t, 2, merlin.BPCCount)
+	assert.Equal(t, 8, merlin.TotalBPCRuns)
+	assert.False(t, merlin.HasDuplicateBPOs)
+	assert.True(t, merlin.HasUnusedBPCs)
+
+	rifter := library.Groups[1]
+	assert.Equal(t, 2, rifter.BPOCount)
+	assert.Equal(t, 1, rifter.BPCCount)
+	assert.Equal(t, 10, rifter.BestME)
+	assert.Equal(t, 20, rifter.BestTE)
+	assert.True(t, rifter.HasUnresearchedBPO)
+	assert.True(t, rifter.HasDuplicateBPOs)
+	assert.False(t, rifter.HasUnusedBPCs)
+
+	assert.False(t, rifter.Blueprints[0].IsDuplicate)
+	assert.True(t, rifter.Blueprints[0].IsFullyResearched)
+	assert.True(t, rifter.Blueprints[1].IsDuplicate)
+	assert.True(t, rifter.Blueprints[1].IsUnresearched)
+	assert.False(t, rifter.Blueprints[2].IsDuplicate)
+
+	assert.Equal(t, 2, library.Summary.TotalBPOs)
+	assert.Equal(t, 3, library.Summary.TotalBPCs)
+	assert.Equal(t, 1, library.Summary.UnresearchedBPOs)
+	assert.Equal(t, 1, library.Summary.DuplicateBPOs)
+	assert.Equal(t, 1, library.Summary.UnusedBPCGroups)
+	assert.Equal(t, 2, library.Summary.ProductsCovered)
+}
+
+func TestBuildBlueprintLibrary_StackedOriginalsAreDuplicates(t *testing.T) {
+	items := []*models.BlueprintLibraryItem{
+		libraryItem(1, 787, "Rifter Blueprint", 3, 0, 0, -1, false),
+	}
+
+	library := BuildBlueprintLibrary(items)
+
+	assert.Len(t, library.Groups, 1)
+	assert.Equal(t, 3, library.Groups[0].BPOCount)
+	assert.True(t, library.Groups[0].HasDuplicateBPOs)
+	assert.True(t, library.Groups[0].Blueprints[0].IsDuplicate)
+	assert.Equal(t, 2, library.Summary.DuplicateBPOs)
+}
+
+func TestBuildBlueprintLibrary_Empty(t *testing.T) {
+	library := BuildBlueprintLibrary([]*models.BlueprintLibraryItem{})
+
+	assert.NotNil(t, library.Groups)
+	assert.Len(t, library.Groups, 0)
+	assert.Equal(t, 0, library.Summary.TotalBPOs)
+}
+
+func TestValueBlueprintGroup(t *testing.T) {
+	mineralPrice := 10.0
+	productPrice := 2000000.0
+	data := &ManufacturingData{
+		Blueprint: &repositories.ManufacturingBlueprintRow{
+			BlueprintTypeID: 787,
+			ProductTypeID:   587,
+			ProductName:     "Rifter",
+			ProductQuantity: 1,
+			Time:            3600,
+		},
+		Materials: []*repositories.ManufacturingMaterialRow{
+			{BlueprintTypeID: 787, TypeID: 34, TypeName: "Tritanium", Quantity: 100000},
+		},
+		AdjustedPrices: map[int64]float64{},
+		JitaPrices: map[int64]*models.MarketPrice{
+			34:  {TypeID: 34, SellPrice: &mineralPrice},
+			587: {TypeID: 587, SellPrice: &productPrice},
+		},
+	}
+
+	group := &models.BlueprintLibraryGroup{BlueprintTypeID: 787, BPOCount: 1, BestME: 0, BestTE: 0}
+	ValueBlueprintGroup(group, data, nil)
+
+	// ME0: 100000 trit * 10 = 1,000,000 input; ME10: 90000 * 10 = 900,000 input
+	assert.InDelta(t, 1000000.0, *group.ProfitPerRun, 0.01)
+	assert.InDelta(t, 1100000.0, *group.MaxMEProfitPerRun, 0.01)
+	assert.InDelta(t, 100000.0, *group.ResearchGainPerRun, 0.01)
+	assert.NotNil(t, group.ProfitPerDay)
+	assert.Greater(t, *group.ProfitPerDay, *group.ProfitPerRun)
+}
+
+func TestValueBlueprintGroup_NetOfFees(t *testing.T) {
+	productPrice := 2000000.0
+	data := &ManufacturingData{
+		Blueprint: &repositories.ManufacturingBlueprintRow{
+			BlueprintTypeID: 787,
+			ProductTypeID:   587,
+			ProductQuantity: 1,
+			Time:            3600,
+		},
+		Materials:      []*repositories.ManufacturingMaterialRow{},
+		AdjustedPrices: map[int64]float64{},
+		JitaPrices: map[int64]*models.MarketPrice{
+			587: {TypeID: 587, SellPrice: &productPrice},
+		},
+	}
+
+	group := &models.BlueprintLibraryGroup{BlueprintTypeID: 787, BPCCount: 1}
+	ValueBlueprintGroup(group, data, &models.TradeFees{SalesTaxPct: 5, BrokerFeePct: 2})
+
+	// 2,000,000 less 7% fees
+	assert.InDelta(t, 1860000.0, *group.ProfitPerRun, 0.01)
+}
+
+func TestValueBlueprintGroup_NoResearchGainForCopiesOnly(t *testing.T) {
+	data := &ManufacturingData{
+		Blueprint: &repositories.ManufacturingBlueprintRow{
+			BlueprintTypeID: 787,
+			ProductTypeID:   587,
+			ProductQuantity: 1,
+			Time:            3600,
+		},
+		Materials:      []*repositories.ManufacturingMaterialRow{},
+		AdjustedPrices: map[int64]float64{},
+		JitaPrices:     map[int64]*models.MarketPrice{},
+	}
+
+	group := &models.BlueprintLibraryGroup{BlueprintTypeID: 787, BPCCount: 2, BestME: 4, BestTE: 8}
+	ValueBlueprintGroup(group, data, nil)
+
+	assert.NotNil(t, group.ProfitPerRun)
+	assert.Nil(t, group.ResearchGainPerRun)
+}
@@ -0,0 +1,112 @@
+package calculator
+
+import (
+	"sort"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+)
+
+// ConsolidationCandidate is an open transport job with the systems its route
+// passes through, origin and destination included.
+type ConsolidationCandidate struct {
+	Job   *models.TransportJob
+	Route []int64
+}
+
+// ConsolidationCostFunc returns the trips and cost of carrying volume and
+// collateral along host's route.
+type ConsolidationCostFunc func(host *ConsolidationCandidate, volume, collateral float64) (int, float64)
+
+// IsSubRoute reports whether sub's origin and destination both lie on route,
+// in travel order. A route is a sub-route of itself.
+func IsSubRoute(route, sub []int64) bool {
+	if len(sub) < 2 {
+		return false
+	}
+	origin, destination := sub[0], sub[len(sub)-1]
+	for i, id := range route {
+		if id != origin {
+			continue
+		}
+		for _, next := range route[i+1:] {
+			if next == destination {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PlanConsolidation groups candidates sharing one transport profile into trips.
+// Jobs with the longest routes host a trip; a job joins when its route is a
+// sub-route of the host's, it fits in the spare cargo room of the host's trips
+// and carrying it costs no more than hauling it alone. Only trips merging two
+// or more jobs are returned.
+func PlanConsolidation(candidates []*ConsolidationCandidate, costFor ConsolidationCostFunc) []*models.TransportConsolidation {
+	sorted := make([]*ConsolidationCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if len(c.Route) >= 2 {
+			sorted = append(sorted, c)
+		}
+	}
+	sort.SliceStable(sorted, func(a, b int) bool {
+		if len(sorted[a].Route) != len(sorted[b].Route) {
+			return len(sorted[a].Route) > len(sorted[b].Route)
+		}
+		return sorted[a].Job.ID < sorted[b].Job.ID
+	})
+
+	used := map[int64]bool{}
+	proposals := []*models.TransportConsolidation{}
+	for i, host := range sorted {
+		if used[host.Job.ID] {
+			continue
+		}
+
+		volume, collateral := host.Job.TotalVolumeM3, host.Job.TotalCollateral
+		trips, cost := costFor(host, volume, collateral)
+		proposal := &models.TransportConsolidation{
+			HostJobID:             host.Job.ID,
+			JobIDs:                []int64{host.Job.ID},
+			TransportMethod:       host.Job.TransportMethod,
+			TransportProfileID:    host.Job.TransportProfileID,
+			OriginSystemName:      host.Job.OriginSystemName,
+			DestinationSystemName: host.Job.DestinationSystemName,
+			SeparateTrips:         trips,
+			SeparateCost:          cost,
+		}
+
+		for _, c := range sorted[i+1:] {
+			if used[c.Job.ID] || !IsSubRoute(host.Route, c.Route) {
+				continue
+			}
+			aloneTrips, aloneCost := costFor(c, c.Job.TotalVolumeM3, c.Job.TotalCollateral)
+			withTrips, withCost := costFor(host, volume+c.Job.TotalVolumeM3, collateral+c.Job.TotalCollateral)
+			if withTrips > trips || withCost-cost > aloneCost {
+				continue
+			}
+
+			proposal.JobIDs = append(proposal.JobIDs, c.Job.ID)
+			proposal.SeparateTrips += aloneTrips
+			proposal.SeparateCost += aloneCost
+			volume += c.Job.TotalVolumeM3
+			collateral += c.Job.TotalCollateral
+			cost = withCost
+		}
+
+		if len(proposal.JobIDs) < 2 {
+			continue
+		}
+		for _, id := range proposal.JobIDs {
+			used[id] = true
+		}
+		proposal.TotalVolumeM3 = volume
+		proposal.TotalCollateral = collateral
+		proposal.ConsolidatedTrips = trips
+		proposal.ConsolidatedCost = cost
+		proposal.IskSaved = proposal.SeparateCost - cost
+		proposals = append(proposals, proposal)
+	}
+
+	return proposals
+}
@@ -0,0 +1,110 @@
+package calculator
+
+import (
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func consolidationCandidate(id int64, volume, collateral float64, route ...int64) *ConsolidationCandidate {
+	return &ConsolidationCandidate{
+		Job:   &models.TransportJob{ID: id, TotalVolumeM3: volume, TotalCollateral: collateral, TransportMethod: "jump_freighter"},
+		Route: route,
+	}
+}
+
+func TestIsSubRoute(t *testing.T) {
+	route := []int64{1, 2, 3, 4}
+
+	assert.True(t, IsSubRoute(route, []int64{1, 2, 3, 4}))
+	assert.True(t, IsSubRoute(route, []int64{2, 4}))
+	assert.True(t, IsSubRoute(route, []int64{2, 9, 3}), "only the endpoints need to be on the route")
+	assert.False(t, IsSubRoute(route, []int64{4, 2}), "wrong direction")
+	assert.False(t, IsSubRoute(route, []int64{2, 5}))
+	assert.False(t, IsSubRoute(route, []int64{2}))
+}
+
+func TestPlanConsolidation(t *testing.T) {
+	// JF pricing: fixed fuel per trip plus collateral, 1,000 m3 cargo
+	jfCost := func(host *ConsolidationCandidate, volume, collateral float64) (int, float64) {
+		result := CalculateJFTransportCost(&JFTransportCostParams{
+			TotalVolumeM3:   volume,
+			TotalCollateral: collateral,
+			CargoM3:         1000,
+			CollateralRate:  0.01,
+			FuelPerLY:       100,
+			IsotopePrice:    1000,
+			Waypoints:       []*models.JFRouteWaypoint{{DistanceLY: 0}, {DistanceLY: float64(len(host.Route) - 1)}},
+		})
+		return result.Trips, result.Cost
+	}
+
+	t.Run("same lane and sub-route", func(t *testing.T) {
+		proposals := PlanConsolidation([]*ConsolidationCandidate{
+			consolidationCandidate(1, 300, 1000000, 10, 20),
+			consolidationCandidate(2, 200, 1000000, 10, 20, 30),
+			consolidationCandidate(3, 100, 0, 10, 20, 30),
+		}, jfCost)
+
+		require.Len(t, proposals, 1)
+		p := proposals[0]
+		assert.Equal(t, int64(2), p.HostJobID)
+		assert.Equal(t, []int64{2, 3, 1}, p.JobIDs)
+		assert.Equal(t, 600.0, p.TotalVolumeM3)
+		assert.Equal(t, 3, p.SeparateTrips)
+		assert.Equal(t, 1, p.ConsolidatedTrips)
+		// Alone: 210,000 + 200,000 + 110,000; together: 200,000 fuel + 20,000 collateral
+		assert.InDelta(t, 520000, p.SeparateCost, 0.01)
+		assert.InDelta(t, 220000, p.ConsolidatedCost, 0.01)
+		assert.InDelta(t, 300000, p.IskSaved, 0.01)
+	})
+
+	t.Run("respects cargo capacity", func(t *testing.T) {
+		proposals := PlanConsolidation([]*ConsolidationCandidate{
+			consolidationCandidate(1, 700, 0, 10, 20),
+			consolidationCandidate(2, 400, 0, 10, 20),
+			consolidationCandidate(3, 300, 0, 10, 20),
+		}, jfCost)
+
+		require.Len(t, proposals, 1)
+		assert.Equal(t, []int64{1, 3}, proposals[0].JobIDs)
+		assert.Equal(t, 1, proposals[0].ConsolidatedTrips)
+	})
+
+	t.Run("skips merges that cost more", func(t *testing.T) {
+		// Gate pricing charges every m3 for every jump of the host route
+		gateCost := func(host *ConsolidationCandidate, volume, collateral float64) (int, float64) {
+			result := CalculateGateTransportCost(&GateTransportCostParams{
+				TotalVolumeM3:    volume,
+				TotalCollateral:  collateral,
+				Jumps:            len(host.Route) - 1,
+				CargoM3:          1000,
+				RatePerM3PerJump: 10,
+			})
+			return result.Trips, result.Cost
+		}
+
+		proposals := PlanConsolidation([]*ConsolidationCandidate{
+			consolidationCandidate(1, 100, 0, 10, 20, 30, 40),
+			consolidationCandidate(2, 100, 0, 20, 30),
+			consolidationCandidate(3, 100, 0, 10, 20, 30, 40),
+		}, gateCost)
+
+		require.Len(t, proposals, 1)
+		assert.Equal(t, []int64{1, 3}, proposals[0].JobIDs)
+		assert.Equal(t, 2, proposals[0].SeparateTrips)
+		assert.Equal(t, 0.0, proposals[0].IskSaved)
+	})
+
+	t.Run("nothing to merge", func(t *testing.T) {
+		proposals := PlanConsolidation([]*ConsolidationCandidate{
+			consolidationCandidate(1, 100, 0, 10, 20),
+			consolidationCandidate(2, 100, 0, 20, 10),
+			consolidationCandidate(3, 100, 0, 10),
+		}, jfCost)
+
+		assert.Empty(t, proposals)
+	})
+}
@@ -0,0 +1,211 @@
+package calculator
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+)
+
+// routeSecurityPenalty is the per-jump cost of an unwanted security band under
+// the "secure" and "insecure" flags. It outweighs any detour through the
+// preferred band, so unwanted systems are only entered when there is no way
+// around them, as with ESI's route flags.
+const routeSecurityPenalty = 10000.0
+
+// RouteJumpCostsForFlag returns the per-jump costs implied by an ESI route
+// flag. Unknown flags route by fewest jumps.
+func RouteJumpCostsForFlag(flag string) models.RouteJumpCosts {
+	switch flag {
+	case "secure":
+		return models.RouteJumpCosts{Highsec: 1, Lowsec: routeSecurityPenalty, Nullsec: routeSecurityPenalty}
+	case "insecure":
+		return models.RouteJumpCosts{Highsec: routeSecurityPenalty, Lowsec: 1, Nullsec: 1}
+	default:
+		return models.RouteJumpCosts{Highsec: 1, Lowsec: 1, Nullsec: 1}
+	}
+}
+
+// UnknownRouteSystemError is returned when a route endpoint is not in the
+// stargate graph.
+type UnknownRouteSystemError struct {
+	SystemID int64
+}
+
+func (e *UnknownRouteSystemError) Error() string {
+	return fmt.Sprintf("system %d is not in the stargate graph", e.SystemID)
+}
+
+// NoGateRouteError is returned when no gate route connects the endpoints
+// under the route options.
+type NoGateRouteError struct {
+	Origin      int64
+	Destination int64
+}
+
+func (e *NoGateRouteError) Error() string {
+	return fmt.Sprintf("no gate route from %d to %d", e.Origin, e.Destination)
+}
+
+func routeJumpCost(costs models.RouteJumpCosts, security float64) float64 {
+	switch {
+	case security >= HighsecMinSecurity:
+		return costs.Highsec
+	case security > 0.0:
+		return costs.Lowsec
+	default:
+		return costs.Nullsec
+	}
+}
+
+// GateGraph is the stargate network: solar systems joined by gate jumps.
+type GateGraph struct {
+	systems   map[int64]models.RouteSystem
+	adjacency map[int64][]int64
+}
+
+// NewGateGraph builds a graph from SDE systems and jumps. Jumps touching a
+// system not in systems are dropped.
+func NewGateGraph(systems []models.RouteSystem, jumps []models.SystemJump) *GateGraph {
+	g := &GateGraph{
+		systems:   make(map[int64]models.RouteSystem, len(systems)),
+		adjacency: make(map[int64][]int64, len(systems)),
+	}
+	for _, s := range systems {
+		g.systems[s.ID] = s
+	}
+	for _, j := range jumps {
+		if _, ok := g.systems[j.FromSystemID]; !ok {
+			continue
+		}
+		if _, ok := g.systems[j.ToSystemID]; !ok {
+			continue
+		}
+		g.adjacency[j.FromSystemID] = append(g.adjacency[j.FromSystemID], j.ToSystemID)
+	}
+	// Sorted neighbours keep equal-cost routes stable between runs
+	for id := range g.adjacency {
+		sort.Slice(g.adjacency[id], func(a, b int) bool { return g.adjacency[id][a] < g.adjacency[id][b] })
+	}
+	return g
+}
+
+// Len returns the number of systems in the graph.
+func (g *GateGraph) Len() int {
+	return len(g.systems)
+}
+
+// routeQueueItem is a system waiting to be visited with its cost so far.
+type routeQueueItem struct {
+	systemID int64
+	cost     float64
+	jumps    int
+}
+
+type routeQueue []routeQueueItem
+
+func (q routeQueue) Len() int { return len(q) }
+func (q routeQueue) Less(a, b int) bool {
+	if q[a].cost != q[b].cost {
+		return q[a].cost < q[b].cost
+	}
+	if q[a].jumps != q[b].jumps {
+		return q[a].jumps < q[b].jumps
+	}
+	return q[a].systemID < q[b].systemID
+}
+func (q routeQueue) Swap(a, b int) { q[a], q[b] = q[b], q[a] }
+func (q *routeQueue) Push(x any)   { *q = append(*q, x.(routeQueueItem)) }
+func (q *routeQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// FindRoute returns the cheapest gate route from origin to destination.
+// Each jump costs the opts jump cost for the security of the system entered,
+// with ties broken by fewest jumps. Avoided systems and regions, and systems
+// below MinSecurity, are never entered; the origin itself is always allowed.
+func (g *GateGraph) FindRoute(origin, destination int64, opts *models.RouteOptions) (*models.GateRoute, error) {
+	if opts == nil {
+		opts = &models.RouteOptions{}
+	}
+	if _, ok := g.systems[origin]; !ok {
+		return nil, &UnknownRouteSystemError{SystemID: origin}
+	}
+	if _, ok := g.systems[destination]; !ok {
+		return nil, &UnknownRouteSystemError{SystemID: destination}
+	}
+
+	costs := RouteJumpCostsForFlag(opts.Flag)
+	if opts.JumpCosts != nil {
+		costs = *opts.JumpCosts
+	}
+	avoidSystems := make(map[int64]bool, len(opts.AvoidSystemIDs))
+	for _, id := range opts.AvoidSystemIDs {
+		avoidSystems[id] = true
+	}
+	avoidRegions := make(map[int64]bool, len(opts.AvoidRegionIDs))
+	for _, id := range opts.AvoidRegionIDs {
+		avoidRegions[id] = true
+	}
+	allowed := func(s models.RouteSystem) bool {
+		if avoidSystems[s.ID] || avoidRegions[s.RegionID] {
+			return false
+		}
+		return opts.MinSecurity == nil || s.Security >= *opts.MinSecurity
+	}
+
+	best := map[int64]routeQueueItem{origin: {systemID: origin}}
+	prev := map[int64]int64{}
+	done := map[int64]bool{}
+	queue := &routeQueue{{systemID: origin}}
+
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(routeQueueItem)
+		if done[current.systemID] {
+			continue
+		}
+		done[current.systemID] = true
+		if current.systemID == destination {
+			break
+		}
+
+		for _, next := range g.adjacency[current.systemID] {
+			system := g.systems[next]
+			if done[next] || !allowed(system) {
+				continue
+			}
+			candidate := routeQueueItem{
+				systemID: next,
+				cost:     current.cost + routeJumpCost(costs, system.Security),
+				jumps:    current.jumps + 1,
+			}
+			if known, ok := best[next]; ok {
+				if known.cost < candidate.cost || (known.cost == candidate.cost && known.jumps <= candidate.jumps) {
+					continue
+				}
+			}
+			best[next] = candidate
+			prev[next] = current.systemID
+			heap.Push(queue, candidate)
+		}
+	}
+
+	if !done[destination] {
+		return nil, &NoGateRouteError{Origin: origin, Destination: destination}
+	}
+
+	route := []int32{int32(destination)}
+	for id := destination; id != origin; {
+		id = prev[id]
+		route = append([]int32{int32(id)}, route...)
+	}
+	return &models.GateRoute{
+		Route: route,
+		Jumps: len(route) - 1,
+		Cost:  best[destination].cost,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package calculator
+
+import (
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGateGraph has a two-jump lowsec shortcut (1-3-5) and a three-jump
+// highsec way round (1-2-4-5).
+func testGateGraph() *GateGraph {
+	systems := []models.RouteSystem{
+		{ID: 1, RegionID: 100, Security: 0.9},
+		{ID: 2, RegionID: 100, Security: 0.8},
+		{ID: 3, RegionID: 100, Security: 0.3},
+		{ID: 4, RegionID: 200, Security: 0.7},
+		{ID: 5, RegionID: 200, Security: 0.9},
+	}
+	jumps := []models.SystemJump{}
+	for _, pair := range [][2]int64{{1, 3}, {3, 5}, {1, 2}, {2, 4}, {4, 5}, {5, 99}} {
+		jumps = append(jumps,
+			models.SystemJump{FromSystemID: pair[0], ToSystemID: pair[1]},
+			models.SystemJump{FromSystemID: pair[1], ToSystemID: pair[0]},
+		)
+	}
+	return NewGateGraph(systems, jumps)
+}
+
+func TestGateGraphFindRoute(t *testing.T) {
+	g := testGateGraph()
+	assert.Equal(t, 5, g.Len())
+
+	minSecurity := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name  string
+		from  int64
+		to    int64
+		opts  *models.RouteOptions
+		route []int32
+		cost  float64
+	}{
+		{"shortest", 1, 5, &models.RouteOptions{Flag: "shortest"}, []int32{1, 3, 5}, 2},
+		{"default options", 1, 5, nil, []int32{1, 3, 5}, 2},
+		{"secure", 1, 5, &models.RouteOptions{Flag: "secure"}, []int32{1, 2, 4, 5}, 3},
+		{"insecure", 1, 5, &models.RouteOptions{Flag: "insecure"}, []int32{1, 3, 5}, 1 + routeSecurityPenalty},
+		{"avoid system", 1, 5, &models.RouteOptions{AvoidSystemIDs: []int64{3}}, []int32{1, 2, 4, 5}, 3},
+		{"minimum security", 1, 5, &models.RouteOptions{MinSecurity: minSecurity(0.5)}, []int32{1, 2, 4, 5}, 3},
+		{"origin exempt from constraints", 3, 5, &models.RouteOptions{MinSecurity: minSecurity(0.5), AvoidSystemIDs: []int64{3}}, []int32{3, 5}, 1},
+		{"custom jump costs", 1, 5, &models.RouteOptions{JumpCosts: &models.RouteJumpCosts{Highsec: 1, Lowsec: 5, Nullsec: 10}}, []int32{1, 2, 4, 5}, 3},
+		{"same system", 1, 1, nil, []int32{1}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, err := g.FindRoute(tt.from, tt.to, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.route, route.Route)
+			assert.Equal(t, len(tt.route)-1, route.Jumps)
+			assert.InDelta(t, tt.cost, route.Cost, 0.0001)
+		})
+	}
+
+	t.Run("avoided destination region", func(t *testing.T) {
+		_, err := g.FindRoute(1, 5, &models.RouteOptions{AvoidRegionIDs: []int64{200}})
+		assert.EqualError(t, err, "no gate route from 1 to 5")
+	})
+
+	t.Run("minimum security above every route", func(t *testing.T) {
+		_, err := g.FindRoute(1, 5, &models.RouteOptions{MinSecurity: minSecurity(0.95)})
+		assert.EqualError(t, err, "no gate route from 1 to 5")
+	})
+
+	t.Run("unknown system", func(t *testing.T) {
+		_, err := g.FindRoute(1, 99, nil)
+		assert.EqualError(t, err, "system 99 is not in the stargate graph")
+	})
+}
+
+func TestRouteJumpCostsForFlag(t *testing.T) {
+	assert.Equal(t, models.RouteJumpCosts{Highsec: 1, Lowsec: 1, Nullsec: 1}, RouteJumpCostsForFlag("shortest"))
+	assert.Equal(t, models.RouteJumpCosts{Highsec: 1, Lowsec: 1, Nullsec: 1}, RouteJumpCostsForFlag(""))
+	assert.Equal(t, routeSecurityPenalty, RouteJumpCostsForFlag("secure").Lowsec)
+	assert.Equal(t, routeSecurityPenalty, RouteJumpCostsForFlag("insecure").Highsec)
+}
@@ -0,0 +1,153 @@
+package calculator
+
+import (
+	"math"
+	"sort"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+)
+
+// cargoVolumeWeights blend how scarce cargo space is against capital when
+// ordering candidates: 1 ranks by profit per m³, 0 by profit per ISK spent.
+var cargoVolumeWeights = []float64{1, 0.75, 0.5, 0.25, 0}
+
+// CargoCandidate is one item type the optimizer may load.
+type CargoCandidate struct {
+	Row           *models.HaulingArbitrageRow
+	VolumeM3      float64
+	UnitCostISK   float64 // buy price plus buy-side fees
+	ProfitPerUnit float64 // sell price less sell-side fees, less UnitCostISK
+	MaxUnits      int64
+}
+
+// CargoPick is a chosen quantity of one candidate.
+type CargoPick struct {
+	Candidate *CargoCandidate
+	Quantity  int64
+}
+
+// CargoPlan is the optimizer's chosen load.
+type CargoPlan struct {
+	Picks          []*CargoPick
+	VolumeM3       float64
+	CostISK        float64
+	ExpectedProfit float64
+}
+
+// CargoLimits bounds how much of each type is loaded.
+type CargoLimits struct {
+	MaxUnitsPerType int64   // 0 = no limit
+	MaxDaysToSell   float64 // caps units at destination avg daily volume × days; 0 = no cap
+}
+
+// BuildCargoCandidates turns scanner rows into optimizer candidates, with
+// profit after fees and units capped by availability and the limits. Rows
+// without a price or volume, or that lose money after fees, are dropped.
+func BuildCargoCandidates(rows []*models.HaulingArbitrageRow, fees *models.HaulingFeesProfile, limits CargoLimits) []*CargoCandidate {
+	candidates := []*CargoCandidate{}
+	for _, row := range rows {
+		if row.BuyPrice == nil || row.SellPrice == nil || row.VolumeM3 == nil || *row.VolumeM3 <= 0 {
+			continue
+		}
+
+		unitCost := *row.BuyPrice * (1 + fees.BuyBrokerFeePct/100)
+		unitRevenue := *row.SellPrice * (1 - (fees.SalesTaxPct+fees.SellBrokerFeePct)/100)
+		profit := unitRevenue - unitCost
+		if profit <= 0 {
+			continue
+		}
+
+		maxUnits := int64(math.MaxInt64)
+		if row.VolumeAvailable != nil {
+			maxUnits = *row.VolumeAvailable
+		}
+		if limits.MaxUnitsPerType > 0 && limits.MaxUnitsPerType < maxUnits {
+			maxUnits = limits.MaxUnitsPerType
+		}
+		if limits.MaxDaysToSell > 0 && row.DestAvgDailyVolume != nil {
+			sellable := int64(math.Floor(*row.DestAvgDailyVolume * limits.MaxDaysToSell))
+			if sellable < maxUnits {
+				maxUnits = sellable
+			}
+		}
+		if maxUnits <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, &CargoCandidate{
+			Row:           row,
+			VolumeM3:      *row.VolumeM3,
+			UnitCostISK:   unitCost,
+			ProfitPerUnit: profit,
+			MaxUnits:      maxUnits,
+		})
+	}
+	return candidates
+}
+
+// OptimizeCargo chooses quantities that maximize expected profit within the
+// cargo volume and capital. capitalISK <= 0 means capital is not a limit.
+//
+// Loading under two limits is a bounded knapsack, so this is a greedy
+// heuristic: candidates are ordered by profit per blended share of cargo and
+// capital and loaded as far as they fit, for several blends, keeping the best
+// plan. When only one limit binds this is the usual profit-density greedy.
+func OptimizeCargo(candidates []*CargoCandidate, volumeM3, capitalISK float64) *CargoPlan {
+	best := &CargoPlan{Picks: []*CargoPick{}}
+	if volumeM3 <= 0 {
+		return best
+	}
+
+	for _, weight := range cargoVolumeWeights {
+		if capitalISK <= 0 && weight < 1 {
+			continue
+		}
+		plan := greedyCargo(candidates, volumeM3, capitalISK, weight)
+		if plan.ExpectedProfit > best.ExpectedProfit {
+			best = plan
+		}
+	}
+	return best
+}
+
+func greedyCargo(candidates []*CargoCandidate, volumeM3, capitalISK, volumeWeight float64) *CargoPlan {
+	share := func(c *CargoCandidate) float64 {
+		s := volumeWeight * c.VolumeM3 / volumeM3
+		if capitalISK > 0 {
+			s += (1 - volumeWeight) * c.UnitCostISK / capitalISK
+		}
+		return s
+	}
+
+	ordered := make([]*CargoCandidate, len(candidates))
+	copy(ordered, candidates)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].ProfitPerUnit/share(ordered[i]) > ordered[j].ProfitPerUnit/share(ordered[j])
+	})
+
+	plan := &CargoPlan{Picks: []*CargoPick{}}
+	remainingVolume := volumeM3
+	remainingCapital := capitalISK
+	for _, c := range ordered {
+		qty := c.MaxUnits
+		if fit := int64(math.Floor(remainingVolume / c.VolumeM3)); fit < qty {
+			qty = fit
+		}
+		if capitalISK > 0 && c.UnitCostISK > 0 {
+			if afford := int64(math.Floor(remainingCapital / c.UnitCostISK)); afford < qty {
+				qty = afford
+			}
+		}
+		if qty <= 0 {
+			continue
+		}
+
+		plan.Picks = append(plan.Picks, &CargoPick{Candidate: c, Quantity: qty})
+		plan.VolumeM3 += float64(qty) * c.VolumeM3
+		plan.CostISK += float64(qty) * c.UnitCostISK
+		plan.ExpectedProfit += float64(qty) * c.ProfitPerUnit
+		remainingVolume -= float64(qty) * c.VolumeM3
+		remainingCapital -= float64(qty) * c.UnitCostISK
+	}
+	return plan
+}
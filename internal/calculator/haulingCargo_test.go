@@ -0,0 +1,100 @@
+package calculator
+
+import (
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func cargoRow(typeID int64, buy, sell, volume float64, available int64) *models.HaulingArbitrageRow {
+	return &models.HaulingArbitrageRow{
+		TypeID:          typeID,
+		BuyPrice:        &buy,
+		SellPrice:       &sell,
+		VolumeM3:        &volume,
+		VolumeAvailable: &available,
+	}
+}
+
+func TestBuildCargoCandidates(t *testing.T) {
+	t.Run("fees applied and losers dropped", func(t *testing.T) {
+		rows := []*models.HaulingArbitrageRow{
+			cargoRow(1, 100, 120, 1, 1000),
+			// 5% margin does not cover 7.5% sales tax
+			cargoRow(2, 100, 105, 1, 1000),
+		}
+		fees := &models.HaulingFeesProfile{SalesTaxPct: 7.5, BuyBrokerFeePct: 1}
+
+		candidates := BuildCargoCandidates(rows, fees, CargoLimits{})
+
+		assert.Len(t, candidates, 1)
+		assert.InDelta(t, 101.0, candidates[0].UnitCostISK, 0.0001)
+		// 120 * 0.925 - 101
+		assert.InDelta(t, 10.0, candidates[0].ProfitPerUnit, 0.0001)
+		assert.Equal(t, int64(1000), candidates[0].MaxUnits)
+	})
+
+	t.Run("units capped by limit and days to sell", func(t *testing.T) {
+		row := cargoRow(1, 100, 200, 1, 1000)
+		daily := 30.0
+		row.DestAvgDailyVolume = &daily
+
+		candidates := BuildCargoCandidates([]*models.HaulingArbitrageRow{row}, &models.HaulingFeesProfile{}, CargoLimits{MaxUnitsPerType: 500, MaxDaysToSell: 3})
+
+		assert.Equal(t, int64(90), candidates[0].MaxUnits)
+	})
+
+	t.Run("rows without volume are skipped", func(t *testing.T) {
+		buy, sell := 1.0, 2.0
+		rows := []*models.HaulingArbitrageRow{{TypeID: 1, BuyPrice: &buy, SellPrice: &sell}}
+
+		assert.Len(t, BuildCargoCandidates(rows, &models.HaulingFeesProfile{}, CargoLimits{}), 0)
+	})
+}
+
+func TestOptimizeCargo(t *testing.T) {
+	t.Run("volume bound prefers profit per m3", func(t *testing.T) {
+		candidates := []*CargoCandidate{
+			{Row: &models.HaulingArbitrageRow{TypeID: 1}, VolumeM3: 10, UnitCostISK: 100, ProfitPerUnit: 50, MaxUnits: 100},
+			{Row: &models.HaulingArbitrageRow{TypeID: 2}, VolumeM3: 1, UnitCostISK: 100, ProfitPerUnit: 10, MaxUnits: 100},
+		}
+
+		plan := OptimizeCargo(candidates, 200, 0)
+
+		// Type 2 (10/m³) fills 100 m³, type 1 (5/m³) takes the other 100 m³
+		assert.Len(t, plan.Picks, 2)
+		assert.Equal(t, int64(2), plan.Picks[0].Candidate.Row.TypeID)
+		assert.Equal(t, int64(100), plan.Picks[0].Quantity)
+		assert.Equal(t, int64(10), plan.Picks[1].Quantity)
+		assert.InDelta(t, 1500.0, plan.ExpectedProfit, 0.0001)
+		assert.InDelta(t, 200.0, plan.VolumeM3, 0.0001)
+	})
+
+	t.Run("capital bound prefers profit per ISK", func(t *testing.T) {
+		candidates := []*CargoCandidate{
+			// High ISK/m³ but expensive
+			{Row: &models.HaulingArbitrageRow{TypeID: 1}, VolumeM3: 1, UnitCostISK: 1000, ProfitPerUnit: 100, MaxUnits: 100},
+			// Bulky but cheap
+			{Row: &models.HaulingArbitrageRow{TypeID: 2}, VolumeM3: 5, UnitCostISK: 100, ProfitPerUnit: 30, MaxUnits: 100},
+		}
+
+		plan := OptimizeCargo(candidates, 10000, 10000)
+
+		// Per m³ first: 10 of type 1 spend all capital for 1,000 profit.
+		// Per ISK first: 100 of type 2 spend all capital for 3,000 profit.
+		assert.InDelta(t, 3000.0, plan.ExpectedProfit, 0.0001)
+		assert.LessOrEqual(t, plan.CostISK, 10000.0)
+	})
+
+	t.Run("nothing fits", func(t *testing.T) {
+		candidates := []*CargoCandidate{
+			{Row: &models.HaulingArbitrageRow{TypeID: 1}, VolumeM3: 500, UnitCostISK: 1, ProfitPerUnit: 1, MaxUnits: 1},
+		}
+
+		plan := OptimizeCargo(candidates, 100, 0)
+
+		assert.Len(t, plan.Picks, 0)
+		assert.Equal(t, 0.0, plan.ExpectedProfit)
+	})
+}
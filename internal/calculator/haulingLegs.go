@@ -0,0 +1,155 @@
+package calculator
+
+import (
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+// haulingStopIndex maps stop IDs to their position along the run.
+func haulingStopIndex(stops []*models.HaulingRunStop) map[int64]int {
+	index := make(map[int64]int, len(stops))
+	for i, stop := range stops {
+		index[stop.ID] = i
+	}
+	return index
+}
+
+// haulingItemStops returns the positions an item is loaded and unloaded at.
+// Unassigned items ride from the first stop to the last.
+func haulingItemStops(item *models.HaulingRunItem, index map[int64]int, last int) (buy, sell int) {
+	buy, sell = 0, last
+	if item.BuyStopID != nil {
+		if i, ok := index[*item.BuyStopID]; ok {
+			buy = i
+		}
+	}
+	if item.SellStopID != nil {
+		if i, ok := index[*item.SellStopID]; ok {
+			sell = i
+		}
+	}
+	return buy, sell
+}
+
+// ValidateHaulingItemStops checks that assigned stops belong to the run and
+// that an item is bought at an earlier stop than it is sold.
+func ValidateHaulingItemStops(stops []*models.HaulingRunStop, buyStopID, sellStopID *int64) error {
+	if buyStopID == nil && sellStopID == nil {
+		return nil
+	}
+	if len(stops) < 2 {
+		return errors.New("the run has no stops")
+	}
+	index := haulingStopIndex(stops)
+	buy, sell := 0, len(stops)-1
+	if buyStopID != nil {
+		i, ok := index[*buyStopID]
+		if !ok {
+			return errors.New("buy stop is not on the run")
+		}
+		buy = i
+	}
+	if sellStopID != nil {
+		i, ok := index[*sellStopID]
+		if !ok {
+			return errors.New("sell stop is not on the run")
+		}
+		sell = i
+	}
+	if buy >= sell {
+		return errors.New("buy stop must come before sell stop")
+	}
+	return nil
+}
+
+// haulingRunType keys P&L, which is recorded once per run and type.
+type haulingRunType struct {
+	runID  int64
+	typeID int64
+}
+
+// BuildHaulingLegs splits a multi-stop run into legs between consecutive
+// stops. An item is aboard on every leg from its buy stop to its sell stop;
+// its planned profit counts on the leg that delivers it. P&L is recorded per
+// run and type, so it counts on the latest leg delivering that type.
+// Stops must be in route order. Returns nil for runs with fewer than two stops.
+func BuildHaulingLegs(
+	stops []*models.HaulingRunStop,
+	items []*models.HaulingRunItem,
+	pnl []*models.HaulingRunPnlEntry,
+	capacityM3 *float64,
+) []*models.HaulingRunLeg {
+	if len(stops) < 2 {
+		return nil
+	}
+
+	legs := make([]*models.HaulingRunLeg, len(stops)-1)
+	for i := range legs {
+		legs[i] = &models.HaulingRunLeg{
+			Seq:        i,
+			FromStop:   stops[i],
+			ToStop:     stops[i+1],
+			CapacityM3: capacityM3,
+		}
+	}
+
+	index := haulingStopIndex(stops)
+	last := len(stops) - 1
+	deliveredBy := map[haulingRunType]*models.HaulingRunLeg{}
+	for _, item := range items {
+		buy, sell := haulingItemStops(item, index, last)
+		if buy >= sell {
+			continue
+		}
+		for i := buy; i < sell; i++ {
+			legs[i].ItemsAboard++
+			if item.VolumeM3 != nil {
+				legs[i].LoadM3 += *item.VolumeM3 * float64(item.QuantityPlanned)
+			}
+		}
+
+		delivery := legs[sell-1]
+		delivery.ItemsDelivered++
+		if item.BuyPriceISK != nil && item.SellPriceISK != nil {
+			delivery.PlannedProfitISK += (*item.SellPriceISK - *item.BuyPriceISK) * float64(item.QuantityPlanned)
+		}
+		key := haulingRunType{runID: item.RunID, typeID: item.TypeID}
+		if current, ok := deliveredBy[key]; !ok || delivery.Seq > current.Seq {
+			deliveredBy[key] = delivery
+		}
+	}
+
+	for _, entry := range pnl {
+		leg, ok := deliveredBy[haulingRunType{runID: entry.RunID, typeID: entry.TypeID}]
+		if !ok {
+			leg = legs[len(legs)-1]
+		}
+		if entry.TotalRevenueISK != nil {
+			leg.RevenueISK += *entry.TotalRevenueISK
+		}
+		if entry.TotalCostISK != nil {
+			leg.CostISK += *entry.TotalCostISK
+		}
+		if entry.NetProfitISK != nil {
+			leg.NetProfitISK += *entry.NetProfitISK
+		}
+	}
+
+	if capacityM3 != nil {
+		for _, leg := range legs {
+			leg.OverCapacity = leg.LoadM3 > *capacityM3
+		}
+	}
+	return legs
+}
+
+// FirstOverloadedLeg returns the first leg carrying more than capacityM3,
+// or nil when every leg fits or the run has no capacity set.
+func FirstOverloadedLeg(legs []*models.HaulingRunLeg) *models.HaulingRunLeg {
+	for _, leg := range legs {
+		if leg.OverCapacity {
+			return leg
+		}
+	}
+	return nil
+}
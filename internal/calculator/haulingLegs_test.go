@@ -0,0 +1,135 @@
+package calculator
+
+import (
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func legStops() []*models.HaulingRunStop {
+	return []*models.HaulingRunStop{
+		{ID: 11, Seq: 0, RegionID: 10000002}, // Jita
+		{ID: 12, Seq: 1, RegionID: 10000043}, // Amarr
+		{ID: 13, Seq: 2, RegionID: 10000032}, // Dodixie
+	}
+}
+
+func legItem(typeID int64, qty int64, volume, buy, sell float64, buyStop, sellStop *int64) *models.HaulingRunItem {
+	return &models.HaulingRunItem{
+		TypeID:          typeID,
+		QuantityPlanned: qty,
+		VolumeM3:        &volume,
+		BuyPriceISK:     &buy,
+		SellPriceISK:    &sell,
+		BuyStopID:       buyStop,
+		SellStopID:      sellStop,
+	}
+}
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func TestBuildHaulingLegs(t *testing.T) {
+	t.Run("cargo aboard and delivery per leg", func(t *testing.T) {
+		capacity := 1500.0
+		items := []*models.HaulingRunItem{
+			// Jita → Amarr
+			legItem(34, 100, 10, 5, 7, int64Ptr(11), int64Ptr(12)),
+			// Amarr → Dodixie
+			legItem(35, 50, 20, 100, 150, int64Ptr(12), int64Ptr(13)),
+			// Unassigned: Jita → Dodixie
+			legItem(36, 10, 5, 1000, 1100, nil, nil),
+		}
+
+		legs := BuildHaulingLegs(legStops(), items, nil, &capacity)
+
+		assert.Len(t, legs, 2)
+		assert.Equal(t, int64(11), legs[0].FromStop.ID)
+		assert.Equal(t, int64(12), legs[0].ToStop.ID)
+		// 100*10 + 10*5
+		assert.InDelta(t, 1050.0, legs[0].LoadM3, 0.0001)
+		assert.Equal(t, 2, legs[0].ItemsAboard)
+		assert.Equal(t, 1, legs[0].ItemsDelivered)
+		assert.InDelta(t, 200.0, legs[0].PlannedProfitISK, 0.0001)
+		assert.False(t, legs[0].OverCapacity)
+
+		// 50*20 + 10*5
+		assert.InDelta(t, 1050.0, legs[1].LoadM3, 0.0001)
+		assert.Equal(t, 2, legs[1].ItemsDelivered)
+		// 50*50 + 10*100
+		assert.InDelta(t, 3500.0, legs[1].PlannedProfitISK, 0.0001)
+		assert.Nil(t, FirstOverloadedLeg(legs))
+	})
+
+	t.Run("over capacity on one leg", func(t *testing.T) {
+		capacity := 1000.0
+		items := []*models.HaulingRunItem{
+			legItem(34, 100, 8, 5, 7, nil, int64Ptr(13)),
+			legItem(35, 10, 30, 100, 150, int64Ptr(12), nil),
+		}
+
+		legs := BuildHaulingLegs(legStops(), items, nil, &capacity)
+
+		assert.False(t, legs[0].OverCapacity)
+		assert.True(t, legs[1].OverCapacity)
+		assert.Equal(t, 1, FirstOverloadedLeg(legs).Seq)
+	})
+
+	t.Run("recorded P&L counts on the delivering leg", func(t *testing.T) {
+		items := []*models.HaulingRunItem{
+			legItem(34, 100, 10, 5, 7, nil, int64Ptr(12)),
+			legItem(35, 50, 20, 100, 150, nil, nil),
+		}
+		rev1, cost1, net1 := 700.0, 500.0, 200.0
+		rev2, cost2, net2 := 7000.0, 5000.0, 2000.0
+		pnl := []*models.HaulingRunPnlEntry{
+			{TypeID: 34, TotalRevenueISK: &rev1, TotalCostISK: &cost1, NetProfitISK: &net1},
+			{TypeID: 35, TotalRevenueISK: &rev2, TotalCostISK: &cost2, NetProfitISK: &net2},
+		}
+
+		legs := BuildHaulingLegs(legStops(), items, pnl, nil)
+
+		assert.Equal(t, 700.0, legs[0].RevenueISK)
+		assert.Equal(t, 200.0, legs[0].NetProfitISK)
+		assert.Equal(t, 7000.0, legs[1].RevenueISK)
+		assert.Equal(t, 5000.0, legs[1].CostISK)
+		assert.Equal(t, 2000.0, legs[1].NetProfitISK)
+		assert.Nil(t, legs[1].CapacityM3)
+		assert.False(t, legs[1].OverCapacity)
+	})
+
+	t.Run("type on two items counts P&L once on the latest delivery", func(t *testing.T) {
+		items := []*models.HaulingRunItem{
+			legItem(34, 100, 10, 5, 7, nil, int64Ptr(12)),
+			legItem(34, 50, 10, 5, 8, nil, int64Ptr(13)),
+		}
+		rev, cost, net := 1100.0, 750.0, 350.0
+		pnl := []*models.HaulingRunPnlEntry{
+			{TypeID: 34, TotalRevenueISK: &rev, TotalCostISK: &cost, NetProfitISK: &net},
+		}
+
+		legs := BuildHaulingLegs(legStops(), items, pnl, nil)
+
+		assert.Equal(t, 0.0, legs[0].RevenueISK)
+		assert.Equal(t, 1100.0, legs[1].RevenueISK)
+		assert.Equal(t, 350.0, legs[1].NetProfitISK)
+	})
+
+	t.Run("fewer than two stops", func(t *testing.T) {
+		assert.Nil(t, BuildHaulingLegs(legStops()[:1], nil, nil, nil))
+	})
+}
+
+func TestValidateHaulingItemStops(t *testing.T) {
+	stops := legStops()
+
+	assert.NoError(t, ValidateHaulingItemStops(stops, nil, nil))
+	assert.NoError(t, ValidateHaulingItemStops(stops, int64Ptr(11), int64Ptr(13)))
+	assert.NoError(t, ValidateHaulingItemStops(stops, int64Ptr(12), nil))
+
+	assert.EqualError(t, ValidateHaulingItemStops(stops, int64Ptr(13), nil), "buy stop must come before sell stop")
+	assert.EqualError(t, ValidateHaulingItemStops(stops, int64Ptr(12), int64Ptr(12)), "buy stop must come before sell stop")
+	assert.EqualError(t, ValidateHaulingItemStops(stops, int64Ptr(99), nil), "buy stop is not on the run")
+	assert.EqualError(t, ValidateHaulingItemStops(stops, nil, int64Ptr(99)), "sell stop is not on the run")
+	assert.EqualError(t, ValidateHaulingItemStops(nil, int64Ptr(11), nil), "the run has no stops")
+}
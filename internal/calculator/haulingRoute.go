@@ -0,0 +1,104 @@
+package calculator
+
+import (
+	"math"
+	"sort"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+)
+
+// HighsecMinSecurity is the lowest true security that displays as 0.5.
+const HighsecMinSecurity = 0.45
+
+// HaulingTripOverheadSeconds covers docking, buying and listing on each trip.
+const HaulingTripOverheadSeconds = 600.0
+
+// defaultSecondsPerJump is used for transport methods without their own figure.
+const defaultSecondsPerJump = 90.0
+
+// haulingSecondsPerJump is the time one gate jump takes, align and warp included.
+var haulingSecondsPerJump = map[string]float64{
+	"freighter":       120,
+	"jump_freighter":  110,
+	"dst":             75,
+	"blockade_runner": 45,
+}
+
+// TradeHubSystems maps a region to the system of its main trade hub. A
+// region-wide scan routes from and to the hub.
+var TradeHubSystems = map[int64]int64{
+	10000002: 30000142, // The Forge: Jita
+	10000043: 30002187, // Domain: Amarr
+	10000032: 30002659, // Sinq Laison: Dodixie
+	10000030: 30002510, // Heimatar: Rens
+	10000042: 30002053, // Metropolis: Hek
+}
+
+// HaulingSecondsPerJump returns the time per gate jump for a transport method.
+func HaulingSecondsPerJump(transportMethod string) float64 {
+	if s, ok := haulingSecondsPerJump[transportMethod]; ok {
+		return s
+	}
+	return defaultSecondsPerJump
+}
+
+// HaulingRoute is the gate route between a scan's source and destination.
+type HaulingRoute struct {
+	Jumps       int
+	MinSecurity *float64
+}
+
+// ApplyHaulingRouteMetrics sets route and rate figures on scanner rows.
+//   - ISK/m³/jump: per-unit profit / unit volume / jumps (same system counts as 1 jump)
+//   - ISK/hour: profit of one full cargo over a round trip, only when cargoM3 > 0
+//
+// A cargo load is capped by the units available at the source.
+func ApplyHaulingRouteMetrics(rows []*models.HaulingArbitrageRow, route *HaulingRoute, cargoM3, secondsPerJump float64) {
+	jumps := route.Jumps
+	jumpDivisor := float64(jumps)
+	if jumpDivisor < 1 {
+		jumpDivisor = 1
+	}
+	tripHours := (2*float64(jumps)*secondsPerJump + HaulingTripOverheadSeconds) / 3600
+
+	for _, row := range rows {
+		row.Jumps = &jumps
+		row.MinSecurity = route.MinSecurity
+
+		if row.NetProfitISK == nil || row.VolumeM3 == nil || *row.VolumeM3 <= 0 {
+			continue
+		}
+
+		perM3PerJump := *row.NetProfitISK / *row.VolumeM3 / jumpDivisor
+		row.IskPerM3PerJump = &perM3PerJump
+
+		if cargoM3 <= 0 {
+			continue
+		}
+		units := math.Floor(cargoM3 / *row.VolumeM3)
+		if row.VolumeAvailable != nil && float64(*row.VolumeAvailable) < units {
+			units = float64(*row.VolumeAvailable)
+		}
+		perHour := units * *row.NetProfitISK / tripHours
+		row.IskPerHour = &perHour
+	}
+}
+
+// RankHaulingRows sorts scanner rows best first by "isk_per_hour" or
+// "isk_per_m3_jump". Rows without the figure keep their order at the end.
+func RankHaulingRows(rows []*models.HaulingArbitrageRow, sortBy string) {
+	value := func(row *models.HaulingArbitrageRow) *float64 {
+		if sortBy == "isk_per_hour" {
+			return row.IskPerHour
+		}
+		return row.IskPerM3PerJump
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := value(rows[i]), value(rows[j])
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return *a > *b
+	})
+}
@@ -0,0 +1,64 @@
+package calculator
+
+import (
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyHaulingRouteMetrics(t *testing.T) {
+	t.Run("same system counts as one jump", func(t *testing.T) {
+		net, vol := 100.0, 10.0
+		rows := []*models.HaulingArbitrageRow{{NetProfitISK: &net, VolumeM3: &vol}}
+
+		ApplyHaulingRouteMetrics(rows, &HaulingRoute{Jumps: 0}, 0, 60)
+
+		assert.Equal(t, 0, *rows[0].Jumps)
+		assert.InDelta(t, 10.0, *rows[0].IskPerM3PerJump, 0.001)
+		assert.Nil(t, rows[0].IskPerHour)
+	})
+
+	t.Run("cargo load capped by units available", func(t *testing.T) {
+		net, vol := 1000.0, 1.0
+		avail := int64(50)
+		rows := []*models.HaulingArbitrageRow{{NetProfitISK: &net, VolumeM3: &vol, VolumeAvailable: &avail}}
+
+		// 10 jumps at 60s: 1200s round trip + 600s overhead = 0.5h
+		ApplyHaulingRouteMetrics(rows, &HaulingRoute{Jumps: 10}, 1000, 60)
+
+		assert.InDelta(t, 100000.0, *rows[0].IskPerHour, 0.001)
+		assert.InDelta(t, 100.0, *rows[0].IskPerM3PerJump, 0.001)
+	})
+
+	t.Run("rows without volume get no rates", func(t *testing.T) {
+		net := 100.0
+		rows := []*models.HaulingArbitrageRow{{NetProfitISK: &net}}
+
+		ApplyHaulingRouteMetrics(rows, &HaulingRoute{Jumps: 3}, 1000, 60)
+
+		assert.Equal(t, 3, *rows[0].Jumps)
+		assert.Nil(t, rows[0].IskPerM3PerJump)
+		assert.Nil(t, rows[0].IskPerHour)
+	})
+}
+
+func TestRankHaulingRows(t *testing.T) {
+	low, high := 1.0, 5.0
+	rows := []*models.HaulingArbitrageRow{
+		{TypeID: 1},
+		{TypeID: 2, IskPerM3PerJump: &low, IskPerHour: &high},
+		{TypeID: 3, IskPerM3PerJump: &high, IskPerHour: &low},
+	}
+
+	RankHaulingRows(rows, "isk_per_m3_jump")
+	assert.Equal(t, []int64{3, 2, 1}, []int64{rows[0].TypeID, rows[1].TypeID, rows[2].TypeID})
+
+	RankHaulingRows(rows, "isk_per_hour")
+	assert.Equal(t, []int64{2, 3, 1}, []int64{rows[0].TypeID, rows[1].TypeID, rows[2].TypeID})
+}
+
+func TestHaulingSecondsPerJump(t *testing.T) {
+	assert.Equal(t, 45.0, HaulingSecondsPerJump("blockade_runner"))
+	assert.Equal(t, defaultSecondsPerJump, HaulingSecondsPerJump("unknown"))
+}
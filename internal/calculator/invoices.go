@@ -0,0 +1,61 @@
+package calculator
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// InvoiceReferencePrefix starts every invoice reference code.
+const InvoiceReferencePrefix = "INV-"
+
+// InvoicePaidTolerance absorbs float rounding when a transfer is matched to an
+// invoice's outstanding balance.
+const InvoicePaidTolerance = 0.01
+
+// InvoiceReminderLead is how long before the due date the payer is reminded.
+const InvoiceReminderLead = 24 * time.Hour
+
+// RentalInvoiceAmount prices a job slot rental from its listing terms.
+//   - per_slot_day: price × slots × rental days, part days rounded up
+//   - per_job: price × jobs run on the rented slots
+//   - flat_fee: price
+func RentalInvoiceAmount(priceAmount float64, pricingUnit string, slots int, startsAt, endsAt time.Time, jobsRun int) float64 {
+	switch pricingUnit {
+	case "per_slot_day":
+		days := math.Ceil(endsAt.Sub(startsAt).Hours() / 24)
+		if days < 1 {
+			days = 1
+		}
+		return priceAmount * float64(slots) * days
+	case "per_job":
+		return priceAmount * float64(jobsRun)
+	default:
+		return priceAmount
+	}
+}
+
+// InvoiceStatus derives an open invoice's status from what has been paid so
+// far and its due date. Both amounts come from numeric columns, so they are
+// compared exactly.
+func InvoiceStatus(amount, amountPaid float64, dueAt, now time.Time) string {
+	switch {
+	case amountPaid >= amount:
+		return "paid"
+	case now.After(dueAt):
+		return "overdue"
+	case amountPaid > 0:
+		return "partial"
+	default:
+		return "unpaid"
+	}
+}
+
+// ReasonHasReference reports whether a wallet journal reason carries an
+// invoice reference code, ignoring case and surrounding text.
+func ReasonHasReference(reason, referenceCode string) bool {
+	if reason == "" || referenceCode == "" {
+		return false
+	}
+	return strings.Contains(strings.ToUpper(reason), strings.ToUpper(referenceCode))
+}
@@ -0,0 +1,44 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RentalInvoiceAmount(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// 2 slots for 7 days at 1M per slot-day
+	assert.Equal(t, 14_000_000.0, RentalInvoiceAmount(1_000_000, "per_slot_day", 2, start, start.AddDate(0, 0, 7), 0))
+
+	// A part day counts as a full day
+	assert.Equal(t, 2_000_000.0, RentalInvoiceAmount(1_000_000, "per_slot_day", 1, start, start.Add(25*time.Hour), 0))
+
+	// Per job uses jobs actually run, not slots or duration
+	assert.Equal(t, 1_500_000.0, RentalInvoiceAmount(500_000, "per_job", 4, start, start.AddDate(0, 0, 30), 3))
+
+	assert.Equal(t, 25_000_000.0, RentalInvoiceAmount(25_000_000, "flat_fee", 3, start, start.AddDate(0, 0, 30), 10))
+}
+
+func Test_InvoiceStatus(t *testing.T) {
+	now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	due := now.Add(time.Hour)
+	past := now.Add(-time.Hour)
+
+	assert.Equal(t, "unpaid", InvoiceStatus(100, 0, due, now))
+	assert.Equal(t, "partial", InvoiceStatus(100, 40, due, now))
+	assert.Equal(t, "paid", InvoiceStatus(100, 100, due, now))
+	assert.Equal(t, "partial", InvoiceStatus(100, 99.99, due, now))
+	assert.Equal(t, "paid", InvoiceStatus(100, 120, past, now))
+	assert.Equal(t, "overdue", InvoiceStatus(100, 40, past, now))
+	assert.Equal(t, "overdue", InvoiceStatus(100, 0, past, now))
+}
+
+func Test_ReasonHasReference(t *testing.T) {
+	assert.True(t, ReasonHasReference("rent inv-7KQ2MX thanks", "INV-7KQ2MX"))
+	assert.True(t, ReasonHasReference("INV-7KQ2MX", "INV-7KQ2MX"))
+	assert.False(t, ReasonHasReference("INV-7KQ2M", "INV-7KQ2MX"))
+	assert.False(t, ReasonHasReference("", "INV-7KQ2MX"))
+}
@@ -0,0 +1,232 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+// MetersPerLightYear converts SDE coordinates (meters) to light years.
+const MetersPerLightYear = 9.461e+15
+
+// jumpRangeBonusPerLevel is the Jump Drive Calibration bonus to base range.
+const jumpRangeBonusPerLevel = 0.20
+
+// JumpShipClass holds a jump-capable hull's base range and isotope use before skills.
+type JumpShipClass struct {
+	BaseRangeLY float64
+	FuelPerLY   float64
+}
+
+// DefaultJumpShipClass is used for profiles without a known ship class.
+const DefaultJumpShipClass = "jump_freighter"
+
+// JumpShipClasses maps a ship class to its base jump figures.
+var JumpShipClasses = map[string]JumpShipClass{
+	"jump_freighter": {BaseRangeLY: 5.0, FuelPerLY: 10000},
+	"rorqual":        {BaseRangeLY: 5.0, FuelPerLY: 4000},
+	"black_ops":      {BaseRangeLY: 4.0, FuelPerLY: 700},
+	"capital":        {BaseRangeLY: 3.5, FuelPerLY: 3000},
+	"supercapital":   {BaseRangeLY: 3.0, FuelPerLY: 10000},
+}
+
+func jumpShipClass(class string) JumpShipClass {
+	if c, ok := JumpShipClasses[class]; ok {
+		return c
+	}
+	return JumpShipClasses[DefaultJumpShipClass]
+}
+
+// JumpRangeLY returns the maximum jump distance for a ship class at a Jump
+// Drive Calibration level (0-5). Each level adds 20% to the base range.
+func JumpRangeLY(shipClass string, jdcLevel int) float64 {
+	if jdcLevel < 0 {
+		jdcLevel = 0
+	}
+	if jdcLevel > 5 {
+		jdcLevel = 5
+	}
+	return jumpShipClass(shipClass).BaseRangeLY * (1 + jumpRangeBonusPerLevel*float64(jdcLevel))
+}
+
+// JumpFuelPerLY returns the isotopes per light year for a profile. A fuel
+// figure set on the profile wins over the ship class default.
+func JumpFuelPerLY(profile *models.TransportProfile) float64 {
+	if profile.FuelPerLY != nil {
+		return *profile.FuelPerLY
+	}
+	return jumpShipClass(profile.JumpShipClass).FuelPerLY
+}
+
+// SystemDistanceLY returns the light-year distance between two systems, or
+// false when either is missing coordinates.
+func SystemDistanceLY(a, b *models.SolarSystem) (float64, bool) {
+	if a == nil || b == nil || a.X == nil || a.Y == nil || a.Z == nil || b.X == nil || b.Y == nil || b.Z == nil {
+		return 0, false
+	}
+	dx := *b.X - *a.X
+	dy := *b.Y - *a.Y
+	dz := *b.Z - *a.Z
+	return math.Sqrt(dx*dx+dy*dy+dz*dz) / MetersPerLightYear, true
+}
+
+// ApplyJFWaypointDistances sets each waypoint's DistanceLY to the jump from
+// the previous waypoint (the first is 0) and returns the route total.
+// Waypoints whose systems lack coordinates keep a distance of 0.
+func ApplyJFWaypointDistances(waypoints []*models.JFRouteWaypoint, systems map[int64]*models.SolarSystem) float64 {
+	total := 0.0
+	for i, wp := range waypoints {
+		wp.DistanceLY = 0
+		if i > 0 {
+			if d, ok := SystemDistanceLY(systems[waypoints[i-1].SystemID], systems[wp.SystemID]); ok {
+				wp.DistanceLY = d
+			}
+		}
+		total += wp.DistanceLY
+	}
+	return total
+}
+
+func systemLabel(id int64, systems map[int64]*models.SolarSystem) string {
+	if s, ok := systems[id]; ok && s.Name != "" {
+		return s.Name
+	}
+	return fmt.Sprintf("system %d", id)
+}
+
+// ValidateJFRoute checks that every jump of a route is within rangeLY and
+// lands outside highsec, where cynos cannot be lit. The origin may be anywhere.
+func ValidateJFRoute(waypoints []*models.JFRouteWaypoint, systems map[int64]*models.SolarSystem, rangeLY float64) error {
+	for i := 1; i < len(waypoints); i++ {
+		from, to := waypoints[i-1].SystemID, waypoints[i].SystemID
+		d, ok := SystemDistanceLY(systems[from], systems[to])
+		if !ok {
+			return errors.Errorf("no coordinates for the jump from %s to %s", systemLabel(from, systems), systemLabel(to, systems))
+		}
+		if d > rangeLY {
+			return errors.Errorf("jump %d from %s to %s is %.2f LY, beyond the %.2f LY jump range",
+				i, systemLabel(from, systems), systemLabel(to, systems), d, rangeLY)
+		}
+		if systems[to].Security >= HighsecMinSecurity {
+			return errors.Errorf("jump %d lands in highsec system %s", i, systemLabel(to, systems))
+		}
+	}
+	return nil
+}
+
+// JFPlanParams holds inputs for planning a jump route.
+type JFPlanParams struct {
+	Origin      *models.SolarSystem
+	Destination *models.SolarSystem
+	// Midpoints the planner may use; highsec systems and systems without
+	// coordinates are skipped.
+	Candidates []*models.SolarSystem
+	RangeLY    float64
+	// Optimize is "fuel" (shortest total distance) or "jumps" (fewest jumps,
+	// then shortest distance).
+	Optimize string
+}
+
+type jfPlanCost struct {
+	jumps    int
+	distance float64
+}
+
+func (a jfPlanCost) less(b jfPlanCost, optimize string) bool {
+	if optimize == "fuel" {
+		if a.distance != b.distance {
+			return a.distance < b.distance
+		}
+		return a.jumps < b.jumps
+	}
+	if a.jumps != b.jumps {
+		return a.jumps < b.jumps
+	}
+	return a.distance < b.distance
+}
+
+// PlanJFRoute finds a jump route from origin to destination through the
+// candidate midpoints, with no jump longer than RangeLY. It returns the
+// waypoints, origin and destination included, with leg distances set.
+func PlanJFRoute(params *JFPlanParams) ([]*models.JFRouteWaypoint, error) {
+	if params.Origin == nil || params.Destination == nil {
+		return nil, errors.New("origin and destination are required")
+	}
+	if _, ok := SystemDistanceLY(params.Origin, params.Destination); !ok {
+		return nil, errors.New("origin or destination has no coordinates")
+	}
+	if params.Destination.Security >= HighsecMinSecurity {
+		return nil, errors.Errorf("cannot jump into highsec destination %s", params.Destination.Name)
+	}
+
+	nodes := []*models.SolarSystem{params.Origin}
+	seen := map[int64]bool{params.Origin.ID: true, params.Destination.ID: true}
+	for _, s := range params.Candidates {
+		if seen[s.ID] || s.X == nil || s.Y == nil || s.Z == nil || s.Security >= HighsecMinSecurity {
+			continue
+		}
+		seen[s.ID] = true
+		nodes = append(nodes, s)
+	}
+	nodes = append(nodes, params.Destination)
+	target := len(nodes) - 1
+
+	costs := make([]*jfPlanCost, len(nodes))
+	prev := make([]int, len(nodes))
+	done := make([]bool, len(nodes))
+	costs[0] = &jfPlanCost{}
+
+	for {
+		current := -1
+		for i, c := range costs {
+			if c != nil && !done[i] && (current < 0 || c.less(*costs[current], params.Optimize)) {
+				current = i
+			}
+		}
+		if current < 0 {
+			return nil, errors.Errorf("no route to %s within %.2f LY jumps", params.Destination.Name, params.RangeLY)
+		}
+		if current == target {
+			break
+		}
+		done[current] = true
+
+		for i, node := range nodes {
+			if done[i] {
+				continue
+			}
+			d, ok := SystemDistanceLY(nodes[current], node)
+			if !ok || d > params.RangeLY {
+				continue
+			}
+			next := jfPlanCost{jumps: costs[current].jumps + 1, distance: costs[current].distance + d}
+			if costs[i] == nil || next.less(*costs[i], params.Optimize) {
+				costs[i] = &next
+				prev[i] = current
+			}
+		}
+	}
+
+	path := []int{}
+	for i := target; i != 0; i = prev[i] {
+		path = append([]int{i}, path...)
+	}
+	path = append([]int{0}, path...)
+
+	waypoints := make([]*models.JFRouteWaypoint, len(path))
+	systems := make(map[int64]*models.SolarSystem, len(path))
+	for seq, i := range path {
+		security := nodes[i].Security
+		waypoints[seq] = &models.JFRouteWaypoint{
+			Sequence:   seq,
+			SystemID:   nodes[i].ID,
+			SystemName: nodes[i].Name,
+			Security:   &security,
+		}
+		systems[nodes[i].ID] = nodes[i]
+	}
+	ApplyJFWaypointDistances(waypoints, systems)
+	return waypoints, nil
+}
@@ -0,0 +1,188 @@
+package calculator
+
+import (
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jumpSystem places a system on the x axis, xLY light years from the origin.
+func jumpSystem(id int64, name string, security, xLY float64) *models.SolarSystem {
+	x, y, z := xLY*MetersPerLightYear, 0.0, 0.0
+	return &models.SolarSystem{ID: id, Name: name, Security: security, X: &x, Y: &y, Z: &z}
+}
+
+func TestJumpRangeLY(t *testing.T) {
+	assert.InDelta(t, 10.0, JumpRangeLY("jump_freighter", 5), 0.0001)
+	assert.InDelta(t, 8.0, JumpRangeLY("jump_freighter", 3), 0.0001)
+	assert.InDelta(t, 5.0, JumpRangeLY("jump_freighter", 0), 0.0001)
+	assert.InDelta(t, 8.0, JumpRangeLY("black_ops", 5), 0.0001)
+	assert.InDelta(t, 7.0, JumpRangeLY("capital", 5), 0.0001)
+	// Unknown class falls back to a jump freighter; level is clamped
+	assert.InDelta(t, 10.0, JumpRangeLY("", 9), 0.0001)
+}
+
+func TestJumpFuelPerLY(t *testing.T) {
+	assert.Equal(t, 10000.0, JumpFuelPerLY(&models.TransportProfile{JumpShipClass: "jump_freighter"}))
+	assert.Equal(t, 700.0, JumpFuelPerLY(&models.TransportProfile{JumpShipClass: "black_ops"}))
+
+	custom := 8200.0
+	assert.Equal(t, 8200.0, JumpFuelPerLY(&models.TransportProfile{JumpShipClass: "jump_freighter", FuelPerLY: &custom}))
+}
+
+func TestApplyJFWaypointDistances(t *testing.T) {
+	systems := map[int64]*models.SolarSystem{
+		1: jumpSystem(1, "A", 0.9, 0),
+		2: jumpSystem(2, "B", 0.1, 6),
+		3: jumpSystem(3, "C", -0.2, 14),
+	}
+	waypoints := []*models.JFRouteWaypoint{
+		{Sequence: 0, SystemID: 1},
+		{Sequence: 1, SystemID: 2},
+		{Sequence: 2, SystemID: 3},
+		{Sequence: 3, SystemID: 99},
+	}
+
+	total := ApplyJFWaypointDistances(waypoints, systems)
+
+	assert.Equal(t, 0.0, waypoints[0].DistanceLY)
+	assert.InDelta(t, 6.0, waypoints[1].DistanceLY, 0.0001)
+	assert.InDelta(t, 8.0, waypoints[2].DistanceLY, 0.0001)
+	assert.Equal(t, 0.0, waypoints[3].DistanceLY)
+	assert.InDelta(t, 14.0, total, 0.0001)
+}
+
+func TestValidateJFRoute(t *testing.T) {
+	systems := map[int64]*models.SolarSystem{
+		1: jumpSystem(1, "Jita", 0.9, 0),
+		2: jumpSystem(2, "Ahbazon", 0.4, 6),
+		3: jumpSystem(3, "Amamake", 0.4, 14),
+		4: jumpSystem(4, "Amarr", 1.0, 20),
+		5: {ID: 5, Name: "NoCoords"},
+	}
+	route := func(ids ...int64) []*models.JFRouteWaypoint {
+		wps := []*models.JFRouteWaypoint{}
+		for i, id := range ids {
+			wps = append(wps, &models.JFRouteWaypoint{Sequence: i, SystemID: id})
+		}
+		return wps
+	}
+
+	assert.NoError(t, ValidateJFRoute(route(1, 2, 3), systems, 10))
+	assert.EqualError(t, ValidateJFRoute(route(1, 2, 3), systems, 7),
+		"jump 2 from Ahbazon to Amamake is 8.00 LY, beyond the 7.00 LY jump range")
+	assert.EqualError(t, ValidateJFRoute(route(2, 3, 4), systems, 10),
+		"jump 2 lands in highsec system Amarr")
+	assert.EqualError(t, ValidateJFRoute(route(1, 5), systems, 10),
+		"no coordinates for the jump from Jita to NoCoords")
+}
+
+func TestPlanJFRoute(t *testing.T) {
+	origin := jumpSystem(1, "Origin", 0.9, 0)
+	destination := jumpSystem(9, "Destination", -0.5, 18)
+	candidates := []*models.SolarSystem{
+		jumpSystem(2, "Low4", 0.3, 4),
+		jumpSystem(3, "Low9", 0.2, 9),
+		jumpSystem(4, "Null10", -0.1, 10),
+		jumpSystem(5, "High9", 0.7, 9.5),
+	}
+
+	t.Run("fewest jumps", func(t *testing.T) {
+		waypoints, err := PlanJFRoute(&JFPlanParams{
+			Origin: origin, Destination: destination, Candidates: candidates,
+			RangeLY: 10, Optimize: "jumps",
+		})
+		require.NoError(t, err)
+
+		ids := []int64{}
+		for _, wp := range waypoints {
+			ids = append(ids, wp.SystemID)
+		}
+		// Two jumps through a lowsec or nullsec midpoint
+		assert.Len(t, waypoints, 3)
+		assert.Equal(t, int64(1), ids[0])
+		assert.Equal(t, int64(9), ids[2])
+		assert.NotEqual(t, int64(5), ids[1], "highsec systems are never midpoints")
+		assert.Equal(t, 0, waypoints[0].Sequence)
+		assert.Equal(t, 2, waypoints[2].Sequence)
+		assert.NotNil(t, waypoints[1].Security)
+	})
+
+	t.Run("range limits the midpoints", func(t *testing.T) {
+		waypoints, err := PlanJFRoute(&JFPlanParams{
+			Origin: origin, Destination: destination, Candidates: candidates,
+			RangeLY: 9, Optimize: "jumps",
+		})
+		require.NoError(t, err)
+
+		ids := []int64{}
+		for _, wp := range waypoints {
+			ids = append(ids, wp.SystemID)
+		}
+		assert.Equal(t, []int64{1, 3, 9}, ids)
+		assert.InDelta(t, 9.0, waypoints[1].DistanceLY, 0.0001)
+		assert.InDelta(t, 9.0, waypoints[2].DistanceLY, 0.0001)
+	})
+
+	t.Run("fuel trades extra jumps for distance", func(t *testing.T) {
+		offAxis := jumpSystem(6, "OffAxis", -0.3, 9)
+		y := 4 * MetersPerLightYear
+		offAxis.Y = &y
+		params := &JFPlanParams{
+			Origin: origin, Destination: destination,
+			Candidates: []*models.SolarSystem{
+				offAxis,
+				jumpSystem(7, "Low6", 0.2, 6),
+				jumpSystem(8, "Low12", 0.2, 12),
+			},
+			RangeLY: 10,
+		}
+
+		params.Optimize = "jumps"
+		waypoints, err := PlanJFRoute(params)
+		require.NoError(t, err)
+		assert.Len(t, waypoints, 3)
+		assert.Equal(t, int64(6), waypoints[1].SystemID)
+
+		params.Optimize = "fuel"
+		waypoints, err = PlanJFRoute(params)
+		require.NoError(t, err)
+		ids := []int64{}
+		total := 0.0
+		for _, wp := range waypoints {
+			ids = append(ids, wp.SystemID)
+			total += wp.DistanceLY
+		}
+		assert.Equal(t, []int64{1, 7, 8, 9}, ids)
+		assert.InDelta(t, 18.0, total, 0.0001)
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		_, err := PlanJFRoute(&JFPlanParams{
+			Origin: origin, Destination: destination, Candidates: candidates,
+			RangeLY: 5, Optimize: "jumps",
+		})
+		assert.EqualError(t, err, "no route to Destination within 5.00 LY jumps")
+	})
+
+	t.Run("systems without full coordinates are skipped", func(t *testing.T) {
+		partial := jumpSystem(6, "Partial", -0.2, 9)
+		partial.Z = nil
+		_, err := PlanJFRoute(&JFPlanParams{
+			Origin: origin, Destination: destination,
+			Candidates: []*models.SolarSystem{partial},
+			RangeLY: 10, Optimize: "jumps",
+		})
+		assert.EqualError(t, err, "no route to Destination within 10.00 LY jumps")
+	})
+
+	t.Run("highsec destination", func(t *testing.T) {
+		_, err := PlanJFRoute(&JFPlanParams{
+			Origin: origin, Destination: jumpSystem(9, "Amarr", 1.0, 8),
+			RangeLY: 10, Optimize: "jumps",
+		})
+		assert.EqualError(t, err, "cannot jump into highsec destination Amarr")
+	})
+}
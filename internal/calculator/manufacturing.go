@@ -19,6 +19,8 @@ type ManufacturingParams struct {
 	AdvIndustrySkill int   // 0-5
 	FacilityTax    float64 // percentage
 	SystemID       int64
+	SalesTaxPct    float64 // percentage of output value; 0 when the output is not sold
+	BrokerFeePct   float64 // percentage of output value for the sell order
 }
 
 // ManufacturingData holds data fetched from the database for calculations
@@ -170,9 +172,13 @@ func CalculateManufacturingJob(params *ManufacturingParams, data *ManufacturingD
 	outputPrice := GetPrice(data.Blueprint.ProductTypeID, "sell", data.JitaPrices)
 	totalOutputValue := outputPrice * float64(totalProducts)
 
+	// Fees for selling the output through a sell order
+	salesTax := totalOutputValue * params.SalesTaxPct / 100.0
+	brokerFee := totalOutputValue * params.BrokerFeePct / 100.0
+
 	// Total cost and profit
 	totalCost := totalInputCost + totalJobCost
-	profit := totalOutputValue - totalCost
+	profit := totalOutputValue - totalCost - salesTax - brokerFee
 
 	var margin float64
 	if totalOutputValue > 0 {
@@ -193,6 +199,8 @@ func CalculateManufacturingJob(params *ManufacturingParams, data *ManufacturingD
 		JobCost:         math.Round(totalJobCost*100) / 100,
 		TotalCost:       math.Round(totalCost*100) / 100,
 		OutputValue:     math.Round(totalOutputValue*100) / 100,
+		SalesTax:        math.Round(salesTax*100) / 100,
+		BrokerFee:       math.Round(brokerFee*100) / 100,
 		Profit:          math.Round(profit*100) / 100,
 		Margin:          math.Round(margin*100) / 100,
 		Materials:       materials,
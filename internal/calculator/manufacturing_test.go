@@ -227,6 +227,40 @@ func TestCalculateManufacturingJob(t *testing.T) {
 	assert.Equal(t, result.SecsPerRun*10, result.TotalDuration)
 }
 
+func TestCalculateManufacturingJob_SellFees(t *testing.T) {
+	productPrice := 1000000.0
+	params := &ManufacturingParams{
+		Runs:         2,
+		Structure:    "station",
+		Rig:          "none",
+		Security:     "high",
+		SalesTaxPct:  3.375,
+		BrokerFeePct: 1.5,
+	}
+	data := &ManufacturingData{
+		Blueprint: &repositories.ManufacturingBlueprintRow{
+			BlueprintTypeID: 787,
+			ProductTypeID:   786,
+			ProductName:     "Test Ship",
+			ProductQuantity: 1,
+			Time:            3600,
+		},
+		Materials:      []*repositories.ManufacturingMaterialRow{},
+		AdjustedPrices: map[int64]float64{},
+		JitaPrices: map[int64]*models.MarketPrice{
+			786: {TypeID: 786, SellPrice: &productPrice},
+		},
+	}
+
+	result := CalculateManufacturingJob(params, data)
+
+	// Output 2,000,000: tax 67,500, broker 30,000, no inputs
+	assert.InDelta(t, 67500.0, result.SalesTax, 0.01)
+	assert.InDelta(t, 30000.0, result.BrokerFee, 0.01)
+	assert.InDelta(t, 1902500.0, result.Profit, 0.01)
+	assert.InDelta(t, 95.13, result.Margin, 0.001)
+}
+
 func TestCalculateManufacturingJob_NoJitaPrices(t *testing.T) {
 	params := &ManufacturingParams{
 		BlueprintME:      0,
@@ -0,0 +1,61 @@
+package calculator
+
+import (
+	"math"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+)
+
+// StockpileConsumptionWindowDays is how far back snapshots and industry jobs count towards a
+// marker's burn rate.
+const StockpileConsumptionWindowDays = 30
+
+// DefaultStockpileCoverDays is the cover used for markers without their own cover days.
+const DefaultStockpileCoverDays = 14
+
+// MinConsumptionHistoryDays is how many days of snapshots a marker needs before its asset history
+// is trusted. Markers with less history and no production usage get no suggestion.
+const MinConsumptionHistoryDays = 3
+
+// CalculateStockpileConsumption derives the daily burn rates of a marker from its consumed
+// quantities, projects when its held stock runs out and suggests a desired quantity covering
+// coverDays days (the marker's own cover days take precedence), clamped to its min/max bounds.
+func CalculateStockpileConsumption(c *models.StockpileConsumption, windowDays, coverDays int, now time.Time) {
+	c.AssetBurnRate = 0
+	if c.HistoryDays >= MinConsumptionHistoryDays {
+		c.AssetBurnRate = float64(c.AssetConsumed) / float64(c.HistoryDays)
+	}
+	c.ProductionBurnRate = 0
+	if windowDays > 0 {
+		c.ProductionBurnRate = float64(c.ProductionConsumed) / float64(windowDays)
+	}
+	// Snapshots already include materials pulled by jobs, so the rates overlap; take the larger.
+	c.BurnRate = max(c.AssetBurnRate, c.ProductionBurnRate)
+
+	c.DaysRemaining = nil
+	c.ProjectedRunOutAt = nil
+	if c.BurnRate > 0 {
+		days := float64(c.HeldQuantity) / c.BurnRate
+		runOut := now.Add(time.Duration(days * float64(24*time.Hour)))
+		c.DaysRemaining = &days
+		c.ProjectedRunOutAt = &runOut
+	}
+
+	c.SuggestedDesiredQuantity = nil
+	if c.HistoryDays < MinConsumptionHistoryDays && c.ProductionConsumed == 0 {
+		return
+	}
+	if c.CoverDays != nil {
+		coverDays = *c.CoverDays
+	}
+
+	suggested := int64(math.Ceil(c.BurnRate * float64(coverDays)))
+	if c.MinDesiredQuantity != nil {
+		suggested = max(suggested, *c.MinDesiredQuantity)
+	}
+	if c.MaxDesiredQuantity != nil {
+		suggested = min(suggested, *c.MaxDesiredQuantity)
+	}
+	c.SuggestedDesiredQuantity = &suggested
+}
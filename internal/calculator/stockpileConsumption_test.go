@@ -0,0 +1,63 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateStockpileConsumption_UsesLargerRate(t *testing.T) {
+	now := time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)
+	c := &models.StockpileConsumption{
+		HeldQuantity:       5000,
+		HistoryDays:        10,
+		AssetConsumed:      10000, // 1,000/day
+		ProductionConsumed: 15000, // 500/day over 30 days
+	}
+
+	CalculateStockpileConsumption(c, 30, 14, now)
+
+	assert.Equal(t, 1000.0, c.AssetBurnRate)
+	assert.Equal(t, 500.0, c.ProductionBurnRate)
+	assert.Equal(t, 1000.0, c.BurnRate)
+	assert.Equal(t, 5.0, *c.DaysRemaining)
+	assert.Equal(t, now.Add(5*24*time.Hour), *c.ProjectedRunOutAt)
+	assert.Equal(t, int64(14000), *c.SuggestedDesiredQuantity)
+}
+
+func TestCalculateStockpileConsumption_ClampsToMarkerBounds(t *testing.T) {
+	coverDays := 7
+	minQuantity := int64(2000)
+	maxQuantity := int64(5000)
+	c := &models.StockpileConsumption{
+		HistoryDays:        5,
+		AssetConsumed:      5000,
+		CoverDays:          &coverDays,
+		MinDesiredQuantity: &minQuantity,
+		MaxDesiredQuantity: &maxQuantity,
+	}
+
+	CalculateStockpileConsumption(c, 30, 14, time.Now())
+	assert.Equal(t, int64(5000), *c.SuggestedDesiredQuantity) // 1,000/day * 7 capped at 5,000
+
+	c.AssetConsumed = 50
+	CalculateStockpileConsumption(c, 30, 14, time.Now())
+	assert.Equal(t, int64(2000), *c.SuggestedDesiredQuantity) // 70 raised to the minimum
+}
+
+func TestCalculateStockpileConsumption_NoSuggestionWithoutData(t *testing.T) {
+	c := &models.StockpileConsumption{
+		HeldQuantity:  100,
+		HistoryDays:   1,
+		AssetConsumed: 50,
+	}
+
+	CalculateStockpileConsumption(c, 30, 14, time.Now())
+
+	assert.Equal(t, 0.0, c.BurnRate)
+	assert.Nil(t, c.DaysRemaining)
+	assert.Nil(t, c.ProjectedRunOutAt)
+	assert.Nil(t, c.SuggestedDesiredQuantity)
+}
@@ -0,0 +1,188 @@
+package calculator
+
+import (
+	"sort"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+)
+
+type rebalanceLaneKey struct {
+	ownerType   string
+	ownerID     int64
+	origin      int64
+	destination int64
+}
+
+// MatchStockpileSurplus pairs stockpile deficits with surplus of the same owner and type at other
+// stations and groups the moves into one proposal per origin/destination station pair.
+//
+// A position is short by desired - held - in transit and has surplus of held - desired. Sources in
+// the same solar system are preferred, then the same region, then the largest surplus, so the
+// cheapest hauls are proposed first. Prices and transport options are not set.
+func MatchStockpileSurplus(positions []*models.StockpilePosition) []*models.StockpileRebalanceProposal {
+	type ownerTypeKey struct {
+		ownerType string
+		ownerID   int64
+		typeID    int64
+	}
+
+	byType := map[ownerTypeKey][]*models.StockpilePosition{}
+	keys := []ownerTypeKey{}
+	for _, position := range positions {
+		key := ownerTypeKey{position.OwnerType, position.OwnerID, position.TypeID}
+		if _, ok := byType[key]; !ok {
+			keys = append(keys, key)
+		}
+		byType[key] = append(byType[key], position)
+	}
+
+	proposals := []*models.StockpileRebalanceProposal{}
+	lanes := map[rebalanceLaneKey]*models.StockpileRebalanceProposal{}
+
+	for _, key := range keys {
+		group := byType[key]
+
+		available := map[*models.StockpilePosition]int64{}
+		sources := []*models.StockpilePosition{}
+		deficits := []*models.StockpilePosition{}
+		for _, position := range group {
+			if surplus := position.HeldQuantity - position.DesiredQuantity; surplus > 0 {
+				available[position] = surplus
+				sources = append(sources, position)
+			}
+			if stockpileShortfall(position) > 0 {
+				deficits = append(deficits, position)
+			}
+		}
+		if len(sources) == 0 || len(deficits) == 0 {
+			continue
+		}
+
+		sort.SliceStable(deficits, func(i, j int) bool {
+			return stockpileShortfall(deficits[i]) > stockpileShortfall(deficits[j])
+		})
+
+		for _, deficit := range deficits {
+			need := stockpileShortfall(deficit)
+
+			candidates := append([]*models.StockpilePosition{}, sources...)
+			sort.SliceStable(candidates, func(i, j int) bool {
+				ri, rj := sourceRank(candidates[i], deficit), sourceRank(candidates[j], deficit)
+				if ri != rj {
+					return ri < rj
+				}
+				if available[candidates[i]] != available[candidates[j]] {
+					return available[candidates[i]] > available[candidates[j]]
+				}
+				return candidates[i].StationID < candidates[j].StationID
+			})
+
+			for _, source := range candidates {
+				if need <= 0 {
+					break
+				}
+				if source.StationID == deficit.StationID || available[source] <= 0 {
+					continue
+				}
+
+				quantity := min(need, available[source])
+				available[source] -= quantity
+				need -= quantity
+
+				lane := getRebalanceLane(lanes, &proposals, source, deficit)
+				lane.Items = append(lane.Items, &models.StockpileRebalanceItem{
+					TypeID:   deficit.TypeID,
+					TypeName: deficit.TypeName,
+					Quantity: quantity,
+					VolumeM3: float64(quantity) * deficit.UnitVolume,
+				})
+				lane.TotalVolumeM3 += float64(quantity) * deficit.UnitVolume
+			}
+		}
+	}
+
+	return proposals
+}
+
+// PriceStockpileRebalance values the items of a proposal at Jita sell and sets the cost of buying
+// them at the destination instead, using destination region sell prices and falling back to Jita.
+func PriceStockpileRebalance(proposal *models.StockpileRebalanceProposal, jitaPrices, localPrices map[int64]*models.MarketPrice) {
+	proposal.TotalValue = 0
+	proposal.LocalBuyCost = 0
+	for _, item := range proposal.Items {
+		jitaSell := PriceForBasis(jitaPrices[item.TypeID], "sell")
+		localSell := PriceForBasis(localPrices[item.TypeID], "sell")
+		if localSell == 0 {
+			localSell = jitaSell
+		}
+
+		item.EstimatedValue = jitaSell * float64(item.Quantity)
+		item.LocalBuyCost = localSell * float64(item.Quantity)
+		proposal.TotalValue += item.EstimatedValue
+		proposal.LocalBuyCost += item.LocalBuyCost
+	}
+}
+
+// ChooseRebalanceTransport picks the cheapest option of a proposal and compares it with buying the
+// items locally. Proposals without any usable option are left to be bought locally.
+func ChooseRebalanceTransport(proposal *models.StockpileRebalanceProposal) {
+	proposal.BestOption = nil
+	for _, option := range proposal.Options {
+		if proposal.BestOption == nil || option.Cost < proposal.BestOption.Cost {
+			proposal.BestOption = option
+		}
+	}
+
+	if proposal.BestOption == nil {
+		proposal.Savings = 0
+		proposal.Recommendation = "buy_locally"
+		return
+	}
+
+	proposal.Savings = proposal.LocalBuyCost - proposal.BestOption.Cost
+	if proposal.Savings > 0 {
+		proposal.Recommendation = "transport"
+	} else {
+		proposal.Recommendation = "buy_locally"
+	}
+}
+
+func stockpileShortfall(position *models.StockpilePosition) int64 {
+	return position.DesiredQuantity - position.HeldQuantity - position.InTransitQuantity
+}
+
+func sourceRank(source, deficit *models.StockpilePosition) int {
+	switch {
+	case source.SolarSystemID == deficit.SolarSystemID:
+		return 0
+	case source.RegionID == deficit.RegionID:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func getRebalanceLane(lanes map[rebalanceLaneKey]*models.StockpileRebalanceProposal, proposals *[]*models.StockpileRebalanceProposal, source, deficit *models.StockpilePosition) *models.StockpileRebalanceProposal {
+	key := rebalanceLaneKey{source.OwnerType, source.OwnerID, source.StationID, deficit.StationID}
+	if lane, ok := lanes[key]; ok {
+		return lane
+	}
+
+	lane := &models.StockpileRebalanceProposal{
+		OwnerType:              source.OwnerType,
+		OwnerID:                source.OwnerID,
+		OwnerName:              source.OwnerName,
+		OriginStationID:        source.StationID,
+		OriginStationName:      source.StationName,
+		OriginSystemID:         source.SolarSystemID,
+		DestinationStationID:   deficit.StationID,
+		DestinationStationName: deficit.StationName,
+		DestinationSystemID:    deficit.SolarSystemID,
+		DestinationRegionID:    deficit.RegionID,
+		Items:                  []*models.StockpileRebalanceItem{},
+		Options:                []*models.StockpileRebalanceOption{},
+	}
+	lanes[key] = lane
+	*proposals = append(*proposals, lane)
+	return lane
+}
@@ -0,0 +1,114 @@
+package calculator
+
+import (
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func position(stationID, systemID, regionID int64, held, desired int64) *models.StockpilePosition {
+	return &models.StockpilePosition{
+		OwnerType:       "corporation",
+		OwnerID:         2001,
+		StationID:       stationID,
+		StationName:     "Station",
+		SolarSystemID:   systemID,
+		RegionID:        regionID,
+		TypeID:          34,
+		TypeName:        "Tritanium",
+		UnitVolume:      0.01,
+		HeldQuantity:    held,
+		DesiredQuantity: desired,
+	}
+}
+
+func TestMatchStockpileSurplus_PrefersSameSystemSource(t *testing.T) {
+	positions := []*models.StockpilePosition{
+		position(1, 100, 10, 5000, 0), // surplus 5000, other region
+		position(2, 200, 20, 3000, 0), // surplus 3000, same system as deficit
+		position(3, 200, 20, 0, 4000), // short 4000
+	}
+
+	proposals := MatchStockpileSurplus(positions)
+
+	assert.Len(t, proposals, 2)
+	assert.Equal(t, int64(2), proposals[0].OriginStationID)
+	assert.Equal(t, int64(3000), proposals[0].Items[0].Quantity)
+	assert.Equal(t, int64(1), proposals[1].OriginStationID)
+	assert.Equal(t, int64(1000), proposals[1].Items[0].Quantity)
+	assert.InDelta(t, 10.0, proposals[1].TotalVolumeM3, 0.0001)
+}
+
+func TestMatchStockpileSurplus_CountsInTransitAndKeepsMarkedStock(t *testing.T) {
+	source := position(1, 100, 10, 5000, 4500) // only 500 above its own marker
+	deficit := position(2, 200, 10, 0, 1000)
+	deficit.InTransitQuantity = 800
+
+	proposals := MatchStockpileSurplus([]*models.StockpilePosition{source, deficit})
+
+	assert.Len(t, proposals, 1)
+	assert.Equal(t, int64(200), proposals[0].Items[0].Quantity)
+}
+
+func TestMatchStockpileSurplus_DoesNotMoveBetweenOwners(t *testing.T) {
+	source := position(1, 100, 10, 5000, 0)
+	source.OwnerType = "character"
+	source.OwnerID = 1337
+	deficit := position(2, 200, 10, 0, 1000)
+
+	proposals := MatchStockpileSurplus([]*models.StockpilePosition{source, deficit})
+
+	assert.Empty(t, proposals)
+}
+
+func TestPriceStockpileRebalance_FallsBackToJita(t *testing.T) {
+	jitaSell := 5.0
+	localSell := 7.0
+	proposal := &models.StockpileRebalanceProposal{
+		Items: []*models.StockpileRebalanceItem{
+			{TypeID: 34, Quantity: 100},
+			{TypeID: 35, Quantity: 10},
+		},
+	}
+
+	PriceStockpileRebalance(proposal,
+		map[int64]*models.MarketPrice{
+			34: {TypeID: 34, SellPrice: &jitaSell},
+			35: {TypeID: 35, SellPrice: &jitaSell},
+		},
+		map[int64]*models.MarketPrice{
+			34: {TypeID: 34, SellPrice: &localSell},
+		},
+	)
+
+	assert.Equal(t, 550.0, proposal.TotalValue)
+	assert.Equal(t, 700.0, proposal.Items[0].LocalBuyCost)
+	assert.Equal(t, 50.0, proposal.Items[1].LocalBuyCost)
+	assert.Equal(t, 750.0, proposal.LocalBuyCost)
+}
+
+func TestChooseRebalanceTransport(t *testing.T) {
+	proposal := &models.StockpileRebalanceProposal{
+		LocalBuyCost: 1000,
+		Options: []*models.StockpileRebalanceOption{
+			{ProfileID: 1, Cost: 800},
+			{ProfileID: 2, Cost: 300},
+		},
+	}
+
+	ChooseRebalanceTransport(proposal)
+
+	assert.Equal(t, int64(2), proposal.BestOption.ProfileID)
+	assert.Equal(t, 700.0, proposal.Savings)
+	assert.Equal(t, "transport", proposal.Recommendation)
+
+	proposal.LocalBuyCost = 200
+	ChooseRebalanceTransport(proposal)
+	assert.Equal(t, "buy_locally", proposal.Recommendation)
+
+	proposal.Options = nil
+	ChooseRebalanceTransport(proposal)
+	assert.Nil(t, proposal.BestOption)
+	assert.Equal(t, "buy_locally", proposal.Recommendation)
+}
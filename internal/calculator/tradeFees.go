@@ -0,0 +1,178 @@
+package calculator
+
+import (
+	"math"
+	"sort"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+)
+
+// Skill ID constants for market fee skills.
+const (
+	SkillAccounting      int64 = 16622 // 11% sales tax reduction per level
+	SkillBrokerRelations int64 = 3446  // 0.3 point NPC broker fee reduction per level
+)
+
+const (
+	// BaseSalesTaxPct is the sales tax with no Accounting skill.
+	BaseSalesTaxPct = 7.5
+	// BaseBrokerFeePct is the NPC station broker fee with no skills or standings.
+	BaseBrokerFeePct = 3.0
+	// MinBrokerFeePct is the floor of the NPC station broker fee.
+	MinBrokerFeePct = 1.0
+)
+
+// TradeStandings are a character's standings toward the NPC station owner.
+// Negative standings raise the broker fee.
+type TradeStandings struct {
+	Faction     float64
+	Corporation float64
+}
+
+// SalesTaxPct returns the sales tax for an Accounting level.
+// sales_tax = 7.5% × (1 - 0.11 × accounting)
+func SalesTaxPct(accounting int) float64 {
+	return roundPct(BaseSalesTaxPct * (1 - 0.11*float64(accounting)))
+}
+
+// NpcBrokerFeePct returns the broker fee at an NPC station, floored at 1%.
+// broker_fee = 3% - 0.3% × broker_relations - 0.03% × faction - 0.02% × corporation
+func NpcBrokerFeePct(brokerRelations int, standings *TradeStandings) float64 {
+	fee := BaseBrokerFeePct - 0.3*float64(brokerRelations)
+	if standings != nil {
+		fee -= 0.03*standings.Faction + 0.02*standings.Corporation
+	}
+	return roundPct(math.Max(fee, MinBrokerFeePct))
+}
+
+// ComputeTradeFees returns the fee rates for a character's skill levels. When
+// structureBrokerFeePct is set the order is placed in a player structure, whose
+// owner-set rate replaces the NPC broker fee; skills and standings do not apply.
+func ComputeTradeFees(skills map[int64]int, standings *TradeStandings, structureBrokerFeePct *float64) *models.TradeFees {
+	fees := &models.TradeFees{
+		AccountingLevel:      skills[SkillAccounting],
+		BrokerRelationsLevel: skills[SkillBrokerRelations],
+	}
+	fees.SalesTaxPct = SalesTaxPct(fees.AccountingLevel)
+	if structureBrokerFeePct != nil {
+		fees.BrokerFeePct = *structureBrokerFeePct
+	} else {
+		fees.BrokerFeePct = NpcBrokerFeePct(fees.BrokerRelationsLevel, standings)
+	}
+	return fees
+}
+
+// BuildCharacterTradeFees computes fees for every named character, sorted by
+// lowest total fee first and then by name. Characters without synced skills
+// pay the base rates.
+func BuildCharacterTradeFees(
+	characterNames map[int64]string,
+	skillsByCharacter map[int64]map[int64]int,
+	standings *TradeStandings,
+	structureBrokerFeePct *float64,
+) []*models.TradeFees {
+	result := []*models.TradeFees{}
+	for characterID, name := range characterNames {
+		fees := ComputeTradeFees(skillsByCharacter[characterID], standings, structureBrokerFeePct)
+		fees.CharacterID = characterID
+		fees.CharacterName = name
+		result = append(result, fees)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		ti := result[i].SalesTaxPct + result[i].BrokerFeePct
+		tj := result[j].SalesTaxPct + result[j].BrokerFeePct
+		if ti != tj {
+			return ti < tj
+		}
+		return result[i].CharacterName < result[j].CharacterName
+	})
+	return result
+}
+
+// BestTradeFees returns the lowest fees among a user's characters, or the base
+// rates when no character has synced skills.
+func BestTradeFees(skillsByCharacter map[int64]map[int64]int, standings *TradeStandings, structureBrokerFeePct *float64) *models.TradeFees {
+	best := ComputeTradeFees(map[int64]int{}, standings, structureBrokerFeePct)
+	bestTotal := best.SalesTaxPct + best.BrokerFeePct
+	for characterID, skills := range skillsByCharacter {
+		fees := ComputeTradeFees(skills, standings, structureBrokerFeePct)
+		fees.CharacterID = characterID
+		total := fees.SalesTaxPct + fees.BrokerFeePct
+		// Ties go to the lowest character ID so the choice is stable
+		if total < bestTotal || (total == bestTotal && (best.CharacterID == 0 || characterID < best.CharacterID)) {
+			best, bestTotal = fees, total
+		}
+	}
+	return best
+}
+
+// StructureBrokerFeePct returns the configured broker rate of a user's
+// trading structure, or nil when the location is not one or has no rate.
+func StructureBrokerFeePct(structures []*models.UserTradingStructure, locationID int64) *float64 {
+	for _, s := range structures {
+		if s.StructureID == locationID {
+			return s.BrokerFeePct
+		}
+	}
+	return nil
+}
+
+// TradeSkillsByCharacter groups the market fee skills of a user's characters:
+// characterID → skillID → active level.
+func TradeSkillsByCharacter(skills []*models.CharacterSkill) map[int64]map[int64]int {
+	byCharacter := map[int64]map[int64]int{}
+	for _, skill := range skills {
+		if skill.SkillID != SkillAccounting && skill.SkillID != SkillBrokerRelations {
+			continue
+		}
+		if _, ok := byCharacter[skill.CharacterID]; !ok {
+			byCharacter[skill.CharacterID] = map[int64]int{}
+		}
+		byCharacter[skill.CharacterID][skill.SkillID] = skill.ActiveLevel
+	}
+	return byCharacter
+}
+
+// SellOrderFees returns the sales tax and broker fee on gross sales made
+// through a sell order.
+func SellOrderFees(gross float64, fees *models.TradeFees) (salesTax, brokerFee float64) {
+	return gross * fees.SalesTaxPct / 100, gross * fees.BrokerFeePct / 100
+}
+
+// HaulingFeesFromTradeFees turns character fees into a hauling fees profile
+// that buys from sell orders and sells through a sell order.
+func HaulingFeesFromTradeFees(fees *models.TradeFees) *models.HaulingFeesProfile {
+	return &models.HaulingFeesProfile{
+		SalesTaxPct:      fees.SalesTaxPct,
+		SellBrokerFeePct: fees.BrokerFeePct,
+	}
+}
+
+// ApplyHaulingRowFees reduces each scanner row's per-unit net profit by the
+// sell-side fees: net = sell × (1 - (tax + broker)%) - buy.
+func ApplyHaulingRowFees(rows []*models.HaulingArbitrageRow, fees *models.HaulingFeesProfile) {
+	for _, row := range rows {
+		if row.BuyPrice == nil || row.SellPrice == nil {
+			continue
+		}
+		net := *row.SellPrice*(1-(fees.SalesTaxPct+fees.SellBrokerFeePct)/100) -
+			*row.BuyPrice*(1+fees.BuyBrokerFeePct/100)
+		row.NetProfitISK = &net
+	}
+}
+
+// ApplyPnlSummaryFees charges sales tax and broker fees on a run's revenue and
+// recomputes net profit and margin.
+func ApplyPnlSummaryFees(summary *models.HaulingRunPnlSummary, fees *models.TradeFees) {
+	summary.SalesTaxISK, summary.BrokerFeesISK = SellOrderFees(summary.TotalRevenueISK, fees)
+	summary.NetProfitISK = summary.TotalRevenueISK - summary.TotalCostISK - summary.SalesTaxISK - summary.BrokerFeesISK
+	summary.MarginPct = 0
+	if summary.TotalRevenueISK > 0 {
+		summary.MarginPct = summary.NetProfitISK / summary.TotalRevenueISK * 100
+	}
+}
+
+func roundPct(pct float64) float64 {
+	return math.Round(pct*10000) / 10000
+}
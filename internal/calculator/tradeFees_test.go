@@ -0,0 +1,156 @@
+package calculator
+
+import (
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSalesTaxPct(t *testing.T) {
+	assert.InDelta(t, 7.5, SalesTaxPct(0), 0.0001)
+	assert.InDelta(t, 6.675, SalesTaxPct(1), 0.0001)
+	assert.InDelta(t, 3.375, SalesTaxPct(5), 0.0001)
+}
+
+func TestNpcBrokerFeePct(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     int
+		standings *TradeStandings
+		expected  float64
+	}{
+		{"untrained", 0, nil, 3.0},
+		{"broker relations 5", 5, nil, 1.5},
+		{"standings lower the fee", 5, &TradeStandings{Faction: 5, Corporation: 5}, 1.25},
+		{"negative standings raise the fee", 0, &TradeStandings{Faction: -10}, 3.3},
+		{"floored at 1%", 5, &TradeStandings{Faction: 10, Corporation: 10}, 1.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, NpcBrokerFeePct(tt.level, tt.standings), 0.0001)
+		})
+	}
+}
+
+func TestComputeTradeFees(t *testing.T) {
+	skills := map[int64]int{SkillAccounting: 4, SkillBrokerRelations: 3}
+
+	t.Run("NPC station", func(t *testing.T) {
+		fees := ComputeTradeFees(skills, nil, nil)
+		assert.Equal(t, 4, fees.AccountingLevel)
+		assert.Equal(t, 3, fees.BrokerRelationsLevel)
+		assert.InDelta(t, 4.2, fees.SalesTaxPct, 0.0001)
+		assert.InDelta(t, 2.1, fees.BrokerFeePct, 0.0001)
+	})
+
+	t.Run("structure rate replaces NPC broker fee", func(t *testing.T) {
+		rate := 0.5
+		fees := ComputeTradeFees(skills, &TradeStandings{Faction: 10}, &rate)
+		assert.InDelta(t, 4.2, fees.SalesTaxPct, 0.0001)
+		assert.InDelta(t, 0.5, fees.BrokerFeePct, 0.0001)
+	})
+
+	t.Run("no skills pays base rates", func(t *testing.T) {
+		fees := ComputeTradeFees(nil, nil, nil)
+		assert.InDelta(t, BaseSalesTaxPct, fees.SalesTaxPct, 0.0001)
+		assert.InDelta(t, BaseBrokerFeePct, fees.BrokerFeePct, 0.0001)
+	})
+}
+
+func TestBuildCharacterTradeFees(t *testing.T) {
+	names := map[int64]string{1: "Trader", 2: "Alt", 3: "Miner"}
+	skills := map[int64]map[int64]int{
+		1: {SkillAccounting: 5, SkillBrokerRelations: 5},
+		2: {SkillAccounting: 2},
+	}
+
+	result := BuildCharacterTradeFees(names, skills, nil, nil)
+
+	assert.Len(t, result, 3)
+	assert.Equal(t, "Trader", result[0].CharacterName)
+	assert.InDelta(t, 3.375, result[0].SalesTaxPct, 0.0001)
+	assert.InDelta(t, 1.5, result[0].BrokerFeePct, 0.0001)
+	assert.Equal(t, "Alt", result[1].CharacterName)
+	assert.Equal(t, "Miner", result[2].CharacterName)
+	assert.InDelta(t, BaseSalesTaxPct, result[2].SalesTaxPct, 0.0001)
+}
+
+func TestBestTradeFees(t *testing.T) {
+	t.Run("lowest total wins", func(t *testing.T) {
+		best := BestTradeFees(map[int64]map[int64]int{
+			10: {SkillAccounting: 1},
+			20: {SkillAccounting: 5, SkillBrokerRelations: 2},
+		}, nil, nil)
+		assert.Equal(t, int64(20), best.CharacterID)
+		assert.InDelta(t, 3.375, best.SalesTaxPct, 0.0001)
+		assert.InDelta(t, 2.4, best.BrokerFeePct, 0.0001)
+	})
+
+	t.Run("ties go to the lowest character ID", func(t *testing.T) {
+		best := BestTradeFees(map[int64]map[int64]int{
+			30: {SkillAccounting: 3},
+			10: {SkillAccounting: 3},
+		}, nil, nil)
+		assert.Equal(t, int64(10), best.CharacterID)
+	})
+
+	t.Run("no characters pays base rates", func(t *testing.T) {
+		best := BestTradeFees(map[int64]map[int64]int{}, nil, nil)
+		assert.Equal(t, int64(0), best.CharacterID)
+		assert.InDelta(t, BaseSalesTaxPct, best.SalesTaxPct, 0.0001)
+	})
+}
+
+func TestStructureBrokerFeePct(t *testing.T) {
+	rate := 1.2
+	structures := []*models.UserTradingStructure{
+		{StructureID: 1000000000001, BrokerFeePct: &rate},
+		{StructureID: 1000000000002},
+	}
+
+	assert.InDelta(t, 1.2, *StructureBrokerFeePct(structures, 1000000000001), 0.0001)
+	assert.Nil(t, StructureBrokerFeePct(structures, 1000000000002))
+	assert.Nil(t, StructureBrokerFeePct(structures, 60003760))
+}
+
+func TestTradeSkillsByCharacter(t *testing.T) {
+	result := TradeSkillsByCharacter([]*models.CharacterSkill{
+		{CharacterID: 1, SkillID: SkillAccounting, ActiveLevel: 4},
+		{CharacterID: 1, SkillID: SkillIndustry, ActiveLevel: 5},
+		{CharacterID: 2, SkillID: SkillBrokerRelations, ActiveLevel: 2},
+	})
+
+	assert.Equal(t, map[int64]map[int64]int{
+		1: {SkillAccounting: 4},
+		2: {SkillBrokerRelations: 2},
+	}, result)
+}
+
+func TestApplyHaulingRowFees(t *testing.T) {
+	buy, sell := 100.0, 200.0
+	row := &models.HaulingArbitrageRow{BuyPrice: &buy, SellPrice: &sell}
+	noPrice := &models.HaulingArbitrageRow{SellPrice: &sell}
+
+	ApplyHaulingRowFees([]*models.HaulingArbitrageRow{row, noPrice}, &models.HaulingFeesProfile{SalesTaxPct: 5, SellBrokerFeePct: 3})
+
+	// 200 × 0.92 - 100
+	assert.InDelta(t, 84.0, *row.NetProfitISK, 0.0001)
+	assert.Nil(t, noPrice.NetProfitISK)
+}
+
+func TestApplyPnlSummaryFees(t *testing.T) {
+	summary := &models.HaulingRunPnlSummary{
+		TotalRevenueISK: 10000000,
+		TotalCostISK:    8000000,
+		NetProfitISK:    2000000,
+		MarginPct:       20,
+	}
+
+	ApplyPnlSummaryFees(summary, &models.TradeFees{SalesTaxPct: 3.375, BrokerFeePct: 1.5})
+
+	assert.InDelta(t, 337500.0, summary.SalesTaxISK, 0.01)
+	assert.InDelta(t, 150000.0, summary.BrokerFeesISK, 0.01)
+	assert.InDelta(t, 1512500.0, summary.NetProfitISK, 0.01)
+	assert.InDelta(t, 15.125, summary.MarginPct, 0.0001)
+}
@@ -111,38 +111,37 @@ func CalculateCourierCost(params *CourierCostParams) float64 {
 func CalculateCollateralValue(items []*models.TransportJobItem, jitaPrices map[int64]*models.MarketPrice, priceBasis string) float64 {
 	total := 0.0
 	for _, item := range items {
-		price := jitaPrices[item.TypeID]
-		if price == nil {
-			continue
-		}
+		total += PriceForBasis(jitaPrices[item.TypeID], priceBasis) * float64(item.Quantity)
+	}
+	return total
+}
 
-		var unitPrice float64
-		switch priceBasis {
-		case "buy":
-			if price.BuyPrice != nil {
-				unitPrice = *price.BuyPrice
-			}
-		case "sell":
-			if price.SellPrice != nil {
-				unitPrice = *price.SellPrice
-			}
-		case "split":
-			buyPrice := 0.0
-			sellPrice := 0.0
-			if price.BuyPrice != nil {
-				buyPrice = *price.BuyPrice
-			}
-			if price.SellPrice != nil {
-				sellPrice = *price.SellPrice
-			}
-			unitPrice = (buyPrice + sellPrice) / 2.0
-		default:
-			if price.SellPrice != nil {
-				unitPrice = *price.SellPrice
-			}
-		}
+// PriceForBasis returns the unit price of a market price for a price basis (buy, sell, or split).
+// Unknown bases use the sell price; missing prices are 0.
+func PriceForBasis(price *models.MarketPrice, priceBasis string) float64 {
+	if price == nil {
+		return 0
+	}
 
-		total += unitPrice * float64(item.Quantity)
+	switch priceBasis {
+	case "buy":
+		if price.BuyPrice != nil {
+			return *price.BuyPrice
+		}
+	case "split":
+		buyPrice := 0.0
+		sellPrice := 0.0
+		if price.BuyPrice != nil {
+			buyPrice = *price.BuyPrice
+		}
+		if price.SellPrice != nil {
+			sellPrice = *price.SellPrice
+		}
+		return (buyPrice + sellPrice) / 2.0
+	default:
+		if price.SellPrice != nil {
+			return *price.SellPrice
+		}
 	}
-	return total
+	return 0
 }
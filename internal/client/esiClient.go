@@ -8,7 +8,6 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"slices"
 	"strconv"
 	"time"
 
@@ -35,10 +34,9 @@ type HTTPDoer interface {
 }
 
 type EsiClient struct {
-	oauthConfig                *oauth2.Config
-	assetLocationFlagAllowList []string
-	httpClient                 HTTPDoer
-	baseURL                    string
+	oauthConfig *oauth2.Config
+	httpClient  HTTPDoer
+	baseURL     string
 }
 
 func NewEsiClient(clientID, clientSecret string) *EsiClient {
@@ -55,39 +53,14 @@ func NewEsiClientWithHTTPClient(clientID, clientSecret string, httpClient HTTPDo
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
 	}
-	assetLocationFlagAllowList := []string{
-		"Hangar",
-		"Unlocked",
-		"Cargo",
-		"CapsuleerDeliveries",
-		"CorpDeliveries",
-		"Deliveries",
-		"ExpeditionHold",
-		"HangarAll",
-		"InfrastructureHangar",
-		"Locked",
-		"MoonMaterialBay",
-		"SpecializedAsteroidHold",
-		"AssetSafety",
-		"CorporationGoalDeliveries",
-		"CorpSAG1",
-		"CorpSAG2",
-		"CorpSAG3",
-		"CorpSAG4",
-		"CorpSAG5",
-		"CorpSAG6",
-		"CorpSAG7",
-		"OfficeFolder",
-	}
 	if baseURL == "" {
 		baseURL = "https://esi.evetech.net"
 	}
 
 	return &EsiClient{
-		oauthConfig:                oauthConfig,
-		assetLocationFlagAllowList: assetLocationFlagAllowList,
-		httpClient:                 httpClient,
-		baseURL:                    baseURL,
+		oauthConfig: oauthConfig,
+		httpClient:  httpClient,
+		baseURL:     baseURL,
 	}
 }
 
@@ -106,6 +79,7 @@ type MarketOrder struct {
 	Range        string  `json:"range"`
 }
 
+// GetCharacterAssets returns every asset of the character. Location flag filtering is left to the caller.
 func (c *EsiClient) GetCharacterAssets(ctx context.Context, characterID int64, token, refresh string, expire time.Time) ([]*models.EveAsset, error) {
 	assets := []*models.EveAsset{}
 
@@ -154,13 +128,7 @@ func (c *EsiClient) GetCharacterAssets(ctx context.Context, characterID int64, t
 			return nil, errors.Wrap(err, "failed to unmarshal data")
 		}
 
-		for _, asset := range moreAssets {
-			allowed := slices.Contains(c.assetLocationFlagAllowList, asset.LocationFlag)
-			if !allowed {
-				continue
-			}
-			assets = append(assets, asset)
-		}
+		assets = append(assets, moreAssets...)
 
 		if totalPages == page {
 			return assets, nil
@@ -403,6 +371,7 @@ func (c *EsiClient) GetCharacterCorporation(ctx context.Context, characterID int
 	return corp, nil
 }
 
+// GetCorporationAssets returns every asset of the corporation. Location flag filtering is left to the caller.
 func (c *EsiClient) GetCorporationAssets(ctx context.Context, corpID int64, token, refresh string, expire time.Time) ([]*models.EveAsset, error) {
 	assets := []*models.EveAsset{}
 
@@ -448,13 +417,7 @@ func (c *EsiClient) GetCorporationAssets(ctx context.Context, corpID int64, toke
 			return nil, errors.Wrap(err, "failed to unmarshal data")
 		}
 
-		for _, asset := range moreAssets {
-			allowed := slices.Contains(c.assetLocationFlagAllowList, asset.LocationFlag)
-			if !allowed {
-				continue
-			}
-			assets = append(assets, asset)
-		}
+		assets = append(assets, moreAssets...)
 
 		if totalPages == page {
 			return assets, nil
@@ -1061,17 +1024,26 @@ type RefreshedToken struct {
 
 // EsiContract represents a contract from the ESI contracts endpoint.
 type EsiContract struct {
-	ContractID     int64   `json:"contract_id"`
-	IssuerID       int64   `json:"issuer_id"`
-	AcceptorID     int64   `json:"acceptor_id"`
-	AssigneeID     int64   `json:"assignee_id"`
-	Type           string  `json:"type"`
-	Status         string  `json:"status"`
-	Title          string  `json:"title"`
-	DateCompleted  string  `json:"date_completed"`
-	DateExpired    string  `json:"date_expired"`
-	ForCorporation bool    `json:"for_corporation"`
-	Price          float64 `json:"price"`
+	ContractID          int64   `json:"contract_id"`
+	IssuerID            int64   `json:"issuer_id"`
+	IssuerCorporationID int64   `json:"issuer_corporation_id"`
+	AcceptorID          int64   `json:"acceptor_id"`
+	AssigneeID          int64   `json:"assignee_id"`
+	Type                string  `json:"type"`
+	Status              string  `json:"status"`
+	Title               string  `json:"title"`
+	DateIssued          string  `json:"date_issued"`
+	DateAccepted        string  `json:"date_accepted"`
+	DateCompleted       string  `json:"date_completed"`
+	DateExpired         string  `json:"date_expired"`
+	ForCorporation      bool    `json:"for_corporation"`
+	Price               float64 `json:"price"`
+	// Courier contract fields
+	StartLocationID int64   `json:"start_location_id"`
+	EndLocationID   int64   `json:"end_location_id"`
+	Volume          float64 `json:"volume"`
+	Collateral      float64 `json:"collateral"`
+	Reward          float64 `json:"reward"`
 }
 
 // GetCharacterContracts fetches all contracts for a character from ESI.
@@ -1184,6 +1156,60 @@ func (c *EsiClient) GetCorporationContracts(ctx context.Context, corporationID i
 	}
 }
 
+// EsiContractItem is one line of a contract's items. Included items are given by the issuer;
+// excluded items are asked for in return.
+type EsiContractItem struct {
+	RecordID    int64 `json:"record_id"`
+	TypeID      int64 `json:"type_id"`
+	Quantity    int64 `json:"quantity"`
+	IsIncluded  bool  `json:"is_included"`
+	IsSingleton bool  `json:"is_singleton"`
+
+	// -1 for a blueprint original, -2 for a blueprint copy; absent for most other items
+	RawQuantity *int64 `json:"raw_quantity,omitempty"`
+}
+
+// GetCharacterContractItems fetches the items of a character's contract from ESI.
+func (c *EsiClient) GetCharacterContractItems(ctx context.Context, characterID, contractID int64, token string) ([]*EsiContractItem, error) {
+	return c.getContractItems(ctx, fmt.Sprintf("%s/v1/characters/%d/contracts/%d/items/", c.baseURL, characterID, contractID), token, "character")
+}
+
+// GetCorporationContractItems fetches the items of a corporation's contract from ESI.
+func (c *EsiClient) GetCorporationContractItems(ctx context.Context, corporationID, contractID int64, token string) ([]*EsiContractItem, error) {
+	return c.getContractItems(ctx, fmt.Sprintf("%s/v1/corporations/%d/contracts/%d/items/", c.baseURL, corporationID, contractID), token, "corporation")
+}
+
+func (c *EsiClient) getContractItems(ctx context.Context, rawURL, token, owner string) ([]*EsiContractItem, error) {
+	url, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse url")
+	}
+
+	req := &http.Request{
+		Method: "GET",
+		URL:    url,
+		Header: c.getAuthHeaders(token),
+	}
+
+	res, err := c.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %s contract items", owner)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		errText, _ := io.ReadAll(res.Body)
+		return nil, errors.New(fmt.Sprintf("failed to get %s contract items, expected statusCode 200 got %d, %s", owner, res.StatusCode, errText))
+	}
+
+	items := []*EsiContractItem{}
+	if err := json.NewDecoder(res.Body).Decode(&items); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal contract items")
+	}
+
+	return items, nil
+}
+
 // EsiSkillEntry represents one trained skill from the ESI skills endpoint.
 type EsiSkillEntry struct {
 	SkillID            int64 `json:"skill_id"`
@@ -1687,6 +1713,59 @@ func (c *EsiClient) GetCharacterWalletTransactions(ctx context.Context, characte
 	return transactions, nil
 }
 
+// WalletJournalEntry represents a character wallet journal entry from ESI.
+// Amount is positive for ISK coming into the wallet.
+type WalletJournalEntry struct {
+	ID            int64    `json:"id"`
+	Date          string   `json:"date"`
+	RefType       string   `json:"ref_type"`
+	Amount        *float64 `json:"amount,omitempty"`
+	Balance       *float64 `json:"balance,omitempty"`
+	FirstPartyID  *int64   `json:"first_party_id,omitempty"`
+	SecondPartyID *int64   `json:"second_party_id,omitempty"`
+	Reason        string   `json:"reason,omitempty"`
+	Description   string   `json:"description"`
+}
+
+// GetCharacterWalletJournal fetches the first page of a character's wallet journal.
+// Requires esi-wallet.read_character_wallet.v1 scope.
+// Entries are newest first; the first page covers the most recent 2500 entries.
+func (c *EsiClient) GetCharacterWalletJournal(ctx context.Context, characterID int64, token string) ([]*WalletJournalEntry, error) {
+	parsedURL, err := url.Parse(fmt.Sprintf("%s/latest/characters/%d/wallet/journal/", c.baseURL, characterID))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse url")
+	}
+
+	req := &http.Request{
+		Method: "GET",
+		URL:    parsedURL,
+		Header: c.getAuthHeaders(token),
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get character wallet journal")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		errText, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("failed to get character wallet journal, expected 200 got %d, %s", res.StatusCode, errText)
+	}
+
+	respBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	entries := []*WalletJournalEntry{}
+	if err := json.Unmarshal(respBody, &entries); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal wallet journal")
+	}
+
+	return entries, nil
+}
+
 // RefreshAccessToken uses the refresh token to obtain a new access token from EVE SSO.
 // Returns the new access token, refresh token, and expiry. The caller is responsible
 // for persisting these back to the database.
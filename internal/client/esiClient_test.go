@@ -417,6 +417,63 @@ func Test_ClientShouldHandleCorporationContractsError(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to get corporation contracts")
 }
 
+func Test_ClientShouldGetCharacterContractItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHTTPClient := NewMockHTTPDoer(ctrl)
+
+	mockItems := []*client.EsiContractItem{
+		{RecordID: 1, TypeID: 34, Quantity: 1000, IsIncluded: true},
+		{RecordID: 2, TypeID: 35, Quantity: 50, IsIncluded: false},
+	}
+	itemsJSON, _ := json.Marshal(mockItems)
+
+	mockHTTPClient.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "GET", req.Method)
+			assert.Contains(t, req.URL.String(), "/v1/characters/12345/contracts/777/items/")
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader(itemsJSON))}, nil
+		}).
+		Times(1)
+
+	esiClient := client.NewEsiClientWithHTTPClient("test-client-id", "test-client-secret", mockHTTPClient, "https://esi.test.com")
+
+	items, err := esiClient.GetCharacterContractItems(context.Background(), 12345, 777, "test-token")
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, int64(34), items[0].TypeID)
+	assert.Equal(t, int64(1000), items[0].Quantity)
+	assert.True(t, items[0].IsIncluded)
+	assert.False(t, items[1].IsIncluded)
+}
+
+func Test_ClientShouldHandleCorporationContractItemsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHTTPClient := NewMockHTTPDoer(ctrl)
+
+	mockHTTPClient.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			assert.Contains(t, req.URL.String(), "/v1/corporations/5001/contracts/777/items/")
+			return &http.Response{
+				StatusCode: 404,
+				Body:       io.NopCloser(bytes.NewReader([]byte(`{"error":"contract not found"}`))),
+			}, nil
+		}).
+		Times(1)
+
+	esiClient := client.NewEsiClientWithHTTPClient("test-client-id", "test-client-secret", mockHTTPClient, "https://esi.test.com")
+
+	items, err := esiClient.GetCorporationContractItems(context.Background(), 5001, 777, "test-token")
+	assert.Error(t, err)
+	assert.Nil(t, items)
+	assert.Contains(t, err.Error(), "failed to get corporation contract items")
+}
+
 func Test_ClientShouldGetCharacterSkills(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
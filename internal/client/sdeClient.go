@@ -31,6 +31,7 @@ type SdeData struct {
 	Constellations []models.Constellation
 	SolarSystems   []models.SolarSystem
 	Stations       []models.Station
+	Stargates      []models.Stargate
 
 	Blueprints         []models.SdeBlueprint
 	BlueprintActivities []models.SdeBlueprintActivity
@@ -199,6 +200,7 @@ func (c *SdeClient) ParseSDE(zipPath string) (*SdeData, error) {
 		"mapConstellations.yaml":        parseConstellations,
 		"mapSolarSystems.yaml":          parseSolarSystems,
 		"npcStations.yaml":              parseStations,
+		"mapStargates.yaml":             parseStargates,
 	}
 
 	for name, parser := range parsers {
@@ -279,6 +281,68 @@ type sdeTypeYAML struct {
 	RaceID          *int64            `yaml:"raceID"`
 }
 
+// shipPackagedVolumes holds the repackaged volume of each ship group. The SDE
+// only carries packagedVolume for some types, while ships are always hauled
+// packaged at their group's volume.
+var shipPackagedVolumes = map[int64]float64{
+	25:   2500,     // Frigate
+	26:   10000,    // Cruiser
+	27:   50000,    // Battleship
+	28:   20000,    // Hauler
+	30:   10000000, // Titan
+	31:   500,      // Shuttle
+	237:  2500,     // Corvette
+	324:  2500,     // Assault Frigate
+	358:  10000,    // Heavy Assault Cruiser
+	380:  20000,    // Deep Space Transport
+	419:  15000,    // Combat Battlecruiser
+	420:  5000,     // Destroyer
+	463:  3750,     // Mining Barge
+	485:  1300000,  // Dreadnought
+	513:  1000000,  // Freighter
+	540:  15000,    // Command Ship
+	541:  5000,     // Interdictor
+	543:  3750,     // Exhumer
+	547:  1300000,  // Carrier
+	659:  1300000,  // Supercarrier
+	830:  2500,     // Covert Ops
+	831:  2500,     // Interceptor
+	832:  10000,    // Logistics
+	833:  10000,    // Force Recon Ship
+	834:  2500,     // Stealth Bomber
+	883:  1300000,  // Capital Industrial Ship
+	893:  2500,     // Electronic Attack Ship
+	894:  10000,    // Heavy Interdiction Cruiser
+	898:  50000,    // Black Ops
+	900:  50000,    // Marauder
+	902:  1000000,  // Jump Freighter
+	906:  10000,    // Combat Recon Ship
+	941:  500000,   // Industrial Command Ship
+	963:  5000,     // Strategic Cruiser
+	1201: 15000,    // Attack Battlecruiser
+	1202: 20000,    // Blockade Runner
+	1283: 2500,     // Expedition Frigate
+	1305: 5000,     // Tactical Destroyer
+	1527: 2500,     // Logistics Frigate
+	1534: 5000,     // Command Destroyer
+	1538: 1300000,  // Force Auxiliary
+	1972: 10000,    // Flag Cruiser
+}
+
+// typePackagedVolume returns the SDE packaged volume, falling back to the
+// ship group volume.
+func typePackagedVolume(t sdeTypeYAML) *float64 {
+	if t.PackagedVolume != nil {
+		return t.PackagedVolume
+	}
+	if t.GroupID != nil {
+		if v, ok := shipPackagedVolumes[*t.GroupID]; ok {
+			return &v
+		}
+	}
+	return nil
+}
+
 func parseTypeIDs(f *zip.File, data *SdeData) error {
 	raw, err := parseYAMLMap[sdeTypeYAML](f)
 	if err != nil {
@@ -307,7 +371,7 @@ func parseTypeIDs(f *zip.File, data *SdeData) error {
 			Volume:         volume,
 			IconID:         t.IconID,
 			GroupID:        t.GroupID,
-			PackagedVolume: t.PackagedVolume,
+			PackagedVolume: typePackagedVolume(t),
 			Mass:           t.Mass,
 			Capacity:       t.Capacity,
 			PortionSize:    t.PortionSize,
@@ -1376,6 +1440,35 @@ func parseSolarSystems(f *zip.File, data *SdeData) error {
 	return nil
 }
 
+type sdeStargateDestinationYAML struct {
+	SolarSystemID int64 `yaml:"solarSystemID"`
+	StargateID    int64 `yaml:"stargateID"`
+}
+
+type sdeStargateYAML struct {
+	SolarSystemID int64                       `yaml:"solarSystemID"`
+	Destination   sdeStargateDestinationYAML `yaml:"destination"`
+}
+
+func parseStargates(f *zip.File, data *SdeData) error {
+	raw, err := parseYAMLMap[sdeStargateYAML](f)
+	if err != nil {
+		return err
+	}
+
+	stargates := make([]models.Stargate, 0, len(raw))
+	for id, g := range raw {
+		stargates = append(stargates, models.Stargate{
+			ID:                       id,
+			SolarSystemID:            g.SolarSystemID,
+			DestinationStargateID:    g.Destination.StargateID,
+			DestinationSolarSystemID: g.Destination.SolarSystemID,
+		})
+	}
+	data.Stargates = stargates
+	return nil
+}
+
 type sdeStationYAML struct {
 	StationName   localizedString `yaml:"stationName"`
 	SolarSystemID int64           `yaml:"solarSystemID"`
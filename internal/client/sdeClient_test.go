@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/annymsMthd/industry-tool/internal/client"
+	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -205,6 +206,46 @@ func Test_SdeClient_ParseSDEWithTypeIDs(t *testing.T) {
 	assert.Equal(t, "Pyerite", typeMap[35])
 }
 
+func Test_SdeClient_ParseSDEPackagedVolumes(t *testing.T) {
+	zipPath := createTestZip(t, map[string]string{
+		"types.yaml": `
+34:
+  name:
+    en: Tritanium
+  volume: 0.01
+  groupID: 18
+24698:
+  name:
+    en: Drake
+  volume: 252000
+  groupID: 419
+17366:
+  name:
+    en: Station Container
+  volume: 1000000
+  packagedVolume: 10000
+  groupID: 448
+`,
+	})
+	defer os.Remove(zipPath)
+
+	c := client.NewSdeClientWithBaseURL(nil, "https://test.example.com/")
+	data, err := c.ParseSDE(zipPath)
+	assert.NoError(t, err)
+
+	packaged := map[int64]*float64{}
+	for _, t := range data.Types {
+		packaged[t.TypeID] = t.PackagedVolume
+	}
+	assert.Nil(t, packaged[34])
+	if assert.NotNil(t, packaged[24698], "ship groups fall back to the group packaged volume") {
+		assert.Equal(t, 15000.0, *packaged[24698])
+	}
+	if assert.NotNil(t, packaged[17366]) {
+		assert.Equal(t, 10000.0, *packaged[17366])
+	}
+}
+
 func Test_SdeClient_ParseSDEWithBlueprints(t *testing.T) {
 	zipPath := createTestZip(t, map[string]string{
 		"blueprints.yaml": `
@@ -269,6 +310,48 @@ func Test_SdeClient_ParseSDEWithRegions(t *testing.T) {
 	assert.Equal(t, "Sinq Laison", regionMap[10000032])
 }
 
+func Test_SdeClient_ParseSDEWithStargates(t *testing.T) {
+	zipPath := createTestZip(t, map[string]string{
+		"mapStargates.yaml": `
+50001248:
+  destination:
+    solarSystemID: 30000144
+    stargateID: 50001249
+  position:
+    x: 1.0
+    y: 2.0
+    z: 3.0
+  solarSystemID: 30000142
+  typeID: 29635
+50001249:
+  destination:
+    solarSystemID: 30000142
+    stargateID: 50001248
+  solarSystemID: 30000144
+  typeID: 29635
+`,
+	})
+	defer os.Remove(zipPath)
+
+	c := client.NewSdeClientWithBaseURL(nil, "https://test.example.com/")
+	data, err := c.ParseSDE(zipPath)
+
+	assert.NoError(t, err)
+	assert.Len(t, data.Stargates, 2)
+
+	gates := map[int64]models.Stargate{}
+	for _, g := range data.Stargates {
+		gates[g.ID] = g
+	}
+	assert.Equal(t, models.Stargate{
+		ID:                       50001248,
+		SolarSystemID:            30000142,
+		DestinationStargateID:    50001249,
+		DestinationSolarSystemID: 30000144,
+	}, gates[50001248])
+	assert.Equal(t, int64(30000142), gates[50001249].DestinationSolarSystemID)
+}
+
 func Test_SdeClient_ParseSDEMissingFile(t *testing.T) {
 	zipPath := createTestZip(t, map[string]string{
 		"unknownFile.yaml": "data: true",
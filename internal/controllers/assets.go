@@ -2,7 +2,10 @@ package controllers
 
 import (
 	"context"
+	"net/http"
+	"strconv"
 
+	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/annymsMthd/industry-tool/internal/web"
 	"github.com/pkg/errors"
@@ -12,6 +15,7 @@ type AssetsRepository interface {
 	GetUserAssets(ctx context.Context, user int64) (*repositories.AssetsResponse, error)
 	GetUserAssetsSummary(ctx context.Context, user int64) (*repositories.AssetsSummary, error)
 	InjectOrphanStockpileRows(ctx context.Context, userID int64, response *repositories.AssetsResponse) error
+	SearchAssets(ctx context.Context, user int64, filters *models.AssetSearchFilters) (*models.AssetSearchResult, error)
 }
 
 type Assets struct {
@@ -25,6 +29,7 @@ func NewAssets(router Routerer, repository AssetsRepository) *Assets {
 
 	router.RegisterRestAPIRoute("/v1/assets/", web.AuthAccessUser, controller.GetUserAssets, "GET")
 	router.RegisterRestAPIRoute("/v1/assets/summary", web.AuthAccessUser, controller.GetUserAssetsSummary, "GET")
+	router.RegisterRestAPIRoute("/v1/assets/search", web.AuthAccessUser, controller.SearchAssets, "GET")
 
 	return controller
 }
@@ -60,3 +65,104 @@ func (c *Assets) GetUserAssetsSummary(args *web.HandlerArgs) (any, *web.HttpErro
 
 	return summary, nil
 }
+
+// SearchAssets searches all character and corporation assets of the user.
+// Supports ?q=&group_id=&category_id=&market_group_id=&owner_type=&owner_id=&region_id=
+// &system_id=&station_id=&container_id=&blueprint=bpo|bpc&min_value=&sort=value|quantity|name
+// &order=asc|desc&limit=&offset= query params (defaults: sort=value, order=desc, limit=50, offset=0).
+func (c *Assets) SearchAssets(args *web.HandlerArgs) (any, *web.HttpError) {
+	q := args.Request.URL.Query()
+	filters := &models.AssetSearchFilters{
+		TypeName: q.Get("q"),
+		SortBy:   "value",
+		SortDesc: true,
+		Limit:    50,
+	}
+
+	idParams := []struct {
+		name   string
+		target **int64
+	}{
+		{"group_id", &filters.GroupID},
+		{"category_id", &filters.CategoryID},
+		{"market_group_id", &filters.MarketGroupID},
+		{"owner_id", &filters.OwnerID},
+		{"region_id", &filters.RegionID},
+		{"system_id", &filters.SolarSystemID},
+		{"station_id", &filters.StationID},
+		{"container_id", &filters.ContainerID},
+	}
+	for _, p := range idParams {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		id, err := parseID(v)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.Errorf("invalid %s", p.name)}
+		}
+		*p.target = &id
+	}
+
+	if v := q.Get("owner_type"); v != "" {
+		if v != "character" && v != "corporation" {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("owner_type must be character or corporation")}
+		}
+		filters.OwnerType = v
+	}
+
+	if v := q.Get("blueprint"); v != "" {
+		if v != "bpo" && v != "bpc" {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("blueprint must be bpo or bpc")}
+		}
+		filters.Blueprint = v
+	}
+
+	if v := q.Get("min_value"); v != "" {
+		minValue, err := strconv.ParseFloat(v, 64)
+		if err != nil || minValue < 0 {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid min_value")}
+		}
+		filters.MinValue = &minValue
+	}
+
+	if v := q.Get("sort"); v != "" {
+		if v != "value" && v != "quantity" && v != "name" {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("sort must be value, quantity or name")}
+		}
+		filters.SortBy = v
+	}
+
+	if v := q.Get("order"); v != "" {
+		if v != "asc" && v != "desc" {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("order must be asc or desc")}
+		}
+		filters.SortDesc = v == "desc"
+	}
+
+	if v := q.Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > 500 {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid limit")}
+		}
+		filters.Limit = parsed
+	}
+
+	if v := q.Get("offset"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid offset")}
+		}
+		filters.Offset = parsed
+	}
+
+	result, err := c.repository.SearchAssets(args.Request.Context(), *args.User, filters)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to search assets"),
+		}
+	}
+
+	return result, nil
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/annymsMthd/industry-tool/internal/web"
 	"github.com/stretchr/testify/assert"
@@ -39,6 +40,14 @@ func (m *MockAssetsRepository) InjectOrphanStockpileRows(ctx context.Context, us
 	return args.Error(0)
 }
 
+func (m *MockAssetsRepository) SearchAssets(ctx context.Context, user int64, filters *models.AssetSearchFilters) (*models.AssetSearchResult, error) {
+	args := m.Called(ctx, user, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AssetSearchResult), args.Error(1)
+}
+
 func Test_AssetsController_GetUserAssets_Success(t *testing.T) {
 	mockRepo := new(MockAssetsRepository)
 	mockRouter := &MockRouter{}
@@ -231,3 +240,141 @@ func Test_AssetsController_GetUserAssetsSummary_ZeroValues(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func Test_AssetsController_SearchAssets_Defaults(t *testing.T) {
+	mockRepo := new(MockAssetsRepository)
+	mockRouter := &MockRouter{}
+
+	controller := controllers.NewAssets(mockRouter, mockRepo)
+
+	userID := int64(42)
+	expected := &models.AssetSearchResult{
+		Items: []*models.AssetSearchItem{
+			{ItemID: 1001, TypeID: 11399, TypeName: "Morphite", Quantity: 500, TotalValue: 5000000},
+		},
+		Total:         1,
+		TotalQuantity: 500,
+		TotalValue:    5000000,
+		Limit:         50,
+	}
+
+	mockRepo.On("SearchAssets", mock.Anything, userID, &models.AssetSearchFilters{
+		SortBy:   "value",
+		SortDesc: true,
+		Limit:    50,
+	}).Return(expected, nil)
+
+	req := httptest.NewRequest("GET", "/v1/assets/search", nil)
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+	}
+
+	result, httpErr := controller.SearchAssets(args)
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, expected, result)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_AssetsController_SearchAssets_ParsesFilters(t *testing.T) {
+	mockRepo := new(MockAssetsRepository)
+	mockRouter := &MockRouter{}
+
+	controller := controllers.NewAssets(mockRouter, mockRepo)
+
+	userID := int64(42)
+	groupID := int64(18)
+	ownerID := int64(1337)
+	regionID := int64(10000002)
+	minValue := 1000000.0
+
+	mockRepo.On("SearchAssets", mock.Anything, userID, &models.AssetSearchFilters{
+		TypeName:  "zydrine",
+		GroupID:   &groupID,
+		OwnerType: "character",
+		OwnerID:   &ownerID,
+		RegionID:  &regionID,
+		Blueprint: "bpc",
+		MinValue:  &minValue,
+		SortBy:    "quantity",
+		SortDesc:  false,
+		Limit:     10,
+		Offset:    20,
+	}).Return(&models.AssetSearchResult{Items: []*models.AssetSearchItem{}}, nil)
+
+	req := httptest.NewRequest("GET", "/v1/assets/search?q=zydrine&group_id=18&owner_type=character&owner_id=1337&region_id=10000002&blueprint=bpc&min_value=1000000&sort=quantity&order=asc&limit=10&offset=20", nil)
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+	}
+
+	_, httpErr := controller.SearchAssets(args)
+
+	assert.Nil(t, httpErr)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_AssetsController_SearchAssets_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"invalid group id", "group_id=abc"},
+		{"invalid owner type", "owner_type=alliance"},
+		{"invalid blueprint", "blueprint=both"},
+		{"negative min value", "min_value=-5"},
+		{"invalid sort", "sort=volume"},
+		{"invalid order", "order=up"},
+		{"limit too large", "limit=1000"},
+		{"negative offset", "offset=-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockAssetsRepository)
+			mockRouter := &MockRouter{}
+
+			controller := controllers.NewAssets(mockRouter, mockRepo)
+
+			userID := int64(42)
+			req := httptest.NewRequest("GET", "/v1/assets/search?"+tt.query, nil)
+			args := &web.HandlerArgs{
+				Request: req,
+				User:    &userID,
+			}
+
+			result, httpErr := controller.SearchAssets(args)
+
+			assert.Nil(t, result)
+			assert.NotNil(t, httpErr)
+			assert.Equal(t, 400, httpErr.StatusCode)
+			mockRepo.AssertNotCalled(t, "SearchAssets", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func Test_AssetsController_SearchAssets_RepositoryError(t *testing.T) {
+	mockRepo := new(MockAssetsRepository)
+	mockRouter := &MockRouter{}
+
+	controller := controllers.NewAssets(mockRouter, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("SearchAssets", mock.Anything, userID, mock.Anything).Return(nil, errors.New("database error"))
+
+	req := httptest.NewRequest("GET", "/v1/assets/search", nil)
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+	}
+
+	result, httpErr := controller.SearchAssets(args)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 500, httpErr.StatusCode)
+
+	mockRepo.AssertExpectations(t)
+}
@@ -31,6 +31,15 @@ var allowedPriceSources = map[string]bool{
 	"jita_split": true,
 }
 
+// autoSellPriceSources adds sources that only make sense when selling.
+var autoSellPriceSources = map[string]bool{
+	"jita_buy":   true,
+	"jita_sell":  true,
+	"jita_split": true,
+	// Jita sell less the seller's sales tax and broker fee
+	"jita_sell_net": true,
+}
+
 type AutoSellContainers struct {
 	repository     AutoSellContainersRepository
 	syncer         AutoSellSyncer
@@ -110,7 +119,7 @@ func (c *AutoSellContainers) CreateConfig(args *web.HandlerArgs) (any, *web.Http
 	if req.PriceSource == "" {
 		req.PriceSource = "jita_buy"
 	}
-	if !allowedPriceSources[req.PriceSource] {
+	if !autoSellPriceSources[req.PriceSource] {
 		return nil, &web.HttpError{StatusCode: 400, Error: errors.Errorf("invalid priceSource: %s", req.PriceSource)}
 	}
 
@@ -179,7 +188,7 @@ func (c *AutoSellContainers) UpdateConfig(args *web.HandlerArgs) (any, *web.Http
 	}
 
 	if req.PriceSource != "" {
-		if !allowedPriceSources[req.PriceSource] {
+		if !autoSellPriceSources[req.PriceSource] {
 			return nil, &web.HttpError{StatusCode: 400, Error: errors.Errorf("invalid priceSource: %s", req.PriceSource)}
 		}
 		existing.PriceSource = req.PriceSource
@@ -370,6 +370,48 @@ func Test_AutoSellContainersController_CreateConfig_WithJitaSell(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func Test_AutoSellContainersController_CreateConfig_WithJitaSellNet(t *testing.T) {
+	mockRepo := new(MockAutoSellContainersRepository)
+	mockSyncer := new(MockAutoSellSyncer)
+	mockDeactivator := new(MockForSaleItemsDeactivator)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+
+	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(c *models.AutoSellContainer) bool {
+		return c.PriceSource == "jita_sell_net" && c.PricePercentage == 95.0
+	})).Return(nil)
+
+	mockSyncer.On("SyncForUser", mock.Anything, userID).Return(nil)
+
+	body := map[string]interface{}{
+		"ownerType":       "character",
+		"ownerId":         456,
+		"locationId":      60003760,
+		"containerId":     9000,
+		"pricePercentage": 95.0,
+		"priceSource":     "jita_sell_net",
+	}
+	bodyBytes, _ := json.Marshal(body)
+
+	req := httptest.NewRequest("POST", "/v1/auto-sell", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+		Params:  map[string]string{},
+	}
+
+	controller := controllers.NewAutoSellContainers(mockRouter, mockRepo, mockSyncer, mockDeactivator)
+	result, httpErr := controller.CreateConfig(args)
+
+	assert.Nil(t, httpErr)
+	assert.NotNil(t, result)
+
+	mockRepo.AssertExpectations(t)
+}
+
 func Test_AutoSellContainersController_CreateConfig_InvalidPriceSource(t *testing.T) {
 	mockRepo := new(MockAutoSellContainersRepository)
 	mockSyncer := new(MockAutoSellSyncer)
@@ -29,13 +29,15 @@ type BuyOrdersController struct {
 	repository        BuyOrdersRepository
 	permRepo          ContactPermissionsRepository
 	autoFulfillSyncer BuyOrdersAutoFulfillSyncer
+	trustRepository   TradeTrustStatsRepository
 }
 
-func NewBuyOrders(router Routerer, repository BuyOrdersRepository, permRepo ContactPermissionsRepository, autoFulfillSyncer BuyOrdersAutoFulfillSyncer) *BuyOrdersController {
+func NewBuyOrders(router Routerer, repository BuyOrdersRepository, permRepo ContactPermissionsRepository, autoFulfillSyncer BuyOrdersAutoFulfillSyncer, trustRepository TradeTrustStatsRepository) *BuyOrdersController {
 	controller := &BuyOrdersController{
 		repository:        repository,
 		permRepo:          permRepo,
 		autoFulfillSyncer: autoFulfillSyncer,
+		trustRepository:   trustRepository,
 	}
 
 	router.RegisterRestAPIRoute("/v1/buy-orders", web.AuthAccessUser, controller.GetMyOrders, "GET")
@@ -323,6 +325,26 @@ func (c *BuyOrdersController) GetDemand(args *web.HandlerArgs) (any, *web.HttpEr
 		}
 	}
 
+	// Attach each buyer's trade record
+	if c.trustRepository != nil && len(orders) > 0 {
+		buyerIDs := make([]int64, 0, len(orders))
+		for _, order := range orders {
+			buyerIDs = append(buyerIDs, order.BuyerUserID)
+		}
+
+		stats, err := c.trustRepository.GetStats(ctx, buyerIDs)
+		if err != nil {
+			log.Error("failed to get buyer trust", "error", err.Error())
+			return nil, &web.HttpError{
+				StatusCode: http.StatusInternalServerError,
+				Error:      err,
+			}
+		}
+		for _, order := range orders {
+			order.Trust = stats[order.BuyerUserID]
+		}
+	}
+
 	log.Info("demand retrieved", "userId", *args.User, "count", len(orders))
 
 	return orders, nil
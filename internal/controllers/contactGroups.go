@@ -0,0 +1,321 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+type ContactGroupsRepository interface {
+	GetByUser(ctx context.Context, userID int64) ([]*models.ContactGroup, error)
+	GetByID(ctx context.Context, groupID int64, userID int64) (*models.ContactGroup, error)
+	Create(ctx context.Context, group *models.ContactGroup) error
+	Update(ctx context.Context, group *models.ContactGroup) error
+	Delete(ctx context.Context, groupID int64, userID int64) error
+	GetNonContacts(ctx context.Context, ownerUserID int64, userIDs []int64) ([]int64, error)
+	UpdateMembers(ctx context.Context, groupID int64, addUserIDs, removeUserIDs []int64) error
+	GetPrices(ctx context.Context, groupID int64) ([]*models.ContactGroupPrice, error)
+	SetPrice(ctx context.Context, groupID int64, forSaleItemID int64, pricePerUnit float64) error
+	DeletePrice(ctx context.Context, groupID int64, forSaleItemID int64) error
+}
+
+// contactGroupPermissions are the services a group can grant its members
+var contactGroupPermissions = map[string]bool{
+	"for_sale_browse": true,
+	"job_slot_browse": true,
+}
+
+const contactGroupNotFound = "contact group not found or user is not the owner"
+
+type ContactGroups struct {
+	repository ContactGroupsRepository
+}
+
+func NewContactGroups(router Routerer, repository ContactGroupsRepository) *ContactGroups {
+	controller := &ContactGroups{
+		repository: repository,
+	}
+
+	router.RegisterRestAPIRoute("/v1/contact-groups", web.AuthAccessUser, controller.GetMyGroups, "GET")
+	router.RegisterRestAPIRoute("/v1/contact-groups", web.AuthAccessUser, controller.CreateGroup, "POST")
+	router.RegisterRestAPIRoute("/v1/contact-groups/{id}", web.AuthAccessUser, controller.UpdateGroup, "PUT")
+	router.RegisterRestAPIRoute("/v1/contact-groups/{id}", web.AuthAccessUser, controller.DeleteGroup, "DELETE")
+	router.RegisterRestAPIRoute("/v1/contact-groups/{id}/members", web.AuthAccessUser, controller.UpdateMembers, "POST")
+	router.RegisterRestAPIRoute("/v1/contact-groups/{id}/prices", web.AuthAccessUser, controller.GetPrices, "GET")
+	router.RegisterRestAPIRoute("/v1/contact-groups/{id}/prices/{forSaleItemId}", web.AuthAccessUser, controller.SetPrice, "PUT")
+	router.RegisterRestAPIRoute("/v1/contact-groups/{id}/prices/{forSaleItemId}", web.AuthAccessUser, controller.DeletePrice, "DELETE")
+
+	return controller
+}
+
+type contactGroupRequest struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+func (req *contactGroupRequest) validate() *web.HttpError {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return &web.HttpError{StatusCode: 400, Error: errors.New("name is required")}
+	}
+	if len(req.Name) > 100 {
+		return &web.HttpError{StatusCode: 400, Error: errors.New("name must be 100 characters or fewer")}
+	}
+
+	if req.Permissions == nil {
+		req.Permissions = []string{}
+	}
+	for _, p := range req.Permissions {
+		if !contactGroupPermissions[p] {
+			return &web.HttpError{StatusCode: 400, Error: errors.Errorf("invalid permission: %s", p)}
+		}
+	}
+
+	return nil
+}
+
+func (c *ContactGroups) GetMyGroups(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	groups, err := c.repository.GetByUser(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get contact groups")}
+	}
+
+	return groups, nil
+}
+
+func (c *ContactGroups) CreateGroup(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	var req contactGroupRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+	if httpErr := req.validate(); httpErr != nil {
+		return nil, httpErr
+	}
+
+	group := &models.ContactGroup{
+		UserID:      *args.User,
+		Name:        req.Name,
+		Permissions: req.Permissions,
+	}
+
+	if err := c.repository.Create(args.Request.Context(), group); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to create contact group")}
+	}
+
+	return group, nil
+}
+
+func (c *ContactGroups) UpdateGroup(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	groupID, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("invalid id")}
+	}
+
+	var req contactGroupRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+	if httpErr := req.validate(); httpErr != nil {
+		return nil, httpErr
+	}
+
+	group := &models.ContactGroup{
+		ID:          groupID,
+		UserID:      *args.User,
+		Name:        req.Name,
+		Permissions: req.Permissions,
+	}
+
+	if err := c.repository.Update(args.Request.Context(), group); err != nil {
+		if err.Error() == contactGroupNotFound {
+			return nil, &web.HttpError{StatusCode: 404, Error: err}
+		}
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to update contact group")}
+	}
+
+	return c.getGroup(args.Request.Context(), groupID, *args.User)
+}
+
+func (c *ContactGroups) DeleteGroup(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	groupID, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("invalid id")}
+	}
+
+	if err := c.repository.Delete(args.Request.Context(), groupID, *args.User); err != nil {
+		if err.Error() == contactGroupNotFound {
+			return nil, &web.HttpError{StatusCode: 404, Error: err}
+		}
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to delete contact group")}
+	}
+
+	return nil, nil
+}
+
+type updateContactGroupMembersRequest struct {
+	Add    []int64 `json:"add"`
+	Remove []int64 `json:"remove"`
+}
+
+// UpdateMembers adds and removes several members at once. Only accepted contacts can be added.
+func (c *ContactGroups) UpdateMembers(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+	ctx := args.Request.Context()
+
+	groupID, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("invalid id")}
+	}
+
+	var req updateContactGroupMembersRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if _, httpErr := c.getGroup(ctx, groupID, *args.User); httpErr != nil {
+		return nil, httpErr
+	}
+
+	if len(req.Add) > 0 {
+		nonContacts, err := c.repository.GetNonContacts(ctx, *args.User, req.Add)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to check contacts")}
+		}
+		if len(nonContacts) > 0 {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.Errorf("users %v are not your contacts", nonContacts)}
+		}
+	}
+
+	if err := c.repository.UpdateMembers(ctx, groupID, req.Add, req.Remove); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to update contact group members")}
+	}
+
+	return c.getGroup(ctx, groupID, *args.User)
+}
+
+// GetPrices returns the group's price list
+func (c *ContactGroups) GetPrices(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	groupID, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("invalid id")}
+	}
+
+	if _, httpErr := c.getGroup(args.Request.Context(), groupID, *args.User); httpErr != nil {
+		return nil, httpErr
+	}
+
+	prices, err := c.repository.GetPrices(args.Request.Context(), groupID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get contact group prices")}
+	}
+
+	return prices, nil
+}
+
+// SetPrice sets the price the group's members pay for one of the user's listings
+func (c *ContactGroups) SetPrice(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	groupID, forSaleItemID, httpErr := parseContactGroupPriceParams(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	var req struct {
+		PricePerUnit float64 `json:"pricePerUnit"`
+	}
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+	if req.PricePerUnit <= 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("pricePerUnit must be positive")}
+	}
+
+	if _, httpErr := c.getGroup(args.Request.Context(), groupID, *args.User); httpErr != nil {
+		return nil, httpErr
+	}
+
+	if err := c.repository.SetPrice(args.Request.Context(), groupID, forSaleItemID, req.PricePerUnit); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "failed to set contact group price")}
+	}
+
+	return c.GetPrices(args)
+}
+
+// DeletePrice removes a listing from the group's price list
+func (c *ContactGroups) DeletePrice(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	groupID, forSaleItemID, httpErr := parseContactGroupPriceParams(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	if _, httpErr := c.getGroup(args.Request.Context(), groupID, *args.User); httpErr != nil {
+		return nil, httpErr
+	}
+
+	if err := c.repository.DeletePrice(args.Request.Context(), groupID, forSaleItemID); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to delete contact group price")}
+	}
+
+	return nil, nil
+}
+
+// getGroup loads one of the user's groups, answering 404 when they do not own it
+func (c *ContactGroups) getGroup(ctx context.Context, groupID int64, userID int64) (*models.ContactGroup, *web.HttpError) {
+	group, err := c.repository.GetByID(ctx, groupID, userID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get contact group")}
+	}
+	if group == nil {
+		return nil, &web.HttpError{StatusCode: 404, Error: errors.New(contactGroupNotFound)}
+	}
+
+	return group, nil
+}
+
+func parseContactGroupPriceParams(args *web.HandlerArgs) (int64, int64, *web.HttpError) {
+	groupID, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return 0, 0, &web.HttpError{StatusCode: 400, Error: errors.New("invalid id")}
+	}
+
+	forSaleItemID, err := strconv.ParseInt(args.Params["forSaleItemId"], 10, 64)
+	if err != nil {
+		return 0, 0, &web.HttpError{StatusCode: 400, Error: errors.New("invalid for-sale item id")}
+	}
+
+	return groupID, forSaleItemID, nil
+}
@@ -0,0 +1,163 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockContactGroupsRepository struct {
+	mock.Mock
+}
+
+func (m *MockContactGroupsRepository) GetByUser(ctx context.Context, userID int64) ([]*models.ContactGroup, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ContactGroup), args.Error(1)
+}
+
+func (m *MockContactGroupsRepository) GetByID(ctx context.Context, groupID int64, userID int64) (*models.ContactGroup, error) {
+	args := m.Called(ctx, groupID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ContactGroup), args.Error(1)
+}
+
+func (m *MockContactGroupsRepository) Create(ctx context.Context, group *models.ContactGroup) error {
+	args := m.Called(ctx, group)
+	return args.Error(0)
+}
+
+func (m *MockContactGroupsRepository) Update(ctx context.Context, group *models.ContactGroup) error {
+	args := m.Called(ctx, group)
+	return args.Error(0)
+}
+
+func (m *MockContactGroupsRepository) Delete(ctx context.Context, groupID int64, userID int64) error {
+	args := m.Called(ctx, groupID, userID)
+	return args.Error(0)
+}
+
+func (m *MockContactGroupsRepository) GetNonContacts(ctx context.Context, ownerUserID int64, userIDs []int64) ([]int64, error) {
+	args := m.Called(ctx, ownerUserID, userIDs)
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *MockContactGroupsRepository) UpdateMembers(ctx context.Context, groupID int64, addUserIDs, removeUserIDs []int64) error {
+	args := m.Called(ctx, groupID, addUserIDs, removeUserIDs)
+	return args.Error(0)
+}
+
+func (m *MockContactGroupsRepository) GetPrices(ctx context.Context, groupID int64) ([]*models.ContactGroupPrice, error) {
+	args := m.Called(ctx, groupID)
+	return args.Get(0).([]*models.ContactGroupPrice), args.Error(1)
+}
+
+func (m *MockContactGroupsRepository) SetPrice(ctx context.Context, groupID int64, forSaleItemID int64, pricePerUnit float64) error {
+	args := m.Called(ctx, groupID, forSaleItemID, pricePerUnit)
+	return args.Error(0)
+}
+
+func (m *MockContactGroupsRepository) DeletePrice(ctx context.Context, groupID int64, forSaleItemID int64) error {
+	args := m.Called(ctx, groupID, forSaleItemID)
+	return args.Error(0)
+}
+
+func Test_ContactGroupsController_CreateGroup_Success(t *testing.T) {
+	mockRepo := new(MockContactGroupsRepository)
+	controller := controllers.NewContactGroups(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(g *models.ContactGroup) bool {
+		return g.UserID == 42 && g.Name == "Inner circle" && len(g.Permissions) == 2
+	})).Return(nil)
+
+	body := []byte(`{"name":"  Inner circle ","permissions":["for_sale_browse","job_slot_browse"]}`)
+	req := httptest.NewRequest("POST", "/v1/contact-groups", bytes.NewReader(body))
+
+	result, httpErr := controller.CreateGroup(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, "Inner circle", result.(*models.ContactGroup).Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_ContactGroupsController_CreateGroup_InvalidPermission(t *testing.T) {
+	mockRepo := new(MockContactGroupsRepository)
+	controller := controllers.NewContactGroups(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	body := []byte(`{"name":"Corp mates","permissions":["hangar_access"]}`)
+	req := httptest.NewRequest("POST", "/v1/contact-groups", bytes.NewReader(body))
+
+	result, httpErr := controller.CreateGroup(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_ContactGroupsController_UpdateMembers_Bulk(t *testing.T) {
+	mockRepo := new(MockContactGroupsRepository)
+	controller := controllers.NewContactGroups(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	group := &models.ContactGroup{ID: 7, UserID: 42, Name: "Corp mates"}
+	mockRepo.On("GetByID", mock.Anything, int64(7), userID).Return(group, nil)
+	mockRepo.On("GetNonContacts", mock.Anything, userID, []int64{100, 101, 102}).Return([]int64{}, nil)
+	mockRepo.On("UpdateMembers", mock.Anything, int64(7), []int64{100, 101, 102}, []int64{90}).Return(nil)
+
+	body := []byte(`{"add":[100,101,102],"remove":[90]}`)
+	req := httptest.NewRequest("POST", "/v1/contact-groups/7/members", bytes.NewReader(body))
+
+	result, httpErr := controller.UpdateMembers(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "7"}})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, group, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_ContactGroupsController_UpdateMembers_RejectsNonContacts(t *testing.T) {
+	mockRepo := new(MockContactGroupsRepository)
+	controller := controllers.NewContactGroups(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("GetByID", mock.Anything, int64(7), userID).Return(&models.ContactGroup{ID: 7, UserID: 42}, nil)
+	mockRepo.On("GetNonContacts", mock.Anything, userID, []int64{100, 555}).Return([]int64{555}, nil)
+
+	body := []byte(`{"add":[100,555]}`)
+	req := httptest.NewRequest("POST", "/v1/contact-groups/7/members", bytes.NewReader(body))
+
+	_, httpErr := controller.UpdateMembers(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "7"}})
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "UpdateMembers", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_ContactGroupsController_SetPrice_NotOwner(t *testing.T) {
+	mockRepo := new(MockContactGroupsRepository)
+	controller := controllers.NewContactGroups(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("GetByID", mock.Anything, int64(8), userID).Return(nil, nil)
+
+	req := httptest.NewRequest("PUT", "/v1/contact-groups/8/prices/3", bytes.NewReader([]byte(`{"pricePerUnit":90}`)))
+
+	_, httpErr := controller.SetPrice(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "8", "forSaleItemId": "3"}})
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "SetPrice", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"net/url"
 	"strconv"
 
 	"github.com/annymsMthd/industry-tool/internal/models"
@@ -20,19 +21,29 @@ type ForSaleItemsRepository interface {
 	GetUserIDByCharacterID(ctx context.Context, characterID int64) (int64, error)
 }
 
+type ForSaleBlueprintsRepository interface {
+	GetByItemID(ctx context.Context, userID int64, itemID int64) (*models.CharacterBlueprint, error)
+	GetByOwnerAndType(ctx context.Context, userID int64, ownerType string, ownerID int64, typeID int64) ([]*models.CharacterBlueprint, error)
+}
+
 type ForSaleItems struct {
 	repository            ForSaleItemsRepository
 	permissionsRepository ContactPermissionsRepository
+	trustRepository       TradeTrustStatsRepository
+	blueprintsRepository  ForSaleBlueprintsRepository
 }
 
-func NewForSaleItems(router Routerer, repository ForSaleItemsRepository, permissionsRepository ContactPermissionsRepository) *ForSaleItems {
+func NewForSaleItems(router Routerer, repository ForSaleItemsRepository, permissionsRepository ContactPermissionsRepository, trustRepository TradeTrustStatsRepository, blueprintsRepository ForSaleBlueprintsRepository) *ForSaleItems {
 	controller := &ForSaleItems{
 		repository:            repository,
 		permissionsRepository: permissionsRepository,
+		trustRepository:       trustRepository,
+		blueprintsRepository:  blueprintsRepository,
 	}
 
 	router.RegisterRestAPIRoute("/v1/for-sale", web.AuthAccessUser, controller.GetMyListings, "GET")
 	router.RegisterRestAPIRoute("/v1/for-sale/browse", web.AuthAccessUser, controller.BrowseListings, "GET")
+	router.RegisterRestAPIRoute("/v1/for-sale/blueprints", web.AuthAccessUser, controller.GetListableBlueprints, "GET")
 	router.RegisterRestAPIRoute("/v1/for-sale", web.AuthAccessUser, controller.CreateListing, "POST")
 	router.RegisterRestAPIRoute("/v1/for-sale/{id}", web.AuthAccessUser, controller.UpdateListing, "PUT")
 	router.RegisterRestAPIRoute("/v1/for-sale/{id}", web.AuthAccessUser, controller.DeleteListing, "DELETE")
@@ -66,7 +77,9 @@ func (c *ForSaleItems) GetMyListings(args *web.HandlerArgs) (any, *web.HttpError
 	return items, nil
 }
 
-// BrowseListings returns for-sale items from contacts who granted browse permission
+// BrowseListings returns for-sale items from contacts who granted browse permission.
+// Blueprint listings can be narrowed with the blueprint (copy or original), minRuns, minMe and
+// minTe query parameters; any of them leaves out listings that are not for a blueprint item.
 func (c *ForSaleItems) BrowseListings(args *web.HandlerArgs) (any, *web.HttpError) {
 	if args.User == nil {
 		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
@@ -89,9 +102,59 @@ func (c *ForSaleItems) BrowseListings(args *web.HandlerArgs) (any, *web.HttpErro
 		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get browsable items")}
 	}
 
+	items, err = filterBlueprintListings(items, args.Request.URL.Query())
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: err}
+	}
+
+	// Attach each seller's trade record
+	if c.trustRepository != nil && len(items) > 0 {
+		listingSellerIDs := make([]int64, 0, len(items))
+		for _, item := range items {
+			listingSellerIDs = append(listingSellerIDs, item.UserID)
+		}
+
+		stats, err := c.trustRepository.GetStats(args.Request.Context(), listingSellerIDs)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get seller trust")}
+		}
+		for _, item := range items {
+			item.Trust = stats[item.UserID]
+		}
+	}
+
 	return items, nil
 }
 
+// GetListableBlueprints returns the user's blueprint items of a type held by an owner, so a
+// listing can be made for one specific copy or original
+func (c *ForSaleItems) GetListableBlueprints(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	query := args.Request.URL.Query()
+	typeID, err := strconv.ParseInt(query.Get("typeId"), 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("invalid typeId")}
+	}
+	ownerID, err := strconv.ParseInt(query.Get("ownerId"), 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("invalid ownerId")}
+	}
+	ownerType := query.Get("ownerType")
+	if ownerType == "" {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("ownerType is required")}
+	}
+
+	blueprints, err := c.blueprintsRepository.GetByOwnerAndType(args.Request.Context(), *args.User, ownerType, ownerID, typeID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get blueprints")}
+	}
+
+	return blueprints, nil
+}
+
 // CreateListing creates a new for-sale item listing
 func (c *ForSaleItems) CreateListing(args *web.HandlerArgs) (any, *web.HttpError) {
 	if args.User == nil {
@@ -113,6 +176,7 @@ func (c *ForSaleItems) CreateListing(args *web.HandlerArgs) (any, *web.HttpError
 		QuantityAvailable int64   `json:"quantityAvailable"`
 		PricePerUnit      float64 `json:"pricePerUnit"`
 		Notes             *string `json:"notes"`
+		BlueprintItemID   *int64  `json:"blueprintItemId"`
 	}
 
 	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
@@ -139,6 +203,35 @@ func (c *ForSaleItems) CreateListing(args *web.HandlerArgs) (any, *web.HttpError
 		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("pricePerUnit must be non-negative")}
 	}
 
+	// A blueprint listing is for one specific blueprint item and carries its attributes
+	var blueprint *models.ListingBlueprint
+	if req.BlueprintItemID != nil {
+		bp, err := c.blueprintsRepository.GetByItemID(args.Request.Context(), userID, *req.BlueprintItemID)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get blueprint")}
+		}
+		if bp == nil || bp.TypeID != req.TypeID || bp.OwnerType != req.OwnerType || bp.OwnerID != req.OwnerID {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.New("blueprint not found for this owner and type")}
+		}
+
+		// Copies and single originals are one item each; only stacked originals have a quantity
+		available := int64(1)
+		if bp.Quantity > 0 {
+			available = int64(bp.Quantity)
+		}
+		if req.QuantityAvailable > available {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.Errorf("blueprint item has only %d available", available)}
+		}
+
+		blueprint = &models.ListingBlueprint{
+			ItemID:             bp.ItemID,
+			IsCopy:             bp.Quantity == -2,
+			Runs:               bp.Runs,
+			MaterialEfficiency: bp.MaterialEfficiency,
+			TimeEfficiency:     bp.TimeEfficiency,
+		}
+	}
+
 	item := &models.ForSaleItem{
 		UserID:            userID,
 		TypeID:            req.TypeID,
@@ -151,6 +244,7 @@ func (c *ForSaleItems) CreateListing(args *web.HandlerArgs) (any, *web.HttpError
 		PricePerUnit:      req.PricePerUnit,
 		Notes:             req.Notes,
 		IsActive:          true,
+		Blueprint:         blueprint,
 	}
 
 	if err := c.repository.Upsert(args.Request.Context(), item); err != nil {
@@ -258,3 +352,52 @@ func (c *ForSaleItems) DeleteListing(args *web.HandlerArgs) (any, *web.HttpError
 
 	return nil, nil
 }
+
+// filterBlueprintListings applies the browse blueprint filters to a set of listings
+func filterBlueprintListings(items []*models.ForSaleItem, query url.Values) ([]*models.ForSaleItem, error) {
+	kind := query.Get("blueprint")
+	if kind != "" && kind != "copy" && kind != "original" {
+		return nil, errors.New("blueprint must be copy or original")
+	}
+
+	minimums := map[string]int{}
+	for _, name := range []string{"minRuns", "minMe", "minTe"} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.Errorf("invalid %s", name)
+		}
+		minimums[name] = parsed
+	}
+
+	if kind == "" && len(minimums) == 0 {
+		return items, nil
+	}
+
+	filtered := []*models.ForSaleItem{}
+	for _, item := range items {
+		bp := item.Blueprint
+		if bp == nil {
+			continue
+		}
+		if (kind == "copy" && !bp.IsCopy) || (kind == "original" && bp.IsCopy) {
+			continue
+		}
+		// Originals have unlimited runs, so they pass any minimum
+		if min, ok := minimums["minRuns"]; ok && bp.IsCopy && bp.Runs < min {
+			continue
+		}
+		if min, ok := minimums["minMe"]; ok && bp.MaterialEfficiency < min {
+			continue
+		}
+		if min, ok := minimums["minTe"]; ok && bp.TimeEfficiency < min {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+
+	return filtered, nil
+}
@@ -59,6 +59,23 @@ func (m *MockForSaleItemsRepository) GetUserIDByCharacterID(ctx context.Context,
 	return args.Get(0).(int64), args.Error(1)
 }
 
+type MockForSaleBlueprintsRepository struct {
+	mock.Mock
+}
+
+func (m *MockForSaleBlueprintsRepository) GetByItemID(ctx context.Context, userID int64, itemID int64) (*models.CharacterBlueprint, error) {
+	args := m.Called(ctx, userID, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CharacterBlueprint), args.Error(1)
+}
+
+func (m *MockForSaleBlueprintsRepository) GetByOwnerAndType(ctx context.Context, userID int64, ownerType string, ownerID int64, typeID int64) ([]*models.CharacterBlueprint, error) {
+	args := m.Called(ctx, userID, ownerType, ownerID, typeID)
+	return args.Get(0).([]*models.CharacterBlueprint), args.Error(1)
+}
+
 func Test_ForSaleItemsController_GetMyListings_Success(t *testing.T) {
 	mockRepo := new(MockForSaleItemsRepository)
 	mockRouter := &MockRouter{}
@@ -91,7 +108,7 @@ func Test_ForSaleItemsController_GetMyListings_Success(t *testing.T) {
 		Params:  map[string]string{},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, nil, nil)
 	result, httpErr := controller.GetMyListings(args)
 
 	assert.Nil(t, httpErr)
@@ -120,7 +137,7 @@ func Test_ForSaleItemsController_GetMyListings_RepositoryError(t *testing.T) {
 		Params:  map[string]string{},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, nil, nil)
 	result, httpErr := controller.GetMyListings(args)
 
 	assert.Nil(t, result)
@@ -162,7 +179,7 @@ func Test_ForSaleItemsController_CreateListing_Success(t *testing.T) {
 		Params:  map[string]string{},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, nil, nil)
 	result, httpErr := controller.CreateListing(args)
 
 	assert.Nil(t, httpErr)
@@ -186,7 +203,7 @@ func Test_ForSaleItemsController_CreateListing_InvalidJSON(t *testing.T) {
 		Params:  map[string]string{},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, nil, nil)
 	result, httpErr := controller.CreateListing(args)
 
 	assert.Nil(t, result)
@@ -220,7 +237,7 @@ func Test_ForSaleItemsController_CreateListing_MissingRequiredFields(t *testing.
 		Params:  map[string]string{},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, nil, nil)
 	result, httpErr := controller.CreateListing(args)
 
 	assert.Nil(t, result)
@@ -256,7 +273,7 @@ func Test_ForSaleItemsController_CreateListing_InvalidQuantity(t *testing.T) {
 		Params:  map[string]string{},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, nil, nil)
 	result, httpErr := controller.CreateListing(args)
 
 	assert.Nil(t, result)
@@ -307,7 +324,7 @@ func Test_ForSaleItemsController_UpdateListing_Success(t *testing.T) {
 		Params:  map[string]string{"id": "1"},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, nil, nil)
 	result, httpErr := controller.UpdateListing(args)
 
 	assert.Nil(t, httpErr)
@@ -340,7 +357,7 @@ func Test_ForSaleItemsController_UpdateListing_NotFound(t *testing.T) {
 		Params:  map[string]string{"id": "999"},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, nil, nil)
 	result, httpErr := controller.UpdateListing(args)
 
 	assert.Nil(t, result)
@@ -383,7 +400,7 @@ func Test_ForSaleItemsController_UpdateListing_NotOwner(t *testing.T) {
 		Params:  map[string]string{"id": "1"},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, nil, nil)
 	result, httpErr := controller.UpdateListing(args)
 
 	assert.Nil(t, result)
@@ -410,7 +427,7 @@ func Test_ForSaleItemsController_DeleteListing_Success(t *testing.T) {
 		Params:  map[string]string{"id": "1"},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, nil, nil)
 	result, httpErr := controller.DeleteListing(args)
 
 	assert.Nil(t, httpErr)
@@ -436,7 +453,7 @@ func Test_ForSaleItemsController_DeleteListing_NotFound(t *testing.T) {
 		Params:  map[string]string{"id": "999"},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, nil, nil)
 	result, httpErr := controller.DeleteListing(args)
 
 	assert.Nil(t, result)
@@ -461,10 +478,119 @@ func Test_ForSaleItemsController_DeleteListing_InvalidID(t *testing.T) {
 		Params:  map[string]string{"id": "invalid"},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, nil, nil)
 	result, httpErr := controller.DeleteListing(args)
 
 	assert.Nil(t, result)
 	assert.NotNil(t, httpErr)
 	assert.Equal(t, 400, httpErr.StatusCode)
 }
+
+func Test_ForSaleItemsController_BrowseListings_AttachesSellerTrust(t *testing.T) {
+	mockRepo := new(MockForSaleItemsRepository)
+	mockPerms := new(MockContactPermissionsRepository)
+	mockTrust := new(MockTradeTrustRepository)
+
+	userID := int64(123)
+	score := 75
+
+	mockPerms.On("GetUserPermissionsForService", mock.Anything, userID, "for_sale_browse").Return([]int64{456, 789}, nil)
+	mockRepo.On("GetBrowsableItems", mock.Anything, userID, []int64{456, 789}).Return([]*models.ForSaleItem{
+		{ID: 1, UserID: 456, TypeName: "Tritanium"},
+	}, nil)
+	mockTrust.On("GetStats", mock.Anything, []int64{456}).Return(map[int64]*models.TradeTrustStats{
+		456: {UserID: 456, CompletedCount: 3, TrustScore: &score},
+	}, nil)
+
+	controller := controllers.NewForSaleItems(&MockRouter{}, mockRepo, mockPerms, mockTrust, nil)
+	result, httpErr := controller.BrowseListings(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/for-sale/browse", nil),
+		User:    &userID,
+	})
+
+	assert.Nil(t, httpErr)
+	items := result.([]*models.ForSaleItem)
+	assert.Equal(t, 75, *items[0].Trust.TrustScore)
+	mockTrust.AssertExpectations(t)
+}
+
+func Test_ForSaleItemsController_CreateListing_BlueprintCopy(t *testing.T) {
+	mockRepo := new(MockForSaleItemsRepository)
+	mockBlueprints := new(MockForSaleBlueprintsRepository)
+
+	userID := int64(123)
+	mockBlueprints.On("GetByItemID", mock.Anything, userID, int64(9001)).Return(&models.CharacterBlueprint{
+		ItemID: 9001, OwnerType: "character", OwnerID: 456, TypeID: 1000,
+		Quantity: -2, Runs: 10, MaterialEfficiency: 10, TimeEfficiency: 20,
+	}, nil)
+	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(item *models.ForSaleItem) bool {
+		return item.Blueprint != nil &&
+			item.Blueprint.ItemID == 9001 &&
+			item.Blueprint.IsCopy &&
+			item.Blueprint.Runs == 10 &&
+			item.Blueprint.MaterialEfficiency == 10 &&
+			item.Blueprint.TimeEfficiency == 20
+	})).Return(nil)
+
+	body := []byte(`{"typeId":1000,"ownerType":"character","ownerId":456,"locationId":30000142,"quantityAvailable":1,"pricePerUnit":2000000,"blueprintItemId":9001}`)
+	req := httptest.NewRequest("POST", "/v1/for-sale", bytes.NewReader(body))
+
+	controller := controllers.NewForSaleItems(&MockRouter{}, mockRepo, &MockContactPermissionsRepository{}, nil, mockBlueprints)
+	_, httpErr := controller.CreateListing(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_ForSaleItemsController_CreateListing_BlueprintCopyQuantityAboveOne(t *testing.T) {
+	mockRepo := new(MockForSaleItemsRepository)
+	mockBlueprints := new(MockForSaleBlueprintsRepository)
+
+	userID := int64(123)
+	mockBlueprints.On("GetByItemID", mock.Anything, userID, int64(9001)).Return(&models.CharacterBlueprint{
+		ItemID: 9001, OwnerType: "character", OwnerID: 456, TypeID: 1000, Quantity: -2, Runs: 10,
+	}, nil)
+
+	body := []byte(`{"typeId":1000,"ownerType":"character","ownerId":456,"locationId":30000142,"quantityAvailable":5,"pricePerUnit":2000000,"blueprintItemId":9001}`)
+	req := httptest.NewRequest("POST", "/v1/for-sale", bytes.NewReader(body))
+
+	controller := controllers.NewForSaleItems(&MockRouter{}, mockRepo, &MockContactPermissionsRepository{}, nil, mockBlueprints)
+	_, httpErr := controller.CreateListing(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func Test_ForSaleItemsController_BrowseListings_FiltersBlueprints(t *testing.T) {
+	mockRepo := new(MockForSaleItemsRepository)
+	mockPerms := new(MockContactPermissionsRepository)
+
+	userID := int64(123)
+	mockPerms.On("GetUserPermissionsForService", mock.Anything, userID, "for_sale_browse").Return([]int64{456}, nil)
+	mockRepo.On("GetBrowsableItems", mock.Anything, userID, []int64{456}).Return([]*models.ForSaleItem{
+		{ID: 1, UserID: 456, TypeName: "Tritanium"},
+		{ID: 2, UserID: 456, TypeName: "Rifter Blueprint", Blueprint: &models.ListingBlueprint{ItemID: 1, IsCopy: true, Runs: 10, MaterialEfficiency: 10, TimeEfficiency: 20}},
+		{ID: 3, UserID: 456, TypeName: "Rifter Blueprint", Blueprint: &models.ListingBlueprint{ItemID: 2, IsCopy: true, Runs: 2, MaterialEfficiency: 10, TimeEfficiency: 20}},
+		{ID: 4, UserID: 456, TypeName: "Rifter Blueprint", Blueprint: &models.ListingBlueprint{ItemID: 3, IsCopy: true, Runs: 10, MaterialEfficiency: 4, TimeEfficiency: 8}},
+		{ID: 5, UserID: 456, TypeName: "Rifter Blueprint", Blueprint: &models.ListingBlueprint{ItemID: 4, Runs: -1, MaterialEfficiency: 10, TimeEfficiency: 20}},
+	}, nil)
+
+	controller := controllers.NewForSaleItems(&MockRouter{}, mockRepo, mockPerms, nil, nil)
+	result, httpErr := controller.BrowseListings(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/for-sale/browse?blueprint=copy&minRuns=5&minMe=10", nil),
+		User:    &userID,
+	})
+
+	assert.Nil(t, httpErr)
+	items := result.([]*models.ForSaleItem)
+	assert.Len(t, items, 1)
+	assert.Equal(t, int64(2), items[0].ID)
+
+	_, httpErr = controller.BrowseListings(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/for-sale/browse?minRuns=many", nil),
+		User:    &userID,
+	})
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/url"
 	"strconv"
 
+	"github.com/annymsMthd/industry-tool/internal/calculator"
+	log "github.com/annymsMthd/industry-tool/internal/logging"
 	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/web"
 	"github.com/pkg/errors"
@@ -22,11 +25,19 @@ type HaulingRunsRepository interface {
 
 type HaulingRunItemsRepository interface {
 	AddItem(ctx context.Context, item *models.HaulingRunItem) (*models.HaulingRunItem, error)
+	AddItems(ctx context.Context, items []*models.HaulingRunItem) ([]*models.HaulingRunItem, error)
 	GetItemsByRunID(ctx context.Context, runID int64) ([]*models.HaulingRunItem, error)
 	UpdateItemAcquired(ctx context.Context, itemID int64, runID int64, quantityAcquired int64) error
+	UpdateItemStops(ctx context.Context, itemID int64, runID int64, buyStopID, sellStopID *int64) error
 	RemoveItem(ctx context.Context, itemID int64, runID int64) error
 }
 
+// HaulingRunStopsRepository provides the ordered stops of multi-stop runs.
+type HaulingRunStopsRepository interface {
+	GetStopsByRunID(ctx context.Context, runID int64) ([]*models.HaulingRunStop, error)
+	ReplaceStops(ctx context.Context, runID int64, stops []*models.HaulingRunStop) ([]*models.HaulingRunStop, error)
+}
+
 type HaulingMarketUpdater interface {
 	ScanRegion(ctx context.Context, regionID int64, systemID int64) error
 	ScanStructure(ctx context.Context, structureID int64, token string) (bool, error)
@@ -59,43 +70,84 @@ type HaulingRunNotifier interface {
 // HaulingAnalyticsRepository provides analytics data access for hauling runs.
 type HaulingAnalyticsRepository interface {
 	GetRouteAnalytics(ctx context.Context, userID int64) ([]*models.HaulingRouteAnalytics, error)
+	GetLegAnalytics(ctx context.Context, userID int64) ([]*models.HaulingLegAnalytics, error)
 	GetItemAnalytics(ctx context.Context, userID int64) ([]*models.HaulingItemAnalytics, error)
 	GetProfitTimeSeries(ctx context.Context, userID int64) ([]*models.HaulingProfitDataPoint, error)
 	GetRunDurationSummary(ctx context.Context, userID int64) (*models.HaulingRunDurationSummary, error)
 	GetCompletedRuns(ctx context.Context, userID int64, limit, offset int) ([]*models.HaulingRun, int64, error)
 }
 
+// HaulingRouteLookup finds the gate route between two solar systems.
+type HaulingRouteLookup interface {
+	GetRoute(ctx context.Context, origin, destination int64, flag string) ([]int32, error)
+}
+
+// HaulingSolarSystemsRepository provides system security for scanner routes.
+type HaulingSolarSystemsRepository interface {
+	GetByIDs(ctx context.Context, ids []int64) ([]*models.SolarSystem, error)
+}
+
+// HaulingTransportProfilesRepository provides the ship profile for scanner ISK/hour.
+type HaulingTransportProfilesRepository interface {
+	GetByID(ctx context.Context, id, userID int64) (*models.TransportProfile, error)
+}
+
+// HaulingTradeSkillsRepository provides the skills that set a user's market fees.
+type HaulingTradeSkillsRepository interface {
+	GetSkillsForUser(ctx context.Context, userID int64) ([]*models.CharacterSkill, error)
+}
+
+// HaulingTradingStructuresRepository provides owner-set broker rates of player structures.
+type HaulingTradingStructuresRepository interface {
+	List(ctx context.Context, userID int64) ([]*models.UserTradingStructure, error)
+}
+
 type HaulingRunsController struct {
 	runs       HaulingRunsRepository
 	items      HaulingRunItemsRepository
+	stops      HaulingRunStopsRepository
 	market     HaulingMarketRepository
 	structures HaulingStructureRepository
 	scanner    HaulingMarketUpdater
 	pnl        HaulingPnlRepository
 	notifier   HaulingRunNotifier // may be nil
 	analytics  HaulingAnalyticsRepository
+	routes     HaulingRouteLookup
+	systems    HaulingSolarSystemsRepository
+	profiles   HaulingTransportProfilesRepository
+
+	tradeSkills       HaulingTradeSkillsRepository       // may be nil
+	tradingStructures HaulingTradingStructuresRepository // may be nil
 }
 
 func NewHaulingRuns(
 	router Routerer,
 	runs HaulingRunsRepository,
 	items HaulingRunItemsRepository,
+	stops HaulingRunStopsRepository,
 	market HaulingMarketRepository,
 	structures HaulingStructureRepository,
 	scanner HaulingMarketUpdater,
 	pnl HaulingPnlRepository,
 	notifier HaulingRunNotifier,
 	analytics HaulingAnalyticsRepository,
+	routes HaulingRouteLookup,
+	systems HaulingSolarSystemsRepository,
+	profiles HaulingTransportProfilesRepository,
 ) *HaulingRunsController {
 	c := &HaulingRunsController{
 		runs:       runs,
 		items:      items,
+		stops:      stops,
 		market:     market,
 		structures: structures,
 		scanner:    scanner,
 		pnl:        pnl,
 		notifier:   notifier,
 		analytics:  analytics,
+		routes:     routes,
+		systems:    systems,
+		profiles:   profiles,
 	}
 	router.RegisterRestAPIRoute("/v1/hauling/runs", web.AuthAccessUser, c.ListRuns, "GET")
 	router.RegisterRestAPIRoute("/v1/hauling/runs", web.AuthAccessUser, c.CreateRun, "POST")
@@ -103,15 +155,21 @@ func NewHaulingRuns(
 	router.RegisterRestAPIRoute("/v1/hauling/runs/{id}", web.AuthAccessUser, c.UpdateRun, "PUT")
 	router.RegisterRestAPIRoute("/v1/hauling/runs/{id}", web.AuthAccessUser, c.DeleteRun, "DELETE")
 	router.RegisterRestAPIRoute("/v1/hauling/runs/{id}/status", web.AuthAccessUser, c.UpdateStatus, "PUT")
+	router.RegisterRestAPIRoute("/v1/hauling/runs/{id}/stops", web.AuthAccessUser, c.GetStops, "GET")
+	router.RegisterRestAPIRoute("/v1/hauling/runs/{id}/stops", web.AuthAccessUser, c.ReplaceStops, "PUT")
+	router.RegisterRestAPIRoute("/v1/hauling/runs/{id}/legs", web.AuthAccessUser, c.GetLegs, "GET")
 	router.RegisterRestAPIRoute("/v1/hauling/runs/{id}/items", web.AuthAccessUser, c.AddItem, "POST")
+	router.RegisterRestAPIRoute("/v1/hauling/runs/{id}/optimize", web.AuthAccessUser, c.OptimizeCargo, "POST")
 	router.RegisterRestAPIRoute("/v1/hauling/runs/{id}/items/{itemId}", web.AuthAccessUser, c.UpdateItemAcquired, "PUT")
 	router.RegisterRestAPIRoute("/v1/hauling/runs/{id}/items/{itemId}", web.AuthAccessUser, c.RemoveItem, "DELETE")
+	router.RegisterRestAPIRoute("/v1/hauling/runs/{id}/items/{itemId}/stops", web.AuthAccessUser, c.UpdateItemStops, "PUT")
 	router.RegisterRestAPIRoute("/v1/hauling/runs/{id}/pnl", web.AuthAccessUser, c.GetPnl, "GET")
 	router.RegisterRestAPIRoute("/v1/hauling/runs/{id}/pnl", web.AuthAccessUser, c.UpsertPnlEntry, "PUT")
 	router.RegisterRestAPIRoute("/v1/hauling/runs/{id}/pnl/summary", web.AuthAccessUser, c.GetPnlSummary, "GET")
 	router.RegisterRestAPIRoute("/v1/hauling/scanner", web.AuthAccessUser, c.GetScannerResults, "GET")
 	router.RegisterRestAPIRoute("/v1/hauling/scanner/scan", web.AuthAccessUser, c.TriggerScan, "POST")
 	router.RegisterRestAPIRoute("/v1/hauling/analytics/routes", web.AuthAccessUser, c.GetRouteAnalytics, "GET")
+	router.RegisterRestAPIRoute("/v1/hauling/analytics/legs", web.AuthAccessUser, c.GetLegAnalytics, "GET")
 	router.RegisterRestAPIRoute("/v1/hauling/analytics/items", web.AuthAccessUser, c.GetItemAnalytics, "GET")
 	router.RegisterRestAPIRoute("/v1/hauling/analytics/timeseries", web.AuthAccessUser, c.GetProfitTimeSeries, "GET")
 	router.RegisterRestAPIRoute("/v1/hauling/analytics/summary", web.AuthAccessUser, c.GetRunDurationSummary, "GET")
@@ -119,6 +177,36 @@ func NewHaulingRuns(
 	return c
 }
 
+// WithTradeFees sets the optional repositories used to charge each user's
+// skill-based sales tax and broker fee on scanner rows, P&L and the cargo
+// optimizer. Without them scanner rows and P&L are gross of fees.
+func (c *HaulingRunsController) WithTradeFees(skills HaulingTradeSkillsRepository, structures HaulingTradingStructuresRepository) {
+	c.tradeSkills = skills
+	c.tradingStructures = structures
+}
+
+// sellFees returns the user's lowest sell-order fees at a location, using the
+// structure's broker rate when it is a configured trading structure. Returns
+// nil when trade fees are not configured.
+func (c *HaulingRunsController) sellFees(ctx context.Context, userID int64, locationID int64) (*models.TradeFees, error) {
+	if c.tradeSkills == nil {
+		return nil, nil
+	}
+	skills, err := c.tradeSkills.GetSkillsForUser(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get character skills")
+	}
+	var structureBrokerFeePct *float64
+	if c.tradingStructures != nil && locationID != 0 {
+		structures, err := c.tradingStructures.List(ctx, userID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get trading structures")
+		}
+		structureBrokerFeePct = calculator.StructureBrokerFeePct(structures, locationID)
+	}
+	return calculator.BestTradeFees(calculator.TradeSkillsByCharacter(skills), nil, structureBrokerFeePct), nil
+}
+
 func (c *HaulingRunsController) ListRuns(args *web.HandlerArgs) (interface{}, *web.HttpError) {
 	runs, err := c.runs.ListRunsByUser(args.Request.Context(), *args.User)
 	if err != nil {
@@ -159,6 +247,11 @@ func (c *HaulingRunsController) GetRun(args *web.HandlerArgs) (interface{}, *web
 		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get items")}
 	}
 	run.Items = items
+	stops, err := c.stops.GetStopsByRunID(args.Request.Context(), id)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get stops")}
+	}
+	run.Stops = stops
 	return run, nil
 }
 
@@ -208,6 +301,11 @@ func (c *HaulingRunsController) UpdateStatus(args *web.HandlerArgs) (interface{}
 			ctx := context.Background()
 			run, _ := c.runs.GetRunByID(ctx, id, userID)
 			summary, _ := c.pnl.GetPnlSummaryByRunID(ctx, id)
+			if run != nil && summary != nil {
+				if fees, err := c.sellFees(ctx, userID, runSellLocation(run)); err == nil && fees != nil {
+					calculator.ApplyPnlSummaryFees(summary, fees)
+				}
+			}
 			c.notifier.NotifyHaulingComplete(ctx, userID, run, summary)
 		}()
 	}
@@ -244,6 +342,24 @@ func (c *HaulingRunsController) AddItem(args *web.HandlerArgs) (interface{}, *we
 		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.Wrap(err, "invalid request body")}
 	}
 	item.RunID = id
+
+	stops, err := c.stops.GetStopsByRunID(args.Request.Context(), id)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get stops")}
+	}
+	if err := calculator.ValidateHaulingItemStops(stops, item.BuyStopID, item.SellStopID); err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: err}
+	}
+	if len(stops) >= 2 && run.MaxVolumeM3 != nil {
+		items, err := c.items.GetItemsByRunID(args.Request.Context(), id)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get items")}
+		}
+		if httpErr := checkLegCapacity(run, stops, append(items, &item)); httpErr != nil {
+			return nil, httpErr
+		}
+	}
+
 	created, err := c.items.AddItem(args.Request.Context(), &item)
 	if err != nil {
 		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to add item")}
@@ -251,6 +367,129 @@ func (c *HaulingRunsController) AddItem(args *web.HandlerArgs) (interface{}, *we
 	return created, nil
 }
 
+// OptimizeCargo chooses quantities from scanner rows that maximize expected
+// profit within the run's free volume and the given capital, and adds them to
+// the run. Types already on the run are skipped; their planned volume and cost
+// count against the limits.
+func (c *HaulingRunsController) OptimizeCargo(args *web.HandlerArgs) (interface{}, *web.HttpError) {
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid run id")}
+	}
+	ctx := args.Request.Context()
+	run, err := c.runs.GetRunByID(ctx, id, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get run")}
+	}
+	if run == nil {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: errors.New("run not found")}
+	}
+
+	var req models.HaulingCargoOptimizeRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.Wrap(err, "invalid request body")}
+	}
+	if len(req.Rows) == 0 {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("rows are required")}
+	}
+	if req.CapitalISK < 0 || req.MaxUnitsPerType < 0 || req.MaxDaysToSell < 0 {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("capitalIsk, maxUnitsPerType and maxDaysToSell must be non-negative")}
+	}
+
+	volumeM3 := run.MaxVolumeM3
+	if req.VolumeM3 != nil {
+		volumeM3 = req.VolumeM3
+	}
+	if volumeM3 == nil || *volumeM3 <= 0 {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("volumeM3 is required when the run has no max volume")}
+	}
+
+	fees := req.Fees
+	if fees == nil {
+		tradeFees, err := c.sellFees(ctx, *args.User, runSellLocation(run))
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get trade fees")}
+		}
+		if tradeFees == nil {
+			tradeFees = calculator.ComputeTradeFees(map[int64]int{}, nil, nil)
+		}
+		fees = calculator.HaulingFeesFromTradeFees(tradeFees)
+	}
+
+	existing, err := c.items.GetItemsByRunID(ctx, id)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get items")}
+	}
+	onRun := map[int64]bool{}
+	usedVolume, usedCapital := 0.0, 0.0
+	for _, item := range existing {
+		onRun[item.TypeID] = true
+		if item.VolumeM3 != nil {
+			usedVolume += *item.VolumeM3 * float64(item.QuantityPlanned)
+		}
+		if item.BuyPriceISK != nil {
+			usedCapital += *item.BuyPriceISK * float64(item.QuantityPlanned)
+		}
+	}
+
+	result := &models.HaulingCargoPlan{
+		Items:             []*models.HaulingRunItem{},
+		RemainingVolumeM3: *volumeM3 - usedVolume,
+	}
+	capital := req.CapitalISK
+	if capital > 0 {
+		capital -= usedCapital
+		if capital <= 0 {
+			return result, nil
+		}
+	}
+	if result.RemainingVolumeM3 <= 0 {
+		result.RemainingVolumeM3 = 0
+		return result, nil
+	}
+
+	rows := []*models.HaulingArbitrageRow{}
+	for _, row := range req.Rows {
+		if !onRun[row.TypeID] {
+			rows = append(rows, row)
+		}
+	}
+
+	candidates := calculator.BuildCargoCandidates(rows, fees, calculator.CargoLimits{
+		MaxUnitsPerType: req.MaxUnitsPerType,
+		MaxDaysToSell:   req.MaxDaysToSell,
+	})
+	plan := calculator.OptimizeCargo(candidates, result.RemainingVolumeM3, capital)
+	if len(plan.Picks) == 0 {
+		return result, nil
+	}
+
+	items := make([]*models.HaulingRunItem, 0, len(plan.Picks))
+	for _, pick := range plan.Picks {
+		row := pick.Candidate.Row
+		items = append(items, &models.HaulingRunItem{
+			RunID:           id,
+			TypeID:          row.TypeID,
+			TypeName:        row.TypeName,
+			QuantityPlanned: pick.Quantity,
+			BuyPriceISK:     row.BuyPrice,
+			SellPriceISK:    row.SellPrice,
+			VolumeM3:        row.VolumeM3,
+		})
+	}
+	added, err := c.items.AddItems(ctx, items)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to add items")}
+	}
+
+	result.Items = added
+	result.TotalVolumeM3 = plan.VolumeM3
+	result.TotalCostISK = plan.CostISK
+	result.ExpectedProfitISK = plan.ExpectedProfit
+	result.RemainingVolumeM3 -= plan.VolumeM3
+	return result, nil
+}
+
 func (c *HaulingRunsController) UpdateItemAcquired(args *web.HandlerArgs) (interface{}, *web.HttpError) {
 	runID, err := strconv.ParseInt(args.Params["id"], 10, 64)
 	if err != nil {
@@ -324,6 +563,225 @@ func (c *HaulingRunsController) RemoveItem(args *web.HandlerArgs) (interface{},
 	return nil, nil
 }
 
+// GetStops returns a run's stops in route order.
+func (c *HaulingRunsController) GetStops(args *web.HandlerArgs) (interface{}, *web.HttpError) {
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid run id")}
+	}
+	// Verify user owns the run
+	run, err := c.runs.GetRunByID(args.Request.Context(), id, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get run")}
+	}
+	if run == nil {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: errors.New("run not found")}
+	}
+	stops, err := c.stops.GetStopsByRunID(args.Request.Context(), id)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get stops")}
+	}
+	return stops, nil
+}
+
+// ReplaceStops sets a run's ordered stops. Existing stops are kept by ID;
+// stops left out are removed and items assigned to them fall back to the
+// first or last stop. An empty list turns the run back into a single leg.
+// The new order must keep every item's buy stop before its sell stop and
+// every leg within the run's max volume.
+func (c *HaulingRunsController) ReplaceStops(args *web.HandlerArgs) (interface{}, *web.HttpError) {
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid run id")}
+	}
+	ctx := args.Request.Context()
+	run, err := c.runs.GetRunByID(ctx, id, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get run")}
+	}
+	if run == nil {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: errors.New("run not found")}
+	}
+
+	var stops []*models.HaulingRunStop
+	if err := json.NewDecoder(args.Request.Body).Decode(&stops); err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.Wrap(err, "invalid request body")}
+	}
+	if len(stops) == 1 {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("a multi-stop run needs at least two stops")}
+	}
+
+	existing, err := c.stops.GetStopsByRunID(ctx, id)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get stops")}
+	}
+	onRun := map[int64]bool{}
+	for _, stop := range existing {
+		onRun[stop.ID] = true
+	}
+	kept := map[int64]bool{}
+	for i, stop := range stops {
+		if stop.RegionID == 0 {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.Errorf("stop %d needs a regionId", i+1)}
+		}
+		if stop.ID != 0 {
+			if !onRun[stop.ID] || kept[stop.ID] {
+				return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.Errorf("stop %d is not on the run", i+1)}
+			}
+			kept[stop.ID] = true
+		}
+	}
+
+	if len(stops) > 0 {
+		items, err := c.items.GetItemsByRunID(ctx, id)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get items")}
+		}
+		// Assignments to removed stops are cleared by the database.
+		planned := make([]*models.HaulingRunItem, 0, len(items))
+		for _, item := range items {
+			next := *item
+			if next.BuyStopID != nil && !kept[*next.BuyStopID] {
+				next.BuyStopID = nil
+			}
+			if next.SellStopID != nil && !kept[*next.SellStopID] {
+				next.SellStopID = nil
+			}
+			if err := calculator.ValidateHaulingItemStops(stops, next.BuyStopID, next.SellStopID); err != nil {
+				return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.Wrapf(err, "%s", itemLabel(item))}
+			}
+			planned = append(planned, &next)
+		}
+		if httpErr := checkLegCapacity(run, stops, planned); httpErr != nil {
+			return nil, httpErr
+		}
+	}
+
+	saved, err := c.stops.ReplaceStops(ctx, id, stops)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to save stops")}
+	}
+	return saved, nil
+}
+
+// UpdateItemStops assigns the stops an item is bought and sold at. A nil stop
+// means the first (buy) or last (sell) stop of the run.
+func (c *HaulingRunsController) UpdateItemStops(args *web.HandlerArgs) (interface{}, *web.HttpError) {
+	runID, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid run id")}
+	}
+	itemID, err := strconv.ParseInt(args.Params["itemId"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid item id")}
+	}
+	var body struct {
+		BuyStopID  *int64 `json:"buyStopId"`
+		SellStopID *int64 `json:"sellStopId"`
+	}
+	if err := json.NewDecoder(args.Request.Body).Decode(&body); err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.Wrap(err, "invalid request body")}
+	}
+	ctx := args.Request.Context()
+	run, err := c.runs.GetRunByID(ctx, runID, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get run")}
+	}
+	if run == nil {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: errors.New("run not found")}
+	}
+
+	stops, err := c.stops.GetStopsByRunID(ctx, runID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get stops")}
+	}
+	if err := calculator.ValidateHaulingItemStops(stops, body.BuyStopID, body.SellStopID); err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: err}
+	}
+
+	items, err := c.items.GetItemsByRunID(ctx, runID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get items")}
+	}
+	planned := make([]*models.HaulingRunItem, 0, len(items))
+	found := false
+	for _, item := range items {
+		if item.ID == itemID {
+			next := *item
+			next.BuyStopID = body.BuyStopID
+			next.SellStopID = body.SellStopID
+			item = &next
+			found = true
+		}
+		planned = append(planned, item)
+	}
+	if !found {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: errors.New("item not found")}
+	}
+	if httpErr := checkLegCapacity(run, stops, planned); httpErr != nil {
+		return nil, httpErr
+	}
+
+	if err := c.items.UpdateItemStops(ctx, itemID, runID, body.BuyStopID, body.SellStopID); err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to update item stops")}
+	}
+	return nil, nil
+}
+
+// GetLegs returns cargo load, planned profit and recorded P&L for each leg
+// of a multi-stop run. Runs without stops have no legs.
+func (c *HaulingRunsController) GetLegs(args *web.HandlerArgs) (interface{}, *web.HttpError) {
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid run id")}
+	}
+	ctx := args.Request.Context()
+	run, err := c.runs.GetRunByID(ctx, id, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get run")}
+	}
+	if run == nil {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: errors.New("run not found")}
+	}
+	stops, err := c.stops.GetStopsByRunID(ctx, id)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get stops")}
+	}
+	if len(stops) < 2 {
+		return []*models.HaulingRunLeg{}, nil
+	}
+	items, err := c.items.GetItemsByRunID(ctx, id)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get items")}
+	}
+	entries, err := c.pnl.GetPnlByRunID(ctx, id)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get pnl entries")}
+	}
+	return calculator.BuildHaulingLegs(stops, items, entries, run.MaxVolumeM3), nil
+}
+
+// checkLegCapacity rejects a planned cargo that overloads any leg of the run.
+func checkLegCapacity(run *models.HaulingRun, stops []*models.HaulingRunStop, items []*models.HaulingRunItem) *web.HttpError {
+	if run.MaxVolumeM3 == nil {
+		return nil
+	}
+	leg := calculator.FirstOverloadedLeg(calculator.BuildHaulingLegs(stops, items, nil, run.MaxVolumeM3))
+	if leg == nil {
+		return nil
+	}
+	return &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.Errorf(
+		"leg %d is over capacity: %.1f m3 of %.1f m3", leg.Seq+1, leg.LoadM3, *run.MaxVolumeM3)}
+}
+
+// itemLabel names an item in validation errors.
+func itemLabel(item *models.HaulingRunItem) string {
+	if item.TypeName != "" {
+		return item.TypeName
+	}
+	return "type " + strconv.FormatInt(item.TypeID, 10)
+}
+
 // GetPnl returns all P&L entries for a run.
 func (c *HaulingRunsController) GetPnl(args *web.HandlerArgs) (interface{}, *web.HttpError) {
 	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
@@ -388,15 +846,32 @@ func (c *HaulingRunsController) GetPnlSummary(args *web.HandlerArgs) (interface{
 	if err != nil {
 		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get pnl summary")}
 	}
+	fees, err := c.sellFees(args.Request.Context(), *args.User, runSellLocation(run))
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get trade fees")}
+	}
+	if fees != nil {
+		calculator.ApplyPnlSummaryFees(summary, fees)
+	}
 	return summary, nil
 }
 
+// runSellLocation is the station or structure a run sells at, 0 when unset.
+func runSellLocation(run *models.HaulingRun) int64 {
+	if run.ToStationID != nil {
+		return *run.ToStationID
+	}
+	return 0
+}
+
+// GetScannerResults returns arbitrage rows between a source and destination,
+// with route jumps, lowest security and ISK/m³/jump on every row. Optional
+// query params: transport_profile_id (adds ISK/hour for that ship),
+// highsec_only, max_jumps and sort ("isk_per_hour" or "isk_per_m3_jump").
 func (c *HaulingRunsController) GetScannerResults(args *web.HandlerArgs) (interface{}, *web.HttpError) {
 	q := args.Request.URL.Query()
 	sourceRegionStr := q.Get("source_region_id")
 	destRegionStr := q.Get("dest_region_id")
-	sourceSystemStr := q.Get("source_system_id")
-	destSystemStr := q.Get("dest_system_id")
 	sourceStructureStr := q.Get("source_structure_id")
 	destStructureStr := q.Get("dest_structure_id")
 
@@ -421,79 +896,247 @@ func (c *HaulingRunsController) GetScannerResults(args *web.HandlerArgs) (interf
 		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("structure-to-structure scanning is not supported")}
 	}
 
+	// System IDs are optional everywhere; they pin the route endpoints
+	sourceSystemID, httpErr := parseOptionalInt64Param(q, "source_system_id")
+	if httpErr != nil {
+		return nil, httpErr
+	}
+	destSystemID, httpErr := parseOptionalInt64Param(q, "dest_system_id")
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	filters, httpErr := parseScannerRouteFilters(q)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	ctx := args.Request.Context()
+	var results []*models.HaulingArbitrageRow
+	var sourceRegionID, destRegionID int64
+
+	switch {
 	// Source is a structure
-	if sourceStructureID > 0 {
+	case sourceStructureID > 0:
 		if destRegionStr == "" {
 			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("dest_region_id is required")}
 		}
-		destRegionID, err := strconv.ParseInt(destRegionStr, 10, 64)
+		destRegionID, err = strconv.ParseInt(destRegionStr, 10, 64)
 		if err != nil {
 			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid dest_region_id")}
 		}
-		var destSystemID int64
-		if destSystemStr != "" {
-			destSystemID, err = strconv.ParseInt(destSystemStr, 10, 64)
-			if err != nil {
-				return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid dest_system_id")}
-			}
-		}
-		results, err := c.structures.GetStructureScannerResults(args.Request.Context(), sourceStructureID, destRegionID, destSystemID)
+		results, err = c.structures.GetStructureScannerResults(ctx, sourceStructureID, destRegionID, destSystemID)
 		if err != nil {
 			return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get structure scanner results")}
 		}
-		return results, nil
-	}
 
 	// Destination is a structure
-	if destStructureID > 0 {
+	case destStructureID > 0:
 		if sourceRegionStr == "" {
 			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("source_region_id is required")}
 		}
-		sourceRegionID, err := strconv.ParseInt(sourceRegionStr, 10, 64)
+		sourceRegionID, err = strconv.ParseInt(sourceRegionStr, 10, 64)
 		if err != nil {
 			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid source_region_id")}
 		}
-		var sourceSystemID int64
-		if sourceSystemStr != "" {
-			sourceSystemID, err = strconv.ParseInt(sourceSystemStr, 10, 64)
-			if err != nil {
-				return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid source_system_id")}
-			}
-		}
-		results, err := c.structures.GetRegionToStructureResults(args.Request.Context(), sourceRegionID, sourceSystemID, destStructureID)
+		results, err = c.structures.GetRegionToStructureResults(ctx, sourceRegionID, sourceSystemID, destStructureID)
 		if err != nil {
 			return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get region-to-structure results")}
 		}
-		return results, nil
-	}
 
 	// Standard region-to-region scan
-	if sourceRegionStr == "" || destRegionStr == "" {
-		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("source_region_id and dest_region_id are required")}
+	default:
+		if sourceRegionStr == "" || destRegionStr == "" {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("source_region_id and dest_region_id are required")}
+		}
+		sourceRegionID, err = strconv.ParseInt(sourceRegionStr, 10, 64)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid source_region_id")}
+		}
+		destRegionID, err = strconv.ParseInt(destRegionStr, 10, 64)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid dest_region_id")}
+		}
+		results, err = c.market.GetScannerResults(ctx, sourceRegionID, sourceSystemID, destRegionID)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get scanner results")}
+		}
 	}
-	sourceRegionID, err := strconv.ParseInt(sourceRegionStr, 10, 64)
+
+	// Net profit after selling through a sell order at the destination
+	fees, err := c.sellFees(ctx, *args.User, destStructureID)
 	if err != nil {
-		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid source_region_id")}
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get trade fees")}
+	}
+	if fees != nil {
+		calculator.ApplyHaulingRowFees(results, calculator.HaulingFeesFromTradeFees(fees))
+	}
+
+	// Region-wide endpoints route from and to the region's trade hub
+	if sourceSystemID == 0 {
+		sourceSystemID = calculator.TradeHubSystems[sourceRegionID]
+	}
+	if destSystemID == 0 {
+		destSystemID = calculator.TradeHubSystems[destRegionID]
+	}
+
+	return c.applyScannerRoute(ctx, *args.User, results, sourceSystemID, destSystemID, filters)
+}
+
+// scannerRouteFilters are the optional route and ranking params of the scanner.
+type scannerRouteFilters struct {
+	highsecOnly bool
+	maxJumps    int // 0 = no limit
+	profileID   int64
+	sortBy      string
+}
+
+func (f *scannerRouteFilters) active() bool {
+	return f.highsecOnly || f.maxJumps > 0
+}
+
+func parseScannerRouteFilters(q url.Values) (*scannerRouteFilters, *web.HttpError) {
+	filters := &scannerRouteFilters{
+		highsecOnly: q.Get("highsec_only") == "true",
+		sortBy:      q.Get("sort"),
+	}
+
+	if s := q.Get("max_jumps"); s != "" {
+		maxJumps, err := strconv.Atoi(s)
+		if err != nil || maxJumps < 0 {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid max_jumps")}
+		}
+		filters.maxJumps = maxJumps
 	}
-	destRegionID, err := strconv.ParseInt(destRegionStr, 10, 64)
+
+	profileID, httpErr := parseOptionalInt64Param(q, "transport_profile_id")
+	if httpErr != nil {
+		return nil, httpErr
+	}
+	filters.profileID = profileID
+
+	if filters.sortBy != "" && filters.sortBy != "isk_per_hour" && filters.sortBy != "isk_per_m3_jump" {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("sort must be isk_per_hour or isk_per_m3_jump")}
+	}
+
+	return filters, nil
+}
+
+func parseOptionalInt64Param(q url.Values, name string) (int64, *web.HttpError) {
+	s := q.Get(name)
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
 	if err != nil {
-		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid dest_region_id")}
+		return 0, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid " + name)}
+	}
+	return v, nil
+}
+
+// applyScannerRoute looks up the route between the scan endpoints, drops
+// everything when the route fails a filter, and ranks the rows. When the
+// route cannot be looked up the rows are returned unranked, unless a route
+// filter was asked for.
+func (c *HaulingRunsController) applyScannerRoute(
+	ctx context.Context,
+	userID int64,
+	results []*models.HaulingArbitrageRow,
+	sourceSystemID, destSystemID int64,
+	filters *scannerRouteFilters,
+) (interface{}, *web.HttpError) {
+	if sourceSystemID == 0 || destSystemID == 0 {
+		if filters.active() {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("route filters need a source_system_id and dest_system_id outside the known trade hub regions")}
+		}
+		return results, nil
 	}
-	var sourceSystemID int64
-	if sourceSystemStr != "" {
-		sourceSystemID, err = strconv.ParseInt(sourceSystemStr, 10, 64)
+
+	var profile *models.TransportProfile
+	if filters.profileID > 0 {
+		var err error
+		profile, err = c.profiles.GetByID(ctx, filters.profileID, userID)
 		if err != nil {
-			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid source_system_id")}
+			return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get transport profile")}
 		}
+		if profile == nil {
+			return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: errors.New("transport profile not found")}
+		}
+	}
+
+	flag := "shortest"
+	if filters.highsecOnly {
+		flag = "secure"
+	} else if profile != nil && profile.RoutePreference != "" {
+		flag = profile.RoutePreference
 	}
 
-	results, err := c.market.GetScannerResults(args.Request.Context(), sourceRegionID, sourceSystemID, destRegionID)
+	route, err := c.scannerRoute(ctx, sourceSystemID, destSystemID, flag)
 	if err != nil {
-		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get scanner results")}
+		if filters.active() {
+			return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get route")}
+		}
+		log.Error("hauling scanner: failed to get route", "origin", sourceSystemID, "destination", destSystemID, "error", err)
+		return results, nil
+	}
+
+	// The secure flag still routes through lowsec when there is no other way
+	if filters.highsecOnly && route.MinSecurity != nil && *route.MinSecurity < calculator.HighsecMinSecurity {
+		return []*models.HaulingArbitrageRow{}, nil
 	}
+	if filters.maxJumps > 0 && route.Jumps > filters.maxJumps {
+		return []*models.HaulingArbitrageRow{}, nil
+	}
+
+	cargoM3 := 0.0
+	secondsPerJump := calculator.HaulingSecondsPerJump("")
+	sortBy := filters.sortBy
+	if profile != nil {
+		cargoM3 = profile.CargoM3
+		secondsPerJump = calculator.HaulingSecondsPerJump(profile.TransportMethod)
+		if sortBy == "" {
+			sortBy = "isk_per_hour"
+		}
+	}
+
+	calculator.ApplyHaulingRouteMetrics(results, route, cargoM3, secondsPerJump)
+	calculator.RankHaulingRows(results, sortBy)
 	return results, nil
 }
 
+// scannerRoute returns jumps and the lowest security between two systems.
+func (c *HaulingRunsController) scannerRoute(ctx context.Context, origin, destination int64, flag string) (*calculator.HaulingRoute, error) {
+	systemIDs := []int64{origin}
+	if origin != destination {
+		route, err := c.routes.GetRoute(ctx, origin, destination, flag)
+		if err != nil {
+			return nil, err
+		}
+		systemIDs = make([]int64, 0, len(route))
+		for _, id := range route {
+			systemIDs = append(systemIDs, int64(id))
+		}
+	}
+
+	result := &calculator.HaulingRoute{Jumps: len(systemIDs) - 1}
+	if result.Jumps < 0 {
+		result.Jumps = 0
+	}
+
+	systems, err := c.systems.GetByIDs(ctx, systemIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get route systems")
+	}
+	for _, system := range systems {
+		if result.MinSecurity == nil || system.Security < *result.MinSecurity {
+			security := system.Security
+			result.MinSecurity = &security
+		}
+	}
+
+	return result, nil
+}
+
 func (c *HaulingRunsController) TriggerScan(args *web.HandlerArgs) (interface{}, *web.HttpError) {
 	var body struct {
 		RegionID          int64  `json:"regionId"`
@@ -560,6 +1203,16 @@ func (c *HaulingRunsController) GetRouteAnalytics(args *web.HandlerArgs) (interf
 	return results, nil
 }
 
+// GetLegAnalytics returns P&L stats for completed runs aggregated per leg
+// region pair. Runs without stops count as one leg.
+func (c *HaulingRunsController) GetLegAnalytics(args *web.HandlerArgs) (interface{}, *web.HttpError) {
+	results, err := c.analytics.GetLegAnalytics(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get leg analytics")}
+	}
+	return results, nil
+}
+
 // GetItemAnalytics returns per-item-type aggregated P&L stats for completed runs.
 func (c *HaulingRunsController) GetItemAnalytics(args *web.HandlerArgs) (interface{}, *web.HttpError) {
 	results, err := c.analytics.GetItemAnalytics(args.Request.Context(), *args.User)
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/annymsMthd/industry-tool/internal/calculator"
 	"github.com/annymsMthd/industry-tool/internal/controllers"
 	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/web"
@@ -74,6 +75,22 @@ func (m *MockHaulingRunItemsRepository) AddItem(ctx context.Context, item *model
 	return args.Get(0).(*models.HaulingRunItem), args.Error(1)
 }
 
+func (m *MockHaulingRunItemsRepository) AddItems(ctx context.Context, items []*models.HaulingRunItem) ([]*models.HaulingRunItem, error) {
+	args := m.Called(ctx, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.HaulingRunItem), args.Error(1)
+}
+
+// addItemsEcho makes the AddItems mock return the items it was given.
+func addItemsEcho(m *MockHaulingRunItemsRepository, matcher interface{}) {
+	call := m.On("AddItems", mock.Anything, matcher)
+	call.Run(func(args mock.Arguments) {
+		call.ReturnArguments = mock.Arguments{args.Get(1), nil}
+	})
+}
+
 func (m *MockHaulingRunItemsRepository) GetItemsByRunID(ctx context.Context, runID int64) ([]*models.HaulingRunItem, error) {
 	args := m.Called(ctx, runID)
 	if args.Get(0) == nil {
@@ -87,11 +104,38 @@ func (m *MockHaulingRunItemsRepository) UpdateItemAcquired(ctx context.Context,
 	return args.Error(0)
 }
 
+func (m *MockHaulingRunItemsRepository) UpdateItemStops(ctx context.Context, itemID int64, runID int64, buyStopID, sellStopID *int64) error {
+	args := m.Called(ctx, itemID, runID, buyStopID, sellStopID)
+	return args.Error(0)
+}
+
 func (m *MockHaulingRunItemsRepository) RemoveItem(ctx context.Context, itemID int64, runID int64) error {
 	args := m.Called(ctx, itemID, runID)
 	return args.Error(0)
 }
 
+// --- Mock HaulingRunStopsRepository ---
+
+type MockHaulingRunStopsRepository struct {
+	mock.Mock
+}
+
+func (m *MockHaulingRunStopsRepository) GetStopsByRunID(ctx context.Context, runID int64) ([]*models.HaulingRunStop, error) {
+	args := m.Called(ctx, runID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.HaulingRunStop), args.Error(1)
+}
+
+func (m *MockHaulingRunStopsRepository) ReplaceStops(ctx context.Context, runID int64, stops []*models.HaulingRunStop) ([]*models.HaulingRunStop, error) {
+	args := m.Called(ctx, runID, stops)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.HaulingRunStop), args.Error(1)
+}
+
 // --- Mock HaulingMarketRepository ---
 
 type MockHaulingMarketRepo struct {
@@ -203,6 +247,14 @@ func (m *MockHaulingAnalyticsRepository) GetRouteAnalytics(ctx context.Context,
 	return args.Get(0).([]*models.HaulingRouteAnalytics), args.Error(1)
 }
 
+func (m *MockHaulingAnalyticsRepository) GetLegAnalytics(ctx context.Context, userID int64) ([]*models.HaulingLegAnalytics, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.HaulingLegAnalytics), args.Error(1)
+}
+
 func (m *MockHaulingAnalyticsRepository) GetItemAnalytics(ctx context.Context, userID int64) ([]*models.HaulingItemAnalytics, error) {
 	args := m.Called(ctx, userID)
 	if args.Get(0) == nil {
@@ -240,27 +292,35 @@ func (m *MockHaulingAnalyticsRepository) GetCompletedRuns(ctx context.Context, u
 type haulingMocks struct {
 	runs       *MockHaulingRunsRepository
 	items      *MockHaulingRunItemsRepository
+	stops      *MockHaulingRunStopsRepository
 	market     *MockHaulingMarketRepo
 	structures *MockHaulingStructureRepository
 	scanner    *MockHaulingMarketUpdater
 	pnl        *MockHaulingPnlRepository
 	notifier   *MockHaulingRunNotifier
 	analytics  *MockHaulingAnalyticsRepository
+	routes     *MockTransportRouteFinder
+	systems    *MockTransportSolarSystemsRepo
+	profiles   *MockTransportProfilesRepo
 }
 
 func setupHaulingController() (*controllers.HaulingRunsController, haulingMocks) {
 	mocks := haulingMocks{
 		runs:       new(MockHaulingRunsRepository),
 		items:      new(MockHaulingRunItemsRepository),
+		stops:      new(MockHaulingRunStopsRepository),
 		market:     new(MockHaulingMarketRepo),
 		structures: new(MockHaulingStructureRepository),
 		scanner:    new(MockHaulingMarketUpdater),
 		pnl:        new(MockHaulingPnlRepository),
 		notifier:   nil, // default: no notifier
 		analytics:  new(MockHaulingAnalyticsRepository),
+		routes:     new(MockTransportRouteFinder),
+		systems:    new(MockTransportSolarSystemsRepo),
+		profiles:   new(MockTransportProfilesRepo),
 	}
 	router := &MockRouter{}
-	controller := controllers.NewHaulingRuns(router, mocks.runs, mocks.items, mocks.market, mocks.structures, mocks.scanner, mocks.pnl, nil, mocks.analytics)
+	controller := controllers.NewHaulingRuns(router, mocks.runs, mocks.items, mocks.stops, mocks.market, mocks.structures, mocks.scanner, mocks.pnl, nil, mocks.analytics, mocks.routes, mocks.systems, mocks.profiles)
 	return controller, mocks
 }
 
@@ -268,15 +328,19 @@ func setupHaulingControllerWithNotifier() (*controllers.HaulingRunsController, h
 	mocks := haulingMocks{
 		runs:       new(MockHaulingRunsRepository),
 		items:      new(MockHaulingRunItemsRepository),
+		stops:      new(MockHaulingRunStopsRepository),
 		market:     new(MockHaulingMarketRepo),
 		structures: new(MockHaulingStructureRepository),
 		scanner:    new(MockHaulingMarketUpdater),
 		pnl:        new(MockHaulingPnlRepository),
 		notifier:   new(MockHaulingRunNotifier),
 		analytics:  new(MockHaulingAnalyticsRepository),
+		routes:     new(MockTransportRouteFinder),
+		systems:    new(MockTransportSolarSystemsRepo),
+		profiles:   new(MockTransportProfilesRepo),
 	}
 	router := &MockRouter{}
-	controller := controllers.NewHaulingRuns(router, mocks.runs, mocks.items, mocks.market, mocks.structures, mocks.scanner, mocks.pnl, mocks.notifier, mocks.analytics)
+	controller := controllers.NewHaulingRuns(router, mocks.runs, mocks.items, mocks.stops, mocks.market, mocks.structures, mocks.scanner, mocks.pnl, mocks.notifier, mocks.analytics, mocks.routes, mocks.systems, mocks.profiles)
 	return controller, mocks
 }
 
@@ -398,6 +462,7 @@ func Test_HaulingRuns_GetRun_Success(t *testing.T) {
 
 	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
 	mocks.items.On("GetItemsByRunID", mock.Anything, int64(5)).Return(items, nil)
+	mocks.stops.On("GetStopsByRunID", mock.Anything, int64(5)).Return([]*models.HaulingRunStop{}, nil)
 
 	req := httptest.NewRequest("GET", "/v1/hauling/runs/5", nil)
 	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
@@ -582,6 +647,7 @@ func Test_HaulingRuns_AddItem_Success(t *testing.T) {
 	createdItem := &models.HaulingRunItem{ID: int64(1), RunID: int64(5), TypeID: int64(34), TypeName: "Tritanium", QuantityPlanned: int64(100)}
 
 	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+	mocks.stops.On("GetStopsByRunID", mock.Anything, int64(5)).Return([]*models.HaulingRunStop{}, nil)
 	mocks.items.On("AddItem", mock.Anything, mock.AnythingOfType("*models.HaulingRunItem")).Return(createdItem, nil)
 
 	body, _ := json.Marshal(models.HaulingRunItem{TypeID: int64(34), TypeName: "Tritanium", QuantityPlanned: int64(100)})
@@ -613,6 +679,156 @@ func Test_HaulingRuns_AddItem_RunNotFound(t *testing.T) {
 	assert.Equal(t, 404, httpErr.StatusCode)
 }
 
+// --- Tests: OptimizeCargo ---
+
+func optimizeRows() []*models.HaulingArbitrageRow {
+	tritBuy, tritSell, tritVol := 5.0, 7.0, 0.01
+	pyeBuy, pyeSell, pyeVol := 10.0, 14.0, 0.01
+	tritAvail, pyeAvail := int64(1000000), int64(1000000)
+	return []*models.HaulingArbitrageRow{
+		{TypeID: 34, TypeName: "Tritanium", BuyPrice: &tritBuy, SellPrice: &tritSell, VolumeM3: &tritVol, VolumeAvailable: &tritAvail},
+		{TypeID: 35, TypeName: "Pyerite", BuyPrice: &pyeBuy, SellPrice: &pyeSell, VolumeM3: &pyeVol, VolumeAvailable: &pyeAvail},
+	}
+}
+
+func Test_HaulingRuns_OptimizeCargo_Success(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	maxVolume := 1000.0
+	run := &models.HaulingRun{ID: int64(5), UserID: userID, Status: "PLANNING", MaxVolumeM3: &maxVolume}
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+	mocks.items.On("GetItemsByRunID", mock.Anything, int64(5)).Return([]*models.HaulingRunItem{}, nil)
+	addItemsEcho(mocks.items, mock.AnythingOfType("[]*models.HaulingRunItem"))
+
+	body, _ := json.Marshal(models.HaulingCargoOptimizeRequest{
+		Rows:       optimizeRows(),
+		CapitalISK: 600000,
+		Fees:       &models.HaulingFeesProfile{},
+	})
+	req := httptest.NewRequest("POST", "/v1/hauling/runs/5/optimize", bytes.NewReader(body))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	result, httpErr := controller.OptimizeCargo(args)
+	assert.Nil(t, httpErr)
+	plan := result.(*models.HaulingCargoPlan)
+	// Pyerite earns 400 ISK/m³ against Tritanium's 200, and capital caps it at
+	// 60,000 units (240,000 profit). Filling with Tritanium first would use all
+	// 1,000 m³ for only 200,000.
+	assert.Len(t, plan.Items, 1)
+	assert.Equal(t, int64(35), plan.Items[0].TypeID)
+	assert.Equal(t, int64(60000), plan.Items[0].QuantityPlanned)
+	assert.Equal(t, int64(5), plan.Items[0].RunID)
+	assert.InDelta(t, 240000.0, plan.ExpectedProfitISK, 0.0001)
+	assert.InDelta(t, 600000.0, plan.TotalCostISK, 0.0001)
+	assert.InDelta(t, 400.0, plan.RemainingVolumeM3, 0.0001)
+	mocks.items.AssertExpectations(t)
+}
+
+// withTradeFees wires skill-based fees: one character with Accounting 5 and
+// Broker Relations 5, and a trading structure with a 1% broker rate.
+func withTradeFees(controller *controllers.HaulingRunsController, userID int64) {
+	skills := new(MockIndustryCharacterSkillsRepository)
+	skills.On("GetSkillsForUser", mock.Anything, userID).Return([]*models.CharacterSkill{
+		{CharacterID: 2001001, SkillID: calculator.SkillAccounting, ActiveLevel: 5},
+		{CharacterID: 2001001, SkillID: calculator.SkillBrokerRelations, ActiveLevel: 5},
+	}, nil)
+	brokerFee := 1.0
+	structures := new(MockUserTradingStructuresRepository)
+	structures.On("List", mock.Anything, userID).Return([]*models.UserTradingStructure{
+		{StructureID: 1035466617946, BrokerFeePct: &brokerFee},
+	}, nil)
+	controller.WithTradeFees(skills, structures)
+}
+
+func Test_HaulingRuns_OptimizeCargo_DefaultFeesFromSkills(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+	withTradeFees(controller, userID)
+
+	maxVolume := 1000.0
+	toStation := int64(1035466617946)
+	run := &models.HaulingRun{ID: int64(5), UserID: userID, Status: "PLANNING", MaxVolumeM3: &maxVolume, ToStationID: &toStation}
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+	mocks.items.On("GetItemsByRunID", mock.Anything, int64(5)).Return([]*models.HaulingRunItem{}, nil)
+	addItemsEcho(mocks.items, mock.AnythingOfType("[]*models.HaulingRunItem"))
+
+	body, _ := json.Marshal(models.HaulingCargoOptimizeRequest{
+		Rows:       optimizeRows(),
+		CapitalISK: 600000,
+	})
+	req := httptest.NewRequest("POST", "/v1/hauling/runs/5/optimize", bytes.NewReader(body))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	result, httpErr := controller.OptimizeCargo(args)
+	assert.Nil(t, httpErr)
+	plan := result.(*models.HaulingCargoPlan)
+	// 3.375% sales tax and the structure's 1% broker fee: Pyerite nets
+	// 14 × 0.95625 - 10 = 3.3875 per unit over 60,000 units
+	assert.Len(t, plan.Items, 1)
+	assert.Equal(t, int64(60000), plan.Items[0].QuantityPlanned)
+	assert.InDelta(t, 203250.0, plan.ExpectedProfitISK, 0.01)
+}
+
+func Test_HaulingRuns_OptimizeCargo_SkipsTypesOnRunAndCountsTheirVolume(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	maxVolume := 1000.0
+	run := &models.HaulingRun{ID: int64(5), UserID: userID, Status: "PLANNING", MaxVolumeM3: &maxVolume}
+	existingVol := 0.01
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+	mocks.items.On("GetItemsByRunID", mock.Anything, int64(5)).Return([]*models.HaulingRunItem{
+		{ID: 1, RunID: 5, TypeID: 35, QuantityPlanned: 60000, VolumeM3: &existingVol},
+	}, nil)
+	addItemsEcho(mocks.items, mock.MatchedBy(func(items []*models.HaulingRunItem) bool {
+		// Only Tritanium, limited to the 400 m³ left
+		return len(items) == 1 && items[0].TypeID == 34 && items[0].QuantityPlanned == 40000
+	}))
+
+	body, _ := json.Marshal(models.HaulingCargoOptimizeRequest{Rows: optimizeRows(), Fees: &models.HaulingFeesProfile{}})
+	req := httptest.NewRequest("POST", "/v1/hauling/runs/5/optimize", bytes.NewReader(body))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	result, httpErr := controller.OptimizeCargo(args)
+	assert.Nil(t, httpErr)
+	plan := result.(*models.HaulingCargoPlan)
+	assert.InDelta(t, 80000.0, plan.ExpectedProfitISK, 0.0001)
+	assert.InDelta(t, 0.0, plan.RemainingVolumeM3, 0.0001)
+	mocks.items.AssertExpectations(t)
+}
+
+func Test_HaulingRuns_OptimizeCargo_NoVolume(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	run := &models.HaulingRun{ID: int64(5), UserID: userID, Status: "PLANNING"}
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+
+	body, _ := json.Marshal(models.HaulingCargoOptimizeRequest{Rows: optimizeRows()})
+	req := httptest.NewRequest("POST", "/v1/hauling/runs/5/optimize", bytes.NewReader(body))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	result, httpErr := controller.OptimizeCargo(args)
+	assert.Nil(t, result)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
+func Test_HaulingRuns_OptimizeCargo_RunNotFound(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(nil, nil)
+
+	body, _ := json.Marshal(models.HaulingCargoOptimizeRequest{Rows: optimizeRows()})
+	req := httptest.NewRequest("POST", "/v1/hauling/runs/5/optimize", bytes.NewReader(body))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	result, httpErr := controller.OptimizeCargo(args)
+	assert.Nil(t, result)
+	assert.Equal(t, 404, httpErr.StatusCode)
+}
+
 // --- Tests: UpdateItemAcquired ---
 
 func Test_HaulingRuns_UpdateItemAcquired_Success(t *testing.T) {
@@ -695,6 +911,255 @@ func Test_HaulingRuns_RemoveItem_RunNotFound(t *testing.T) {
 	assert.Equal(t, 404, httpErr.StatusCode)
 }
 
+// --- Tests: Stops and legs ---
+
+func haulingTestStops() []*models.HaulingRunStop {
+	return []*models.HaulingRunStop{
+		{ID: int64(11), RunID: int64(5), Seq: 0, RegionID: int64(10000002)},
+		{ID: int64(12), RunID: int64(5), Seq: 1, RegionID: int64(10000043)},
+		{ID: int64(13), RunID: int64(5), Seq: 2, RegionID: int64(10000032)},
+	}
+}
+
+func Test_HaulingRuns_GetRun_IncludesStops(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	run := &models.HaulingRun{ID: int64(5), Name: "Loop", Status: "PLANNING"}
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+	mocks.items.On("GetItemsByRunID", mock.Anything, int64(5)).Return([]*models.HaulingRunItem{}, nil)
+	mocks.stops.On("GetStopsByRunID", mock.Anything, int64(5)).Return(haulingTestStops(), nil)
+
+	req := httptest.NewRequest("GET", "/v1/hauling/runs/5", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	result, httpErr := controller.GetRun(args)
+	assert.Nil(t, httpErr)
+	assert.Len(t, result.(*models.HaulingRun).Stops, 3)
+}
+
+func Test_HaulingRuns_ReplaceStops_Success(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	run := &models.HaulingRun{ID: int64(5), UserID: userID, Status: "PLANNING"}
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+	mocks.stops.On("GetStopsByRunID", mock.Anything, int64(5)).Return(haulingTestStops()[:2], nil)
+	mocks.items.On("GetItemsByRunID", mock.Anything, int64(5)).Return([]*models.HaulingRunItem{}, nil)
+	mocks.stops.On("ReplaceStops", mock.Anything, int64(5), mock.MatchedBy(func(stops []*models.HaulingRunStop) bool {
+		return len(stops) == 3 && stops[0].ID == 12 && stops[1].ID == 11 && stops[2].ID == 0
+	})).Return(haulingTestStops(), nil)
+
+	body := `[{"id":12,"regionId":10000043},{"id":11,"regionId":10000002},{"regionId":10000032}]`
+	req := httptest.NewRequest("PUT", "/v1/hauling/runs/5/stops", bytes.NewReader([]byte(body)))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	result, httpErr := controller.ReplaceStops(args)
+	assert.Nil(t, httpErr)
+	assert.Len(t, result.([]*models.HaulingRunStop), 3)
+	mocks.stops.AssertExpectations(t)
+}
+
+func Test_HaulingRuns_ReplaceStops_SingleStop(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	run := &models.HaulingRun{ID: int64(5), UserID: userID, Status: "PLANNING"}
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+
+	req := httptest.NewRequest("PUT", "/v1/hauling/runs/5/stops", bytes.NewReader([]byte(`[{"regionId":10000002}]`)))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	result, httpErr := controller.ReplaceStops(args)
+	assert.Nil(t, result)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mocks.stops.AssertNotCalled(t, "ReplaceStops", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_HaulingRuns_ReplaceStops_UnknownStop(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	run := &models.HaulingRun{ID: int64(5), UserID: userID, Status: "PLANNING"}
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+	mocks.stops.On("GetStopsByRunID", mock.Anything, int64(5)).Return(haulingTestStops(), nil)
+
+	body := `[{"id":11,"regionId":10000002},{"id":99,"regionId":10000043}]`
+	req := httptest.NewRequest("PUT", "/v1/hauling/runs/5/stops", bytes.NewReader([]byte(body)))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	result, httpErr := controller.ReplaceStops(args)
+	assert.Nil(t, result)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	assert.Contains(t, httpErr.Error.Error(), "stop 2 is not on the run")
+}
+
+func Test_HaulingRuns_ReplaceStops_ReorderBreaksItem(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	run := &models.HaulingRun{ID: int64(5), UserID: userID, Status: "PLANNING"}
+	buy, sell := int64(11), int64(12)
+	items := []*models.HaulingRunItem{{ID: int64(1), TypeID: int64(34), TypeName: "Tritanium", BuyStopID: &buy, SellStopID: &sell}}
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+	mocks.stops.On("GetStopsByRunID", mock.Anything, int64(5)).Return(haulingTestStops()[:2], nil)
+	mocks.items.On("GetItemsByRunID", mock.Anything, int64(5)).Return(items, nil)
+
+	body := `[{"id":12,"regionId":10000043},{"id":11,"regionId":10000002}]`
+	req := httptest.NewRequest("PUT", "/v1/hauling/runs/5/stops", bytes.NewReader([]byte(body)))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	result, httpErr := controller.ReplaceStops(args)
+	assert.Nil(t, result)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	assert.Contains(t, httpErr.Error.Error(), "Tritanium: buy stop must come before sell stop")
+}
+
+func Test_HaulingRuns_UpdateItemStops_Success(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	maxVolume := 2000.0
+	volume := 10.0
+	run := &models.HaulingRun{ID: int64(5), UserID: userID, Status: "PLANNING", MaxVolumeM3: &maxVolume}
+	items := []*models.HaulingRunItem{{ID: int64(1), TypeID: int64(34), QuantityPlanned: int64(100), VolumeM3: &volume}}
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+	mocks.stops.On("GetStopsByRunID", mock.Anything, int64(5)).Return(haulingTestStops(), nil)
+	mocks.items.On("GetItemsByRunID", mock.Anything, int64(5)).Return(items, nil)
+	mocks.items.On("UpdateItemStops", mock.Anything, int64(1), int64(5), mock.Anything, mock.Anything).Return(nil)
+
+	req := httptest.NewRequest("PUT", "/v1/hauling/runs/5/items/1/stops", bytes.NewReader([]byte(`{"buyStopId":12,"sellStopId":13}`)))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5", "itemId": "1"}}
+
+	result, httpErr := controller.UpdateItemStops(args)
+	assert.Nil(t, result)
+	assert.Nil(t, httpErr)
+	mocks.items.AssertExpectations(t)
+	call := mocks.items.Calls[len(mocks.items.Calls)-1]
+	assert.Equal(t, int64(12), *call.Arguments.Get(3).(*int64))
+	assert.Equal(t, int64(13), *call.Arguments.Get(4).(*int64))
+}
+
+func Test_HaulingRuns_UpdateItemStops_SellBeforeBuy(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	run := &models.HaulingRun{ID: int64(5), UserID: userID, Status: "PLANNING"}
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+	mocks.stops.On("GetStopsByRunID", mock.Anything, int64(5)).Return(haulingTestStops(), nil)
+
+	req := httptest.NewRequest("PUT", "/v1/hauling/runs/5/items/1/stops", bytes.NewReader([]byte(`{"buyStopId":13,"sellStopId":11}`)))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5", "itemId": "1"}}
+
+	_, httpErr := controller.UpdateItemStops(args)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mocks.items.AssertNotCalled(t, "UpdateItemStops", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_HaulingRuns_UpdateItemStops_OverCapacity(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	maxVolume := 1000.0
+	volume := 8.0
+	buy := int64(12)
+	run := &models.HaulingRun{ID: int64(5), UserID: userID, Status: "PLANNING", MaxVolumeM3: &maxVolume}
+	items := []*models.HaulingRunItem{
+		{ID: int64(1), TypeID: int64(34), QuantityPlanned: int64(100), VolumeM3: &volume},
+		{ID: int64(2), TypeID: int64(35), QuantityPlanned: int64(50), VolumeM3: &volume, BuyStopID: &buy},
+	}
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+	mocks.stops.On("GetStopsByRunID", mock.Anything, int64(5)).Return(haulingTestStops(), nil)
+	mocks.items.On("GetItemsByRunID", mock.Anything, int64(5)).Return(items, nil)
+
+	// Item 1 rides all the way; item 2 joins at the second stop → 1200 m3 on leg 2.
+	req := httptest.NewRequest("PUT", "/v1/hauling/runs/5/items/1/stops", bytes.NewReader([]byte(`{"buyStopId":11,"sellStopId":13}`)))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5", "itemId": "1"}}
+
+	_, httpErr := controller.UpdateItemStops(args)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	assert.Contains(t, httpErr.Error.Error(), "leg 2 is over capacity")
+	mocks.items.AssertNotCalled(t, "UpdateItemStops", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_HaulingRuns_UpdateItemStops_ItemNotFound(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	run := &models.HaulingRun{ID: int64(5), UserID: userID, Status: "PLANNING"}
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+	mocks.stops.On("GetStopsByRunID", mock.Anything, int64(5)).Return(haulingTestStops(), nil)
+	mocks.items.On("GetItemsByRunID", mock.Anything, int64(5)).Return([]*models.HaulingRunItem{}, nil)
+
+	req := httptest.NewRequest("PUT", "/v1/hauling/runs/5/items/9/stops", bytes.NewReader([]byte(`{"sellStopId":12}`)))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5", "itemId": "9"}}
+
+	_, httpErr := controller.UpdateItemStops(args)
+	assert.Equal(t, 404, httpErr.StatusCode)
+}
+
+func Test_HaulingRuns_AddItem_StopNotOnRun(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	run := &models.HaulingRun{ID: int64(5), UserID: userID, Status: "PLANNING"}
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+	mocks.stops.On("GetStopsByRunID", mock.Anything, int64(5)).Return(haulingTestStops(), nil)
+
+	req := httptest.NewRequest("POST", "/v1/hauling/runs/5/items", bytes.NewReader([]byte(`{"typeId":34,"quantityPlanned":10,"buyStopId":99}`)))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	_, httpErr := controller.AddItem(args)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mocks.items.AssertNotCalled(t, "AddItem", mock.Anything, mock.Anything)
+}
+
+func Test_HaulingRuns_GetLegs_Success(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	volume, buyPrice, sellPrice := 10.0, 5.0, 7.0
+	sell := int64(12)
+	net := 150.0
+	run := &models.HaulingRun{ID: int64(5), UserID: userID, Status: "SELLING"}
+	items := []*models.HaulingRunItem{
+		{ID: int64(1), TypeID: int64(34), QuantityPlanned: int64(100), VolumeM3: &volume, BuyPriceISK: &buyPrice, SellPriceISK: &sellPrice, SellStopID: &sell},
+	}
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+	mocks.stops.On("GetStopsByRunID", mock.Anything, int64(5)).Return(haulingTestStops(), nil)
+	mocks.items.On("GetItemsByRunID", mock.Anything, int64(5)).Return(items, nil)
+	mocks.pnl.On("GetPnlByRunID", mock.Anything, int64(5)).Return([]*models.HaulingRunPnlEntry{{TypeID: int64(34), NetProfitISK: &net}}, nil)
+
+	req := httptest.NewRequest("GET", "/v1/hauling/runs/5/legs", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	result, httpErr := controller.GetLegs(args)
+	assert.Nil(t, httpErr)
+	legs := result.([]*models.HaulingRunLeg)
+	assert.Len(t, legs, 2)
+	assert.Equal(t, 1000.0, legs[0].LoadM3)
+	assert.Equal(t, 200.0, legs[0].PlannedProfitISK)
+	assert.Equal(t, 150.0, legs[0].NetProfitISK)
+	assert.Equal(t, 0.0, legs[1].LoadM3)
+}
+
+func Test_HaulingRuns_GetLegs_NoStops(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	run := &models.HaulingRun{ID: int64(5), UserID: userID, Status: "PLANNING"}
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+	mocks.stops.On("GetStopsByRunID", mock.Anything, int64(5)).Return([]*models.HaulingRunStop{}, nil)
+
+	req := httptest.NewRequest("GET", "/v1/hauling/runs/5/legs", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	result, httpErr := controller.GetLegs(args)
+	assert.Nil(t, httpErr)
+	assert.Empty(t, result.([]*models.HaulingRunLeg))
+	mocks.items.AssertNotCalled(t, "GetItemsByRunID", mock.Anything, mock.Anything)
+}
+
 // --- Tests: GetScannerResults ---
 
 func Test_HaulingRuns_GetScannerResults_Success(t *testing.T) {
@@ -718,6 +1183,13 @@ func Test_HaulingRuns_GetScannerResults_Success(t *testing.T) {
 	}
 
 	mocks.market.On("GetScannerResults", mock.Anything, int64(10000002), int64(0), int64(10000043)).Return(expectedRows, nil)
+	// Region-wide scan routes Jita -> Amarr
+	mocks.routes.On("GetRoute", mock.Anything, int64(30000142), int64(30002187), "shortest").Return([]int32{30000142, 30000144, 30002187}, nil)
+	mocks.systems.On("GetByIDs", mock.Anything, []int64{30000142, 30000144, 30002187}).Return([]*models.SolarSystem{
+		{ID: 30000142, Security: 0.95},
+		{ID: 30000144, Security: 0.6},
+		{ID: 30002187, Security: 1.0},
+	}, nil)
 
 	req := httptest.NewRequest("GET", "/v1/hauling/scanner?source_region_id=10000002&dest_region_id=10000043", nil)
 	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{}}
@@ -727,7 +1199,10 @@ func Test_HaulingRuns_GetScannerResults_Success(t *testing.T) {
 	assert.NotNil(t, result)
 	rows := result.([]*models.HaulingArbitrageRow)
 	assert.Len(t, rows, 1)
+	assert.Equal(t, 2, *rows[0].Jumps)
+	assert.Equal(t, 0.6, *rows[0].MinSecurity)
 	mocks.market.AssertExpectations(t)
+	mocks.routes.AssertExpectations(t)
 }
 
 func Test_HaulingRuns_GetScannerResults_WithSystem(t *testing.T) {
@@ -735,6 +1210,8 @@ func Test_HaulingRuns_GetScannerResults_WithSystem(t *testing.T) {
 	userID := int64(100)
 
 	mocks.market.On("GetScannerResults", mock.Anything, int64(10000002), int64(30000142), int64(10000043)).Return([]*models.HaulingArbitrageRow{}, nil)
+	mocks.routes.On("GetRoute", mock.Anything, int64(30000142), int64(30002187), "shortest").Return([]int32{30000142, 30002187}, nil)
+	mocks.systems.On("GetByIDs", mock.Anything, []int64{30000142, 30002187}).Return([]*models.SolarSystem{}, nil)
 
 	req := httptest.NewRequest("GET", "/v1/hauling/scanner?source_region_id=10000002&dest_region_id=10000043&source_system_id=30000142", nil)
 	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{}}
@@ -887,6 +1364,164 @@ func Test_HaulingRuns_TriggerScan_InvalidBody(t *testing.T) {
 	assert.Equal(t, 400, httpErr.StatusCode)
 }
 
+func scannerRouteRows() []*models.HaulingArbitrageRow {
+	// Tritanium: 2 ISK/unit over 0.01 m³; Rifter: 200k ISK/unit over 27,289 m³
+	tritNet, tritVol := 2.0, 0.01
+	rifterNet, rifterVol := 200000.0, 27289.0
+	tritAvail, rifterAvail := int64(100000000), int64(5)
+	return []*models.HaulingArbitrageRow{
+		{TypeID: 587, TypeName: "Rifter", NetProfitISK: &rifterNet, VolumeM3: &rifterVol, VolumeAvailable: &rifterAvail},
+		{TypeID: 34, TypeName: "Tritanium", NetProfitISK: &tritNet, VolumeM3: &tritVol, VolumeAvailable: &tritAvail},
+	}
+}
+
+func Test_HaulingRuns_GetScannerResults_RankedByIskPerM3PerJump(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	mocks.market.On("GetScannerResults", mock.Anything, int64(10000002), int64(0), int64(10000043)).Return(scannerRouteRows(), nil)
+	mocks.routes.On("GetRoute", mock.Anything, int64(30000142), int64(30002187), "shortest").Return([]int32{30000142, 30000144, 30002187}, nil)
+	mocks.systems.On("GetByIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return([]*models.SolarSystem{}, nil)
+
+	req := httptest.NewRequest("GET", "/v1/hauling/scanner?source_region_id=10000002&dest_region_id=10000043", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{}}
+
+	result, httpErr := controller.GetScannerResults(args)
+	assert.Nil(t, httpErr)
+	rows := result.([]*models.HaulingArbitrageRow)
+	assert.Len(t, rows, 2)
+	// Tritanium: 2 / 0.01 / 2 = 100 ISK/m³/jump beats Rifter's ~3.66
+	assert.Equal(t, "Tritanium", rows[0].TypeName)
+	assert.InDelta(t, 100.0, *rows[0].IskPerM3PerJump, 0.001)
+	// No ship profile, no ISK/hour
+	assert.Nil(t, rows[0].IskPerHour)
+}
+
+func Test_HaulingRuns_GetScannerResults_ProfileRanksByIskPerHour(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	mocks.market.On("GetScannerResults", mock.Anything, int64(10000002), int64(0), int64(10000043)).Return(scannerRouteRows(), nil)
+	mocks.profiles.On("GetByID", mock.Anything, int64(7), userID).Return(&models.TransportProfile{
+		ID: 7, TransportMethod: "blockade_runner", CargoM3: 10000, RoutePreference: "safer",
+	}, nil)
+	mocks.routes.On("GetRoute", mock.Anything, int64(30000142), int64(30002187), "safer").Return([]int32{30000142, 30000144, 30002187}, nil)
+	mocks.systems.On("GetByIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return([]*models.SolarSystem{}, nil)
+
+	req := httptest.NewRequest("GET", "/v1/hauling/scanner?source_region_id=10000002&dest_region_id=10000043&transport_profile_id=7", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{}}
+
+	result, httpErr := controller.GetScannerResults(args)
+	assert.Nil(t, httpErr)
+	rows := result.([]*models.HaulingArbitrageRow)
+	// Rifters do not fit in 10,000 m³; Tritanium fills it with 1M units
+	assert.Equal(t, "Tritanium", rows[0].TypeName)
+	// 2,000,000 ISK per trip / ((2*2*45 + 600) / 3600 h)
+	assert.InDelta(t, 2000000.0/(780.0/3600.0), *rows[0].IskPerHour, 0.01)
+	assert.InDelta(t, 0.0, *rows[1].IskPerHour, 0.001)
+	mocks.routes.AssertExpectations(t)
+}
+
+func Test_HaulingRuns_GetScannerResults_HighsecOnlyDropsLowsecRoute(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	mocks.market.On("GetScannerResults", mock.Anything, int64(10000002), int64(0), int64(10000043)).Return(scannerRouteRows(), nil)
+	mocks.routes.On("GetRoute", mock.Anything, int64(30000142), int64(30002187), "secure").Return([]int32{30000142, 30000144, 30002187}, nil)
+	mocks.systems.On("GetByIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return([]*models.SolarSystem{
+		{ID: 30000144, Security: 0.4},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/v1/hauling/scanner?source_region_id=10000002&dest_region_id=10000043&highsec_only=true", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{}}
+
+	result, httpErr := controller.GetScannerResults(args)
+	assert.Nil(t, httpErr)
+	assert.Len(t, result.([]*models.HaulingArbitrageRow), 0)
+	mocks.routes.AssertExpectations(t)
+}
+
+func Test_HaulingRuns_GetScannerResults_MaxJumps(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	mocks.market.On("GetScannerResults", mock.Anything, int64(10000002), int64(0), int64(10000043)).Return(scannerRouteRows(), nil)
+	mocks.routes.On("GetRoute", mock.Anything, int64(30000142), int64(30002187), "shortest").Return([]int32{30000142, 30000144, 30002187}, nil)
+	mocks.systems.On("GetByIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return([]*models.SolarSystem{}, nil)
+
+	req := httptest.NewRequest("GET", "/v1/hauling/scanner?source_region_id=10000002&dest_region_id=10000043&max_jumps=1", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{}}
+
+	result, httpErr := controller.GetScannerResults(args)
+	assert.Nil(t, httpErr)
+	assert.Len(t, result.([]*models.HaulingArbitrageRow), 0)
+}
+
+func Test_HaulingRuns_GetScannerResults_RouteErrorReturnsUnranked(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	mocks.market.On("GetScannerResults", mock.Anything, int64(10000002), int64(0), int64(10000043)).Return(scannerRouteRows(), nil)
+	mocks.routes.On("GetRoute", mock.Anything, int64(30000142), int64(30002187), "shortest").Return(nil, errors.New("esi down"))
+
+	req := httptest.NewRequest("GET", "/v1/hauling/scanner?source_region_id=10000002&dest_region_id=10000043", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{}}
+
+	result, httpErr := controller.GetScannerResults(args)
+	assert.Nil(t, httpErr)
+	rows := result.([]*models.HaulingArbitrageRow)
+	assert.Equal(t, "Rifter", rows[0].TypeName)
+	assert.Nil(t, rows[0].Jumps)
+}
+
+func Test_HaulingRuns_GetScannerResults_NetOfTradeFees(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+	withTradeFees(controller, userID)
+
+	buy, sell, vol := 100.0, 200.0, 1.0
+	mocks.market.On("GetScannerResults", mock.Anything, int64(10000002), int64(0), int64(10000043)).Return([]*models.HaulingArbitrageRow{
+		{TypeID: 34, TypeName: "Tritanium", BuyPrice: &buy, SellPrice: &sell, VolumeM3: &vol},
+	}, nil)
+	mocks.routes.On("GetRoute", mock.Anything, int64(30000142), int64(30002187), "shortest").Return(nil, errors.New("esi down"))
+
+	req := httptest.NewRequest("GET", "/v1/hauling/scanner?source_region_id=10000002&dest_region_id=10000043", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{}}
+
+	result, httpErr := controller.GetScannerResults(args)
+	assert.Nil(t, httpErr)
+	rows := result.([]*models.HaulingArbitrageRow)
+	// NPC hub: 3.375% sales tax and 1.5% broker fee on the 200 ISK sale
+	assert.InDelta(t, 90.25, *rows[0].NetProfitISK, 0.0001)
+}
+
+func Test_HaulingRuns_GetScannerResults_RouteErrorWithFilter(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	mocks.market.On("GetScannerResults", mock.Anything, int64(10000002), int64(0), int64(10000043)).Return(scannerRouteRows(), nil)
+	mocks.routes.On("GetRoute", mock.Anything, int64(30000142), int64(30002187), "shortest").Return(nil, errors.New("esi down"))
+
+	req := httptest.NewRequest("GET", "/v1/hauling/scanner?source_region_id=10000002&dest_region_id=10000043&max_jumps=10", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{}}
+
+	result, httpErr := controller.GetScannerResults(args)
+	assert.Nil(t, result)
+	assert.Equal(t, 500, httpErr.StatusCode)
+}
+
+func Test_HaulingRuns_GetScannerResults_InvalidMaxJumps(t *testing.T) {
+	controller, _ := setupHaulingController()
+	userID := int64(100)
+
+	req := httptest.NewRequest("GET", "/v1/hauling/scanner?source_region_id=10000002&dest_region_id=10000043&max_jumps=abc", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{}}
+
+	result, httpErr := controller.GetScannerResults(args)
+	assert.Nil(t, result)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
 // --- Tests: GetScannerResults — structure variants ---
 
 func Test_HaulingRuns_GetScannerResults_SourceStructure(t *testing.T) {
@@ -1238,6 +1873,34 @@ func Test_HaulingRuns_GetPnlSummary_Success(t *testing.T) {
 	mocks.pnl.AssertExpectations(t)
 }
 
+func Test_HaulingRuns_GetPnlSummary_AppliesTradeFees(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+	withTradeFees(controller, userID)
+
+	toStation := int64(1035466617946)
+	run := &models.HaulingRun{ID: int64(5), UserID: userID, Status: "SELLING", ToStationID: &toStation}
+	summary := &models.HaulingRunPnlSummary{
+		TotalRevenueISK: 150000.0,
+		TotalCostISK:    100000.0,
+		NetProfitISK:    50000.0,
+		ItemsSold:       int64(2),
+	}
+
+	mocks.runs.On("GetRunByID", mock.Anything, int64(5), userID).Return(run, nil)
+	mocks.pnl.On("GetPnlSummaryByRunID", mock.Anything, int64(5)).Return(summary, nil)
+
+	req := httptest.NewRequest("GET", "/v1/hauling/runs/5/pnl/summary", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	result, httpErr := controller.GetPnlSummary(args)
+	assert.Nil(t, httpErr)
+	s := result.(*models.HaulingRunPnlSummary)
+	assert.InDelta(t, 5062.5, s.SalesTaxISK, 0.01)
+	assert.InDelta(t, 1500.0, s.BrokerFeesISK, 0.01)
+	assert.InDelta(t, 43437.5, s.NetProfitISK, 0.01)
+}
+
 func Test_HaulingRuns_GetPnlSummary_RunNotFound(t *testing.T) {
 	controller, mocks := setupHaulingController()
 	userID := int64(100)
@@ -1340,6 +2003,42 @@ func Test_HaulingRuns_GetRouteAnalytics_Error(t *testing.T) {
 	assert.Equal(t, 500, httpErr.StatusCode)
 }
 
+// --- Tests: GetLegAnalytics ---
+
+func Test_HaulingRuns_GetLegAnalytics_Success(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	expected := []*models.HaulingLegAnalytics{
+		{FromRegionID: int64(10000002), ToRegionID: int64(10000043), TotalLegs: int64(4), TotalRuns: int64(3), TotalProfitISK: 250000.0},
+	}
+	mocks.analytics.On("GetLegAnalytics", mock.Anything, userID).Return(expected, nil)
+
+	req := httptest.NewRequest("GET", "/v1/hauling/analytics/legs", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{}}
+
+	result, httpErr := controller.GetLegAnalytics(args)
+	assert.Nil(t, httpErr)
+	rows := result.([]*models.HaulingLegAnalytics)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, int64(4), rows[0].TotalLegs)
+	mocks.analytics.AssertExpectations(t)
+}
+
+func Test_HaulingRuns_GetLegAnalytics_Error(t *testing.T) {
+	controller, mocks := setupHaulingController()
+	userID := int64(100)
+
+	mocks.analytics.On("GetLegAnalytics", mock.Anything, userID).Return(nil, errors.New("db error"))
+
+	req := httptest.NewRequest("GET", "/v1/hauling/analytics/legs", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{}}
+
+	result, httpErr := controller.GetLegAnalytics(args)
+	assert.Nil(t, result)
+	assert.Equal(t, 500, httpErr.StatusCode)
+}
+
 // --- Tests: GetItemAnalytics ---
 
 func Test_HaulingRuns_GetItemAnalytics_Success(t *testing.T) {
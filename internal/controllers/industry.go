@@ -51,6 +51,7 @@ type IndustryCostIndicesRepository interface {
 
 type IndustryBlueprintsRepository interface {
 	GetBlueprintLevels(ctx context.Context, userID int64, typeIDs []int64) (map[int64]*models.BlueprintLevel, error)
+	GetBlueprintLibrary(ctx context.Context, userID int64) ([]*models.BlueprintLibraryItem, error)
 }
 
 type Industry struct {
@@ -96,9 +97,10 @@ func NewIndustry(
 	router.RegisterRestAPIRoute("/v1/industry/queue/{id}/character", web.AuthAccessUser, c.ReassignQueueCharacter, "PUT")
 	router.RegisterRestAPIRoute("/v1/industry/character-slots", web.AuthAccessUser, c.GetCharacterSlots, "GET")
 	router.RegisterRestAPIRoute("/v1/industry/blueprint-levels", web.AuthAccessUser, c.GetBlueprintLevels, "POST")
+	router.RegisterRestAPIRoute("/v1/industry/blueprint-library", web.AuthAccessUser, c.GetBlueprintLibrary, "GET")
 
 	// Backend-scoped endpoints (no user required)
-	router.RegisterRestAPIRoute("/v1/industry/calculate", web.AuthAccessBackend, c.Calculate, "POST")
+	router.RegisterRestAPIRoute("/v1/industry/calculate", web.AuthAccessUser, c.Calculate, "POST")
 	router.RegisterRestAPIRoute("/v1/industry/blueprints", web.AuthAccessBackend, c.SearchBlueprints, "GET")
 	router.RegisterRestAPIRoute("/v1/industry/systems", web.AuthAccessBackend, c.GetSystems, "GET")
 
@@ -173,7 +175,7 @@ func (c *Industry) CreateQueueEntry(args *web.HandlerArgs) (any, *web.HttpError)
 	if req.Activity == "manufacturing" {
 		calcResult, httpErr := c.calculateForBlueprint(ctx, req.BlueprintTypeID, req.Runs, req.MELevel, req.TELevel,
 			req.IndustrySkill, req.AdvIndustrySkill, req.SystemID, req.FacilityTax,
-			withDefault(req.Structure, "station"), withDefault(req.Rig, "none"), withDefault(req.Security, "high"))
+			withDefault(req.Structure, "station"), withDefault(req.Rig, "none"), withDefault(req.Security, "high"), nil)
 		if httpErr != nil {
 			// Non-fatal: still create the entry without estimates
 			estimatedCost = nil
@@ -235,7 +237,7 @@ func (c *Industry) UpdateQueueEntry(args *web.HandlerArgs) (any, *web.HttpError)
 	if req.Activity == "manufacturing" {
 		calcResult, httpErr := c.calculateForBlueprint(ctx, req.BlueprintTypeID, req.Runs, req.MELevel, req.TELevel,
 			req.IndustrySkill, req.AdvIndustrySkill, req.SystemID, req.FacilityTax,
-			withDefault(req.Structure, "station"), withDefault(req.Rig, "none"), withDefault(req.Security, "high"))
+			withDefault(req.Structure, "station"), withDefault(req.Rig, "none"), withDefault(req.Security, "high"), nil)
 		if httpErr == nil {
 			estimatedCost = &calcResult.TotalCost
 			estimatedDuration = &calcResult.TotalDuration
@@ -340,6 +342,7 @@ type calculateRequest struct {
 	Structure        string  `json:"structure"`
 	Rig              string  `json:"rig"`
 	Security         string  `json:"security"`
+	CharacterID      *int64  `json:"character_id"` // sells the output; its skills set sales tax and broker fee
 }
 
 func (c *Industry) Calculate(args *web.HandlerArgs) (any, *web.HttpError) {
@@ -357,9 +360,18 @@ func (c *Industry) Calculate(args *web.HandlerArgs) (any, *web.HttpError) {
 		req.Runs = 1
 	}
 
+	var fees *models.TradeFees
+	if req.CharacterID != nil {
+		var httpErr *web.HttpError
+		fees, httpErr = c.characterTradeFees(ctx, *args.User, *req.CharacterID)
+		if httpErr != nil {
+			return nil, httpErr
+		}
+	}
+
 	result, httpErr := c.calculateForBlueprint(ctx, req.BlueprintTypeID, req.Runs, req.MELevel, req.TELevel,
 		req.IndustrySkill, req.AdvIndustrySkill, req.SystemID, req.FacilityTax,
-		withDefault(req.Structure, "station"), withDefault(req.Rig, "none"), withDefault(req.Security, "high"))
+		withDefault(req.Structure, "station"), withDefault(req.Rig, "none"), withDefault(req.Security, "high"), fees)
 	if httpErr != nil {
 		return nil, httpErr
 	}
@@ -405,7 +417,30 @@ func (c *Industry) GetSystems(args *web.HandlerArgs) (any, *web.HttpError) {
 	return systems, nil
 }
 
+// characterTradeFees returns the NPC station sell-order fees of one of the user's characters.
+func (c *Industry) characterTradeFees(ctx context.Context, userID, characterID int64) (*models.TradeFees, *web.HttpError) {
+	names, err := c.characterRepo.GetNames(ctx, userID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get character names")}
+	}
+	name, ok := names[characterID]
+	if !ok {
+		return nil, &web.HttpError{StatusCode: 404, Error: errors.New("character not found")}
+	}
+
+	skills, err := c.skillsRepo.GetSkillsForUser(ctx, userID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get character skills")}
+	}
+
+	fees := calculator.ComputeTradeFees(calculator.TradeSkillsByCharacter(skills)[characterID], nil, nil)
+	fees.CharacterID = characterID
+	fees.CharacterName = name
+	return fees, nil
+}
+
 // calculateForBlueprint performs the full manufacturing calculation for a given blueprint.
+// Profit is net of sales tax and broker fee when fees is set.
 func (c *Industry) calculateForBlueprint(
 	ctx context.Context,
 	blueprintTypeID int64,
@@ -413,6 +448,7 @@ func (c *Industry) calculateForBlueprint(
 	systemID *int64,
 	facilityTax float64,
 	structure, rig, security string,
+	fees *models.TradeFees,
 ) (*models.ManufacturingCalcResult, *web.HttpError) {
 	blueprint, err := c.sdeRepo.GetManufacturingBlueprint(ctx, blueprintTypeID)
 	if err != nil {
@@ -462,6 +498,10 @@ func (c *Industry) calculateForBlueprint(
 	if systemID != nil {
 		params.SystemID = *systemID
 	}
+	if fees != nil {
+		params.SalesTaxPct = fees.SalesTaxPct
+		params.BrokerFeePct = fees.BrokerFeePct
+	}
 
 	data := &calculator.ManufacturingData{
 		Blueprint:      blueprint,
@@ -491,6 +531,75 @@ func (c *Industry) GetBlueprintLevels(args *web.HandlerArgs) (any, *web.HttpErro
 	return levels, nil
 }
 
+// GetBlueprintLibrary returns every owned blueprint grouped by product, with research,
+// duplicate and plan-usage flags, valued by the manufacturing profit each blueprint enables.
+func (c *Industry) GetBlueprintLibrary(args *web.HandlerArgs) (any, *web.HttpError) {
+	ctx := args.Request.Context()
+
+	items, err := c.blueprintsRepo.GetBlueprintLibrary(ctx, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get blueprint library")}
+	}
+
+	library := calculator.BuildBlueprintLibrary(items)
+
+	hasManufacturing := false
+	for _, group := range library.Groups {
+		if group.Activity != nil && *group.Activity == "manufacturing" {
+			hasManufacturing = true
+			break
+		}
+	}
+	if !hasManufacturing {
+		return library, nil
+	}
+
+	jitaPrices, err := c.marketRepo.GetAllJitaPrices(ctx)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get Jita prices")}
+	}
+
+	adjustedPrices, err := c.marketRepo.GetAllAdjustedPrices(ctx)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get adjusted prices")}
+	}
+
+	// Value products as sold by the user's character with the lowest fees
+	skills, err := c.skillsRepo.GetSkillsForUser(ctx, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get character skills")}
+	}
+	fees := calculator.BestTradeFees(calculator.TradeSkillsByCharacter(skills), nil, nil)
+
+	for _, group := range library.Groups {
+		if group.Activity == nil || *group.Activity != "manufacturing" {
+			continue
+		}
+
+		blueprint, err := c.sdeRepo.GetManufacturingBlueprint(ctx, group.BlueprintTypeID)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get blueprint")}
+		}
+		if blueprint == nil {
+			continue
+		}
+
+		materials, err := c.sdeRepo.GetManufacturingMaterials(ctx, group.BlueprintTypeID)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get materials")}
+		}
+
+		calculator.ValueBlueprintGroup(group, &calculator.ManufacturingData{
+			Blueprint:      blueprint,
+			Materials:      materials,
+			AdjustedPrices: adjustedPrices,
+			JitaPrices:     jitaPrices,
+		}, fees)
+	}
+
+	return library, nil
+}
+
 // characterSlotsResponse is the JSON response shape for GetCharacterSlots.
 type characterSlotsResponse struct {
 	CharacterID      int64  `json:"characterId"`
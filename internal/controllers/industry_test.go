@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/annymsMthd/industry-tool/internal/calculator"
 	"github.com/annymsMthd/industry-tool/internal/controllers"
 	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/repositories"
@@ -188,6 +189,14 @@ func (m *MockIndustryBlueprintsRepository) GetBlueprintLevels(ctx context.Contex
 	return args.Get(0).(map[int64]*models.BlueprintLevel), args.Error(1)
 }
 
+func (m *MockIndustryBlueprintsRepository) GetBlueprintLibrary(ctx context.Context, userID int64) ([]*models.BlueprintLibraryItem, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.BlueprintLibraryItem), args.Error(1)
+}
+
 // --- Helper to create controller with mocks ---
 
 type industryMocks struct {
@@ -722,6 +731,71 @@ func Test_IndustryController_Calculate_Success(t *testing.T) {
 	mocks.costIndicesRepo.AssertExpectations(t)
 }
 
+func Test_IndustryController_Calculate_CharacterFees(t *testing.T) {
+	controller, mocks := setupIndustryController()
+
+	userID := int64(100)
+	characterID := int64(2001001)
+	bodyBytes, _ := json.Marshal(map[string]any{
+		"blueprint_type_id": 787,
+		"runs":              2,
+		"character_id":      characterID,
+	})
+
+	productPrice := 1000000.0
+	blueprint := &repositories.ManufacturingBlueprintRow{
+		BlueprintTypeID: 787,
+		ProductTypeID:   587,
+		ProductName:     "Rifter",
+		ProductQuantity: 1,
+		Time:            3600,
+	}
+
+	mocks.characterRepo.On("GetNames", mock.Anything, userID).Return(map[int64]string{characterID: "Trader"}, nil)
+	mocks.skillsRepo.On("GetSkillsForUser", mock.Anything, userID).Return([]*models.CharacterSkill{
+		{CharacterID: characterID, SkillID: calculator.SkillAccounting, ActiveLevel: 5},
+		{CharacterID: characterID, SkillID: calculator.SkillBrokerRelations, ActiveLevel: 5},
+	}, nil)
+	mocks.sdeRepo.On("GetManufacturingBlueprint", mock.Anything, int64(787)).Return(blueprint, nil)
+	mocks.sdeRepo.On("GetManufacturingMaterials", mock.Anything, int64(787)).Return([]*repositories.ManufacturingMaterialRow{}, nil)
+	mocks.marketRepo.On("GetAllJitaPrices", mock.Anything).Return(map[int64]*models.MarketPrice{
+		587: {TypeID: 587, SellPrice: &productPrice},
+	}, nil)
+	mocks.marketRepo.On("GetAllAdjustedPrices", mock.Anything).Return(map[int64]float64{}, nil)
+
+	req := httptest.NewRequest("POST", "/v1/industry/calculate", bytes.NewReader(bodyBytes))
+	args := &web.HandlerArgs{Request: req, User: &userID}
+
+	result, httpErr := controller.Calculate(args)
+
+	assert.Nil(t, httpErr)
+	calcResult := result.(*models.ManufacturingCalcResult)
+	assert.InDelta(t, 67500.0, calcResult.SalesTax, 0.01)
+	assert.InDelta(t, 30000.0, calcResult.BrokerFee, 0.01)
+	assert.InDelta(t, 1902500.0, calcResult.Profit, 0.01)
+}
+
+func Test_IndustryController_Calculate_CharacterNotFound(t *testing.T) {
+	controller, mocks := setupIndustryController()
+
+	userID := int64(100)
+	bodyBytes, _ := json.Marshal(map[string]any{
+		"blueprint_type_id": 787,
+		"character_id":      999,
+	})
+
+	mocks.characterRepo.On("GetNames", mock.Anything, userID).Return(map[int64]string{2001001: "Trader"}, nil)
+
+	req := httptest.NewRequest("POST", "/v1/industry/calculate", bytes.NewReader(bodyBytes))
+	args := &web.HandlerArgs{Request: req, User: &userID}
+
+	result, httpErr := controller.Calculate(args)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+}
+
 func Test_IndustryController_Calculate_InvalidBody(t *testing.T) {
 	controller, _ := setupIndustryController()
 
@@ -1334,3 +1408,102 @@ func Test_IndustryController_GetBlueprintLevels_RepoError(t *testing.T) {
 	assert.Equal(t, 500, httpErr.StatusCode)
 	mocks.blueprintsRepo.AssertExpectations(t)
 }
+
+// --- GetBlueprintLibrary Tests ---
+
+func Test_IndustryController_GetBlueprintLibrary_ValuesManufacturingGroups(t *testing.T) {
+	controller, mocks := setupIndustryController()
+
+	userID := int64(100)
+	productID := int64(587)
+	productName := "Rifter"
+	manufacturing := "manufacturing"
+	items := []*models.BlueprintLibraryItem{
+		{ItemID: 1, BlueprintTypeID: 787, BlueprintName: "Rifter Blueprint", ProductTypeID: &productID, ProductName: &productName, Activity: &manufacturing, Quantity: -1, Runs: -1},
+		{ItemID: 2, BlueprintTypeID: 787, BlueprintName: "Rifter Blueprint", ProductTypeID: &productID, ProductName: &productName, Activity: &manufacturing, Quantity: -2, IsCopy: true, MaterialEfficiency: 10, TimeEfficiency: 20, Runs: 10},
+	}
+
+	sellPrice := 500000.0
+	mineralPrice := 5.0
+	blueprint := &repositories.ManufacturingBlueprintRow{
+		BlueprintTypeID: 787,
+		ProductTypeID:   587,
+		ProductName:     "Rifter",
+		ProductQuantity: 1,
+		Time:            3600,
+	}
+	materials := []*repositories.ManufacturingMaterialRow{
+		{BlueprintTypeID: 787, TypeID: 34, TypeName: "Tritanium", Quantity: 22000},
+	}
+
+	mocks.blueprintsRepo.On("GetBlueprintLibrary", mock.Anything, userID).Return(items, nil)
+	mocks.marketRepo.On("GetAllJitaPrices", mock.Anything).Return(map[int64]*models.MarketPrice{
+		34:  {TypeID: 34, SellPrice: &mineralPrice},
+		587: {TypeID: 587, SellPrice: &sellPrice},
+	}, nil)
+	mocks.marketRepo.On("GetAllAdjustedPrices", mock.Anything).Return(map[int64]float64{}, nil)
+	mocks.sdeRepo.On("GetManufacturingBlueprint", mock.Anything, int64(787)).Return(blueprint, nil)
+	mocks.sdeRepo.On("GetManufacturingMaterials", mock.Anything, int64(787)).Return(materials, nil)
+	mocks.skillsRepo.On("GetSkillsForUser", mock.Anything, userID).Return([]*models.CharacterSkill{
+		{CharacterID: 2001001, SkillID: calculator.SkillAccounting, ActiveLevel: 5},
+		{CharacterID: 2001001, SkillID: calculator.SkillBrokerRelations, ActiveLevel: 5},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/v1/industry/blueprint-library", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID}
+
+	result, httpErr := controller.GetBlueprintLibrary(args)
+
+	assert.Nil(t, httpErr)
+	library := result.(*models.BlueprintLibrary)
+	assert.Len(t, library.Groups, 1)
+	group := library.Groups[0]
+	// BPC ME10: 19800 trit × 5 = 99,000 input; 500,000 output less 3.375% tax and 1.5% broker
+	assert.InDelta(t, 500000.0-99000.0-16875.0-7500.0, *group.ProfitPerRun, 0.01)
+	assert.Equal(t, 1, group.BPOCount)
+	assert.Equal(t, 1, group.BPCCount)
+	assert.True(t, group.HasUnresearchedBPO)
+	assert.True(t, group.HasUnusedBPCs)
+	assert.NotNil(t, group.ProfitPerRun)
+	assert.Equal(t, 1, library.Summary.UnresearchedBPOs)
+	mocks.blueprintsRepo.AssertExpectations(t)
+	mocks.sdeRepo.AssertExpectations(t)
+}
+
+func Test_IndustryController_GetBlueprintLibrary_SkipsPricingWithoutManufacturing(t *testing.T) {
+	controller, mocks := setupIndustryController()
+
+	userID := int64(100)
+	reaction := "reaction"
+	items := []*models.BlueprintLibraryItem{
+		{ItemID: 1, BlueprintTypeID: 46166, BlueprintName: "Fulleride Reaction Formula", Activity: &reaction, Quantity: -1, Runs: -1},
+	}
+	mocks.blueprintsRepo.On("GetBlueprintLibrary", mock.Anything, userID).Return(items, nil)
+
+	req := httptest.NewRequest("GET", "/v1/industry/blueprint-library", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID}
+
+	result, httpErr := controller.GetBlueprintLibrary(args)
+
+	assert.Nil(t, httpErr)
+	library := result.(*models.BlueprintLibrary)
+	assert.Len(t, library.Groups, 1)
+	assert.Nil(t, library.Groups[0].ProfitPerRun)
+	mocks.marketRepo.AssertNotCalled(t, "GetAllJitaPrices", mock.Anything)
+}
+
+func Test_IndustryController_GetBlueprintLibrary_RepositoryError(t *testing.T) {
+	controller, mocks := setupIndustryController()
+
+	userID := int64(100)
+	mocks.blueprintsRepo.On("GetBlueprintLibrary", mock.Anything, userID).Return(nil, errors.New("db error"))
+
+	req := httptest.NewRequest("GET", "/v1/industry/blueprint-library", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID}
+
+	result, httpErr := controller.GetBlueprintLibrary(args)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 500, httpErr.StatusCode)
+}
@@ -0,0 +1,247 @@
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/calculator"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+type InvoicesRepository interface {
+	Create(ctx context.Context, invoice *models.Invoice) error
+	GetForUser(ctx context.Context, userID int64) ([]*models.Invoice, error)
+	Cancel(ctx context.Context, invoiceID int64, issuerUserID int64) error
+}
+
+type InvoiceRentalsRepository interface {
+	GetAgreementsForUser(ctx context.Context, userID int64) ([]*models.JobSlotRentalAgreement, error)
+	GetByID(ctx context.Context, listingID int64) (*models.JobSlotRentalListing, error)
+}
+
+type InvoiceContactsRepository interface {
+	GetByUser(ctx context.Context, userID int64) ([]*models.Contact, error)
+}
+
+// defaultInvoiceTermDays is how long a payer has when no due date is given
+const defaultInvoiceTermDays = 7
+
+// invoiceReferenceAlphabet leaves out characters that are easy to mistype (0/O, 1/I)
+const invoiceReferenceAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+type Invoices struct {
+	repository         InvoicesRepository
+	rentalsRepository  InvoiceRentalsRepository
+	contactsRepository InvoiceContactsRepository
+}
+
+func NewInvoices(router Routerer, repository InvoicesRepository, rentalsRepository InvoiceRentalsRepository, contactsRepository InvoiceContactsRepository) *Invoices {
+	controller := &Invoices{
+		repository:         repository,
+		rentalsRepository:  rentalsRepository,
+		contactsRepository: contactsRepository,
+	}
+
+	router.RegisterRestAPIRoute("/v1/invoices", web.AuthAccessUser, controller.GetInvoices, "GET")
+	router.RegisterRestAPIRoute("/v1/invoices", web.AuthAccessUser, controller.CreateInvoice, "POST")
+	router.RegisterRestAPIRoute("/v1/invoices/{id}/cancel", web.AuthAccessUser, controller.CancelInvoice, "PUT")
+
+	return controller
+}
+
+// GetInvoices returns invoices the user issued or has to pay
+func (c *Invoices) GetInvoices(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	invoices, err := c.repository.GetForUser(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get invoices")}
+	}
+
+	return invoices, nil
+}
+
+// CreateInvoice issues an invoice. With an agreementId it bills the renter of
+// one of the user's job slot rental agreements, priced from the listing terms
+// unless an amount is given. Without one it bills a contact for any service.
+func (c *Invoices) CreateInvoice(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	userID := *args.User
+	ctx := args.Request.Context()
+
+	var req models.CreateInvoiceRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if req.Amount < 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("amount cannot be negative")}
+	}
+
+	invoice := &models.Invoice{
+		IssuerUserID: userID,
+		PayerUserID:  req.PayerUserID,
+		Amount:       req.Amount,
+		Description:  strings.TrimSpace(req.Description),
+		Status:       "unpaid",
+	}
+
+	if req.AgreementID != nil {
+		if httpErr := c.fillFromAgreement(ctx, userID, *req.AgreementID, invoice); httpErr != nil {
+			return nil, httpErr
+		}
+	} else {
+		if invoice.PayerUserID == 0 {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.New("payerUserId is required")}
+		}
+		if invoice.Amount == 0 {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.New("amount must be greater than 0")}
+		}
+		if invoice.Description == "" {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.New("description is required")}
+		}
+
+		isContact, err := c.isAcceptedContact(ctx, userID, invoice.PayerUserID)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get contacts")}
+		}
+		if !isContact {
+			return nil, &web.HttpError{StatusCode: 403, Error: errors.New("invoices can only be sent to contacts")}
+		}
+	}
+
+	if invoice.PayerUserID == userID {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("cannot invoice yourself")}
+	}
+	// Amounts are stored to the cent; anything smaller would round to 0
+	if invoice.Amount < 0.01 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("invoice amount must be at least 0.01 ISK")}
+	}
+
+	invoice.DueAt = time.Now().AddDate(0, 0, defaultInvoiceTermDays)
+	if req.DueAt != nil {
+		if !req.DueAt.After(time.Now()) {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.New("dueAt must be in the future")}
+		}
+		invoice.DueAt = *req.DueAt
+	}
+
+	reference, err := newInvoiceReference()
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to generate reference code")}
+	}
+	invoice.ReferenceCode = reference
+
+	if err := c.repository.Create(ctx, invoice); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to create invoice")}
+	}
+
+	return invoice, nil
+}
+
+func (c *Invoices) fillFromAgreement(ctx context.Context, userID, agreementID int64, invoice *models.Invoice) *web.HttpError {
+	agreements, err := c.rentalsRepository.GetAgreementsForUser(ctx, userID)
+	if err != nil {
+		return &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get agreements")}
+	}
+
+	var agreement *models.JobSlotRentalAgreement
+	for _, a := range agreements {
+		if a.ID == agreementID && a.OwnerUserID == userID {
+			agreement = a
+			break
+		}
+	}
+	if agreement == nil {
+		return &web.HttpError{StatusCode: 404, Error: errors.New("agreement not found")}
+	}
+
+	invoice.AgreementID = &agreement.ID
+	invoice.PayerUserID = agreement.RenterUserID
+
+	if invoice.Amount == 0 {
+		listing, err := c.rentalsRepository.GetByID(ctx, agreement.ListingID)
+		if err != nil {
+			return &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get listing")}
+		}
+		invoice.Amount = calculator.RentalInvoiceAmount(
+			listing.PriceAmount, listing.PricingUnit, agreement.Slots,
+			agreement.StartsAt, agreement.EndsAt, agreement.JobsRun)
+	}
+
+	if invoice.Description == "" {
+		invoice.Description = fmt.Sprintf("Job slot rental: %d %s slot(s) on %s, %s to %s",
+			agreement.Slots, agreement.ActivityType, agreement.CharacterName,
+			agreement.StartsAt.UTC().Format("2006-01-02"), agreement.EndsAt.UTC().Format("2006-01-02"))
+	}
+
+	return nil
+}
+
+func (c *Invoices) isAcceptedContact(ctx context.Context, userID, otherUserID int64) (bool, error) {
+	contacts, err := c.contactsRepository.GetByUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, contact := range contacts {
+		if contact.Status != "accepted" {
+			continue
+		}
+		if contact.RequesterUserID == otherUserID || contact.RecipientUserID == otherUserID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// CancelInvoice cancels an open invoice. Only the issuer can cancel.
+func (c *Invoices) CancelInvoice(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	invoiceID, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("invalid invoice ID")}
+	}
+
+	if err := c.repository.Cancel(args.Request.Context(), invoiceID, *args.User); err != nil {
+		if err.Error() == "invoice not found or not open" {
+			return nil, &web.HttpError{StatusCode: 404, Error: err}
+		}
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to cancel invoice")}
+	}
+
+	return nil, nil
+}
+
+// newInvoiceReference returns a short code like INV-7KQ2MX for the payer to
+// put in the reason of their ISK transfer
+func newInvoiceReference() (string, error) {
+	var b strings.Builder
+	b.WriteString(calculator.InvoiceReferencePrefix)
+	max := big.NewInt(int64(len(invoiceReferenceAlphabet)))
+	for i := 0; i < 6; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(invoiceReferenceAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
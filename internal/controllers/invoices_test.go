@@ -0,0 +1,201 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock InvoicesRepository
+type MockInvoicesRepository struct {
+	mock.Mock
+}
+
+func (m *MockInvoicesRepository) Create(ctx context.Context, invoice *models.Invoice) error {
+	args := m.Called(ctx, invoice)
+	return args.Error(0)
+}
+
+func (m *MockInvoicesRepository) GetForUser(ctx context.Context, userID int64) ([]*models.Invoice, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Invoice), args.Error(1)
+}
+
+func (m *MockInvoicesRepository) Cancel(ctx context.Context, invoiceID int64, issuerUserID int64) error {
+	args := m.Called(ctx, invoiceID, issuerUserID)
+	return args.Error(0)
+}
+
+func setupInvoicesController() (*controllers.Invoices, *MockInvoicesRepository, *MockJobSlotRentalsRepository, *MockContactsRepository) {
+	repo := new(MockInvoicesRepository)
+	rentalsRepo := new(MockJobSlotRentalsRepository)
+	contactsRepo := new(MockContactsRepository)
+	controller := controllers.NewInvoices(&MockRouter{}, repo, rentalsRepo, contactsRepo)
+	return controller, repo, rentalsRepo, contactsRepo
+}
+
+func Test_InvoicesController_CreateInvoice_FromAgreementUsesListingPrice(t *testing.T) {
+	controller, repo, rentalsRepo, _ := setupInvoicesController()
+
+	userID := int64(100)
+	startsAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rentalsRepo.On("GetAgreementsForUser", mock.Anything, userID).Return([]*models.JobSlotRentalAgreement{
+		{
+			ID:            5,
+			ListingID:     9,
+			OwnerUserID:   100,
+			RenterUserID:  200,
+			CharacterName: "Slot Owner",
+			ActivityType:  "manufacturing",
+			Slots:         2,
+			StartsAt:      startsAt,
+			EndsAt:        startsAt.Add(3 * 24 * time.Hour),
+		},
+	}, nil)
+	rentalsRepo.On("GetByID", mock.Anything, int64(9)).Return(&models.JobSlotRentalListing{
+		ID:          9,
+		PriceAmount: 1_000_000,
+		PricingUnit: "per_slot_day",
+	}, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(i *models.Invoice) bool {
+		return i.IssuerUserID == 100 &&
+			i.PayerUserID == 200 &&
+			*i.AgreementID == 5 &&
+			i.Amount == 6_000_000 &&
+			i.Status == "unpaid" &&
+			strings.HasPrefix(i.ReferenceCode, "INV-") &&
+			len(i.ReferenceCode) == 10 &&
+			strings.Contains(i.Description, "manufacturing")
+	})).Return(nil)
+
+	body := []byte(`{"agreementId":5}`)
+	req := httptest.NewRequest("POST", "/v1/invoices", bytes.NewReader(body))
+
+	result, httpErr := controller.CreateInvoice(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	invoice := result.(*models.Invoice)
+	assert.True(t, invoice.DueAt.After(time.Now().Add(6*24*time.Hour)))
+	repo.AssertExpectations(t)
+}
+
+func Test_InvoicesController_CreateInvoice_AgreementOfAnotherOwner(t *testing.T) {
+	controller, repo, rentalsRepo, _ := setupInvoicesController()
+
+	// The renter cannot invoice the owner for the owner's own agreement
+	userID := int64(200)
+	rentalsRepo.On("GetAgreementsForUser", mock.Anything, userID).Return([]*models.JobSlotRentalAgreement{
+		{ID: 5, ListingID: 9, OwnerUserID: 100, RenterUserID: 200},
+	}, nil)
+
+	body := []byte(`{"agreementId":5,"amount":1000}`)
+	req := httptest.NewRequest("POST", "/v1/invoices", bytes.NewReader(body))
+
+	_, httpErr := controller.CreateInvoice(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_InvoicesController_CreateInvoice_ManualToContact(t *testing.T) {
+	controller, repo, _, contactsRepo := setupInvoicesController()
+
+	userID := int64(100)
+	dueAt := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+	contactsRepo.On("GetByUser", mock.Anything, userID).Return([]*models.Contact{
+		{ID: 1, RequesterUserID: 300, RecipientUserID: 100, Status: "accepted"},
+	}, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(i *models.Invoice) bool {
+		return i.PayerUserID == 300 && i.AgreementID == nil && i.Amount == 50_000_000 && i.Description == "Freighter run"
+	})).Return(nil)
+
+	body := []byte(`{"payerUserId":300,"amount":50000000,"description":" Freighter run ","dueAt":"` + dueAt + `"}`)
+	req := httptest.NewRequest("POST", "/v1/invoices", bytes.NewReader(body))
+
+	_, httpErr := controller.CreateInvoice(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	repo.AssertExpectations(t)
+}
+
+func Test_InvoicesController_CreateInvoice_ManualToNonContact(t *testing.T) {
+	controller, repo, _, contactsRepo := setupInvoicesController()
+
+	userID := int64(100)
+	contactsRepo.On("GetByUser", mock.Anything, userID).Return([]*models.Contact{
+		{ID: 1, RequesterUserID: 100, RecipientUserID: 300, Status: "pending"},
+	}, nil)
+
+	body := []byte(`{"payerUserId":300,"amount":50000000,"description":"Freighter run"}`)
+	req := httptest.NewRequest("POST", "/v1/invoices", bytes.NewReader(body))
+
+	_, httpErr := controller.CreateInvoice(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 403, httpErr.StatusCode)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_InvoicesController_CreateInvoice_DueDateInPast(t *testing.T) {
+	controller, repo, _, contactsRepo := setupInvoicesController()
+
+	userID := int64(100)
+	contactsRepo.On("GetByUser", mock.Anything, userID).Return([]*models.Contact{
+		{ID: 1, RequesterUserID: 100, RecipientUserID: 300, Status: "accepted"},
+	}, nil)
+
+	body := []byte(`{"payerUserId":300,"amount":1000,"description":"Run","dueAt":"2020-01-01T00:00:00Z"}`)
+	req := httptest.NewRequest("POST", "/v1/invoices", bytes.NewReader(body))
+
+	_, httpErr := controller.CreateInvoice(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_InvoicesController_CreateInvoice_BelowOneCent(t *testing.T) {
+	controller, repo, _, contactsRepo := setupInvoicesController()
+
+	userID := int64(100)
+	contactsRepo.On("GetByUser", mock.Anything, userID).Return([]*models.Contact{
+		{ID: 1, RequesterUserID: 100, RecipientUserID: 300, Status: "accepted"},
+	}, nil)
+
+	body := []byte(`{"payerUserId":300,"amount":0.004,"description":"Run"}`)
+	req := httptest.NewRequest("POST", "/v1/invoices", bytes.NewReader(body))
+
+	_, httpErr := controller.CreateInvoice(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_InvoicesController_CancelInvoice_NotFound(t *testing.T) {
+	controller, repo, _, _ := setupInvoicesController()
+
+	userID := int64(100)
+	repo.On("Cancel", mock.Anything, int64(12), userID).Return(errors.New("invoice not found or not open"))
+
+	req := httptest.NewRequest("PUT", "/v1/invoices/12/cancel", nil)
+
+	_, httpErr := controller.CancelInvoice(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "12"}})
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+}
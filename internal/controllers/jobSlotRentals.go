@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"strconv"
+	"time"
 
 	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/web"
@@ -23,6 +24,9 @@ type JobSlotRentalsRepository interface {
 	GetInterestsByRequester(ctx context.Context, requesterUserID int64) ([]*models.JobSlotInterestRequest, error)
 	UpdateInterestStatus(ctx context.Context, interestID int64, userID int64, status string) error
 	GetReceivedInterests(ctx context.Context, userID int64) ([]*models.JobSlotInterestRequest, error)
+	CreateAgreement(ctx context.Context, agreement *models.JobSlotRentalAgreement) error
+	GetAgreementsForUser(ctx context.Context, userID int64) ([]*models.JobSlotRentalAgreement, error)
+	CancelAgreement(ctx context.Context, agreementID int64, userID int64) error
 }
 
 type JobSlotRentals struct {
@@ -46,6 +50,9 @@ func NewJobSlotRentals(router Routerer, repository JobSlotRentalsRepository, per
 	router.RegisterRestAPIRoute("/v1/job-slots/interest/sent", web.AuthAccessUser, controller.GetSentInterests, "GET")
 	router.RegisterRestAPIRoute("/v1/job-slots/interest/received", web.AuthAccessUser, controller.GetReceivedInterests, "GET")
 	router.RegisterRestAPIRoute("/v1/job-slots/interest/{id}/status", web.AuthAccessUser, controller.UpdateInterestStatus, "PUT")
+	router.RegisterRestAPIRoute("/v1/job-slots/agreements", web.AuthAccessUser, controller.GetAgreements, "GET")
+	router.RegisterRestAPIRoute("/v1/job-slots/agreements", web.AuthAccessUser, controller.CreateAgreement, "POST")
+	router.RegisterRestAPIRoute("/v1/job-slots/agreements/{id}/cancel", web.AuthAccessUser, controller.CancelAgreement, "PUT")
 
 	return controller
 }
@@ -419,3 +426,171 @@ func (c *JobSlotRentals) UpdateInterestStatus(args *web.HandlerArgs) (any, *web.
 
 	return nil, nil
 }
+
+// GetAgreements returns rental agreements where the user is the slot owner or the renter
+func (c *JobSlotRentals) GetAgreements(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	agreements, err := c.repository.GetAgreementsForUser(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get agreements")}
+	}
+
+	return agreements, nil
+}
+
+// CreateAgreement turns a received interest request into a rental agreement
+// that reserves slots on the listing's character. Only the listing owner can
+// create it, and the listing must have enough unrented slots for the period.
+func (c *JobSlotRentals) CreateAgreement(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	userID := *args.User
+	ctx := args.Request.Context()
+
+	var req models.CreateJobSlotAgreementRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if req.InterestID == 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("interestId is required")}
+	}
+
+	received, err := c.repository.GetReceivedInterests(ctx, userID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get received interests")}
+	}
+
+	var interest *models.JobSlotInterestRequest
+	for _, i := range received {
+		if i.ID == req.InterestID {
+			interest = i
+			break
+		}
+	}
+	if interest == nil {
+		return nil, &web.HttpError{StatusCode: 404, Error: errors.New("interest request not found")}
+	}
+	if interest.Status != "pending" && interest.Status != "accepted" {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Errorf("cannot create an agreement from a %s interest request", interest.Status)}
+	}
+
+	listing, err := c.repository.GetByID(ctx, interest.ListingID)
+	if err != nil {
+		if errors.Cause(err).Error() == "job slot listing not found" {
+			return nil, &web.HttpError{StatusCode: 404, Error: errors.New("job slot listing not found")}
+		}
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get listing")}
+	}
+	if !listing.IsActive {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("listing is no longer active")}
+	}
+
+	slots := req.Slots
+	if slots == 0 {
+		slots = interest.SlotsRequested
+	}
+	if slots <= 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("slots must be greater than 0")}
+	}
+
+	startsAt := time.Now()
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+
+	var endsAt time.Time
+	switch {
+	case req.EndsAt != nil:
+		endsAt = *req.EndsAt
+	case interest.DurationDays != nil && *interest.DurationDays > 0:
+		endsAt = startsAt.AddDate(0, 0, *interest.DurationDays)
+	default:
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("endsAt is required when the interest request has no duration")}
+	}
+	if !endsAt.After(startsAt) {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("endsAt must be after startsAt")}
+	}
+
+	existing, err := c.repository.GetAgreementsForUser(ctx, userID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get agreements")}
+	}
+
+	// Overlapping agreements on the same listing share its slots
+	committed := 0
+	for _, a := range existing {
+		if a.ListingID != listing.ID || a.Status != "active" {
+			continue
+		}
+		if a.StartsAt.Before(endsAt) && a.EndsAt.After(startsAt) {
+			committed += a.Slots
+		}
+	}
+	if committed+slots > listing.SlotsListed {
+		free := listing.SlotsListed - committed
+		if free < 0 {
+			free = 0
+		}
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Errorf("listing only has %d slots free for that period", free)}
+	}
+
+	interestID := interest.ID
+	agreement := &models.JobSlotRentalAgreement{
+		ListingID:     listing.ID,
+		InterestID:    &interestID,
+		OwnerUserID:   userID,
+		RenterUserID:  interest.RequesterUserID,
+		RenterName:    interest.RequesterName,
+		CharacterID:   listing.CharacterID,
+		CharacterName: listing.CharacterName,
+		ActivityType:  listing.ActivityType,
+		Slots:         slots,
+		StartsAt:      startsAt,
+		EndsAt:        endsAt,
+		Status:        "active",
+	}
+
+	if err := c.repository.CreateAgreement(ctx, agreement); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to create agreement")}
+	}
+
+	if interest.Status == "pending" {
+		if err := c.repository.UpdateInterestStatus(ctx, interest.ID, userID, "accepted"); err != nil {
+			return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to accept interest request")}
+		}
+	}
+
+	return agreement, nil
+}
+
+// CancelAgreement ends an active agreement early, releasing its slots
+func (c *JobSlotRentals) CancelAgreement(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	agreementIDStr, ok := args.Params["id"]
+	if !ok {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("agreement ID is required")}
+	}
+
+	agreementID, err := strconv.ParseInt(agreementIDStr, 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("invalid agreement ID")}
+	}
+
+	if err := c.repository.CancelAgreement(args.Request.Context(), agreementID, *args.User); err != nil {
+		if err.Error() == "job slot agreement not found or user not authorized" {
+			return nil, &web.HttpError{StatusCode: 404, Error: err}
+		}
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to cancel agreement")}
+	}
+
+	return nil, nil
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/annymsMthd/industry-tool/internal/controllers"
 	"github.com/annymsMthd/industry-tool/internal/models"
@@ -101,6 +102,24 @@ func (m *MockJobSlotRentalsRepository) GetReceivedInterests(ctx context.Context,
 	return args.Get(0).([]*models.JobSlotInterestRequest), args.Error(1)
 }
 
+func (m *MockJobSlotRentalsRepository) CreateAgreement(ctx context.Context, agreement *models.JobSlotRentalAgreement) error {
+	args := m.Called(ctx, agreement)
+	return args.Error(0)
+}
+
+func (m *MockJobSlotRentalsRepository) GetAgreementsForUser(ctx context.Context, userID int64) ([]*models.JobSlotRentalAgreement, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.JobSlotRentalAgreement), args.Error(1)
+}
+
+func (m *MockJobSlotRentalsRepository) CancelAgreement(ctx context.Context, agreementID int64, userID int64) error {
+	args := m.Called(ctx, agreementID, userID)
+	return args.Error(0)
+}
+
 func Test_JobSlotRentalsController_GetSlotInventory_Success(t *testing.T) {
 	mockRepo := new(MockJobSlotRentalsRepository)
 	mockRouter := &MockRouter{}
@@ -749,3 +768,143 @@ func Test_JobSlotRentalsController_UpdateInterestStatus_NotFound(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func Test_JobSlotRentalsController_CreateAgreement_Success(t *testing.T) {
+	mockRepo := new(MockJobSlotRentalsRepository)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+	duration := 7
+
+	mockRepo.On("GetReceivedInterests", mock.Anything, userID).Return([]*models.JobSlotInterestRequest{
+		{ID: 1, ListingID: 10, RequesterUserID: 456, RequesterName: "Renter", SlotsRequested: 2, DurationDays: &duration, Status: "pending"},
+	}, nil)
+	mockRepo.On("GetByID", mock.Anything, int64(10)).Return(&models.JobSlotRentalListing{
+		ID: 10, UserID: userID, CharacterID: 1001, CharacterName: "Builder", ActivityType: "manufacturing", SlotsListed: 3, IsActive: true,
+	}, nil)
+	mockRepo.On("GetAgreementsForUser", mock.Anything, userID).Return([]*models.JobSlotRentalAgreement{}, nil)
+	mockRepo.On("CreateAgreement", mock.Anything, mock.MatchedBy(func(a *models.JobSlotRentalAgreement) bool {
+		return a.ListingID == 10 &&
+			a.RenterUserID == 456 &&
+			a.CharacterID == 1001 &&
+			a.ActivityType == "manufacturing" &&
+			a.Slots == 2 &&
+			a.EndsAt.Sub(a.StartsAt) == 7*24*time.Hour &&
+			a.Status == "active"
+	})).Return(nil)
+	mockRepo.On("UpdateInterestStatus", mock.Anything, int64(1), userID, "accepted").Return(nil)
+
+	bodyBytes, _ := json.Marshal(map[string]interface{}{"interestId": 1})
+	req := httptest.NewRequest("POST", "/v1/job-slots/agreements", bytes.NewReader(bodyBytes))
+
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+		Params:  map[string]string{},
+	}
+
+	controller := controllers.NewJobSlotRentals(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	result, httpErr := controller.CreateAgreement(args)
+
+	assert.Nil(t, httpErr)
+	agreement := result.(*models.JobSlotRentalAgreement)
+	assert.Equal(t, 2, agreement.Slots)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_JobSlotRentalsController_CreateAgreement_OverlappingAgreementsExceedListing(t *testing.T) {
+	mockRepo := new(MockJobSlotRentalsRepository)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+	start := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 5)
+
+	mockRepo.On("GetReceivedInterests", mock.Anything, userID).Return([]*models.JobSlotInterestRequest{
+		{ID: 1, ListingID: 10, RequesterUserID: 456, SlotsRequested: 2, Status: "accepted"},
+	}, nil)
+	mockRepo.On("GetByID", mock.Anything, int64(10)).Return(&models.JobSlotRentalListing{
+		ID: 10, UserID: userID, CharacterID: 1001, ActivityType: "manufacturing", SlotsListed: 3, IsActive: true,
+	}, nil)
+	mockRepo.On("GetAgreementsForUser", mock.Anything, userID).Return([]*models.JobSlotRentalAgreement{
+		// Overlaps the requested window
+		{ID: 5, ListingID: 10, Slots: 2, Status: "active", StartsAt: start.AddDate(0, 0, -2), EndsAt: start.AddDate(0, 0, 1)},
+		// Ends before the requested window
+		{ID: 6, ListingID: 10, Slots: 3, Status: "active", StartsAt: start.AddDate(0, 0, -9), EndsAt: start},
+		// Cancelled agreements don't hold slots
+		{ID: 7, ListingID: 10, Slots: 3, Status: "cancelled", StartsAt: start, EndsAt: end},
+	}, nil)
+
+	bodyBytes, _ := json.Marshal(map[string]interface{}{"interestId": 1, "startsAt": start, "endsAt": end})
+	req := httptest.NewRequest("POST", "/v1/job-slots/agreements", bytes.NewReader(bodyBytes))
+
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+		Params:  map[string]string{},
+	}
+
+	controller := controllers.NewJobSlotRentals(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	result, httpErr := controller.CreateAgreement(args)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	assert.Contains(t, httpErr.Error.Error(), "only has 1 slots free")
+
+	mockRepo.AssertNotCalled(t, "CreateAgreement", mock.Anything, mock.Anything)
+}
+
+func Test_JobSlotRentalsController_CreateAgreement_DeclinedInterest(t *testing.T) {
+	mockRepo := new(MockJobSlotRentalsRepository)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+
+	mockRepo.On("GetReceivedInterests", mock.Anything, userID).Return([]*models.JobSlotInterestRequest{
+		{ID: 1, ListingID: 10, RequesterUserID: 456, SlotsRequested: 1, Status: "declined"},
+	}, nil)
+
+	bodyBytes, _ := json.Marshal(map[string]interface{}{"interestId": 1})
+	req := httptest.NewRequest("POST", "/v1/job-slots/agreements", bytes.NewReader(bodyBytes))
+
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+		Params:  map[string]string{},
+	}
+
+	controller := controllers.NewJobSlotRentals(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	result, httpErr := controller.CreateAgreement(args)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
+func Test_JobSlotRentalsController_CancelAgreement_NotFound(t *testing.T) {
+	mockRepo := new(MockJobSlotRentalsRepository)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+
+	mockRepo.On("CancelAgreement", mock.Anything, int64(9), userID).Return(errors.New("job slot agreement not found or user not authorized"))
+
+	req := httptest.NewRequest("PUT", "/v1/job-slots/agreements/9/cancel", nil)
+
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+		Params:  map[string]string{"id": "9"},
+	}
+
+	controller := controllers.NewJobSlotRentals(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	result, httpErr := controller.CancelAgreement(args)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+
+	mockRepo.AssertExpectations(t)
+}
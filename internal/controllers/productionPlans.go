@@ -61,6 +61,7 @@ type ProductionPlansCostIndicesRepository interface {
 type ProductionPlansJobQueueRepository interface {
 	Create(ctx context.Context, entry *models.IndustryJobQueueEntry) (*models.IndustryJobQueueEntry, error)
 	GetSlotUsage(ctx context.Context, userID int64) (map[int64]map[string]int, error)
+	GetRentedSlotUsage(ctx context.Context, userID int64) (map[int64]map[string]int, error)
 }
 
 type ProductionPlanRunsRepository interface {
@@ -893,6 +894,7 @@ func (c *ProductionPlans) GenerateJobs(args *web.HandlerArgs) (any, *web.HttpErr
 				EstimatedCost:     estimatedCost,
 				EstimatedDuration: &dur,
 				Notes:             &entryNote,
+				Reservations:      services.BuildMaterialReservations(orig, aj.Runs),
 			}
 
 			created, err := c.queueRepo.Create(ctx, newEntry)
@@ -915,6 +917,7 @@ func (c *ProductionPlans) GenerateJobs(args *web.HandlerArgs) (any, *web.HttpErr
 			pj.Entry.UserID = *args.User
 			pj.Entry.Notes = &note
 			pj.Entry.PlanRunID = &run.ID
+			pj.Entry.Reservations = services.BuildMaterialReservations(pj, pj.Entry.Runs)
 
 			created, err := c.queueRepo.Create(ctx, pj.Entry)
 			if err != nil {
@@ -1130,10 +1133,7 @@ func (c *ProductionPlans) generateTransportJobs(
 						}
 					}
 
-					fuelPerLY := 0.0
-					if profile.FuelPerLY != nil {
-						fuelPerLY = *profile.FuelPerLY
-					}
+					fuelPerLY := calculator.JumpFuelPerLY(profile)
 
 					costResult := calculator.CalculateJFTransportCost(&calculator.JFTransportCostParams{
 						TotalVolumeM3:         totalVolume,
@@ -1400,8 +1400,13 @@ func (c *ProductionPlans) PreviewPlan(args *web.HandlerArgs) (any, *web.HttpErro
 		skillsByCharacter[sk.CharacterID][sk.SkillID] = sk.ActiveLevel
 	}
 
-	// No slot usage data for preview — we're simulating fresh slots
-	capacities := calculator.BuildCharacterCapacities(characterNames, skillsByCharacter, nil)
+	// Preview simulates fresh slots, except for slots rented out to other
+	// players under an active agreement, which are never ours to fill
+	rentedUsage, err := c.queueRepo.GetRentedSlotUsage(ctx, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get rented slot usage")}
+	}
+	capacities := calculator.BuildCharacterCapacities(characterNames, skillsByCharacter, rentedUsage)
 
 	result := &models.PlanPreviewResult{
 		Options:            []*models.PlanPreviewOption{},
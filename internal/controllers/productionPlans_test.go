@@ -243,6 +243,14 @@ func (m *MockProductionPlansJobQueueRepository) GetSlotUsage(ctx context.Context
 	return args.Get(0).(map[int64]map[string]int), args.Error(1)
 }
 
+func (m *MockProductionPlansJobQueueRepository) GetRentedSlotUsage(ctx context.Context, userID int64) (map[int64]map[string]int, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int64]map[string]int), args.Error(1)
+}
+
 type MockProductionPlanRunsRepository struct {
 	mock.Mock
 }
@@ -2331,6 +2339,7 @@ func Test_ProductionPlans_PreviewPlan_Success(t *testing.T) {
 		skillVal(charB, 3388, 3), // AdvIndustry 3
 		skillVal(charB, 3387, 3), // MassProduction 3
 	}, nil)
+	mocks.queueRepo.On("GetRentedSlotUsage", mock.Anything, userID).Return(map[int64]map[string]int{}, nil)
 
 	body, _ := json.Marshal(map[string]any{"quantity": 1})
 	req := httptest.NewRequest("POST", "/v1/industry/plans/1/preview", bytes.NewReader(body))
@@ -2399,6 +2408,7 @@ func Test_ProductionPlans_PreviewPlan_NoCharacters(t *testing.T) {
 	// No characters at all
 	mocks.characterRepo.On("GetNames", mock.Anything, userID).Return(map[int64]string{}, nil)
 	mocks.skillsRepo.On("GetSkillsForUser", mock.Anything, userID).Return([]*models.CharacterSkill{}, nil)
+	mocks.queueRepo.On("GetRentedSlotUsage", mock.Anything, userID).Return(map[int64]map[string]int{}, nil)
 
 	body, _ := json.Marshal(map[string]any{"quantity": 1})
 	req := httptest.NewRequest("POST", "/v1/industry/plans/1/preview", bytes.NewReader(body))
@@ -2412,6 +2422,79 @@ func Test_ProductionPlans_PreviewPlan_NoCharacters(t *testing.T) {
 	assert.Len(t, preview.Options, 0)
 }
 
+func Test_ProductionPlans_PreviewPlan_RentedSlotsAreNotFilled(t *testing.T) {
+	controller, mocks := setupProductionPlansController()
+
+	userID := int64(100)
+	charA := int64(201)
+	charB := int64(202)
+
+	plan := &models.ProductionPlan{
+		ID: 1, UserID: userID, Name: "Test Plan",
+		Steps: []*models.ProductionPlanStep{
+			{
+				ID: 10, PlanID: 1, ProductTypeID: 587,
+				BlueprintTypeID: 787, Activity: "manufacturing",
+				MELevel: 10, TELevel: 20, IndustrySkill: 5, AdvIndustrySkill: 5,
+				Structure: "raitaru", Rig: "t2", Security: "high", FacilityTax: 1.0,
+				ProductName: "Rifter",
+			},
+		},
+	}
+
+	mocks.plansRepo.On("GetByID", mock.Anything, int64(1), userID).Return(plan, nil)
+	mocks.marketRepo.On("GetAllJitaPrices", mock.Anything).Return(map[int64]*models.MarketPrice{}, nil)
+	mocks.marketRepo.On("GetAllAdjustedPrices", mock.Anything).Return(map[int64]float64{}, nil)
+
+	mocks.sdeRepo.On("GetBlueprintForActivity", mock.Anything, int64(787), "manufacturing").Return(&repositories.ManufacturingBlueprintRow{
+		BlueprintTypeID: 787, ProductTypeID: 587, ProductName: "Rifter",
+		ProductQuantity: 1, Time: 7200,
+	}, nil)
+	mocks.sdeRepo.On("GetBlueprintMaterialsForActivity", mock.Anything, int64(787), "manufacturing").Return([]*repositories.ManufacturingMaterialRow{}, nil)
+
+	mocks.characterRepo.On("GetNames", mock.Anything, userID).Return(map[int64]string{
+		charA: "Alpha",
+		charB: "Beta",
+	}, nil)
+	mocks.skillsRepo.On("GetSkillsForUser", mock.Anything, userID).Return([]*models.CharacterSkill{
+		skillVal(charA, 3380, 5), // Industry 5
+		skillVal(charA, 3387, 5), // MassProduction 5 → 6 mfg slots
+		skillVal(charB, 3380, 4), // Industry 4
+	}, nil)
+
+	// Every manufacturing slot on Alpha is rented out to another player
+	mocks.queueRepo.On("GetRentedSlotUsage", mock.Anything, userID).Return(map[int64]map[string]int{
+		charA: {"manufacturing": 6},
+	}, nil)
+
+	body, _ := json.Marshal(map[string]any{"quantity": 1})
+	req := httptest.NewRequest("POST", "/v1/industry/plans/1/preview", bytes.NewReader(body))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "1"}}
+
+	result, httpErr := controller.PreviewPlan(args)
+
+	assert.Nil(t, httpErr)
+	preview := result.(*models.PlanPreviewResult)
+	assert.Len(t, preview.Options, 2)
+
+	alpha := preview.Options[0].Characters[0]
+	assert.Equal(t, charA, alpha.CharacterID)
+	assert.Equal(t, 6, alpha.MfgSlotsUsed)
+	assert.Equal(t, 0, alpha.JobCount)
+
+	// With both characters available the job lands on Beta
+	for _, ch := range preview.Options[1].Characters {
+		if ch.CharacterID == charB {
+			assert.Equal(t, 1, ch.JobCount)
+		} else {
+			assert.Equal(t, 0, ch.JobCount)
+		}
+	}
+
+	mocks.queueRepo.AssertExpectations(t)
+	mocks.queueRepo.AssertNotCalled(t, "GetSlotUsage", mock.Anything, mock.Anything)
+}
+
 func Test_ProductionPlans_PreviewPlan_InvalidQuantity(t *testing.T) {
 	controller, _ := setupProductionPlansController()
 
@@ -0,0 +1,420 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+type PurchaseCartRepository interface {
+	GetByUser(ctx context.Context, userID int64) ([]*models.PurchaseCartItem, error)
+	Upsert(ctx context.Context, userID, forSaleItemID, quantity int64) error
+	Remove(ctx context.Context, userID, forSaleItemID int64) error
+	RemoveItems(ctx context.Context, tx *sql.Tx, userID int64, forSaleItemIDs []int64) error
+}
+
+type PurchaseOrdersRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, order *models.PurchaseOrder) error
+	GetByUser(ctx context.Context, userID int64) ([]*models.PurchaseOrder, error)
+	GetByID(ctx context.Context, orderID int64) (*models.PurchaseOrder, error)
+	MarkContractCreated(ctx context.Context, orderID int64, contractKey string) (int64, error)
+}
+
+type PurchasesForPurchaseCart interface {
+	Create(ctx context.Context, tx *sql.Tx, purchase *models.PurchaseTransaction) error
+}
+
+type PermissionsForPurchaseCart interface {
+	CheckPermission(ctx context.Context, grantingUserID, receivingUserID int64, serviceType string) (bool, error)
+}
+
+type PurchaseOrderNotifierInterface interface {
+	NotifyPurchaseOrder(ctx context.Context, order *models.PurchaseOrder)
+	NotifyOrderContractCreated(ctx context.Context, order *models.PurchaseOrder)
+}
+
+type PurchaseCart struct {
+	db                    *sql.DB
+	cartRepository        PurchaseCartRepository
+	ordersRepository      PurchaseOrdersRepository
+	purchaseRepository    PurchasesForPurchaseCart
+	forSaleRepository     ForSaleItemsForPurchases
+	permissionsRepository PermissionsForPurchaseCart
+	usersRepository       UsersForPurchases
+	notifier              PurchaseOrderNotifierInterface
+}
+
+func NewPurchaseCart(
+	router Routerer,
+	db *sql.DB,
+	cartRepository PurchaseCartRepository,
+	ordersRepository PurchaseOrdersRepository,
+	purchaseRepository PurchasesForPurchaseCart,
+	forSaleRepository ForSaleItemsForPurchases,
+	permissionsRepository PermissionsForPurchaseCart,
+	usersRepository UsersForPurchases,
+	notifier PurchaseOrderNotifierInterface,
+) *PurchaseCart {
+	controller := &PurchaseCart{
+		db:                    db,
+		cartRepository:        cartRepository,
+		ordersRepository:      ordersRepository,
+		purchaseRepository:    purchaseRepository,
+		forSaleRepository:     forSaleRepository,
+		permissionsRepository: permissionsRepository,
+		usersRepository:       usersRepository,
+		notifier:              notifier,
+	}
+
+	router.RegisterRestAPIRoute("/v1/purchases/cart", web.AuthAccessUser, controller.GetCart, "GET")
+	router.RegisterRestAPIRoute("/v1/purchases/cart", web.AuthAccessUser, controller.AddToCart, "POST")
+	router.RegisterRestAPIRoute("/v1/purchases/cart/checkout", web.AuthAccessUser, controller.Checkout, "POST")
+	router.RegisterRestAPIRoute("/v1/purchases/cart/{forSaleItemId}", web.AuthAccessUser, controller.RemoveFromCart, "DELETE")
+	router.RegisterRestAPIRoute("/v1/purchases/orders", web.AuthAccessUser, controller.GetOrders, "GET")
+	router.RegisterRestAPIRoute("/v1/purchases/orders/{id}/mark-contract-created", web.AuthAccessUser, controller.MarkOrderContractCreated, "POST")
+
+	return controller
+}
+
+type AddToCartRequest struct {
+	ForSaleItemID int64 `json:"forSaleItemId"`
+	Quantity      int64 `json:"quantity"`
+}
+
+type CheckoutRequest struct {
+	Notes string `json:"notes,omitempty"`
+}
+
+// CheckoutFailure reports why the items of one seller could not be checked out. Those items stay
+// in the cart.
+type CheckoutFailure struct {
+	SellerUserID int64  `json:"sellerUserId"`
+	SellerName   string `json:"sellerName"`
+	Error        string `json:"error"`
+}
+
+type CheckoutResponse struct {
+	Orders   []*models.PurchaseOrder `json:"orders"`
+	Failures []*CheckoutFailure      `json:"failures"`
+}
+
+// GetCart returns the items in the authenticated user's cart
+func (c *PurchaseCart) GetCart(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	items, err := c.cartRepository.GetByUser(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get cart")}
+	}
+
+	return items, nil
+}
+
+// AddToCart adds a for-sale item to the cart or replaces its quantity
+func (c *PurchaseCart) AddToCart(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+	buyerUserID := *args.User
+
+	var req AddToCartRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if req.Quantity <= 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("quantity must be positive")}
+	}
+
+	item, err := c.forSaleRepository.GetByID(args.Request.Context(), req.ForSaleItemID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 404, Error: errors.Wrap(err, "for-sale item not found")}
+	}
+
+	if !item.IsActive {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("for-sale item is no longer active")}
+	}
+
+	if buyerUserID == item.UserID {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("cannot purchase your own items")}
+	}
+
+	hasPermission, err := c.permissionsRepository.CheckPermission(args.Request.Context(), item.UserID, buyerUserID, "for_sale_browse")
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to check permission")}
+	}
+	if !hasPermission {
+		return nil, &web.HttpError{StatusCode: 403, Error: errors.New("you do not have permission to purchase from this seller")}
+	}
+
+	if req.Quantity > item.QuantityAvailable {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("requested quantity exceeds available quantity")}
+	}
+
+	if err := c.cartRepository.Upsert(args.Request.Context(), buyerUserID, item.ID, req.Quantity); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to add item to cart")}
+	}
+
+	return c.GetCart(args)
+}
+
+// RemoveFromCart removes a for-sale item from the cart
+func (c *PurchaseCart) RemoveFromCart(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	forSaleItemID, err := strconv.ParseInt(args.Params["forSaleItemId"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid for-sale item ID")}
+	}
+
+	if err := c.cartRepository.Remove(args.Request.Context(), *args.User, forSaleItemID); err != nil {
+		return nil, &web.HttpError{StatusCode: 404, Error: err}
+	}
+
+	return nil, nil
+}
+
+// Checkout buys everything in the cart. Each seller's items are purchased atomically in one
+// transaction and grouped under one order; a failure for one seller leaves the other orders in place.
+func (c *PurchaseCart) Checkout(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+	buyerUserID := *args.User
+	ctx := args.Request.Context()
+
+	var req CheckoutRequest
+	if args.Request.Body != nil {
+		if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil && err.Error() != "EOF" {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+		}
+	}
+
+	items, err := c.cartRepository.GetByUser(ctx, buyerUserID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get cart")}
+	}
+	if len(items) == 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("cart is empty")}
+	}
+
+	var notes *string
+	if req.Notes != "" {
+		notes = &req.Notes
+	}
+
+	// Cart items come back ordered by seller
+	sellerItems := map[int64][]*models.PurchaseCartItem{}
+	sellerIDs := []int64{}
+	for _, item := range items {
+		if _, ok := sellerItems[item.SellerUserID]; !ok {
+			sellerIDs = append(sellerIDs, item.SellerUserID)
+		}
+		sellerItems[item.SellerUserID] = append(sellerItems[item.SellerUserID], item)
+	}
+
+	buyerName, err := c.usersRepository.GetUserName(ctx, buyerUserID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get user name")}
+	}
+
+	response := &CheckoutResponse{
+		Orders:   []*models.PurchaseOrder{},
+		Failures: []*CheckoutFailure{},
+	}
+	for _, sellerID := range sellerIDs {
+		group := sellerItems[sellerID]
+		order, err := c.checkoutSeller(ctx, buyerUserID, buyerName, sellerID, group, notes)
+		if err != nil {
+			log.Error("purchase cart: checkout failed for seller", "buyerUserID", buyerUserID, "sellerUserID", sellerID, "error", err)
+			response.Failures = append(response.Failures, &CheckoutFailure{
+				SellerUserID: sellerID,
+				SellerName:   group[0].SellerName,
+				Error:        err.Error(),
+			})
+			continue
+		}
+
+		response.Orders = append(response.Orders, order)
+		if c.notifier != nil {
+			go c.notifier.NotifyPurchaseOrder(context.Background(), order)
+		}
+	}
+
+	return response, nil
+}
+
+func (c *PurchaseCart) checkoutSeller(ctx context.Context, buyerUserID int64, buyerName string, sellerUserID int64, items []*models.PurchaseCartItem, notes *string) (*models.PurchaseOrder, error) {
+	if buyerUserID == sellerUserID {
+		return nil, errors.New("cannot purchase your own items")
+	}
+
+	hasPermission, err := c.permissionsRepository.CheckPermission(ctx, sellerUserID, buyerUserID, "for_sale_browse")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check permission")
+	}
+	if !hasPermission {
+		return nil, errors.New("you do not have permission to purchase from this seller")
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	order := &models.PurchaseOrder{
+		BuyerUserID:  buyerUserID,
+		BuyerName:    buyerName,
+		SellerUserID: sellerUserID,
+		SellerName:   items[0].SellerName,
+		Notes:        notes,
+		Status:       "pending",
+		Purchases:    []*models.PurchaseTransaction{},
+	}
+	if err := c.ordersRepository.Create(ctx, tx, order); err != nil {
+		return nil, err
+	}
+
+	forSaleItemIDs := make([]int64, 0, len(items))
+	for _, cartItem := range items {
+		item, err := c.forSaleRepository.GetByID(ctx, cartItem.ForSaleItemID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "for-sale item %d not found", cartItem.ForSaleItemID)
+		}
+		if !item.IsActive {
+			return nil, fmt.Errorf("%s is no longer for sale", item.TypeName)
+		}
+		if cartItem.Quantity > item.QuantityAvailable {
+			return nil, fmt.Errorf("only %d %s available", item.QuantityAvailable, item.TypeName)
+		}
+
+		pricePerUnit, err := c.forSaleRepository.GetPriceForBuyer(ctx, item, buyerUserID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get price")
+		}
+
+		err = c.forSaleRepository.UpdateQuantity(ctx, tx, item.ID, item.QuantityAvailable-cartItem.Quantity)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to update quantity")
+		}
+
+		purchase := &models.PurchaseTransaction{
+			ForSaleItemID:     item.ID,
+			BuyerUserID:       buyerUserID,
+			BuyerName:         buyerName,
+			SellerUserID:      sellerUserID,
+			SellerName:        order.SellerName,
+			TypeID:            item.TypeID,
+			TypeName:          item.TypeName,
+			LocationID:        item.LocationID,
+			LocationName:      item.LocationName,
+			QuantityPurchased: cartItem.Quantity,
+			PricePerUnit:      pricePerUnit,
+			TotalPrice:        float64(cartItem.Quantity) * pricePerUnit,
+			Status:            "pending",
+			TransactionNotes:  notes,
+			OrderID:           &order.ID,
+			Blueprint:         item.Blueprint,
+		}
+		if err := c.purchaseRepository.Create(ctx, tx, purchase); err != nil {
+			return nil, err
+		}
+
+		order.Purchases = append(order.Purchases, purchase)
+		order.TotalPrice += purchase.TotalPrice
+		forSaleItemIDs = append(forSaleItemIDs, item.ID)
+	}
+
+	if err := c.cartRepository.RemoveItems(ctx, tx, buyerUserID, forSaleItemIDs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return order, nil
+}
+
+// GetOrders returns the orders the authenticated user bought or sold
+func (c *PurchaseCart) GetOrders(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	orders, err := c.ordersRepository.GetByUser(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get purchase orders")}
+	}
+
+	return orders, nil
+}
+
+// MarkOrderContractCreated marks every pending purchase of an order as contract_created under
+// one contract key (seller action)
+func (c *PurchaseCart) MarkOrderContractCreated(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	orderID, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid order ID")}
+	}
+
+	var req MarkContractCreatedRequest
+	if args.Request.Body != nil {
+		err = json.NewDecoder(args.Request.Body).Decode(&req)
+		if err != nil && err.Error() != "EOF" {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+		}
+	}
+	if httpErr := validateContractKey(req.ContractKey); httpErr != nil {
+		return nil, httpErr
+	}
+
+	order, err := c.ordersRepository.GetByID(args.Request.Context(), orderID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get purchase order")}
+	}
+	if order == nil {
+		return nil, &web.HttpError{StatusCode: 404, Error: errors.New("purchase order not found")}
+	}
+
+	if order.SellerUserID != *args.User {
+		return nil, &web.HttpError{StatusCode: 403, Error: errors.New("you are not the seller of this order")}
+	}
+
+	if order.Status != "pending" {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("order must be in pending status")}
+	}
+
+	contractKey := fmt.Sprintf("PO-%d", orderID)
+	if req.ContractKey != nil && *req.ContractKey != "" {
+		contractKey = *req.ContractKey
+	}
+
+	_, err = c.ordersRepository.MarkContractCreated(args.Request.Context(), orderID, contractKey)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to mark order contract created")}
+	}
+
+	if c.notifier != nil {
+		order.ContractKey = &contractKey
+		go c.notifier.NotifyOrderContractCreated(context.Background(), order)
+	}
+
+	return map[string]string{"status": "contract_created", "contractKey": contractKey}, nil
+}
@@ -0,0 +1,203 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PurchaseCart_CheckoutGroupsBySeller(t *testing.T) {
+	db := setupPurchasesTestDB(t)
+	ctx := context.Background()
+
+	buyerID := int64(4100)
+	sellerID := int64(4101)
+	strangerID := int64(4102)
+
+	userRepo := repositories.NewUserRepository(db)
+	charRepo := repositories.NewCharacterRepository(db)
+	itemTypesRepo := repositories.NewItemTypeRepository(db)
+	forSaleRepo := repositories.NewForSaleItems(db)
+	permRepo := repositories.NewContactPermissions(db)
+	contactsRepo := repositories.NewContacts(db)
+	purchaseRepo := repositories.NewPurchaseTransactions(db)
+	cartRepo := repositories.NewPurchaseCart(db)
+	ordersRepo := repositories.NewPurchaseOrders(db)
+
+	for _, user := range []*repositories.User{
+		{ID: buyerID, Name: "Cart Buyer"},
+		{ID: sellerID, Name: "Cart Seller"},
+		{ID: strangerID, Name: "Cart Stranger"},
+	} {
+		assert.NoError(t, userRepo.Add(ctx, user))
+		assert.NoError(t, charRepo.Add(ctx, &repositories.Character{ID: user.ID * 10, Name: user.Name + " Char", UserID: user.ID}))
+	}
+
+	itemTypes := []models.EveInventoryType{
+		{TypeID: 60, TypeName: "Megacyte", Volume: 0.01},
+		{TypeID: 61, TypeName: "Morphite", Volume: 0.01},
+	}
+	assert.NoError(t, itemTypesRepo.UpsertItemTypes(ctx, itemTypes))
+
+	contact, err := contactsRepo.Create(ctx, buyerID, sellerID)
+	assert.NoError(t, err)
+	_, err = contactsRepo.UpdateStatus(ctx, contact.ID, sellerID, "accepted")
+	assert.NoError(t, err)
+	assert.NoError(t, permRepo.Upsert(ctx, &models.ContactPermission{
+		ContactID:       contact.ID,
+		GrantingUserID:  sellerID,
+		ReceivingUserID: buyerID,
+		ServiceType:     "for_sale_browse",
+		CanAccess:       true,
+	}))
+
+	megacyte := &models.ForSaleItem{UserID: sellerID, TypeID: 60, OwnerType: "character", OwnerID: sellerID * 10, LocationID: 30000142, QuantityAvailable: 100, PricePerUnit: 1000, IsActive: true}
+	morphite := &models.ForSaleItem{UserID: sellerID, TypeID: 61, OwnerType: "character", OwnerID: sellerID * 10, LocationID: 30000142, QuantityAvailable: 50, PricePerUnit: 2000, IsActive: true}
+	strangerItem := &models.ForSaleItem{UserID: strangerID, TypeID: 60, OwnerType: "character", OwnerID: strangerID * 10, LocationID: 30000142, QuantityAvailable: 10, PricePerUnit: 900, IsActive: true}
+	for _, item := range []*models.ForSaleItem{megacyte, morphite, strangerItem} {
+		assert.NoError(t, forSaleRepo.Upsert(ctx, item))
+	}
+
+	controller := controllers.NewPurchaseCart(&MockRouter{}, db, cartRepo, ordersRepo, purchaseRepo, forSaleRepo, permRepo, userRepo, nil)
+
+	addToCart := func(itemID, quantity int64) *web.HttpError {
+		body, _ := json.Marshal(map[string]int64{"forSaleItemId": itemID, "quantity": quantity})
+		_, httpErr := controller.AddToCart(&web.HandlerArgs{
+			Request: httptest.NewRequest("POST", "/v1/purchases/cart", bytes.NewReader(body)),
+			User:    &buyerID,
+		})
+		return httpErr
+	}
+
+	assert.Nil(t, addToCart(megacyte.ID, 40))
+	assert.Nil(t, addToCart(morphite.ID, 50))
+
+	// No permission from the stranger
+	httpErr := addToCart(strangerItem.ID, 5)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 403, httpErr.StatusCode)
+
+	// Over the available quantity
+	httpErr = addToCart(megacyte.ID, 101)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+
+	result, httpErr := controller.Checkout(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/purchases/cart/checkout", bytes.NewReader([]byte(`{"notes":"one contract please"}`))),
+		User:    &buyerID,
+	})
+	assert.Nil(t, httpErr)
+
+	response := result.(*controllers.CheckoutResponse)
+	assert.Empty(t, response.Failures)
+	assert.Len(t, response.Orders, 1)
+	order := response.Orders[0]
+	assert.Equal(t, sellerID, order.SellerUserID)
+	assert.Len(t, order.Purchases, 2)
+	assert.Equal(t, float64(40*1000+50*2000), order.TotalPrice)
+	for _, purchase := range order.Purchases {
+		assert.Equal(t, order.ID, *purchase.OrderID)
+	}
+
+	// Cart is emptied and listings reduced
+	cart, err := cartRepo.GetByUser(ctx, buyerID)
+	assert.NoError(t, err)
+	assert.Empty(t, cart)
+
+	updated, err := forSaleRepo.GetByID(ctx, megacyte.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(60), updated.QuantityAvailable)
+	updated, err = forSaleRepo.GetByID(ctx, morphite.ID)
+	assert.NoError(t, err)
+	assert.False(t, updated.IsActive)
+
+	// Single purchases of an order cannot be contracted on their own
+	purchasesController := controllers.NewPurchases(&MockRouter{}, db, purchaseRepo, forSaleRepo, permRepo, userRepo, nil, nil)
+	purchaseID := strconv.FormatInt(order.Purchases[0].ID, 10)
+	_, httpErr = purchasesController.MarkContractCreated(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/purchases/"+purchaseID+"/mark-contract-created", nil),
+		Params:  map[string]string{"id": purchaseID},
+		User:    &sellerID,
+	})
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+
+	// Only the seller can mark the order
+	orderID := strconv.FormatInt(order.ID, 10)
+	_, httpErr = controller.MarkOrderContractCreated(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/purchases/orders/"+orderID+"/mark-contract-created", nil),
+		Params:  map[string]string{"id": orderID},
+		User:    &buyerID,
+	})
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 403, httpErr.StatusCode)
+
+	// Custom keys must be matchable by contract sync
+	_, httpErr = controller.MarkOrderContractCreated(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/purchases/orders/"+orderID+"/mark-contract-created", bytes.NewReader([]byte(`{"contractKey":"PO 1"}`))),
+		Params:  map[string]string{"id": orderID},
+		User:    &sellerID,
+	})
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+
+	result, httpErr = controller.MarkOrderContractCreated(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/purchases/orders/"+orderID+"/mark-contract-created", nil),
+		Params:  map[string]string{"id": orderID},
+		User:    &sellerID,
+	})
+	assert.Nil(t, httpErr)
+	assert.Equal(t, "PO-"+orderID, result.(map[string]string)["contractKey"])
+
+	fetched, err := ordersRepo.GetByID(ctx, order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "contract_created", fetched.Status)
+	assert.Equal(t, "PO-"+orderID, *fetched.ContractKey)
+	for _, purchase := range fetched.Purchases {
+		assert.Equal(t, "contract_created", purchase.Status)
+		assert.Equal(t, "PO-"+orderID, *purchase.ContractKey)
+	}
+
+	// The ESI contract completes the whole order at once
+	completed, err := purchaseRepo.CompleteOrderWithContractID(ctx, order.ID, 123456)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), completed)
+
+	orders, err := ordersRepo.GetByUser(ctx, sellerID)
+	assert.NoError(t, err)
+	assert.Len(t, orders, 1)
+	assert.Equal(t, "completed", orders[0].Status)
+}
+
+func Test_PurchaseCart_CheckoutEmptyCart(t *testing.T) {
+	db := setupPurchasesTestDB(t)
+
+	buyerID := int64(4110)
+	userRepo := repositories.NewUserRepository(db)
+	assert.NoError(t, userRepo.Add(context.Background(), &repositories.User{ID: buyerID, Name: "Empty Cart Buyer"}))
+
+	controller := controllers.NewPurchaseCart(&MockRouter{}, db,
+		repositories.NewPurchaseCart(db),
+		repositories.NewPurchaseOrders(db),
+		repositories.NewPurchaseTransactions(db),
+		repositories.NewForSaleItems(db),
+		repositories.NewContactPermissions(db),
+		userRepo,
+		nil)
+
+	_, httpErr := controller.Checkout(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/purchases/cart/checkout", nil),
+		User:    &buyerID,
+	})
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
@@ -0,0 +1,487 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+type PurchaseOffersRepository interface {
+	Create(ctx context.Context, offer *models.PurchaseOffer, message *string) error
+	GetByID(ctx context.Context, offerID int64) (*models.PurchaseOffer, error)
+	GetByPurchaseID(ctx context.Context, purchaseID int64) (*models.PurchaseOffer, error)
+	GetByUser(ctx context.Context, userID int64) ([]*models.PurchaseOffer, error)
+	Counter(ctx context.Context, offerID, userID, awaitingUserID, quantity int64, pricePerUnit float64, message *string) (bool, error)
+	Close(ctx context.Context, offerID, userID int64, status string, message *string) (bool, error)
+	Accept(ctx context.Context, tx *sql.Tx, offerID, userID, purchaseID int64, message *string) (bool, error)
+}
+
+type PurchasesForOffers interface {
+	Create(ctx context.Context, tx *sql.Tx, purchase *models.PurchaseTransaction) error
+	GetByID(ctx context.Context, purchaseID int64) (*models.PurchaseTransaction, error)
+}
+
+type BuyOrdersForOffers interface {
+	GetByID(ctx context.Context, id int64) (*models.BuyOrder, error)
+}
+
+type PurchaseOfferNotifierInterface interface {
+	NotifyPurchaseOffer(ctx context.Context, offer *models.PurchaseOffer, actorUserID int64, action string)
+}
+
+type PurchaseOffers struct {
+	db                    *sql.DB
+	repository            PurchaseOffersRepository
+	purchaseRepository    PurchasesForOffers
+	forSaleRepository     ForSaleItemsForPurchases
+	buyOrdersRepository   BuyOrdersForOffers
+	permissionsRepository PermissionsForPurchaseCart
+	notifier              PurchaseOfferNotifierInterface
+}
+
+func NewPurchaseOffers(
+	router Routerer,
+	db *sql.DB,
+	repository PurchaseOffersRepository,
+	purchaseRepository PurchasesForOffers,
+	forSaleRepository ForSaleItemsForPurchases,
+	buyOrdersRepository BuyOrdersForOffers,
+	permissionsRepository PermissionsForPurchaseCart,
+	notifier PurchaseOfferNotifierInterface,
+) *PurchaseOffers {
+	controller := &PurchaseOffers{
+		db:                    db,
+		repository:            repository,
+		purchaseRepository:    purchaseRepository,
+		forSaleRepository:     forSaleRepository,
+		buyOrdersRepository:   buyOrdersRepository,
+		permissionsRepository: permissionsRepository,
+		notifier:              notifier,
+	}
+
+	router.RegisterRestAPIRoute("/v1/purchases/offers", web.AuthAccessUser, controller.GetOffers, "GET")
+	router.RegisterRestAPIRoute("/v1/purchases/offers", web.AuthAccessUser, controller.CreateOffer, "POST")
+	router.RegisterRestAPIRoute("/v1/purchases/offers/{id}", web.AuthAccessUser, controller.GetOffer, "GET")
+	router.RegisterRestAPIRoute("/v1/purchases/offers/{id}/counter", web.AuthAccessUser, controller.CounterOffer, "POST")
+	router.RegisterRestAPIRoute("/v1/purchases/offers/{id}/accept", web.AuthAccessUser, controller.AcceptOffer, "POST")
+	router.RegisterRestAPIRoute("/v1/purchases/offers/{id}/reject", web.AuthAccessUser, controller.RejectOffer, "POST")
+	router.RegisterRestAPIRoute("/v1/purchases/offers/{id}/withdraw", web.AuthAccessUser, controller.WithdrawOffer, "POST")
+	router.RegisterRestAPIRoute("/v1/purchases/{id}/offer", web.AuthAccessUser, controller.GetPurchaseOffer, "GET")
+
+	return controller
+}
+
+// CreateOfferRequest opens an offer on a for-sale listing. Without BuyOrderID the caller is the
+// buyer proposing on the listing; with it the caller is the seller proposing their listing
+// against the buy order.
+type CreateOfferRequest struct {
+	ForSaleItemID int64   `json:"forSaleItemId"`
+	BuyOrderID    *int64  `json:"buyOrderId,omitempty"`
+	Quantity      int64   `json:"quantity"`
+	PricePerUnit  float64 `json:"pricePerUnit"`
+	Message       string  `json:"message,omitempty"`
+}
+
+type CounterOfferRequest struct {
+	Quantity     int64   `json:"quantity"`
+	PricePerUnit float64 `json:"pricePerUnit"`
+	Message      string  `json:"message,omitempty"`
+}
+
+type OfferResponseRequest struct {
+	Message string `json:"message,omitempty"`
+}
+
+// GetOffers returns the offers the authenticated user is buying or selling in
+func (c *PurchaseOffers) GetOffers(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	offers, err := c.repository.GetByUser(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get offers")}
+	}
+
+	return offers, nil
+}
+
+// GetOffer returns an offer with its history
+func (c *PurchaseOffers) GetOffer(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	offer, httpErr := c.getParticipantOffer(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	return offer, nil
+}
+
+// GetPurchaseOffer returns the negotiation a purchase came from, or null if it was bought at the
+// listed price
+func (c *PurchaseOffers) GetPurchaseOffer(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	purchaseID, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid purchase ID")}
+	}
+
+	purchase, err := c.purchaseRepository.GetByID(args.Request.Context(), purchaseID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 404, Error: errors.Wrap(err, "purchase not found")}
+	}
+
+	if purchase.BuyerUserID != *args.User && purchase.SellerUserID != *args.User {
+		return nil, &web.HttpError{StatusCode: 403, Error: errors.New("you are not part of this purchase")}
+	}
+
+	offer, err := c.repository.GetByPurchaseID(args.Request.Context(), purchaseID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get purchase offer")}
+	}
+
+	return offer, nil
+}
+
+// CreateOffer opens a negotiation on a listing (buyer) or against a buy order (seller)
+func (c *PurchaseOffers) CreateOffer(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+	ctx := args.Request.Context()
+	userID := *args.User
+
+	var req CreateOfferRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if req.Quantity <= 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("quantity must be positive")}
+	}
+	if req.PricePerUnit <= 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("price must be positive")}
+	}
+
+	item, err := c.forSaleRepository.GetByID(ctx, req.ForSaleItemID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 404, Error: errors.Wrap(err, "for-sale item not found")}
+	}
+	if !item.IsActive {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("listing is no longer active")}
+	}
+	if req.Quantity > item.QuantityAvailable {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("requested quantity exceeds available quantity")}
+	}
+
+	offer := &models.PurchaseOffer{
+		ForSaleItemID: item.ID,
+		SellerUserID:  item.UserID,
+		TypeID:        item.TypeID,
+		Quantity:      req.Quantity,
+		PricePerUnit:  req.PricePerUnit,
+	}
+
+	if req.BuyOrderID == nil {
+		// Buyer proposing on a listing: the seller must let them browse
+		if userID == item.UserID {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.New("cannot make an offer on your own listing")}
+		}
+
+		hasPermission, err := c.permissionsRepository.CheckPermission(ctx, item.UserID, userID, "for_sale_browse")
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to check permission")}
+		}
+		if !hasPermission {
+			return nil, &web.HttpError{StatusCode: 403, Error: errors.New("you do not have permission to purchase from this seller")}
+		}
+
+		offer.BuyerUserID = userID
+		offer.AwaitingUserID = &item.UserID
+	} else {
+		// Seller proposing their listing against a buy order they can see as demand
+		if userID != item.UserID {
+			return nil, &web.HttpError{StatusCode: 403, Error: errors.New("you can only offer your own listings against a buy order")}
+		}
+
+		order, err := c.buyOrdersRepository.GetByID(ctx, *req.BuyOrderID)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: 404, Error: errors.Wrap(err, "buy order not found")}
+		}
+		if !order.IsActive {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.New("buy order is no longer active")}
+		}
+		if order.BuyerUserID == userID {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.New("cannot make an offer on your own buy order")}
+		}
+		if order.TypeID != item.TypeID {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.New("listing does not match the buy order item type")}
+		}
+
+		hasPermission, err := c.permissionsRepository.CheckPermission(ctx, order.BuyerUserID, userID, "for_sale_browse")
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to check permission")}
+		}
+		if !hasPermission {
+			return nil, &web.HttpError{StatusCode: 403, Error: errors.New("you do not have permission to see this buy order")}
+		}
+
+		offer.BuyOrderID = &order.ID
+		offer.BuyerUserID = order.BuyerUserID
+		offer.AwaitingUserID = &order.BuyerUserID
+	}
+
+	if err := c.repository.Create(ctx, offer, optionalMessage(req.Message)); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to create offer")}
+	}
+
+	return c.reloadAndNotify(ctx, offer.ID, userID, "proposed")
+}
+
+// CounterOffer replaces the terms of an offer awaiting the authenticated user and hands it back
+func (c *PurchaseOffers) CounterOffer(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+	ctx := args.Request.Context()
+	userID := *args.User
+
+	offer, httpErr := c.getParticipantOffer(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	var req CounterOfferRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if req.Quantity <= 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("quantity must be positive")}
+	}
+	if req.PricePerUnit <= 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("price must be positive")}
+	}
+
+	if httpErr := checkAwaiting(offer, userID); httpErr != nil {
+		return nil, httpErr
+	}
+
+	item, err := c.forSaleRepository.GetByID(ctx, offer.ForSaleItemID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 404, Error: errors.Wrap(err, "for-sale item not found")}
+	}
+	if req.Quantity > item.QuantityAvailable {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("requested quantity exceeds available quantity")}
+	}
+
+	otherUserID := offer.BuyerUserID
+	if userID == offer.BuyerUserID {
+		otherUserID = offer.SellerUserID
+	}
+
+	countered, err := c.repository.Counter(ctx, offer.ID, userID, otherUserID, req.Quantity, req.PricePerUnit, optionalMessage(req.Message))
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to counter offer")}
+	}
+	if !countered {
+		return nil, &web.HttpError{StatusCode: 409, Error: errors.New("offer changed while countering")}
+	}
+
+	return c.reloadAndNotify(ctx, offer.ID, userID, "countered")
+}
+
+// AcceptOffer accepts the current terms of an offer awaiting the authenticated user, turning it
+// into a pending purchase at the negotiated price
+func (c *PurchaseOffers) AcceptOffer(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+	ctx := args.Request.Context()
+	userID := *args.User
+
+	offer, httpErr := c.getParticipantOffer(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	req, httpErr := decodeOfferResponse(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	if httpErr := checkAwaiting(offer, userID); httpErr != nil {
+		return nil, httpErr
+	}
+
+	item, err := c.forSaleRepository.GetByID(ctx, offer.ForSaleItemID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 404, Error: errors.Wrap(err, "for-sale item not found")}
+	}
+	if !item.IsActive {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("listing is no longer active")}
+	}
+	if offer.Quantity > item.QuantityAvailable {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("offered quantity exceeds available quantity")}
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to begin transaction")}
+	}
+	defer tx.Rollback()
+
+	err = c.forSaleRepository.UpdateQuantity(ctx, tx, item.ID, item.QuantityAvailable-offer.Quantity)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to update quantity")}
+	}
+
+	purchase := &models.PurchaseTransaction{
+		ForSaleItemID:     item.ID,
+		BuyerUserID:       offer.BuyerUserID,
+		SellerUserID:      offer.SellerUserID,
+		TypeID:            offer.TypeID,
+		QuantityPurchased: offer.Quantity,
+		PricePerUnit:      offer.PricePerUnit,
+		TotalPrice:        float64(offer.Quantity) * offer.PricePerUnit,
+		Status:            "pending",
+		BuyOrderID:        offer.BuyOrderID,
+		Blueprint:         item.Blueprint,
+	}
+	if err := c.purchaseRepository.Create(ctx, tx, purchase); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to create purchase transaction")}
+	}
+
+	accepted, err := c.repository.Accept(ctx, tx, offer.ID, userID, purchase.ID, optionalMessage(req.Message))
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to accept offer")}
+	}
+	if !accepted {
+		return nil, &web.HttpError{StatusCode: 409, Error: errors.New("offer changed while accepting")}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to commit transaction")}
+	}
+
+	return c.reloadAndNotify(ctx, offer.ID, userID, "accepted")
+}
+
+// RejectOffer declines an offer awaiting the authenticated user, ending the negotiation
+func (c *PurchaseOffers) RejectOffer(args *web.HandlerArgs) (any, *web.HttpError) {
+	return c.closeOffer(args, "rejected")
+}
+
+// WithdrawOffer takes back the authenticated user's own terms before the other side responds
+func (c *PurchaseOffers) WithdrawOffer(args *web.HandlerArgs) (any, *web.HttpError) {
+	return c.closeOffer(args, "withdrawn")
+}
+
+func (c *PurchaseOffers) closeOffer(args *web.HandlerArgs, status string) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+	ctx := args.Request.Context()
+	userID := *args.User
+
+	offer, httpErr := c.getParticipantOffer(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	req, httpErr := decodeOfferResponse(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	if offer.Status != "open" {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Errorf("offer is already %s", offer.Status)}
+	}
+
+	closed, err := c.repository.Close(ctx, offer.ID, userID, status, optionalMessage(req.Message))
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to close offer")}
+	}
+	if !closed {
+		if status == "rejected" {
+			return nil, &web.HttpError{StatusCode: 403, Error: errors.New("only the side the offer is waiting on can reject it")}
+		}
+		return nil, &web.HttpError{StatusCode: 403, Error: errors.New("only the side that made the current terms can withdraw them")}
+	}
+
+	return c.reloadAndNotify(ctx, offer.ID, userID, status)
+}
+
+// getParticipantOffer loads the offer named in the route and checks the user is its buyer or seller
+func (c *PurchaseOffers) getParticipantOffer(args *web.HandlerArgs) (*models.PurchaseOffer, *web.HttpError) {
+	offerID, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid offer ID")}
+	}
+
+	offer, err := c.repository.GetByID(args.Request.Context(), offerID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get offer")}
+	}
+	if offer == nil {
+		return nil, &web.HttpError{StatusCode: 404, Error: errors.New("offer not found")}
+	}
+
+	if offer.BuyerUserID != *args.User && offer.SellerUserID != *args.User {
+		return nil, &web.HttpError{StatusCode: 403, Error: errors.New("you are not part of this offer")}
+	}
+
+	return offer, nil
+}
+
+func (c *PurchaseOffers) reloadAndNotify(ctx context.Context, offerID, actorUserID int64, action string) (any, *web.HttpError) {
+	offer, err := c.repository.GetByID(ctx, offerID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get offer")}
+	}
+
+	if c.notifier != nil && offer != nil {
+		go c.notifier.NotifyPurchaseOffer(context.Background(), offer, actorUserID, action)
+	}
+
+	return offer, nil
+}
+
+func checkAwaiting(offer *models.PurchaseOffer, userID int64) *web.HttpError {
+	if offer.Status != "open" {
+		return &web.HttpError{StatusCode: 400, Error: errors.Errorf("offer is already %s", offer.Status)}
+	}
+	if offer.AwaitingUserID == nil || *offer.AwaitingUserID != userID {
+		return &web.HttpError{StatusCode: 403, Error: errors.New("offer is waiting on the other side")}
+	}
+	return nil
+}
+
+func decodeOfferResponse(args *web.HandlerArgs) (*OfferResponseRequest, *web.HttpError) {
+	var req OfferResponseRequest
+	if args.Request.Body != nil {
+		err := json.NewDecoder(args.Request.Body).Decode(&req)
+		if err != nil && err.Error() != "EOF" {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+		}
+	}
+	return &req, nil
+}
+
+func optionalMessage(message string) *string {
+	if message == "" {
+		return nil
+	}
+	return &message
+}
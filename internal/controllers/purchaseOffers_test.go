@@ -0,0 +1,231 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+)
+
+func grantForSaleBrowse(t *testing.T, ctx context.Context, contactsRepo *repositories.Contacts, permRepo *repositories.ContactPermissions, grantingUserID, receivingUserID int64) {
+	contact, err := contactsRepo.Create(ctx, receivingUserID, grantingUserID)
+	assert.NoError(t, err)
+	_, err = contactsRepo.UpdateStatus(ctx, contact.ID, grantingUserID, "accepted")
+	assert.NoError(t, err)
+	assert.NoError(t, permRepo.Upsert(ctx, &models.ContactPermission{
+		ContactID:       contact.ID,
+		GrantingUserID:  grantingUserID,
+		ReceivingUserID: receivingUserID,
+		ServiceType:     "for_sale_browse",
+		CanAccess:       true,
+	}))
+}
+
+func Test_PurchaseOffers_CounterAndAcceptCreatesPurchase(t *testing.T) {
+	db := setupPurchasesTestDB(t)
+	ctx := context.Background()
+
+	buyerID := int64(4200)
+	sellerID := int64(4201)
+
+	userRepo := repositories.NewUserRepository(db)
+	charRepo := repositories.NewCharacterRepository(db)
+	itemTypesRepo := repositories.NewItemTypeRepository(db)
+	forSaleRepo := repositories.NewForSaleItems(db)
+	permRepo := repositories.NewContactPermissions(db)
+	contactsRepo := repositories.NewContacts(db)
+	purchaseRepo := repositories.NewPurchaseTransactions(db)
+	offersRepo := repositories.NewPurchaseOffers(db)
+
+	for _, user := range []*repositories.User{
+		{ID: buyerID, Name: "Offer Buyer"},
+		{ID: sellerID, Name: "Offer Seller"},
+	} {
+		assert.NoError(t, userRepo.Add(ctx, user))
+		assert.NoError(t, charRepo.Add(ctx, &repositories.Character{ID: user.ID * 10, Name: user.Name + " Char", UserID: user.ID}))
+	}
+	assert.NoError(t, itemTypesRepo.UpsertItemTypes(ctx, []models.EveInventoryType{{TypeID: 62, TypeName: "Zydrine", Volume: 0.01}}))
+	grantForSaleBrowse(t, ctx, contactsRepo, permRepo, sellerID, buyerID)
+
+	item := &models.ForSaleItem{UserID: sellerID, TypeID: 62, OwnerType: "character", OwnerID: sellerID * 10, LocationID: 30000142, QuantityAvailable: 100, PricePerUnit: 1000, IsActive: true}
+	assert.NoError(t, forSaleRepo.Upsert(ctx, item))
+
+	controller := controllers.NewPurchaseOffers(&MockRouter{}, db, offersRepo, purchaseRepo, forSaleRepo, repositories.NewBuyOrders(db), permRepo, nil)
+
+	post := func(path string, offerID int64, userID int64, body any, handler func(*web.HandlerArgs) (any, *web.HttpError)) (*models.PurchaseOffer, *web.HttpError) {
+		payload, _ := json.Marshal(body)
+		result, httpErr := handler(&web.HandlerArgs{
+			Request: httptest.NewRequest("POST", path, bytes.NewReader(payload)),
+			Params:  map[string]string{"id": strconv.FormatInt(offerID, 10)},
+			User:    &userID,
+		})
+		if httpErr != nil {
+			return nil, httpErr
+		}
+		return result.(*models.PurchaseOffer), nil
+	}
+
+	// Buyer proposes below the list price
+	offer, httpErr := post("/v1/purchases/offers", 0, buyerID, map[string]any{
+		"forSaleItemId": item.ID, "quantity": 40, "pricePerUnit": 800, "message": "bulk discount?",
+	}, controller.CreateOffer)
+	assert.Nil(t, httpErr)
+	assert.Equal(t, "open", offer.Status)
+	assert.Equal(t, sellerID, *offer.AwaitingUserID)
+	assert.Equal(t, float64(1000), offer.ListPrice)
+
+	// The buyer cannot accept their own terms
+	_, httpErr = post("/accept", offer.ID, buyerID, map[string]any{}, controller.AcceptOffer)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 403, httpErr.StatusCode)
+
+	// Seller counters, buyer accepts
+	offer, httpErr = post("/counter", offer.ID, sellerID, map[string]any{"quantity": 40, "pricePerUnit": 900}, controller.CounterOffer)
+	assert.Nil(t, httpErr)
+	assert.Equal(t, buyerID, *offer.AwaitingUserID)
+
+	offer, httpErr = post("/accept", offer.ID, buyerID, map[string]any{}, controller.AcceptOffer)
+	assert.Nil(t, httpErr)
+	assert.Equal(t, "accepted", offer.Status)
+	assert.NotNil(t, offer.PurchaseID)
+
+	actions := []string{}
+	for _, round := range offer.Rounds {
+		actions = append(actions, round.Action)
+	}
+	assert.Equal(t, []string{"proposed", "countered", "accepted"}, actions)
+	assert.Equal(t, "bulk discount?", *offer.Rounds[0].Message)
+
+	purchase, err := purchaseRepo.GetByID(ctx, *offer.PurchaseID)
+	assert.NoError(t, err)
+	assert.Equal(t, "pending", purchase.Status)
+	assert.Equal(t, int64(40), purchase.QuantityPurchased)
+	assert.Equal(t, float64(900), purchase.PricePerUnit)
+	assert.Equal(t, float64(36000), purchase.TotalPrice)
+
+	updated, err := forSaleRepo.GetByID(ctx, item.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(60), updated.QuantityAvailable)
+
+	// The history stays with the purchase
+	purchaseID := strconv.FormatInt(purchase.ID, 10)
+	result, httpErr := controller.GetPurchaseOffer(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/purchases/"+purchaseID+"/offer", nil),
+		Params:  map[string]string{"id": purchaseID},
+		User:    &sellerID,
+	})
+	assert.Nil(t, httpErr)
+	assert.Equal(t, offer.ID, result.(*models.PurchaseOffer).ID)
+	assert.Len(t, result.(*models.PurchaseOffer).Rounds, 3)
+
+	// A closed offer cannot be countered again
+	_, httpErr = post("/counter", offer.ID, sellerID, map[string]any{"quantity": 40, "pricePerUnit": 950}, controller.CounterOffer)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
+func Test_PurchaseOffers_SellerOffersAgainstBuyOrder(t *testing.T) {
+	db := setupPurchasesTestDB(t)
+	ctx := context.Background()
+
+	buyerID := int64(4210)
+	sellerID := int64(4211)
+
+	userRepo := repositories.NewUserRepository(db)
+	charRepo := repositories.NewCharacterRepository(db)
+	itemTypesRepo := repositories.NewItemTypeRepository(db)
+	forSaleRepo := repositories.NewForSaleItems(db)
+	permRepo := repositories.NewContactPermissions(db)
+	contactsRepo := repositories.NewContacts(db)
+	buyOrdersRepo := repositories.NewBuyOrders(db)
+	offersRepo := repositories.NewPurchaseOffers(db)
+
+	for _, user := range []*repositories.User{
+		{ID: buyerID, Name: "Order Buyer"},
+		{ID: sellerID, Name: "Order Seller"},
+	} {
+		assert.NoError(t, userRepo.Add(ctx, user))
+		assert.NoError(t, charRepo.Add(ctx, &repositories.Character{ID: user.ID * 10, Name: user.Name + " Char", UserID: user.ID}))
+	}
+	assert.NoError(t, itemTypesRepo.UpsertItemTypes(ctx, []models.EveInventoryType{
+		{TypeID: 63, TypeName: "Nocxium", Volume: 0.01},
+		{TypeID: 64, TypeName: "Isogen", Volume: 0.01},
+	}))
+
+	item := &models.ForSaleItem{UserID: sellerID, TypeID: 63, OwnerType: "character", OwnerID: sellerID * 10, LocationID: 30000142, QuantityAvailable: 500, PricePerUnit: 700, IsActive: true}
+	otherItem := &models.ForSaleItem{UserID: sellerID, TypeID: 64, OwnerType: "character", OwnerID: sellerID * 10, LocationID: 30000142, QuantityAvailable: 500, PricePerUnit: 50, IsActive: true}
+	for _, listing := range []*models.ForSaleItem{item, otherItem} {
+		assert.NoError(t, forSaleRepo.Upsert(ctx, listing))
+	}
+
+	order := &models.BuyOrder{BuyerUserID: buyerID, TypeID: 63, LocationID: 30000142, QuantityDesired: 200, MinPricePerUnit: 500, MaxPricePerUnit: 600, IsActive: true}
+	assert.NoError(t, buyOrdersRepo.Create(ctx, order))
+
+	controller := controllers.NewPurchaseOffers(&MockRouter{}, db, offersRepo, repositories.NewPurchaseTransactions(db), forSaleRepo, buyOrdersRepo, permRepo, nil)
+
+	createOffer := func(itemID int64) (any, *web.HttpError) {
+		body, _ := json.Marshal(map[string]any{"forSaleItemId": itemID, "buyOrderId": order.ID, "quantity": 200, "pricePerUnit": 650})
+		return controller.CreateOffer(&web.HandlerArgs{
+			Request: httptest.NewRequest("POST", "/v1/purchases/offers", bytes.NewReader(body)),
+			User:    &sellerID,
+		})
+	}
+
+	// The buyer has not shared their demand with this seller yet
+	_, httpErr := createOffer(item.ID)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 403, httpErr.StatusCode)
+
+	grantForSaleBrowse(t, ctx, contactsRepo, permRepo, buyerID, sellerID)
+
+	// The listing has to be the item the buyer wants
+	_, httpErr = createOffer(otherItem.ID)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+
+	result, httpErr := createOffer(item.ID)
+	assert.Nil(t, httpErr)
+	offer := result.(*models.PurchaseOffer)
+	assert.Equal(t, buyerID, offer.BuyerUserID)
+	assert.Equal(t, order.ID, *offer.BuyOrderID)
+	assert.Equal(t, buyerID, *offer.AwaitingUserID)
+
+	offerID := strconv.FormatInt(offer.ID, 10)
+
+	// Only the seller who made the terms can withdraw them
+	_, httpErr = controller.WithdrawOffer(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/purchases/offers/"+offerID+"/withdraw", nil),
+		Params:  map[string]string{"id": offerID},
+		User:    &buyerID,
+	})
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 403, httpErr.StatusCode)
+
+	result, httpErr = controller.RejectOffer(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/purchases/offers/"+offerID+"/reject", bytes.NewReader([]byte(`{"message":"too high"}`))),
+		Params:  map[string]string{"id": offerID},
+		User:    &buyerID,
+	})
+	assert.Nil(t, httpErr)
+	rejected := result.(*models.PurchaseOffer)
+	assert.Equal(t, "rejected", rejected.Status)
+	assert.Nil(t, rejected.AwaitingUserID)
+	assert.Equal(t, "too high", *rejected.Rounds[len(rejected.Rounds)-1].Message)
+
+	offers, err := offersRepo.GetByUser(ctx, sellerID)
+	assert.NoError(t, err)
+	assert.Len(t, offers, 1)
+
+	// Listing quantity is untouched by a rejected offer
+	unchanged, err := forSaleRepo.GetByID(ctx, item.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(500), unchanged.QuantityAvailable)
+}
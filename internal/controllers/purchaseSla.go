@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+type PurchaseSlaSettingsRepository interface {
+	GetByUser(ctx context.Context, userID int64) (*models.PurchaseSlaSettings, error)
+	Upsert(ctx context.Context, settings *models.PurchaseSlaSettings) error
+}
+
+type PurchaseSla struct {
+	repository PurchaseSlaSettingsRepository
+}
+
+func NewPurchaseSla(router Routerer, repository PurchaseSlaSettingsRepository) *PurchaseSla {
+	c := &PurchaseSla{
+		repository: repository,
+	}
+
+	router.RegisterRestAPIRoute("/v1/purchases/sla-settings", web.AuthAccessUser, c.GetSettings, "GET")
+	router.RegisterRestAPIRoute("/v1/purchases/sla-settings", web.AuthAccessUser, c.UpdateSettings, "PUT")
+
+	return c
+}
+
+// GetSettings returns the seller's purchase SLA timers
+func (c *PurchaseSla) GetSettings(args *web.HandlerArgs) (any, *web.HttpError) {
+	settings, err := c.repository.GetByUser(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get sla settings")}
+	}
+
+	return settings, nil
+}
+
+type updatePurchaseSlaRequest struct {
+	ContractHours *int `json:"contractHours"`
+	AcceptDays    *int `json:"acceptDays"`
+}
+
+// UpdateSettings saves the seller's purchase SLA timers. A null timer turns it off.
+func (c *PurchaseSla) UpdateSettings(args *web.HandlerArgs) (any, *web.HttpError) {
+	var req updatePurchaseSlaRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if req.ContractHours != nil && *req.ContractHours <= 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("contractHours must be positive")}
+	}
+	if req.AcceptDays != nil && *req.AcceptDays <= 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("acceptDays must be positive")}
+	}
+
+	settings := &models.PurchaseSlaSettings{
+		UserID:        *args.User,
+		ContractHours: req.ContractHours,
+		AcceptDays:    req.AcceptDays,
+	}
+
+	if err := c.repository.Upsert(args.Request.Context(), settings); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to save sla settings")}
+	}
+
+	return settings, nil
+}
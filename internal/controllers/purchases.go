@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strconv"
 
 	"github.com/annymsMthd/industry-tool/internal/models"
@@ -25,6 +26,7 @@ type PurchaseTransactionsRepository interface {
 type ForSaleItemsForPurchases interface {
 	GetByID(ctx context.Context, itemID int64) (*models.ForSaleItem, error)
 	UpdateQuantity(ctx context.Context, tx *sql.Tx, itemID int64, newQuantity int64) error
+	GetPriceForBuyer(ctx context.Context, item *models.ForSaleItem, buyerUserID int64) (float64, error)
 }
 
 type PurchaseNotifierInterface interface {
@@ -130,21 +132,27 @@ func (c *Purchases) PurchaseItem(args *web.HandlerArgs) (any, *web.HttpError) {
 		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("requested quantity exceeds available quantity")}
 	}
 
-	// 5. Begin transaction
+	// 5. Resolve the buyer's price, which may come from one of the seller's contact groups
+	pricePerUnit, err := c.forSaleRepository.GetPriceForBuyer(args.Request.Context(), item, buyerUserID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get price")}
+	}
+
+	// 6. Begin transaction
 	tx, err := c.db.BeginTx(args.Request.Context(), nil)
 	if err != nil {
 		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to begin transaction")}
 	}
 	defer tx.Rollback()
 
-	// 6. Update quantity
+	// 7. Update quantity
 	newQuantity := item.QuantityAvailable - req.QuantityPurchased
 	err = c.forSaleRepository.UpdateQuantity(args.Request.Context(), tx, item.ID, newQuantity)
 	if err != nil {
 		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to update quantity")}
 	}
 
-	// 7. Create purchase record
+	// 8. Create purchase record
 	var notes *string
 	if req.Notes != "" {
 		notes = &req.Notes
@@ -156,10 +164,11 @@ func (c *Purchases) PurchaseItem(args *web.HandlerArgs) (any, *web.HttpError) {
 		SellerUserID:      item.UserID,
 		TypeID:            item.TypeID,
 		QuantityPurchased: req.QuantityPurchased,
-		PricePerUnit:      item.PricePerUnit,
-		TotalPrice:        float64(req.QuantityPurchased) * item.PricePerUnit,
+		PricePerUnit:      pricePerUnit,
+		TotalPrice:        float64(req.QuantityPurchased) * pricePerUnit,
 		Status:            "pending",
 		TransactionNotes:  notes,
+		Blueprint:         item.Blueprint,
 	}
 
 	err = c.repository.Create(args.Request.Context(), tx, purchase)
@@ -167,13 +176,13 @@ func (c *Purchases) PurchaseItem(args *web.HandlerArgs) (any, *web.HttpError) {
 		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to create purchase transaction")}
 	}
 
-	// 8. Commit transaction
+	// 9. Commit transaction
 	err = tx.Commit()
 	if err != nil {
 		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to commit transaction")}
 	}
 
-	// 9. Populate fields for notification display
+	// 10. Populate fields for notification display
 	purchase.TypeName = item.TypeName
 	purchase.LocationName = item.LocationName
 	purchase.LocationID = item.LocationID
@@ -181,7 +190,7 @@ func (c *Purchases) PurchaseItem(args *web.HandlerArgs) (any, *web.HttpError) {
 		purchase.BuyerName = buyerName
 	}
 
-	// 10. Send notifications (non-blocking, never fails the purchase)
+	// 11. Send notifications (non-blocking, never fails the purchase)
 	if c.notifier != nil {
 		go c.notifier.NotifyPurchase(context.Background(), purchase)
 	}
@@ -250,6 +259,20 @@ type MarkContractCreatedRequest struct {
 	ContractKey *string `json:"contractKey,omitempty"`
 }
 
+// contractKeyPattern matches the keys contract sync can find in a contract description
+var contractKeyPattern = regexp.MustCompile(`^[\w-]+$`)
+
+// validateContractKey rejects custom contract keys that contract sync could not match
+func validateContractKey(contractKey *string) *web.HttpError {
+	if contractKey == nil || *contractKey == "" {
+		return nil
+	}
+	if !contractKeyPattern.MatchString(*contractKey) {
+		return &web.HttpError{StatusCode: 400, Error: errors.New("contract key may only contain letters, digits, underscores and hyphens")}
+	}
+	return nil
+}
+
 // MarkContractCreated marks a purchase as contract_created (seller action)
 func (c *Purchases) MarkContractCreated(args *web.HandlerArgs) (any, *web.HttpError) {
 	if args.User == nil {
@@ -275,6 +298,9 @@ func (c *Purchases) MarkContractCreated(args *web.HandlerArgs) (any, *web.HttpEr
 			return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
 		}
 	}
+	if httpErr := validateContractKey(req.ContractKey); httpErr != nil {
+		return nil, httpErr
+	}
 
 	// Get purchase to verify seller
 	purchase, err := c.repository.GetByID(args.Request.Context(), purchaseID)
@@ -287,6 +313,11 @@ func (c *Purchases) MarkContractCreated(args *web.HandlerArgs) (any, *web.HttpEr
 		return nil, &web.HttpError{StatusCode: 403, Error: errors.New("you are not the seller of this purchase")}
 	}
 
+	// Purchases checked out together are contracted as one order
+	if purchase.OrderID != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("purchase is part of an order; mark the order contract created instead")}
+	}
+
 	// Verify status is pending
 	if purchase.Status != "pending" {
 		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("purchase must be in pending status")}
@@ -353,9 +384,9 @@ func (c *Purchases) CompletePurchase(args *web.HandlerArgs) (any, *web.HttpError
 		return nil, &web.HttpError{StatusCode: 403, Error: errors.New("you are not the buyer of this purchase")}
 	}
 
-	// Verify status is contract_created
-	if purchase.Status != "contract_created" {
-		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("purchase must be in contract_created status")}
+	// Verify status is contract_created, or disputed when the buyer accepts the contract anyway
+	if purchase.Status != "contract_created" && purchase.Status != "disputed" {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("purchase must be in contract_created or disputed status")}
 	}
 
 	// Update status
@@ -400,6 +431,11 @@ func (c *Purchases) CancelPurchase(args *web.HandlerArgs) (any, *web.HttpError)
 		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("cannot cancel completed purchase")}
 	}
 
+	// Expiry already restored the quantity
+	if purchase.Status == "expired" {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("cannot cancel expired purchase")}
+	}
+
 	// If cancelling, restore the quantity to the for-sale item
 	tx, err := c.db.BeginTx(args.Request.Context(), nil)
 	if err != nil {
@@ -749,6 +749,22 @@ func Test_MarkContractCreated_AutoGeneratesKey(t *testing.T) {
 	assert.Equal(t, expectedKey, *updated.ContractKey)
 }
 
+func Test_MarkContractCreated_RejectsInvalidKey(t *testing.T) {
+	controller := controllers.NewPurchases(&MockRouter{}, nil, nil, nil, nil, nil, nil, nil)
+
+	sellerID := int64(4092)
+	for _, key := range []string{"PT 1", "PT-1;", "<b>PT-1</b>"} {
+		body, _ := json.Marshal(map[string]interface{}{"contractKey": key})
+		_, httpErr := controller.MarkContractCreated(&web.HandlerArgs{
+			Request: httptest.NewRequest("POST", "/v1/purchases/1/mark-contract-created", bytes.NewReader(body)),
+			Params:  map[string]string{"id": "1"},
+			User:    &sellerID,
+		})
+		assert.NotNil(t, httpErr, key)
+		assert.Equal(t, 400, httpErr.StatusCode, key)
+	}
+}
+
 func Test_MarkContractCreated_UsesProvidedKey(t *testing.T) {
 	db := setupPurchasesTestDB(t)
 
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/calculator"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+type StockpileConsumptionRepository interface {
+	GetConsumptionByUser(ctx context.Context, userID int64, windowDays int) ([]*models.StockpileConsumption, error)
+}
+
+type StockpileConsumption struct {
+	repository StockpileConsumptionRepository
+}
+
+func NewStockpileConsumption(router Routerer, repository StockpileConsumptionRepository) *StockpileConsumption {
+	controller := &StockpileConsumption{
+		repository: repository,
+	}
+
+	router.RegisterRestAPIRoute("/v1/stockpiles/consumption", web.AuthAccessUser, controller.GetConsumption, "GET")
+
+	return controller
+}
+
+// GetConsumption returns the burn rate, projected run-out date and suggested desired quantity of
+// every stockpile marker. coverDays overrides the default cover for markers without their own.
+func (c *StockpileConsumption) GetConsumption(args *web.HandlerArgs) (any, *web.HttpError) {
+	coverDays := calculator.DefaultStockpileCoverDays
+	if s := args.Request.URL.Query().Get("coverDays"); s != "" {
+		days, err := strconv.Atoi(s)
+		if err != nil || days <= 0 {
+			return nil, &web.HttpError{
+				StatusCode: http.StatusBadRequest,
+				Error:      errors.New("coverDays must be a positive integer"),
+			}
+		}
+		coverDays = days
+	}
+
+	consumption, err := c.repository.GetConsumptionByUser(args.Request.Context(), *args.User, calculator.StockpileConsumptionWindowDays)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to get stockpile consumption"),
+		}
+	}
+
+	now := time.Now()
+	for _, item := range consumption {
+		calculator.CalculateStockpileConsumption(item, calculator.StockpileConsumptionWindowDays, coverDays, now)
+	}
+
+	return consumption, nil
+}
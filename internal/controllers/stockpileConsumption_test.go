@@ -0,0 +1,76 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStockpileConsumptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockStockpileConsumptionRepository) GetConsumptionByUser(ctx context.Context, userID int64, windowDays int) ([]*models.StockpileConsumption, error) {
+	args := m.Called(ctx, userID, windowDays)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StockpileConsumption), args.Error(1)
+}
+
+func Test_StockpileConsumption_GetConsumption_ProjectsRunOut(t *testing.T) {
+	mockRepo := new(MockStockpileConsumptionRepository)
+	controller := controllers.NewStockpileConsumption(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	markerCover := 3
+	mockRepo.On("GetConsumptionByUser", mock.Anything, userID, 30).Return([]*models.StockpileConsumption{
+		{MarkerID: 1, TypeID: 34, HeldQuantity: 4000, HistoryDays: 10, AssetConsumed: 10000},
+		{MarkerID: 2, TypeID: 35, HeldQuantity: 4000, HistoryDays: 10, AssetConsumed: 10000, CoverDays: &markerCover},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/v1/stockpiles/consumption?coverDays=7", nil)
+	result, httpErr := controller.GetConsumption(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	consumption := result.([]*models.StockpileConsumption)
+	assert.Equal(t, 1000.0, consumption[0].BurnRate)
+	assert.Equal(t, 4.0, *consumption[0].DaysRemaining)
+	assert.NotNil(t, consumption[0].ProjectedRunOutAt)
+	assert.Equal(t, int64(7000), *consumption[0].SuggestedDesiredQuantity)
+	assert.Equal(t, int64(3000), *consumption[1].SuggestedDesiredQuantity)
+}
+
+func Test_StockpileConsumption_GetConsumption_InvalidCoverDays(t *testing.T) {
+	mockRepo := new(MockStockpileConsumptionRepository)
+	controller := controllers.NewStockpileConsumption(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	req := httptest.NewRequest("GET", "/v1/stockpiles/consumption?coverDays=0", nil)
+	result, httpErr := controller.GetConsumption(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, result)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "GetConsumptionByUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_StockpileConsumption_GetConsumption_RepositoryError(t *testing.T) {
+	mockRepo := new(MockStockpileConsumptionRepository)
+	controller := controllers.NewStockpileConsumption(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("GetConsumptionByUser", mock.Anything, userID, 30).Return(nil, errors.New("db error"))
+
+	req := httptest.NewRequest("GET", "/v1/stockpiles/consumption", nil)
+	result, httpErr := controller.GetConsumption(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, result)
+	assert.Equal(t, 500, httpErr.StatusCode)
+}
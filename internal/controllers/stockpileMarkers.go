@@ -54,6 +54,10 @@ func (c *StockpileMarkers) UpsertStockpile(args *web.HandlerArgs) (any, *web.Htt
 		}
 	}
 
+	if httpErr := validateStockpileCover(&marker); httpErr != nil {
+		return nil, httpErr
+	}
+
 	// Set user ID from auth context
 	marker.UserID = *args.User
 
@@ -92,3 +96,19 @@ func (c *StockpileMarkers) DeleteStockpile(args *web.HandlerArgs) (any, *web.Htt
 
 	return nil, nil
 }
+
+func validateStockpileCover(marker *models.StockpileMarker) *web.HttpError {
+	if marker.CoverDays != nil && *marker.CoverDays <= 0 {
+		return &web.HttpError{StatusCode: 400, Error: errors.New("coverDays must be positive")}
+	}
+	if marker.MinDesiredQuantity != nil && *marker.MinDesiredQuantity < 0 {
+		return &web.HttpError{StatusCode: 400, Error: errors.New("minDesiredQuantity cannot be negative")}
+	}
+	if marker.MaxDesiredQuantity != nil && *marker.MaxDesiredQuantity < 0 {
+		return &web.HttpError{StatusCode: 400, Error: errors.New("maxDesiredQuantity cannot be negative")}
+	}
+	if marker.MinDesiredQuantity != nil && marker.MaxDesiredQuantity != nil && *marker.MinDesiredQuantity > *marker.MaxDesiredQuantity {
+		return &web.HttpError{StatusCode: 400, Error: errors.New("minDesiredQuantity cannot exceed maxDesiredQuantity")}
+	}
+	return nil
+}
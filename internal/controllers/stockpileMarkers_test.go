@@ -198,6 +198,72 @@ func Test_StockpileMarkersController_UpsertStockpile_RepositoryError(t *testing.
 	mockRepo.AssertExpectations(t)
 }
 
+func Test_StockpileMarkersController_UpsertStockpile_InvalidCoverBounds(t *testing.T) {
+	mockRepo := new(MockStockpileMarkersRepository)
+	mockRouter := &MockRouter{}
+
+	controller := controllers.NewStockpileMarkers(mockRouter, mockRepo)
+
+	userID := int64(42)
+	minQuantity := int64(5000)
+	maxQuantity := int64(1000)
+	autoAdjust := true
+	marker := models.StockpileMarker{
+		TypeID:             34,
+		OwnerType:          "character",
+		OwnerID:            1337,
+		LocationID:         60003760,
+		DesiredQuantity:    1000,
+		MinDesiredQuantity: &minQuantity,
+		MaxDesiredQuantity: &maxQuantity,
+		AutoAdjustEnabled:  &autoAdjust,
+	}
+
+	body, _ := json.Marshal(marker)
+	req := httptest.NewRequest("POST", "/v1/stockpiles", bytes.NewReader(body))
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+	}
+
+	result, httpErr := controller.UpsertStockpile(args)
+
+	assert.Nil(t, result)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func Test_StockpileMarkersController_UpsertStockpile_NegativeMinQuantity(t *testing.T) {
+	mockRepo := new(MockStockpileMarkersRepository)
+	mockRouter := &MockRouter{}
+
+	controller := controllers.NewStockpileMarkers(mockRouter, mockRepo)
+
+	userID := int64(42)
+	minQuantity := int64(-100)
+	marker := models.StockpileMarker{
+		TypeID:             34,
+		OwnerType:          "character",
+		OwnerID:            1337,
+		LocationID:         60003760,
+		DesiredQuantity:    1000,
+		MinDesiredQuantity: &minQuantity,
+	}
+
+	body, _ := json.Marshal(marker)
+	req := httptest.NewRequest("POST", "/v1/stockpiles", bytes.NewReader(body))
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+	}
+
+	result, httpErr := controller.UpsertStockpile(args)
+
+	assert.Nil(t, result)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
 func Test_StockpileMarkersController_DeleteStockpile_Success(t *testing.T) {
 	mockRepo := new(MockStockpileMarkersRepository)
 	mockRouter := &MockRouter{}
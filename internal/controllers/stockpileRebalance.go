@@ -0,0 +1,374 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/annymsMthd/industry-tool/internal/calculator"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+type StockpilePositionsRepository interface {
+	GetStockpilePositions(ctx context.Context, user int64) ([]*models.StockpilePosition, error)
+}
+
+type RebalanceProfilesRepository interface {
+	GetByUser(ctx context.Context, userID int64) ([]*models.TransportProfile, error)
+}
+
+type RebalanceJFRoutesRepository interface {
+	FindBySystemPair(ctx context.Context, userID, originSystemID, destSystemID int64) (*models.JFRoute, error)
+}
+
+type RebalanceMarketPricesRepository interface {
+	GetAllJitaPrices(ctx context.Context) (map[int64]*models.MarketPrice, error)
+	GetPricesForTypes(ctx context.Context, typeIDs []int64, regionID int64) (map[int64]*models.MarketPrice, error)
+}
+
+type RebalanceTransportJobsRepository interface {
+	Create(ctx context.Context, job *models.TransportJob) (*models.TransportJob, error)
+	SetQueueEntryID(ctx context.Context, id int64, queueEntryID int64) error
+}
+
+// RebalanceRouteLookup finds the gate route between two solar systems.
+type RebalanceRouteLookup interface {
+	GetRoute(ctx context.Context, origin, destination int64, flag string) ([]int32, error)
+}
+
+const jitaRegionID = int64(10000002)
+
+type StockpileRebalance struct {
+	profilesRepo  RebalanceProfilesRepository
+	stockpileRepo StockpilePositionsRepository
+	jfRoutesRepo  RebalanceJFRoutesRepository
+	marketRepo    RebalanceMarketPricesRepository
+	jobsRepo      RebalanceTransportJobsRepository
+	queueRepo     TransportJobQueueRepository
+	routes        RebalanceRouteLookup
+}
+
+func NewStockpileRebalance(
+	router Routerer,
+	stockpileRepo StockpilePositionsRepository,
+	profilesRepo RebalanceProfilesRepository,
+	jfRoutesRepo RebalanceJFRoutesRepository,
+	marketRepo RebalanceMarketPricesRepository,
+	jobsRepo RebalanceTransportJobsRepository,
+	queueRepo TransportJobQueueRepository,
+	routes RebalanceRouteLookup,
+) *StockpileRebalance {
+	c := &StockpileRebalance{
+		profilesRepo:  profilesRepo,
+		stockpileRepo: stockpileRepo,
+		jfRoutesRepo:  jfRoutesRepo,
+		marketRepo:    marketRepo,
+		jobsRepo:      jobsRepo,
+		queueRepo:     queueRepo,
+		routes:        routes,
+	}
+
+	router.RegisterRestAPIRoute("/v1/stockpiles/rebalance", web.AuthAccessUser, c.GetProposals, "GET")
+	router.RegisterRestAPIRoute("/v1/stockpiles/rebalance/jobs", web.AuthAccessUser, c.CreateJob, "POST")
+
+	return c
+}
+
+// GetProposals returns proposed transport jobs moving surplus stock to stations short of their
+// stockpile markers, each costed against every transport profile and against buying locally
+func (c *StockpileRebalance) GetProposals(args *web.HandlerArgs) (any, *web.HttpError) {
+	proposals, err := c.buildProposals(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to build rebalance proposals")}
+	}
+
+	return proposals, nil
+}
+
+type createRebalanceJobRequest struct {
+	OwnerType            string `json:"ownerType"`
+	OwnerID              int64  `json:"ownerId"`
+	OriginStationID      int64  `json:"originStationId"`
+	DestinationStationID int64  `json:"destinationStationId"`
+	ProfileID            *int64 `json:"profileId"`
+}
+
+// CreateJob turns a current proposal into a planned transport job. The proposal is rebuilt from
+// live data so the job always reflects current stock; the cheapest profile is used unless one is given
+func (c *StockpileRebalance) CreateJob(args *web.HandlerArgs) (any, *web.HttpError) {
+	var req createRebalanceJobRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if req.OwnerType == "" || req.OwnerID == 0 || req.OriginStationID == 0 || req.DestinationStationID == 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("ownerType, ownerId, originStationId and destinationStationId are required")}
+	}
+
+	ctx := args.Request.Context()
+
+	proposals, err := c.buildProposals(ctx, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to build rebalance proposals")}
+	}
+
+	var proposal *models.StockpileRebalanceProposal
+	for _, p := range proposals {
+		if p.OwnerType == req.OwnerType && p.OwnerID == req.OwnerID &&
+			p.OriginStationID == req.OriginStationID && p.DestinationStationID == req.DestinationStationID {
+			proposal = p
+			break
+		}
+	}
+	if proposal == nil {
+		return nil, &web.HttpError{StatusCode: 404, Error: errors.New("no rebalance proposal for this route")}
+	}
+
+	option := proposal.BestOption
+	if req.ProfileID != nil {
+		option = nil
+		for _, o := range proposal.Options {
+			if o.ProfileID == *req.ProfileID {
+				option = o
+				break
+			}
+		}
+	}
+	if option == nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("no transport profile can haul this proposal")}
+	}
+
+	note := fmt.Sprintf("Stockpile rebalance: %s → %s", proposal.OriginStationName, proposal.DestinationStationName)
+	job := &models.TransportJob{
+		UserID:               *args.User,
+		OriginStationID:      proposal.OriginStationID,
+		DestinationStationID: proposal.DestinationStationID,
+		OriginSystemID:       proposal.OriginSystemID,
+		DestinationSystemID:  proposal.DestinationSystemID,
+		TransportMethod:      option.TransportMethod,
+		RoutePreference:      option.RoutePreference,
+		TotalVolumeM3:        proposal.TotalVolumeM3,
+		TotalCollateral:      option.Collateral,
+		EstimatedCost:        option.Cost,
+		Jumps:                option.Jumps,
+		DistanceLY:           option.DistanceLY,
+		JFRouteID:            option.JFRouteID,
+		FulfillmentType:      "self_haul",
+		TransportProfileID:   &option.ProfileID,
+		Notes:                &note,
+		OwnerType:            &proposal.OwnerType,
+		OwnerID:              &proposal.OwnerID,
+		Items:                rebalanceJobItems(proposal),
+	}
+
+	created, err := c.jobsRepo.Create(ctx, job)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to create transport job")}
+	}
+
+	queueEntry, err := c.queueRepo.Create(ctx, &models.IndustryJobQueueEntry{
+		UserID:         *args.User,
+		Activity:       "transport",
+		EstimatedCost:  &option.Cost,
+		TransportJobID: &created.ID,
+	})
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to create queue entry for transport job")}
+	}
+
+	if err := c.jobsRepo.SetQueueEntryID(ctx, created.ID, queueEntry.ID); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to link queue entry to transport job")}
+	}
+	created.QueueEntryID = &queueEntry.ID
+
+	return created, nil
+}
+
+func (c *StockpileRebalance) buildProposals(ctx context.Context, userID int64) ([]*models.StockpileRebalanceProposal, error) {
+	positions, err := c.stockpileRepo.GetStockpilePositions(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get stockpile positions")
+	}
+
+	proposals := calculator.MatchStockpileSurplus(positions)
+	if len(proposals) == 0 {
+		return proposals, nil
+	}
+
+	profiles, err := c.profilesRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get transport profiles")
+	}
+
+	jitaPrices, err := c.marketRepo.GetAllJitaPrices(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get jita prices")
+	}
+
+	localPrices, err := c.getDestinationPrices(ctx, proposals, jitaPrices)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := map[string]int{}
+	for _, proposal := range proposals {
+		calculator.PriceStockpileRebalance(proposal, jitaPrices, localPrices[proposal.DestinationRegionID])
+
+		for _, profile := range profiles {
+			option, err := c.costOption(ctx, userID, proposal, profile, jitaPrices, routes)
+			if err != nil {
+				return nil, err
+			}
+			if option != nil {
+				proposal.Options = append(proposal.Options, option)
+			}
+		}
+
+		calculator.ChooseRebalanceTransport(proposal)
+	}
+
+	sort.SliceStable(proposals, func(i, j int) bool {
+		return proposals[i].Savings > proposals[j].Savings
+	})
+
+	return proposals, nil
+}
+
+// getDestinationPrices loads market prices of the proposed types for every destination region.
+// Jita prices are reused for The Forge.
+func (c *StockpileRebalance) getDestinationPrices(ctx context.Context, proposals []*models.StockpileRebalanceProposal, jitaPrices map[int64]*models.MarketPrice) (map[int64]map[int64]*models.MarketPrice, error) {
+	typesByRegion := map[int64]map[int64]bool{}
+	for _, proposal := range proposals {
+		if typesByRegion[proposal.DestinationRegionID] == nil {
+			typesByRegion[proposal.DestinationRegionID] = map[int64]bool{}
+		}
+		for _, item := range proposal.Items {
+			typesByRegion[proposal.DestinationRegionID][item.TypeID] = true
+		}
+	}
+
+	prices := map[int64]map[int64]*models.MarketPrice{}
+	for regionID, types := range typesByRegion {
+		if regionID == jitaRegionID {
+			prices[regionID] = jitaPrices
+			continue
+		}
+
+		typeIDs := []int64{}
+		for typeID := range types {
+			typeIDs = append(typeIDs, typeID)
+		}
+
+		regionPrices, err := c.marketRepo.GetPricesForTypes(ctx, typeIDs, regionID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get destination region prices")
+		}
+		prices[regionID] = regionPrices
+	}
+
+	return prices, nil
+}
+
+// costOption returns the cost of hauling a proposal with a profile, or nil when the profile
+// cannot be used on the route (no JF route between the systems).
+func (c *StockpileRebalance) costOption(
+	ctx context.Context,
+	userID int64,
+	proposal *models.StockpileRebalanceProposal,
+	profile *models.TransportProfile,
+	jitaPrices map[int64]*models.MarketPrice,
+	routes map[string]int,
+) (*models.StockpileRebalanceOption, error) {
+	collateral := calculator.CalculateCollateralValue(rebalanceJobItems(proposal), jitaPrices, profile.CollateralPriceBasis)
+
+	option := &models.StockpileRebalanceOption{
+		ProfileID:       profile.ID,
+		ProfileName:     profile.Name,
+		TransportMethod: profile.TransportMethod,
+		RoutePreference: "shortest",
+		Collateral:      collateral,
+	}
+	if profile.RoutePreference != "" {
+		option.RoutePreference = profile.RoutePreference
+	}
+
+	switch profile.TransportMethod {
+	case "freighter", "dst", "blockade_runner":
+		routePreference := option.RoutePreference
+		key := fmt.Sprintf("%d:%d:%s", proposal.OriginSystemID, proposal.DestinationSystemID, routePreference)
+		jumps, ok := routes[key]
+		if !ok {
+			route, err := c.routes.GetRoute(ctx, proposal.OriginSystemID, proposal.DestinationSystemID, routePreference)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to calculate route")
+			}
+			jumps = max(len(route)-1, 0)
+			routes[key] = jumps
+		}
+
+		result := calculator.CalculateGateTransportCost(&calculator.GateTransportCostParams{
+			TotalVolumeM3:    proposal.TotalVolumeM3,
+			TotalCollateral:  collateral,
+			Jumps:            jumps,
+			CargoM3:          profile.CargoM3,
+			RatePerM3PerJump: profile.RatePerM3PerJump,
+			CollateralRate:   profile.CollateralRate,
+		})
+		option.Jumps = jumps
+		option.Trips = result.Trips
+		option.Cost = result.Cost
+
+	case "jump_freighter":
+		jfRoute, err := c.jfRoutesRepo.FindBySystemPair(ctx, userID, proposal.OriginSystemID, proposal.DestinationSystemID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find JF route")
+		}
+		if jfRoute == nil {
+			return nil, nil
+		}
+
+		isotopePrice := 0.0
+		if profile.FuelTypeID != nil {
+			isotopePrice = calculator.PriceForBasis(jitaPrices[*profile.FuelTypeID], profile.CollateralPriceBasis)
+		}
+		fuelPerLY := calculator.JumpFuelPerLY(profile)
+
+		result := calculator.CalculateJFTransportCost(&calculator.JFTransportCostParams{
+			TotalVolumeM3:         proposal.TotalVolumeM3,
+			TotalCollateral:       collateral,
+			CargoM3:               profile.CargoM3,
+			CollateralRate:        profile.CollateralRate,
+			FuelPerLY:             fuelPerLY,
+			FuelConservationLevel: profile.FuelConservationLevel,
+			IsotopePrice:          isotopePrice,
+			Waypoints:             jfRoute.Waypoints,
+		})
+		distance := jfRoute.TotalDistanceLY
+		option.Jumps = max(len(jfRoute.Waypoints)-1, 0)
+		option.DistanceLY = &distance
+		option.JFRouteID = &jfRoute.ID
+		option.Trips = result.Trips
+		option.Cost = result.Cost
+
+	default:
+		return nil, nil
+	}
+
+	return option, nil
+}
+
+func rebalanceJobItems(proposal *models.StockpileRebalanceProposal) []*models.TransportJobItem {
+	items := []*models.TransportJobItem{}
+	for _, item := range proposal.Items {
+		items = append(items, &models.TransportJobItem{
+			TypeID:         item.TypeID,
+			Quantity:       int(item.Quantity),
+			VolumeM3:       item.VolumeM3,
+			EstimatedValue: item.EstimatedValue,
+		})
+	}
+	return items
+}
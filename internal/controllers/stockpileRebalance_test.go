@@ -0,0 +1,223 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStockpilePositionsRepository struct{ mock.Mock }
+
+func (m *MockStockpilePositionsRepository) GetStockpilePositions(ctx context.Context, user int64) ([]*models.StockpilePosition, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StockpilePosition), args.Error(1)
+}
+
+type MockRebalanceMarketPricesRepository struct{ mock.Mock }
+
+func (m *MockRebalanceMarketPricesRepository) GetAllJitaPrices(ctx context.Context) (map[int64]*models.MarketPrice, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int64]*models.MarketPrice), args.Error(1)
+}
+
+func (m *MockRebalanceMarketPricesRepository) GetPricesForTypes(ctx context.Context, typeIDs []int64, regionID int64) (map[int64]*models.MarketPrice, error) {
+	args := m.Called(ctx, typeIDs, regionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int64]*models.MarketPrice), args.Error(1)
+}
+
+type rebalanceMocks struct {
+	positions *MockStockpilePositionsRepository
+	profiles  *MockTransportProfilesRepo
+	jfRoutes  *MockProductionPlansJFRoutesRepository
+	market    *MockRebalanceMarketPricesRepository
+	jobs      *MockTransportJobsRepo
+	queue     *MockTransportJobQueueRepo
+	esi       *MockTransportRouteFinder
+}
+
+func newStockpileRebalanceController() (*controllers.StockpileRebalance, *rebalanceMocks) {
+	m := &rebalanceMocks{
+		positions: &MockStockpilePositionsRepository{},
+		profiles:  &MockTransportProfilesRepo{},
+		jfRoutes:  &MockProductionPlansJFRoutesRepository{},
+		market:    &MockRebalanceMarketPricesRepository{},
+		jobs:      &MockTransportJobsRepo{},
+		queue:     &MockTransportJobQueueRepo{},
+		esi:       &MockTransportRouteFinder{},
+	}
+	c := controllers.NewStockpileRebalance(&MockRouter{}, m.positions, m.profiles, m.jfRoutes, m.market, m.jobs, m.queue, m.esi)
+	return c, m
+}
+
+// Amarr hangar holds 10,000 surplus Tritanium; Jita is short 4,000.
+func rebalancePositions() []*models.StockpilePosition {
+	return []*models.StockpilePosition{
+		{OwnerType: "corporation", OwnerID: 2001, StationID: 60008494, StationName: "Amarr", SolarSystemID: 30002187, RegionID: 10000043,
+			TypeID: 34, TypeName: "Tritanium", UnitVolume: 0.01, HeldQuantity: 10000},
+		{OwnerType: "corporation", OwnerID: 2001, StationID: 60003760, StationName: "Jita", SolarSystemID: 30000142, RegionID: 10000002,
+			TypeID: 34, TypeName: "Tritanium", UnitVolume: 0.01, DesiredQuantity: 4000},
+	}
+}
+
+func setupRebalanceMocks(m *rebalanceMocks, sellPrice float64) {
+	userID := int64(42)
+	m.positions.On("GetStockpilePositions", mock.Anything, userID).Return(rebalancePositions(), nil)
+	m.profiles.On("GetByUser", mock.Anything, userID).Return([]*models.TransportProfile{
+		{ID: 1, Name: "Freighter", TransportMethod: "freighter", CargoM3: 1000000, RatePerM3PerJump: 10, CollateralPriceBasis: "sell"},
+		{ID: 2, Name: "JF", TransportMethod: "jump_freighter", CargoM3: 300000, CollateralPriceBasis: "sell"},
+	}, nil)
+	m.market.On("GetAllJitaPrices", mock.Anything).Return(map[int64]*models.MarketPrice{
+		34: {TypeID: 34, SellPrice: &sellPrice},
+	}, nil)
+	// 9 jumps Amarr → Jita
+	m.esi.On("GetRoute", mock.Anything, int64(30002187), int64(30000142), "shortest").
+		Return([]int32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, nil)
+	m.jfRoutes.On("FindBySystemPair", mock.Anything, userID, int64(30002187), int64(30000142)).Return(nil, nil)
+}
+
+func Test_StockpileRebalance_GetProposals_RecommendsTransport(t *testing.T) {
+	c, m := newStockpileRebalanceController()
+	setupRebalanceMocks(m, 5)
+
+	userID := int64(42)
+	req := httptest.NewRequest("GET", "/v1/stockpiles/rebalance", nil)
+	result, httpErr := c.GetProposals(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	proposals := result.([]*models.StockpileRebalanceProposal)
+	assert.Len(t, proposals, 1)
+
+	p := proposals[0]
+	assert.Equal(t, int64(60008494), p.OriginStationID)
+	assert.Equal(t, int64(60003760), p.DestinationStationID)
+	assert.Equal(t, int64(4000), p.Items[0].Quantity)
+	assert.Equal(t, 20000.0, p.LocalBuyCost)
+
+	// No JF route between the systems, so only the freighter can haul it: 40 m3 * 10 * 9 jumps
+	assert.Len(t, p.Options, 1)
+	assert.Equal(t, int64(1), p.BestOption.ProfileID)
+	assert.Equal(t, 9, p.BestOption.Jumps)
+	assert.InDelta(t, 3600.0, p.BestOption.Cost, 0.001)
+	assert.Equal(t, "transport", p.Recommendation)
+	assert.InDelta(t, 16400.0, p.Savings, 0.001)
+}
+
+func Test_StockpileRebalance_GetProposals_RecommendsBuyingLocally(t *testing.T) {
+	c, m := newStockpileRebalanceController()
+	setupRebalanceMocks(m, 0.5)
+
+	userID := int64(42)
+	req := httptest.NewRequest("GET", "/v1/stockpiles/rebalance", nil)
+	result, httpErr := c.GetProposals(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	proposals := result.([]*models.StockpileRebalanceProposal)
+	assert.Len(t, proposals, 1)
+	assert.Equal(t, "buy_locally", proposals[0].Recommendation)
+}
+
+func Test_StockpileRebalance_GetProposals_RouteError(t *testing.T) {
+	c, m := newStockpileRebalanceController()
+
+	userID := int64(42)
+	sellPrice := 5.0
+	m.positions.On("GetStockpilePositions", mock.Anything, userID).Return(rebalancePositions(), nil)
+	m.profiles.On("GetByUser", mock.Anything, userID).Return([]*models.TransportProfile{
+		{ID: 1, Name: "Freighter", TransportMethod: "freighter", CargoM3: 1000000, RatePerM3PerJump: 10, CollateralPriceBasis: "sell"},
+	}, nil)
+	m.market.On("GetAllJitaPrices", mock.Anything).Return(map[int64]*models.MarketPrice{
+		34: {TypeID: 34, SellPrice: &sellPrice},
+	}, nil)
+	m.esi.On("GetRoute", mock.Anything, int64(30002187), int64(30000142), "shortest").Return(nil, errors.New("esi down"))
+
+	req := httptest.NewRequest("GET", "/v1/stockpiles/rebalance", nil)
+	result, httpErr := c.GetProposals(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, result)
+	assert.Equal(t, 500, httpErr.StatusCode)
+	assert.ErrorContains(t, httpErr.Error, "esi down")
+}
+
+func Test_StockpileRebalance_GetProposals_NothingToMove(t *testing.T) {
+	c, m := newStockpileRebalanceController()
+
+	userID := int64(42)
+	m.positions.On("GetStockpilePositions", mock.Anything, userID).Return([]*models.StockpilePosition{}, nil)
+
+	req := httptest.NewRequest("GET", "/v1/stockpiles/rebalance", nil)
+	result, httpErr := c.GetProposals(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	assert.Empty(t, result)
+	m.profiles.AssertNotCalled(t, "GetByUser", mock.Anything, mock.Anything)
+}
+
+func Test_StockpileRebalance_CreateJob_UsesBestOption(t *testing.T) {
+	c, m := newStockpileRebalanceController()
+	setupRebalanceMocks(m, 5)
+
+	m.jobs.On("Create", mock.Anything, mock.MatchedBy(func(job *models.TransportJob) bool {
+		return job.OriginStationID == 60008494 && job.DestinationStationID == 60003760 &&
+			job.TransportMethod == "freighter" && *job.TransportProfileID == 1 &&
+			*job.OwnerType == "corporation" && *job.OwnerID == 2001 &&
+			len(job.Items) == 1 && job.Items[0].Quantity == 4000
+	})).Return(&models.TransportJob{ID: 77}, nil)
+	m.queue.On("Create", mock.Anything, mock.AnythingOfType("*models.IndustryJobQueueEntry")).
+		Return(&models.IndustryJobQueueEntry{ID: 88}, nil)
+	m.jobs.On("SetQueueEntryID", mock.Anything, int64(77), int64(88)).Return(nil)
+
+	userID := int64(42)
+	body := `{"ownerType":"corporation","ownerId":2001,"originStationId":60008494,"destinationStationId":60003760}`
+	req := httptest.NewRequest("POST", "/v1/stockpiles/rebalance/jobs", bytes.NewBufferString(body))
+	result, httpErr := c.CreateJob(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	job := result.(*models.TransportJob)
+	assert.Equal(t, int64(77), job.ID)
+	assert.Equal(t, int64(88), *job.QueueEntryID)
+	m.jobs.AssertExpectations(t)
+}
+
+func Test_StockpileRebalance_CreateJob_UnknownProposal(t *testing.T) {
+	c, m := newStockpileRebalanceController()
+	setupRebalanceMocks(m, 5)
+
+	userID := int64(42)
+	body := `{"ownerType":"corporation","ownerId":2001,"originStationId":60003760,"destinationStationId":60008494}`
+	req := httptest.NewRequest("POST", "/v1/stockpiles/rebalance/jobs", bytes.NewBufferString(body))
+	result, httpErr := c.CreateJob(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, result)
+	assert.Equal(t, 404, httpErr.StatusCode)
+	m.jobs.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_StockpileRebalance_CreateJob_ProfileCannotHaul(t *testing.T) {
+	c, m := newStockpileRebalanceController()
+	setupRebalanceMocks(m, 5)
+
+	userID := int64(42)
+	body := `{"ownerType":"corporation","ownerId":2001,"originStationId":60008494,"destinationStationId":60003760,"profileId":2}`
+	req := httptest.NewRequest("POST", "/v1/stockpiles/rebalance/jobs", bytes.NewBufferString(body))
+	result, httpErr := c.CreateJob(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, result)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
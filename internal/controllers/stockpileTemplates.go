@@ -0,0 +1,364 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+type StockpileTemplatesRepository interface {
+	GetByUser(ctx context.Context, userID int64) ([]*models.StockpileTemplate, error)
+	GetByID(ctx context.Context, id, userID int64) (*models.StockpileTemplate, error)
+	Create(ctx context.Context, template *models.StockpileTemplate) (*models.StockpileTemplate, error)
+	Update(ctx context.Context, template *models.StockpileTemplate) error
+	Delete(ctx context.Context, id, userID int64) error
+	Apply(ctx context.Context, application *models.StockpileTemplateApplication) (*models.StockpileTemplateApplication, error)
+	UpdateApplicationMultiplier(ctx context.Context, applicationID, templateID, userID int64, multiplier float64) error
+	RemoveApplication(ctx context.Context, applicationID, templateID, userID int64) error
+	GetApplicationDeficits(ctx context.Context, templateID, userID int64) ([]*models.StockpileTemplateApplicationDeficits, error)
+}
+
+type StockpileTemplates struct {
+	repository StockpileTemplatesRepository
+}
+
+func NewStockpileTemplates(router Routerer, repository StockpileTemplatesRepository) *StockpileTemplates {
+	controller := &StockpileTemplates{
+		repository: repository,
+	}
+
+	router.RegisterRestAPIRoute("/v1/stockpile-templates", web.AuthAccessUser, controller.GetTemplates, "GET")
+	router.RegisterRestAPIRoute("/v1/stockpile-templates", web.AuthAccessUser, controller.CreateTemplate, "POST")
+	router.RegisterRestAPIRoute("/v1/stockpile-templates/{id}", web.AuthAccessUser, controller.UpdateTemplate, "PUT")
+	router.RegisterRestAPIRoute("/v1/stockpile-templates/{id}", web.AuthAccessUser, controller.DeleteTemplate, "DELETE")
+	router.RegisterRestAPIRoute("/v1/stockpile-templates/{id}/applications", web.AuthAccessUser, controller.ApplyTemplate, "POST")
+	router.RegisterRestAPIRoute("/v1/stockpile-templates/{id}/applications/{applicationId}", web.AuthAccessUser, controller.UpdateApplication, "PUT")
+	router.RegisterRestAPIRoute("/v1/stockpile-templates/{id}/applications/{applicationId}", web.AuthAccessUser, controller.RemoveApplication, "DELETE")
+	router.RegisterRestAPIRoute("/v1/stockpile-templates/{id}/deficits", web.AuthAccessUser, controller.GetDeficits, "GET")
+
+	return controller
+}
+
+type stockpileTemplateRequest struct {
+	Name            string                          `json:"name"`
+	Notes           *string                         `json:"notes"`
+	PriceSource     *string                         `json:"priceSource"`
+	PricePercentage *float64                        `json:"pricePercentage"`
+	Items           []*models.StockpileTemplateItem `json:"items"`
+}
+
+// GetTemplates returns all stockpile templates of the user with their items and applications
+func (c *StockpileTemplates) GetTemplates(args *web.HandlerArgs) (any, *web.HttpError) {
+	templates, err := c.repository.GetByUser(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get stockpile templates")}
+	}
+
+	return templates, nil
+}
+
+// CreateTemplate creates a new stockpile template
+func (c *StockpileTemplates) CreateTemplate(args *web.HandlerArgs) (any, *web.HttpError) {
+	var req stockpileTemplateRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if httpErr := validateStockpileTemplateRequest(&req); httpErr != nil {
+		return nil, httpErr
+	}
+
+	template, err := c.repository.Create(args.Request.Context(), &models.StockpileTemplate{
+		UserID:          *args.User,
+		Name:            req.Name,
+		Notes:           req.Notes,
+		PriceSource:     req.PriceSource,
+		PricePercentage: req.PricePercentage,
+		Items:           req.Items,
+	})
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to create stockpile template")}
+	}
+
+	return template, nil
+}
+
+// UpdateTemplate replaces a template's settings and items; the change is applied to the
+// stockpile markers of every location the template is applied to
+func (c *StockpileTemplates) UpdateTemplate(args *web.HandlerArgs) (any, *web.HttpError) {
+	existing, httpErr := c.getTemplate(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	var req stockpileTemplateRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if httpErr := validateStockpileTemplateRequest(&req); httpErr != nil {
+		return nil, httpErr
+	}
+
+	existing.Name = req.Name
+	existing.Notes = req.Notes
+	existing.PriceSource = req.PriceSource
+	existing.PricePercentage = req.PricePercentage
+	existing.Items = req.Items
+
+	if err := c.repository.Update(args.Request.Context(), existing); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to update stockpile template")}
+	}
+
+	updated, err := c.repository.GetByID(args.Request.Context(), existing.ID, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get stockpile template")}
+	}
+
+	return updated, nil
+}
+
+// DeleteTemplate removes a template together with its applications and their stockpile markers
+func (c *StockpileTemplates) DeleteTemplate(args *web.HandlerArgs) (any, *web.HttpError) {
+	existing, httpErr := c.getTemplate(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	if err := c.repository.Delete(args.Request.Context(), existing.ID, *args.User); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to delete stockpile template")}
+	}
+
+	return nil, nil
+}
+
+// ApplyTemplate applies a template to a location or container scope
+func (c *StockpileTemplates) ApplyTemplate(args *web.HandlerArgs) (any, *web.HttpError) {
+	existing, httpErr := c.getTemplate(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	var req struct {
+		OwnerType      string   `json:"ownerType"`
+		OwnerID        int64    `json:"ownerId"`
+		LocationID     int64    `json:"locationId"`
+		ContainerID    *int64   `json:"containerId"`
+		DivisionNumber *int     `json:"divisionNumber"`
+		Multiplier     *float64 `json:"multiplier"`
+	}
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if req.OwnerType != "character" && req.OwnerType != "corporation" {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("ownerType must be character or corporation")}
+	}
+	if req.OwnerID == 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("ownerId is required")}
+	}
+	if req.LocationID == 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("locationId is required")}
+	}
+
+	multiplier := 1.0
+	if req.Multiplier != nil {
+		multiplier = *req.Multiplier
+	}
+	if httpErr := validateTemplateMultiplier(multiplier); httpErr != nil {
+		return nil, httpErr
+	}
+
+	templates, err := c.repository.GetByUser(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get stockpile templates")}
+	}
+
+	application := &models.StockpileTemplateApplication{
+		TemplateID:     existing.ID,
+		UserID:         *args.User,
+		OwnerType:      req.OwnerType,
+		OwnerID:        req.OwnerID,
+		LocationID:     req.LocationID,
+		ContainerID:    req.ContainerID,
+		DivisionNumber: req.DivisionNumber,
+		Multiplier:     multiplier,
+	}
+
+	for _, template := range templates {
+		for _, other := range template.Applications {
+			if sameTemplateScope(other, application) {
+				return nil, &web.HttpError{
+					StatusCode: 400,
+					Error:      errors.Errorf("template %q is already applied to this location", template.Name),
+				}
+			}
+		}
+	}
+
+	created, err := c.repository.Apply(args.Request.Context(), application)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to apply stockpile template")}
+	}
+
+	return created, nil
+}
+
+// UpdateApplication changes the multiplier of a template application
+func (c *StockpileTemplates) UpdateApplication(args *web.HandlerArgs) (any, *web.HttpError) {
+	existing, application, httpErr := c.getApplication(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	var req struct {
+		Multiplier float64 `json:"multiplier"`
+	}
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if httpErr := validateTemplateMultiplier(req.Multiplier); httpErr != nil {
+		return nil, httpErr
+	}
+
+	err := c.repository.UpdateApplicationMultiplier(args.Request.Context(), application.ID, existing.ID, *args.User, req.Multiplier)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to update stockpile template application")}
+	}
+
+	application.Multiplier = req.Multiplier
+	return application, nil
+}
+
+// RemoveApplication removes a template from a location together with its stockpile markers
+func (c *StockpileTemplates) RemoveApplication(args *web.HandlerArgs) (any, *web.HttpError) {
+	existing, application, httpErr := c.getApplication(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	err := c.repository.RemoveApplication(args.Request.Context(), application.ID, existing.ID, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to remove stockpile template application")}
+	}
+
+	return nil, nil
+}
+
+// GetDeficits returns the stock against the desired quantities for every application of a template
+func (c *StockpileTemplates) GetDeficits(args *web.HandlerArgs) (any, *web.HttpError) {
+	existing, httpErr := c.getTemplate(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	deficits, err := c.repository.GetApplicationDeficits(args.Request.Context(), existing.ID, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get stockpile template deficits")}
+	}
+
+	return deficits, nil
+}
+
+func (c *StockpileTemplates) getTemplate(args *web.HandlerArgs) (*models.StockpileTemplate, *web.HttpError) {
+	id, err := parseID(args.Params["id"])
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("invalid id")}
+	}
+
+	template, err := c.repository.GetByID(args.Request.Context(), id, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get stockpile template")}
+	}
+	if template == nil {
+		return nil, &web.HttpError{StatusCode: 404, Error: errors.New("stockpile template not found")}
+	}
+
+	return template, nil
+}
+
+func (c *StockpileTemplates) getApplication(args *web.HandlerArgs) (*models.StockpileTemplate, *models.StockpileTemplateApplication, *web.HttpError) {
+	template, httpErr := c.getTemplate(args)
+	if httpErr != nil {
+		return nil, nil, httpErr
+	}
+
+	applicationID, err := parseID(args.Params["applicationId"])
+	if err != nil {
+		return nil, nil, &web.HttpError{StatusCode: 400, Error: errors.New("invalid applicationId")}
+	}
+
+	for _, application := range template.Applications {
+		if application.ID == applicationID {
+			return template, application, nil
+		}
+	}
+
+	return nil, nil, &web.HttpError{StatusCode: 404, Error: errors.New("stockpile template application not found")}
+}
+
+func validateStockpileTemplateRequest(req *stockpileTemplateRequest) *web.HttpError {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return &web.HttpError{StatusCode: 400, Error: errors.New("name is required")}
+	}
+	if len(req.Name) > 200 {
+		return &web.HttpError{StatusCode: 400, Error: errors.New("name must be at most 200 characters")}
+	}
+
+	if len(req.Items) == 0 {
+		return &web.HttpError{StatusCode: 400, Error: errors.New("at least one item is required")}
+	}
+	seen := map[int64]bool{}
+	for _, item := range req.Items {
+		if item == nil || item.TypeID == 0 {
+			return &web.HttpError{StatusCode: 400, Error: errors.New("typeId is required for every item")}
+		}
+		if item.Quantity <= 0 {
+			return &web.HttpError{StatusCode: 400, Error: errors.Errorf("quantity must be positive for type %d", item.TypeID)}
+		}
+		if seen[item.TypeID] {
+			return &web.HttpError{StatusCode: 400, Error: errors.Errorf("duplicate type %d", item.TypeID)}
+		}
+		seen[item.TypeID] = true
+	}
+
+	if req.PriceSource != nil && !allowedPriceSources[*req.PriceSource] {
+		return &web.HttpError{StatusCode: 400, Error: errors.Errorf("invalid priceSource: %s", *req.PriceSource)}
+	}
+	if req.PricePercentage != nil && (*req.PricePercentage <= 0 || *req.PricePercentage > 200) {
+		return &web.HttpError{StatusCode: 400, Error: errors.New("pricePercentage must be between 0 and 200")}
+	}
+
+	return nil
+}
+
+// validateTemplateMultiplier keeps the multiplier within numeric(8,2); smaller
+// values would round to 0.00
+func validateTemplateMultiplier(multiplier float64) *web.HttpError {
+	if multiplier < 0.01 || multiplier > 1000 {
+		return &web.HttpError{StatusCode: 400, Error: errors.New("multiplier must be between 0.01 and 1000")}
+	}
+	return nil
+}
+
+func sameTemplateScope(a, b *models.StockpileTemplateApplication) bool {
+	return a.OwnerType == b.OwnerType &&
+		a.OwnerID == b.OwnerID &&
+		a.LocationID == b.LocationID &&
+		scopeValue(a.ContainerID) == scopeValue(b.ContainerID) &&
+		scopeValue(a.DivisionNumber) == scopeValue(b.DivisionNumber)
+}
+
+// scopeValue mirrors the COALESCE(..., 0) of the application scope index.
+func scopeValue[T int | int64](v *T) T {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
@@ -0,0 +1,312 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStockpileTemplatesRepository struct {
+	mock.Mock
+}
+
+func (m *MockStockpileTemplatesRepository) GetByUser(ctx context.Context, userID int64) ([]*models.StockpileTemplate, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StockpileTemplate), args.Error(1)
+}
+
+func (m *MockStockpileTemplatesRepository) GetByID(ctx context.Context, id, userID int64) (*models.StockpileTemplate, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockpileTemplate), args.Error(1)
+}
+
+func (m *MockStockpileTemplatesRepository) Create(ctx context.Context, template *models.StockpileTemplate) (*models.StockpileTemplate, error) {
+	args := m.Called(ctx, template)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockpileTemplate), args.Error(1)
+}
+
+func (m *MockStockpileTemplatesRepository) Update(ctx context.Context, template *models.StockpileTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockStockpileTemplatesRepository) Delete(ctx context.Context, id, userID int64) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockStockpileTemplatesRepository) Apply(ctx context.Context, application *models.StockpileTemplateApplication) (*models.StockpileTemplateApplication, error) {
+	args := m.Called(ctx, application)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockpileTemplateApplication), args.Error(1)
+}
+
+func (m *MockStockpileTemplatesRepository) UpdateApplicationMultiplier(ctx context.Context, applicationID, templateID, userID int64, multiplier float64) error {
+	args := m.Called(ctx, applicationID, templateID, userID, multiplier)
+	return args.Error(0)
+}
+
+func (m *MockStockpileTemplatesRepository) RemoveApplication(ctx context.Context, applicationID, templateID, userID int64) error {
+	args := m.Called(ctx, applicationID, templateID, userID)
+	return args.Error(0)
+}
+
+func (m *MockStockpileTemplatesRepository) GetApplicationDeficits(ctx context.Context, templateID, userID int64) ([]*models.StockpileTemplateApplicationDeficits, error) {
+	args := m.Called(ctx, templateID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StockpileTemplateApplicationDeficits), args.Error(1)
+}
+
+func stagingKitTemplate() *models.StockpileTemplate {
+	return &models.StockpileTemplate{
+		ID:     7,
+		UserID: 42,
+		Name:   "Staging Kit",
+		Items: []*models.StockpileTemplateItem{
+			{TypeID: 34, Quantity: 1000},
+		},
+		Applications: []*models.StockpileTemplateApplication{
+			{ID: 3, TemplateID: 7, UserID: 42, OwnerType: "character", OwnerID: 1337, LocationID: 60003760, Multiplier: 1},
+		},
+	}
+}
+
+func Test_StockpileTemplates_CreateTemplate_Success(t *testing.T) {
+	mockRepo := new(MockStockpileTemplatesRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(template *models.StockpileTemplate) bool {
+		return template.UserID == 42 && template.Name == "Staging Kit" && len(template.Items) == 2 &&
+			*template.PriceSource == "jita_sell"
+	})).Return(&models.StockpileTemplate{ID: 7, Name: "Staging Kit"}, nil)
+
+	body := `{"name":"  Staging Kit ","priceSource":"jita_sell","pricePercentage":110,"items":[{"typeId":34,"quantity":1000},{"typeId":35,"quantity":500}]}`
+	req := httptest.NewRequest("POST", "/v1/stockpile-templates", bytes.NewBufferString(body))
+
+	result, httpErr := controller.CreateTemplate(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, int64(7), result.(*models.StockpileTemplate).ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_StockpileTemplates_CreateTemplate_Validation(t *testing.T) {
+	cases := map[string]string{
+		"missing name":       `{"items":[{"typeId":34,"quantity":1}]}`,
+		"no items":           `{"name":"Kit","items":[]}`,
+		"zero quantity":      `{"name":"Kit","items":[{"typeId":34,"quantity":0}]}`,
+		"duplicate type":     `{"name":"Kit","items":[{"typeId":34,"quantity":1},{"typeId":34,"quantity":2}]}`,
+		"invalid source":     `{"name":"Kit","priceSource":"amarr","items":[{"typeId":34,"quantity":1}]}`,
+		"invalid percentage": `{"name":"Kit","pricePercentage":250,"items":[{"typeId":34,"quantity":1}]}`,
+	}
+
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockStockpileTemplatesRepository)
+			controller := controllers.NewStockpileTemplates(&MockRouter{}, mockRepo)
+
+			userID := int64(42)
+			req := httptest.NewRequest("POST", "/v1/stockpile-templates", bytes.NewBufferString(body))
+
+			result, httpErr := controller.CreateTemplate(&web.HandlerArgs{Request: req, User: &userID})
+
+			assert.Nil(t, result)
+			assert.NotNil(t, httpErr)
+			assert.Equal(t, 400, httpErr.StatusCode)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func Test_StockpileTemplates_UpdateTemplate_Success(t *testing.T) {
+	mockRepo := new(MockStockpileTemplatesRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("GetByID", mock.Anything, int64(7), userID).Return(stagingKitTemplate(), nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(template *models.StockpileTemplate) bool {
+		return template.ID == 7 && template.Name == "Staging Kit v2" && template.Items[0].Quantity == 2000
+	})).Return(nil)
+
+	body := `{"name":"Staging Kit v2","items":[{"typeId":34,"quantity":2000}]}`
+	req := httptest.NewRequest("PUT", "/v1/stockpile-templates/7", bytes.NewBufferString(body))
+
+	result, httpErr := controller.UpdateTemplate(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "7"}})
+
+	assert.Nil(t, httpErr)
+	assert.NotNil(t, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_StockpileTemplates_UpdateTemplate_NotFound(t *testing.T) {
+	mockRepo := new(MockStockpileTemplatesRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("GetByID", mock.Anything, int64(99), userID).Return(nil, nil)
+
+	body := `{"name":"Kit","items":[{"typeId":34,"quantity":1}]}`
+	req := httptest.NewRequest("PUT", "/v1/stockpile-templates/99", bytes.NewBufferString(body))
+
+	result, httpErr := controller.UpdateTemplate(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "99"}})
+
+	assert.Nil(t, result)
+	assert.Equal(t, 404, httpErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func Test_StockpileTemplates_ApplyTemplate_DefaultsMultiplier(t *testing.T) {
+	mockRepo := new(MockStockpileTemplatesRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("GetByID", mock.Anything, int64(7), userID).Return(stagingKitTemplate(), nil)
+	mockRepo.On("GetByUser", mock.Anything, userID).Return([]*models.StockpileTemplate{stagingKitTemplate()}, nil)
+	mockRepo.On("Apply", mock.Anything, mock.MatchedBy(func(application *models.StockpileTemplateApplication) bool {
+		return application.TemplateID == 7 && application.LocationID == 60003761 && application.Multiplier == 1
+	})).Return(&models.StockpileTemplateApplication{ID: 4, TemplateID: 7}, nil)
+
+	body := `{"ownerType":"character","ownerId":1337,"locationId":60003761}`
+	req := httptest.NewRequest("POST", "/v1/stockpile-templates/7/applications", bytes.NewBufferString(body))
+
+	result, httpErr := controller.ApplyTemplate(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "7"}})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, int64(4), result.(*models.StockpileTemplateApplication).ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_StockpileTemplates_ApplyTemplate_RejectsOccupiedScope(t *testing.T) {
+	mockRepo := new(MockStockpileTemplatesRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("GetByID", mock.Anything, int64(7), userID).Return(stagingKitTemplate(), nil)
+	mockRepo.On("GetByUser", mock.Anything, userID).Return([]*models.StockpileTemplate{stagingKitTemplate()}, nil)
+
+	body := `{"ownerType":"character","ownerId":1337,"locationId":60003760,"multiplier":2}`
+	req := httptest.NewRequest("POST", "/v1/stockpile-templates/7/applications", bytes.NewBufferString(body))
+
+	result, httpErr := controller.ApplyTemplate(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "7"}})
+
+	assert.Nil(t, result)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
+}
+
+func Test_StockpileTemplates_ApplyTemplate_InvalidMultiplier(t *testing.T) {
+	mockRepo := new(MockStockpileTemplatesRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("GetByID", mock.Anything, int64(7), userID).Return(stagingKitTemplate(), nil)
+
+	// Values below 0.01 would round to zero in storage
+	for _, multiplier := range []string{"0", "0.004", "1001"} {
+		body := `{"ownerType":"character","ownerId":1337,"locationId":60003761,"multiplier":` + multiplier + `}`
+		req := httptest.NewRequest("POST", "/v1/stockpile-templates/7/applications", bytes.NewBufferString(body))
+
+		result, httpErr := controller.ApplyTemplate(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "7"}})
+
+		assert.Nil(t, result, multiplier)
+		assert.Equal(t, 400, httpErr.StatusCode, multiplier)
+	}
+}
+
+func Test_StockpileTemplates_UpdateApplication_Success(t *testing.T) {
+	mockRepo := new(MockStockpileTemplatesRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("GetByID", mock.Anything, int64(7), userID).Return(stagingKitTemplate(), nil)
+	mockRepo.On("UpdateApplicationMultiplier", mock.Anything, int64(3), int64(7), userID, 2.5).Return(nil)
+
+	req := httptest.NewRequest("PUT", "/v1/stockpile-templates/7/applications/3", bytes.NewBufferString(`{"multiplier":2.5}`))
+
+	result, httpErr := controller.UpdateApplication(&web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+		Params:  map[string]string{"id": "7", "applicationId": "3"},
+	})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, 2.5, result.(*models.StockpileTemplateApplication).Multiplier)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_StockpileTemplates_RemoveApplication_UnknownApplication(t *testing.T) {
+	mockRepo := new(MockStockpileTemplatesRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("GetByID", mock.Anything, int64(7), userID).Return(stagingKitTemplate(), nil)
+
+	req := httptest.NewRequest("DELETE", "/v1/stockpile-templates/7/applications/99", nil)
+
+	result, httpErr := controller.RemoveApplication(&web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+		Params:  map[string]string{"id": "7", "applicationId": "99"},
+	})
+
+	assert.Nil(t, result)
+	assert.Equal(t, 404, httpErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "RemoveApplication", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_StockpileTemplates_GetDeficits_Success(t *testing.T) {
+	mockRepo := new(MockStockpileTemplatesRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	deficits := []*models.StockpileTemplateApplicationDeficits{
+		{LocationName: "Jita IV - Moon 4", MissingTypes: 1, TotalDeficitValue: 2500},
+	}
+	mockRepo.On("GetByID", mock.Anything, int64(7), userID).Return(stagingKitTemplate(), nil)
+	mockRepo.On("GetApplicationDeficits", mock.Anything, int64(7), userID).Return(deficits, nil)
+
+	req := httptest.NewRequest("GET", "/v1/stockpile-templates/7/deficits", nil)
+
+	result, httpErr := controller.GetDeficits(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "7"}})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, deficits, result)
+}
+
+func Test_StockpileTemplates_GetDeficits_RepositoryError(t *testing.T) {
+	mockRepo := new(MockStockpileTemplatesRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("GetByID", mock.Anything, int64(7), userID).Return(stagingKitTemplate(), nil)
+	mockRepo.On("GetApplicationDeficits", mock.Anything, int64(7), userID).Return(nil, errors.New("db down"))
+
+	req := httptest.NewRequest("GET", "/v1/stockpile-templates/7/deficits", nil)
+
+	result, httpErr := controller.GetDeficits(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "7"}})
+
+	assert.Nil(t, result)
+	assert.Equal(t, 500, httpErr.StatusCode)
+}
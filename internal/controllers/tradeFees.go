@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/annymsMthd/industry-tool/internal/calculator"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+type TradeFeesCharacterRepository interface {
+	GetNames(ctx context.Context, userID int64) (map[int64]string, error)
+}
+
+type TradeFeesSkillsRepository interface {
+	GetSkillsForUser(ctx context.Context, userID int64) ([]*models.CharacterSkill, error)
+}
+
+type TradeFeesStructuresRepository interface {
+	List(ctx context.Context, userID int64) ([]*models.UserTradingStructure, error)
+}
+
+type TradeFees struct {
+	characters TradeFeesCharacterRepository
+	skills     TradeFeesSkillsRepository
+	structures TradeFeesStructuresRepository
+}
+
+func NewTradeFees(
+	router Routerer,
+	characters TradeFeesCharacterRepository,
+	skills TradeFeesSkillsRepository,
+	structures TradeFeesStructuresRepository,
+) *TradeFees {
+	c := &TradeFees{
+		characters: characters,
+		skills:     skills,
+		structures: structures,
+	}
+
+	router.RegisterRestAPIRoute("/v1/trade-fees", web.AuthAccessUser, c.GetTradeFees, "GET")
+
+	return c
+}
+
+// GetTradeFees returns each character's sales tax and broker fee, lowest first.
+// Optional query params: location_id (a trading structure whose broker rate
+// applies), faction_standing and corporation_standing (toward the NPC station owner).
+func (c *TradeFees) GetTradeFees(args *web.HandlerArgs) (any, *web.HttpError) {
+	ctx := args.Request.Context()
+	q := args.Request.URL.Query()
+
+	standings, err := parseTradeStandings(q)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: err}
+	}
+
+	var structureBrokerFeePct *float64
+	if raw := q.Get("location_id"); raw != "" {
+		locationID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.New("invalid location_id")}
+		}
+		structures, err := c.structures.List(ctx, *args.User)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get trading structures")}
+		}
+		structureBrokerFeePct = calculator.StructureBrokerFeePct(structures, locationID)
+	}
+
+	names, err := c.characters.GetNames(ctx, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get character names")}
+	}
+
+	skills, err := c.skills.GetSkillsForUser(ctx, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get character skills")}
+	}
+
+	return calculator.BuildCharacterTradeFees(names, calculator.TradeSkillsByCharacter(skills), standings, structureBrokerFeePct), nil
+}
+
+// parseTradeStandings reads faction_standing and corporation_standing.
+// Returns nil when neither is given.
+func parseTradeStandings(q url.Values) (*calculator.TradeStandings, error) {
+	factionRaw, corporationRaw := q.Get("faction_standing"), q.Get("corporation_standing")
+	if factionRaw == "" && corporationRaw == "" {
+		return nil, nil
+	}
+
+	standings := &calculator.TradeStandings{}
+	for _, p := range []struct {
+		name  string
+		raw   string
+		value *float64
+	}{
+		{"faction_standing", factionRaw, &standings.Faction},
+		{"corporation_standing", corporationRaw, &standings.Corporation},
+	} {
+		if p.raw == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(p.raw, 64)
+		if err != nil || v < -10 || v > 10 {
+			return nil, errors.Errorf("%s must be a number between -10 and 10", p.name)
+		}
+		*p.value = v
+	}
+	return standings, nil
+}
@@ -0,0 +1,137 @@
+package controllers_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/calculator"
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type tradeFeesMocks struct {
+	characters *MockIndustryCharacterRepository
+	skills     *MockIndustryCharacterSkillsRepository
+	structures *MockUserTradingStructuresRepository
+}
+
+func setupTradeFeesController() (*controllers.TradeFees, tradeFeesMocks) {
+	mocks := tradeFeesMocks{
+		characters: new(MockIndustryCharacterRepository),
+		skills:     new(MockIndustryCharacterSkillsRepository),
+		structures: new(MockUserTradingStructuresRepository),
+	}
+	controller := controllers.NewTradeFees(&MockRouter{}, mocks.characters, mocks.skills, mocks.structures)
+	return controller, mocks
+}
+
+func tradeFeesSkills() []*models.CharacterSkill {
+	return []*models.CharacterSkill{
+		{CharacterID: 2001001, SkillID: calculator.SkillAccounting, ActiveLevel: 5},
+		{CharacterID: 2001001, SkillID: calculator.SkillBrokerRelations, ActiveLevel: 4},
+		{CharacterID: 2001002, SkillID: calculator.SkillAccounting, ActiveLevel: 1},
+	}
+}
+
+func Test_TradeFees_GetTradeFees_Success(t *testing.T) {
+	controller, mocks := setupTradeFeesController()
+	userID := int64(100)
+
+	mocks.characters.On("GetNames", mock.Anything, userID).Return(map[int64]string{2001001: "Trader", 2001002: "Alt"}, nil)
+	mocks.skills.On("GetSkillsForUser", mock.Anything, userID).Return(tradeFeesSkills(), nil)
+
+	req := httptest.NewRequest("GET", "/v1/trade-fees", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID}
+
+	result, httpErr := controller.GetTradeFees(args)
+	assert.Nil(t, httpErr)
+	fees := result.([]*models.TradeFees)
+	assert.Len(t, fees, 2)
+	assert.Equal(t, "Trader", fees[0].CharacterName)
+	assert.InDelta(t, 3.375, fees[0].SalesTaxPct, 0.0001)
+	assert.InDelta(t, 1.8, fees[0].BrokerFeePct, 0.0001)
+	assert.Equal(t, "Alt", fees[1].CharacterName)
+	assert.InDelta(t, 6.675, fees[1].SalesTaxPct, 0.0001)
+	assert.InDelta(t, 3.0, fees[1].BrokerFeePct, 0.0001)
+	mocks.structures.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
+
+func Test_TradeFees_GetTradeFees_StructureBrokerRate(t *testing.T) {
+	controller, mocks := setupTradeFeesController()
+	userID := int64(100)
+
+	brokerFee := 0.75
+	mocks.structures.On("List", mock.Anything, userID).Return([]*models.UserTradingStructure{
+		{StructureID: 1035466617946, BrokerFeePct: &brokerFee},
+	}, nil)
+	mocks.characters.On("GetNames", mock.Anything, userID).Return(map[int64]string{2001001: "Trader"}, nil)
+	mocks.skills.On("GetSkillsForUser", mock.Anything, userID).Return(tradeFeesSkills(), nil)
+
+	req := httptest.NewRequest("GET", "/v1/trade-fees?location_id=1035466617946", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID}
+
+	result, httpErr := controller.GetTradeFees(args)
+	assert.Nil(t, httpErr)
+	fees := result.([]*models.TradeFees)
+	assert.InDelta(t, 0.75, fees[0].BrokerFeePct, 0.0001)
+}
+
+func Test_TradeFees_GetTradeFees_Standings(t *testing.T) {
+	controller, mocks := setupTradeFeesController()
+	userID := int64(100)
+
+	mocks.characters.On("GetNames", mock.Anything, userID).Return(map[int64]string{2001001: "Trader"}, nil)
+	mocks.skills.On("GetSkillsForUser", mock.Anything, userID).Return(tradeFeesSkills(), nil)
+
+	req := httptest.NewRequest("GET", "/v1/trade-fees?faction_standing=5&corporation_standing=5", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID}
+
+	result, httpErr := controller.GetTradeFees(args)
+	assert.Nil(t, httpErr)
+	fees := result.([]*models.TradeFees)
+	// 3 - 1.2 - 0.15 - 0.1
+	assert.InDelta(t, 1.55, fees[0].BrokerFeePct, 0.0001)
+}
+
+func Test_TradeFees_GetTradeFees_InvalidStanding(t *testing.T) {
+	controller, _ := setupTradeFeesController()
+	userID := int64(100)
+
+	req := httptest.NewRequest("GET", "/v1/trade-fees?faction_standing=11", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID}
+
+	result, httpErr := controller.GetTradeFees(args)
+	assert.Nil(t, result)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
+func Test_TradeFees_GetTradeFees_InvalidLocation(t *testing.T) {
+	controller, _ := setupTradeFeesController()
+	userID := int64(100)
+
+	req := httptest.NewRequest("GET", "/v1/trade-fees?location_id=abc", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID}
+
+	result, httpErr := controller.GetTradeFees(args)
+	assert.Nil(t, result)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
+func Test_TradeFees_GetTradeFees_SkillsError(t *testing.T) {
+	controller, mocks := setupTradeFeesController()
+	userID := int64(100)
+
+	mocks.characters.On("GetNames", mock.Anything, userID).Return(map[int64]string{2001001: "Trader"}, nil)
+	mocks.skills.On("GetSkillsForUser", mock.Anything, userID).Return(nil, errors.New("db error"))
+
+	req := httptest.NewRequest("GET", "/v1/trade-fees", nil)
+	args := &web.HandlerArgs{Request: req, User: &userID}
+
+	result, httpErr := controller.GetTradeFees(args)
+	assert.Nil(t, result)
+	assert.Equal(t, 500, httpErr.StatusCode)
+}
@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+// TradeTrustStatsRepository looks up counterparties' trade records for display
+type TradeTrustStatsRepository interface {
+	GetStats(ctx context.Context, userIDs []int64) (map[int64]*models.TradeTrustStats, error)
+}
+
+type TradeTrustRepository interface {
+	TradeTrustStatsRepository
+	UpsertRating(ctx context.Context, rating *models.PurchaseRating) error
+	GetRatingsForUser(ctx context.Context, ratedUserID int64) ([]*models.PurchaseRating, error)
+	GetSettings(ctx context.Context, userID int64) (*models.TradeTrustSettings, error)
+	UpsertSettings(ctx context.Context, settings *models.TradeTrustSettings) error
+}
+
+type PurchasesForTradeTrust interface {
+	GetByID(ctx context.Context, purchaseID int64) (*models.PurchaseTransaction, error)
+}
+
+// TradeTrustContactsRepository limits trade records to the caller's accepted contacts
+type TradeTrustContactsRepository interface {
+	GetNonContacts(ctx context.Context, ownerUserID int64, userIDs []int64) ([]int64, error)
+}
+
+type TradeTrust struct {
+	repository         TradeTrustRepository
+	purchaseRepository PurchasesForTradeTrust
+	contactsRepository TradeTrustContactsRepository
+}
+
+func NewTradeTrust(router Routerer, repository TradeTrustRepository, purchaseRepository PurchasesForTradeTrust, contactsRepository TradeTrustContactsRepository) *TradeTrust {
+	c := &TradeTrust{
+		repository:         repository,
+		purchaseRepository: purchaseRepository,
+		contactsRepository: contactsRepository,
+	}
+
+	router.RegisterRestAPIRoute("/v1/trust", web.AuthAccessUser, c.GetStats, "GET")
+	router.RegisterRestAPIRoute("/v1/trust/settings", web.AuthAccessUser, c.GetSettings, "GET")
+	router.RegisterRestAPIRoute("/v1/trust/settings", web.AuthAccessUser, c.UpdateSettings, "PUT")
+	router.RegisterRestAPIRoute("/v1/trust/{userId}/ratings", web.AuthAccessUser, c.GetRatings, "GET")
+	router.RegisterRestAPIRoute("/v1/purchases/{id}/rating", web.AuthAccessUser, c.RatePurchase, "POST")
+
+	return c
+}
+
+// checkContacts rejects user IDs that are neither the caller nor one of the caller's accepted contacts
+func (c *TradeTrust) checkContacts(ctx context.Context, callerUserID int64, userIDs []int64) *web.HttpError {
+	others := []int64{}
+	for _, userID := range userIDs {
+		if userID != callerUserID {
+			others = append(others, userID)
+		}
+	}
+	if len(others) == 0 {
+		return nil
+	}
+
+	nonContacts, err := c.contactsRepository.GetNonContacts(ctx, callerUserID, others)
+	if err != nil {
+		return &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to check contacts")}
+	}
+	if len(nonContacts) > 0 {
+		return &web.HttpError{StatusCode: 403, Error: errors.New("trade records are only visible for your contacts")}
+	}
+	return nil
+}
+
+// GetStats returns the trade records of the users in the comma-separated userIds query parameter.
+// Only the caller and their accepted contacts can be looked up.
+func (c *TradeTrust) GetStats(args *web.HandlerArgs) (any, *web.HttpError) {
+	raw := args.Request.URL.Query().Get("userIds")
+	if raw == "" {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("userIds is required")}
+	}
+
+	userIDs := []int64{}
+	for _, part := range strings.Split(raw, ",") {
+		userID, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid user ID")}
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if httpErr := c.checkContacts(args.Request.Context(), *args.User, userIDs); httpErr != nil {
+		return nil, httpErr
+	}
+
+	stats, err := c.repository.GetStats(args.Request.Context(), userIDs)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get trade stats")}
+	}
+
+	return stats, nil
+}
+
+// GetRatings returns the ratings and notes a user has received. Only the caller's own ratings and
+// those of their accepted contacts can be read.
+func (c *TradeTrust) GetRatings(args *web.HandlerArgs) (any, *web.HttpError) {
+	userID, err := strconv.ParseInt(args.Params["userId"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid user ID")}
+	}
+
+	if httpErr := c.checkContacts(args.Request.Context(), *args.User, []int64{userID}); httpErr != nil {
+		return nil, httpErr
+	}
+
+	ratings, err := c.repository.GetRatingsForUser(args.Request.Context(), userID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get ratings")}
+	}
+
+	return ratings, nil
+}
+
+type RatePurchaseRequest struct {
+	Rating int    `json:"rating"`
+	Note   string `json:"note,omitempty"`
+}
+
+// RatePurchase lets the buyer or seller of a completed purchase rate the other side
+func (c *TradeTrust) RatePurchase(args *web.HandlerArgs) (any, *web.HttpError) {
+	purchaseID, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid purchase ID")}
+	}
+
+	var req RatePurchaseRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if req.Rating < 1 || req.Rating > 5 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("rating must be between 1 and 5")}
+	}
+
+	purchase, err := c.purchaseRepository.GetByID(args.Request.Context(), purchaseID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 404, Error: errors.Wrap(err, "purchase not found")}
+	}
+
+	var ratedUserID int64
+	switch *args.User {
+	case purchase.BuyerUserID:
+		ratedUserID = purchase.SellerUserID
+	case purchase.SellerUserID:
+		ratedUserID = purchase.BuyerUserID
+	default:
+		return nil, &web.HttpError{StatusCode: 403, Error: errors.New("you are not part of this purchase")}
+	}
+
+	if purchase.Status != "completed" {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("only completed purchases can be rated")}
+	}
+
+	rating := &models.PurchaseRating{
+		PurchaseID:  purchase.ID,
+		RaterUserID: *args.User,
+		RatedUserID: ratedUserID,
+		Rating:      req.Rating,
+		Note:        optionalMessage(strings.TrimSpace(req.Note)),
+		TypeName:    purchase.TypeName,
+	}
+
+	if err := c.repository.UpsertRating(args.Request.Context(), rating); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to save rating")}
+	}
+
+	return rating, nil
+}
+
+// GetSettings returns the user's minimum trust score for auto-fulfill counterparties
+func (c *TradeTrust) GetSettings(args *web.HandlerArgs) (any, *web.HttpError) {
+	settings, err := c.repository.GetSettings(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get trust settings")}
+	}
+
+	return settings, nil
+}
+
+type updateTradeTrustSettingsRequest struct {
+	MinAutoFulfillScore *int `json:"minAutoFulfillScore"`
+}
+
+// UpdateSettings saves the user's minimum trust score. A null minimum accepts everyone.
+func (c *TradeTrust) UpdateSettings(args *web.HandlerArgs) (any, *web.HttpError) {
+	var req updateTradeTrustSettingsRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if req.MinAutoFulfillScore != nil && (*req.MinAutoFulfillScore < 0 || *req.MinAutoFulfillScore > 100) {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("minAutoFulfillScore must be between 0 and 100")}
+	}
+
+	settings := &models.TradeTrustSettings{
+		UserID:              *args.User,
+		MinAutoFulfillScore: req.MinAutoFulfillScore,
+	}
+
+	if err := c.repository.UpsertSettings(args.Request.Context(), settings); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to save trust settings")}
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,188 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTradeTrustRepository struct {
+	mock.Mock
+}
+
+func (m *MockTradeTrustRepository) GetStats(ctx context.Context, userIDs []int64) (map[int64]*models.TradeTrustStats, error) {
+	args := m.Called(ctx, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int64]*models.TradeTrustStats), args.Error(1)
+}
+
+func (m *MockTradeTrustRepository) UpsertRating(ctx context.Context, rating *models.PurchaseRating) error {
+	args := m.Called(ctx, rating)
+	return args.Error(0)
+}
+
+func (m *MockTradeTrustRepository) GetRatingsForUser(ctx context.Context, ratedUserID int64) ([]*models.PurchaseRating, error) {
+	args := m.Called(ctx, ratedUserID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PurchaseRating), args.Error(1)
+}
+
+func (m *MockTradeTrustRepository) GetSettings(ctx context.Context, userID int64) (*models.TradeTrustSettings, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TradeTrustSettings), args.Error(1)
+}
+
+func (m *MockTradeTrustRepository) UpsertSettings(ctx context.Context, settings *models.TradeTrustSettings) error {
+	args := m.Called(ctx, settings)
+	return args.Error(0)
+}
+
+type MockPurchasesForTradeTrust struct {
+	mock.Mock
+}
+
+func (m *MockPurchasesForTradeTrust) GetByID(ctx context.Context, purchaseID int64) (*models.PurchaseTransaction, error) {
+	args := m.Called(ctx, purchaseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseTransaction), args.Error(1)
+}
+
+func ratePurchase(controller *controllers.TradeTrust, userID int64, body string) (any, *web.HttpError) {
+	return controller.RatePurchase(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/purchases/7/rating", bytes.NewReader([]byte(body))),
+		Params:  map[string]string{"id": "7"},
+		User:    &userID,
+	})
+}
+
+func Test_TradeTrustController_RatePurchase_BuyerRatesSeller(t *testing.T) {
+	mockRepo := new(MockTradeTrustRepository)
+	mockPurchases := new(MockPurchasesForTradeTrust)
+
+	mockPurchases.On("GetByID", mock.Anything, int64(7)).Return(&models.PurchaseTransaction{
+		ID: 7, BuyerUserID: 100, SellerUserID: 200, TypeName: "Tritanium", Status: "completed",
+	}, nil)
+	mockRepo.On("UpsertRating", mock.Anything, mock.MatchedBy(func(rating *models.PurchaseRating) bool {
+		return rating.PurchaseID == 7 &&
+			rating.RaterUserID == 100 &&
+			rating.RatedUserID == 200 &&
+			rating.Rating == 5 &&
+			*rating.Note == "fast contract"
+	})).Return(nil)
+
+	controller := controllers.NewTradeTrust(&MockRouter{}, mockRepo, mockPurchases, new(MockContactGroupsRepository))
+	result, httpErr := ratePurchase(controller, 100, `{"rating":5,"note":" fast contract "}`)
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, int64(200), result.(*models.PurchaseRating).RatedUserID)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_TradeTrustController_RatePurchase_Rejections(t *testing.T) {
+	mockPurchases := new(MockPurchasesForTradeTrust)
+	mockPurchases.On("GetByID", mock.Anything, int64(7)).Return(&models.PurchaseTransaction{
+		ID: 7, BuyerUserID: 100, SellerUserID: 200, Status: "pending",
+	}, nil)
+
+	controller := controllers.NewTradeTrust(&MockRouter{}, new(MockTradeTrustRepository), mockPurchases, new(MockContactGroupsRepository))
+
+	_, httpErr := ratePurchase(controller, 100, `{"rating":6}`)
+	assert.Equal(t, 400, httpErr.StatusCode)
+
+	_, httpErr = ratePurchase(controller, 300, `{"rating":4}`)
+	assert.Equal(t, 403, httpErr.StatusCode)
+
+	// Not completed yet
+	_, httpErr = ratePurchase(controller, 200, `{"rating":4}`)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
+func Test_TradeTrustController_GetStats_ParsesUserIDs(t *testing.T) {
+	mockRepo := new(MockTradeTrustRepository)
+	score := 90
+	stats := map[int64]*models.TradeTrustStats{
+		100: {UserID: 100, CompletedCount: 9, CancelledCount: 1, TrustScore: &score},
+		200: {UserID: 200},
+	}
+	mockRepo.On("GetStats", mock.Anything, []int64{100, 200}).Return(stats, nil)
+	mockContacts := new(MockContactGroupsRepository)
+	mockContacts.On("GetNonContacts", mock.Anything, int64(1), []int64{100, 200}).Return([]int64{}, nil)
+
+	userID := int64(1)
+	controller := controllers.NewTradeTrust(&MockRouter{}, mockRepo, new(MockPurchasesForTradeTrust), mockContacts)
+
+	result, httpErr := controller.GetStats(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/trust?userIds=100,%20200", nil),
+		User:    &userID,
+	})
+	assert.Nil(t, httpErr)
+	assert.Equal(t, stats, result)
+
+	_, httpErr = controller.GetStats(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/trust?userIds=abc", nil),
+		User:    &userID,
+	})
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
+func Test_TradeTrustController_UpdateSettings_RejectsOutOfRange(t *testing.T) {
+	userID := int64(1)
+	controller := controllers.NewTradeTrust(&MockRouter{}, new(MockTradeTrustRepository), new(MockPurchasesForTradeTrust), new(MockContactGroupsRepository))
+
+	_, httpErr := controller.UpdateSettings(&web.HandlerArgs{
+		Request: httptest.NewRequest("PUT", "/v1/trust/settings", bytes.NewReader([]byte(`{"minAutoFulfillScore":101}`))),
+		User:    &userID,
+	})
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
+func Test_TradeTrustController_RejectsNonContacts(t *testing.T) {
+	mockRepo := new(MockTradeTrustRepository)
+	mockContacts := new(MockContactGroupsRepository)
+	mockContacts.On("GetNonContacts", mock.Anything, int64(1), []int64{100, 555}).Return([]int64{555}, nil)
+	mockContacts.On("GetNonContacts", mock.Anything, int64(1), []int64{555}).Return([]int64{555}, nil)
+	mockRepo.On("GetRatingsForUser", mock.Anything, int64(1)).Return([]*models.PurchaseRating{}, nil)
+
+	userID := int64(1)
+	controller := controllers.NewTradeTrust(&MockRouter{}, mockRepo, new(MockPurchasesForTradeTrust), mockContacts)
+
+	_, httpErr := controller.GetStats(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/trust?userIds=100,555", nil),
+		User:    &userID,
+	})
+	assert.Equal(t, 403, httpErr.StatusCode)
+
+	_, httpErr = controller.GetRatings(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/trust/555/ratings", nil),
+		Params:  map[string]string{"userId": "555"},
+		User:    &userID,
+	})
+	assert.Equal(t, 403, httpErr.StatusCode)
+
+	// The caller can always read their own ratings
+	_, httpErr = controller.GetRatings(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/trust/1/ratings", nil),
+		Params:  map[string]string{"userId": "1"},
+		User:    &userID,
+	})
+	assert.Nil(t, httpErr)
+
+	mockRepo.AssertNotCalled(t, "GetStats", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "GetRatingsForUser", mock.Anything, int64(555))
+}
@@ -25,6 +25,7 @@ type UserTradingStructuresRepository interface {
 	Upsert(ctx context.Context, s *models.UserTradingStructure) (*models.UserTradingStructure, error)
 	Delete(ctx context.Context, id int64, userID int64) error
 	UpdateAccessStatus(ctx context.Context, userID int64, structureID int64, accessOK bool) error
+	UpdateBrokerFee(ctx context.Context, id int64, userID int64, brokerFeePct *float64) error
 }
 
 // TradingStructureMarketUpdater scans a player structure's market orders.
@@ -88,6 +89,7 @@ func NewTradingStructures(
 	router.RegisterRestAPIRoute("/v1/hauling/structures", web.AuthAccessUser, c.AddStructure, "POST")
 	router.RegisterRestAPIRoute("/v1/hauling/structures/{id}", web.AuthAccessUser, c.DeleteStructure, "DELETE")
 	router.RegisterRestAPIRoute("/v1/hauling/structures/{id}/scan", web.AuthAccessUser, c.ScanStructure, "POST")
+	router.RegisterRestAPIRoute("/v1/hauling/structures/{id}/broker-fee", web.AuthAccessUser, c.UpdateBrokerFee, "PUT")
 	router.RegisterRestAPIRoute("/v1/hauling/characters/{id}/asset-structures", web.AuthAccessUser, c.ListCharacterAssetStructures, "GET")
 	return c
 }
@@ -207,6 +209,28 @@ func (c *TradingStructuresController) DeleteStructure(args *web.HandlerArgs) (in
 	return nil, nil
 }
 
+// UpdateBrokerFee sets the owner-configured broker rate used for sell orders
+// in a structure. A null rate clears it.
+func (c *TradingStructuresController) UpdateBrokerFee(args *web.HandlerArgs) (interface{}, *web.HttpError) {
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid id")}
+	}
+	var body struct {
+		BrokerFeePct *float64 `json:"brokerFeePct"`
+	}
+	if err := json.NewDecoder(args.Request.Body).Decode(&body); err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.Wrap(err, "invalid request body")}
+	}
+	if body.BrokerFeePct != nil && (*body.BrokerFeePct < 0 || *body.BrokerFeePct > 100) {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("brokerFeePct must be between 0 and 100")}
+	}
+	if err := c.structures.UpdateBrokerFee(args.Request.Context(), id, *args.User, body.BrokerFeePct); err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to update broker fee")}
+	}
+	return nil, nil
+}
+
 // ListCharacterAssetStructures returns structure IDs from the character's assets, with names where known.
 func (c *TradingStructuresController) ListCharacterAssetStructures(args *web.HandlerArgs) (interface{}, *web.HttpError) {
 	charID, err := strconv.ParseInt(args.Params["id"], 10, 64)
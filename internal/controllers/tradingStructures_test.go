@@ -63,6 +63,11 @@ func (m *MockUserTradingStructuresRepository) UpdateAccessStatus(ctx context.Con
 	return args.Error(0)
 }
 
+func (m *MockUserTradingStructuresRepository) UpdateBrokerFee(ctx context.Context, id int64, userID int64, brokerFeePct *float64) error {
+	args := m.Called(ctx, id, userID, brokerFeePct)
+	return args.Error(0)
+}
+
 // --- Mock TradingStructureMarketUpdater ---
 
 type MockTradingStructureMarketUpdater struct {
@@ -419,6 +424,52 @@ func Test_TradingStructures_DeleteStructure_InvalidID(t *testing.T) {
 	assert.Equal(t, 400, httpErr.StatusCode)
 }
 
+// --- Tests: UpdateBrokerFee ---
+
+func Test_TradingStructures_UpdateBrokerFee_Success(t *testing.T) {
+	controller, mocks := setupTradingStructuresController()
+	userID := int64(100)
+
+	mocks.structures.On("UpdateBrokerFee", mock.Anything, int64(5), userID, mock.MatchedBy(func(pct *float64) bool {
+		return pct != nil && *pct == 1.5
+	})).Return(nil)
+
+	req := httptest.NewRequest("PUT", "/v1/hauling/structures/5/broker-fee", bytes.NewBufferString(`{"brokerFeePct":1.5}`))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	result, httpErr := controller.UpdateBrokerFee(args)
+	assert.Nil(t, httpErr)
+	assert.Nil(t, result)
+	mocks.structures.AssertExpectations(t)
+}
+
+func Test_TradingStructures_UpdateBrokerFee_Clear(t *testing.T) {
+	controller, mocks := setupTradingStructuresController()
+	userID := int64(100)
+
+	mocks.structures.On("UpdateBrokerFee", mock.Anything, int64(5), userID, (*float64)(nil)).Return(nil)
+
+	req := httptest.NewRequest("PUT", "/v1/hauling/structures/5/broker-fee", bytes.NewBufferString(`{"brokerFeePct":null}`))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	_, httpErr := controller.UpdateBrokerFee(args)
+	assert.Nil(t, httpErr)
+	mocks.structures.AssertExpectations(t)
+}
+
+func Test_TradingStructures_UpdateBrokerFee_OutOfRange(t *testing.T) {
+	controller, _ := setupTradingStructuresController()
+	userID := int64(100)
+
+	req := httptest.NewRequest("PUT", "/v1/hauling/structures/5/broker-fee", bytes.NewBufferString(`{"brokerFeePct":-1}`))
+	args := &web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}}
+
+	result, httpErr := controller.UpdateBrokerFee(args)
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
 // --- Tests: ScanStructure ---
 
 func Test_TradingStructures_ScanStructure_Success(t *testing.T) {
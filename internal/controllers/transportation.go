@@ -3,7 +3,11 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	"math"
+	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/annymsMthd/industry-tool/internal/calculator"
 	"github.com/annymsMthd/industry-tool/internal/models"
@@ -37,6 +41,7 @@ type TransportJobsRepository interface {
 	UpdateStatus(ctx context.Context, id, userID int64, status string) error
 	SetQueueEntryID(ctx context.Context, id int64, queueEntryID int64) error
 	Cancel(ctx context.Context, id, userID int64) error
+	Consolidate(ctx context.Context, userID, hostID int64, jobIDs []int64, totalVolume, totalCollateral, estimatedCost float64) error
 }
 
 type TransportTriggerConfigRepository interface {
@@ -55,10 +60,13 @@ type TransportMarketPricesRepository interface {
 type TransportSolarSystemsRepository interface {
 	GetByIDs(ctx context.Context, ids []int64) ([]*models.SolarSystem, error)
 	Search(ctx context.Context, query string, limit int) ([]*models.SolarSystem, error)
+	GetJumpCandidates(ctx context.Context) ([]*models.SolarSystem, error)
 }
 
-type TransportEsiClient interface {
+// TransportRouteFinder finds gate routes, from the local stargate graph.
+type TransportRouteFinder interface {
 	GetRoute(ctx context.Context, origin, destination int64, flag string) ([]int32, error)
+	FindRoute(ctx context.Context, origin, destination int64, opts *models.RouteOptions) (*models.GateRoute, error)
 }
 
 // Controller
@@ -71,7 +79,7 @@ type Transportation struct {
 	queueRepo      TransportJobQueueRepository
 	marketRepo     TransportMarketPricesRepository
 	solarSysRepo   TransportSolarSystemsRepository
-	esiClient      TransportEsiClient
+	routes         TransportRouteFinder
 }
 
 func NewTransportation(
@@ -83,7 +91,7 @@ func NewTransportation(
 	queueRepo TransportJobQueueRepository,
 	marketRepo TransportMarketPricesRepository,
 	solarSysRepo TransportSolarSystemsRepository,
-	esiClient TransportEsiClient,
+	routes TransportRouteFinder,
 ) *Transportation {
 	c := &Transportation{
 		profilesRepo: profilesRepo,
@@ -93,7 +101,7 @@ func NewTransportation(
 		queueRepo:    queueRepo,
 		marketRepo:   marketRepo,
 		solarSysRepo: solarSysRepo,
-		esiClient:    esiClient,
+		routes:       routes,
 	}
 
 	// Transport Profiles
@@ -105,6 +113,7 @@ func NewTransportation(
 	// JF Routes
 	router.RegisterRestAPIRoute("/v1/transport/jf-routes", web.AuthAccessUser, c.GetJFRoutes, "GET")
 	router.RegisterRestAPIRoute("/v1/transport/jf-routes", web.AuthAccessUser, c.CreateJFRoute, "POST")
+	router.RegisterRestAPIRoute("/v1/transport/jf-routes/plan", web.AuthAccessUser, c.PlanJFRoute, "POST")
 	router.RegisterRestAPIRoute("/v1/transport/jf-routes/{id:[0-9]+}", web.AuthAccessUser, c.UpdateJFRoute, "PUT")
 	router.RegisterRestAPIRoute("/v1/transport/jf-routes/{id:[0-9]+}", web.AuthAccessUser, c.DeleteJFRoute, "DELETE")
 
@@ -112,6 +121,8 @@ func NewTransportation(
 	router.RegisterRestAPIRoute("/v1/transport/jobs", web.AuthAccessUser, c.GetJobs, "GET")
 	router.RegisterRestAPIRoute("/v1/transport/jobs", web.AuthAccessUser, c.CreateJob, "POST")
 	router.RegisterRestAPIRoute("/v1/transport/jobs/{id:[0-9]+}/status", web.AuthAccessUser, c.UpdateJobStatus, "POST")
+	router.RegisterRestAPIRoute("/v1/transport/jobs/consolidation", web.AuthAccessUser, c.GetConsolidation, "GET")
+	router.RegisterRestAPIRoute("/v1/transport/jobs/consolidate", web.AuthAccessUser, c.ConsolidateJobs, "POST")
 
 	// Route Calculation
 	router.RegisterRestAPIRoute("/v1/transport/route", web.AuthAccessUser, c.GetRoute, "GET")
@@ -149,6 +160,30 @@ type createProfileRequest struct {
 	FuelConservationLevel int      `json:"fuelConservationLevel"`
 	RoutePreference       string   `json:"routePreference"`
 	IsDefault             bool     `json:"isDefault"`
+	// Jump drive settings; class defaults to jump_freighter, JDC level to 5
+	JumpShipClass             string  `json:"jumpShipClass"`
+	JumpDriveCalibrationLevel *int    `json:"jumpDriveCalibrationLevel"`
+	PreferredCynoSystemIDs    []int64 `json:"preferredCynoSystemIds"`
+}
+
+// jumpSettings returns the request's jump ship class and Jump Drive
+// Calibration level with defaults applied.
+func (r *createProfileRequest) jumpSettings() (string, int, error) {
+	class := r.JumpShipClass
+	if class == "" {
+		class = calculator.DefaultJumpShipClass
+	}
+	if _, ok := calculator.JumpShipClasses[class]; !ok {
+		return "", 0, errors.Errorf("unknown jumpShipClass %q", class)
+	}
+	level := 5
+	if r.JumpDriveCalibrationLevel != nil {
+		level = *r.JumpDriveCalibrationLevel
+	}
+	if level < 0 || level > 5 {
+		return "", 0, errors.New("jumpDriveCalibrationLevel must be between 0 and 5")
+	}
+	return class, level, nil
 }
 
 func (c *Transportation) CreateProfile(args *web.HandlerArgs) (any, *web.HttpError) {
@@ -166,21 +201,28 @@ func (c *Transportation) CreateProfile(args *web.HandlerArgs) (any, *web.HttpErr
 	if req.CargoM3 <= 0 {
 		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("cargoM3 must be positive")}
 	}
+	jumpShipClass, jdcLevel, err := req.jumpSettings()
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: err}
+	}
 
 	profile := &models.TransportProfile{
-		UserID:                *args.User,
-		Name:                  req.Name,
-		TransportMethod:       req.TransportMethod,
-		CharacterID:           req.CharacterID,
-		CargoM3:               req.CargoM3,
-		RatePerM3PerJump:      req.RatePerM3PerJump,
-		CollateralRate:        req.CollateralRate,
-		CollateralPriceBasis:  req.CollateralPriceBasis,
-		FuelTypeID:            req.FuelTypeID,
-		FuelPerLY:             req.FuelPerLY,
-		FuelConservationLevel: req.FuelConservationLevel,
-		RoutePreference:       req.RoutePreference,
-		IsDefault:             req.IsDefault,
+		UserID:                    *args.User,
+		Name:                      req.Name,
+		TransportMethod:           req.TransportMethod,
+		CharacterID:               req.CharacterID,
+		CargoM3:                   req.CargoM3,
+		RatePerM3PerJump:          req.RatePerM3PerJump,
+		CollateralRate:            req.CollateralRate,
+		CollateralPriceBasis:      req.CollateralPriceBasis,
+		FuelTypeID:                req.FuelTypeID,
+		FuelPerLY:                 req.FuelPerLY,
+		FuelConservationLevel:     req.FuelConservationLevel,
+		RoutePreference:           req.RoutePreference,
+		IsDefault:                 req.IsDefault,
+		JumpShipClass:             jumpShipClass,
+		JumpDriveCalibrationLevel: jdcLevel,
+		PreferredCynoSystemIDs:    req.PreferredCynoSystemIDs,
 	}
 
 	if profile.CollateralPriceBasis == "" {
@@ -208,22 +250,29 @@ func (c *Transportation) UpdateProfile(args *web.HandlerArgs) (any, *web.HttpErr
 	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
 		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
 	}
+	jumpShipClass, jdcLevel, err := req.jumpSettings()
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: err}
+	}
 
 	profile := &models.TransportProfile{
-		ID:                    id,
-		UserID:                *args.User,
-		Name:                  req.Name,
-		TransportMethod:       req.TransportMethod,
-		CharacterID:           req.CharacterID,
-		CargoM3:               req.CargoM3,
-		RatePerM3PerJump:      req.RatePerM3PerJump,
-		CollateralRate:        req.CollateralRate,
-		CollateralPriceBasis:  req.CollateralPriceBasis,
-		FuelTypeID:            req.FuelTypeID,
-		FuelPerLY:             req.FuelPerLY,
-		FuelConservationLevel: req.FuelConservationLevel,
-		RoutePreference:       req.RoutePreference,
-		IsDefault:             req.IsDefault,
+		ID:                        id,
+		UserID:                    *args.User,
+		Name:                      req.Name,
+		TransportMethod:           req.TransportMethod,
+		CharacterID:               req.CharacterID,
+		CargoM3:                   req.CargoM3,
+		RatePerM3PerJump:          req.RatePerM3PerJump,
+		CollateralRate:            req.CollateralRate,
+		CollateralPriceBasis:      req.CollateralPriceBasis,
+		FuelTypeID:                req.FuelTypeID,
+		FuelPerLY:                 req.FuelPerLY,
+		FuelConservationLevel:     req.FuelConservationLevel,
+		RoutePreference:           req.RoutePreference,
+		IsDefault:                 req.IsDefault,
+		JumpShipClass:             jumpShipClass,
+		JumpDriveCalibrationLevel: jdcLevel,
+		PreferredCynoSystemIDs:    req.PreferredCynoSystemIDs,
 	}
 
 	if profile.CollateralPriceBasis == "" {
@@ -277,6 +326,41 @@ type createJFRouteRequest struct {
 	OriginSystemID      int64                    `json:"originSystemId"`
 	DestinationSystemID int64                    `json:"destinationSystemId"`
 	Waypoints           []jfRouteWaypointRequest `json:"waypoints"`
+	// Optional; every jump is checked against the profile's jump range, or a
+	// default jump freighter's range when unset
+	TransportProfileID *int64 `json:"transportProfileId"`
+}
+
+// checkJFRouteRange validates a route's jumps against the range of the given
+// transport profile. Routes saved without a profile are checked against a jump
+// freighter with Jump Drive Calibration V, the same default PlanJFRoute uses.
+func (c *Transportation) checkJFRouteRange(
+	ctx context.Context,
+	profileID *int64,
+	userID int64,
+	waypoints []*models.JFRouteWaypoint,
+	systemCoords map[int64]*models.SolarSystem,
+) *web.HttpError {
+	profile := &models.TransportProfile{
+		JumpShipClass:             calculator.DefaultJumpShipClass,
+		JumpDriveCalibrationLevel: 5,
+	}
+	if profileID != nil {
+		p, err := c.profilesRepo.GetByID(ctx, *profileID, userID)
+		if err != nil {
+			return &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get transport profile")}
+		}
+		if p == nil {
+			return &web.HttpError{StatusCode: 400, Error: errors.New("transport profile not found")}
+		}
+		profile = p
+	}
+
+	rangeLY := calculator.JumpRangeLY(profile.JumpShipClass, profile.JumpDriveCalibrationLevel)
+	if err := calculator.ValidateJFRoute(waypoints, systemCoords, rangeLY); err != nil {
+		return &web.HttpError{StatusCode: 400, Error: err}
+	}
+	return nil
 }
 
 func (c *Transportation) CreateJFRoute(args *web.HandlerArgs) (any, *web.HttpError) {
@@ -313,6 +397,10 @@ func (c *Transportation) CreateJFRoute(args *web.HandlerArgs) (any, *web.HttpErr
 		systemCoords[s.ID] = s
 	}
 
+	if httpErr := c.checkJFRouteRange(args.Request.Context(), req.TransportProfileID, *args.User, waypoints, systemCoords); httpErr != nil {
+		return nil, httpErr
+	}
+
 	route := &models.JFRoute{
 		UserID:              *args.User,
 		Name:                req.Name,
@@ -359,6 +447,10 @@ func (c *Transportation) UpdateJFRoute(args *web.HandlerArgs) (any, *web.HttpErr
 		systemCoords[s.ID] = s
 	}
 
+	if httpErr := c.checkJFRouteRange(args.Request.Context(), req.TransportProfileID, *args.User, waypoints, systemCoords); httpErr != nil {
+		return nil, httpErr
+	}
+
 	route := &models.JFRoute{
 		ID:                  id,
 		UserID:              *args.User,
@@ -379,6 +471,128 @@ func (c *Transportation) UpdateJFRoute(args *web.HandlerArgs) (any, *web.HttpErr
 	return updated, nil
 }
 
+type planJFRouteRequest struct {
+	OriginSystemID      int64  `json:"originSystemId"`
+	DestinationSystemID int64  `json:"destinationSystemId"`
+	TransportProfileID  *int64 `json:"transportProfileId"`
+	Optimize            string `json:"optimize"`
+}
+
+// PlanJFRoute proposes cyno midpoints between two systems. Midpoints are the
+// profile's preferred cyno systems when it has any, otherwise any lowsec or
+// nullsec system. Without a profile a jump freighter with JDC V is assumed.
+func (c *Transportation) PlanJFRoute(args *web.HandlerArgs) (any, *web.HttpError) {
+	var req planJFRouteRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if req.OriginSystemID <= 0 || req.DestinationSystemID <= 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("originSystemId and destinationSystemId are required")}
+	}
+	if req.Optimize == "" {
+		req.Optimize = "jumps"
+	}
+	if req.Optimize != "jumps" && req.Optimize != "fuel" {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("optimize must be jumps or fuel")}
+	}
+
+	ctx := args.Request.Context()
+
+	profile := &models.TransportProfile{
+		CargoM3:                   1,
+		JumpShipClass:             calculator.DefaultJumpShipClass,
+		JumpDriveCalibrationLevel: 5,
+	}
+	if req.TransportProfileID != nil {
+		p, err := c.profilesRepo.GetByID(ctx, *req.TransportProfileID, *args.User)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get transport profile")}
+		}
+		if p == nil {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.New("transport profile not found")}
+		}
+		profile = p
+	}
+
+	endpoints, err := c.solarSysRepo.GetByIDs(ctx, []int64{req.OriginSystemID, req.DestinationSystemID})
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get solar systems")}
+	}
+	var origin, destination *models.SolarSystem
+	for _, s := range endpoints {
+		if s.ID == req.OriginSystemID {
+			origin = s
+		}
+		if s.ID == req.DestinationSystemID {
+			destination = s
+		}
+	}
+	if origin == nil || destination == nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("origin or destination system not found")}
+	}
+
+	var candidates []*models.SolarSystem
+	if len(profile.PreferredCynoSystemIDs) > 0 {
+		candidates, err = c.solarSysRepo.GetByIDs(ctx, profile.PreferredCynoSystemIDs)
+	} else {
+		candidates, err = c.solarSysRepo.GetJumpCandidates(ctx)
+	}
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get cyno systems")}
+	}
+
+	rangeLY := calculator.JumpRangeLY(profile.JumpShipClass, profile.JumpDriveCalibrationLevel)
+	waypoints, err := calculator.PlanJFRoute(&calculator.JFPlanParams{
+		Origin:      origin,
+		Destination: destination,
+		Candidates:  candidates,
+		RangeLY:     rangeLY,
+		Optimize:    req.Optimize,
+	})
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: err}
+	}
+
+	fuel := calculator.CalculateJFTransportCost(&calculator.JFTransportCostParams{
+		CargoM3:               profile.CargoM3,
+		FuelPerLY:             calculator.JumpFuelPerLY(profile),
+		FuelConservationLevel: profile.FuelConservationLevel,
+		IsotopePrice:          c.isotopePrice(ctx, profile),
+		Waypoints:             waypoints,
+	})
+
+	totalDistance := 0.0
+	for _, wp := range waypoints {
+		totalDistance += wp.DistanceLY
+	}
+
+	return &models.JFRoutePlan{
+		OriginSystemID:      origin.ID,
+		DestinationSystemID: destination.ID,
+		Optimize:            req.Optimize,
+		JumpRangeLY:         rangeLY,
+		Jumps:               len(waypoints) - 1,
+		TotalDistanceLY:     totalDistance,
+		TotalFuel:           fuel.TotalFuel,
+		FuelCost:            fuel.FuelCost,
+		Waypoints:           waypoints,
+	}, nil
+}
+
+// isotopePrice returns the Jita price of a profile's fuel type on the
+// profile's price basis, or 0 when no fuel type is set or prices fail to load.
+func (c *Transportation) isotopePrice(ctx context.Context, profile *models.TransportProfile) float64 {
+	if profile.FuelTypeID == nil {
+		return 0
+	}
+	prices, err := c.marketRepo.GetAllJitaPrices(ctx)
+	if err != nil {
+		return 0
+	}
+	return calculator.PriceForBasis(prices[*profile.FuelTypeID], profile.CollateralPriceBasis)
+}
+
 func (c *Transportation) DeleteJFRoute(args *web.HandlerArgs) (any, *web.HttpError) {
 	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
 	if err != nil {
@@ -476,8 +690,8 @@ func (c *Transportation) CreateJob(args *web.HandlerArgs) (any, *web.HttpError)
 
 		switch req.TransportMethod {
 		case "freighter", "dst", "blockade_runner":
-			// Gate-based: use ESI route for jump count
-			route, err := c.esiClient.GetRoute(ctx, req.OriginSystemID, req.DestinationSystemID, req.RoutePreference)
+			// Gate-based: use the gate route for jump count
+			route, err := c.routes.GetRoute(ctx, req.OriginSystemID, req.DestinationSystemID, req.RoutePreference)
 			if err != nil {
 				return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to calculate route")}
 			}
@@ -507,48 +721,14 @@ func (c *Transportation) CreateJob(args *web.HandlerArgs) (any, *web.HttpError)
 					distanceLY = &d
 					jumps = len(jfRoute.Waypoints) - 1
 
-					// Get isotope price
-					isotopePrice := 0.0
-					if profile.FuelTypeID != nil {
-						prices, err := c.marketRepo.GetAllJitaPrices(ctx)
-						if err == nil {
-							if p, ok := prices[*profile.FuelTypeID]; ok {
-								switch profile.CollateralPriceBasis {
-								case "buy":
-									if p.BuyPrice != nil {
-										isotopePrice = *p.BuyPrice
-									}
-								case "split":
-									buy, sell := 0.0, 0.0
-									if p.BuyPrice != nil {
-										buy = *p.BuyPrice
-									}
-									if p.SellPrice != nil {
-										sell = *p.SellPrice
-									}
-									isotopePrice = (buy + sell) / 2
-								default:
-									if p.SellPrice != nil {
-										isotopePrice = *p.SellPrice
-									}
-								}
-							}
-						}
-					}
-
-					fuelPerLY := 0.0
-					if profile.FuelPerLY != nil {
-						fuelPerLY = *profile.FuelPerLY
-					}
-
 					result := calculator.CalculateJFTransportCost(&calculator.JFTransportCostParams{
 						TotalVolumeM3:         totalVolume,
 						TotalCollateral:       totalValue,
 						CargoM3:               profile.CargoM3,
 						CollateralRate:        profile.CollateralRate,
-						FuelPerLY:             fuelPerLY,
+						FuelPerLY:             calculator.JumpFuelPerLY(profile),
 						FuelConservationLevel: profile.FuelConservationLevel,
-						IsotopePrice:          isotopePrice,
+						IsotopePrice:          c.isotopePrice(ctx, profile),
 						Waypoints:             jfRoute.Waypoints,
 					})
 					estimatedCost = result.Cost
@@ -633,12 +813,203 @@ func (c *Transportation) UpdateJobStatus(args *web.HandlerArgs) (any, *web.HttpE
 	return map[string]bool{"success": true}, nil
 }
 
+// ---- Job Consolidation ----
+
+// GetConsolidation proposes merging open self-haul jobs that share a lane, or
+// ride a stretch of another job's route, into single trips.
+func (c *Transportation) GetConsolidation(args *web.HandlerArgs) (any, *web.HttpError) {
+	proposals, err := c.planConsolidation(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: err}
+	}
+	return proposals, nil
+}
+
+type consolidateJobsRequest struct {
+	JobIDs []int64 `json:"jobIds"`
+}
+
+// ConsolidateJobs applies a consolidation proposal. The proposal is planned
+// again so stale or hand-picked job sets are rejected.
+func (c *Transportation) ConsolidateJobs(args *web.HandlerArgs) (any, *web.HttpError) {
+	var req consolidateJobsRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+	if len(req.JobIDs) < 2 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("at least two jobIds are required")}
+	}
+
+	ctx := args.Request.Context()
+	proposals, err := c.planConsolidation(ctx, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: err}
+	}
+
+	requested := map[int64]bool{}
+	for _, id := range req.JobIDs {
+		requested[id] = true
+	}
+	var proposal *models.TransportConsolidation
+	for _, p := range proposals {
+		if len(p.JobIDs) != len(requested) {
+			continue
+		}
+		matches := true
+		for _, id := range p.JobIDs {
+			matches = matches && requested[id]
+		}
+		if matches {
+			proposal = p
+			break
+		}
+	}
+	if proposal == nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("these transport jobs cannot be consolidated")}
+	}
+
+	merged := []int64{}
+	for _, id := range proposal.JobIDs {
+		if id != proposal.HostJobID {
+			merged = append(merged, id)
+		}
+	}
+	if err := c.jobsRepo.Consolidate(ctx, *args.User, proposal.HostJobID, merged,
+		proposal.TotalVolumeM3, proposal.TotalCollateral, proposal.ConsolidatedCost); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to consolidate transport jobs")}
+	}
+
+	return proposal, nil
+}
+
+// planConsolidation groups the user's planned self-haul jobs by transport
+// profile and plans consolidated trips for each profile, costed the same way
+// jobs are costed at creation. Jobs whose route cannot be resolved are left out.
+func (c *Transportation) planConsolidation(ctx context.Context, userID int64) ([]*models.TransportConsolidation, error) {
+	jobs, err := c.jobsRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get transport jobs")
+	}
+
+	byProfile := map[int64][]*models.TransportJob{}
+	profileIDs := []int64{}
+	for _, job := range jobs {
+		if job.Status != "planned" || job.FulfillmentType != "self_haul" || job.TransportProfileID == nil {
+			continue
+		}
+		id := *job.TransportProfileID
+		if _, ok := byProfile[id]; !ok {
+			profileIDs = append(profileIDs, id)
+		}
+		byProfile[id] = append(byProfile[id], job)
+	}
+
+	proposals := []*models.TransportConsolidation{}
+	for _, profileID := range profileIDs {
+		if len(byProfile[profileID]) < 2 {
+			continue
+		}
+		profile, err := c.profilesRepo.GetByID(ctx, profileID, userID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get transport profile")
+		}
+		if profile == nil {
+			continue
+		}
+
+		planned, err := c.planProfileConsolidation(ctx, userID, profile, byProfile[profileID])
+		if err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, planned...)
+	}
+
+	sort.SliceStable(proposals, func(a, b int) bool { return proposals[a].IskSaved > proposals[b].IskSaved })
+	return proposals, nil
+}
+
+func (c *Transportation) planProfileConsolidation(ctx context.Context, userID int64, profile *models.TransportProfile, jobs []*models.TransportJob) ([]*models.TransportConsolidation, error) {
+	candidates := []*calculator.ConsolidationCandidate{}
+	var costFor calculator.ConsolidationCostFunc
+
+	switch profile.TransportMethod {
+	case "freighter", "dst", "blockade_runner":
+		for _, job := range jobs {
+			if job.TransportMethod != profile.TransportMethod {
+				continue
+			}
+			route, err := c.routes.GetRoute(ctx, job.OriginSystemID, job.DestinationSystemID, job.RoutePreference)
+			if err != nil {
+				continue
+			}
+			systems := make([]int64, len(route))
+			for i, id := range route {
+				systems[i] = int64(id)
+			}
+			candidates = append(candidates, &calculator.ConsolidationCandidate{Job: job, Route: systems})
+		}
+		costFor = func(host *calculator.ConsolidationCandidate, volume, collateral float64) (int, float64) {
+			result := calculator.CalculateGateTransportCost(&calculator.GateTransportCostParams{
+				TotalVolumeM3:    volume,
+				TotalCollateral:  collateral,
+				Jumps:            len(host.Route) - 1,
+				CargoM3:          profile.CargoM3,
+				RatePerM3PerJump: profile.RatePerM3PerJump,
+				CollateralRate:   profile.CollateralRate,
+			})
+			return result.Trips, result.Cost
+		}
+
+	case "jump_freighter":
+		waypoints := map[int64][]*models.JFRouteWaypoint{}
+		for _, job := range jobs {
+			if job.TransportMethod != profile.TransportMethod || job.JFRouteID == nil {
+				continue
+			}
+			jfRoute, err := c.jfRoutesRepo.GetByID(ctx, *job.JFRouteID, userID)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get JF route")
+			}
+			if jfRoute == nil {
+				continue
+			}
+			sort.Slice(jfRoute.Waypoints, func(a, b int) bool { return jfRoute.Waypoints[a].Sequence < jfRoute.Waypoints[b].Sequence })
+			systems := make([]int64, len(jfRoute.Waypoints))
+			for i, wp := range jfRoute.Waypoints {
+				systems[i] = wp.SystemID
+			}
+			waypoints[job.ID] = jfRoute.Waypoints
+			candidates = append(candidates, &calculator.ConsolidationCandidate{Job: job, Route: systems})
+		}
+		isotopePrice := c.isotopePrice(ctx, profile)
+		costFor = func(host *calculator.ConsolidationCandidate, volume, collateral float64) (int, float64) {
+			result := calculator.CalculateJFTransportCost(&calculator.JFTransportCostParams{
+				TotalVolumeM3:         volume,
+				TotalCollateral:       collateral,
+				CargoM3:               profile.CargoM3,
+				CollateralRate:        profile.CollateralRate,
+				FuelPerLY:             calculator.JumpFuelPerLY(profile),
+				FuelConservationLevel: profile.FuelConservationLevel,
+				IsotopePrice:          isotopePrice,
+				Waypoints:             waypoints[host.Job.ID],
+			})
+			return result.Trips, result.Cost
+		}
+
+	default:
+		return nil, nil
+	}
+
+	return calculator.PlanConsolidation(candidates, costFor), nil
+}
+
 // ---- Route Calculation ----
 
 func (c *Transportation) GetRoute(args *web.HandlerArgs) (any, *web.HttpError) {
-	originStr := args.Request.URL.Query().Get("origin")
-	destStr := args.Request.URL.Query().Get("destination")
-	flag := args.Request.URL.Query().Get("flag")
+	query := args.Request.URL.Query()
+	originStr := query.Get("origin")
+	destStr := query.Get("destination")
+	flag := query.Get("flag")
 
 	if originStr == "" || destStr == "" {
 		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("origin and destination query params required")}
@@ -657,17 +1028,93 @@ func (c *Transportation) GetRoute(args *web.HandlerArgs) (any, *web.HttpError) {
 		flag = "shortest"
 	}
 
-	route, err := c.esiClient.GetRoute(args.Request.Context(), origin, dest, flag)
+	opts := &models.RouteOptions{Flag: flag}
+	if opts.AvoidSystemIDs, err = parseIDList(query.Get("avoidSystems")); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid avoidSystems")}
+	}
+	if opts.AvoidRegionIDs, err = parseIDList(query.Get("avoidRegions")); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid avoidRegions")}
+	}
+	if v := query.Get("minSecurity"); v != "" {
+		minSecurity, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid minSecurity")}
+		}
+		opts.MinSecurity = &minSecurity
+	}
+	if opts.JumpCosts, err = parseRouteJumpCosts(query, flag); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: err}
+	}
+
+	route, err := c.routes.FindRoute(args.Request.Context(), origin, dest, opts)
 	if err != nil {
+		var unknownSystem *calculator.UnknownRouteSystemError
+		if errors.As(err, &unknownSystem) {
+			return nil, &web.HttpError{StatusCode: 400, Error: err}
+		}
+		var noRoute *calculator.NoGateRouteError
+		if errors.As(err, &noRoute) {
+			return nil, &web.HttpError{StatusCode: 404, Error: err}
+		}
 		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get route")}
 	}
 
 	return map[string]any{
-		"route": route,
-		"jumps": len(route) - 1,
+		"route": route.Route,
+		"jumps": route.Jumps,
+		"cost":  route.Cost,
 	}, nil
 }
 
+// parseRouteJumpCosts reads the highsecCost, lowsecCost and nullsecCost
+// params. Bands left out keep the cost implied by flag; nil when none are set.
+func parseRouteJumpCosts(query url.Values, flag string) (*models.RouteJumpCosts, error) {
+	costs := calculator.RouteJumpCostsForFlag(flag)
+	set := false
+	for _, band := range []struct {
+		param string
+		cost  *float64
+	}{
+		{"highsecCost", &costs.Highsec},
+		{"lowsecCost", &costs.Lowsec},
+		{"nullsecCost", &costs.Nullsec},
+	} {
+		v := query.Get(band.param)
+		if v == "" {
+			continue
+		}
+		cost, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", band.param)
+		}
+		if cost <= 0 || math.IsInf(cost, 0) || math.IsNaN(cost) {
+			return nil, errors.Errorf("%s must be positive", band.param)
+		}
+		*band.cost = cost
+		set = true
+	}
+	if !set {
+		return nil, nil
+	}
+	return &costs, nil
+}
+
+// parseIDList parses a comma-separated list of IDs; empty input gives nil.
+func parseIDList(raw string) ([]int64, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	ids := []int64{}
+	for _, part := range strings.Split(raw, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 // ---- Solar System Search ----
 
 func (c *Transportation) SearchSystems(args *web.HandlerArgs) (any, *web.HttpError) {
@@ -8,11 +8,13 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/annymsMthd/industry-tool/internal/calculator"
 	"github.com/annymsMthd/industry-tool/internal/controllers"
 	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/web"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // --- Mock repositories ---
@@ -129,6 +131,10 @@ func (m *MockTransportJobsRepo) Cancel(ctx context.Context, id, userID int64) er
 	args := m.Called(ctx, id, userID)
 	return args.Error(0)
 }
+func (m *MockTransportJobsRepo) Consolidate(ctx context.Context, userID, hostID int64, jobIDs []int64, totalVolume, totalCollateral, estimatedCost float64) error {
+	args := m.Called(ctx, userID, hostID, jobIDs, totalVolume, totalCollateral, estimatedCost)
+	return args.Error(0)
+}
 
 type MockTransportTriggerConfigRepo struct{ mock.Mock }
 
@@ -183,10 +189,17 @@ func (m *MockTransportSolarSystemsRepo) Search(ctx context.Context, query string
 	}
 	return args.Get(0).([]*models.SolarSystem), args.Error(1)
 }
+func (m *MockTransportSolarSystemsRepo) GetJumpCandidates(ctx context.Context) ([]*models.SolarSystem, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.SolarSystem), args.Error(1)
+}
 
-type MockTransportEsiClient struct{ mock.Mock }
+type MockTransportRouteFinder struct{ mock.Mock }
 
-func (m *MockTransportEsiClient) GetRoute(ctx context.Context, origin, destination int64, flag string) ([]int32, error) {
+func (m *MockTransportRouteFinder) GetRoute(ctx context.Context, origin, destination int64, flag string) ([]int32, error) {
 	args := m.Called(ctx, origin, destination, flag)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -194,9 +207,17 @@ func (m *MockTransportEsiClient) GetRoute(ctx context.Context, origin, destinati
 	return args.Get(0).([]int32), args.Error(1)
 }
 
+func (m *MockTransportRouteFinder) FindRoute(ctx context.Context, origin, destination int64, opts *models.RouteOptions) (*models.GateRoute, error) {
+	args := m.Called(ctx, origin, destination, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GateRoute), args.Error(1)
+}
+
 // --- Helper ---
 
-func newTransportController() (*controllers.Transportation, *MockTransportProfilesRepo, *MockJFRoutesRepo, *MockTransportJobsRepo, *MockTransportTriggerConfigRepo, *MockTransportJobQueueRepo, *MockTransportMarketPricesRepo, *MockTransportSolarSystemsRepo, *MockTransportEsiClient) {
+func newTransportController() (*controllers.Transportation, *MockTransportProfilesRepo, *MockJFRoutesRepo, *MockTransportJobsRepo, *MockTransportTriggerConfigRepo, *MockTransportJobQueueRepo, *MockTransportMarketPricesRepo, *MockTransportSolarSystemsRepo, *MockTransportRouteFinder) {
 	profilesRepo := &MockTransportProfilesRepo{}
 	jfRoutesRepo := &MockJFRoutesRepo{}
 	jobsRepo := &MockTransportJobsRepo{}
@@ -204,7 +225,7 @@ func newTransportController() (*controllers.Transportation, *MockTransportProfil
 	queueRepo := &MockTransportJobQueueRepo{}
 	marketRepo := &MockTransportMarketPricesRepo{}
 	solarSysRepo := &MockTransportSolarSystemsRepo{}
-	esiClient := &MockTransportEsiClient{}
+	routeFinder := &MockTransportRouteFinder{}
 
 	c := controllers.NewTransportation(
 		&MockRouter{},
@@ -215,10 +236,10 @@ func newTransportController() (*controllers.Transportation, *MockTransportProfil
 		queueRepo,
 		marketRepo,
 		solarSysRepo,
-		esiClient,
+		routeFinder,
 	)
 
-	return c, profilesRepo, jfRoutesRepo, jobsRepo, triggerRepo, queueRepo, marketRepo, solarSysRepo, esiClient
+	return c, profilesRepo, jfRoutesRepo, jobsRepo, triggerRepo, queueRepo, marketRepo, solarSysRepo, routeFinder
 }
 
 // --- Transport Profile Tests ---
@@ -284,6 +305,38 @@ func Test_TransportCreateProfile(t *testing.T) {
 	profile := result.(*models.TransportProfile)
 	assert.Equal(t, "New Profile", profile.Name)
 	assert.Equal(t, 350000.0, profile.CargoM3)
+
+	saved := profilesRepo.Calls[0].Arguments.Get(1).(*models.TransportProfile)
+	assert.Equal(t, "jump_freighter", saved.JumpShipClass)
+	assert.Equal(t, 5, saved.JumpDriveCalibrationLevel)
+}
+
+func Test_TransportCreateProfileInvalidJumpSettings(t *testing.T) {
+	c, _, _, _, _, _, _, _, _ := newTransportController()
+
+	for _, extra := range []map[string]any{
+		{"jumpShipClass": "titanic"},
+		{"jumpDriveCalibrationLevel": 6},
+	} {
+		req := map[string]any{
+			"name":            "JF",
+			"transportMethod": "jump_freighter",
+			"cargoM3":         320000,
+		}
+		for k, v := range extra {
+			req[k] = v
+		}
+		body, _ := json.Marshal(req)
+
+		userID := int64(42)
+		_, httpErr := c.CreateProfile(&web.HandlerArgs{
+			Request: httptest.NewRequest("POST", "/v1/transport/profiles", bytes.NewReader(body)),
+			User:    &userID,
+		})
+
+		assert.NotNil(t, httpErr)
+		assert.Equal(t, 400, httpErr.StatusCode)
+	}
 }
 
 func Test_TransportCreateProfileMissingName(t *testing.T) {
@@ -450,13 +503,10 @@ func Test_TransportGetJFRoutesError(t *testing.T) {
 func Test_TransportCreateJFRoute(t *testing.T) {
 	c, _, jfRoutesRepo, _, _, _, _, solarSysRepo, _ := newTransportController()
 
-	x1, y1, z1 := 1.0e17, 2.0e17, 3.0e17
-	x2, y2, z2 := 4.0e17, 5.0e17, 6.0e17
-
 	solarSysRepo.On("GetByIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return(
 		[]*models.SolarSystem{
-			{ID: 30000142, Name: "Jita", X: &x1, Y: &y1, Z: &z1},
-			{ID: 30002187, Name: "Amarr", X: &x2, Y: &y2, Z: &z2},
+			jumpTestSystem(30000142, "Jita", 0.9, 0),
+			jumpTestSystem(30002187, "Amarr", 0.3, 8.5),
 		}, nil,
 	)
 
@@ -537,13 +587,10 @@ func Test_TransportCreateJFRouteTooFewWaypoints(t *testing.T) {
 func Test_TransportUpdateJFRoute(t *testing.T) {
 	c, _, jfRoutesRepo, _, _, _, _, solarSysRepo, _ := newTransportController()
 
-	x1, y1, z1 := 1.0e17, 2.0e17, 3.0e17
-	x2, y2, z2 := 4.0e17, 5.0e17, 6.0e17
-
 	solarSysRepo.On("GetByIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return(
 		[]*models.SolarSystem{
-			{ID: 30000142, Name: "Jita", X: &x1, Y: &y1, Z: &z1},
-			{ID: 30002187, Name: "Amarr", X: &x2, Y: &y2, Z: &z2},
+			jumpTestSystem(30000142, "Jita", 0.9, 0),
+			jumpTestSystem(30002187, "Amarr", 0.3, 8.5),
 		}, nil,
 	)
 
@@ -580,7 +627,10 @@ func Test_TransportUpdateJFRouteNotFound(t *testing.T) {
 	c, _, jfRoutesRepo, _, _, _, _, solarSysRepo, _ := newTransportController()
 
 	solarSysRepo.On("GetByIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return(
-		[]*models.SolarSystem{}, nil,
+		[]*models.SolarSystem{
+			jumpTestSystem(30000142, "Jita", 0.9, 0),
+			jumpTestSystem(30002187, "Amarr", 0.3, 8.5),
+		}, nil,
 	)
 
 	jfRoutesRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.JFRoute"), mock.AnythingOfType("map[int64]*models.SolarSystem")).Return(nil, nil)
@@ -606,6 +656,235 @@ func Test_TransportUpdateJFRouteNotFound(t *testing.T) {
 	assert.Equal(t, 404, httpErr.StatusCode)
 }
 
+// jumpTestSystem places a system xLY light years along the x axis.
+func jumpTestSystem(id int64, name string, security, xLY float64) *models.SolarSystem {
+	x, y, z := xLY*9.461e+15, 0.0, 0.0
+	return &models.SolarSystem{ID: id, Name: name, Security: security, X: &x, Y: &y, Z: &z}
+}
+
+func Test_TransportCreateJFRouteOutOfRange(t *testing.T) {
+	c, profilesRepo, jfRoutesRepo, _, _, _, _, solarSysRepo, _ := newTransportController()
+
+	profilesRepo.On("GetByID", mock.Anything, int64(20), int64(42)).Return(
+		&models.TransportProfile{
+			ID: 20, UserID: 42, TransportMethod: "jump_freighter", CargoM3: 320000,
+			JumpShipClass: "jump_freighter", JumpDriveCalibrationLevel: 4,
+		}, nil,
+	)
+	solarSysRepo.On("GetByIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return(
+		[]*models.SolarSystem{
+			jumpTestSystem(30000142, "Jita", 0.9, 0),
+			jumpTestSystem(30002813, "Tama", 0.3, 9.5),
+		}, nil,
+	)
+
+	body, _ := json.Marshal(map[string]any{
+		"name":                "Jita to Tama",
+		"originSystemId":      30000142,
+		"destinationSystemId": 30002813,
+		"transportProfileId":  20,
+		"waypoints": []any{
+			map[string]any{"sequence": 0, "systemId": 30000142},
+			map[string]any{"sequence": 1, "systemId": 30002813},
+		},
+	})
+
+	userID := int64(42)
+	_, httpErr := c.CreateJFRoute(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/transport/jf-routes", bytes.NewReader(body)),
+		User:    &userID,
+	})
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	assert.Contains(t, httpErr.Error.Error(), "beyond the 9.00 LY jump range")
+	jfRoutesRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_TransportCreateJFRouteOutOfDefaultRange(t *testing.T) {
+	c, profilesRepo, jfRoutesRepo, _, _, _, _, solarSysRepo, _ := newTransportController()
+
+	solarSysRepo.On("GetByIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return(
+		[]*models.SolarSystem{
+			jumpTestSystem(30000142, "Jita", 0.9, 0),
+			jumpTestSystem(30002813, "Tama", 0.3, 12),
+		}, nil,
+	)
+
+	// No profile: checked against a jump freighter with JDC V
+	body, _ := json.Marshal(map[string]any{
+		"name":                "Jita to Tama",
+		"originSystemId":      30000142,
+		"destinationSystemId": 30002813,
+		"waypoints": []any{
+			map[string]any{"sequence": 0, "systemId": 30000142},
+			map[string]any{"sequence": 1, "systemId": 30002813},
+		},
+	})
+
+	userID := int64(42)
+	_, httpErr := c.CreateJFRoute(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/transport/jf-routes", bytes.NewReader(body)),
+		User:    &userID,
+	})
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	assert.Contains(t, httpErr.Error.Error(), "beyond the 10.00 LY jump range")
+	profilesRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	jfRoutesRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_TransportCreateJFRouteWithinRange(t *testing.T) {
+	c, profilesRepo, jfRoutesRepo, _, _, _, _, solarSysRepo, _ := newTransportController()
+
+	profilesRepo.On("GetByID", mock.Anything, int64(20), int64(42)).Return(
+		&models.TransportProfile{
+			ID: 20, UserID: 42, TransportMethod: "jump_freighter", CargoM3: 320000,
+			JumpShipClass: "jump_freighter", JumpDriveCalibrationLevel: 5,
+		}, nil,
+	)
+	solarSysRepo.On("GetByIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return(
+		[]*models.SolarSystem{
+			jumpTestSystem(30000142, "Jita", 0.9, 0),
+			jumpTestSystem(30002813, "Tama", 0.3, 9.5),
+		}, nil,
+	)
+	jfRoutesRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.JFRoute"), mock.AnythingOfType("map[int64]*models.SolarSystem")).Return(
+		&models.JFRoute{ID: 1, UserID: 42, Name: "Jita to Tama", TotalDistanceLY: 9.5}, nil,
+	)
+
+	body, _ := json.Marshal(map[string]any{
+		"name":                "Jita to Tama",
+		"originSystemId":      30000142,
+		"destinationSystemId": 30002813,
+		"transportProfileId":  20,
+		"waypoints": []any{
+			map[string]any{"sequence": 0, "systemId": 30000142},
+			map[string]any{"sequence": 1, "systemId": 30002813},
+		},
+	})
+
+	userID := int64(42)
+	result, httpErr := c.CreateJFRoute(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/transport/jf-routes", bytes.NewReader(body)),
+		User:    &userID,
+	})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, 9.5, result.(*models.JFRoute).TotalDistanceLY)
+}
+
+func Test_TransportPlanJFRoute(t *testing.T) {
+	c, profilesRepo, _, _, _, _, marketRepo, solarSysRepo, _ := newTransportController()
+
+	fuelTypeID := int64(16274)
+	sellPrice := 1000.0
+	profilesRepo.On("GetByID", mock.Anything, int64(20), int64(42)).Return(
+		&models.TransportProfile{
+			ID: 20, UserID: 42, TransportMethod: "jump_freighter", CargoM3: 320000,
+			CollateralPriceBasis: "sell", FuelTypeID: &fuelTypeID, FuelConservationLevel: 5,
+			JumpShipClass: "jump_freighter", JumpDriveCalibrationLevel: 5,
+		}, nil,
+	)
+	solarSysRepo.On("GetByIDs", mock.Anything, []int64{30000142, 30004759}).Return(
+		[]*models.SolarSystem{
+			jumpTestSystem(30000142, "Jita", 0.9, 0),
+			jumpTestSystem(30004759, "1DQ1-A", -0.4, 16),
+		}, nil,
+	)
+	solarSysRepo.On("GetJumpCandidates", mock.Anything).Return(
+		[]*models.SolarSystem{
+			jumpTestSystem(30002813, "Tama", 0.3, 8),
+			jumpTestSystem(30003504, "Niarja", 0.2, 12),
+		}, nil,
+	)
+	marketRepo.On("GetAllJitaPrices", mock.Anything).Return(
+		map[int64]*models.MarketPrice{16274: {SellPrice: &sellPrice}}, nil,
+	)
+
+	body, _ := json.Marshal(map[string]any{
+		"originSystemId":      30000142,
+		"destinationSystemId": 30004759,
+		"transportProfileId":  20,
+	})
+
+	userID := int64(42)
+	result, httpErr := c.PlanJFRoute(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/transport/jf-routes/plan", bytes.NewReader(body)),
+		User:    &userID,
+	})
+
+	assert.Nil(t, httpErr)
+	plan := result.(*models.JFRoutePlan)
+	assert.Equal(t, "jumps", plan.Optimize)
+	assert.Equal(t, 2, plan.Jumps)
+	assert.Equal(t, int64(30002813), plan.Waypoints[1].SystemID)
+	assert.InDelta(t, 10.0, plan.JumpRangeLY, 0.0001)
+	assert.InDelta(t, 16.0, plan.TotalDistanceLY, 0.0001)
+	// 10,000 isotopes/LY at Jump Fuel Conservation V: 8 LY x 5,000 per jump
+	assert.Equal(t, 80000, plan.TotalFuel)
+	assert.InDelta(t, 80000000.0, plan.FuelCost, 0.01)
+}
+
+func Test_TransportPlanJFRoutePreferredCynos(t *testing.T) {
+	c, profilesRepo, _, _, _, _, _, solarSysRepo, _ := newTransportController()
+
+	profilesRepo.On("GetByID", mock.Anything, int64(20), int64(42)).Return(
+		&models.TransportProfile{
+			ID: 20, UserID: 42, TransportMethod: "jump_freighter", CargoM3: 320000,
+			JumpShipClass: "jump_freighter", JumpDriveCalibrationLevel: 5,
+			PreferredCynoSystemIDs: []int64{30003504},
+		}, nil,
+	)
+	solarSysRepo.On("GetByIDs", mock.Anything, []int64{30000142, 30004759}).Return(
+		[]*models.SolarSystem{
+			jumpTestSystem(30000142, "Jita", 0.9, 0),
+			jumpTestSystem(30004759, "1DQ1-A", -0.4, 16),
+		}, nil,
+	)
+	solarSysRepo.On("GetByIDs", mock.Anything, []int64{30003504}).Return(
+		[]*models.SolarSystem{jumpTestSystem(30003504, "Niarja", 0.2, 12)}, nil,
+	)
+
+	body, _ := json.Marshal(map[string]any{
+		"originSystemId":      30000142,
+		"destinationSystemId": 30004759,
+		"transportProfileId":  20,
+	})
+
+	userID := int64(42)
+	_, httpErr := c.PlanJFRoute(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/transport/jf-routes/plan", bytes.NewReader(body)),
+		User:    &userID,
+	})
+
+	// Niarja is 12 LY out, beyond a JF's 10 LY range
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	solarSysRepo.AssertNotCalled(t, "GetJumpCandidates", mock.Anything)
+}
+
+func Test_TransportPlanJFRouteInvalidRequest(t *testing.T) {
+	c, _, _, _, _, _, _, _, _ := newTransportController()
+
+	for _, req := range []map[string]any{
+		{"originSystemId": 30000142},
+		{"originSystemId": 30000142, "destinationSystemId": 30004759, "optimize": "speed"},
+	} {
+		body, _ := json.Marshal(req)
+
+		userID := int64(42)
+		_, httpErr := c.PlanJFRoute(&web.HandlerArgs{
+			Request: httptest.NewRequest("POST", "/v1/transport/jf-routes/plan", bytes.NewReader(body)),
+			User:    &userID,
+		})
+
+		assert.NotNil(t, httpErr)
+		assert.Equal(t, 400, httpErr.StatusCode)
+	}
+}
+
 func Test_TransportDeleteJFRoute(t *testing.T) {
 	c, _, jfRoutesRepo, _, _, _, _, _, _ := newTransportController()
 
@@ -661,7 +940,7 @@ func Test_TransportGetJobsError(t *testing.T) {
 }
 
 func Test_TransportCreateJobFreighter(t *testing.T) {
-	c, profilesRepo, _, jobsRepo, _, queueRepo, _, _, esiClient := newTransportController()
+	c, profilesRepo, _, jobsRepo, _, queueRepo, _, _, routeFinder := newTransportController()
 
 	profileID := int64(10)
 	profilesRepo.On("GetByID", mock.Anything, int64(10), int64(42)).Return(
@@ -673,7 +952,7 @@ func Test_TransportCreateJobFreighter(t *testing.T) {
 
 	// ESI returns 10-system route (9 jumps)
 	route := []int32{30000142, 30000143, 30000144, 30000145, 30000146, 30000147, 30000148, 30000149, 30000150, 30002187}
-	esiClient.On("GetRoute", mock.Anything, int64(30000142), int64(30002187), "shortest").Return(route, nil)
+	routeFinder.On("GetRoute", mock.Anything, int64(30000142), int64(30002187), "shortest").Return(route, nil)
 
 	jobsRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.TransportJob")).Return(
 		&models.TransportJob{
@@ -893,13 +1172,140 @@ func Test_TransportUpdateJobStatusDelivered(t *testing.T) {
 	assert.True(t, resp["success"])
 }
 
+// --- Consolidation Tests ---
+
+// consolidationJFJobs returns three planned JF jobs on one profile: jobs 1 and 3
+// share the Jita → Amarr lane and job 2 rides its first leg.
+func consolidationJFJobs() []*models.TransportJob {
+	profileID := int64(20)
+	fullRoute := int64(5)
+	firstLeg := int64(6)
+	return []*models.TransportJob{
+		{ID: 1, UserID: 42, Status: "planned", FulfillmentType: "self_haul", TransportMethod: "jump_freighter",
+			TransportProfileID: &profileID, JFRouteID: &fullRoute, TotalVolumeM3: 100000, TotalCollateral: 1000000000},
+		{ID: 2, UserID: 42, Status: "planned", FulfillmentType: "self_haul", TransportMethod: "jump_freighter",
+			TransportProfileID: &profileID, JFRouteID: &firstLeg, TotalVolumeM3: 50000, TotalCollateral: 500000000},
+		{ID: 3, UserID: 42, Status: "planned", FulfillmentType: "self_haul", TransportMethod: "jump_freighter",
+			TransportProfileID: &profileID, JFRouteID: &fullRoute, TotalVolumeM3: 100000, TotalCollateral: 1000000000},
+		{ID: 4, UserID: 42, Status: "in_transit", FulfillmentType: "self_haul", TransportMethod: "jump_freighter",
+			TransportProfileID: &profileID, JFRouteID: &fullRoute, TotalVolumeM3: 1000, TotalCollateral: 1000},
+	}
+}
+
+func setupConsolidationMocks(profilesRepo *MockTransportProfilesRepo, jfRoutesRepo *MockJFRoutesRepo, jobsRepo *MockTransportJobsRepo, marketRepo *MockTransportMarketPricesRepo) {
+	fuelTypeID := int64(16274)
+	fuelPerLY := 1000.0
+	sellPrice := 1000.0
+
+	jobsRepo.On("GetByUser", mock.Anything, int64(42)).Return(consolidationJFJobs(), nil)
+	profilesRepo.On("GetByID", mock.Anything, int64(20), int64(42)).Return(
+		&models.TransportProfile{
+			ID: 20, UserID: 42, Name: "JF", TransportMethod: "jump_freighter",
+			CargoM3: 300000, CollateralRate: 0, CollateralPriceBasis: "sell",
+			FuelTypeID: &fuelTypeID, FuelPerLY: &fuelPerLY,
+		}, nil,
+	)
+	jfRoutesRepo.On("GetByID", mock.Anything, int64(5), int64(42)).Return(
+		&models.JFRoute{ID: 5, Waypoints: []*models.JFRouteWaypoint{
+			{Sequence: 0, SystemID: 30000142},
+			{Sequence: 1, SystemID: 30001000, DistanceLY: 5},
+			{Sequence: 2, SystemID: 30002187, DistanceLY: 5},
+		}}, nil,
+	)
+	jfRoutesRepo.On("GetByID", mock.Anything, int64(6), int64(42)).Return(
+		&models.JFRoute{ID: 6, Waypoints: []*models.JFRouteWaypoint{
+			{Sequence: 0, SystemID: 30000142},
+			{Sequence: 1, SystemID: 30001000, DistanceLY: 5},
+		}}, nil,
+	)
+	marketRepo.On("GetAllJitaPrices", mock.Anything).Return(
+		map[int64]*models.MarketPrice{16274: {SellPrice: &sellPrice}}, nil,
+	)
+}
+
+func Test_TransportGetConsolidation(t *testing.T) {
+	c, profilesRepo, jfRoutesRepo, jobsRepo, _, _, marketRepo, _, _ := newTransportController()
+	setupConsolidationMocks(profilesRepo, jfRoutesRepo, jobsRepo, marketRepo)
+
+	userID := int64(42)
+	result, httpErr := c.GetConsolidation(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/transport/jobs/consolidation", nil),
+		User:    &userID,
+	})
+
+	assert.Nil(t, httpErr)
+	proposals := result.([]*models.TransportConsolidation)
+	require.Len(t, proposals, 1)
+	p := proposals[0]
+	assert.Equal(t, int64(1), p.HostJobID)
+	assert.Equal(t, []int64{1, 3, 2}, p.JobIDs)
+	assert.Equal(t, 250000.0, p.TotalVolumeM3)
+	assert.Equal(t, 3, p.SeparateTrips)
+	assert.Equal(t, 1, p.ConsolidatedTrips)
+	// 10,000 isotopes per full trip and 5,000 for the first leg, at 1,000 ISK
+	assert.InDelta(t, 25000000, p.SeparateCost, 0.01)
+	assert.InDelta(t, 10000000, p.ConsolidatedCost, 0.01)
+	assert.InDelta(t, 15000000, p.IskSaved, 0.01)
+}
+
+func Test_TransportConsolidateJobs(t *testing.T) {
+	c, profilesRepo, jfRoutesRepo, jobsRepo, _, _, marketRepo, _, _ := newTransportController()
+	setupConsolidationMocks(profilesRepo, jfRoutesRepo, jobsRepo, marketRepo)
+	jobsRepo.On("Consolidate", mock.Anything, int64(42), int64(1), []int64{3, 2}, 250000.0, 2500000000.0, 10000000.0).Return(nil)
+
+	body, _ := json.Marshal(map[string]any{"jobIds": []int64{2, 1, 3}})
+
+	userID := int64(42)
+	result, httpErr := c.ConsolidateJobs(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/transport/jobs/consolidate", bytes.NewReader(body)),
+		User:    &userID,
+	})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, int64(1), result.(*models.TransportConsolidation).HostJobID)
+	jobsRepo.AssertExpectations(t)
+}
+
+func Test_TransportConsolidateJobsRejectsUnplannedSet(t *testing.T) {
+	c, profilesRepo, jfRoutesRepo, jobsRepo, _, _, marketRepo, _, _ := newTransportController()
+	setupConsolidationMocks(profilesRepo, jfRoutesRepo, jobsRepo, marketRepo)
+
+	body, _ := json.Marshal(map[string]any{"jobIds": []int64{1, 4}})
+
+	userID := int64(42)
+	_, httpErr := c.ConsolidateJobs(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/transport/jobs/consolidate", bytes.NewReader(body)),
+		User:    &userID,
+	})
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	jobsRepo.AssertNotCalled(t, "Consolidate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_TransportConsolidateJobsTooFewJobs(t *testing.T) {
+	c, _, _, _, _, _, _, _, _ := newTransportController()
+
+	body, _ := json.Marshal(map[string]any{"jobIds": []int64{1}})
+
+	userID := int64(42)
+	_, httpErr := c.ConsolidateJobs(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/transport/jobs/consolidate", bytes.NewReader(body)),
+		User:    &userID,
+	})
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
 // --- Route Calculation Tests ---
 
 func Test_TransportGetRoute(t *testing.T) {
-	c, _, _, _, _, _, _, _, esiClient := newTransportController()
+	c, _, _, _, _, _, _, _, routeFinder := newTransportController()
 
 	route := []int32{30000142, 30000143, 30000144, 30000145, 30002187}
-	esiClient.On("GetRoute", mock.Anything, int64(30000142), int64(30002187), "secure").Return(route, nil)
+	routeFinder.On("FindRoute", mock.Anything, int64(30000142), int64(30002187), &models.RouteOptions{Flag: "secure"}).
+		Return(&models.GateRoute{Route: route, Jumps: 4, Cost: 4}, nil)
 
 	userID := int64(42)
 	result, httpErr := c.GetRoute(&web.HandlerArgs{
@@ -914,10 +1320,11 @@ func Test_TransportGetRoute(t *testing.T) {
 }
 
 func Test_TransportGetRouteDefaultFlag(t *testing.T) {
-	c, _, _, _, _, _, _, _, esiClient := newTransportController()
+	c, _, _, _, _, _, _, _, routeFinder := newTransportController()
 
 	route := []int32{30000142, 30002187}
-	esiClient.On("GetRoute", mock.Anything, int64(30000142), int64(30002187), "shortest").Return(route, nil)
+	routeFinder.On("FindRoute", mock.Anything, int64(30000142), int64(30002187), &models.RouteOptions{Flag: "shortest"}).
+		Return(&models.GateRoute{Route: route, Jumps: 1, Cost: 1}, nil)
 
 	userID := int64(42)
 	result, httpErr := c.GetRoute(&web.HandlerArgs{
@@ -930,6 +1337,70 @@ func Test_TransportGetRouteDefaultFlag(t *testing.T) {
 	assert.Equal(t, 1, resp["jumps"])
 }
 
+func Test_TransportGetRouteWithConstraints(t *testing.T) {
+	c, _, _, _, _, _, _, _, routeFinder := newTransportController()
+
+	minSecurity := 0.5
+	expectedOpts := &models.RouteOptions{
+		Flag:           "secure",
+		AvoidSystemIDs: []int64{30002813, 30000144},
+		AvoidRegionIDs: []int64{10000012},
+		MinSecurity:    &minSecurity,
+	}
+	route := []int32{30000142, 30000143, 30002187}
+	routeFinder.On("FindRoute", mock.Anything, int64(30000142), int64(30002187), expectedOpts).
+		Return(&models.GateRoute{Route: route, Jumps: 2, Cost: 2}, nil)
+
+	userID := int64(42)
+	result, httpErr := c.GetRoute(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/transport/route?origin=30000142&destination=30002187&flag=secure&avoidSystems=30002813,%2030000144&avoidRegions=10000012&minSecurity=0.5", nil),
+		User:    &userID,
+	})
+
+	assert.Nil(t, httpErr)
+	resp := result.(map[string]any)
+	assert.Equal(t, route, resp["route"])
+	assert.Equal(t, 2, resp["jumps"])
+	assert.Equal(t, 2.0, resp["cost"])
+}
+
+func Test_TransportGetRouteWithJumpCosts(t *testing.T) {
+	c, _, _, _, _, _, _, _, routeFinder := newTransportController()
+
+	// Bands left out keep the flag's cost
+	expectedOpts := &models.RouteOptions{
+		Flag:      "shortest",
+		JumpCosts: &models.RouteJumpCosts{Highsec: 1, Lowsec: 5, Nullsec: 20},
+	}
+	route := []int32{30000142, 30002187}
+	routeFinder.On("FindRoute", mock.Anything, int64(30000142), int64(30002187), expectedOpts).
+		Return(&models.GateRoute{Route: route, Jumps: 1, Cost: 1}, nil)
+
+	userID := int64(42)
+	_, httpErr := c.GetRoute(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/transport/route?origin=30000142&destination=30002187&lowsecCost=5&nullsecCost=20", nil),
+		User:    &userID,
+	})
+
+	assert.Nil(t, httpErr)
+	routeFinder.AssertExpectations(t)
+}
+
+func Test_TransportGetRouteInvalidConstraints(t *testing.T) {
+	c, _, _, _, _, _, _, _, _ := newTransportController()
+
+	userID := int64(42)
+	for _, query := range []string{"avoidSystems=abc", "avoidRegions=1,x", "minSecurity=high", "highsecCost=cheap", "lowsecCost=0", "nullsecCost=-2"} {
+		_, httpErr := c.GetRoute(&web.HandlerArgs{
+			Request: httptest.NewRequest("GET", "/v1/transport/route?origin=30000142&destination=30002187&"+query, nil),
+			User:    &userID,
+		})
+
+		assert.NotNil(t, httpErr, query)
+		assert.Equal(t, 400, httpErr.StatusCode, query)
+	}
+}
+
 func Test_TransportGetRouteMissingParams(t *testing.T) {
 	c, _, _, _, _, _, _, _, _ := newTransportController()
 
@@ -944,9 +1415,10 @@ func Test_TransportGetRouteMissingParams(t *testing.T) {
 }
 
 func Test_TransportGetRouteError(t *testing.T) {
-	c, _, _, _, _, _, _, _, esiClient := newTransportController()
+	c, _, _, _, _, _, _, _, routeFinder := newTransportController()
 
-	esiClient.On("GetRoute", mock.Anything, int64(30000142), int64(30002187), "shortest").Return(nil, errors.New("esi error"))
+	routeFinder.On("FindRoute", mock.Anything, int64(30000142), int64(30002187), &models.RouteOptions{Flag: "shortest"}).
+		Return(nil, errors.New("failed to load route systems"))
 
 	userID := int64(42)
 	_, httpErr := c.GetRoute(&web.HandlerArgs{
@@ -958,6 +1430,30 @@ func Test_TransportGetRouteError(t *testing.T) {
 	assert.Equal(t, 500, httpErr.StatusCode)
 }
 
+func Test_TransportGetRouteNotFound(t *testing.T) {
+	c, _, _, _, _, _, _, _, routeFinder := newTransportController()
+
+	routeFinder.On("FindRoute", mock.Anything, int64(30000142), int64(30002187), &models.RouteOptions{Flag: "shortest"}).
+		Return(nil, &calculator.NoGateRouteError{Origin: 30000142, Destination: 30002187})
+	routeFinder.On("FindRoute", mock.Anything, int64(30000142), int64(1), &models.RouteOptions{Flag: "shortest"}).
+		Return(nil, &calculator.UnknownRouteSystemError{SystemID: 1})
+
+	userID := int64(42)
+	_, httpErr := c.GetRoute(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/transport/route?origin=30000142&destination=30002187", nil),
+		User:    &userID,
+	})
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+
+	_, httpErr = c.GetRoute(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/transport/route?origin=30000142&destination=1", nil),
+		User:    &userID,
+	})
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
 // --- Solar System Search Tests ---
 
 func Test_TransportSearchSystems(t *testing.T) {
@@ -3,9 +3,11 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	"regexp"
 	"strconv"
 	"time"
 
+	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/annymsMthd/industry-tool/internal/web"
 	"github.com/pkg/errors"
@@ -20,18 +22,27 @@ type UserAssetsStatusRepository interface {
 	GetAssetsLastUpdated(ctx context.Context, userID int64) (*time.Time, error)
 }
 
+type UserAssetSettingsRepository interface {
+	GetAssetLocationFlags(ctx context.Context, userID int64) (*models.AssetLocationFlagSettings, error)
+	SetAssetLocationFlags(ctx context.Context, userID int64, flags []string) error
+}
+
 type Users struct {
-	repository       UserRepository
-	assetsStatusRepo UserAssetsStatusRepository
+	repository        UserRepository
+	assetsStatusRepo  UserAssetsStatusRepository
+	assetSettingsRepo UserAssetSettingsRepository
 }
 
-func NewUsers(router Routerer, repository UserRepository, assetsStatusRepo UserAssetsStatusRepository) *Users {
+func NewUsers(router Routerer, repository UserRepository, assetsStatusRepo UserAssetsStatusRepository, assetSettingsRepo UserAssetSettingsRepository) *Users {
 	controller := &Users{
-		repository:       repository,
-		assetsStatusRepo: assetsStatusRepo,
+		repository:        repository,
+		assetsStatusRepo:  assetsStatusRepo,
+		assetSettingsRepo: assetSettingsRepo,
 	}
 
 	router.RegisterRestAPIRoute("/v1/users/asset-status", web.AuthAccessUser, controller.GetAssetStatus, "GET")
+	router.RegisterRestAPIRoute("/v1/users/asset-settings", web.AuthAccessUser, controller.GetAssetSettings, "GET")
+	router.RegisterRestAPIRoute("/v1/users/asset-settings", web.AuthAccessUser, controller.UpdateAssetSettings, "PUT")
 	router.RegisterRestAPIRoute("/v1/users/{id}", web.AuthAccessBackend, controller.GetUser, "GET")
 	router.RegisterRestAPIRoute("/v1/users/", web.AuthAccessBackend, controller.AddUser, "POST")
 
@@ -113,3 +124,77 @@ func (c *Users) GetAssetStatus(args *web.HandlerArgs) (any, *web.HttpError) {
 
 	return response, nil
 }
+
+var locationFlagPattern = regexp.MustCompile(`^[A-Za-z0-9]{1,64}$`)
+
+func (c *Users) GetAssetSettings(args *web.HandlerArgs) (any, *web.HttpError) {
+	settings, err := c.assetSettingsRepo.GetAssetLocationFlags(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to get asset settings"),
+		}
+	}
+
+	return settings, nil
+}
+
+type UpdateAssetSettingsRequest struct {
+	Flags []string `json:"flags"`
+}
+
+// UpdateAssetSettings replaces the user's asset sync allow list. Sending a null flags list
+// resets it to the defaults. Changes apply from the next asset refresh.
+func (c *Users) UpdateAssetSettings(args *web.HandlerArgs) (any, *web.HttpError) {
+	var req UpdateAssetSettingsRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "failed to decode asset settings json"),
+		}
+	}
+
+	var flags []string
+	if req.Flags != nil {
+		if len(req.Flags) == 0 {
+			return nil, &web.HttpError{
+				StatusCode: 400,
+				Error:      errors.New("flags must not be empty"),
+			}
+		}
+
+		seen := map[string]bool{}
+		flags = []string{}
+		for _, flag := range req.Flags {
+			if !locationFlagPattern.MatchString(flag) {
+				return nil, &web.HttpError{
+					StatusCode: 400,
+					Error:      errors.Errorf("invalid location flag %q", flag),
+				}
+			}
+			if seen[flag] {
+				continue
+			}
+			seen[flag] = true
+			flags = append(flags, flag)
+		}
+	}
+
+	err := c.assetSettingsRepo.SetAssetLocationFlags(args.Request.Context(), *args.User, flags)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to update asset settings"),
+		}
+	}
+
+	settings, err := c.assetSettingsRepo.GetAssetLocationFlags(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to get asset settings"),
+		}
+	}
+
+	return settings, nil
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/annymsMthd/industry-tool/internal/web"
 	"github.com/stretchr/testify/assert"
@@ -46,12 +47,29 @@ func (m *MockAssetsStatusRepository) GetAssetsLastUpdated(ctx context.Context, u
 	return args.Get(0).(*time.Time), args.Error(1)
 }
 
+type MockAssetSettingsRepository struct {
+	mock.Mock
+}
+
+func (m *MockAssetSettingsRepository) GetAssetLocationFlags(ctx context.Context, userID int64) (*models.AssetLocationFlagSettings, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AssetLocationFlagSettings), args.Error(1)
+}
+
+func (m *MockAssetSettingsRepository) SetAssetLocationFlags(ctx context.Context, userID int64, flags []string) error {
+	args := m.Called(ctx, userID, flags)
+	return args.Error(0)
+}
+
 func Test_UsersController_GetUser_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockStatusRepo := new(MockAssetsStatusRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo, new(MockAssetSettingsRepository))
 
 	expectedUser := &repositories.User{
 		ID:   42,
@@ -83,7 +101,7 @@ func Test_UsersController_GetUser_MissingID(t *testing.T) {
 	mockStatusRepo := new(MockAssetsStatusRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo, new(MockAssetSettingsRepository))
 
 	req := httptest.NewRequest("GET", "/v1/users/", nil)
 	args := &web.HandlerArgs{
@@ -104,7 +122,7 @@ func Test_UsersController_GetUser_InvalidID(t *testing.T) {
 	mockStatusRepo := new(MockAssetsStatusRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo, new(MockAssetSettingsRepository))
 
 	req := httptest.NewRequest("GET", "/v1/users/invalid", nil)
 	args := &web.HandlerArgs{
@@ -125,7 +143,7 @@ func Test_UsersController_GetUser_RepositoryError(t *testing.T) {
 	mockStatusRepo := new(MockAssetsStatusRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo, new(MockAssetSettingsRepository))
 
 	mockRepo.On("Get", mock.Anything, int64(42)).Return(nil, errors.New("database error"))
 
@@ -149,7 +167,7 @@ func Test_UsersController_AddUser_Success(t *testing.T) {
 	mockStatusRepo := new(MockAssetsStatusRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo, new(MockAssetSettingsRepository))
 
 	user := repositories.User{
 		ID:   42,
@@ -179,7 +197,7 @@ func Test_UsersController_AddUser_InvalidJSON(t *testing.T) {
 	mockStatusRepo := new(MockAssetsStatusRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo, new(MockAssetSettingsRepository))
 
 	req := httptest.NewRequest("POST", "/v1/users/", bytes.NewReader([]byte("invalid json")))
 	args := &web.HandlerArgs{
@@ -198,7 +216,7 @@ func Test_UsersController_AddUser_RepositoryError(t *testing.T) {
 	mockStatusRepo := new(MockAssetsStatusRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo, new(MockAssetSettingsRepository))
 
 	user := repositories.User{
 		ID:   42,
@@ -227,7 +245,7 @@ func Test_UsersController_GetAssetStatus_Success(t *testing.T) {
 	mockStatusRepo := new(MockAssetsStatusRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo, new(MockAssetSettingsRepository))
 
 	userID := int64(42)
 	lastUpdated := time.Date(2026, 2, 17, 12, 0, 0, 0, time.UTC)
@@ -257,7 +275,7 @@ func Test_UsersController_GetAssetStatus_NeverUpdated(t *testing.T) {
 	mockStatusRepo := new(MockAssetsStatusRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo, new(MockAssetSettingsRepository))
 
 	userID := int64(42)
 
@@ -286,7 +304,7 @@ func Test_UsersController_GetAssetStatus_RepositoryError(t *testing.T) {
 	mockStatusRepo := new(MockAssetsStatusRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockStatusRepo, new(MockAssetSettingsRepository))
 
 	userID := int64(42)
 
@@ -306,3 +324,97 @@ func Test_UsersController_GetAssetStatus_RepositoryError(t *testing.T) {
 
 	mockStatusRepo.AssertExpectations(t)
 }
+
+func Test_UsersController_GetAssetSettings_Success(t *testing.T) {
+	mockSettingsRepo := new(MockAssetSettingsRepository)
+	controller := controllers.NewUsers(&MockRouter{}, new(MockUserRepository), new(MockAssetsStatusRepository), mockSettingsRepo)
+
+	userID := int64(42)
+	settings := &models.AssetLocationFlagSettings{Flags: []string{"Hangar", "DroneBay"}, IsDefault: false}
+	mockSettingsRepo.On("GetAssetLocationFlags", mock.Anything, userID).Return(settings, nil)
+
+	req := httptest.NewRequest("GET", "/v1/users/asset-settings", nil)
+	result, httpErr := controller.GetAssetSettings(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, settings, result)
+	mockSettingsRepo.AssertExpectations(t)
+}
+
+func Test_UsersController_UpdateAssetSettings_DedupesFlags(t *testing.T) {
+	mockSettingsRepo := new(MockAssetSettingsRepository)
+	controller := controllers.NewUsers(&MockRouter{}, new(MockUserRepository), new(MockAssetsStatusRepository), mockSettingsRepo)
+
+	userID := int64(42)
+	saved := &models.AssetLocationFlagSettings{Flags: []string{"Hangar", "HiSlot0"}}
+	mockSettingsRepo.On("SetAssetLocationFlags", mock.Anything, userID, []string{"Hangar", "HiSlot0"}).Return(nil)
+	mockSettingsRepo.On("GetAssetLocationFlags", mock.Anything, userID).Return(saved, nil)
+
+	body, _ := json.Marshal(map[string]any{"flags": []string{"Hangar", "HiSlot0", "Hangar"}})
+	req := httptest.NewRequest("PUT", "/v1/users/asset-settings", bytes.NewReader(body))
+	result, httpErr := controller.UpdateAssetSettings(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, saved, result)
+	mockSettingsRepo.AssertExpectations(t)
+}
+
+func Test_UsersController_UpdateAssetSettings_NullResetsToDefaults(t *testing.T) {
+	mockSettingsRepo := new(MockAssetSettingsRepository)
+	controller := controllers.NewUsers(&MockRouter{}, new(MockUserRepository), new(MockAssetsStatusRepository), mockSettingsRepo)
+
+	userID := int64(42)
+	defaults := &models.AssetLocationFlagSettings{Flags: models.DefaultAssetLocationFlags, IsDefault: true}
+	mockSettingsRepo.On("SetAssetLocationFlags", mock.Anything, userID, []string(nil)).Return(nil)
+	mockSettingsRepo.On("GetAssetLocationFlags", mock.Anything, userID).Return(defaults, nil)
+
+	req := httptest.NewRequest("PUT", "/v1/users/asset-settings", bytes.NewReader([]byte(`{"flags":null}`)))
+	result, httpErr := controller.UpdateAssetSettings(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	assert.True(t, result.(*models.AssetLocationFlagSettings).IsDefault)
+	mockSettingsRepo.AssertExpectations(t)
+}
+
+func Test_UsersController_UpdateAssetSettings_InvalidFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid json", `{`},
+		{"empty list", `{"flags":[]}`},
+		{"blank flag", `{"flags":["Hangar",""]}`},
+		{"bad characters", `{"flags":["Hangar; drop"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSettingsRepo := new(MockAssetSettingsRepository)
+			controller := controllers.NewUsers(&MockRouter{}, new(MockUserRepository), new(MockAssetsStatusRepository), mockSettingsRepo)
+
+			userID := int64(42)
+			req := httptest.NewRequest("PUT", "/v1/users/asset-settings", bytes.NewReader([]byte(tt.body)))
+			result, httpErr := controller.UpdateAssetSettings(&web.HandlerArgs{Request: req, User: &userID})
+
+			assert.Nil(t, result)
+			assert.NotNil(t, httpErr)
+			assert.Equal(t, 400, httpErr.StatusCode)
+			mockSettingsRepo.AssertNotCalled(t, "SetAssetLocationFlags")
+		})
+	}
+}
+
+func Test_UsersController_UpdateAssetSettings_RepositoryError(t *testing.T) {
+	mockSettingsRepo := new(MockAssetSettingsRepository)
+	controller := controllers.NewUsers(&MockRouter{}, new(MockUserRepository), new(MockAssetsStatusRepository), mockSettingsRepo)
+
+	userID := int64(42)
+	mockSettingsRepo.On("SetAssetLocationFlags", mock.Anything, userID, []string{"Hangar"}).Return(errors.New("db error"))
+
+	req := httptest.NewRequest("PUT", "/v1/users/asset-settings", bytes.NewReader([]byte(`{"flags":["Hangar"]}`)))
+	result, httpErr := controller.UpdateAssetSettings(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 500, httpErr.StatusCode)
+}
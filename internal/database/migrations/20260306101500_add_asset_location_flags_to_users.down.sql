@@ -0,0 +1,4 @@
+-- Migration: add_asset_location_flags_to_users
+-- Created: Fri Mar  6 10:15:00 AM PST 2026
+
+alter table users drop column asset_location_flags;
@@ -0,0 +1,5 @@
+-- Migration: add_asset_location_flags_to_users
+-- Created: Fri Mar  6 10:15:00 AM PST 2026
+
+-- NULL means the user has not customised the list and the built-in defaults are used.
+alter table users add column asset_location_flags text[];
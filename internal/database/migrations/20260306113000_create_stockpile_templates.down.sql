@@ -0,0 +1,8 @@
+-- Migration: create_stockpile_templates
+-- Created: Fri Mar  6 11:30:00 AM PST 2026
+
+delete from stockpile_markers where template_application_id is not null;
+alter table stockpile_markers drop column template_application_id;
+drop table stockpile_template_applications;
+drop table stockpile_template_items;
+drop table stockpile_templates;
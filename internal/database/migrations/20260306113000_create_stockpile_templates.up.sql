@@ -0,0 +1,50 @@
+-- Migration: create_stockpile_templates
+-- Created: Fri Mar  6 11:30:00 AM PST 2026
+
+create table stockpile_templates (
+	id bigserial primary key,
+	user_id bigint not null references users(id),
+	name varchar(200) not null,
+	notes text,
+	price_source varchar(20),
+	price_percentage numeric(5, 2),
+	created_at timestamp not null default now(),
+	updated_at timestamp not null default now()
+);
+
+create unique index idx_stockpile_templates_user_name on stockpile_templates(user_id, name);
+
+create table stockpile_template_items (
+	template_id bigint not null references stockpile_templates(id) on delete cascade,
+	type_id bigint not null references asset_item_types(type_id),
+	quantity bigint not null check (quantity > 0),
+	primary key (template_id, type_id)
+);
+
+create table stockpile_template_applications (
+	id bigserial primary key,
+	template_id bigint not null references stockpile_templates(id) on delete cascade,
+	user_id bigint not null references users(id),
+	owner_type varchar(20) not null,
+	owner_id bigint not null,
+	location_id bigint not null,
+	container_id bigint,
+	division_number int,
+	multiplier numeric(8, 2) not null default 1 check (multiplier > 0),
+	created_at timestamp not null default now()
+);
+
+-- A location/container scope runs at most one template
+create unique index idx_stockpile_template_applications_scope on stockpile_template_applications(
+	user_id, owner_type, owner_id, location_id,
+	COALESCE(container_id, 0), COALESCE(division_number, 0)
+);
+
+create index idx_stockpile_template_applications_template on stockpile_template_applications(template_id);
+
+-- Markers materialised from a template application; removed with the application
+alter table stockpile_markers
+	add column template_application_id bigint references stockpile_template_applications(id) on delete cascade;
+
+create index idx_stockpile_markers_template_application on stockpile_markers(template_application_id)
+	where template_application_id is not null;
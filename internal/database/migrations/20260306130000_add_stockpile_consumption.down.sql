@@ -0,0 +1,10 @@
+-- Migration: add_stockpile_consumption
+-- Created: Fri Mar  6 01:00:00 PM PST 2026
+
+drop table stockpile_marker_snapshots;
+
+alter table stockpile_markers
+	drop column cover_days,
+	drop column min_desired_quantity,
+	drop column max_desired_quantity,
+	drop column auto_adjust_enabled;
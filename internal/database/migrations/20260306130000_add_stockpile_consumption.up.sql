@@ -0,0 +1,16 @@
+-- Migration: add_stockpile_consumption
+-- Created: Fri Mar  6 01:00:00 PM PST 2026
+
+alter table stockpile_markers
+	add column cover_days int,
+	add column min_desired_quantity bigint,
+	add column max_desired_quantity bigint,
+	add column auto_adjust_enabled boolean not null default false;
+
+create table stockpile_marker_snapshots (
+	marker_id int not null references stockpile_markers(id) on delete cascade,
+	snapshot_date date not null,
+	quantity bigint not null,
+	recorded_at timestamp not null default now(),
+	primary key (marker_id, snapshot_date)
+);
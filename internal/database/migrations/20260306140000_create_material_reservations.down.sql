@@ -0,0 +1,6 @@
+-- Migration: create_material_reservations
+-- Created: Fri Mar  6 02:00:00 PM PST 2026
+
+drop function reserved_material_quantity(bigint, text, bigint, bigint, bigint, int, bigint);
+drop view active_material_reservations;
+drop table material_reservations;
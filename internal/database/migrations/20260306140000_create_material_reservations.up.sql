@@ -0,0 +1,54 @@
+-- Migration: create_material_reservations
+-- Created: Fri Mar  6 02:00:00 PM PST 2026
+
+create table material_reservations (
+	id bigserial primary key,
+	user_id bigint not null references users(id),
+	plan_run_id bigint not null references production_plan_runs(id) on delete cascade,
+	queue_entry_id bigint not null references industry_job_queue(id) on delete cascade,
+	type_id bigint not null,
+	quantity bigint not null,
+	owner_type text not null,
+	owner_id bigint not null,
+	location_id bigint not null,
+	container_id bigint,
+	division_number int,
+	created_at timestamp not null default now()
+);
+
+create index idx_material_reservations_scope on material_reservations (user_id, owner_type, owner_id, location_id, type_id);
+create index idx_material_reservations_run on material_reservations (plan_run_id);
+
+-- A reservation holds while its queue entry is still planned. Installing the job consumes the
+-- materials, and completed or cancelled entries no longer need them.
+create or replace view active_material_reservations as
+select r.*
+from material_reservations r
+join industry_job_queue q on q.id = r.queue_entry_id
+where q.status = 'planned';
+
+-- Reserved quantity of a type in a stockpile-style scope. Without a container the scope covers
+-- the station hangar, or the given corporation division (any division when null).
+create or replace function reserved_material_quantity(
+	p_user_id bigint,
+	p_owner_type text,
+	p_owner_id bigint,
+	p_location_id bigint,
+	p_container_id bigint,
+	p_division_number int,
+	p_type_id bigint
+)
+returns bigint
+language sql
+stable
+as $$
+	select coalesce(sum(r.quantity), 0)::bigint
+	from active_material_reservations r
+	where r.user_id = p_user_id
+		and r.owner_type = p_owner_type
+		and r.owner_id = p_owner_id
+		and r.location_id = p_location_id
+		and r.type_id = p_type_id
+		and coalesce(r.container_id, 0) = coalesce(p_container_id, 0)
+		and (p_division_number is null or r.division_number = p_division_number)
+$$;
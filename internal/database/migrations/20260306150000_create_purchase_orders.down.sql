@@ -0,0 +1,7 @@
+-- Migration: create_purchase_orders
+-- Created: Fri Mar  6 03:00:00 PM PST 2026
+
+drop table purchase_cart_items;
+drop index idx_purchase_order;
+alter table purchase_transactions drop column order_id;
+drop table purchase_orders;
@@ -0,0 +1,29 @@
+-- Migration: create_purchase_orders
+-- Created: Fri Mar  6 03:00:00 PM PST 2026
+
+create table purchase_orders (
+	id bigserial primary key,
+	buyer_user_id bigint not null references users(id),
+	seller_user_id bigint not null references users(id),
+	contract_key text,
+	notes text,
+	created_at timestamp not null default now(),
+	constraint purchase_order_different_users check (buyer_user_id != seller_user_id)
+);
+
+create index idx_purchase_orders_buyer on purchase_orders(buyer_user_id, created_at desc);
+create index idx_purchase_orders_seller on purchase_orders(seller_user_id, created_at desc);
+
+alter table purchase_transactions
+	add column order_id bigint references purchase_orders(id);
+
+create index idx_purchase_order on purchase_transactions(order_id) where order_id is not null;
+
+create table purchase_cart_items (
+	user_id bigint not null references users(id),
+	for_sale_item_id bigint not null references for_sale_items(id) on delete cascade,
+	quantity bigint not null,
+	added_at timestamp not null default now(),
+	primary key (user_id, for_sale_item_id),
+	constraint purchase_cart_positive_quantity check (quantity > 0)
+);
@@ -0,0 +1,6 @@
+-- Migration: add_purchase_disputes
+-- Created: Fri Mar  6 04:00:00 PM PST 2026
+
+alter table purchase_transactions
+	drop column disputed_at,
+	drop column dispute_reason;
@@ -0,0 +1,6 @@
+-- Migration: add_purchase_disputes
+-- Created: Fri Mar  6 04:00:00 PM PST 2026
+
+alter table purchase_transactions
+	add column dispute_reason text,
+	add column disputed_at timestamp;
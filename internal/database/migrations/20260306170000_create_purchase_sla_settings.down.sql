@@ -0,0 +1,8 @@
+-- Migration: create_purchase_sla_settings
+-- Created: Fri Mar  6 05:00:00 PM PST 2026
+
+alter table purchase_transactions
+	drop column expired_from_status,
+	drop column expired_at;
+
+drop table if exists purchase_sla_settings;
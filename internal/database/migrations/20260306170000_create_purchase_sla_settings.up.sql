@@ -0,0 +1,15 @@
+-- Migration: create_purchase_sla_settings
+-- Created: Fri Mar  6 05:00:00 PM PST 2026
+
+create table purchase_sla_settings (
+	user_id bigint primary key references users(id),
+	contract_hours int,
+	accept_days int,
+	updated_at timestamp not null default now(),
+	constraint purchase_sla_positive_contract_hours check (contract_hours is null or contract_hours > 0),
+	constraint purchase_sla_positive_accept_days check (accept_days is null or accept_days > 0)
+);
+
+alter table purchase_transactions
+	add column expired_at timestamp,
+	add column expired_from_status text;
@@ -0,0 +1,5 @@
+-- Migration: create_purchase_offers
+-- Created: Fri Mar  6 06:00:00 PM PST 2026
+
+drop table if exists purchase_offer_rounds;
+drop table if exists purchase_offers;
@@ -0,0 +1,39 @@
+-- Migration: create_purchase_offers
+-- Created: Fri Mar  6 06:00:00 PM PST 2026
+
+create table purchase_offers (
+	id bigserial primary key,
+	for_sale_item_id bigint not null references for_sale_items(id),
+	buy_order_id bigint references buy_orders(id),
+	buyer_user_id bigint not null references users(id),
+	seller_user_id bigint not null references users(id),
+	type_id bigint not null references asset_item_types(type_id),
+	quantity bigint not null,
+	price_per_unit numeric(20,2) not null,
+	status text not null default 'open',
+	awaiting_user_id bigint references users(id),
+	purchase_id bigint references purchase_transactions(id),
+	created_at timestamp not null default now(),
+	updated_at timestamp not null default now(),
+	constraint purchase_offers_positive_quantity check (quantity > 0),
+	constraint purchase_offers_positive_price check (price_per_unit > 0),
+	constraint purchase_offers_status check (status in ('open', 'accepted', 'rejected', 'withdrawn'))
+);
+
+create index idx_purchase_offers_buyer on purchase_offers(buyer_user_id);
+create index idx_purchase_offers_seller on purchase_offers(seller_user_id);
+create index idx_purchase_offers_purchase on purchase_offers(purchase_id);
+
+create table purchase_offer_rounds (
+	id bigserial primary key,
+	offer_id bigint not null references purchase_offers(id) on delete cascade,
+	user_id bigint not null references users(id),
+	action text not null,
+	quantity bigint not null,
+	price_per_unit numeric(20,2) not null,
+	message text,
+	created_at timestamp not null default now(),
+	constraint purchase_offer_rounds_action check (action in ('proposed', 'countered', 'accepted', 'rejected', 'withdrawn'))
+);
+
+create index idx_purchase_offer_rounds_offer on purchase_offer_rounds(offer_id);
@@ -0,0 +1,5 @@
+-- Migration: create_purchase_ratings
+-- Created: Fri Mar  6 07:00:00 PM PST 2026
+
+drop table if exists trade_trust_settings;
+drop table if exists purchase_ratings;
@@ -0,0 +1,23 @@
+-- Migration: create_purchase_ratings
+-- Created: Fri Mar  6 07:00:00 PM PST 2026
+
+create table purchase_ratings (
+	purchase_id bigint not null references purchase_transactions(id) on delete cascade,
+	rater_user_id bigint not null references users(id),
+	rated_user_id bigint not null references users(id),
+	rating smallint not null,
+	note text,
+	created_at timestamp not null default now(),
+	updated_at timestamp not null default now(),
+	primary key (purchase_id, rater_user_id),
+	constraint purchase_ratings_range check (rating between 1 and 5)
+);
+
+create index idx_purchase_ratings_rated on purchase_ratings(rated_user_id);
+
+create table trade_trust_settings (
+	user_id bigint primary key references users(id),
+	min_auto_fulfill_score int,
+	updated_at timestamp not null default now(),
+	constraint trade_trust_settings_score_range check (min_auto_fulfill_score is null or min_auto_fulfill_score between 0 and 100)
+);
@@ -0,0 +1,6 @@
+-- Migration: create_contact_groups
+-- Created: Fri Mar  6 08:00:00 PM PST 2026
+
+drop table if exists contact_group_prices;
+drop table if exists contact_group_members;
+drop table if exists contact_groups;
@@ -0,0 +1,32 @@
+-- Migration: create_contact_groups
+-- Created: Fri Mar  6 08:00:00 PM PST 2026
+
+create table contact_groups (
+	id bigserial primary key,
+	user_id bigint not null references users(id),
+	name varchar(100) not null,
+	permissions jsonb not null default '[]',
+	created_at timestamp not null default now(),
+	updated_at timestamp not null default now(),
+	constraint contact_groups_unique_name unique (user_id, name)
+);
+
+create table contact_group_members (
+	group_id bigint not null references contact_groups(id) on delete cascade,
+	member_user_id bigint not null references users(id),
+	added_at timestamp not null default now(),
+	primary key (group_id, member_user_id)
+);
+
+create index idx_contact_group_members_member on contact_group_members(member_user_id);
+
+create table contact_group_prices (
+	group_id bigint not null references contact_groups(id) on delete cascade,
+	for_sale_item_id bigint not null references for_sale_items(id) on delete cascade,
+	price_per_unit numeric(20,2) not null,
+	updated_at timestamp not null default now(),
+	primary key (group_id, for_sale_item_id),
+	constraint contact_group_prices_positive check (price_per_unit > 0)
+);
+
+create index idx_contact_group_prices_item on contact_group_prices(for_sale_item_id);
@@ -0,0 +1,22 @@
+-- Migration: add_blueprint_listings
+-- Created: Fri Mar  6 09:00:00 PM PST 2026
+
+alter table purchase_transactions
+	drop column if exists blueprint_item_id,
+	drop column if exists blueprint_is_copy,
+	drop column if exists blueprint_runs,
+	drop column if exists blueprint_material_efficiency,
+	drop column if exists blueprint_time_efficiency;
+
+drop index if exists idx_for_sale_unique;
+create unique index idx_for_sale_unique on for_sale_items(
+	user_id, type_id, owner_type, owner_id, location_id,
+	coalesce(container_id, 0), coalesce(division_number, 0)
+) where is_active = true;
+
+alter table for_sale_items
+	drop column if exists blueprint_item_id,
+	drop column if exists blueprint_is_copy,
+	drop column if exists blueprint_runs,
+	drop column if exists blueprint_material_efficiency,
+	drop column if exists blueprint_time_efficiency;
@@ -0,0 +1,24 @@
+-- Migration: add_blueprint_listings
+-- Created: Fri Mar  6 09:00:00 PM PST 2026
+
+alter table for_sale_items
+	add column blueprint_item_id bigint,
+	add column blueprint_is_copy boolean,
+	add column blueprint_runs int,
+	add column blueprint_material_efficiency int,
+	add column blueprint_time_efficiency int;
+
+-- A blueprint listing is for one specific blueprint item, so several copies of the same
+-- blueprint type in one hangar can be listed side by side
+drop index idx_for_sale_unique;
+create unique index idx_for_sale_unique on for_sale_items(
+	user_id, type_id, owner_type, owner_id, location_id,
+	coalesce(container_id, 0), coalesce(division_number, 0), coalesce(blueprint_item_id, 0)
+) where is_active = true;
+
+alter table purchase_transactions
+	add column blueprint_item_id bigint,
+	add column blueprint_is_copy boolean,
+	add column blueprint_runs int,
+	add column blueprint_material_efficiency int,
+	add column blueprint_time_efficiency int;
@@ -0,0 +1,4 @@
+-- Migration: create_job_slot_rental_agreements
+-- Created: Fri Mar  6 10:00:00 PM PST 2026
+
+drop table if exists job_slot_rental_agreements;
@@ -0,0 +1,32 @@
+-- Migration: create_job_slot_rental_agreements
+-- Created: Fri Mar  6 10:00:00 PM PST 2026
+
+-- Job Slot Rental Agreements
+-- An accepted rental that reserves slots on the owner's character for a date range
+create table job_slot_rental_agreements (
+	id bigserial primary key,
+	listing_id bigint not null references job_slot_rental_listings(id),
+	interest_id bigint references job_slot_interest_requests(id) on delete set null,
+	owner_user_id bigint not null references users(id),
+	renter_user_id bigint not null references users(id),
+	character_id bigint not null,
+	activity_type text not null,
+	slots int not null,
+	starts_at timestamptz not null,
+	ends_at timestamptz not null,
+	status text not null default 'active',
+	created_at timestamptz not null default now(),
+	updated_at timestamptz not null default now(),
+	constraint job_slot_agreements_positive_slots check (slots > 0),
+	constraint job_slot_agreements_valid_dates check (ends_at > starts_at),
+	constraint job_slot_agreements_valid_activity check (
+		activity_type in ('manufacturing', 'reaction', 'copying', 'invention', 'me_research', 'te_research')
+	),
+	constraint job_slot_agreements_valid_status check (
+		status in ('active', 'cancelled')
+	)
+);
+
+create index idx_job_slot_agreements_owner on job_slot_rental_agreements(owner_user_id, status);
+create index idx_job_slot_agreements_renter on job_slot_rental_agreements(renter_user_id);
+create index idx_job_slot_agreements_listing on job_slot_rental_agreements(listing_id);
@@ -0,0 +1,5 @@
+-- Migration: create_invoices
+-- Created: Fri Mar  6 11:00:00 PM PST 2026
+
+drop table if exists invoice_payments;
+drop table if exists invoices;
@@ -0,0 +1,45 @@
+-- Migration: create_invoices
+-- Created: Fri Mar  6 11:00:00 PM PST 2026
+
+-- Invoices for job slot rentals and other services between contacts.
+-- Payments are matched from the issuer's wallet journal by reference code.
+create table invoices (
+	id bigserial primary key,
+	issuer_user_id bigint not null references users(id),
+	payer_user_id bigint not null references users(id),
+	agreement_id bigint references job_slot_rental_agreements(id) on delete set null,
+	reference_code text not null,
+	description text not null,
+	amount double precision not null,
+	amount_paid double precision not null default 0,
+	due_at timestamptz not null,
+	status text not null default 'unpaid',
+	paid_at timestamptz,
+	reminded_at timestamptz,
+	created_at timestamptz not null default now(),
+	updated_at timestamptz not null default now(),
+	constraint invoices_positive_amount check (amount > 0),
+	constraint invoices_valid_status check (
+		status in ('unpaid', 'partial', 'paid', 'overdue', 'cancelled')
+	)
+);
+
+create unique index idx_invoices_reference_code on invoices(reference_code);
+create index idx_invoices_issuer on invoices(issuer_user_id);
+create index idx_invoices_payer on invoices(payer_user_id);
+create index idx_invoices_open on invoices(status) where status in ('unpaid', 'partial', 'overdue');
+
+-- One row per wallet journal entry applied to an invoice. The journal ref is
+-- unique so a transfer is never counted twice.
+create table invoice_payments (
+	id bigserial primary key,
+	invoice_id bigint not null references invoices(id) on delete cascade,
+	journal_ref_id bigint not null,
+	character_id bigint not null,
+	amount double precision not null,
+	paid_at timestamptz not null,
+	created_at timestamptz not null default now()
+);
+
+create unique index idx_invoice_payments_journal_ref on invoice_payments(journal_ref_id);
+create index idx_invoice_payments_invoice on invoice_payments(invoice_id);
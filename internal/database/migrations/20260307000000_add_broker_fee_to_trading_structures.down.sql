@@ -0,0 +1,5 @@
+-- Migration: add_broker_fee_to_trading_structures
+-- Created: Sat Mar  7 12:00:00 AM PST 2026
+
+alter table user_trading_structures
+	drop column if exists broker_fee_pct;
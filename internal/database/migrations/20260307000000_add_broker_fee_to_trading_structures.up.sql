@@ -0,0 +1,6 @@
+-- Migration: add_broker_fee_to_trading_structures
+-- Created: Sat Mar  7 12:00:00 AM PST 2026
+
+-- Broker rate set by the structure owner, in percent. Null = not configured.
+alter table user_trading_structures
+	add column broker_fee_pct numeric(6,4);
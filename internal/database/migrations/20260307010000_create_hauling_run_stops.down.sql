@@ -0,0 +1,8 @@
+-- Migration: create_hauling_run_stops
+-- Created: Sat Mar  7 01:00:00 AM PST 2026
+
+alter table hauling_run_items
+	drop column if exists sell_stop_id,
+	drop column if exists buy_stop_id;
+
+drop table if exists hauling_run_stops;
@@ -0,0 +1,24 @@
+-- Migration: create_hauling_run_stops
+-- Created: Sat Mar  7 01:00:00 AM PST 2026
+
+-- Ordered pickup and drop-off stops of a multi-stop hauling run.
+-- Runs without stops keep using from/to on hauling_runs as a single leg.
+create table hauling_run_stops (
+	id bigserial primary key,
+	run_id bigint not null references hauling_runs(id) on delete cascade,
+	seq int not null,
+	region_id bigint not null,
+	system_id bigint,
+	station_id bigint,
+	name text,
+	created_at timestamptz not null default now(),
+	constraint hauling_run_stops_non_negative_seq check (seq >= 0),
+	constraint hauling_run_stops_unique_seq unique (run_id, seq) deferrable initially deferred
+);
+
+create index idx_hauling_run_stops_run_id on hauling_run_stops(run_id);
+
+-- Where each item is bought and sold along the run
+alter table hauling_run_items
+	add column buy_stop_id bigint references hauling_run_stops(id) on delete set null,
+	add column sell_stop_id bigint references hauling_run_stops(id) on delete set null;
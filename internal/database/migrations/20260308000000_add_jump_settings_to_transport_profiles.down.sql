@@ -0,0 +1,7 @@
+-- Migration: add_jump_settings_to_transport_profiles
+-- Created: Sun Mar  8 12:00:00 AM PST 2026
+
+alter table transport_profiles
+	drop column if exists jump_ship_class,
+	drop column if exists jump_drive_calibration_level,
+	drop column if exists preferred_cyno_system_ids;
@@ -0,0 +1,10 @@
+-- Migration: add_jump_settings_to_transport_profiles
+-- Created: Sun Mar  8 12:00:00 AM PST 2026
+
+-- Jump drive settings used to validate JF route legs and plan midpoints.
+-- jump_ship_class picks the base jump range and fuel per LY; preferred cyno
+-- systems, when set, are the only midpoints the planner will use.
+alter table transport_profiles
+	add column jump_ship_class text not null default 'jump_freighter',
+	add column jump_drive_calibration_level int not null default 5,
+	add column preferred_cyno_system_ids bigint[] not null default '{}';
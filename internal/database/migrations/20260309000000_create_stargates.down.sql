@@ -0,0 +1,4 @@
+-- Migration: create_stargates
+-- Created: Mon Mar  9 12:00:00 AM PST 2026
+
+drop table if exists stargates;
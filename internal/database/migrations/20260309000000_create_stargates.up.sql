@@ -0,0 +1,13 @@
+-- Migration: create_stargates
+-- Created: Mon Mar  9 12:00:00 AM PST 2026
+
+-- Stargate connections imported from the SDE (mapStargates.yaml). Each row is
+-- one gate; a connection between two systems has a gate on each side.
+create table stargates (
+	stargate_id bigint primary key not null,
+	solar_system_id bigint not null,
+	destination_stargate_id bigint not null,
+	destination_solar_system_id bigint not null
+);
+
+create index idx_stargates_solar_system on stargates(solar_system_id);
@@ -0,0 +1,9 @@
+-- Migration: add_courier_contract_to_transport_jobs
+-- Created: Tue Mar 10 12:00:00 AM PST 2026
+
+drop index if exists idx_transport_jobs_open_courier;
+
+alter table transport_jobs
+	drop column if exists eve_contract_id,
+	drop column if exists contract_status,
+	drop column if exists contract_updated_at;
@@ -0,0 +1,13 @@
+-- Migration: add_courier_contract_to_transport_jobs
+-- Created: Tue Mar 10 12:00:00 AM PST 2026
+
+-- Courier contract linked to a courier_contract transport job by the contract
+-- sync. contract_status is the ESI status, or 'expired' for an outstanding
+-- contract past its expiry date.
+alter table transport_jobs
+	add column eve_contract_id bigint,
+	add column contract_status text,
+	add column contract_updated_at timestamptz;
+
+create index idx_transport_jobs_open_courier on transport_jobs(user_id)
+	where fulfillment_type = 'courier_contract' and status in ('planned', 'in_transit');
@@ -0,0 +1,7 @@
+-- Migration: add_consolidation_to_transport_jobs
+-- Created: Wed Mar 11 12:00:00 AM PST 2026
+
+drop index if exists idx_transport_jobs_consolidated_into;
+
+alter table transport_jobs
+	drop column if exists consolidated_into_id;
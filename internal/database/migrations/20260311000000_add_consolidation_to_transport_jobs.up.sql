@@ -0,0 +1,10 @@
+-- Migration: add_consolidation_to_transport_jobs
+-- Created: Wed Mar 11 12:00:00 AM PST 2026
+
+-- A consolidated job rides along on the trip of the job it was merged into.
+-- Its status is 'consolidated' until that job moves on, then follows it.
+alter table transport_jobs
+	add column consolidated_into_id bigint references transport_jobs(id) on delete set null;
+
+create index idx_transport_jobs_consolidated_into on transport_jobs(consolidated_into_id)
+	where consolidated_into_id is not null;
@@ -0,0 +1,7 @@
+-- Migration: reset_sde_checksum_for_stargates
+-- Created: Thu Mar 12 12:00:00 AM PST 2026
+
+-- Stargates are only imported when the SDE checksum changes. Forget the stored
+-- checksum so installs that already have the current SDE import them on the
+-- next SDE update run.
+delete from sde_metadata where key = 'checksum';
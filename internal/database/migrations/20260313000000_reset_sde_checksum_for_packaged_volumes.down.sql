@@ -0,0 +1,4 @@
+-- Migration: reset_sde_checksum_for_packaged_volumes
+-- Created: Fri Mar 13 12:00:00 AM PST 2026
+
+-- No-op: the checksum is stored again by the next SDE import.
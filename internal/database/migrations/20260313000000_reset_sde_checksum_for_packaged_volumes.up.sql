@@ -0,0 +1,7 @@
+-- Migration: reset_sde_checksum_for_packaged_volumes
+-- Created: Fri Mar 13 12:00:00 AM PST 2026
+
+-- Ship types get their group's packaged volume during the SDE import, which
+-- only runs when the SDE checksum changes. Forget the stored checksum so
+-- existing installs backfill packaged_volume on the next SDE update run.
+delete from sde_metadata where key = 'checksum';
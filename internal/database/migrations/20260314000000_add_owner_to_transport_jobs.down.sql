@@ -0,0 +1,6 @@
+-- Migration: add_owner_to_transport_jobs
+-- Created: Sat Mar 14 12:00:00 AM PST 2026
+
+alter table transport_jobs
+	drop column if exists owner_type,
+	drop column if exists owner_id;
@@ -0,0 +1,8 @@
+-- Migration: add_owner_to_transport_jobs
+-- Created: Sat Mar 14 12:00:00 AM PST 2026
+
+-- Character or corporation whose stock a job moves, set by the stockpile
+-- rebalance so in-transit quantities only count toward that owner.
+alter table transport_jobs
+	add column owner_type text,
+	add column owner_id bigint;
@@ -0,0 +1,6 @@
+-- Migration: numeric_invoice_amounts
+-- Created: Sun Mar 15 12:00:00 AM PST 2026
+
+alter table invoices alter column amount type double precision;
+alter table invoices alter column amount_paid type double precision;
+alter table invoice_payments alter column amount type double precision;
@@ -0,0 +1,8 @@
+-- Migration: numeric_invoice_amounts
+-- Created: Sun Mar 15 12:00:00 AM PST 2026
+
+-- ISK amounts are stored as numeric like every other price, so payment sums
+-- and the paid/partial thresholds are exact.
+alter table invoices alter column amount type numeric(20,2);
+alter table invoices alter column amount_paid type numeric(20,2);
+alter table invoice_payments alter column amount type numeric(20,2);
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -62,6 +63,55 @@ type Station struct {
 	IsNPC         bool
 }
 
+// Stargate is one side of a gate connection between two solar systems.
+type Stargate struct {
+	ID                       int64
+	SolarSystemID            int64
+	DestinationStargateID    int64
+	DestinationSolarSystemID int64
+}
+
+// RouteSystem is a solar system node of the stargate graph.
+type RouteSystem struct {
+	ID       int64
+	RegionID int64
+	Security float64
+}
+
+// SystemJump is a gate connection from one solar system to another.
+type SystemJump struct {
+	FromSystemID int64
+	ToSystemID   int64
+}
+
+// RouteOptions controls how a gate route is found. Flag is "shortest",
+// "secure" or "insecure", as ESI's route endpoint takes it.
+type RouteOptions struct {
+	Flag           string
+	AvoidSystemIDs []int64
+	AvoidRegionIDs []int64
+	// Systems below MinSecurity are never entered; the origin is exempt
+	MinSecurity *float64
+	// Per-jump cost by the security of the system jumped into. Nil uses the
+	// costs implied by Flag.
+	JumpCosts *RouteJumpCosts
+}
+
+// RouteJumpCosts weighs a jump by the security band of the system entered.
+type RouteJumpCosts struct {
+	Highsec float64 `json:"highsec"`
+	Lowsec  float64 `json:"lowsec"`
+	Nullsec float64 `json:"nullsec"`
+}
+
+// GateRoute is a route through stargates. Route includes the origin and
+// destination, matching ESI's route response.
+type GateRoute struct {
+	Route []int32 `json:"route"`
+	Jumps int     `json:"jumps"`
+	Cost  float64 `json:"cost"`
+}
+
 type Corporation struct {
 	ID           int64
 	Name         string
@@ -89,6 +139,11 @@ type StockpileMarker struct {
 	PlanID                    *int64   `json:"planId"`
 	AutoProductionParallelism int      `json:"autoProductionParallelism"`
 	AutoProductionEnabled     bool     `json:"autoProductionEnabled"`
+	TemplateApplicationID     *int64   `json:"templateApplicationId"`
+	CoverDays                 *int     `json:"coverDays"`
+	MinDesiredQuantity        *int64   `json:"minDesiredQuantity"`
+	MaxDesiredQuantity        *int64   `json:"maxDesiredQuantity"`
+	AutoAdjustEnabled         *bool    `json:"autoAdjustEnabled"`
 }
 
 type MarketPrice struct {
@@ -125,6 +180,30 @@ type ContactRule struct {
 	UpdatedAt   time.Time `json:"updatedAt"`
 }
 
+type ContactGroup struct {
+	ID          int64                 `json:"id"`
+	UserID      int64                 `json:"userId"`
+	Name        string                `json:"name"`
+	Permissions []string              `json:"permissions"`
+	Members     []*ContactGroupMember `json:"members"`
+	CreatedAt   time.Time             `json:"createdAt"`
+	UpdatedAt   time.Time             `json:"updatedAt"`
+}
+
+type ContactGroupMember struct {
+	UserID  int64     `json:"userId"`
+	Name    string    `json:"name"`
+	AddedAt time.Time `json:"addedAt"`
+}
+
+type ContactGroupPrice struct {
+	GroupID       int64   `json:"groupId"`
+	ForSaleItemID int64   `json:"forSaleItemId"`
+	TypeName      string  `json:"typeName"`
+	ListPrice     float64 `json:"listPrice"`
+	PricePerUnit  float64 `json:"pricePerUnit"`
+}
+
 type ContactPermission struct {
 	ID              int64  `json:"id"`
 	ContactID       int64  `json:"contactId"`
@@ -153,6 +232,25 @@ type ForSaleItem struct {
 	IsActive            bool      `json:"isActive"`
 	CreatedAt           time.Time `json:"createdAt"`
 	UpdatedAt           time.Time `json:"updatedAt"`
+
+	// Seller's trade record, set when browsing
+	Trust *TradeTrustStats `json:"trust,omitempty"`
+
+	// Listed price when a contact group price replaces PricePerUnit for the browsing buyer
+	ListPricePerUnit *float64 `json:"listPricePerUnit,omitempty"`
+
+	// Set when the listing is for one specific blueprint item
+	Blueprint *ListingBlueprint `json:"blueprint,omitempty"`
+}
+
+// ListingBlueprint identifies the blueprint item a listing is for and snapshots its attributes,
+// so copies with different runs or research levels are told apart. Runs is -1 for originals.
+type ListingBlueprint struct {
+	ItemID             int64 `json:"itemId"`
+	IsCopy             bool  `json:"isCopy"`
+	Runs               int   `json:"runs"`
+	MaterialEfficiency int   `json:"materialEfficiency"`
+	TimeEfficiency     int   `json:"timeEfficiency"`
 }
 
 type AutoSellContainer struct {
@@ -193,8 +291,125 @@ type PurchaseTransaction struct {
 	ContractKey       *string   `json:"contractKey,omitempty"`
 	TransactionNotes  *string   `json:"transactionNotes"`
 	BuyOrderID        *int64    `json:"buyOrderId,omitempty"`
+	OrderID           *int64    `json:"orderId,omitempty"`
+	DisputeReason     *string   `json:"disputeReason,omitempty"`
 	IsAutoFulfilled   bool      `json:"isAutoFulfilled"`
 	PurchasedAt       time.Time `json:"purchasedAt"`
+
+	// Blueprint attributes of the listing at the time of purchase
+	Blueprint *ListingBlueprint `json:"blueprint,omitempty"`
+}
+
+// PurchaseCartItem is a for-sale item a buyer has added to their cart, enriched with the
+// current state of the listing.
+type PurchaseCartItem struct {
+	ForSaleItemID     int64     `json:"forSaleItemId"`
+	Quantity          int64     `json:"quantity"`
+	AddedAt           time.Time `json:"addedAt"`
+	SellerUserID      int64     `json:"sellerUserId"`
+	SellerName        string    `json:"sellerName"`
+	TypeID            int64     `json:"typeId"`
+	TypeName          string    `json:"typeName"`
+	LocationID        int64     `json:"locationId"`
+	LocationName      string    `json:"locationName"`
+	PricePerUnit      float64   `json:"pricePerUnit"`
+	QuantityAvailable int64     `json:"quantityAvailable"`
+	IsActive          bool      `json:"isActive"`
+}
+
+// PurchaseOrder groups the purchases a buyer checked out from one seller so they are delivered
+// with a single contract.
+type PurchaseOrder struct {
+	ID           int64                  `json:"id"`
+	BuyerUserID  int64                  `json:"buyerUserId"`
+	BuyerName    string                 `json:"buyerName"`
+	SellerUserID int64                  `json:"sellerUserId"`
+	SellerName   string                 `json:"sellerName"`
+	ContractKey  *string                `json:"contractKey,omitempty"`
+	Notes        *string                `json:"notes"`
+	Status       string                 `json:"status"`
+	TotalPrice   float64                `json:"totalPrice"`
+	CreatedAt    time.Time              `json:"createdAt"`
+	Purchases    []*PurchaseTransaction `json:"purchases"`
+}
+
+// PurchaseSlaSettings are a seller's deadlines for their purchases. A nil timer never expires.
+type PurchaseSlaSettings struct {
+	UserID        int64 `json:"userId"`
+	ContractHours *int  `json:"contractHours"`
+	AcceptDays    *int  `json:"acceptDays"`
+}
+
+// TradeTrustStats summarize how a user has traded across all of their purchases and sales.
+// TrustScore is nil until they have finished at least one trade.
+type TradeTrustStats struct {
+	UserID              int64    `json:"userId"`
+	CompletedCount      int64    `json:"completedCount"`
+	CancelledCount      int64    `json:"cancelledCount"`
+	ExpiredCount        int64    `json:"expiredCount"`
+	DisputedCount       int64    `json:"disputedCount"`
+	CancellationRate    float64  `json:"cancellationRate"`
+	MedianContractHours *float64 `json:"medianContractHours,omitempty"`
+	TotalVolume         float64  `json:"totalVolume"`
+	RatingCount         int64    `json:"ratingCount"`
+	AverageRating       *float64 `json:"averageRating,omitempty"`
+	TrustScore          *int     `json:"trustScore,omitempty"`
+}
+
+// PurchaseRating is one party's rating of the other on a completed purchase.
+type PurchaseRating struct {
+	PurchaseID  int64     `json:"purchaseId"`
+	RaterUserID int64     `json:"raterUserId"`
+	RaterName   string    `json:"raterName"`
+	RatedUserID int64     `json:"ratedUserId"`
+	Rating      int       `json:"rating"`
+	Note        *string   `json:"note,omitempty"`
+	TypeName    string    `json:"typeName"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// TradeTrustSettings hold a user's minimum trust score for counterparties that auto-fulfill
+// matches them with. A nil minimum accepts everyone.
+type TradeTrustSettings struct {
+	UserID              int64 `json:"userId"`
+	MinAutoFulfillScore *int  `json:"minAutoFulfillScore"`
+}
+
+// PurchaseOffer is a price and quantity negotiation on a for-sale listing, opened by a buyer on
+// the listing or by a seller against a buy order. Quantity and PricePerUnit are the current
+// terms; AwaitingUserID is the side that must accept, reject or counter them.
+type PurchaseOffer struct {
+	ID             int64                 `json:"id"`
+	ForSaleItemID  int64                 `json:"forSaleItemId"`
+	BuyOrderID     *int64                `json:"buyOrderId,omitempty"`
+	BuyerUserID    int64                 `json:"buyerUserId"`
+	BuyerName      string                `json:"buyerName"`
+	SellerUserID   int64                 `json:"sellerUserId"`
+	SellerName     string                `json:"sellerName"`
+	TypeID         int64                 `json:"typeId"`
+	TypeName       string                `json:"typeName"`
+	LocationID     int64                 `json:"locationId"`
+	LocationName   string                `json:"locationName"`
+	Quantity       int64                 `json:"quantity"`
+	PricePerUnit   float64               `json:"pricePerUnit"`
+	ListPrice      float64               `json:"listPrice"`
+	Status         string                `json:"status"`
+	AwaitingUserID *int64                `json:"awaitingUserId,omitempty"`
+	PurchaseID     *int64                `json:"purchaseId,omitempty"`
+	CreatedAt      time.Time             `json:"createdAt"`
+	UpdatedAt      time.Time             `json:"updatedAt"`
+	Rounds         []*PurchaseOfferRound `json:"rounds"`
+}
+
+// PurchaseOfferRound is one step in an offer's history.
+type PurchaseOfferRound struct {
+	ID           int64     `json:"id"`
+	UserID       int64     `json:"userId"`
+	Action       string    `json:"action"`
+	Quantity     int64     `json:"quantity"`
+	PricePerUnit float64   `json:"pricePerUnit"`
+	Message      *string   `json:"message,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
 }
 
 type BuyOrder struct {
@@ -212,6 +427,9 @@ type BuyOrder struct {
 	IsActive        bool      `json:"isActive"`
 	CreatedAt       time.Time `json:"createdAt"`
 	UpdatedAt       time.Time `json:"updatedAt"`
+
+	// Buyer's trade record, set when viewing demand
+	Trust *TradeTrustStats `json:"trust,omitempty"`
 }
 
 type AutoBuyConfig struct {
@@ -302,6 +520,19 @@ type SalesMetrics struct {
 	UniqueBuyers      int64          `json:"uniqueBuyers"`
 	TimeSeriesData    []TimeSeriesData `json:"timeSeriesData"`
 	TopItems          []ItemSalesData  `json:"topItems"`
+	Aging             *PurchaseAging   `json:"aging"`
+}
+
+// PurchaseAging describes how long a seller's purchases wait at each step and how many are
+// still open or were expired by the SLA timers.
+type PurchaseAging struct {
+	AvgHoursToContract        float64 `json:"avgHoursToContract"`
+	AvgHoursToAccept          float64 `json:"avgHoursToAccept"`
+	OpenPending               int64   `json:"openPending"`
+	OpenContractCreated       int64   `json:"openContractCreated"`
+	OldestOpenHours           float64 `json:"oldestOpenHours"`
+	ExpiredAwaitingContract   int64   `json:"expiredAwaitingContract"`
+	ExpiredAwaitingAcceptance int64   `json:"expiredAwaitingAcceptance"`
 }
 
 type TimeSeriesData struct {
@@ -853,6 +1084,8 @@ type IndustryJobQueueEntry struct {
 	TransportVolumeM3     float64 `json:"transportVolumeM3,omitempty"`
 	TransportJumps        int     `json:"transportJumps,omitempty"`
 	TransportItemsSummary string  `json:"transportItemsSummary,omitempty"`
+	// Materials earmarked for this entry; written together with the entry on create
+	Reservations []*MaterialReservation `json:"reservations,omitempty"`
 }
 
 type ManufacturingCalcResult struct {
@@ -869,6 +1102,8 @@ type ManufacturingCalcResult struct {
 	JobCost         float64                 `json:"jobCost"`
 	TotalCost       float64                 `json:"totalCost"`
 	OutputValue     float64                 `json:"outputValue"`
+	SalesTax        float64                 `json:"salesTax"`
+	BrokerFee       float64                 `json:"brokerFee"`
 	Profit          float64                 `json:"profit"`
 	Margin          float64                 `json:"margin"`
 	Materials       []*ManufacturingMaterial `json:"materials"`
@@ -1026,8 +1261,30 @@ type ProductionPlanRun struct {
 	PlanName    string                   `json:"planName,omitempty"`
 	ProductName string                   `json:"productName,omitempty"`
 	Status      string                   `json:"status"`
-	Jobs        []*IndustryJobQueueEntry `json:"jobs,omitempty"`
-	JobSummary  *PlanRunJobSummary       `json:"jobSummary,omitempty"`
+	Jobs         []*IndustryJobQueueEntry `json:"jobs,omitempty"`
+	JobSummary   *PlanRunJobSummary       `json:"jobSummary,omitempty"`
+	Reservations []*MaterialReservation   `json:"reservations,omitempty"`
+}
+
+// MaterialReservation earmarks stock at a location for a plan run's queue entry. It is active
+// while the entry is planned, and auto-sell, auto-fulfill and stockpile deficits treat the
+// reserved quantity as unavailable.
+type MaterialReservation struct {
+	ID             int64     `json:"id"`
+	UserID         int64     `json:"userId"`
+	PlanRunID      int64     `json:"planRunId"`
+	QueueEntryID   int64     `json:"queueEntryId"`
+	TypeID         int64     `json:"typeId"`
+	Quantity       int64     `json:"quantity"`
+	OwnerType      string    `json:"ownerType"`
+	OwnerID        int64     `json:"ownerId"`
+	LocationID     int64     `json:"locationId"`
+	ContainerID    *int64    `json:"containerId"`
+	DivisionNumber *int      `json:"divisionNumber"`
+	CreatedAt      time.Time `json:"createdAt"`
+	// Enriched
+	TypeName string `json:"typeName,omitempty"`
+	Active   bool   `json:"active"`
 }
 
 type PlanRunJobSummary struct {
@@ -1092,9 +1349,14 @@ type TransportProfile struct {
 	RoutePreference       string    `json:"routePreference"`
 	IsDefault             bool      `json:"isDefault"`
 	CreatedAt             time.Time `json:"createdAt"`
+	// Jump drive settings, used by JF route validation and planning
+	JumpShipClass             string  `json:"jumpShipClass"`
+	JumpDriveCalibrationLevel int     `json:"jumpDriveCalibrationLevel"`
+	PreferredCynoSystemIDs    []int64 `json:"preferredCynoSystemIds"`
 	// Enriched
-	CharacterName string `json:"characterName,omitempty"`
-	FuelTypeName  string `json:"fuelTypeName,omitempty"`
+	CharacterName            string   `json:"characterName,omitempty"`
+	FuelTypeName             string   `json:"fuelTypeName,omitempty"`
+	PreferredCynoSystemNames []string `json:"preferredCynoSystemNames,omitempty"`
 }
 
 type JFRoute struct {
@@ -1118,7 +1380,22 @@ type JFRouteWaypoint struct {
 	SystemID   int64   `json:"systemId"`
 	DistanceLY float64 `json:"distanceLy"`
 	// Enriched
-	SystemName string `json:"systemName,omitempty"`
+	SystemName string   `json:"systemName,omitempty"`
+	Security   *float64 `json:"security,omitempty"`
+}
+
+// JFRoutePlan is a proposed jump route between two systems, with midpoints
+// chosen by the planner. Waypoints carry the leg distance into each stop.
+type JFRoutePlan struct {
+	OriginSystemID      int64              `json:"originSystemId"`
+	DestinationSystemID int64              `json:"destinationSystemId"`
+	Optimize            string             `json:"optimize"`
+	JumpRangeLY         float64            `json:"jumpRangeLy"`
+	Jumps               int                `json:"jumps"`
+	TotalDistanceLY     float64            `json:"totalDistanceLy"`
+	TotalFuel           int                `json:"totalFuel"`
+	FuelCost            float64            `json:"fuelCost"`
+	Waypoints           []*JFRouteWaypoint `json:"waypoints"`
 }
 
 type TransportJob struct {
@@ -1146,7 +1423,17 @@ type TransportJob struct {
 	CreatedAt            time.Time           `json:"createdAt"`
 	UpdatedAt            time.Time           `json:"updatedAt"`
 	Items                []*TransportJobItem `json:"items"`
+	// Courier contract linked by the contract sync (courier_contract jobs only)
+	EveContractID     *int64     `json:"eveContractId"`
+	ContractStatus    *string    `json:"contractStatus"`
+	ContractUpdatedAt *time.Time `json:"contractUpdatedAt"`
+	// Job whose trip this one was merged into (status 'consolidated')
+	ConsolidatedIntoID *int64 `json:"consolidatedIntoId"`
+	// Character or corporation whose stock the job moves (stockpile rebalance jobs)
+	OwnerType *string `json:"ownerType"`
+	OwnerID   *int64  `json:"ownerId"`
 	// Enriched
+	ContractKey            string `json:"contractKey,omitempty"`
 	OriginStationName      string `json:"originStationName,omitempty"`
 	DestinationStationName string `json:"destinationStationName,omitempty"`
 	OriginSystemName       string `json:"originSystemName,omitempty"`
@@ -1155,6 +1442,24 @@ type TransportJob struct {
 	JFRouteName            string `json:"jfRouteName,omitempty"`
 }
 
+// TransportConsolidation is a proposal to carry open transport jobs on one
+// trip: a host job plus jobs on the same lane or on a stretch of its route.
+type TransportConsolidation struct {
+	HostJobID             int64   `json:"hostJobId"`
+	JobIDs                []int64 `json:"jobIds"`
+	TransportMethod       string  `json:"transportMethod"`
+	TransportProfileID    *int64  `json:"transportProfileId"`
+	OriginSystemName      string  `json:"originSystemName"`
+	DestinationSystemName string  `json:"destinationSystemName"`
+	TotalVolumeM3         float64 `json:"totalVolumeM3"`
+	TotalCollateral       float64 `json:"totalCollateral"`
+	SeparateTrips         int     `json:"separateTrips"`
+	ConsolidatedTrips     int     `json:"consolidatedTrips"`
+	SeparateCost          float64 `json:"separateCost"`
+	ConsolidatedCost      float64 `json:"consolidatedCost"`
+	IskSaved              float64 `json:"iskSaved"`
+}
+
 type TransportJobItem struct {
 	ID             int64   `json:"id"`
 	TransportJobID int64   `json:"transportJobId"`
@@ -1268,6 +1573,7 @@ type ActivitySlotInfo struct {
 	SlotsReserved  int    `json:"slotsReserved"`
 	SlotsAvailable int    `json:"slotsAvailable"`
 	SlotsListed    int    `json:"slotsListed"`
+	SlotsRented    int    `json:"slotsRented"`
 }
 
 type CreateJobSlotListingRequest struct {
@@ -1300,6 +1606,80 @@ type UpdateInterestStatusRequest struct {
 	Status string `json:"status"`
 }
 
+// JobSlotRentalAgreement reserves slots on the owner's character for a renter
+// between StartsAt and EndsAt. The usage fields count ESI jobs installed by the
+// character on that activity during the agreement that are not tied to the
+// owner's own job queue.
+type JobSlotRentalAgreement struct {
+	ID            int64     `json:"id"`
+	ListingID     int64     `json:"listingId"`
+	InterestID    *int64    `json:"interestId"`
+	OwnerUserID   int64     `json:"ownerUserId"`
+	OwnerName     string    `json:"ownerName"`
+	RenterUserID  int64     `json:"renterUserId"`
+	RenterName    string    `json:"renterName"`
+	CharacterID   int64     `json:"characterId"`
+	CharacterName string    `json:"characterName"`
+	ActivityType  string    `json:"activityType"`
+	Slots         int       `json:"slots"`
+	StartsAt      time.Time `json:"startsAt"`
+	EndsAt        time.Time `json:"endsAt"`
+	Status        string    `json:"status"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+	JobsRun       int       `json:"jobsRun"`
+	JobsActive    int       `json:"jobsActive"`
+	RunsTotal     int       `json:"runsTotal"`
+}
+
+type CreateJobSlotAgreementRequest struct {
+	InterestID int64      `json:"interestId"`
+	Slots      int        `json:"slots"`
+	StartsAt   *time.Time `json:"startsAt"`
+	EndsAt     *time.Time `json:"endsAt"`
+}
+
+// Invoice bills a contact for a job slot rental or another service. The payer
+// puts ReferenceCode in the reason of an ISK transfer to any of the issuer's
+// characters, and the invoices runner matches it from the wallet journal.
+type Invoice struct {
+	ID            int64             `json:"id"`
+	IssuerUserID  int64             `json:"issuerUserId"`
+	IssuerName    string            `json:"issuerName"`
+	PayerUserID   int64             `json:"payerUserId"`
+	PayerName     string            `json:"payerName"`
+	AgreementID   *int64            `json:"agreementId"`
+	ReferenceCode string            `json:"referenceCode"`
+	Description   string            `json:"description"`
+	Amount        float64           `json:"amount"`
+	AmountPaid    float64           `json:"amountPaid"`
+	DueAt         time.Time         `json:"dueAt"`
+	Status        string            `json:"status"`
+	PaidAt        *time.Time        `json:"paidAt"`
+	RemindedAt    *time.Time        `json:"remindedAt"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	UpdatedAt     time.Time         `json:"updatedAt"`
+	Payments      []*InvoicePayment `json:"payments"`
+}
+
+// InvoicePayment is one wallet journal entry applied to an invoice
+type InvoicePayment struct {
+	ID           int64     `json:"id"`
+	InvoiceID    int64     `json:"invoiceId"`
+	JournalRefID int64     `json:"journalRefId"`
+	CharacterID  int64     `json:"characterId"`
+	Amount       float64   `json:"amount"`
+	PaidAt       time.Time `json:"paidAt"`
+}
+
+type CreateInvoiceRequest struct {
+	AgreementID *int64     `json:"agreementId"`
+	PayerUserID int64      `json:"payerUserId"`
+	Amount      float64    `json:"amount"`
+	Description string     `json:"description"`
+	DueAt       *time.Time `json:"dueAt"`
+}
+
 // HaulingRun represents a hauling trip in EVE Online
 type HaulingRun struct {
 	ID               int64             `json:"id"`
@@ -1321,6 +1701,37 @@ type HaulingRun struct {
 	CreatedAt        string            `json:"createdAt"`
 	UpdatedAt        string            `json:"updatedAt"`
 	Items            []*HaulingRunItem `json:"items,omitempty"`
+	Stops            []*HaulingRunStop `json:"stops,omitempty"` // empty = single leg From → To
+}
+
+// HaulingRunStop is an ordered pickup or drop-off point of a multi-stop run
+type HaulingRunStop struct {
+	ID        int64   `json:"id"`
+	RunID     int64   `json:"runId"`
+	Seq       int     `json:"seq"` // 0-based order along the route
+	RegionID  int64   `json:"regionId"`
+	SystemID  *int64  `json:"systemId,omitempty"`
+	StationID *int64  `json:"stationId,omitempty"`
+	Name      *string `json:"name,omitempty"`
+	CreatedAt string  `json:"createdAt"`
+}
+
+// HaulingRunLeg is the trip between two consecutive stops of a run.
+// Items are aboard from their buy stop until their sell stop; profit and
+// P&L count on the leg that delivers them.
+type HaulingRunLeg struct {
+	Seq              int             `json:"seq"` // leg from stop Seq to stop Seq+1
+	FromStop         *HaulingRunStop `json:"fromStop"`
+	ToStop           *HaulingRunStop `json:"toStop"`
+	LoadM3           float64         `json:"loadM3"` // planned cargo aboard on this leg
+	CapacityM3       *float64        `json:"capacityM3,omitempty"`
+	OverCapacity     bool            `json:"overCapacity"`
+	ItemsAboard      int             `json:"itemsAboard"`
+	ItemsDelivered   int             `json:"itemsDelivered"`
+	PlannedProfitISK float64         `json:"plannedProfitIsk"` // items delivered at ToStop
+	RevenueISK       float64         `json:"revenueIsk"`       // recorded P&L of items delivered at ToStop
+	CostISK          float64         `json:"costIsk"`
+	NetProfitISK     float64         `json:"netProfitIsk"`
 }
 
 // HaulingRunItem is an item within a hauling run
@@ -1339,6 +1750,8 @@ type HaulingRunItem struct {
 	SellOrderID        *int64   `json:"sellOrderId,omitempty"`
 	QtySold            int64    `json:"qtySold"`
 	ActualSellPriceISK *float64 `json:"actualSellPriceIsk,omitempty"`
+	BuyStopID          *int64   `json:"buyStopId,omitempty"`  // default: the run's first stop
+	SellStopID         *int64   `json:"sellStopId,omitempty"` // default: the run's last stop
 	CreatedAt        string   `json:"createdAt"`
 	UpdatedAt        string   `json:"updatedAt"`
 	// Computed
@@ -1375,6 +1788,50 @@ type HaulingArbitrageRow struct {
 	DaysToSell      *float64 `json:"daysToSell,omitempty"`
 	Indicator       string   `json:"indicator"` // "gap", "markup", "thin"
 	UpdatedAt       string   `json:"updatedAt"`
+	Jumps           *int     `json:"jumps,omitempty"`           // gate jumps source -> destination
+	MinSecurity     *float64 `json:"minSecurity,omitempty"`     // lowest system security on the route
+	IskPerM3PerJump *float64 `json:"iskPerM3PerJump,omitempty"` // per-unit profit / m³ / jump
+	IskPerHour      *float64 `json:"iskPerHour,omitempty"`      // one full cargo over a round trip
+	// Destination avg daily volume, used to cap units by days to sell
+	DestAvgDailyVolume *float64 `json:"destAvgDailyVolume,omitempty"`
+}
+
+// TradeFees is a character's effective market fee rates in percent, from their
+// Accounting and Broker Relations skills, standings where given, and the broker
+// rate of a player structure when selling there.
+type TradeFees struct {
+	CharacterID          int64   `json:"characterId"`
+	CharacterName        string  `json:"characterName"`
+	AccountingLevel      int     `json:"accountingLevel"`
+	BrokerRelationsLevel int     `json:"brokerRelationsLevel"`
+	SalesTaxPct          float64 `json:"salesTaxPct"`
+	BrokerFeePct         float64 `json:"brokerFeePct"`
+}
+
+// HaulingFeesProfile holds trade cost rates in percent.
+type HaulingFeesProfile struct {
+	SalesTaxPct      float64 `json:"salesTaxPct"`
+	BuyBrokerFeePct  float64 `json:"buyBrokerFeePct"`  // 0 when buying from sell orders
+	SellBrokerFeePct float64 `json:"sellBrokerFeePct"` // 0 when selling into buy orders
+}
+
+// HaulingCargoOptimizeRequest asks the optimizer to fill a run from scanner rows.
+type HaulingCargoOptimizeRequest struct {
+	Rows            []*HaulingArbitrageRow `json:"rows"`
+	VolumeM3        *float64               `json:"volumeM3,omitempty"` // default: run's MaxVolumeM3
+	CapitalISK      float64                `json:"capitalIsk"`         // 0 = no limit
+	MaxUnitsPerType int64                  `json:"maxUnitsPerType"`    // 0 = no limit
+	MaxDaysToSell   float64                `json:"maxDaysToSell"`      // 0 = no cap
+	Fees            *HaulingFeesProfile    `json:"fees,omitempty"`     // default: the user's best character fees
+}
+
+// HaulingCargoPlan is the optimizer's result: the items it added to the run.
+type HaulingCargoPlan struct {
+	Items             []*HaulingRunItem `json:"items"`
+	TotalVolumeM3     float64           `json:"totalVolumeM3"`
+	TotalCostISK      float64           `json:"totalCostIsk"`
+	ExpectedProfitISK float64           `json:"expectedProfitIsk"`
+	RemainingVolumeM3 float64           `json:"remainingVolumeM3"`
 }
 
 // HaulingRunPnlEntry is a P&L record for a single item type within a hauling run.
@@ -1396,7 +1853,9 @@ type HaulingRunPnlEntry struct {
 type HaulingRunPnlSummary struct {
 	TotalRevenueISK float64 `json:"totalRevenueIsk"`
 	TotalCostISK    float64 `json:"totalCostIsk"`
-	NetProfitISK    float64 `json:"netProfitIsk"`
+	SalesTaxISK     float64 `json:"salesTaxIsk"`
+	BrokerFeesISK   float64 `json:"brokerFeesIsk"`
+	NetProfitISK    float64 `json:"netProfitIsk"` // after sales tax and broker fees
 	MarginPct       float64 `json:"marginPct"`    // netProfit/totalRevenue * 100
 	ItemsSold       int64   `json:"itemsSold"`
 	ItemsPending    int64   `json:"itemsPending"` // items with qty_sold < qty_acquired
@@ -1415,6 +1874,18 @@ type HaulingRouteAnalytics struct {
 	WorstRunProfitISK float64 `json:"worstRunProfitIsk"`
 }
 
+// HaulingLegAnalytics contains aggregated stats per leg (stop region -> next stop region)
+// across completed runs. Runs without stops count as one leg.
+type HaulingLegAnalytics struct {
+	FromRegionID   int64   `json:"fromRegionId"`
+	ToRegionID     int64   `json:"toRegionId"`
+	TotalLegs      int64   `json:"totalLegs"`
+	TotalRuns      int64   `json:"totalRuns"`
+	TotalProfitISK float64 `json:"totalProfitIsk"`
+	AvgProfitISK   float64 `json:"avgProfitIsk"`
+	AvgMarginPct   float64 `json:"avgMarginPct"`
+}
+
 // HaulingItemAnalytics contains aggregated per-item-type stats across all completed runs.
 type HaulingItemAnalytics struct {
 	TypeID         int64   `json:"typeId"`
@@ -1455,14 +1926,406 @@ type TradingStation struct {
 
 // UserTradingStructure is a player-owned structure with a market that the user has configured
 type UserTradingStructure struct {
-	ID            int64   `json:"id"`
-	UserID        int64   `json:"userId"`
-	StructureID   int64   `json:"structureId"`
-	Name          string  `json:"name"`
-	SystemID      int64   `json:"systemId"`
-	RegionID      int64   `json:"regionId"`
-	CharacterID   int64   `json:"characterId"`
-	AccessOK      bool    `json:"accessOk"`
-	LastScannedAt *string `json:"lastScannedAt,omitempty"`
-	CreatedAt     string  `json:"createdAt"`
+	ID            int64    `json:"id"`
+	UserID        int64    `json:"userId"`
+	StructureID   int64    `json:"structureId"`
+	Name          string   `json:"name"`
+	SystemID      int64    `json:"systemId"`
+	RegionID      int64    `json:"regionId"`
+	CharacterID   int64    `json:"characterId"`
+	AccessOK      bool     `json:"accessOk"`
+	BrokerFeePct  *float64 `json:"brokerFeePct,omitempty"` // owner-set broker rate; nil = NPC-style rate
+	LastScannedAt *string  `json:"lastScannedAt,omitempty"`
+	CreatedAt     string   `json:"createdAt"`
+}
+
+// --- Asset Search Models ---
+
+// AssetSearchFilters narrows a global asset search across every character and corporation of a user.
+// Nil/empty fields are not applied.
+type AssetSearchFilters struct {
+	TypeName      string
+	GroupID       *int64
+	CategoryID    *int64
+	MarketGroupID *int64
+	OwnerType     string
+	OwnerID       *int64
+	RegionID      *int64
+	SolarSystemID *int64
+	StationID     *int64
+	ContainerID   *int64
+	Blueprint     string // "bpo", "bpc" or "" for no blueprint filter
+	MinValue      *float64
+	SortBy        string // "value", "quantity" or "name"
+	SortDesc      bool
+	Limit         int
+	Offset        int
+}
+
+// AssetSearchItem is a single asset stack matched by a search, with its resolved location.
+type AssetSearchItem struct {
+	ItemID          int64    `json:"itemId"`
+	TypeID          int64    `json:"typeId"`
+	TypeName        string   `json:"typeName"`
+	GroupName       *string  `json:"groupName"`
+	CategoryName    *string  `json:"categoryName"`
+	Quantity        int64    `json:"quantity"`
+	Volume          float64  `json:"volume"`
+	IsBlueprintCopy bool     `json:"isBlueprintCopy"`
+	OwnerType       string   `json:"ownerType"`
+	OwnerID         int64    `json:"ownerId"`
+	OwnerName       string   `json:"ownerName"`
+	LocationFlag    string   `json:"locationFlag"`
+	ContainerID     *int64   `json:"containerId"`
+	ContainerName   *string  `json:"containerName"`
+	StationID       *int64   `json:"stationId"`
+	StationName     *string  `json:"stationName"`
+	SolarSystemID   *int64   `json:"solarSystemId"`
+	SolarSystemName *string  `json:"solarSystemName"`
+	RegionID        *int64   `json:"regionId"`
+	RegionName      *string  `json:"regionName"`
+	UnitPrice       *float64 `json:"unitPrice"`
+	TotalValue      float64  `json:"totalValue"`
+}
+
+// AssetSearchResult is one page of asset search results plus totals over all matches.
+type AssetSearchResult struct {
+	Items         []*AssetSearchItem `json:"items"`
+	Total         int                `json:"total"`
+	TotalQuantity int64              `json:"totalQuantity"`
+	TotalValue    float64            `json:"totalValue"`
+	TotalVolume   float64            `json:"totalVolume"`
+	Limit         int                `json:"limit"`
+	Offset        int                `json:"offset"`
+}
+
+// --- Blueprint Library Models ---
+
+// BlueprintLibraryItem is a single owned blueprint (BPO or BPC) with its product and location resolved.
+type BlueprintLibraryItem struct {
+	ItemID             int64   `json:"itemId"`
+	BlueprintTypeID    int64   `json:"blueprintTypeId"`
+	BlueprintName      string  `json:"blueprintName"`
+	ProductTypeID      *int64  `json:"productTypeId"`
+	ProductName        *string `json:"productName"`
+	Activity           *string `json:"activity"`
+	OwnerType          string  `json:"ownerType"`
+	OwnerID            int64   `json:"ownerId"`
+	OwnerName          string  `json:"ownerName"`
+	LocationID         int64   `json:"locationId"`
+	LocationName       string  `json:"locationName"`
+	LocationFlag       string  `json:"locationFlag"`
+	Quantity           int     `json:"quantity"`
+	IsCopy             bool    `json:"isCopy"`
+	MaterialEfficiency int     `json:"materialEfficiency"`
+	TimeEfficiency     int     `json:"timeEfficiency"`
+	Runs               int     `json:"runs"`
+	UsedByPlan         bool    `json:"usedByPlan"`
+	IsUnresearched     bool    `json:"isUnresearched"`
+	IsFullyResearched  bool    `json:"isFullyResearched"`
+	IsDuplicate        bool    `json:"isDuplicate"`
+}
+
+// BlueprintLibraryGroup groups every blueprint that produces the same product, with research flags and valuation.
+type BlueprintLibraryGroup struct {
+	BlueprintTypeID    int64                   `json:"blueprintTypeId"`
+	BlueprintName      string                  `json:"blueprintName"`
+	ProductTypeID      *int64                  `json:"productTypeId"`
+	ProductName        *string                 `json:"productName"`
+	Activity           *string                 `json:"activity"`
+	BPOCount           int                     `json:"bpoCount"`
+	BPCCount           int                     `json:"bpcCount"`
+	TotalBPCRuns       int                     `json:"totalBpcRuns"`
+	BestME             int                     `json:"bestMe"`
+	BestTE             int                     `json:"bestTe"`
+	HasUnresearchedBPO bool                    `json:"hasUnresearchedBpo"`
+	HasDuplicateBPOs   bool                    `json:"hasDuplicateBpos"`
+	UsedByPlan         bool                    `json:"usedByPlan"`
+	HasUnusedBPCs      bool                    `json:"hasUnusedBpcs"`
+	ProfitPerRun       *float64                `json:"profitPerRun"`
+	ProfitPerDay       *float64                `json:"profitPerDay"`
+	MaxMEProfitPerRun  *float64                `json:"maxMeProfitPerRun"`
+	ResearchGainPerRun *float64                `json:"researchGainPerRun"`
+	Blueprints         []*BlueprintLibraryItem `json:"blueprints"`
+}
+
+// BlueprintLibrarySummary holds library-wide counts.
+type BlueprintLibrarySummary struct {
+	TotalBPOs        int `json:"totalBpos"`
+	TotalBPCs        int `json:"totalBpcs"`
+	UnresearchedBPOs int `json:"unresearchedBpos"`
+	DuplicateBPOs    int `json:"duplicateBpos"`
+	UnusedBPCGroups  int `json:"unusedBpcGroups"`
+	ProductsCovered  int `json:"productsCovered"`
+}
+
+// BlueprintLibrary is the full blueprint library response.
+type BlueprintLibrary struct {
+	Summary BlueprintLibrarySummary  `json:"summary"`
+	Groups  []*BlueprintLibraryGroup `json:"groups"`
+}
+
+// --- Asset Location Flag Models ---
+
+// DefaultAssetLocationFlags are the ESI location flags kept on asset sync when a user has not
+// configured their own list. Besides hangars, deliveries and corp divisions it includes the
+// fitting slots and holds of assembled ships so their contents are stored under the ship.
+var DefaultAssetLocationFlags = []string{
+	"Hangar",
+	"Unlocked",
+	"Cargo",
+	"CapsuleerDeliveries",
+	"CorpDeliveries",
+	"Deliveries",
+	"ExpeditionHold",
+	"HangarAll",
+	"InfrastructureHangar",
+	"Locked",
+	"MoonMaterialBay",
+	"SpecializedAsteroidHold",
+	"AssetSafety",
+	"CorporationGoalDeliveries",
+	"CorpSAG1",
+	"CorpSAG2",
+	"CorpSAG3",
+	"CorpSAG4",
+	"CorpSAG5",
+	"CorpSAG6",
+	"CorpSAG7",
+	"OfficeFolder",
+	"HiSlot0", "HiSlot1", "HiSlot2", "HiSlot3", "HiSlot4", "HiSlot5", "HiSlot6", "HiSlot7",
+	"MedSlot0", "MedSlot1", "MedSlot2", "MedSlot3", "MedSlot4", "MedSlot5", "MedSlot6", "MedSlot7",
+	"LoSlot0", "LoSlot1", "LoSlot2", "LoSlot3", "LoSlot4", "LoSlot5", "LoSlot6", "LoSlot7",
+	"RigSlot0", "RigSlot1", "RigSlot2",
+	"SubSystemSlot0", "SubSystemSlot1", "SubSystemSlot2", "SubSystemSlot3",
+	"DroneBay",
+	"FighterBay",
+	"FighterTube0", "FighterTube1", "FighterTube2", "FighterTube3", "FighterTube4",
+	"FuelBay",
+	"ShipHangar",
+	"FleetHangar",
+	"FrigateEscapeBay",
+	"SpecializedAmmoHold",
+	"SpecializedCommandCenterHold",
+	"SpecializedGasHold",
+	"SpecializedIndustrialShipHold",
+	"SpecializedLargeShipHold",
+	"SpecializedMaterialBay",
+	"SpecializedMediumShipHold",
+	"SpecializedMineralHold",
+	"SpecializedOreHold",
+	"SpecializedPlanetaryCommoditiesHold",
+	"SpecializedSalvageHold",
+	"SpecializedShipHold",
+	"SpecializedSmallShipHold",
+	"SubSystemBay",
+}
+
+// AssetLocationFlagSettings is a user's asset sync allow list.
+// IsDefault is true when the user has not customised it and DefaultAssetLocationFlags is used.
+type AssetLocationFlagSettings struct {
+	Flags     []string `json:"flags"`
+	IsDefault bool     `json:"isDefault"`
+}
+
+// ShipSlotForLocationFlag classifies the location flag of an item inside an assembled ship.
+// Fitted modules return "high", "medium", "low", "rig" or "subsystem"; everything else returns
+// the hold it sits in ("drone_bay", "fighter_bay", "fuel_bay", "cargo" or "other").
+func ShipSlotForLocationFlag(flag string) string {
+	switch {
+	case strings.HasPrefix(flag, "HiSlot"):
+		return "high"
+	case strings.HasPrefix(flag, "MedSlot"):
+		return "medium"
+	case strings.HasPrefix(flag, "LoSlot"):
+		return "low"
+	case strings.HasPrefix(flag, "RigSlot"):
+		return "rig"
+	case strings.HasPrefix(flag, "SubSystemSlot"):
+		return "subsystem"
+	case flag == "DroneBay":
+		return "drone_bay"
+	case flag == "FighterBay", strings.HasPrefix(flag, "FighterTube"):
+		return "fighter_bay"
+	case flag == "FuelBay":
+		return "fuel_bay"
+	case flag == "Cargo":
+		return "cargo"
+	default:
+		return "other"
+	}
+}
+
+// IsShipFittingSlot reports whether a ship slot returned by ShipSlotForLocationFlag is a fitting.
+func IsShipFittingSlot(slot string) bool {
+	switch slot {
+	case "high", "medium", "low", "rig", "subsystem":
+		return true
+	}
+	return false
+}
+
+// --- Stockpile Template Models ---
+
+// StockpileTemplateItem is one type and base quantity of a stockpile template.
+type StockpileTemplateItem struct {
+	TypeID   int64  `json:"typeId"`
+	TypeName string `json:"typeName"`
+	Quantity int64  `json:"quantity"`
+}
+
+// StockpileTemplateApplication places a template at a location or container scope.
+// Desired quantities at the scope are the template quantities times Multiplier, rounded up.
+type StockpileTemplateApplication struct {
+	ID             int64     `json:"id"`
+	TemplateID     int64     `json:"templateId"`
+	UserID         int64     `json:"userId"`
+	OwnerType      string    `json:"ownerType"`
+	OwnerID        int64     `json:"ownerId"`
+	LocationID     int64     `json:"locationId"`
+	ContainerID    *int64    `json:"containerId"`
+	DivisionNumber *int      `json:"divisionNumber"`
+	Multiplier     float64   `json:"multiplier"`
+	CreatedAt      time.Time `json:"createdAt"`
+	// SkippedTypeIDs are template types left to a marker the application does not own
+	SkippedTypeIDs []int64 `json:"skippedTypeIds,omitempty"`
+}
+
+// StockpileTemplate is a named set of type quantities (e.g. a doctrine staging kit) that
+// can be applied to many locations. Its stockpile markers follow every edit of the template.
+type StockpileTemplate struct {
+	ID              int64                           `json:"id"`
+	UserID          int64                           `json:"userId"`
+	Name            string                          `json:"name"`
+	Notes           *string                         `json:"notes"`
+	PriceSource     *string                         `json:"priceSource"`
+	PricePercentage *float64                        `json:"pricePercentage"`
+	Items           []*StockpileTemplateItem        `json:"items"`
+	Applications    []*StockpileTemplateApplication `json:"applications"`
+	CreatedAt       time.Time                       `json:"createdAt"`
+	UpdatedAt       time.Time                       `json:"updatedAt"`
+}
+
+// StockpileTemplateDeficitItem is the stock of one template type at one application scope.
+type StockpileTemplateDeficitItem struct {
+	TypeID          int64   `json:"typeId"`
+	TypeName        string  `json:"typeName"`
+	DesiredQuantity int64   `json:"desiredQuantity"`
+	CurrentQuantity int64   `json:"currentQuantity"`
+	Delta           int64   `json:"delta"`
+	DeficitValue    float64 `json:"deficitValue"`
+}
+
+// StockpileTemplateApplicationDeficits reports how complete one template application is.
+type StockpileTemplateApplicationDeficits struct {
+	Application       *StockpileTemplateApplication   `json:"application"`
+	LocationName      string                          `json:"locationName"`
+	OwnerName         string                          `json:"ownerName"`
+	Items             []*StockpileTemplateDeficitItem `json:"items"`
+	MissingTypes      int                             `json:"missingTypes"`
+	TotalDeficitValue float64                         `json:"totalDeficitValue"`
+	IsComplete        bool                            `json:"isComplete"`
+}
+
+// --- Stockpile Rebalance Models ---
+
+// StockpilePosition is what one owner holds and wants of one type at one station.
+// Held counts stackable items in hangars, corp divisions and containers at the station.
+type StockpilePosition struct {
+	OwnerType         string  `json:"ownerType"`
+	OwnerID           int64   `json:"ownerId"`
+	OwnerName         string  `json:"ownerName"`
+	StationID         int64   `json:"stationId"`
+	StationName       string  `json:"stationName"`
+	SolarSystemID     int64   `json:"solarSystemId"`
+	RegionID          int64   `json:"regionId"`
+	TypeID            int64   `json:"typeId"`
+	TypeName          string  `json:"typeName"`
+	UnitVolume        float64 `json:"unitVolume"`
+	HeldQuantity      int64   `json:"heldQuantity"`
+	DesiredQuantity   int64   `json:"desiredQuantity"`
+	InTransitQuantity int64   `json:"inTransitQuantity"`
+}
+
+// StockpileRebalanceItem is one type moved by a rebalance proposal.
+type StockpileRebalanceItem struct {
+	TypeID         int64   `json:"typeId"`
+	TypeName       string  `json:"typeName"`
+	Quantity       int64   `json:"quantity"`
+	VolumeM3       float64 `json:"volumeM3"`
+	EstimatedValue float64 `json:"estimatedValue"`
+	LocalBuyCost   float64 `json:"localBuyCost"`
+}
+
+// StockpileRebalanceOption is the cost of hauling a proposal with one transport profile.
+type StockpileRebalanceOption struct {
+	ProfileID       int64    `json:"profileId"`
+	ProfileName     string   `json:"profileName"`
+	TransportMethod string   `json:"transportMethod"`
+	RoutePreference string   `json:"routePreference"`
+	Jumps           int      `json:"jumps"`
+	DistanceLY      *float64 `json:"distanceLy"`
+	JFRouteID       *int64   `json:"jfRouteId"`
+	Trips           int      `json:"trips"`
+	Collateral      float64  `json:"collateral"`
+	Cost            float64  `json:"cost"`
+}
+
+// StockpileRebalanceProposal moves one owner's surplus from one station to a station short of stock.
+// Recommendation is "transport" when the cheapest profile beats buying the items at the destination,
+// otherwise "buy_locally".
+type StockpileRebalanceProposal struct {
+	OwnerType              string                      `json:"ownerType"`
+	OwnerID                int64                       `json:"ownerId"`
+	OwnerName              string                      `json:"ownerName"`
+	OriginStationID        int64                       `json:"originStationId"`
+	OriginStationName      string                      `json:"originStationName"`
+	OriginSystemID         int64                       `json:"originSystemId"`
+	DestinationStationID   int64                       `json:"destinationStationId"`
+	DestinationStationName string                      `json:"destinationStationName"`
+	DestinationSystemID    int64                       `json:"destinationSystemId"`
+	DestinationRegionID    int64                       `json:"destinationRegionId"`
+	Items                  []*StockpileRebalanceItem   `json:"items"`
+	TotalVolumeM3          float64                     `json:"totalVolumeM3"`
+	TotalValue             float64                     `json:"totalValue"`
+	LocalBuyCost           float64                     `json:"localBuyCost"`
+	Options                []*StockpileRebalanceOption `json:"options"`
+	BestOption             *StockpileRebalanceOption   `json:"bestOption"`
+	Savings                float64                     `json:"savings"`
+	Recommendation         string                      `json:"recommendation"`
+}
+
+// --- Stockpile Consumption Models ---
+
+// StockpileConsumption is the burn rate of one stockpile marker over the consumption window.
+// AssetConsumed sums the day-over-day drops of the held quantity snapshots, so restocks are not
+// netted against usage. ProductionConsumed is the ME-adjusted material quantity of manufacturing
+// and reaction jobs the marker's owner started at its station. BurnRate is the larger of the two daily rates.
+type StockpileConsumption struct {
+	MarkerID                 int64      `json:"markerId"`
+	TypeID                   int64      `json:"typeId"`
+	TypeName                 string     `json:"typeName"`
+	OwnerType                string     `json:"ownerType"`
+	OwnerID                  int64      `json:"ownerId"`
+	OwnerName                string     `json:"ownerName"`
+	LocationID               int64      `json:"locationId"`
+	LocationName             string     `json:"locationName"`
+	ContainerID              *int64     `json:"containerId"`
+	DivisionNumber           *int       `json:"divisionNumber"`
+	DesiredQuantity          int64      `json:"desiredQuantity"`
+	HeldQuantity             int64      `json:"heldQuantity"`
+	CoverDays                *int       `json:"coverDays"`
+	MinDesiredQuantity       *int64     `json:"minDesiredQuantity"`
+	MaxDesiredQuantity       *int64     `json:"maxDesiredQuantity"`
+	AutoAdjustEnabled        bool       `json:"autoAdjustEnabled"`
+	TemplateManaged          bool       `json:"templateManaged"`
+	HistoryDays              int        `json:"historyDays"`
+	AssetConsumed            int64      `json:"assetConsumed"`
+	ProductionConsumed       int64      `json:"productionConsumed"`
+	AssetBurnRate            float64    `json:"assetBurnRate"`
+	ProductionBurnRate       float64    `json:"productionBurnRate"`
+	BurnRate                 float64    `json:"burnRate"`
+	DaysRemaining            *float64   `json:"daysRemaining"`
+	ProjectedRunOutAt        *time.Time `json:"projectedRunOutAt"`
+	SuggestedDesiredQuantity *int64     `json:"suggestedDesiredQuantity"`
 }
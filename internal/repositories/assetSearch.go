@@ -0,0 +1,300 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+// assetSearchBaseQuery flattens character and corporation assets for a user into one row per
+// item stack, resolving the top-level location (station/structure/system) by walking up the
+// item_id -> location_id chain, and the immediate parent container when there is one.
+const assetSearchBaseQuery = `
+WITH RECURSIVE owned AS (
+	SELECT
+		'character' AS owner_type,
+		ca.character_id AS owner_id,
+		ca.item_id,
+		ca.type_id,
+		ca.quantity,
+		ca.is_singleton,
+		ca.is_blueprint_copy,
+		ca.location_id,
+		ca.location_type,
+		ca.location_flag
+	FROM character_assets ca
+	WHERE ca.user_id = $1
+
+	UNION ALL
+
+	SELECT
+		'corporation' AS owner_type,
+		co.corporation_id AS owner_id,
+		co.item_id,
+		co.type_id,
+		co.quantity,
+		co.is_singleton,
+		co.is_blueprint_copy,
+		co.location_id,
+		co.location_type,
+		co.location_flag
+	FROM corporation_assets co
+	WHERE co.user_id = $1
+),
+chain AS (
+	SELECT o.owner_type, o.owner_id, o.item_id, o.location_id AS root_location_id, 0 AS depth
+	FROM owned o
+
+	UNION ALL
+
+	SELECT c.owner_type, c.owner_id, c.item_id, p.location_id, c.depth + 1
+	FROM chain c
+	INNER JOIN owned p
+		ON p.owner_type = c.owner_type
+		AND p.owner_id = c.owner_id
+		AND p.item_id = c.root_location_id
+	WHERE c.depth < 10
+),
+roots AS (
+	SELECT DISTINCT ON (owner_type, owner_id, item_id)
+		owner_type, owner_id, item_id, root_location_id
+	FROM chain
+	ORDER BY owner_type, owner_id, item_id, depth DESC
+),
+matches AS (
+	SELECT
+		o.item_id,
+		o.type_id,
+		t.type_name,
+		t.group_id,
+		g.category_id,
+		t.market_group_id,
+		g.name AS group_name,
+		cat.name AS category_name,
+		o.quantity,
+		o.quantity * CASE WHEN o.is_singleton THEN t.volume ELSE COALESCE(t.packaged_volume, t.volume) END AS volume,
+		o.is_blueprint_copy,
+		o.owner_type,
+		o.owner_id,
+		resolve_owner_name(o.owner_type, o.owner_id) AS owner_name,
+		o.location_flag,
+		parent.item_id AS container_id,
+		COALESCE(charNames.name, corpNames.name) AS container_name,
+		stations.station_id,
+		stations.name AS station_name,
+		systems.solar_system_id,
+		systems.name AS solar_system_name,
+		regions.region_id,
+		regions.name AS region_name,
+		market.sell_price AS unit_price,
+		CASE
+			WHEN o.is_blueprint_copy THEN 0
+			ELSE o.quantity * COALESCE(market.sell_price, 0)
+		END AS total_value
+	FROM owned o
+	INNER JOIN roots r
+		ON r.owner_type = o.owner_type
+		AND r.owner_id = o.owner_id
+		AND r.item_id = o.item_id
+	INNER JOIN asset_item_types t ON t.type_id = o.type_id
+	LEFT JOIN sde_groups g ON g.group_id = t.group_id
+	LEFT JOIN sde_categories cat ON cat.category_id = g.category_id
+	LEFT JOIN owned parent
+		ON o.location_type = 'item'
+		AND parent.owner_type = o.owner_type
+		AND parent.owner_id = o.owner_id
+		AND parent.item_id = o.location_id
+		AND parent.location_flag <> 'OfficeFolder'
+	LEFT JOIN character_asset_location_names charNames
+		ON parent.owner_type = 'character'
+		AND charNames.character_id = parent.owner_id
+		AND charNames.user_id = $1
+		AND charNames.item_id = parent.item_id
+	LEFT JOIN corporation_asset_location_names corpNames
+		ON parent.owner_type = 'corporation'
+		AND corpNames.corporation_id = parent.owner_id
+		AND corpNames.user_id = $1
+		AND corpNames.item_id = parent.item_id
+	LEFT JOIN stations ON stations.station_id = r.root_location_id
+	LEFT JOIN solar_systems systems ON systems.solar_system_id = COALESCE(stations.solar_system_id, r.root_location_id)
+	LEFT JOIN constellations ON constellations.constellation_id = systems.constellation_id
+	LEFT JOIN regions ON regions.region_id = constellations.region_id
+	LEFT JOIN market_prices market ON market.type_id = o.type_id AND market.region_id = 10000002
+	WHERE o.location_flag <> 'OfficeFolder'
+)`
+
+var assetSearchSortColumns = map[string]string{
+	"value":    "m.total_value",
+	"quantity": "m.quantity",
+	"name":     "m.type_name",
+}
+
+// SearchAssets searches every character and corporation asset of a user, returning one page
+// of matching item stacks sorted as requested plus totals across all matches.
+func (r *Assets) SearchAssets(ctx context.Context, user int64, filters *models.AssetSearchFilters) (*models.AssetSearchResult, error) {
+	args := []interface{}{user}
+	where := []string{}
+
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filters.TypeName != "" {
+		where = append(where, "m.type_name ILIKE "+addArg("%"+filters.TypeName+"%"))
+	}
+	if filters.GroupID != nil {
+		where = append(where, "m.group_id = "+addArg(*filters.GroupID))
+	}
+	if filters.CategoryID != nil {
+		where = append(where, "m.category_id = "+addArg(*filters.CategoryID))
+	}
+	if filters.MarketGroupID != nil {
+		where = append(where, `m.market_group_id IN (
+		WITH RECURSIVE descendants AS (
+			SELECT market_group_id FROM sde_market_groups WHERE market_group_id = `+addArg(*filters.MarketGroupID)+`
+			UNION ALL
+			SELECT child.market_group_id
+			FROM sde_market_groups child
+			INNER JOIN descendants d ON child.parent_group_id = d.market_group_id
+		)
+		SELECT market_group_id FROM descendants
+	)`)
+	}
+	if filters.OwnerType != "" {
+		where = append(where, "m.owner_type = "+addArg(filters.OwnerType))
+	}
+	if filters.OwnerID != nil {
+		where = append(where, "m.owner_id = "+addArg(*filters.OwnerID))
+	}
+	if filters.RegionID != nil {
+		where = append(where, "m.region_id = "+addArg(*filters.RegionID))
+	}
+	if filters.SolarSystemID != nil {
+		where = append(where, "m.solar_system_id = "+addArg(*filters.SolarSystemID))
+	}
+	if filters.StationID != nil {
+		where = append(where, "m.station_id = "+addArg(*filters.StationID))
+	}
+	if filters.ContainerID != nil {
+		where = append(where, "m.container_id = "+addArg(*filters.ContainerID))
+	}
+	switch filters.Blueprint {
+	case "bpc":
+		where = append(where, "m.is_blueprint_copy = true")
+	case "bpo":
+		where = append(where, "m.is_blueprint_copy = false AND m.category_id = 9")
+	}
+	if filters.MinValue != nil {
+		where = append(where, "m.total_value >= "+addArg(*filters.MinValue))
+	}
+
+	whereClause := ""
+	if len(where) > 0 {
+		whereClause = "\nWHERE\n\t" + strings.Join(where, "\n\tAND ")
+	}
+
+	result := &models.AssetSearchResult{
+		Items:  []*models.AssetSearchItem{},
+		Limit:  filters.Limit,
+		Offset: filters.Offset,
+	}
+
+	totalsQuery := assetSearchBaseQuery + `
+SELECT
+	COUNT(*),
+	COALESCE(SUM(m.quantity), 0),
+	COALESCE(SUM(m.total_value), 0),
+	COALESCE(SUM(m.volume), 0)
+FROM matches m` + whereClause
+
+	err := r.db.QueryRowContext(ctx, totalsQuery, args...).Scan(
+		&result.Total,
+		&result.TotalQuantity,
+		&result.TotalValue,
+		&result.TotalVolume,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query asset search totals")
+	}
+
+	sortColumn, ok := assetSearchSortColumns[filters.SortBy]
+	if !ok {
+		sortColumn = assetSearchSortColumns["value"]
+	}
+	sortDirection := "ASC"
+	if filters.SortDesc {
+		sortDirection = "DESC"
+	}
+
+	pageQuery := assetSearchBaseQuery + `
+SELECT
+	m.item_id,
+	m.type_id,
+	m.type_name,
+	m.group_name,
+	m.category_name,
+	m.quantity,
+	m.volume,
+	m.is_blueprint_copy,
+	m.owner_type,
+	m.owner_id,
+	m.owner_name,
+	m.location_flag,
+	m.container_id,
+	m.container_name,
+	m.station_id,
+	m.station_name,
+	m.solar_system_id,
+	m.solar_system_name,
+	m.region_id,
+	m.region_name,
+	m.unit_price,
+	m.total_value
+FROM matches m` + whereClause + `
+ORDER BY ` + sortColumn + ` ` + sortDirection + `, m.item_id
+LIMIT ` + addArg(filters.Limit) + ` OFFSET ` + addArg(filters.Offset)
+
+	rows, err := r.db.QueryContext(ctx, pageQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query asset search results")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := &models.AssetSearchItem{}
+		err = rows.Scan(
+			&item.ItemID,
+			&item.TypeID,
+			&item.TypeName,
+			&item.GroupName,
+			&item.CategoryName,
+			&item.Quantity,
+			&item.Volume,
+			&item.IsBlueprintCopy,
+			&item.OwnerType,
+			&item.OwnerID,
+			&item.OwnerName,
+			&item.LocationFlag,
+			&item.ContainerID,
+			&item.ContainerName,
+			&item.StationID,
+			&item.StationName,
+			&item.SolarSystemID,
+			&item.SolarSystemName,
+			&item.RegionID,
+			&item.RegionName,
+			&item.UnitPrice,
+			&item.TotalValue,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan asset search result")
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	return result, nil
+}
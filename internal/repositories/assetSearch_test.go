@@ -0,0 +1,154 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAssetSearchData(t *testing.T) *repositories.Assets {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	setupTestUniverse(t, db)
+
+	userRepository := repositories.NewUserRepository(db)
+	characterRepository := repositories.NewCharacterRepository(db)
+	characterAssetsRepository := repositories.NewCharacterAssets(db)
+	playerCorpsRepository := repositories.NewPlayerCorporations(db)
+	corpAssetsRepository := repositories.NewCorporationAssets(db)
+	marketPricesRepository := repositories.NewMarketPrices(db)
+
+	err = userRepository.Add(context.Background(), &repositories.User{ID: 42, Name: "Search User"})
+	assert.NoError(t, err)
+
+	err = characterRepository.Add(context.Background(), &repositories.Character{ID: 1337, Name: "Hauler One", UserID: 42})
+	assert.NoError(t, err)
+
+	err = playerCorpsRepository.Upsert(context.Background(), repositories.PlayerCorporation{
+		ID:              2001,
+		UserID:          42,
+		Name:            "Search Corp",
+		EsiToken:        "token123",
+		EsiRefreshToken: "refresh456",
+		EsiExpiresOn:    time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	err = characterAssetsRepository.UpdateAssets(context.Background(), 1337, 42, []*models.EveAsset{
+		{ItemID: 1001, LocationID: 60003760, LocationType: "station", Quantity: 1000, TypeID: 34, LocationFlag: "Hangar"},
+		{ItemID: 1002, IsSingleton: true, LocationID: 60003760, LocationType: "station", Quantity: 1, TypeID: 3293, LocationFlag: "Hangar"},
+		{ItemID: 1003, LocationID: 1002, LocationType: "item", Quantity: 500, TypeID: 35, LocationFlag: "Unlocked"},
+	})
+	assert.NoError(t, err)
+
+	err = characterAssetsRepository.UpsertContainerNames(context.Background(), 1337, 42, map[int64]string{1002: "Minerals Box"})
+	assert.NoError(t, err)
+
+	err = corpAssetsRepository.Upsert(context.Background(), 2001, 42, []*models.EveAsset{
+		{ItemID: 5000, IsSingleton: true, LocationID: 60003761, LocationType: "item", Quantity: 1, TypeID: 27, LocationFlag: "OfficeFolder"},
+		{ItemID: 5001, LocationID: 5000, LocationType: "item", Quantity: 2000, TypeID: 34, LocationFlag: "CorpSAG1"},
+		{ItemID: 5002, LocationID: 5000, LocationType: "item", Quantity: 100, TypeID: 36, LocationFlag: "CorpSAG2"},
+	})
+	assert.NoError(t, err)
+
+	err = marketPricesRepository.UpsertPrices(context.Background(), []models.MarketPrice{
+		{TypeID: 34, RegionID: 10000002, SellPrice: ptrFloat64(5)},
+		{TypeID: 35, RegionID: 10000002, SellPrice: ptrFloat64(10)},
+		{TypeID: 36, RegionID: 10000002, SellPrice: ptrFloat64(100)},
+	})
+	assert.NoError(t, err)
+
+	return repositories.NewAssets(db)
+}
+
+func Test_AssetSearch_ShouldFindAssetsAcrossCharactersAndCorporations(t *testing.T) {
+	assetsRepository := setupAssetSearchData(t)
+
+	result, err := assetsRepository.SearchAssets(context.Background(), 42, &models.AssetSearchFilters{
+		TypeName: "trit",
+		SortBy:   "quantity",
+		SortDesc: true,
+		Limit:    50,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, result.Total)
+	assert.Equal(t, int64(3000), result.TotalQuantity)
+	assert.Equal(t, 15000.0, result.TotalValue)
+	assert.Len(t, result.Items, 2)
+
+	corpItem := result.Items[0]
+	assert.Equal(t, int64(5001), corpItem.ItemID)
+	assert.Equal(t, "corporation", corpItem.OwnerType)
+	assert.Equal(t, "Search Corp", corpItem.OwnerName)
+	assert.Nil(t, corpItem.ContainerID)
+	assert.Equal(t, int64(60003761), *corpItem.StationID)
+	assert.Equal(t, "Jita", *corpItem.SolarSystemName)
+	assert.Equal(t, "The Forge", *corpItem.RegionName)
+
+	charItem := result.Items[1]
+	assert.Equal(t, int64(1001), charItem.ItemID)
+	assert.Equal(t, "character", charItem.OwnerType)
+	assert.Equal(t, "Hauler One", charItem.OwnerName)
+	assert.Equal(t, int64(60003760), *charItem.StationID)
+}
+
+func Test_AssetSearch_ShouldResolveContainerAndStationForNestedItems(t *testing.T) {
+	assetsRepository := setupAssetSearchData(t)
+
+	containerID := int64(1002)
+	result, err := assetsRepository.SearchAssets(context.Background(), 42, &models.AssetSearchFilters{
+		ContainerID: &containerID,
+		SortBy:      "value",
+		SortDesc:    true,
+		Limit:       50,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, result.Total)
+	assert.Len(t, result.Items, 1)
+	assert.Equal(t, int64(35), result.Items[0].TypeID)
+	assert.Equal(t, "Minerals Box", *result.Items[0].ContainerName)
+	assert.Equal(t, int64(60003760), *result.Items[0].StationID)
+	assert.Equal(t, 5000.0, result.Items[0].TotalValue)
+}
+
+func Test_AssetSearch_ShouldFilterByOwnerAndMinValueAndPaginate(t *testing.T) {
+	assetsRepository := setupAssetSearchData(t)
+
+	minValue := 5000.0
+	result, err := assetsRepository.SearchAssets(context.Background(), 42, &models.AssetSearchFilters{
+		OwnerType: "corporation",
+		MinValue:  &minValue,
+		SortBy:    "value",
+		SortDesc:  true,
+		Limit:     1,
+		Offset:    1,
+	})
+	assert.NoError(t, err)
+
+	// Corp Tritanium (10,000) and Mexallon (10,000) match; the office is never returned
+	assert.Equal(t, 2, result.Total)
+	assert.Equal(t, 20000.0, result.TotalValue)
+	assert.Len(t, result.Items, 1)
+	assert.Equal(t, 1, result.Limit)
+	assert.Equal(t, 1, result.Offset)
+}
+
+func Test_AssetSearch_ShouldReturnEmptyForOtherUser(t *testing.T) {
+	assetsRepository := setupAssetSearchData(t)
+
+	result, err := assetsRepository.SearchAssets(context.Background(), 99, &models.AssetSearchFilters{
+		SortBy: "value",
+		Limit:  50,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 0, result.Total)
+	assert.Empty(t, result.Items)
+}
@@ -0,0 +1,248 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+// shipCategoryID is the SDE category of ship hulls.
+const shipCategoryID = 6
+
+// AssetShip is an assembled ship in a hangar together with everything located inside it.
+// TotalValue is the hull plus fittings plus the contents of its bays and holds.
+type AssetShip struct {
+	ID            int64            `json:"id"`
+	TypeID        int64            `json:"typeId"`
+	TypeName      string           `json:"typeName"`
+	Name          string           `json:"name"`
+	OwnerType     string           `json:"ownerType"`
+	OwnerName     string           `json:"ownerName"`
+	OwnerID       int64            `json:"ownerId"`
+	HullValue     float64          `json:"hullValue"`
+	FittingsValue float64          `json:"fittingsValue"`
+	ContentsValue float64          `json:"contentsValue"`
+	TotalValue    float64          `json:"totalValue"`
+	Contents      []*AssetShipItem `json:"contents"`
+}
+
+// AssetShipItem is a stack of items inside a ship, either fitted to a slot or sitting in a hold.
+type AssetShipItem struct {
+	Asset
+	LocationFlag string `json:"locationFlag"`
+	Slot         string `json:"slot"`
+	IsFitted     bool   `json:"isFitted"`
+}
+
+// assembledShipsQuery returns every assembled ship sitting in a character station hangar or a
+// corporation hangar division, with the station it is in and, for corporation ships, the division.
+const assembledShipsQuery = `
+WITH ships AS (
+	SELECT
+		'character' AS owner_type,
+		ca.character_id AS owner_id,
+		ca.item_id,
+		ca.type_id,
+		ca.location_id AS station_id,
+		NULL::int AS division_number
+	FROM character_assets ca
+	INNER JOIN asset_item_types t ON t.type_id = ca.type_id
+	INNER JOIN sde_groups g ON g.group_id = t.group_id
+	WHERE ca.user_id = $1
+		AND ca.is_singleton = true
+		AND g.category_id = $2
+		AND ca.location_flag = 'Hangar'
+
+	UNION ALL
+
+	SELECT
+		'corporation' AS owner_type,
+		co.corporation_id AS owner_id,
+		co.item_id,
+		co.type_id,
+		office.location_id AS station_id,
+		SUBSTRING(co.location_flag, 8, 1)::int AS division_number
+	FROM corporation_assets co
+	INNER JOIN corporation_assets office
+		ON office.item_id = co.location_id
+		AND office.corporation_id = co.corporation_id
+		AND office.user_id = co.user_id
+		AND office.location_flag = 'OfficeFolder'
+	INNER JOIN asset_item_types t ON t.type_id = co.type_id
+	INNER JOIN sde_groups g ON g.group_id = t.group_id
+	WHERE co.user_id = $1
+		AND co.is_singleton = true
+		AND g.category_id = $2
+		AND co.location_flag LIKE 'CorpSAG%'
+)
+SELECT
+	s.owner_type,
+	s.owner_id,
+	resolve_owner_name(s.owner_type, s.owner_id),
+	s.item_id,
+	s.type_id,
+	t.type_name,
+	COALESCE(charNames.name, corpNames.name, t.type_name),
+	s.station_id,
+	s.division_number,
+	COALESCE(market.sell_price, 0)
+FROM ships s
+INNER JOIN asset_item_types t ON t.type_id = s.type_id
+LEFT JOIN character_asset_location_names charNames
+	ON s.owner_type = 'character'
+	AND charNames.character_id = s.owner_id
+	AND charNames.user_id = $1
+	AND charNames.item_id = s.item_id
+LEFT JOIN corporation_asset_location_names corpNames
+	ON s.owner_type = 'corporation'
+	AND corpNames.corporation_id = s.owner_id
+	AND corpNames.user_id = $1
+	AND corpNames.item_id = s.item_id
+LEFT JOIN market_prices market ON market.type_id = s.type_id AND market.region_id = 10000002
+ORDER BY t.type_name, s.item_id`
+
+// shipContentsQuery returns the item stacks directly inside any assembled ship of the user,
+// grouped per ship, location flag and type.
+const shipContentsQuery = `
+WITH owned AS (
+	SELECT character_id AS owner_id, 'character' AS owner_type, item_id, type_id, quantity,
+		is_singleton, is_blueprint_copy, location_id, location_type, location_flag
+	FROM character_assets
+	WHERE user_id = $1
+
+	UNION ALL
+
+	SELECT corporation_id AS owner_id, 'corporation' AS owner_type, item_id, type_id, quantity,
+		is_singleton, is_blueprint_copy, location_id, location_type, location_flag
+	FROM corporation_assets
+	WHERE user_id = $1
+)
+SELECT
+	item.owner_type,
+	item.owner_id,
+	item.location_id,
+	item.location_flag,
+	t.type_id,
+	t.type_name,
+	SUM(item.quantity),
+	SUM(CASE WHEN item.is_singleton THEN t.volume ELSE COALESCE(t.packaged_volume, t.volume) END * item.quantity),
+	market.sell_price,
+	SUM(CASE WHEN item.is_blueprint_copy THEN 0 ELSE item.quantity * COALESCE(market.sell_price, 0) END)
+FROM owned item
+INNER JOIN owned ship
+	ON ship.item_id = item.location_id
+	AND ship.owner_type = item.owner_type
+	AND ship.owner_id = item.owner_id
+	AND ship.is_singleton = true
+INNER JOIN asset_item_types shipTypes ON shipTypes.type_id = ship.type_id
+INNER JOIN sde_groups shipGroups ON shipGroups.group_id = shipTypes.group_id AND shipGroups.category_id = $2
+INNER JOIN asset_item_types t ON t.type_id = item.type_id
+LEFT JOIN market_prices market ON market.type_id = item.type_id AND market.region_id = 10000002
+WHERE item.location_type = 'item'
+GROUP BY
+	item.owner_type,
+	item.owner_id,
+	item.location_id,
+	item.location_flag,
+	t.type_id,
+	t.type_name,
+	market.sell_price
+ORDER BY item.location_flag, t.type_name`
+
+// assetShipPlacement is where an assembled ship sits in the asset tree.
+type assetShipPlacement struct {
+	ship           *AssetShip
+	stationID      int64
+	divisionNumber *int64
+}
+
+// getAssembledShips loads the user's assembled ships with their fittings and hold contents valued
+// at Jita sell prices.
+func (r *Assets) getAssembledShips(ctx context.Context, user int64) ([]*assetShipPlacement, error) {
+	shipRows, err := r.db.QueryContext(ctx, assembledShipsQuery, user, shipCategoryID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query assembled ships")
+	}
+	defer shipRows.Close()
+
+	placements := []*assetShipPlacement{}
+	shipMap := map[int64]*AssetShip{}
+	for shipRows.Next() {
+		ship := &AssetShip{Contents: []*AssetShipItem{}}
+		placement := &assetShipPlacement{ship: ship}
+
+		err = shipRows.Scan(
+			&ship.OwnerType,
+			&ship.OwnerID,
+			&ship.OwnerName,
+			&ship.ID,
+			&ship.TypeID,
+			&ship.TypeName,
+			&ship.Name,
+			&placement.stationID,
+			&placement.divisionNumber,
+			&ship.HullValue,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan assembled ship")
+		}
+		ship.TotalValue = ship.HullValue
+
+		placements = append(placements, placement)
+		shipMap[ship.ID] = ship
+	}
+
+	if len(placements) == 0 {
+		return placements, nil
+	}
+
+	contentRows, err := r.db.QueryContext(ctx, shipContentsQuery, user, shipCategoryID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query ship contents")
+	}
+	defer contentRows.Close()
+
+	for contentRows.Next() {
+		item := &AssetShipItem{}
+		var shipID int64
+		var totalValue float64
+
+		err = contentRows.Scan(
+			&item.OwnerType,
+			&item.OwnerID,
+			&shipID,
+			&item.LocationFlag,
+			&item.TypeID,
+			&item.Name,
+			&item.Quantity,
+			&item.Volume,
+			&item.UnitPrice,
+			&totalValue,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan ship content")
+		}
+
+		ship, ok := shipMap[shipID]
+		if !ok {
+			// ships in space or inside other ships are not placed in the hangar tree
+			continue
+		}
+
+		item.OwnerName = ship.OwnerName
+		item.TotalValue = &totalValue
+		item.Slot = models.ShipSlotForLocationFlag(item.LocationFlag)
+		item.IsFitted = models.IsShipFittingSlot(item.Slot)
+
+		if item.IsFitted {
+			ship.FittingsValue += totalValue
+		} else {
+			ship.ContentsValue += totalValue
+		}
+		ship.TotalValue += totalValue
+		ship.Contents = append(ship.Contents, item)
+	}
+
+	return placements, nil
+}
@@ -0,0 +1,171 @@
+package repositories_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupShipTypes(t *testing.T, db *sql.DB) {
+	sdeRepository := repositories.NewSdeDataRepository(db)
+	itemTypeRepository := repositories.NewItemTypeRepository(db)
+	marketPricesRepository := repositories.NewMarketPrices(db)
+
+	err := sdeRepository.UpsertCategories(context.Background(), []models.SdeCategory{
+		{CategoryID: 6, Name: "Ship", Published: true},
+		{CategoryID: 7, Name: "Module", Published: true},
+		{CategoryID: 18, Name: "Drone", Published: true},
+	})
+	assert.NoError(t, err)
+
+	err = sdeRepository.UpsertGroups(context.Background(), []models.SdeGroup{
+		{GroupID: 25, Name: "Frigate", CategoryID: 6, Published: true},
+		{GroupID: 74, Name: "Hybrid Weapon", CategoryID: 7, Published: true},
+		{GroupID: 100, Name: "Combat Drone", CategoryID: 18, Published: true},
+	})
+	assert.NoError(t, err)
+
+	frigateGroup := int64(25)
+	weaponGroup := int64(74)
+	droneGroup := int64(100)
+	err = itemTypeRepository.UpsertItemTypes(context.Background(), []models.EveInventoryType{
+		{TypeID: 587, TypeName: "Rifter", Volume: 27289, GroupID: &frigateGroup},
+		{TypeID: 3082, TypeName: "150mm Railgun I", Volume: 5, GroupID: &weaponGroup},
+		{TypeID: 2454, TypeName: "Hobgoblin I", Volume: 5, GroupID: &droneGroup},
+	})
+	assert.NoError(t, err)
+
+	err = marketPricesRepository.UpsertPrices(context.Background(), []models.MarketPrice{
+		{TypeID: 34, RegionID: 10000002, SellPrice: ptrFloat64(5)},
+		{TypeID: 587, RegionID: 10000002, SellPrice: ptrFloat64(500000)},
+		{TypeID: 3082, RegionID: 10000002, SellPrice: ptrFloat64(100000)},
+		{TypeID: 2454, RegionID: 10000002, SellPrice: ptrFloat64(20000)},
+	})
+	assert.NoError(t, err)
+}
+
+func Test_AssetsShouldNestCharacterShipContentsUnderTheShip(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	setupTestUniverse(t, db)
+	setupShipTypes(t, db)
+
+	userRepository := repositories.NewUserRepository(db)
+	characterRepository := repositories.NewCharacterRepository(db)
+	characterAssetsRepository := repositories.NewCharacterAssets(db)
+	assetsRepository := repositories.NewAssets(db)
+
+	err = userRepository.Add(context.Background(), &repositories.User{ID: 42, Name: "Pilot"})
+	assert.NoError(t, err)
+
+	err = characterRepository.Add(context.Background(), &repositories.Character{ID: 1337, Name: "Pilot One", UserID: 42})
+	assert.NoError(t, err)
+
+	err = characterAssetsRepository.UpdateAssets(context.Background(), 1337, 42, []*models.EveAsset{
+		{ItemID: 9000, LocationID: 60003760, LocationType: "station", Quantity: 1000, TypeID: 34, LocationFlag: "Hangar"},
+		{ItemID: 9001, IsSingleton: true, LocationID: 60003760, LocationType: "station", Quantity: 1, TypeID: 587, LocationFlag: "Hangar"},
+		{ItemID: 9002, IsSingleton: true, LocationID: 9001, LocationType: "item", Quantity: 1, TypeID: 3082, LocationFlag: "HiSlot0"},
+		{ItemID: 9003, LocationID: 9001, LocationType: "item", Quantity: 5, TypeID: 2454, LocationFlag: "DroneBay"},
+	})
+	assert.NoError(t, err)
+
+	containers, err := characterAssetsRepository.GetAssembledContainers(context.Background(), 1337, 42)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{9001}, containers)
+
+	err = characterAssetsRepository.UpsertContainerNames(context.Background(), 1337, 42, map[int64]string{9001: "My Rifter"})
+	assert.NoError(t, err)
+
+	response, err := assetsRepository.GetUserAssets(context.Background(), 42)
+	assert.NoError(t, err)
+	assert.Len(t, response.Structures, 1)
+
+	station := response.Structures[0]
+	assert.Len(t, station.HangarAssets, 1, "assembled ships should not be listed as loose hangar items")
+	assert.Equal(t, int64(34), station.HangarAssets[0].TypeID)
+
+	assert.Len(t, station.HangarShips, 1)
+	ship := station.HangarShips[0]
+	assert.Equal(t, int64(9001), ship.ID)
+	assert.Equal(t, "Rifter", ship.TypeName)
+	assert.Equal(t, "My Rifter", ship.Name)
+	assert.Equal(t, "Pilot One", ship.OwnerName)
+	assert.Equal(t, 500000.0, ship.HullValue)
+	assert.Equal(t, 100000.0, ship.FittingsValue)
+	assert.Equal(t, 100000.0, ship.ContentsValue)
+	assert.Equal(t, 700000.0, ship.TotalValue)
+
+	assert.Len(t, ship.Contents, 2)
+	assert.Equal(t, "drone_bay", ship.Contents[0].Slot)
+	assert.False(t, ship.Contents[0].IsFitted)
+	assert.Equal(t, int64(5), ship.Contents[0].Quantity)
+	assert.Equal(t, "high", ship.Contents[1].Slot)
+	assert.True(t, ship.Contents[1].IsFitted)
+}
+
+func Test_AssetsShouldPlaceCorporationShipsInTheirDivision(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	setupTestUniverse(t, db)
+	setupShipTypes(t, db)
+
+	userRepository := repositories.NewUserRepository(db)
+	playerCorpsRepository := repositories.NewPlayerCorporations(db)
+	corpAssetsRepository := repositories.NewCorporationAssets(db)
+	assetsRepository := repositories.NewAssets(db)
+
+	err = userRepository.Add(context.Background(), &repositories.User{ID: 42, Name: "Director"})
+	assert.NoError(t, err)
+
+	err = playerCorpsRepository.Upsert(context.Background(), repositories.PlayerCorporation{
+		ID:              2001,
+		UserID:          42,
+		Name:            "Ship Corp",
+		EsiToken:        "token123",
+		EsiRefreshToken: "refresh456",
+		EsiExpiresOn:    time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	err = playerCorpsRepository.UpsertDivisions(context.Background(), 2001, 42, &models.CorporationDivisions{
+		Hanger: map[int]string{1: "Main Hangar", 2: "Ships"},
+		Wallet: map[int]string{},
+	})
+	assert.NoError(t, err)
+
+	err = corpAssetsRepository.Upsert(context.Background(), 2001, 42, []*models.EveAsset{
+		{ItemID: 5000, IsSingleton: true, LocationID: 60003760, LocationType: "item", Quantity: 1, TypeID: 27, LocationFlag: "OfficeFolder"},
+		{ItemID: 5001, IsSingleton: true, LocationID: 5000, LocationType: "item", Quantity: 1, TypeID: 587, LocationFlag: "CorpSAG2"},
+		{ItemID: 5002, IsSingleton: true, LocationID: 5001, LocationType: "item", Quantity: 1, TypeID: 3082, LocationFlag: "HiSlot0"},
+	})
+	assert.NoError(t, err)
+
+	response, err := assetsRepository.GetUserAssets(context.Background(), 42)
+	assert.NoError(t, err)
+	assert.Len(t, response.Structures, 1)
+
+	var shipsDivision *repositories.CorporationHanger
+	for _, hanger := range response.Structures[0].CorporationHangers {
+		if hanger.ID == 2 {
+			shipsDivision = hanger
+		} else {
+			assert.Empty(t, hanger.HangarShips)
+		}
+	}
+	assert.NotNil(t, shipsDivision)
+	assert.Empty(t, shipsDivision.Assets, "assembled ships should not be listed as loose hangar items")
+	assert.Len(t, shipsDivision.HangarShips, 1)
+
+	ship := shipsDivision.HangarShips[0]
+	assert.Equal(t, "corporation", ship.OwnerType)
+	assert.Equal(t, "Rifter", ship.Name, "unnamed ships fall back to the type name")
+	assert.Equal(t, 600000.0, ship.TotalValue)
+	assert.Len(t, ship.Contents, 1)
+}
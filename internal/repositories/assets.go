@@ -37,6 +37,7 @@ type AssetStructure struct {
 	Deliveries         []*Asset              `json:"deliveries"`
 	AssetSafety        []*Asset              `json:"assetSafety"`
 	CorporationHangers []*CorporationHanger  `json:"corporationHangers"`
+	HangarShips        []*AssetShip          `json:"hangarShips,omitempty"`
 }
 
 type CorporationHanger struct {
@@ -46,6 +47,7 @@ type CorporationHanger struct {
 	CorporationName  string            `json:"corporationName"`
 	Assets           []*Asset          `json:"assets"`
 	HangarContainers []*AssetContainer `json:"hangarContainers"`
+	HangarShips      []*AssetShip      `json:"hangarShips,omitempty"`
 }
 
 type AssetContainer struct {
@@ -141,7 +143,7 @@ SELECT
     assetTypes.type_id,
     assetTypes.type_name,
     SUM(characterAssets.quantity) as quantity,
-    SUM(assetTypes.volume * characterAssets.quantity) as "volume",
+    SUM(CASE WHEN characterAssets.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END * characterAssets.quantity) as "volume",
     stockpile.desired_quantity,
     (SUM(characterAssets.quantity) - COALESCE(stockpile.desired_quantity, 0)) as stockpile_delta,
     market.sell_price as unit_price,
@@ -187,6 +189,7 @@ ON
 WHERE
     characterAssets.user_id=$1
     AND NOT (is_singleton=true AND assetTypes.type_name like '%Container')
+    AND NOT (is_singleton=true AND EXISTS (SELECT 1 FROM sde_groups g WHERE g.group_id = assetTypes.group_id AND g.category_id = 6))
 	AND NOT location_flag='AssetSafety'
     AND (
         location_type='station'
@@ -304,7 +307,7 @@ SELECT
     assetTypes.type_id,
     assetTypes.type_name,
     SUM(characterAssets.quantity) as quantity,
-    SUM(assetTypes.volume * characterAssets.quantity) as "volume",
+    SUM(CASE WHEN characterAssets.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END * characterAssets.quantity) as "volume",
     characterAssets.location_id,
     stockpile.desired_quantity,
     (SUM(characterAssets.quantity) - COALESCE(stockpile.desired_quantity, 0)) as stockpile_delta,
@@ -540,7 +543,7 @@ SELECT
 	assetTypes.type_id,
 	assetTypes.type_name,
 	SUM(corporation_assets.quantity) as quantity,
-	SUM(assetTypes.volume * corporation_assets.quantity) as "volume",
+	SUM(CASE WHEN corporation_assets.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END * corporation_assets.quantity) as "volume",
 	stockpile.desired_quantity,
 	(SUM(corporation_assets.quantity) - COALESCE(stockpile.desired_quantity, 0)) as stockpile_delta,
 	market.sell_price as unit_price,
@@ -584,6 +587,7 @@ ON
 WHERE
 	corporation_assets.user_id=$1
 	AND NOT (corporation_assets.is_singleton=true AND assetTypes.type_name like '%Container')
+	AND NOT (corporation_assets.is_singleton=true AND EXISTS (SELECT 1 FROM sde_groups g WHERE g.group_id = assetTypes.group_id AND g.category_id = 6))
 	AND corporation_assets.location_type='item'
 	AND corporation_assets.location_flag like 'CorpSAG%'
 GROUP BY
@@ -754,7 +758,7 @@ SELECT
 	assetTypes.type_id,
 	assetTypes.type_name,
 	SUM(corporation_assets.quantity) as quantity,
-	SUM(assetTypes.volume * corporation_assets.quantity) as "volume",
+	SUM(CASE WHEN corporation_assets.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END * corporation_assets.quantity) as "volume",
 	corporation_assets.location_id,
 	stockpile.desired_quantity,
 	(SUM(corporation_assets.quantity) - COALESCE(stockpile.desired_quantity, 0)) as stockpile_delta,
@@ -835,6 +839,35 @@ ORDER BY
 		container.Assets = append(container.Assets, asset)
 	}
 
+	// assembled ships with their fittings and hold contents
+	ships, err := r.getAssembledShips(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, placement := range ships {
+		ship := placement.ship
+		if placement.divisionNumber == nil {
+			station, ok := stationMap[placement.stationID]
+			if !ok {
+				continue
+			}
+			station.HangarShips = append(station.HangarShips, ship)
+			continue
+		}
+
+		hanger := getOrCreateDivision(placement.stationID, ship.OwnerID, *placement.divisionNumber)
+		if hanger == nil {
+			continue
+		}
+		if stationCorpMap[placement.stationID] == nil {
+			stationCorpMap[placement.stationID] = map[int64]bool{}
+		}
+		stationCorpMap[placement.stationID][ship.OwnerID] = true
+
+		hanger.HangarShips = append(hanger.HangarShips, ship)
+	}
+
 	// Add divisions to stations
 	// If a corp has ANY assets at a station, show ALL its divisions (even empty ones)
 	for stationID, corpIDs := range stationCorpMap {
@@ -929,7 +962,7 @@ type orphanStockpileRow struct {
 func (r *Assets) InjectOrphanStockpileRows(ctx context.Context, userID int64, response *AssetsResponse) error {
 	query := `
 		SELECT
-			sm.type_id, ait.type_name, ait.volume,
+			sm.type_id, ait.type_name, COALESCE(ait.packaged_volume, ait.volume),
 			sm.owner_type, sm.owner_id, sm.location_id,
 			sm.container_id, sm.division_number, sm.desired_quantity,
 			COALESCE(mp.buy_price, 0) as unit_price
@@ -1165,21 +1198,29 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 	}
 
 	// Query for all assets with stockpile deficit (stockpile_delta < 0)
-	// This combines personal and corporation assets in a single query
+	// This combines personal and corporation assets in a single query.
+	// Stock reserved for plan runs does not count towards a marker.
 	query := `
-		WITH all_deficits AS (
+		WITH reserved_markers AS (
+			SELECT sm.*,
+				reserved_material_quantity(sm.user_id, sm.owner_type, sm.owner_id, sm.location_id,
+					sm.container_id, sm.division_number, sm.type_id) AS reserved_quantity
+			FROM stockpile_markers sm
+			WHERE sm.user_id = $1
+		),
+		all_deficits AS (
 			-- Personal hangar items
 			SELECT
 				assetTypes.type_name as name,
 				characterAssets.type_id,
 				characterAssets.quantity,
-				(characterAssets.quantity * assetTypes.volume) as volume,
+				(characterAssets.quantity * CASE WHEN characterAssets.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END) as volume,
 				'character' as owner_type,
 				characters.name as owner_name,
 				characters.id as owner_id,
 				stockpile.desired_quantity,
-				(characterAssets.quantity - COALESCE(stockpile.desired_quantity, 0)) as stockpile_delta,
-				ABS(characterAssets.quantity - COALESCE(stockpile.desired_quantity, 0)) * COALESCE(market.buy_price, 0) as deficit_value,
+				(characterAssets.quantity - COALESCE(stockpile.reserved_quantity, 0) - COALESCE(stockpile.desired_quantity, 0)) as stockpile_delta,
+				ABS(characterAssets.quantity - COALESCE(stockpile.reserved_quantity, 0) - COALESCE(stockpile.desired_quantity, 0)) * COALESCE(market.buy_price, 0) as deficit_value,
 				stations.name as structure_name,
 				systems.name as solar_system,
 				regions.name as region,
@@ -1191,7 +1232,7 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 			INNER JOIN solar_systems systems ON stations.solar_system_id = systems.solar_system_id
 			INNER JOIN constellations ON systems.constellation_id = constellations.constellation_id
 			INNER JOIN regions ON constellations.region_id = regions.region_id
-			LEFT JOIN stockpile_markers stockpile ON (
+			LEFT JOIN reserved_markers stockpile ON (
 				stockpile.type_id = characterAssets.type_id
 				AND stockpile.location_id = characterAssets.location_id
 				AND stockpile.container_id IS NULL
@@ -1201,7 +1242,7 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 			WHERE characterAssets.user_id = $1
 				AND characterAssets.location_type = 'station'
 				AND characterAssets.location_flag IN ('Hangar', 'Deliveries', 'AssetSafety')
-				AND (characterAssets.quantity - COALESCE(stockpile.desired_quantity, 0)) < 0
+				AND (characterAssets.quantity - COALESCE(stockpile.reserved_quantity, 0) - COALESCE(stockpile.desired_quantity, 0)) < 0
 
 			UNION ALL
 
@@ -1210,13 +1251,13 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				assetTypes.type_name as name,
 				characterAssets.type_id,
 				characterAssets.quantity,
-				(characterAssets.quantity * assetTypes.volume) as volume,
+				(characterAssets.quantity * CASE WHEN characterAssets.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END) as volume,
 				'character' as owner_type,
 				characters.name as owner_name,
 				characters.id as owner_id,
 				stockpile.desired_quantity,
-				(characterAssets.quantity - COALESCE(stockpile.desired_quantity, 0)) as stockpile_delta,
-				ABS(characterAssets.quantity - COALESCE(stockpile.desired_quantity, 0)) * COALESCE(market.buy_price, 0) as deficit_value,
+				(characterAssets.quantity - COALESCE(stockpile.reserved_quantity, 0) - COALESCE(stockpile.desired_quantity, 0)) as stockpile_delta,
+				ABS(characterAssets.quantity - COALESCE(stockpile.reserved_quantity, 0) - COALESCE(stockpile.desired_quantity, 0)) * COALESCE(market.buy_price, 0) as deficit_value,
 				stations.name as structure_name,
 				systems.name as solar_system,
 				regions.name as region,
@@ -1230,7 +1271,7 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 			INNER JOIN solar_systems systems ON stations.solar_system_id = systems.solar_system_id
 			INNER JOIN constellations ON systems.constellation_id = constellations.constellation_id
 			INNER JOIN regions ON constellations.region_id = regions.region_id
-			LEFT JOIN stockpile_markers stockpile ON (
+			LEFT JOIN reserved_markers stockpile ON (
 				stockpile.type_id = characterAssets.type_id
 				AND stockpile.container_id = characterAssets.location_id
 				AND stockpile.owner_id = characterAssets.character_id
@@ -1239,7 +1280,7 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 			WHERE characterAssets.user_id = $1
 				AND characterAssets.location_type = 'item'
 				AND NOT (characterAssets.is_singleton = true AND assetTypes.type_name LIKE '%Container')
-				AND (characterAssets.quantity - COALESCE(stockpile.desired_quantity, 0)) < 0
+				AND (characterAssets.quantity - COALESCE(stockpile.reserved_quantity, 0) - COALESCE(stockpile.desired_quantity, 0)) < 0
 
 			UNION ALL
 
@@ -1248,13 +1289,13 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				assetTypes.type_name as name,
 				loc.type_id,
 				ca.quantity,
-				(ca.quantity * assetTypes.volume) as volume,
+				(ca.quantity * CASE WHEN ca.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END) as volume,
 				'corporation' as owner_type,
 				corps.name as owner_name,
 				corps.id as owner_id,
 				stockpile.desired_quantity,
-				(ca.quantity - COALESCE(stockpile.desired_quantity, 0)) as stockpile_delta,
-				ABS(ca.quantity - COALESCE(stockpile.desired_quantity, 0)) * COALESCE(market.buy_price, 0) as deficit_value,
+				(ca.quantity - COALESCE(stockpile.reserved_quantity, 0) - COALESCE(stockpile.desired_quantity, 0)) as stockpile_delta,
+				ABS(ca.quantity - COALESCE(stockpile.reserved_quantity, 0) - COALESCE(stockpile.desired_quantity, 0)) * COALESCE(market.buy_price, 0) as deficit_value,
 				loc.station_name as structure_name,
 				loc.solar_system_name as solar_system,
 				loc.region_name as region,
@@ -1273,7 +1314,7 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				AND divisions.user_id = loc.user_id
 				AND divisions.division_type = 'hangar'
 			)
-			LEFT JOIN stockpile_markers stockpile ON (
+			LEFT JOIN reserved_markers stockpile ON (
 				stockpile.type_id = loc.type_id
 				AND stockpile.location_id = loc.station_id
 				AND stockpile.division_number = loc.division_number
@@ -1284,7 +1325,7 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 			WHERE loc.user_id = $1
 				AND loc.location_flag LIKE 'CorpSAG%'
 				AND loc.station_id IS NOT NULL
-				AND (ca.quantity - COALESCE(stockpile.desired_quantity, 0)) < 0
+				AND (ca.quantity - COALESCE(stockpile.reserved_quantity, 0) - COALESCE(stockpile.desired_quantity, 0)) < 0
 
 			UNION ALL
 
@@ -1293,13 +1334,13 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				assetTypes.type_name as name,
 				loc.type_id,
 				ca.quantity,
-				(ca.quantity * assetTypes.volume) as volume,
+				(ca.quantity * CASE WHEN ca.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END) as volume,
 				'corporation' as owner_type,
 				corps.name as owner_name,
 				corps.id as owner_id,
 				stockpile.desired_quantity,
-				(ca.quantity - COALESCE(stockpile.desired_quantity, 0)) as stockpile_delta,
-				ABS(ca.quantity - COALESCE(stockpile.desired_quantity, 0)) * COALESCE(market.buy_price, 0) as deficit_value,
+				(ca.quantity - COALESCE(stockpile.reserved_quantity, 0) - COALESCE(stockpile.desired_quantity, 0)) as stockpile_delta,
+				ABS(ca.quantity - COALESCE(stockpile.reserved_quantity, 0) - COALESCE(stockpile.desired_quantity, 0)) * COALESCE(market.buy_price, 0) as deficit_value,
 				loc.station_name as structure_name,
 				loc.solar_system_name as solar_system,
 				loc.region_name as region,
@@ -1319,7 +1360,7 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				AND divisions.user_id = loc.user_id
 				AND divisions.division_type = 'hangar'
 			)
-			LEFT JOIN stockpile_markers stockpile ON (
+			LEFT JOIN reserved_markers stockpile ON (
 				stockpile.type_id = loc.type_id
 				AND stockpile.division_number = loc.division_number
 				AND stockpile.container_id = loc.container_id
@@ -1331,7 +1372,7 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				AND loc.container_location_flag LIKE 'CorpSAG%'
 				AND loc.station_id IS NOT NULL
 				AND NOT (ca.is_singleton = true AND assetTypes.type_name LIKE '%Container')
-				AND (ca.quantity - COALESCE(stockpile.desired_quantity, 0)) < 0
+				AND (ca.quantity - COALESCE(stockpile.reserved_quantity, 0) - COALESCE(stockpile.desired_quantity, 0)) < 0
 
 			UNION ALL
 
@@ -1340,7 +1381,7 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				assetTypes.type_name as name,
 				stockpile.type_id,
 				0 as quantity,
-				assetTypes.volume as volume,
+				COALESCE(assetTypes.packaged_volume, assetTypes.volume) as volume,
 				'character' as owner_type,
 				characters.name as owner_name,
 				stockpile.owner_id as owner_id,
@@ -1380,7 +1421,7 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				assetTypes.type_name as name,
 				stockpile.type_id,
 				0 as quantity,
-				assetTypes.volume as volume,
+				COALESCE(assetTypes.packaged_volume, assetTypes.volume) as volume,
 				'corporation' as owner_type,
 				corps.name as owner_name,
 				stockpile.owner_id as owner_id,
@@ -177,6 +177,7 @@ func (r *AutoBuyConfigs) GetByID(ctx context.Context, id int64) (*models.AutoBuy
 
 // GetStockpileDeficitsForConfig returns stockpile deficits for items matching the config's container context.
 // It joins stockpile_markers with asset tables to compute current quantities and deficits.
+// Stock reserved for plan runs is not counted as current quantity.
 func (r *AutoBuyConfigs) GetStockpileDeficitsForConfig(ctx context.Context, config *models.AutoBuyConfig) ([]*models.StockpileDeficitItem, error) {
 	var query string
 
@@ -187,8 +188,8 @@ func (r *AutoBuyConfigs) GetStockpileDeficitsForConfig(ctx context.Context, conf
 				SELECT
 					sm.type_id,
 					sm.desired_quantity,
-					COALESCE(SUM(ca.quantity), 0) AS current_quantity,
-					sm.desired_quantity - COALESCE(SUM(ca.quantity), 0) AS deficit,
+					COALESCE(SUM(ca.quantity), 0) - reserved_material_quantity(sm.user_id, sm.owner_type, sm.owner_id, sm.location_id, sm.container_id, sm.division_number, sm.type_id) AS current_quantity,
+					sm.desired_quantity - (COALESCE(SUM(ca.quantity), 0) - reserved_material_quantity(sm.user_id, sm.owner_type, sm.owner_id, sm.location_id, sm.container_id, sm.division_number, sm.type_id)) AS deficit,
 					sm.price_source,
 					sm.price_percentage
 				FROM stockpile_markers sm
@@ -204,15 +205,15 @@ func (r *AutoBuyConfigs) GetStockpileDeficitsForConfig(ctx context.Context, conf
 					AND COALESCE(sm.container_id, 0::bigint) = COALESCE($5::bigint, 0::bigint)
 					AND COALESCE(sm.division_number, 0) = COALESCE($6, 0)
 					AND sm.auto_production_enabled = FALSE
-				GROUP BY sm.type_id, sm.desired_quantity, sm.price_source, sm.price_percentage
+				GROUP BY sm.id, sm.type_id, sm.desired_quantity, sm.price_source, sm.price_percentage
 			`
 		} else {
 			query = `
 				SELECT
 					sm.type_id,
 					sm.desired_quantity,
-					COALESCE(SUM(ca.quantity), 0) AS current_quantity,
-					sm.desired_quantity - COALESCE(SUM(ca.quantity), 0) AS deficit,
+					COALESCE(SUM(ca.quantity), 0) - reserved_material_quantity(sm.user_id, sm.owner_type, sm.owner_id, sm.location_id, sm.container_id, sm.division_number, sm.type_id) AS current_quantity,
+					sm.desired_quantity - (COALESCE(SUM(ca.quantity), 0) - reserved_material_quantity(sm.user_id, sm.owner_type, sm.owner_id, sm.location_id, sm.container_id, sm.division_number, sm.type_id)) AS deficit,
 					sm.price_source,
 					sm.price_percentage
 				FROM stockpile_markers sm
@@ -228,7 +229,7 @@ func (r *AutoBuyConfigs) GetStockpileDeficitsForConfig(ctx context.Context, conf
 					AND COALESCE(sm.container_id, 0::bigint) = COALESCE($5::bigint, 0::bigint)
 					AND COALESCE(sm.division_number, 0) = COALESCE($6, 0)
 					AND sm.auto_production_enabled = FALSE
-				GROUP BY sm.type_id, sm.desired_quantity, sm.price_source, sm.price_percentage
+				GROUP BY sm.id, sm.type_id, sm.desired_quantity, sm.price_source, sm.price_percentage
 			`
 		}
 	} else {
@@ -238,8 +239,8 @@ func (r *AutoBuyConfigs) GetStockpileDeficitsForConfig(ctx context.Context, conf
 				SELECT
 					sm.type_id,
 					sm.desired_quantity,
-					COALESCE(SUM(ca.quantity), 0) AS current_quantity,
-					sm.desired_quantity - COALESCE(SUM(ca.quantity), 0) AS deficit,
+					COALESCE(SUM(ca.quantity), 0) - reserved_material_quantity(sm.user_id, sm.owner_type, sm.owner_id, sm.location_id, sm.container_id, sm.division_number, sm.type_id) AS current_quantity,
+					sm.desired_quantity - (COALESCE(SUM(ca.quantity), 0) - reserved_material_quantity(sm.user_id, sm.owner_type, sm.owner_id, sm.location_id, sm.container_id, sm.division_number, sm.type_id)) AS deficit,
 					sm.price_source,
 					sm.price_percentage
 				FROM stockpile_markers sm
@@ -256,15 +257,15 @@ func (r *AutoBuyConfigs) GetStockpileDeficitsForConfig(ctx context.Context, conf
 					AND sm.container_id IS NULL
 					AND COALESCE(sm.division_number, 0) = COALESCE($5, 0)
 					AND sm.auto_production_enabled = FALSE
-				GROUP BY sm.type_id, sm.desired_quantity, sm.price_source, sm.price_percentage
+				GROUP BY sm.id, sm.type_id, sm.desired_quantity, sm.price_source, sm.price_percentage
 			`
 		} else {
 			query = `
 				SELECT
 					sm.type_id,
 					sm.desired_quantity,
-					COALESCE(SUM(items.quantity), 0) AS current_quantity,
-					sm.desired_quantity - COALESCE(SUM(items.quantity), 0) AS deficit,
+					COALESCE(SUM(items.quantity), 0) - reserved_material_quantity(sm.user_id, sm.owner_type, sm.owner_id, sm.location_id, sm.container_id, sm.division_number, sm.type_id) AS current_quantity,
+					sm.desired_quantity - (COALESCE(SUM(items.quantity), 0) - reserved_material_quantity(sm.user_id, sm.owner_type, sm.owner_id, sm.location_id, sm.container_id, sm.division_number, sm.type_id)) AS deficit,
 					sm.price_source,
 					sm.price_percentage
 				FROM stockpile_markers sm
@@ -290,7 +291,7 @@ func (r *AutoBuyConfigs) GetStockpileDeficitsForConfig(ctx context.Context, conf
 					AND sm.container_id IS NULL
 					AND COALESCE(sm.division_number, 0) = COALESCE($5, 0)
 					AND sm.auto_production_enabled = FALSE
-				GROUP BY sm.type_id, sm.desired_quantity, sm.price_source, sm.price_percentage
+				GROUP BY sm.id, sm.type_id, sm.desired_quantity, sm.price_source, sm.price_percentage
 			`
 		}
 	}
@@ -356,7 +356,8 @@ func (r *BuyOrders) GetByUser(ctx context.Context, userID int64) ([]*models.BuyO
 	return orders, nil
 }
 
-// GetDemandForSeller returns active buy orders from users who have granted seller the for_sale_browse permission.
+// GetDemandForSeller returns active buy orders from users who have granted seller the for_sale_browse permission,
+// directly or through one of their contact groups.
 // Only exposes min_price_per_unit — max_price_per_unit is private to the buyer.
 func (r *BuyOrders) GetDemandForSeller(ctx context.Context, sellerUserID int64) ([]*models.BuyOrder, error) {
 	query := `
@@ -376,11 +377,22 @@ func (r *BuyOrders) GetDemandForSeller(ctx context.Context, sellerUserID int64)
 			bo.updated_at
 		FROM buy_orders bo
 		LEFT JOIN asset_item_types it ON bo.type_id = it.type_id
-		INNER JOIN contact_permissions cp ON cp.granting_user_id = bo.buyer_user_id
-			AND cp.receiving_user_id = $1
-			AND cp.service_type = 'for_sale_browse'
-			AND cp.can_access = true
 		WHERE bo.is_active = true
+			AND (
+				EXISTS (
+					SELECT 1 FROM contact_permissions cp
+					WHERE cp.granting_user_id = bo.buyer_user_id
+						AND cp.receiving_user_id = $1
+						AND cp.service_type = 'for_sale_browse'
+						AND cp.can_access = true
+				) OR EXISTS (
+					SELECT 1 FROM contact_groups g
+					JOIN contact_group_members m ON m.group_id = g.id
+					WHERE g.user_id = bo.buyer_user_id
+						AND m.member_user_id = $1
+						AND g.permissions ? 'for_sale_browse'
+				)
+			)
 		ORDER BY bo.created_at DESC
 	`
 
@@ -139,6 +139,85 @@ func (r *CharacterBlueprints) GetBlueprintLevels(ctx context.Context, userID int
 	return result, nil
 }
 
+// GetByItemID returns one of the user's blueprints by its item ID, or nil if the user has no such blueprint.
+func (r *CharacterBlueprints) GetByItemID(ctx context.Context, userID int64, itemID int64) (*models.CharacterBlueprint, error) {
+	query := `
+		SELECT
+			item_id, owner_id, owner_type, user_id, type_id, location_id, location_flag,
+			quantity, material_efficiency, time_efficiency, runs, updated_at
+		FROM character_blueprints
+		WHERE user_id = $1 AND item_id = $2
+	`
+
+	var bp models.CharacterBlueprint
+	err := r.db.QueryRowContext(ctx, query, userID, itemID).Scan(
+		&bp.ItemID,
+		&bp.OwnerID,
+		&bp.OwnerType,
+		&bp.UserID,
+		&bp.TypeID,
+		&bp.LocationID,
+		&bp.LocationFlag,
+		&bp.Quantity,
+		&bp.MaterialEfficiency,
+		&bp.TimeEfficiency,
+		&bp.Runs,
+		&bp.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get blueprint")
+	}
+
+	return &bp, nil
+}
+
+// GetByOwnerAndType returns the user's blueprint items of one type held by one owner,
+// best researched first.
+func (r *CharacterBlueprints) GetByOwnerAndType(ctx context.Context, userID int64, ownerType string, ownerID int64, typeID int64) ([]*models.CharacterBlueprint, error) {
+	query := `
+		SELECT
+			item_id, owner_id, owner_type, user_id, type_id, location_id, location_flag,
+			quantity, material_efficiency, time_efficiency, runs, updated_at
+		FROM character_blueprints
+		WHERE user_id = $1 AND owner_type = $2 AND owner_id = $3 AND type_id = $4
+		ORDER BY material_efficiency DESC, time_efficiency DESC, runs DESC, item_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, ownerType, ownerID, typeID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query blueprints")
+	}
+	defer rows.Close()
+
+	blueprints := []*models.CharacterBlueprint{}
+	for rows.Next() {
+		var bp models.CharacterBlueprint
+		err = rows.Scan(
+			&bp.ItemID,
+			&bp.OwnerID,
+			&bp.OwnerType,
+			&bp.UserID,
+			&bp.TypeID,
+			&bp.LocationID,
+			&bp.LocationFlag,
+			&bp.Quantity,
+			&bp.MaterialEfficiency,
+			&bp.TimeEfficiency,
+			&bp.Runs,
+			&bp.UpdatedAt,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan blueprint")
+		}
+		blueprints = append(blueprints, &bp)
+	}
+
+	return blueprints, nil
+}
+
 // DeleteByOwner removes all blueprints belonging to the specified owner.
 func (r *CharacterBlueprints) DeleteByOwner(ctx context.Context, ownerID int64, ownerType string) error {
 	_, err := r.db.ExecContext(ctx,
@@ -150,3 +229,84 @@ func (r *CharacterBlueprints) DeleteByOwner(ctx context.Context, ownerID int64,
 	}
 	return nil
 }
+
+// GetBlueprintLibrary returns every blueprint owned by the user's characters and corporations,
+// with the blueprint's product (manufacturing or reaction), owner and location names resolved,
+// and whether any of the user's production plans uses the blueprint type.
+func (r *CharacterBlueprints) GetBlueprintLibrary(ctx context.Context, userID int64) ([]*models.BlueprintLibraryItem, error) {
+	query := `
+		SELECT
+			cb.item_id,
+			cb.type_id,
+			COALESCE(bpType.type_name, ''),
+			product.type_id,
+			productType.type_name,
+			product.activity,
+			cb.owner_type,
+			cb.owner_id,
+			resolve_owner_name(cb.owner_type, cb.owner_id) AS owner_name,
+			cb.location_id,
+			resolve_location_name(cb.location_id) AS location_name,
+			cb.location_flag,
+			cb.quantity,
+			cb.material_efficiency,
+			cb.time_efficiency,
+			cb.runs,
+			EXISTS (
+				SELECT 1
+				FROM production_plan_steps s
+				JOIN production_plans p ON p.id = s.plan_id
+				WHERE p.user_id = cb.user_id AND s.blueprint_type_id = cb.type_id
+			) AS used_by_plan
+		FROM character_blueprints cb
+		LEFT JOIN asset_item_types bpType ON bpType.type_id = cb.type_id
+		LEFT JOIN LATERAL (
+			SELECT bp.type_id, bp.activity
+			FROM sde_blueprint_products bp
+			WHERE bp.blueprint_type_id = cb.type_id
+				AND bp.activity IN ('manufacturing', 'reaction')
+			ORDER BY CASE WHEN bp.activity = 'manufacturing' THEN 0 ELSE 1 END
+			LIMIT 1
+		) product ON true
+		LEFT JOIN asset_item_types productType ON productType.type_id = product.type_id
+		WHERE cb.user_id = $1
+		ORDER BY bpType.type_name, cb.material_efficiency DESC, cb.time_efficiency DESC, cb.item_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query blueprint library")
+	}
+	defer rows.Close()
+
+	items := []*models.BlueprintLibraryItem{}
+	for rows.Next() {
+		item := &models.BlueprintLibraryItem{}
+		err = rows.Scan(
+			&item.ItemID,
+			&item.BlueprintTypeID,
+			&item.BlueprintName,
+			&item.ProductTypeID,
+			&item.ProductName,
+			&item.Activity,
+			&item.OwnerType,
+			&item.OwnerID,
+			&item.OwnerName,
+			&item.LocationID,
+			&item.LocationName,
+			&item.LocationFlag,
+			&item.Quantity,
+			&item.MaterialEfficiency,
+			&item.TimeEfficiency,
+			&item.Runs,
+			&item.UsedByPlan,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan blueprint library item")
+		}
+		item.IsCopy = item.Quantity == -2
+		items = append(items, item)
+	}
+
+	return items, nil
+}
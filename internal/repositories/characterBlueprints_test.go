@@ -365,3 +365,75 @@ func Test_CharacterBlueprints_DeleteByOwner_RemovesOnlyMatchingOwner(t *testing.
 	assert.Nil(t, levels[790])
 	assert.NotNil(t, levels[791])
 }
+
+func Test_CharacterBlueprints_GetBlueprintLibrary_ReturnsBlueprintsWithPlanUsage(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	userRepo := repositories.NewUserRepository(db)
+	charRepo := repositories.NewCharacterRepository(db)
+	bpRepo := repositories.NewCharacterBlueprints(db)
+	plansRepo := repositories.NewProductionPlans(db)
+
+	user := &repositories.User{ID: 7080, Name: "BP Library User"}
+	err = userRepo.Add(context.Background(), user)
+	assert.NoError(t, err)
+
+	char := &repositories.Character{ID: 70801, Name: "Library Char", UserID: user.ID}
+	err = charRepo.Add(context.Background(), char)
+	assert.NoError(t, err)
+
+	err = bpRepo.ReplaceBlueprints(context.Background(), char.ID, "character", user.ID, []*models.CharacterBlueprint{
+		{ItemID: 88001, TypeID: 787, LocationID: 60003760, LocationFlag: "Hangar", Quantity: -1, MaterialEfficiency: 0, TimeEfficiency: 0, Runs: -1},
+		{ItemID: 88002, TypeID: 788, LocationID: 60003760, LocationFlag: "Hangar", Quantity: -2, MaterialEfficiency: 10, TimeEfficiency: 20, Runs: 5},
+	})
+	assert.NoError(t, err)
+
+	plan, err := plansRepo.Create(context.Background(), &models.ProductionPlan{UserID: user.ID, ProductTypeID: 587, Name: "Rifters"})
+	assert.NoError(t, err)
+
+	_, err = plansRepo.CreateStep(context.Background(), &models.ProductionPlanStep{
+		PlanID:          plan.ID,
+		ProductTypeID:   587,
+		BlueprintTypeID: 787,
+		Activity:        "manufacturing",
+		MELevel:         10,
+		TELevel:         20,
+		Structure:       "raitaru",
+		Rig:             "t2",
+		Security:        "high",
+	})
+	assert.NoError(t, err)
+
+	items, err := bpRepo.GetBlueprintLibrary(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	byItem := map[int64]*models.BlueprintLibraryItem{}
+	for _, item := range items {
+		byItem[item.ItemID] = item
+	}
+
+	bpo := byItem[88001]
+	assert.NotNil(t, bpo)
+	assert.False(t, bpo.IsCopy)
+	assert.True(t, bpo.UsedByPlan)
+	assert.Equal(t, "Library Char", bpo.OwnerName)
+
+	bpc := byItem[88002]
+	assert.NotNil(t, bpc)
+	assert.True(t, bpc.IsCopy)
+	assert.False(t, bpc.UsedByPlan)
+	assert.Equal(t, 5, bpc.Runs)
+}
+
+func Test_CharacterBlueprints_GetBlueprintLibrary_EmptyForUnknownUser(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	bpRepo := repositories.NewCharacterBlueprints(db)
+
+	items, err := bpRepo.GetBlueprintLibrary(context.Background(), 9999998)
+	assert.NoError(t, err)
+	assert.Len(t, items, 0)
+}
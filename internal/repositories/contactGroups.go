@@ -0,0 +1,331 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+type ContactGroups struct {
+	db *sql.DB
+}
+
+func NewContactGroups(db *sql.DB) *ContactGroups {
+	return &ContactGroups{db: db}
+}
+
+// scanContactGroup scans a contact group row including the permissions jsonb column
+func scanContactGroup(scanner rowScanner) (*models.ContactGroup, error) {
+	var group models.ContactGroup
+	var permissionsJSON []byte
+	err := scanner.Scan(
+		&group.ID, &group.UserID, &group.Name, &permissionsJSON, &group.CreatedAt, &group.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	group.Permissions = []string{}
+	if len(permissionsJSON) > 0 {
+		if err := json.Unmarshal(permissionsJSON, &group.Permissions); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal permissions")
+		}
+	}
+	group.Members = []*models.ContactGroupMember{}
+
+	return &group, nil
+}
+
+// GetByUser returns the groups a user owns with their members
+func (r *ContactGroups) GetByUser(ctx context.Context, userID int64) ([]*models.ContactGroup, error) {
+	query := `
+		SELECT id, user_id, name, permissions, created_at, updated_at
+		FROM contact_groups
+		WHERE user_id = $1
+		ORDER BY name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query contact groups")
+	}
+	defer rows.Close()
+
+	groups := []*models.ContactGroup{}
+	byID := map[int64]*models.ContactGroup{}
+	for rows.Next() {
+		group, err := scanContactGroup(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan contact group")
+		}
+		groups = append(groups, group)
+		byID[group.ID] = group
+	}
+	rows.Close()
+
+	if len(groups) == 0 {
+		return groups, nil
+	}
+
+	memberRows, err := r.db.QueryContext(ctx, `
+		SELECT m.group_id, m.member_user_id, COALESCE(u.name, ''), m.added_at
+		FROM contact_group_members m
+		JOIN contact_groups g ON g.id = m.group_id
+		LEFT JOIN users u ON u.id = m.member_user_id
+		WHERE g.user_id = $1
+		ORDER BY u.name
+	`, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query contact group members")
+	}
+	defer memberRows.Close()
+
+	for memberRows.Next() {
+		var groupID int64
+		var member models.ContactGroupMember
+		if err := memberRows.Scan(&groupID, &member.UserID, &member.Name, &member.AddedAt); err != nil {
+			return nil, errors.Wrap(err, "failed to scan contact group member")
+		}
+		byID[groupID].Members = append(byID[groupID].Members, &member)
+	}
+
+	return groups, nil
+}
+
+// GetByID returns one of the user's groups with its members, or nil if the user has no such group
+func (r *ContactGroups) GetByID(ctx context.Context, groupID int64, userID int64) (*models.ContactGroup, error) {
+	groups, err := r.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		if group.ID == groupID {
+			return group, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Create inserts a new contact group
+func (r *ContactGroups) Create(ctx context.Context, group *models.ContactGroup) error {
+	permissionsJSON, err := json.Marshal(group.Permissions)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal permissions")
+	}
+
+	query := `
+		INSERT INTO contact_groups (user_id, name, permissions)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query, group.UserID, group.Name, permissionsJSON).
+		Scan(&group.ID, &group.CreatedAt, &group.UpdatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to create contact group")
+	}
+	group.Members = []*models.ContactGroupMember{}
+
+	return nil
+}
+
+// Update renames a group and replaces its permission set
+func (r *ContactGroups) Update(ctx context.Context, group *models.ContactGroup) error {
+	permissionsJSON, err := json.Marshal(group.Permissions)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal permissions")
+	}
+
+	query := `
+		UPDATE contact_groups
+		SET name = $3, permissions = $4, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, group.ID, group.UserID, group.Name, permissionsJSON)
+	if err != nil {
+		return errors.Wrap(err, "failed to update contact group")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("contact group not found or user is not the owner")
+	}
+
+	return nil
+}
+
+// Delete removes a group along with its members and prices
+func (r *ContactGroups) Delete(ctx context.Context, groupID int64, userID int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM contact_groups WHERE id = $1 AND user_id = $2`, groupID, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete contact group")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("contact group not found or user is not the owner")
+	}
+
+	return nil
+}
+
+// GetNonContacts returns the IDs in userIDs that are not accepted contacts of ownerUserID
+func (r *ContactGroups) GetNonContacts(ctx context.Context, ownerUserID int64, userIDs []int64) ([]int64, error) {
+	query := `
+		SELECT u.user_id
+		FROM unnest($2::bigint[]) AS u(user_id)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM contacts c
+			WHERE c.status = 'accepted'
+				AND ((c.requester_user_id = $1 AND c.recipient_user_id = u.user_id)
+					OR (c.recipient_user_id = $1 AND c.requester_user_id = u.user_id))
+		)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, ownerUserID, pq.Array(userIDs))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query non-contacts")
+	}
+	defer rows.Close()
+
+	nonContacts := []int64{}
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, errors.Wrap(err, "failed to scan user ID")
+		}
+		nonContacts = append(nonContacts, userID)
+	}
+
+	return nonContacts, nil
+}
+
+// UpdateMembers adds and removes members of a group in one transaction
+func (r *ContactGroups) UpdateMembers(ctx context.Context, groupID int64, addUserIDs, removeUserIDs []int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	if len(addUserIDs) > 0 {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO contact_group_members (group_id, member_user_id)
+			SELECT $1, unnest($2::bigint[])
+			ON CONFLICT (group_id, member_user_id) DO NOTHING
+		`, groupID, pq.Array(addUserIDs))
+		if err != nil {
+			return errors.Wrap(err, "failed to add contact group members")
+		}
+	}
+
+	if len(removeUserIDs) > 0 {
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM contact_group_members
+			WHERE group_id = $1 AND member_user_id = ANY($2)
+		`, groupID, pq.Array(removeUserIDs))
+		if err != nil {
+			return errors.Wrap(err, "failed to remove contact group members")
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE contact_groups SET updated_at = NOW() WHERE id = $1`, groupID)
+	if err != nil {
+		return errors.Wrap(err, "failed to touch contact group")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// GetPrices returns a group's price list with the listed price of each listing
+func (r *ContactGroups) GetPrices(ctx context.Context, groupID int64) ([]*models.ContactGroupPrice, error) {
+	query := `
+		SELECT gp.group_id, gp.for_sale_item_id, t.type_name, f.price_per_unit, gp.price_per_unit
+		FROM contact_group_prices gp
+		JOIN for_sale_items f ON f.id = gp.for_sale_item_id
+		JOIN asset_item_types t ON t.type_id = f.type_id
+		WHERE gp.group_id = $1 AND f.is_active = true
+		ORDER BY t.type_name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, groupID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query contact group prices")
+	}
+	defer rows.Close()
+
+	prices := []*models.ContactGroupPrice{}
+	for rows.Next() {
+		var price models.ContactGroupPrice
+		err = rows.Scan(&price.GroupID, &price.ForSaleItemID, &price.TypeName, &price.ListPrice, &price.PricePerUnit)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan contact group price")
+		}
+		prices = append(prices, &price)
+	}
+
+	return prices, nil
+}
+
+// SetPrice sets a group's price for a listing. The listing must belong to the group's owner.
+func (r *ContactGroups) SetPrice(ctx context.Context, groupID int64, forSaleItemID int64, pricePerUnit float64) error {
+	query := `
+		INSERT INTO contact_group_prices (group_id, for_sale_item_id, price_per_unit)
+		SELECT g.id, f.id, $3
+		FROM contact_groups g
+		JOIN for_sale_items f ON f.user_id = g.user_id
+		WHERE g.id = $1 AND f.id = $2
+		ON CONFLICT (group_id, for_sale_item_id)
+		DO UPDATE SET
+			price_per_unit = EXCLUDED.price_per_unit,
+			updated_at = NOW()
+	`
+
+	result, err := r.db.ExecContext(ctx, query, groupID, forSaleItemID, pricePerUnit)
+	if err != nil {
+		return errors.Wrap(err, "failed to set contact group price")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("for-sale item not found or not owned by the group owner")
+	}
+
+	return nil
+}
+
+// DeletePrice removes a group's price for a listing so members pay the listed price
+func (r *ContactGroups) DeletePrice(ctx context.Context, groupID int64, forSaleItemID int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM contact_group_prices
+		WHERE group_id = $1 AND for_sale_item_id = $2
+	`, groupID, forSaleItemID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete contact group price")
+	}
+
+	return nil
+}
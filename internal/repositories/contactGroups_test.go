@@ -0,0 +1,136 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ContactGroups_GrantPermissionsAndPrices(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	sellerID := int64(3301)
+	memberID := int64(3300)
+	outsiderID := int64(3302)
+	strangerID := int64(3303)
+
+	item, err := setupPurchaseTestData(t, db, memberID, sellerID, 52, 30000162)
+	assert.NoError(t, err)
+
+	userRepo := repositories.NewUserRepository(db)
+	assert.NoError(t, userRepo.Add(ctx, &repositories.User{ID: outsiderID, Name: "Outsider"}))
+	assert.NoError(t, userRepo.Add(ctx, &repositories.User{ID: strangerID, Name: "Stranger"}))
+
+	contactsRepo := repositories.NewContacts(db)
+	for _, userID := range []int64{memberID, outsiderID} {
+		contact, err := contactsRepo.Create(ctx, userID, sellerID)
+		assert.NoError(t, err)
+		_, err = contactsRepo.UpdateStatus(ctx, contact.ID, sellerID, "accepted")
+		assert.NoError(t, err)
+	}
+
+	repo := repositories.NewContactGroups(db)
+	permRepo := repositories.NewContactPermissions(db)
+	forSaleRepo := repositories.NewForSaleItems(db)
+
+	nonContacts, err := repo.GetNonContacts(ctx, sellerID, []int64{memberID, outsiderID, strangerID})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{strangerID}, nonContacts)
+
+	group := &models.ContactGroup{UserID: sellerID, Name: "Inner circle", Permissions: []string{"for_sale_browse"}}
+	assert.NoError(t, repo.Create(ctx, group))
+	assert.NoError(t, repo.UpdateMembers(ctx, group.ID, []int64{memberID, outsiderID}, nil))
+	assert.NoError(t, repo.UpdateMembers(ctx, group.ID, nil, []int64{outsiderID}))
+
+	loaded, err := repo.GetByID(ctx, group.ID, sellerID)
+	assert.NoError(t, err)
+	assert.Len(t, loaded.Members, 1)
+	assert.Equal(t, memberID, loaded.Members[0].UserID)
+
+	other, err := repo.GetByID(ctx, group.ID, memberID)
+	assert.NoError(t, err)
+	assert.Nil(t, other)
+
+	// Group membership grants what the group grants, and nothing else
+	canBrowse, err := permRepo.CheckPermission(ctx, sellerID, memberID, "for_sale_browse")
+	assert.NoError(t, err)
+	assert.True(t, canBrowse)
+
+	canBrowse, err = permRepo.CheckPermission(ctx, sellerID, outsiderID, "for_sale_browse")
+	assert.NoError(t, err)
+	assert.False(t, canBrowse)
+
+	sellers, err := permRepo.GetUserPermissionsForService(ctx, memberID, "for_sale_browse")
+	assert.NoError(t, err)
+	assert.Contains(t, sellers, sellerID)
+
+	sellers, err = permRepo.GetUserPermissionsForService(ctx, memberID, "job_slot_browse")
+	assert.NoError(t, err)
+	assert.NotContains(t, sellers, sellerID)
+
+	// Group prices replace the listed price for members only
+	assert.NoError(t, repo.SetPrice(ctx, group.ID, item.ID, 80))
+	assert.Error(t, repo.SetPrice(ctx, group.ID, item.ID+1000000, 80))
+
+	prices, err := repo.GetPrices(ctx, group.ID)
+	assert.NoError(t, err)
+	assert.Len(t, prices, 1)
+	assert.Equal(t, float64(100), prices[0].ListPrice)
+	assert.Equal(t, float64(80), prices[0].PricePerUnit)
+
+	memberPrice, err := forSaleRepo.GetPriceForBuyer(ctx, item, memberID)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(80), memberPrice)
+
+	outsiderPrice, err := forSaleRepo.GetPriceForBuyer(ctx, item, outsiderID)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(100), outsiderPrice)
+
+	browsable, err := forSaleRepo.GetBrowsableItems(ctx, memberID, []int64{sellerID})
+	assert.NoError(t, err)
+	assert.Len(t, browsable, 1)
+	assert.Equal(t, float64(80), browsable[0].PricePerUnit)
+	assert.Equal(t, float64(100), *browsable[0].ListPricePerUnit)
+
+	// Auto-fulfill matches members on the group price, everyone else on the listed price
+	matching, err := forSaleRepo.GetMatchingForSaleItems(ctx, 52, 0, 90, memberID)
+	assert.NoError(t, err)
+	assert.Len(t, matching, 1)
+	assert.Equal(t, float64(100), matching[0].PricePerUnit)
+
+	matching, err = forSaleRepo.GetMatchingForSaleItems(ctx, 52, 0, 90, outsiderID)
+	assert.NoError(t, err)
+	assert.Empty(t, matching)
+
+	// A buyer's group lets its members see their demand
+	buyOrdersRepo := repositories.NewBuyOrders(db)
+	order := &models.BuyOrder{BuyerUserID: memberID, TypeID: 52, LocationID: 30000162, QuantityDesired: 10, MaxPricePerUnit: 90, IsActive: true}
+	assert.NoError(t, buyOrdersRepo.Create(ctx, order))
+
+	demand, err := buyOrdersRepo.GetDemandForSeller(ctx, sellerID)
+	assert.NoError(t, err)
+	assert.Empty(t, demand)
+
+	buyerGroup := &models.ContactGroup{UserID: memberID, Name: "Suppliers", Permissions: []string{"for_sale_browse"}}
+	assert.NoError(t, repo.Create(ctx, buyerGroup))
+	assert.NoError(t, repo.UpdateMembers(ctx, buyerGroup.ID, []int64{sellerID}, nil))
+
+	demand, err = buyOrdersRepo.GetDemandForSeller(ctx, sellerID)
+	assert.NoError(t, err)
+	assert.Len(t, demand, 1)
+
+	// Deleting the group drops its grants and prices
+	assert.NoError(t, repo.Delete(ctx, group.ID, sellerID))
+	memberPrice, err = forSaleRepo.GetPriceForBuyer(ctx, item, memberID)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(100), memberPrice)
+
+	canBrowse, err = permRepo.CheckPermission(ctx, sellerID, memberID, "for_sale_browse")
+	assert.NoError(t, err)
+	assert.False(t, canBrowse)
+}
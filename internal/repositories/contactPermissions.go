@@ -103,19 +103,24 @@ func (r *ContactPermissions) UpsertInTx(ctx context.Context, tx *sql.Tx, perm *m
 	return nil
 }
 
-// CheckPermission verifies if grantingUser allows receivingUser to access serviceType
+// CheckPermission verifies if grantingUser allows receivingUser to access serviceType, either
+// directly on the contact or through a contact group of grantingUser's that receivingUser is in
 func (r *ContactPermissions) CheckPermission(ctx context.Context, grantingUserID, receivingUserID int64, serviceType string) (bool, error) {
 	query := `
-		SELECT can_access
-		FROM contact_permissions
-		WHERE granting_user_id = $1 AND receiving_user_id = $2 AND service_type = $3
+		SELECT EXISTS (
+			SELECT 1
+			FROM contact_permissions
+			WHERE granting_user_id = $1 AND receiving_user_id = $2 AND service_type = $3 AND can_access = true
+		) OR EXISTS (
+			SELECT 1
+			FROM contact_groups g
+			JOIN contact_group_members m ON m.group_id = g.id
+			WHERE g.user_id = $1 AND m.member_user_id = $2 AND g.permissions ? $3
+		)
 	`
 
 	var canAccess bool
 	err := r.db.QueryRowContext(ctx, query, grantingUserID, receivingUserID, serviceType).Scan(&canAccess)
-	if err == sql.ErrNoRows {
-		return false, nil
-	}
 	if err != nil {
 		return false, errors.Wrap(err, "failed to check permission")
 	}
@@ -123,12 +128,18 @@ func (r *ContactPermissions) CheckPermission(ctx context.Context, grantingUserID
 	return canAccess, nil
 }
 
-// GetUserPermissionsForService returns all users who granted permission to viewerUserID for a specific service
+// GetUserPermissionsForService returns all users who granted permission to viewerUserID for a specific service,
+// directly or through one of their contact groups
 func (r *ContactPermissions) GetUserPermissionsForService(ctx context.Context, viewerUserID int64, serviceType string) ([]int64, error) {
 	query := `
 		SELECT granting_user_id
 		FROM contact_permissions
 		WHERE receiving_user_id = $1 AND service_type = $2 AND can_access = true
+		UNION
+		SELECT g.user_id
+		FROM contact_groups g
+		JOIN contact_group_members m ON m.group_id = g.id
+		WHERE m.member_user_id = $1 AND g.permissions ? $2
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, viewerUserID, serviceType)
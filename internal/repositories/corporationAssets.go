@@ -103,6 +103,7 @@ where
 	return nil
 }
 
+// GetAssembledContainers returns the item IDs of assembled containers and ships, the items that carry a player-given name.
 func (r *CorporationAssets) GetAssembledContainers(ctx context.Context, corp, user int64) ([]int64, error) {
 	query := `
 SELECT
@@ -115,7 +116,10 @@ ON
     assetTypes.type_id=corpAssets.type_id
 
 WHERE
-    assetTypes.type_name like '%Container' AND
+    (
+        assetTypes.type_name like '%Container'
+        OR EXISTS (SELECT 1 FROM sde_groups g WHERE g.group_id = assetTypes.group_id AND g.category_id = 6)
+    ) AND
     corporation_id=$1 AND
     user_id=$2 AND
     is_singleton=true;
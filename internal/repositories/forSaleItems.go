@@ -17,6 +17,39 @@ func NewForSaleItems(db *sql.DB) *ForSaleItems {
 	return &ForSaleItems{db: db}
 }
 
+// blueprintColumns receives the nullable blueprint attribute columns of a listing or purchase
+type blueprintColumns struct {
+	itemID sql.NullInt64
+	isCopy sql.NullBool
+	runs   sql.NullInt32
+	me     sql.NullInt32
+	te     sql.NullInt32
+}
+
+// blueprint returns the scanned attributes, or nil when the row is not for a blueprint item
+func (c *blueprintColumns) blueprint() *models.ListingBlueprint {
+	if !c.itemID.Valid {
+		return nil
+	}
+
+	return &models.ListingBlueprint{
+		ItemID:             c.itemID.Int64,
+		IsCopy:             c.isCopy.Bool,
+		Runs:               int(c.runs.Int32),
+		MaterialEfficiency: int(c.me.Int32),
+		TimeEfficiency:     int(c.te.Int32),
+	}
+}
+
+// blueprintArgs returns the values to store for a listing or purchase's blueprint attributes
+func blueprintArgs(bp *models.ListingBlueprint) (any, any, any, any, any) {
+	if bp == nil {
+		return nil, nil, nil, nil, nil
+	}
+
+	return bp.ItemID, bp.IsCopy, bp.Runs, bp.MaterialEfficiency, bp.TimeEfficiency
+}
+
 // GetUserIDByCharacterID converts a character ID to a user ID
 func (r *ForSaleItems) GetUserIDByCharacterID(ctx context.Context, characterID int64) (int64, error) {
 	query := `SELECT user_id FROM characters WHERE id = $1`
@@ -52,7 +85,12 @@ func (r *ForSaleItems) GetByUser(ctx context.Context, userID int64) ([]*models.F
 			f.auto_sell_container_id,
 			f.is_active,
 			f.created_at,
-			f.updated_at
+			f.updated_at,
+			f.blueprint_item_id,
+			f.blueprint_is_copy,
+			f.blueprint_runs,
+			f.blueprint_material_efficiency,
+			f.blueprint_time_efficiency
 		FROM for_sale_items f
 		JOIN asset_item_types t ON f.type_id = t.type_id
 		WHERE f.user_id = $1 AND f.is_active = true
@@ -68,6 +106,7 @@ func (r *ForSaleItems) GetByUser(ctx context.Context, userID int64) ([]*models.F
 	var items []*models.ForSaleItem
 	for rows.Next() {
 		var item models.ForSaleItem
+		var bp blueprintColumns
 		err = rows.Scan(
 			&item.ID,
 			&item.UserID,
@@ -87,10 +126,16 @@ func (r *ForSaleItems) GetByUser(ctx context.Context, userID int64) ([]*models.F
 			&item.IsActive,
 			&item.CreatedAt,
 			&item.UpdatedAt,
+			&bp.itemID,
+			&bp.isCopy,
+			&bp.runs,
+			&bp.me,
+			&bp.te,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan for-sale item")
 		}
+		item.Blueprint = bp.blueprint()
 		items = append(items, &item)
 	}
 
@@ -118,18 +163,30 @@ func (r *ForSaleItems) GetBrowsableItems(ctx context.Context, buyerUserID int64,
 			f.division_number,
 			f.quantity_available,
 			f.price_per_unit,
+			gp.price_per_unit,
 			f.notes,
 			f.auto_sell_container_id,
 			f.is_active,
 			f.created_at,
-			f.updated_at
+			f.updated_at,
+			f.blueprint_item_id,
+			f.blueprint_is_copy,
+			f.blueprint_runs,
+			f.blueprint_material_efficiency,
+			f.blueprint_time_efficiency
 		FROM for_sale_items f
 		JOIN asset_item_types t ON f.type_id = t.type_id
+		LEFT JOIN LATERAL (
+			SELECT MIN(p.price_per_unit) AS price_per_unit
+			FROM contact_group_prices p
+			JOIN contact_group_members m ON m.group_id = p.group_id
+			WHERE p.for_sale_item_id = f.id AND m.member_user_id = $2
+		) gp ON true
 		WHERE f.user_id = ANY($1) AND f.is_active = true
 		ORDER BY f.created_at DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, pq.Array(sellerUserIDs))
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(sellerUserIDs), buyerUserID)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to query browsable items")
 	}
@@ -138,6 +195,8 @@ func (r *ForSaleItems) GetBrowsableItems(ctx context.Context, buyerUserID int64,
 	var items []*models.ForSaleItem
 	for rows.Next() {
 		var item models.ForSaleItem
+		var groupPrice sql.NullFloat64
+		var bp blueprintColumns
 		err = rows.Scan(
 			&item.ID,
 			&item.UserID,
@@ -152,15 +211,28 @@ func (r *ForSaleItems) GetBrowsableItems(ctx context.Context, buyerUserID int64,
 			&item.DivisionNumber,
 			&item.QuantityAvailable,
 			&item.PricePerUnit,
+			&groupPrice,
 			&item.Notes,
 			&item.AutoSellContainerID,
 			&item.IsActive,
 			&item.CreatedAt,
 			&item.UpdatedAt,
+			&bp.itemID,
+			&bp.isCopy,
+			&bp.runs,
+			&bp.me,
+			&bp.te,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan browsable item")
 		}
+		item.Blueprint = bp.blueprint()
+		// The buyer sees their contact group's price in place of the listed one
+		if groupPrice.Valid {
+			listPrice := item.PricePerUnit
+			item.ListPricePerUnit = &listPrice
+			item.PricePerUnit = groupPrice.Float64
+		}
 		items = append(items, &item)
 	}
 
@@ -172,9 +244,10 @@ func (r *ForSaleItems) Upsert(ctx context.Context, item *models.ForSaleItem) err
 	query := `
 		INSERT INTO for_sale_items
 		(user_id, type_id, owner_type, owner_id, location_id, container_id, division_number,
-		 quantity_available, price_per_unit, notes, auto_sell_container_id, is_active, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW())
-		ON CONFLICT (user_id, type_id, owner_type, owner_id, location_id, COALESCE(container_id, 0), COALESCE(division_number, 0))
+		 quantity_available, price_per_unit, notes, auto_sell_container_id, is_active,
+		 blueprint_item_id, blueprint_is_copy, blueprint_runs, blueprint_material_efficiency, blueprint_time_efficiency, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, NOW())
+		ON CONFLICT (user_id, type_id, owner_type, owner_id, location_id, COALESCE(container_id, 0), COALESCE(division_number, 0), COALESCE(blueprint_item_id, 0))
 		WHERE is_active = true
 		DO UPDATE SET
 			quantity_available = EXCLUDED.quantity_available,
@@ -182,10 +255,15 @@ func (r *ForSaleItems) Upsert(ctx context.Context, item *models.ForSaleItem) err
 			notes = EXCLUDED.notes,
 			auto_sell_container_id = EXCLUDED.auto_sell_container_id,
 			is_active = EXCLUDED.is_active,
+			blueprint_is_copy = EXCLUDED.blueprint_is_copy,
+			blueprint_runs = EXCLUDED.blueprint_runs,
+			blueprint_material_efficiency = EXCLUDED.blueprint_material_efficiency,
+			blueprint_time_efficiency = EXCLUDED.blueprint_time_efficiency,
 			updated_at = NOW()
 		RETURNING id, created_at, updated_at
 	`
 
+	bpItemID, bpIsCopy, bpRuns, bpME, bpTE := blueprintArgs(item.Blueprint)
 	err := r.db.QueryRowContext(ctx, query,
 		item.UserID,
 		item.TypeID,
@@ -199,6 +277,11 @@ func (r *ForSaleItems) Upsert(ctx context.Context, item *models.ForSaleItem) err
 		item.Notes,
 		item.AutoSellContainerID,
 		item.IsActive,
+		bpItemID,
+		bpIsCopy,
+		bpRuns,
+		bpME,
+		bpTE,
 	).Scan(&item.ID, &item.CreatedAt, &item.UpdatedAt)
 
 	if err != nil {
@@ -286,13 +369,19 @@ func (r *ForSaleItems) GetByID(ctx context.Context, itemID int64) (*models.ForSa
 			f.auto_sell_container_id,
 			f.is_active,
 			f.created_at,
-			f.updated_at
+			f.updated_at,
+			f.blueprint_item_id,
+			f.blueprint_is_copy,
+			f.blueprint_runs,
+			f.blueprint_material_efficiency,
+			f.blueprint_time_efficiency
 		FROM for_sale_items f
 		JOIN asset_item_types t ON f.type_id = t.type_id
 		WHERE f.id = $1
 	`
 
 	var item models.ForSaleItem
+	var bp blueprintColumns
 	err := r.db.QueryRowContext(ctx, query, itemID).Scan(
 		&item.ID,
 		&item.UserID,
@@ -312,6 +401,11 @@ func (r *ForSaleItems) GetByID(ctx context.Context, itemID int64) (*models.ForSa
 		&item.IsActive,
 		&item.CreatedAt,
 		&item.UpdatedAt,
+		&bp.itemID,
+		&bp.isCopy,
+		&bp.runs,
+		&bp.me,
+		&bp.te,
 	)
 
 	if err == sql.ErrNoRows {
@@ -320,10 +414,34 @@ func (r *ForSaleItems) GetByID(ctx context.Context, itemID int64) (*models.ForSa
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get for-sale item")
 	}
+	item.Blueprint = bp.blueprint()
 
 	return &item, nil
 }
 
+// GetPriceForBuyer returns the unit price buyerUserID pays for a listing: the lowest price among the
+// seller's contact groups the buyer is in, or the listed price when none of them price it
+func (r *ForSaleItems) GetPriceForBuyer(ctx context.Context, item *models.ForSaleItem, buyerUserID int64) (float64, error) {
+	query := `
+		SELECT MIN(p.price_per_unit)
+		FROM contact_group_prices p
+		JOIN contact_group_members m ON m.group_id = p.group_id
+		WHERE p.for_sale_item_id = $1 AND m.member_user_id = $2
+	`
+
+	var groupPrice sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, query, item.ID, buyerUserID).Scan(&groupPrice)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get contact group price")
+	}
+
+	if groupPrice.Valid {
+		return groupPrice.Float64, nil
+	}
+
+	return item.PricePerUnit, nil
+}
+
 // GetActiveAutoSellListings returns all active listings tied to a specific auto-sell container
 func (r *ForSaleItems) GetActiveAutoSellListings(ctx context.Context, autoSellContainerID int64) ([]*models.ForSaleItem, error) {
 	query := `
@@ -394,9 +512,10 @@ func (r *ForSaleItems) DeactivateAutoSellListings(ctx context.Context, autoSellC
 	return nil
 }
 
-// GetMatchingForSaleItems finds active for-sale items matching a type_id with price in range,
-// excluding items owned by excludeUserID (to prevent self-purchase).
-func (r *ForSaleItems) GetMatchingForSaleItems(ctx context.Context, typeID int64, minPrice, maxPrice float64, excludeUserID int64) ([]*models.ForSaleItem, error) {
+// GetMatchingForSaleItems finds active for-sale items matching a type_id whose price for the buyer,
+// their contact group price where they have one, is in range. The buyer's own items are excluded
+// (to prevent self-purchase). Items carry their listed price.
+func (r *ForSaleItems) GetMatchingForSaleItems(ctx context.Context, typeID int64, minPrice, maxPrice float64, buyerUserID int64) ([]*models.ForSaleItem, error) {
 	query := `
 		SELECT
 			f.id,
@@ -416,18 +535,29 @@ func (r *ForSaleItems) GetMatchingForSaleItems(ctx context.Context, typeID int64
 			f.auto_sell_container_id,
 			f.is_active,
 			f.created_at,
-			f.updated_at
+			f.updated_at,
+			f.blueprint_item_id,
+			f.blueprint_is_copy,
+			f.blueprint_runs,
+			f.blueprint_material_efficiency,
+			f.blueprint_time_efficiency
 		FROM for_sale_items f
 		LEFT JOIN asset_item_types t ON f.type_id = t.type_id
+		LEFT JOIN LATERAL (
+			SELECT MIN(p.price_per_unit) AS price_per_unit
+			FROM contact_group_prices p
+			JOIN contact_group_members m ON m.group_id = p.group_id
+			WHERE p.for_sale_item_id = f.id AND m.member_user_id = $4
+		) gp ON true
 		WHERE f.type_id = $1
 			AND f.is_active = true
 			AND f.quantity_available > 0
-			AND f.price_per_unit >= $2
-			AND f.price_per_unit <= $3
+			AND COALESCE(gp.price_per_unit, f.price_per_unit) >= $2
+			AND COALESCE(gp.price_per_unit, f.price_per_unit) <= $3
 			AND f.user_id != $4
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, typeID, minPrice, maxPrice, excludeUserID)
+	rows, err := r.db.QueryContext(ctx, query, typeID, minPrice, maxPrice, buyerUserID)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to query matching for-sale items")
 	}
@@ -436,6 +566,7 @@ func (r *ForSaleItems) GetMatchingForSaleItems(ctx context.Context, typeID int64
 	items := []*models.ForSaleItem{}
 	for rows.Next() {
 		var item models.ForSaleItem
+		var bp blueprintColumns
 		err = rows.Scan(
 			&item.ID,
 			&item.UserID,
@@ -455,10 +586,16 @@ func (r *ForSaleItems) GetMatchingForSaleItems(ctx context.Context, typeID int64
 			&item.IsActive,
 			&item.CreatedAt,
 			&item.UpdatedAt,
+			&bp.itemID,
+			&bp.isCopy,
+			&bp.runs,
+			&bp.me,
+			&bp.te,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan matching for-sale item")
 		}
+		item.Blueprint = bp.blueprint()
 		items = append(items, &item)
 	}
 
@@ -227,3 +227,57 @@ func Test_ForSaleItemsUpdateQuantityPartial_ShouldUpdateQuantity(t *testing.T) {
 	assert.True(t, updated.IsActive)
 	assert.Equal(t, int64(250), updated.QuantityAvailable)
 }
+
+func Test_ForSaleItemsBlueprintListingsCarryAttributesIntoPurchases(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	setupForSaleTestData(t, db, 2600, 26000, 36, 30000147)
+	assert.NoError(t, repositories.NewUserRepository(db).Add(ctx, &repositories.User{ID: 2601, Name: "Buyer"}))
+	forSaleRepo := repositories.NewForSaleItems(db)
+	purchaseRepo := repositories.NewPurchaseTransactions(db)
+
+	// Two copies of the same blueprint in one hangar are separate listings
+	listings := []*models.ForSaleItem{}
+	for i, runs := range []int{10, 2} {
+		item := &models.ForSaleItem{
+			UserID:            2600,
+			TypeID:            36,
+			OwnerType:         "character",
+			OwnerID:           26000,
+			LocationID:        30000147,
+			QuantityAvailable: 1,
+			PricePerUnit:      1000000,
+			IsActive:          true,
+			Blueprint:         &models.ListingBlueprint{ItemID: int64(9100 + i), IsCopy: true, Runs: runs, MaterialEfficiency: 10, TimeEfficiency: 20},
+		}
+		assert.NoError(t, forSaleRepo.Upsert(ctx, item))
+		listings = append(listings, item)
+	}
+	assert.NotEqual(t, listings[0].ID, listings[1].ID)
+
+	loaded, err := forSaleRepo.GetByID(ctx, listings[1].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, listings[1].Blueprint, loaded.Blueprint)
+
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	purchase := &models.PurchaseTransaction{
+		ForSaleItemID:     loaded.ID,
+		BuyerUserID:       2601,
+		SellerUserID:      2600,
+		TypeID:            36,
+		QuantityPurchased: 1,
+		PricePerUnit:      1000000,
+		TotalPrice:        1000000,
+		Status:            "pending",
+		Blueprint:         loaded.Blueprint,
+	}
+	assert.NoError(t, purchaseRepo.Create(ctx, tx, purchase))
+	assert.NoError(t, tx.Commit())
+
+	stored, err := purchaseRepo.GetByID(ctx, purchase.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.ListingBlueprint{ItemID: 9101, IsCopy: true, Runs: 2, MaterialEfficiency: 10, TimeEfficiency: 20}, stored.Blueprint)
+}
@@ -71,6 +71,87 @@ func (r *HaulingAnalytics) GetRouteAnalytics(ctx context.Context, userID int64)
 	return results, nil
 }
 
+// GetLegAnalytics returns per-leg aggregated P&L stats for completed runs.
+// On a multi-stop run an item's P&L counts on the leg that delivers it to its
+// sell stop (the run's last stop when unassigned); a run without stops is one leg.
+func (r *HaulingAnalytics) GetLegAnalytics(ctx context.Context, userID int64) ([]*models.HaulingLegAnalytics, error) {
+	query := `
+		WITH stops AS (
+			SELECT s.id, s.run_id, s.seq, s.region_id
+			FROM hauling_run_stops s
+			JOIN hauling_runs hr ON hr.id = s.run_id
+			WHERE hr.user_id = $1 AND hr.status = 'COMPLETE'
+		),
+		last_stop AS (
+			SELECT run_id, MAX(seq) AS seq FROM stops GROUP BY run_id
+		),
+		-- One sell stop per (run, type): the latest one when a type is on several items
+		type_sell_stop AS (
+			SELECT i.run_id, i.type_id, MAX(COALESCE(ss.seq, ls.seq)) AS seq
+			FROM hauling_run_items i
+			JOIN last_stop ls ON ls.run_id = i.run_id
+			LEFT JOIN stops ss ON ss.id = i.sell_stop_id
+			GROUP BY i.run_id, i.type_id
+		),
+		legs AS (
+			SELECT
+				f.region_id AS from_region_id,
+				t.region_id AS to_region_id,
+				p.run_id,
+				SUM(p.net_profit_isk) AS profit,
+				SUM(p.total_revenue_isk) AS revenue
+			FROM hauling_run_pnl p
+			JOIN last_stop ls ON ls.run_id = p.run_id
+			LEFT JOIN type_sell_stop ts ON ts.run_id = p.run_id AND ts.type_id = p.type_id
+			JOIN stops t ON t.run_id = p.run_id AND t.seq = COALESCE(ts.seq, ls.seq)
+			JOIN stops f ON f.run_id = p.run_id AND f.seq = t.seq - 1
+			GROUP BY f.region_id, t.region_id, p.run_id, t.seq
+			UNION ALL
+			SELECT
+				hr.from_region_id,
+				hr.to_region_id,
+				hr.id,
+				SUM(p.net_profit_isk),
+				SUM(p.total_revenue_isk)
+			FROM hauling_runs hr
+			JOIN hauling_run_pnl p ON p.run_id = hr.id
+			WHERE hr.user_id = $1 AND hr.status = 'COMPLETE'
+			  AND NOT EXISTS (SELECT 1 FROM hauling_run_stops s WHERE s.run_id = hr.id)
+			GROUP BY hr.id, hr.from_region_id, hr.to_region_id
+		)
+		SELECT
+			from_region_id,
+			to_region_id,
+			COUNT(*) AS total_legs,
+			COUNT(DISTINCT run_id) AS total_runs,
+			COALESCE(SUM(profit), 0) AS total_profit,
+			COALESCE(AVG(profit), 0) AS avg_profit,
+			COALESCE(AVG(CASE WHEN revenue > 0 THEN profit / revenue * 100 ELSE 0 END), 0) AS avg_margin_pct
+		FROM legs
+		GROUP BY from_region_id, to_region_id
+		ORDER BY total_profit DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query leg analytics")
+	}
+	defer rows.Close()
+
+	results := []*models.HaulingLegAnalytics{}
+	for rows.Next() {
+		var row models.HaulingLegAnalytics
+		if err := rows.Scan(
+			&row.FromRegionID, &row.ToRegionID,
+			&row.TotalLegs, &row.TotalRuns,
+			&row.TotalProfitISK, &row.AvgProfitISK, &row.AvgMarginPct,
+		); err != nil {
+			return nil, errors.Wrap(err, "failed to scan leg analytics row")
+		}
+		results = append(results, &row)
+	}
+	return results, nil
+}
+
 // GetItemAnalytics returns per-item-type aggregated P&L stats for completed runs.
 func (r *HaulingAnalytics) GetItemAnalytics(ctx context.Context, userID int64) ([]*models.HaulingItemAnalytics, error) {
 	query := `
@@ -179,6 +179,90 @@ func Test_HaulingAnalytics_GetRouteAnalytics_ExcludesNonComplete(t *testing.T) {
 	assert.Len(t, results, 0)
 }
 
+// --- GetLegAnalytics ---
+
+func Test_HaulingAnalytics_GetLegAnalytics_WithData(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	userID := int64(9770)
+	userRepo := repositories.NewUserRepository(db)
+	err = userRepo.Add(ctx, &repositories.User{ID: userID, Name: "Analytics Leg User"})
+	assert.NoError(t, err)
+
+	runsRepo := repositories.NewHaulingRuns(db)
+	stopsRepo := repositories.NewHaulingRunStops(db)
+	itemsRepo := repositories.NewHaulingRunItems(db)
+	pnlRepo := repositories.NewHaulingRunPnl(db)
+
+	// Multi-stop run: Jita → Amarr → Dodixie
+	multi, err := runsRepo.CreateRun(ctx, &models.HaulingRun{
+		UserID: userID, Name: "Leg Run", Status: "PLANNING",
+		FromRegionID: int64(10000002), ToRegionID: int64(10000032),
+	})
+	assert.NoError(t, err)
+	stops, err := stopsRepo.ReplaceStops(ctx, multi.ID, []*models.HaulingRunStop{
+		{RegionID: int64(10000002)},
+		{RegionID: int64(10000043)},
+		{RegionID: int64(10000032)},
+	})
+	assert.NoError(t, err)
+
+	// Tritanium is sold at Amarr; Pyerite rides to the last stop
+	_, err = itemsRepo.AddItem(ctx, &models.HaulingRunItem{
+		RunID: multi.ID, TypeID: int64(34), TypeName: "Tritanium", QuantityPlanned: int64(1000),
+		SellStopID: &stops[1].ID,
+	})
+	assert.NoError(t, err)
+	_, err = itemsRepo.AddItem(ctx, &models.HaulingRunItem{
+		RunID: multi.ID, TypeID: int64(35), TypeName: "Pyerite", QuantityPlanned: int64(500),
+	})
+	assert.NoError(t, err)
+
+	rev1, cost1 := 200000.0, 100000.0
+	rev2, cost2 := 80000.0, 60000.0
+	assert.NoError(t, pnlRepo.UpsertPnlEntry(ctx, &models.HaulingRunPnlEntry{
+		RunID: multi.ID, TypeID: int64(34), QuantitySold: int64(100), TotalRevenueISK: &rev1, TotalCostISK: &cost1,
+	}))
+	assert.NoError(t, pnlRepo.UpsertPnlEntry(ctx, &models.HaulingRunPnlEntry{
+		RunID: multi.ID, TypeID: int64(35), QuantitySold: int64(50), TotalRevenueISK: &rev2, TotalCostISK: &cost2,
+	}))
+	assert.NoError(t, runsRepo.UpdateRunStatus(ctx, multi.ID, userID, "COMPLETE"))
+
+	// Single-leg run: Jita → Amarr
+	single, err := runsRepo.CreateRun(ctx, &models.HaulingRun{
+		UserID: userID, Name: "Single Run", Status: "PLANNING",
+		FromRegionID: int64(10000002), ToRegionID: int64(10000043),
+	})
+	assert.NoError(t, err)
+	rev3, cost3 := 100000.0, 50000.0
+	assert.NoError(t, pnlRepo.UpsertPnlEntry(ctx, &models.HaulingRunPnlEntry{
+		RunID: single.ID, TypeID: int64(34), QuantitySold: int64(10), TotalRevenueISK: &rev3, TotalCostISK: &cost3,
+	}))
+	assert.NoError(t, runsRepo.UpdateRunStatus(ctx, single.ID, userID, "COMPLETE"))
+
+	analyticsRepo := repositories.NewHaulingAnalytics(db)
+	results, err := analyticsRepo.GetLegAnalytics(ctx, userID)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	// Jita → Amarr: Tritanium leg of the multi-stop run plus the single-leg run
+	assert.Equal(t, int64(10000002), results[0].FromRegionID)
+	assert.Equal(t, int64(10000043), results[0].ToRegionID)
+	assert.Equal(t, int64(2), results[0].TotalLegs)
+	assert.Equal(t, int64(2), results[0].TotalRuns)
+	assert.Equal(t, 150000.0, results[0].TotalProfitISK)
+	assert.Equal(t, 75000.0, results[0].AvgProfitISK)
+
+	// Amarr → Dodixie: Pyerite delivered on the last leg
+	assert.Equal(t, int64(10000043), results[1].FromRegionID)
+	assert.Equal(t, int64(10000032), results[1].ToRegionID)
+	assert.Equal(t, int64(1), results[1].TotalLegs)
+	assert.Equal(t, 20000.0, results[1].TotalProfitISK)
+	assert.InDelta(t, 25.0, results[1].AvgMarginPct, 0.1)
+}
+
 // --- GetItemAnalytics ---
 
 func Test_HaulingAnalytics_GetItemAnalytics_Empty(t *testing.T) {
@@ -58,11 +58,12 @@ func (r *HaulingMarket) GetScannerResults(ctx context.Context, sourceRegionID in
 		SELECT
 			src.type_id,
 			COALESCE(it.type_name, src.type_id::text) as type_name,
-			it.volume as volume_m3,
+			COALESCE(it.packaged_volume, it.volume) as volume_m3,
 			src.sell_price as buy_price,
 			dst.buy_price as sell_price,
 			src.volume_available,
 			src.days_to_sell,
+			dst.avg_daily_volume,
 			src.updated_at
 		FROM hauling_market_snapshots src
 		JOIN hauling_market_snapshots dst ON dst.type_id = src.type_id AND dst.region_id = $3 AND dst.system_id = 0
@@ -82,11 +83,11 @@ func (r *HaulingMarket) GetScannerResults(ctx context.Context, sourceRegionID in
 		var row models.HaulingArbitrageRow
 		var volumeM3, buyPrice, sellPrice sql.NullFloat64
 		var volAvail sql.NullInt64
-		var daysToSell sql.NullFloat64
+		var daysToSell, destAvgDaily sql.NullFloat64
 		var updatedAt time.Time
 		if err := rows.Scan(
 			&row.TypeID, &row.TypeName, &volumeM3, &buyPrice, &sellPrice,
-			&volAvail, &daysToSell, &updatedAt,
+			&volAvail, &daysToSell, &destAvgDaily, &updatedAt,
 		); err != nil {
 			return nil, errors.Wrap(err, "failed to scan arbitrage row")
 		}
@@ -105,6 +106,9 @@ func (r *HaulingMarket) GetScannerResults(ctx context.Context, sourceRegionID in
 		if daysToSell.Valid {
 			row.DaysToSell = &daysToSell.Float64
 		}
+		if destAvgDaily.Valid {
+			row.DestAvgDailyVolume = &destAvgDaily.Float64
+		}
 		row.UpdatedAt = updatedAt.Format(time.RFC3339)
 		// Compute net profit and spread
 		if row.BuyPrice != nil && row.SellPrice != nil {
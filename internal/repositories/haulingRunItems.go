@@ -21,14 +21,15 @@ func NewHaulingRunItems(db *sql.DB) *HaulingRunItems {
 func (r *HaulingRunItems) AddItem(ctx context.Context, item *models.HaulingRunItem) (*models.HaulingRunItem, error) {
 	query := `
 		INSERT INTO hauling_run_items (run_id, type_id, type_name, quantity_planned, quantity_acquired,
-			buy_price_isk, sell_price_isk, volume_m3, character_id, notes)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+			buy_price_isk, sell_price_isk, volume_m3, character_id, notes, buy_stop_id, sell_stop_id)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
 		RETURNING id, created_at, updated_at`
 	var id int64
 	var createdAt, updatedAt time.Time
 	err := r.db.QueryRowContext(ctx, query,
 		item.RunID, item.TypeID, item.TypeName, item.QuantityPlanned, item.QuantityAcquired,
 		item.BuyPriceISK, item.SellPriceISK, item.VolumeM3, item.CharacterID, item.Notes,
+		item.BuyStopID, item.SellStopID,
 	).Scan(&id, &createdAt, &updatedAt)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to add hauling run item")
@@ -39,12 +40,50 @@ func (r *HaulingRunItems) AddItem(ctx context.Context, item *models.HaulingRunIt
 	return item, nil
 }
 
+// AddItems adds several items to a hauling run in one transaction.
+func (r *HaulingRunItems) AddItems(ctx context.Context, items []*models.HaulingRunItem) ([]*models.HaulingRunItem, error) {
+	if len(items) == 0 {
+		return items, nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO hauling_run_items (run_id, type_id, type_name, quantity_planned, quantity_acquired,
+			buy_price_isk, sell_price_isk, volume_m3, character_id, notes, buy_stop_id, sell_stop_id)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+		RETURNING id, created_at, updated_at`)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to prepare hauling run item insert")
+	}
+	defer stmt.Close()
+	for _, item := range items {
+		var createdAt, updatedAt time.Time
+		if err := stmt.QueryRowContext(ctx,
+			item.RunID, item.TypeID, item.TypeName, item.QuantityPlanned, item.QuantityAcquired,
+			item.BuyPriceISK, item.SellPriceISK, item.VolumeM3, item.CharacterID, item.Notes,
+			item.BuyStopID, item.SellStopID,
+		).Scan(&item.ID, &createdAt, &updatedAt); err != nil {
+			return nil, errors.Wrap(err, "failed to add hauling run item")
+		}
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		item.UpdatedAt = updatedAt.Format(time.RFC3339)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "failed to commit hauling run items")
+	}
+	return items, nil
+}
+
 // GetItemsByRunID returns all items for a hauling run, with computed fields.
 func (r *HaulingRunItems) GetItemsByRunID(ctx context.Context, runID int64) ([]*models.HaulingRunItem, error) {
 	query := `
 		SELECT id, run_id, type_id, type_name, quantity_planned, quantity_acquired,
 		       buy_price_isk, sell_price_isk, volume_m3, character_id, notes,
 		       sell_order_id, qty_sold, actual_sell_price_isk,
+		       buy_stop_id, sell_stop_id,
 		       created_at, updated_at
 		FROM hauling_run_items WHERE run_id=$1 ORDER BY created_at ASC`
 	rows, err := r.db.QueryContext(ctx, query, runID)
@@ -56,13 +95,14 @@ func (r *HaulingRunItems) GetItemsByRunID(ctx context.Context, runID int64) ([]*
 	for rows.Next() {
 		var item models.HaulingRunItem
 		var buyPrice, sellPrice, volumeM3, actualSellPrice sql.NullFloat64
-		var charID, sellOrderID sql.NullInt64
+		var charID, sellOrderID, buyStopID, sellStopID sql.NullInt64
 		var notes sql.NullString
 		var createdAt, updatedAt time.Time
 		if err := rows.Scan(
 			&item.ID, &item.RunID, &item.TypeID, &item.TypeName, &item.QuantityPlanned, &item.QuantityAcquired,
 			&buyPrice, &sellPrice, &volumeM3, &charID, &notes,
 			&sellOrderID, &item.QtySold, &actualSellPrice,
+			&buyStopID, &sellStopID,
 			&createdAt, &updatedAt,
 		); err != nil {
 			return nil, errors.Wrap(err, "failed to scan hauling run item")
@@ -88,6 +128,12 @@ func (r *HaulingRunItems) GetItemsByRunID(ctx context.Context, runID int64) ([]*
 		if actualSellPrice.Valid {
 			item.ActualSellPriceISK = &actualSellPrice.Float64
 		}
+		if buyStopID.Valid {
+			item.BuyStopID = &buyStopID.Int64
+		}
+		if sellStopID.Valid {
+			item.SellStopID = &sellStopID.Int64
+		}
 		item.CreatedAt = createdAt.Format(time.RFC3339)
 		item.UpdatedAt = updatedAt.Format(time.RFC3339)
 		// Computed fields
@@ -138,6 +184,22 @@ func (r *HaulingRunItems) UpdateItemActualSellPrice(ctx context.Context, itemID
 	return nil
 }
 
+// UpdateItemStops sets where an item is bought and sold along a multi-stop run.
+// Nil clears the assignment back to the run's first or last stop.
+func (r *HaulingRunItems) UpdateItemStops(ctx context.Context, itemID int64, runID int64, buyStopID, sellStopID *int64) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE hauling_run_items SET buy_stop_id=$1, sell_stop_id=$2, updated_at=NOW() WHERE id=$3 AND run_id=$4`,
+		buyStopID, sellStopID, itemID, runID)
+	if err != nil {
+		return errors.Wrap(err, "failed to update item stops")
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New("hauling run item not found")
+	}
+	return nil
+}
+
 // UpdateItemAcquired updates the quantity_acquired for an item.
 func (r *HaulingRunItems) UpdateItemAcquired(ctx context.Context, itemID int64, runID int64, quantityAcquired int64) error {
 	result, err := r.db.ExecContext(ctx,
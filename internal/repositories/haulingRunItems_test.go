@@ -543,3 +543,26 @@ func Test_HaulingRunItems_CascadeDeleteWithRun(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, items, 0)
 }
+
+func Test_HaulingRunItems_AddItems(t *testing.T) {
+	run, itemsRepo, cleanup := createHaulingRunForItems(t, int64(9190), "Item Batch User")
+	defer cleanup()
+
+	buyPrice := 5.0
+	volume := 0.01
+	items := []*models.HaulingRunItem{
+		{RunID: run.ID, TypeID: 34, TypeName: "Tritanium", QuantityPlanned: 100000, BuyPriceISK: &buyPrice, VolumeM3: &volume},
+		{RunID: run.ID, TypeID: 35, TypeName: "Pyerite", QuantityPlanned: 50000, BuyPriceISK: &buyPrice, VolumeM3: &volume},
+	}
+
+	added, err := itemsRepo.AddItems(context.Background(), items)
+	assert.NoError(t, err)
+	assert.Len(t, added, 2)
+	assert.NotZero(t, added[0].ID)
+	assert.NotZero(t, added[1].ID)
+
+	stored, err := itemsRepo.GetItemsByRunID(context.Background(), run.ID)
+	assert.NoError(t, err)
+	assert.Len(t, stored, 2)
+	assert.Equal(t, int64(100000), stored[0].QuantityPlanned)
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+type HaulingRunStops struct {
+	db *sql.DB
+}
+
+func NewHaulingRunStops(db *sql.DB) *HaulingRunStops {
+	return &HaulingRunStops{db: db}
+}
+
+// GetStopsByRunID returns a run's stops in route order.
+func (r *HaulingRunStops) GetStopsByRunID(ctx context.Context, runID int64) ([]*models.HaulingRunStop, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, run_id, seq, region_id, system_id, station_id, name, created_at
+		FROM hauling_run_stops WHERE run_id=$1 ORDER BY seq ASC`, runID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get hauling run stops")
+	}
+	defer rows.Close()
+
+	stops := []*models.HaulingRunStop{}
+	for rows.Next() {
+		var stop models.HaulingRunStop
+		var systemID, stationID sql.NullInt64
+		var name sql.NullString
+		var createdAt time.Time
+		if err := rows.Scan(
+			&stop.ID, &stop.RunID, &stop.Seq, &stop.RegionID, &systemID, &stationID, &name, &createdAt,
+		); err != nil {
+			return nil, errors.Wrap(err, "failed to scan hauling run stop")
+		}
+		if systemID.Valid {
+			stop.SystemID = &systemID.Int64
+		}
+		if stationID.Valid {
+			stop.StationID = &stationID.Int64
+		}
+		if name.Valid {
+			stop.Name = &name.String
+		}
+		stop.CreatedAt = createdAt.Format(time.RFC3339)
+		stops = append(stops, &stop)
+	}
+	return stops, nil
+}
+
+// ReplaceStops sets a run's stops to the given ordered list in one transaction.
+// Stops with an ID keep their identity (and item assignments) and are moved to
+// their new position; stops left out are deleted, which clears item assignments
+// to them. Seq is taken from the list order.
+func (r *HaulingRunStops) ReplaceStops(ctx context.Context, runID int64, stops []*models.HaulingRunStop) ([]*models.HaulingRunStop, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	keep := []int64{}
+	for _, stop := range stops {
+		if stop.ID != 0 {
+			keep = append(keep, stop.ID)
+		}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM hauling_run_stops WHERE run_id=$1 AND NOT (id = ANY($2))`,
+		runID, pq.Array(keep)); err != nil {
+		return nil, errors.Wrap(err, "failed to delete hauling run stops")
+	}
+
+	for i, stop := range stops {
+		stop.RunID = runID
+		stop.Seq = i
+		if stop.ID != 0 {
+			var createdAt time.Time
+			err := tx.QueryRowContext(ctx, `
+				UPDATE hauling_run_stops SET seq=$1, region_id=$2, system_id=$3, station_id=$4, name=$5
+				WHERE id=$6 AND run_id=$7
+				RETURNING created_at`,
+				stop.Seq, stop.RegionID, stop.SystemID, stop.StationID, stop.Name, stop.ID, runID,
+			).Scan(&createdAt)
+			if err == sql.ErrNoRows {
+				return nil, errors.New("hauling run stop not found")
+			}
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to update hauling run stop")
+			}
+			stop.CreatedAt = createdAt.Format(time.RFC3339)
+			continue
+		}
+
+		var createdAt time.Time
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO hauling_run_stops (run_id, seq, region_id, system_id, station_id, name)
+			VALUES ($1,$2,$3,$4,$5,$6)
+			RETURNING id, created_at`,
+			runID, stop.Seq, stop.RegionID, stop.SystemID, stop.StationID, stop.Name,
+		).Scan(&stop.ID, &createdAt); err != nil {
+			return nil, errors.Wrap(err, "failed to add hauling run stop")
+		}
+		stop.CreatedAt = createdAt.Format(time.RFC3339)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "failed to commit hauling run stops")
+	}
+	return stops, nil
+}
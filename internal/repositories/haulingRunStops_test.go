@@ -0,0 +1,136 @@
+package repositories_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+// createHaulingRunForStops is a helper to create a user and run for stop tests.
+func createHaulingRunForStops(t *testing.T, userID int64, userName string) (*sql.DB, *models.HaulingRun) {
+	t.Helper()
+	db, err := setupDatabase(t)
+	if err != nil {
+		t.Fatalf("failed to setup db: %v", err)
+	}
+
+	userRepo := repositories.NewUserRepository(db)
+	if err := userRepo.Add(context.Background(), &repositories.User{ID: userID, Name: userName}); err != nil {
+		t.Fatalf("failed to add user: %v", err)
+	}
+
+	runsRepo := repositories.NewHaulingRuns(db)
+	run, err := runsRepo.CreateRun(context.Background(), &models.HaulingRun{
+		UserID:       userID,
+		Name:         "Stop Test Run",
+		Status:       "PLANNING",
+		FromRegionID: int64(10000002),
+		ToRegionID:   int64(10000032),
+	})
+	if err != nil {
+		t.Fatalf("failed to create run: %v", err)
+	}
+	return db, run
+}
+
+func Test_HaulingRunStops_ReplaceStops(t *testing.T) {
+	db, run := createHaulingRunForStops(t, int64(9760), "Stops User")
+	stopsRepo := repositories.NewHaulingRunStops(db)
+	ctx := context.Background()
+
+	empty, err := stopsRepo.GetStopsByRunID(ctx, run.ID)
+	assert.NoError(t, err)
+	assert.Len(t, empty, 0)
+
+	jitaName := "Jita IV - Moon 4"
+	saved, err := stopsRepo.ReplaceStops(ctx, run.ID, []*models.HaulingRunStop{
+		{RegionID: int64(10000002), Name: &jitaName},
+		{RegionID: int64(10000043)},
+		{RegionID: int64(10000032)},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, saved, 3)
+	jita, amarr, dodixie := saved[0].ID, saved[1].ID, saved[2].ID
+	assert.NotZero(t, jita)
+	assert.Equal(t, 2, saved[2].Seq)
+
+	// Assign an item to the Amarr and Dodixie stops
+	itemsRepo := repositories.NewHaulingRunItems(db)
+	item, err := itemsRepo.AddItem(ctx, &models.HaulingRunItem{
+		RunID:           run.ID,
+		TypeID:          int64(34),
+		TypeName:        "Tritanium",
+		QuantityPlanned: int64(100),
+	})
+	assert.NoError(t, err)
+	err = itemsRepo.UpdateItemStops(ctx, item.ID, run.ID, &amarr, &dodixie)
+	assert.NoError(t, err)
+
+	// Swap Jita and Amarr, drop Dodixie, add Hek
+	reordered, err := stopsRepo.ReplaceStops(ctx, run.ID, []*models.HaulingRunStop{
+		{ID: amarr, RegionID: int64(10000043)},
+		{ID: jita, RegionID: int64(10000002), Name: &jitaName},
+		{RegionID: int64(10000042)},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, reordered, 3)
+
+	stops, err := stopsRepo.GetStopsByRunID(ctx, run.ID)
+	assert.NoError(t, err)
+	assert.Len(t, stops, 3)
+	assert.Equal(t, amarr, stops[0].ID)
+	assert.Equal(t, jita, stops[1].ID)
+	assert.Equal(t, jitaName, *stops[1].Name)
+	assert.Equal(t, int64(10000042), stops[2].RegionID)
+
+	// The dropped sell stop is cleared; the kept buy stop remains
+	items, err := itemsRepo.GetItemsByRunID(ctx, run.ID)
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, amarr, *items[0].BuyStopID)
+	assert.Nil(t, items[0].SellStopID)
+
+	// Clearing all stops turns the run back into a single leg
+	cleared, err := stopsRepo.ReplaceStops(ctx, run.ID, []*models.HaulingRunStop{})
+	assert.NoError(t, err)
+	assert.Len(t, cleared, 0)
+	items, err = itemsRepo.GetItemsByRunID(ctx, run.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, items[0].BuyStopID)
+}
+
+func Test_HaulingRunStops_ReplaceStops_ForeignStop(t *testing.T) {
+	db, run := createHaulingRunForStops(t, int64(9780), "Stops Foreign User")
+	stopsRepo := repositories.NewHaulingRunStops(db)
+	ctx := context.Background()
+
+	runsRepo := repositories.NewHaulingRuns(db)
+	other, err := runsRepo.CreateRun(ctx, &models.HaulingRun{
+		UserID:       int64(9780),
+		Name:         "Other Run",
+		Status:       "PLANNING",
+		FromRegionID: int64(10000002),
+		ToRegionID:   int64(10000043),
+	})
+	assert.NoError(t, err)
+	otherStops, err := stopsRepo.ReplaceStops(ctx, other.ID, []*models.HaulingRunStop{
+		{RegionID: int64(10000002)},
+		{RegionID: int64(10000043)},
+	})
+	assert.NoError(t, err)
+
+	_, err = stopsRepo.ReplaceStops(ctx, run.ID, []*models.HaulingRunStop{
+		{ID: otherStops[0].ID, RegionID: int64(10000002)},
+		{RegionID: int64(10000043)},
+	})
+	assert.Error(t, err)
+
+	// The other run's stops are untouched
+	stops, err := stopsRepo.GetStopsByRunID(ctx, other.ID)
+	assert.NoError(t, err)
+	assert.Len(t, stops, 2)
+}
@@ -69,10 +69,11 @@ func (r *HaulingStructures) GetStructureScannerResults(ctx context.Context, sour
 		SELECT
 			src.type_id,
 			COALESCE(it.type_name, src.type_id::text) as type_name,
-			it.volume as volume_m3,
+			COALESCE(it.packaged_volume, it.volume) as volume_m3,
 			src.sell_price as buy_price,
 			dst.buy_price as sell_price,
 			src.volume_available,
+			dst.avg_daily_volume,
 			src.updated_at
 		FROM hauling_structure_snapshots src
 		JOIN hauling_market_snapshots dst ON dst.type_id = src.type_id AND dst.region_id = $2 AND dst.system_id = $3
@@ -98,10 +99,11 @@ func (r *HaulingStructures) GetRegionToStructureResults(ctx context.Context, src
 		SELECT
 			src.type_id,
 			COALESCE(it.type_name, src.type_id::text) as type_name,
-			it.volume as volume_m3,
+			COALESCE(it.packaged_volume, it.volume) as volume_m3,
 			src.sell_price as buy_price,
 			dst.buy_price as sell_price,
 			src.volume_available,
+			dst.avg_daily_volume,
 			src.updated_at
 		FROM hauling_market_snapshots src
 		JOIN hauling_structure_snapshots dst ON dst.type_id = src.type_id AND dst.structure_id = $3
@@ -125,12 +127,12 @@ func scanArbitrageRows(rows *sql.Rows) ([]*models.HaulingArbitrageRow, error) {
 	results := []*models.HaulingArbitrageRow{}
 	for rows.Next() {
 		var row models.HaulingArbitrageRow
-		var volumeM3, buyPrice, sellPrice sql.NullFloat64
+		var volumeM3, buyPrice, sellPrice, destAvgDaily sql.NullFloat64
 		var volAvail sql.NullInt64
 		var updatedAt time.Time
 		if err := rows.Scan(
 			&row.TypeID, &row.TypeName, &volumeM3, &buyPrice, &sellPrice,
-			&volAvail, &updatedAt,
+			&volAvail, &destAvgDaily, &updatedAt,
 		); err != nil {
 			return nil, errors.Wrap(err, "failed to scan arbitrage row")
 		}
@@ -146,6 +148,9 @@ func scanArbitrageRows(rows *sql.Rows) ([]*models.HaulingArbitrageRow, error) {
 		if volAvail.Valid {
 			row.VolumeAvailable = &volAvail.Int64
 		}
+		if destAvgDaily.Valid {
+			row.DestAvgDailyVolume = &destAvgDaily.Float64
+		}
 		row.UpdatedAt = updatedAt.Format(time.RFC3339)
 		// Compute net profit and spread
 		if row.BuyPrice != nil && row.SellPrice != nil {
@@ -0,0 +1,258 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+type Invoices struct {
+	db *sql.DB
+}
+
+func NewInvoices(db *sql.DB) *Invoices {
+	return &Invoices{db: db}
+}
+
+const invoiceColumns = `
+	i.id,
+	i.issuer_user_id,
+	ui.name AS issuer_name,
+	i.payer_user_id,
+	up.name AS payer_name,
+	i.agreement_id,
+	i.reference_code,
+	i.description,
+	i.amount,
+	i.amount_paid,
+	i.due_at,
+	i.status,
+	i.paid_at,
+	i.reminded_at,
+	i.created_at,
+	i.updated_at
+`
+
+const invoiceJoins = `
+	FROM invoices i
+	JOIN users ui ON i.issuer_user_id = ui.id
+	JOIN users up ON i.payer_user_id = up.id
+`
+
+// Create inserts a new invoice
+func (r *Invoices) Create(ctx context.Context, invoice *models.Invoice) error {
+	query := `
+		INSERT INTO invoices
+		(issuer_user_id, payer_user_id, agreement_id, reference_code, description, amount, due_at, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, amount_paid, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		invoice.IssuerUserID,
+		invoice.PayerUserID,
+		invoice.AgreementID,
+		invoice.ReferenceCode,
+		invoice.Description,
+		invoice.Amount,
+		invoice.DueAt,
+		invoice.Status,
+	).Scan(&invoice.ID, &invoice.AmountPaid, &invoice.CreatedAt, &invoice.UpdatedAt)
+
+	if err != nil {
+		return errors.Wrap(err, "failed to create invoice")
+	}
+
+	return nil
+}
+
+// GetForUser returns invoices the user issued or has to pay, newest first, with their payments
+func (r *Invoices) GetForUser(ctx context.Context, userID int64) ([]*models.Invoice, error) {
+	query := `SELECT ` + invoiceColumns + invoiceJoins + `
+		WHERE i.issuer_user_id = $1 OR i.payer_user_id = $1
+		ORDER BY i.created_at DESC, i.id DESC
+	`
+
+	invoices, err := r.queryInvoices(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.attachPayments(ctx, invoices); err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+// GetOpen returns every unpaid, partially paid or overdue invoice across all users
+func (r *Invoices) GetOpen(ctx context.Context) ([]*models.Invoice, error) {
+	query := `SELECT ` + invoiceColumns + invoiceJoins + `
+		WHERE i.status IN ('unpaid', 'partial', 'overdue')
+		ORDER BY i.issuer_user_id, i.created_at
+	`
+
+	return r.queryInvoices(ctx, query)
+}
+
+func (r *Invoices) queryInvoices(ctx context.Context, query string, args ...any) ([]*models.Invoice, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query invoices")
+	}
+	defer rows.Close()
+
+	invoices := []*models.Invoice{}
+	for rows.Next() {
+		var invoice models.Invoice
+		err = rows.Scan(
+			&invoice.ID,
+			&invoice.IssuerUserID,
+			&invoice.IssuerName,
+			&invoice.PayerUserID,
+			&invoice.PayerName,
+			&invoice.AgreementID,
+			&invoice.ReferenceCode,
+			&invoice.Description,
+			&invoice.Amount,
+			&invoice.AmountPaid,
+			&invoice.DueAt,
+			&invoice.Status,
+			&invoice.PaidAt,
+			&invoice.RemindedAt,
+			&invoice.CreatedAt,
+			&invoice.UpdatedAt,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan invoice")
+		}
+		invoice.Payments = []*models.InvoicePayment{}
+		invoices = append(invoices, &invoice)
+	}
+
+	return invoices, nil
+}
+
+func (r *Invoices) attachPayments(ctx context.Context, invoices []*models.Invoice) error {
+	if len(invoices) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*models.Invoice, len(invoices))
+	ids := make([]int64, 0, len(invoices))
+	for _, invoice := range invoices {
+		byID[invoice.ID] = invoice
+		ids = append(ids, invoice.ID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, invoice_id, journal_ref_id, character_id, amount, paid_at
+		FROM invoice_payments
+		WHERE invoice_id = ANY($1)
+		ORDER BY paid_at
+	`, pq.Array(ids))
+	if err != nil {
+		return errors.Wrap(err, "failed to query invoice payments")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var payment models.InvoicePayment
+		if err := rows.Scan(&payment.ID, &payment.InvoiceID, &payment.JournalRefID, &payment.CharacterID, &payment.Amount, &payment.PaidAt); err != nil {
+			return errors.Wrap(err, "failed to scan invoice payment")
+		}
+		byID[payment.InvoiceID].Payments = append(byID[payment.InvoiceID].Payments, &payment)
+	}
+
+	return nil
+}
+
+// RecordPayment applies a wallet journal entry to an invoice and returns the
+// invoice's new amount paid. It returns false when the journal entry has
+// already been applied, so a transfer is never counted twice.
+func (r *Invoices) RecordPayment(ctx context.Context, payment *models.InvoicePayment) (float64, bool, error) {
+	query := `
+		WITH inserted AS (
+			INSERT INTO invoice_payments (invoice_id, journal_ref_id, character_id, amount, paid_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (journal_ref_id) DO NOTHING
+			RETURNING invoice_id, amount
+		)
+		UPDATE invoices i
+		SET amount_paid = i.amount_paid + inserted.amount, updated_at = NOW()
+		FROM inserted
+		WHERE i.id = inserted.invoice_id
+		RETURNING i.amount_paid
+	`
+
+	var amountPaid float64
+	err := r.db.QueryRowContext(ctx, query,
+		payment.InvoiceID,
+		payment.JournalRefID,
+		payment.CharacterID,
+		payment.Amount,
+		payment.PaidAt,
+	).Scan(&amountPaid)
+
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, errors.Wrap(err, "failed to record invoice payment")
+	}
+
+	return amountPaid, true, nil
+}
+
+// UpdateStatus sets the status of an open invoice. paidAt is stored as given.
+func (r *Invoices) UpdateStatus(ctx context.Context, invoiceID int64, status string, paidAt *time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE invoices
+		SET status = $2, paid_at = $3, updated_at = NOW()
+		WHERE id = $1 AND status IN ('unpaid', 'partial', 'overdue')
+	`, invoiceID, status, paidAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to update invoice status")
+	}
+
+	return nil
+}
+
+// MarkReminded records when the payer was last reminded about an invoice
+func (r *Invoices) MarkReminded(ctx context.Context, invoiceID int64, remindedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE invoices SET reminded_at = $2 WHERE id = $1
+	`, invoiceID, remindedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to mark invoice reminded")
+	}
+
+	return nil
+}
+
+// Cancel cancels an open invoice. Only the issuer can cancel.
+func (r *Invoices) Cancel(ctx context.Context, invoiceID int64, issuerUserID int64) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE invoices
+		SET status = 'cancelled', updated_at = NOW()
+		WHERE id = $1 AND issuer_user_id = $2 AND status IN ('unpaid', 'partial', 'overdue')
+	`, invoiceID, issuerUserID)
+	if err != nil {
+		return errors.Wrap(err, "failed to cancel invoice")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("invoice not found or not open")
+	}
+
+	return nil
+}
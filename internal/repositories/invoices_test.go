@@ -0,0 +1,97 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_InvoicesRecordPaymentsOnceAndCancel(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	issuerUserID := int64(8960)
+	payerUserID := int64(8961)
+
+	userRepo := repositories.NewUserRepository(db)
+	err = userRepo.Add(context.Background(), &repositories.User{ID: issuerUserID, Name: "Issuer User"})
+	assert.NoError(t, err)
+	err = userRepo.Add(context.Background(), &repositories.User{ID: payerUserID, Name: "Payer User"})
+	assert.NoError(t, err)
+
+	repo := repositories.NewInvoices(db)
+
+	invoice := &models.Invoice{
+		IssuerUserID:  issuerUserID,
+		PayerUserID:   payerUserID,
+		ReferenceCode: "INV-TEST01",
+		Description:   "Freighter run",
+		Amount:        100_000_000,
+		DueAt:         time.Now().AddDate(0, 0, 7),
+		Status:        "unpaid",
+	}
+	err = repo.Create(context.Background(), invoice)
+	assert.NoError(t, err)
+	assert.NotZero(t, invoice.ID)
+
+	payment := &models.InvoicePayment{
+		InvoiceID:    invoice.ID,
+		JournalRefID: 896001,
+		CharacterID:  89600,
+		Amount:       40_000_000,
+		PaidAt:       time.Now(),
+	}
+	amountPaid, recorded, err := repo.RecordPayment(context.Background(), payment)
+	assert.NoError(t, err)
+	assert.True(t, recorded)
+	assert.Equal(t, 40_000_000.0, amountPaid)
+
+	// The same journal entry seen again on the next run is not counted twice
+	amountPaid, recorded, err = repo.RecordPayment(context.Background(), payment)
+	assert.NoError(t, err)
+	assert.False(t, recorded)
+	assert.Equal(t, 0.0, amountPaid)
+
+	err = repo.UpdateStatus(context.Background(), invoice.ID, "partial", nil)
+	assert.NoError(t, err)
+
+	open, err := repo.GetOpen(context.Background())
+	assert.NoError(t, err)
+	var found *models.Invoice
+	for _, i := range open {
+		if i.ID == invoice.ID {
+			found = i
+		}
+	}
+	assert.NotNil(t, found)
+	assert.Equal(t, 40_000_000.0, found.AmountPaid)
+	assert.Equal(t, "partial", found.Status)
+
+	for _, userID := range []int64{issuerUserID, payerUserID} {
+		invoices, err := repo.GetForUser(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.Len(t, invoices, 1)
+		assert.Equal(t, "Issuer User", invoices[0].IssuerName)
+		assert.Equal(t, "Payer User", invoices[0].PayerName)
+		assert.Len(t, invoices[0].Payments, 1)
+		assert.Equal(t, int64(896001), invoices[0].Payments[0].JournalRefID)
+	}
+
+	// Only the issuer can cancel
+	err = repo.Cancel(context.Background(), invoice.ID, payerUserID)
+	assert.EqualError(t, err, "invoice not found or not open")
+
+	err = repo.Cancel(context.Background(), invoice.ID, issuerUserID)
+	assert.NoError(t, err)
+
+	invoices, err := repo.GetForUser(context.Background(), issuerUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, "cancelled", invoices[0].Status)
+
+	err = repo.Cancel(context.Background(), invoice.ID, issuerUserID)
+	assert.EqualError(t, err, "invoice not found or not open")
+}
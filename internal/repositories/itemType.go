@@ -135,7 +135,7 @@ func (r *ItemTypeRepository) SearchItemTypes(ctx context.Context, query string,
 	}
 
 	searchQuery := `
-		SELECT type_id, type_name, volume, icon_id
+		SELECT type_id, type_name, volume, packaged_volume, icon_id
 		FROM asset_item_types
 		WHERE LOWER(type_name) LIKE LOWER($1)
 		ORDER BY
@@ -157,7 +157,7 @@ func (r *ItemTypeRepository) SearchItemTypes(ctx context.Context, query string,
 	var items []models.EveInventoryType
 	for rows.Next() {
 		var item models.EveInventoryType
-		err := rows.Scan(&item.TypeID, &item.TypeName, &item.Volume, &item.IconID)
+		err := rows.Scan(&item.TypeID, &item.TypeName, &item.Volume, &item.PackagedVolume, &item.IconID)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan item type")
 		}
@@ -170,7 +170,7 @@ func (r *ItemTypeRepository) SearchItemTypes(ctx context.Context, query string,
 // GetItemTypeByName gets an exact item type by name
 func (r *ItemTypeRepository) GetItemTypeByName(ctx context.Context, typeName string) (*models.EveInventoryType, error) {
 	query := `
-		SELECT type_id, type_name, volume, icon_id
+		SELECT type_id, type_name, volume, packaged_volume, icon_id
 		FROM asset_item_types
 		WHERE type_name = $1
 	`
@@ -180,6 +180,7 @@ func (r *ItemTypeRepository) GetItemTypeByName(ctx context.Context, typeName str
 		&item.TypeID,
 		&item.TypeName,
 		&item.Volume,
+		&item.PackagedVolume,
 		&item.IconID,
 	)
 
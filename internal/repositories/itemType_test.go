@@ -71,3 +71,29 @@ func Test_ItemTypeShouldHandleEmptyUpsert(t *testing.T) {
 	err = itemTypeRepo.UpsertItemTypes(context.Background(), nil)
 	assert.NoError(t, err)
 }
+
+func Test_ItemTypeShouldReturnPackagedVolume(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	itemTypeRepo := repositories.NewItemTypeRepository(db)
+
+	packaged := 50000.0
+	itemTypes := []models.EveInventoryType{
+		{TypeID: 24698, TypeName: "Drake Packaged Volume Test", Volume: 252000, PackagedVolume: &packaged},
+		{TypeID: 24699, TypeName: "Drake Packaged Volume Test Blueprint", Volume: 0.01},
+	}
+	err = itemTypeRepo.UpsertItemTypes(context.Background(), itemTypes)
+	assert.NoError(t, err)
+
+	found, err := itemTypeRepo.SearchItemTypes(context.Background(), "Drake Packaged Volume Test", 20)
+	assert.NoError(t, err)
+	assert.Len(t, found, 2)
+	assert.Equal(t, 252000.0, found[0].Volume)
+	assert.Equal(t, &packaged, found[0].PackagedVolume)
+	assert.Nil(t, found[1].PackagedVolume)
+
+	item, err := itemTypeRepo.GetItemTypeByName(context.Background(), "Drake Packaged Volume Test")
+	assert.NoError(t, err)
+	assert.Equal(t, &packaged, item.PackagedVolume)
+}
@@ -33,8 +33,14 @@ func (r *JobQueue) Create(ctx context.Context, entry *models.IndustryJobQueueEnt
 		          created_at, updated_at
 	`
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
 	var created models.IndustryJobQueueEntry
-	err := r.db.QueryRowContext(ctx, query,
+	err = tx.QueryRowContext(ctx, query,
 		entry.UserID,
 		entry.CharacterID,
 		entry.BlueprintTypeID,
@@ -86,6 +92,15 @@ func (r *JobQueue) Create(ctx context.Context, entry *models.IndustryJobQueueEnt
 		return nil, errors.Wrap(err, "failed to create job queue entry")
 	}
 
+	created.Reservations, err = insertMaterialReservations(ctx, tx, &created, entry.Reservations)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "failed to commit job queue entry")
+	}
+
 	return &created, nil
 }
 
@@ -306,7 +321,8 @@ func (r *JobQueue) GetLinkedActiveJobs(ctx context.Context, userID int64) ([]*mo
 }
 
 // GetSlotUsage returns a nested map of characterID -> activity -> count for
-// all planned and active queue entries that have a character assigned.
+// all planned and active queue entries that have a character assigned, plus
+// any slots rented out under currently active job slot agreements.
 func (r *JobQueue) GetSlotUsage(ctx context.Context, userID int64) (map[int64]map[string]int, error) {
 	query := `
 		select character_id, activity, count(*) as slot_count
@@ -337,9 +353,28 @@ func (r *JobQueue) GetSlotUsage(ctx context.Context, userID int64) (map[int64]ma
 		result[characterID][activity] = count
 	}
 
+	rented, err := queryRentedSlotUsage(ctx, r.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	for characterID, byActivity := range rented {
+		if _, ok := result[characterID]; !ok {
+			result[characterID] = map[string]int{}
+		}
+		for activity, slots := range byActivity {
+			result[characterID][activity] += slots
+		}
+	}
+
 	return result, nil
 }
 
+// GetRentedSlotUsage returns characterID -> activity -> slots rented out under
+// currently active job slot agreements.
+func (r *JobQueue) GetRentedSlotUsage(ctx context.Context, userID int64) (map[int64]map[string]int, error) {
+	return queryRentedSlotUsage(ctx, r.db, userID)
+}
+
 // ReassignCharacter updates the character_id on a planned queue entry.
 // Pass nil for characterID to unassign. Returns an error if the entry is not
 // found, belongs to a different user, or is not in 'planned' status.
@@ -184,12 +184,19 @@ func (r *JobSlotRentals) CalculateSlotInventory(ctx context.Context, userID int6
 		reservedByChar[charID.Int64][activity] += count
 	}
 
-	// Get listed slots from job_slot_rental_listings
+	// Get listed slots from job_slot_rental_listings, less whatever is
+	// currently rented out under an agreement on the same listing
 	listingsQuery := `
-		SELECT character_id, activity_type, SUM(slots_listed)
-		FROM job_slot_rental_listings
-		WHERE user_id = $1 AND is_active = true
-		GROUP BY character_id, activity_type
+		SELECT l.character_id, l.activity_type, SUM(GREATEST(l.slots_listed - COALESCE(a.rented, 0), 0))
+		FROM job_slot_rental_listings l
+		LEFT JOIN (
+			SELECT listing_id, SUM(slots) AS rented
+			FROM job_slot_rental_agreements
+			WHERE status = 'active' AND starts_at <= NOW() AND ends_at > NOW()
+			GROUP BY listing_id
+		) a ON a.listing_id = l.id
+		WHERE l.user_id = $1 AND l.is_active = true
+		GROUP BY l.character_id, l.activity_type
 	`
 	listingRows, err := r.db.QueryContext(ctx, listingsQuery, userID)
 	if err != nil {
@@ -212,6 +219,39 @@ func (r *JobSlotRentals) CalculateSlotInventory(ctx context.Context, userID int6
 		listedByChar[charID][activityType] += slotsListed
 	}
 
+	// Get rented slots from active agreements. Renter jobs already running on
+	// the character show up as in-use, so only the idle part is deducted.
+	rentedQuery := `
+		SELECT a.character_id, a.activity_type, SUM(a.slots), SUM(GREATEST(a.slots - u.jobs_active, 0))
+		FROM job_slot_rental_agreements a
+		` + agreementUsageJoin + `
+		WHERE a.owner_user_id = $1 AND a.status = 'active' AND a.starts_at <= NOW() AND a.ends_at > NOW()
+		GROUP BY a.character_id, a.activity_type
+	`
+	rentedRows, err := r.db.QueryContext(ctx, rentedQuery, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query job slot agreements")
+	}
+	defer rentedRows.Close()
+
+	rentedByChar := make(map[int64]map[string]int)
+	rentedIdleByChar := make(map[int64]map[string]int)
+	for rentedRows.Next() {
+		var charID int64
+		var activityType string
+		var slots, idle int
+		if err := rentedRows.Scan(&charID, &activityType, &slots, &idle); err != nil {
+			return nil, errors.Wrap(err, "failed to scan agreement")
+		}
+
+		if rentedByChar[charID] == nil {
+			rentedByChar[charID] = make(map[string]int)
+			rentedIdleByChar[charID] = make(map[string]int)
+		}
+		rentedByChar[charID][activityType] += slots
+		rentedIdleByChar[charID][activityType] += idle
+	}
+
 	// Build inventory for each character
 	result := []*models.CharacterSlotInventory{}
 	for _, c := range chars {
@@ -228,15 +268,25 @@ func (r *JobSlotRentals) CalculateSlotInventory(ctx context.Context, userID int6
 		if listed == nil {
 			listed = make(map[string]int)
 		}
+		rented := rentedByChar[c.id]
+		if rented == nil {
+			rented = make(map[string]int)
+		}
+		rentedIdle := rentedIdleByChar[c.id]
+		if rentedIdle == nil {
+			rentedIdle = make(map[string]int)
+		}
 
 		// Science activities share a single slot pool
 		scienceInUse := inUse["te_research"] + inUse["me_research"] + inUse["copying"] + inUse["invention"]
 		scienceReserved := reserved["te_research"] + reserved["me_research"] + reserved["copying"] + reserved["invention"]
+		scienceRented := rented["te_research"] + rented["me_research"] + rented["copying"] + rented["invention"]
+		scienceRentedIdle := rentedIdle["te_research"] + rentedIdle["me_research"] + rentedIdle["copying"] + rentedIdle["invention"]
 
 		slotsByActivity := make(map[string]*models.ActivitySlotInfo)
 
 		// Manufacturing
-		mfgAvail := max.mfg - inUse["manufacturing"] - reserved["manufacturing"] - listed["manufacturing"]
+		mfgAvail := max.mfg - inUse["manufacturing"] - reserved["manufacturing"] - listed["manufacturing"] - rentedIdle["manufacturing"]
 		if mfgAvail < 0 {
 			mfgAvail = 0
 		}
@@ -247,10 +297,11 @@ func (r *JobSlotRentals) CalculateSlotInventory(ctx context.Context, userID int6
 			SlotsReserved:  reserved["manufacturing"],
 			SlotsAvailable: mfgAvail,
 			SlotsListed:    listed["manufacturing"],
+			SlotsRented:    rented["manufacturing"],
 		}
 
 		// Reaction
-		reactAvail := max.react - inUse["reaction"] - reserved["reaction"] - listed["reaction"]
+		reactAvail := max.react - inUse["reaction"] - reserved["reaction"] - listed["reaction"] - rentedIdle["reaction"]
 		if reactAvail < 0 {
 			reactAvail = 0
 		}
@@ -261,13 +312,14 @@ func (r *JobSlotRentals) CalculateSlotInventory(ctx context.Context, userID int6
 			SlotsReserved:  reserved["reaction"],
 			SlotsAvailable: reactAvail,
 			SlotsListed:    listed["reaction"],
+			SlotsRented:    rented["reaction"],
 		}
 
 		// Science activities - all share the same pool
 		sciActivities := []string{"te_research", "me_research", "copying", "invention"}
 		for _, act := range sciActivities {
 			sciListedTotal := listed["te_research"] + listed["me_research"] + listed["copying"] + listed["invention"]
-			sciAvail := max.science - scienceInUse - scienceReserved - sciListedTotal
+			sciAvail := max.science - scienceInUse - scienceReserved - sciListedTotal - scienceRentedIdle
 			if sciAvail < 0 {
 				sciAvail = 0
 			}
@@ -279,6 +331,7 @@ func (r *JobSlotRentals) CalculateSlotInventory(ctx context.Context, userID int6
 				SlotsReserved:  scienceReserved,
 				SlotsAvailable: sciAvail,
 				SlotsListed:    sciListedTotal,
+				SlotsRented:    scienceRented,
 			}
 		}
 
@@ -774,3 +827,198 @@ func (r *JobSlotRentals) GetReceivedInterests(ctx context.Context, userID int64)
 
 	return interests, nil
 }
+
+// agreementUsageJoin attaches ESI job usage to each agreement row aliased "a".
+// A job counts toward an agreement when the agreement's character installed it
+// for the agreed activity within the agreement window and the owner's job queue
+// has no entry linked to it.
+const agreementUsageJoin = `
+		LEFT JOIN LATERAL (
+			SELECT
+				COUNT(*) AS jobs_run,
+				COUNT(*) FILTER (WHERE j.status IN ('active', 'paused')) AS jobs_active,
+				COALESCE(SUM(j.runs), 0) AS runs_total
+			FROM esi_industry_jobs j
+			WHERE j.user_id = a.owner_user_id
+				AND j.installer_id = a.character_id
+				AND j.activity_id = CASE a.activity_type
+					WHEN 'manufacturing' THEN 1
+					WHEN 'te_research' THEN 3
+					WHEN 'me_research' THEN 4
+					WHEN 'copying' THEN 5
+					WHEN 'invention' THEN 8
+					WHEN 'reaction' THEN 9
+				END
+				AND j.start_date >= a.starts_at
+				AND j.start_date < a.ends_at
+				AND NOT EXISTS (
+					SELECT 1 FROM industry_job_queue q
+					WHERE q.user_id = a.owner_user_id AND q.esi_job_id = j.job_id
+				)
+		) u ON true
+`
+
+// CreateAgreement inserts a new rental agreement
+func (r *JobSlotRentals) CreateAgreement(ctx context.Context, agreement *models.JobSlotRentalAgreement) error {
+	query := `
+		INSERT INTO job_slot_rental_agreements
+		(listing_id, interest_id, owner_user_id, renter_user_id, character_id, activity_type, slots, starts_at, ends_at, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		agreement.ListingID,
+		agreement.InterestID,
+		agreement.OwnerUserID,
+		agreement.RenterUserID,
+		agreement.CharacterID,
+		agreement.ActivityType,
+		agreement.Slots,
+		agreement.StartsAt,
+		agreement.EndsAt,
+		agreement.Status,
+	).Scan(&agreement.ID, &agreement.CreatedAt, &agreement.UpdatedAt)
+
+	if err != nil {
+		return errors.Wrap(err, "failed to create job slot agreement")
+	}
+
+	return nil
+}
+
+// GetAgreementsForUser returns agreements where the user is either the slot
+// owner or the renter, with usage tracked from ESI industry jobs
+func (r *JobSlotRentals) GetAgreementsForUser(ctx context.Context, userID int64) ([]*models.JobSlotRentalAgreement, error) {
+	query := `
+		SELECT
+			a.id,
+			a.listing_id,
+			a.interest_id,
+			a.owner_user_id,
+			uo.name AS owner_name,
+			a.renter_user_id,
+			ur.name AS renter_name,
+			a.character_id,
+			COALESCE(c.name, '') AS character_name,
+			a.activity_type,
+			a.slots,
+			a.starts_at,
+			a.ends_at,
+			a.status,
+			a.created_at,
+			a.updated_at,
+			u.jobs_run,
+			u.jobs_active,
+			u.runs_total
+		FROM job_slot_rental_agreements a
+		JOIN users uo ON a.owner_user_id = uo.id
+		JOIN users ur ON a.renter_user_id = ur.id
+		LEFT JOIN characters c ON a.character_id = c.id AND c.user_id = a.owner_user_id
+		` + agreementUsageJoin + `
+		WHERE a.owner_user_id = $1 OR a.renter_user_id = $1
+		ORDER BY a.starts_at DESC, a.id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query job slot agreements")
+	}
+	defer rows.Close()
+
+	agreements := []*models.JobSlotRentalAgreement{}
+	for rows.Next() {
+		var agreement models.JobSlotRentalAgreement
+		err = rows.Scan(
+			&agreement.ID,
+			&agreement.ListingID,
+			&agreement.InterestID,
+			&agreement.OwnerUserID,
+			&agreement.OwnerName,
+			&agreement.RenterUserID,
+			&agreement.RenterName,
+			&agreement.CharacterID,
+			&agreement.CharacterName,
+			&agreement.ActivityType,
+			&agreement.Slots,
+			&agreement.StartsAt,
+			&agreement.EndsAt,
+			&agreement.Status,
+			&agreement.CreatedAt,
+			&agreement.UpdatedAt,
+			&agreement.JobsRun,
+			&agreement.JobsActive,
+			&agreement.RunsTotal,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan job slot agreement")
+		}
+		agreements = append(agreements, &agreement)
+	}
+
+	return agreements, nil
+}
+
+// CancelAgreement marks an active agreement as cancelled. Either the owner or
+// the renter may cancel.
+func (r *JobSlotRentals) CancelAgreement(ctx context.Context, agreementID int64, userID int64) error {
+	query := `
+		UPDATE job_slot_rental_agreements
+		SET status = 'cancelled', updated_at = NOW()
+		WHERE id = $1
+			AND status = 'active'
+			AND (owner_user_id = $2 OR renter_user_id = $2)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, agreementID, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to cancel job slot agreement")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("job slot agreement not found or user not authorized")
+	}
+
+	return nil
+}
+
+// queryRentedSlotUsage returns characterID -> activity -> slots rented out under
+// agreements that are active right now
+func queryRentedSlotUsage(ctx context.Context, db *sql.DB, userID int64) (map[int64]map[string]int, error) {
+	query := `
+		select character_id, activity_type, sum(slots)
+		from job_slot_rental_agreements
+		where owner_user_id = $1
+		  and status = 'active'
+		  and starts_at <= now()
+		  and ends_at > now()
+		group by character_id, activity_type
+	`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query rented slot usage")
+	}
+	defer rows.Close()
+
+	result := map[int64]map[string]int{}
+	for rows.Next() {
+		var characterID int64
+		var activity string
+		var slots int
+		if err := rows.Scan(&characterID, &activity, &slots); err != nil {
+			return nil, errors.Wrap(err, "failed to scan rented slot usage row")
+		}
+		if _, ok := result[characterID]; !ok {
+			result[characterID] = map[string]int{}
+		}
+		result[characterID][activity] += slots
+	}
+
+	return result, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/repositories"
@@ -464,3 +465,103 @@ func Test_JobSlotRentalsUpdateInterestStatus(t *testing.T) {
 	assert.Len(t, interests, 1)
 	assert.Equal(t, "accepted", interests[0].Status)
 }
+
+func Test_JobSlotRentalsAgreementsReserveSlotsAndTrackUsage(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	ownerUserID := int64(8950)
+	ownerCharID := int64(89500)
+	renterUserID := int64(8951)
+	setupJobSlotRentalTestData(t, db, ownerUserID, ownerCharID)
+
+	userRepo := repositories.NewUserRepository(db)
+	err = userRepo.Add(context.Background(), &repositories.User{ID: renterUserID, Name: "Renter User"})
+	assert.NoError(t, err)
+
+	repo := repositories.NewJobSlotRentals(db)
+	queueRepo := repositories.NewJobQueue(db)
+
+	locationID := int64(30000142)
+	listing := &models.JobSlotRentalListing{
+		UserID:       ownerUserID,
+		CharacterID:  ownerCharID,
+		ActivityType: "manufacturing",
+		SlotsListed:  3,
+		PriceAmount:  100000,
+		PricingUnit:  "per_slot_day",
+		LocationID:   &locationID,
+		IsActive:     true,
+	}
+	err = repo.Create(context.Background(), listing)
+	assert.NoError(t, err)
+
+	now := time.Now()
+	agreement := &models.JobSlotRentalAgreement{
+		ListingID:    listing.ID,
+		OwnerUserID:  ownerUserID,
+		RenterUserID: renterUserID,
+		CharacterID:  ownerCharID,
+		ActivityType: "manufacturing",
+		Slots:        2,
+		StartsAt:     now.Add(-time.Hour),
+		EndsAt:       now.AddDate(0, 0, 7),
+		Status:       "active",
+	}
+	err = repo.CreateAgreement(context.Background(), agreement)
+	assert.NoError(t, err)
+	assert.NotZero(t, agreement.ID)
+
+	// One renter job running inside the agreement window, one delivered job
+	// from before the agreement started
+	insertJob := func(jobID int64, status string, start time.Time) {
+		_, err := db.ExecContext(context.Background(), `
+			INSERT INTO esi_industry_jobs
+				(job_id, installer_id, user_id, facility_id, station_id, activity_id,
+				 blueprint_id, blueprint_type_id, blueprint_location_id, output_location_id,
+				 runs, status, duration, start_date, end_date)
+			VALUES ($1, $2, $3, 60003760, 60003760, 1, $1, 787, 60003760, 60003760, 10, $4, 3600, $5, $6)
+		`, jobID, ownerCharID, ownerUserID, status, start, start.Add(time.Hour))
+		assert.NoError(t, err)
+	}
+	insertJob(895001, "active", now.Add(-30*time.Minute))
+	insertJob(895002, "delivered", now.Add(-48*time.Hour))
+
+	agreements, err := repo.GetAgreementsForUser(context.Background(), renterUserID)
+	assert.NoError(t, err)
+	assert.Len(t, agreements, 1)
+	assert.Equal(t, "Test Character", agreements[0].CharacterName)
+	assert.Equal(t, "Test User", agreements[0].OwnerName)
+	assert.Equal(t, 1, agreements[0].JobsRun)
+	assert.Equal(t, 1, agreements[0].JobsActive)
+	assert.Equal(t, 10, agreements[0].RunsTotal)
+
+	// 11 max - 1 running renter job - 1 idle rented slot - 1 slot still listed
+	inventory, err := repo.CalculateSlotInventory(context.Background(), ownerUserID)
+	assert.NoError(t, err)
+	mfg := inventory[0].SlotsByActivity["manufacturing"]
+	assert.Equal(t, 2, mfg.SlotsRented)
+	assert.Equal(t, 1, mfg.SlotsListed)
+	assert.Equal(t, 8, mfg.SlotsAvailable)
+
+	usage, err := queueRepo.GetSlotUsage(context.Background(), ownerUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, usage[ownerCharID]["manufacturing"])
+
+	err = repo.CancelAgreement(context.Background(), agreement.ID, renterUserID)
+	assert.NoError(t, err)
+
+	err = repo.CancelAgreement(context.Background(), agreement.ID, renterUserID)
+	assert.Error(t, err)
+
+	inventory, err = repo.CalculateSlotInventory(context.Background(), ownerUserID)
+	assert.NoError(t, err)
+	mfg = inventory[0].SlotsByActivity["manufacturing"]
+	assert.Equal(t, 0, mfg.SlotsRented)
+	assert.Equal(t, 3, mfg.SlotsListed)
+	assert.Equal(t, 7, mfg.SlotsAvailable)
+
+	rented, err := queueRepo.GetRentedSlotUsage(context.Background(), ownerUserID)
+	assert.NoError(t, err)
+	assert.Empty(t, rented)
+}
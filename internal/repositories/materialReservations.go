@@ -0,0 +1,201 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type MaterialReservations struct {
+	db *sql.DB
+}
+
+func NewMaterialReservations(db *sql.DB) *MaterialReservations {
+	return &MaterialReservations{db: db}
+}
+
+// GetReservedQuantitiesForContext returns the active reserved quantity per type in an auto-sell
+// or for-sale scope: a container, or a hangar division when no container is given.
+func (r *MaterialReservations) GetReservedQuantitiesForContext(
+	ctx context.Context,
+	userID int64,
+	ownerType string, ownerID, locationID int64,
+	containerID *int64, divisionNumber *int,
+) (map[int64]int64, error) {
+	query := `
+		SELECT type_id, SUM(quantity)
+		FROM active_material_reservations
+		WHERE user_id = $1
+			AND owner_type = $2
+			AND owner_id = $3
+			AND location_id = $4
+			AND COALESCE(container_id, 0::bigint) = COALESCE($5::bigint, 0::bigint)
+			AND ($6::int IS NULL OR division_number = $6)
+		GROUP BY type_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, ownerType, ownerID, locationID, containerID, divisionNumber)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query reserved quantities")
+	}
+	defer rows.Close()
+
+	result := map[int64]int64{}
+	for rows.Next() {
+		var typeID, quantity int64
+		if err := rows.Scan(&typeID, &quantity); err != nil {
+			return nil, errors.Wrap(err, "failed to scan reserved quantity")
+		}
+		result[typeID] = quantity
+	}
+
+	return result, nil
+}
+
+// GetUnreservedQuantity returns how much of a for-sale item's type its seller holds in the
+// listing's scope beyond active reservations and purchases not yet delivered. The result can be
+// negative when reservations exceed the stock.
+func (r *MaterialReservations) GetUnreservedQuantity(ctx context.Context, item *models.ForSaleItem) (int64, error) {
+	query := `
+		SELECT
+			COALESCE(held.quantity, 0)
+			- reserved_material_quantity(m.user_id, m.owner_type, m.owner_id, m.location_id, m.container_id, m.division_number, m.type_id)
+			- COALESCE((
+				SELECT SUM(pt.quantity_purchased)
+				FROM purchase_transactions pt
+				JOIN for_sale_items f ON pt.for_sale_item_id = f.id
+				WHERE pt.seller_user_id = m.user_id
+					AND pt.type_id = m.type_id
+					AND (pt.status = 'pending'
+						OR (pt.status = 'contract_created'
+							AND pt.contract_created_at > NOW() - INTERVAL '1 hour')
+						OR (pt.status = 'completed'
+							AND pt.completed_at > NOW() - INTERVAL '1 hour'))
+					AND f.owner_type = m.owner_type
+					AND f.owner_id = m.owner_id
+					AND f.location_id = m.location_id
+					AND COALESCE(f.container_id, 0::bigint) = COALESCE(m.container_id, 0::bigint)
+					AND COALESCE(f.division_number, 0) = COALESCE(m.division_number, 0)
+			), 0)
+		FROM (
+			SELECT
+				$1::bigint AS user_id,
+				$2::text AS owner_type,
+				$3::bigint AS owner_id,
+				$4::bigint AS location_id,
+				$5::bigint AS container_id,
+				$6::int AS division_number,
+				$7::bigint AS type_id
+		) m
+		LEFT JOIN LATERAL (` + stockpileMarkerHeldQuantity + `) held ON TRUE
+	`
+
+	var quantity int64
+	err := r.db.QueryRowContext(ctx, query,
+		item.UserID,
+		item.OwnerType,
+		item.OwnerID,
+		item.LocationID,
+		item.ContainerID,
+		item.DivisionNumber,
+		item.TypeID,
+	).Scan(&quantity)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get unreserved quantity")
+	}
+
+	return quantity, nil
+}
+
+func insertMaterialReservations(ctx context.Context, tx *sql.Tx, entry *models.IndustryJobQueueEntry, reservations []*models.MaterialReservation) ([]*models.MaterialReservation, error) {
+	if len(reservations) == 0 {
+		return nil, nil
+	}
+	if entry.PlanRunID == nil {
+		return nil, errors.New("material reservations require a plan run")
+	}
+
+	query := `
+		INSERT INTO material_reservations
+			(user_id, plan_run_id, queue_entry_id, type_id, quantity,
+			 owner_type, owner_id, location_id, container_id, division_number)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at
+	`
+
+	created := make([]*models.MaterialReservation, 0, len(reservations))
+	for _, reservation := range reservations {
+		row := *reservation
+		row.UserID = entry.UserID
+		row.PlanRunID = *entry.PlanRunID
+		row.QueueEntryID = entry.ID
+		row.Active = entry.Status == "planned"
+
+		err := tx.QueryRowContext(ctx, query,
+			row.UserID,
+			row.PlanRunID,
+			row.QueueEntryID,
+			row.TypeID,
+			row.Quantity,
+			row.OwnerType,
+			row.OwnerID,
+			row.LocationID,
+			row.ContainerID,
+			row.DivisionNumber,
+		).Scan(&row.ID, &row.CreatedAt)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to insert material reservation")
+		}
+		created = append(created, &row)
+	}
+
+	return created, nil
+}
+
+func getPlanRunReservations(ctx context.Context, db *sql.DB, runID int64) ([]*models.MaterialReservation, error) {
+	query := `
+		SELECT r.id, r.user_id, r.plan_run_id, r.queue_entry_id, r.type_id, r.quantity,
+		       r.owner_type, r.owner_id, r.location_id, r.container_id, r.division_number,
+		       r.created_at, COALESCE(t.type_name, ''), q.status = 'planned'
+		FROM material_reservations r
+		JOIN industry_job_queue q ON q.id = r.queue_entry_id
+		LEFT JOIN asset_item_types t ON t.type_id = r.type_id
+		WHERE r.plan_run_id = $1
+		ORDER BY r.queue_entry_id, t.type_name, r.type_id
+	`
+
+	rows, err := db.QueryContext(ctx, query, runID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query plan run reservations")
+	}
+	defer rows.Close()
+
+	reservations := []*models.MaterialReservation{}
+	for rows.Next() {
+		var reservation models.MaterialReservation
+		err = rows.Scan(
+			&reservation.ID,
+			&reservation.UserID,
+			&reservation.PlanRunID,
+			&reservation.QueueEntryID,
+			&reservation.TypeID,
+			&reservation.Quantity,
+			&reservation.OwnerType,
+			&reservation.OwnerID,
+			&reservation.LocationID,
+			&reservation.ContainerID,
+			&reservation.DivisionNumber,
+			&reservation.CreatedAt,
+			&reservation.TypeName,
+			&reservation.Active,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan plan run reservation")
+		}
+		reservations = append(reservations, &reservation)
+	}
+
+	return reservations, nil
+}
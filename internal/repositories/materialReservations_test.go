@@ -0,0 +1,112 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MaterialReservationsShouldBeReleasedWithQueueEntry(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	userRepo := repositories.NewUserRepository(db)
+	plansRepo := repositories.NewProductionPlans(db)
+	runsRepo := repositories.NewPlanRuns(db)
+	queueRepo := repositories.NewJobQueue(db)
+	reservationsRepo := repositories.NewMaterialReservations(db)
+
+	user := &repositories.User{ID: 9310, Name: "Reservations User"}
+	err = userRepo.Add(ctx, user)
+	assert.NoError(t, err)
+
+	plan, err := plansRepo.Create(ctx, &models.ProductionPlan{
+		UserID:        user.ID,
+		ProductTypeID: 587,
+		Name:          "Reserved Plan",
+	})
+	assert.NoError(t, err)
+
+	run, err := runsRepo.Create(ctx, &models.ProductionPlanRun{
+		PlanID:   plan.ID,
+		UserID:   user.ID,
+		Quantity: 5,
+	})
+	assert.NoError(t, err)
+
+	containerID := int64(9000)
+	entry, err := queueRepo.Create(ctx, &models.IndustryJobQueueEntry{
+		UserID:          user.ID,
+		BlueprintTypeID: 787,
+		Activity:        "manufacturing",
+		Runs:            5,
+		PlanRunID:       &run.ID,
+		Reservations: []*models.MaterialReservation{
+			{TypeID: 34, Quantity: 1000, OwnerType: "character", OwnerID: 9311, LocationID: 60003760, ContainerID: &containerID},
+			{TypeID: 35, Quantity: 250, OwnerType: "character", OwnerID: 9311, LocationID: 60003760, ContainerID: &containerID},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, entry.Reservations, 2)
+	assert.NotZero(t, entry.Reservations[0].ID)
+
+	reserved, err := reservationsRepo.GetReservedQuantitiesForContext(ctx, user.ID, "character", 9311, 60003760, &containerID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int64{34: 1000, 35: 250}, reserved)
+
+	// A different container holds nothing reserved
+	otherContainerID := int64(9001)
+	reserved, err = reservationsRepo.GetReservedQuantitiesForContext(ctx, user.ID, "character", 9311, 60003760, &otherContainerID, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, reserved)
+
+	fetched, err := runsRepo.GetByID(ctx, run.ID, user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, fetched.Reservations, 2)
+	assert.True(t, fetched.Reservations[0].Active)
+
+	// Cancelling the entry releases its reservations
+	err = queueRepo.Cancel(ctx, entry.ID, user.ID)
+	assert.NoError(t, err)
+
+	reserved, err = reservationsRepo.GetReservedQuantitiesForContext(ctx, user.ID, "character", 9311, 60003760, &containerID, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, reserved)
+
+	fetched, err = runsRepo.GetByID(ctx, run.ID, user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, fetched.Reservations, 2)
+	assert.False(t, fetched.Reservations[0].Active)
+}
+
+func Test_MaterialReservationsShouldRequirePlanRun(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	userRepo := repositories.NewUserRepository(db)
+	queueRepo := repositories.NewJobQueue(db)
+
+	user := &repositories.User{ID: 9320, Name: "Reservations No Run User"}
+	err = userRepo.Add(ctx, user)
+	assert.NoError(t, err)
+
+	_, err = queueRepo.Create(ctx, &models.IndustryJobQueueEntry{
+		UserID:          user.ID,
+		BlueprintTypeID: 787,
+		Activity:        "manufacturing",
+		Runs:            1,
+		Reservations: []*models.MaterialReservation{
+			{TypeID: 34, Quantity: 100, OwnerType: "character", OwnerID: 9321, LocationID: 60003760},
+		},
+	})
+	assert.Error(t, err)
+
+	entries, err := queueRepo.GetByUser(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -291,6 +291,11 @@ func (r *PlanRuns) GetByID(ctx context.Context, runID, userID int64) (*models.Pr
 		run.Jobs = append(run.Jobs, &entry)
 	}
 
+	run.Reservations, err = getPlanRunReservations(ctx, r.db, runID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &run, nil
 }
 
@@ -564,7 +564,7 @@ func (r *ProductionPlans) GetStepMaterials(ctx context.Context, stepID, planID,
 			bm.type_id,
 			COALESCE(ait.type_name, '') as type_name,
 			bm.quantity,
-			COALESCE(ait.volume, 0) as volume,
+			COALESCE(ait.packaged_volume, ait.volume, 0) as volume,
 			CASE WHEN prod.blueprint_type_id IS NOT NULL THEN true ELSE false END as has_blueprint,
 			prod.blueprint_type_id,
 			prod.activity as blueprint_activity,
@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+type PurchaseCart struct {
+	db *sql.DB
+}
+
+func NewPurchaseCart(db *sql.DB) *PurchaseCart {
+	return &PurchaseCart{db: db}
+}
+
+// GetByUser returns the items in a buyer's cart with the current state of each listing,
+// grouped by seller. Prices are the buyer's contact group price where the seller set one.
+func (r *PurchaseCart) GetByUser(ctx context.Context, userID int64) ([]*models.PurchaseCartItem, error) {
+	query := `
+		SELECT
+			c.for_sale_item_id,
+			c.quantity,
+			c.added_at,
+			f.user_id,
+			COALESCE(u.name, ''),
+			f.type_id,
+			t.type_name,
+			f.location_id,
+			resolve_location_name(f.location_id) AS location_name,
+			COALESCE((
+				SELECT MIN(p.price_per_unit)
+				FROM contact_group_prices p
+				JOIN contact_group_members m ON m.group_id = p.group_id
+				WHERE p.for_sale_item_id = f.id AND m.member_user_id = c.user_id
+			), f.price_per_unit),
+			f.quantity_available,
+			f.is_active
+		FROM purchase_cart_items c
+		JOIN for_sale_items f ON f.id = c.for_sale_item_id
+		JOIN asset_item_types t ON t.type_id = f.type_id
+		LEFT JOIN users u ON u.id = f.user_id
+		WHERE c.user_id = $1
+		ORDER BY f.user_id, c.added_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query cart items")
+	}
+	defer rows.Close()
+
+	items := []*models.PurchaseCartItem{}
+	for rows.Next() {
+		var item models.PurchaseCartItem
+		err = rows.Scan(
+			&item.ForSaleItemID,
+			&item.Quantity,
+			&item.AddedAt,
+			&item.SellerUserID,
+			&item.SellerName,
+			&item.TypeID,
+			&item.TypeName,
+			&item.LocationID,
+			&item.LocationName,
+			&item.PricePerUnit,
+			&item.QuantityAvailable,
+			&item.IsActive,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan cart item")
+		}
+		items = append(items, &item)
+	}
+
+	return items, nil
+}
+
+// Upsert adds a for-sale item to a buyer's cart, replacing the quantity if it is already there.
+func (r *PurchaseCart) Upsert(ctx context.Context, userID, forSaleItemID, quantity int64) error {
+	query := `
+		INSERT INTO purchase_cart_items (user_id, for_sale_item_id, quantity)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, for_sale_item_id)
+		DO UPDATE SET quantity = EXCLUDED.quantity
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID, forSaleItemID, quantity)
+	if err != nil {
+		return errors.Wrap(err, "failed to upsert cart item")
+	}
+
+	return nil
+}
+
+// Remove deletes a for-sale item from a buyer's cart.
+func (r *PurchaseCart) Remove(ctx context.Context, userID, forSaleItemID int64) error {
+	query := `DELETE FROM purchase_cart_items WHERE user_id = $1 AND for_sale_item_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, userID, forSaleItemID)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove cart item")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("cart item not found")
+	}
+
+	return nil
+}
+
+// RemoveItems deletes checked-out items from a buyer's cart (within transaction).
+func (r *PurchaseCart) RemoveItems(ctx context.Context, tx *sql.Tx, userID int64, forSaleItemIDs []int64) error {
+	if len(forSaleItemIDs) == 0 {
+		return nil
+	}
+
+	query := `DELETE FROM purchase_cart_items WHERE user_id = $1 AND for_sale_item_id = ANY($2)`
+
+	_, err := tx.ExecContext(ctx, query, userID, pq.Array(forSaleItemIDs))
+	if err != nil {
+		return errors.Wrap(err, "failed to remove checked out cart items")
+	}
+
+	return nil
+}
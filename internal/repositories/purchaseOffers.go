@@ -0,0 +1,358 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+type PurchaseOffers struct {
+	db *sql.DB
+}
+
+func NewPurchaseOffers(db *sql.DB) *PurchaseOffers {
+	return &PurchaseOffers{db: db}
+}
+
+const purchaseOfferSelect = `
+	SELECT
+		o.id,
+		o.for_sale_item_id,
+		o.buy_order_id,
+		o.buyer_user_id,
+		COALESCE(buyer.name, ''),
+		o.seller_user_id,
+		COALESCE(seller.name, ''),
+		o.type_id,
+		t.type_name,
+		f.location_id,
+		COALESCE(resolve_location_name(f.location_id), ''),
+		o.quantity,
+		o.price_per_unit,
+		f.price_per_unit,
+		o.status,
+		o.awaiting_user_id,
+		o.purchase_id,
+		o.created_at,
+		o.updated_at
+	FROM purchase_offers o
+	JOIN for_sale_items f ON f.id = o.for_sale_item_id
+	JOIN asset_item_types t ON t.type_id = o.type_id
+	LEFT JOIN users buyer ON buyer.id = o.buyer_user_id
+	LEFT JOIN users seller ON seller.id = o.seller_user_id
+`
+
+// Create opens a new offer awaiting the other side, recording the opening round.
+func (r *PurchaseOffers) Create(ctx context.Context, offer *models.PurchaseOffer, message *string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO purchase_offers
+		(for_sale_item_id, buy_order_id, buyer_user_id, seller_user_id, type_id, quantity, price_per_unit, status, awaiting_user_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 'open', $8)
+		RETURNING id, status, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		offer.ForSaleItemID,
+		offer.BuyOrderID,
+		offer.BuyerUserID,
+		offer.SellerUserID,
+		offer.TypeID,
+		offer.Quantity,
+		offer.PricePerUnit,
+		offer.AwaitingUserID,
+	).Scan(&offer.ID, &offer.Status, &offer.CreatedAt, &offer.UpdatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to create purchase offer")
+	}
+
+	proposedBy := offer.BuyerUserID
+	if offer.AwaitingUserID != nil && *offer.AwaitingUserID == offer.BuyerUserID {
+		proposedBy = offer.SellerUserID
+	}
+	if err := addOfferRound(ctx, tx, offer.ID, proposedBy, "proposed", message); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit purchase offer")
+	}
+
+	return nil
+}
+
+// GetByID returns an offer with its rounds, or nil if it does not exist.
+func (r *PurchaseOffers) GetByID(ctx context.Context, offerID int64) (*models.PurchaseOffer, error) {
+	return r.getOne(ctx, purchaseOfferSelect+` WHERE o.id = $1`, offerID)
+}
+
+// GetByPurchaseID returns the offer a purchase was negotiated through, or nil if it was bought
+// at the listed price.
+func (r *PurchaseOffers) GetByPurchaseID(ctx context.Context, purchaseID int64) (*models.PurchaseOffer, error) {
+	return r.getOne(ctx, purchaseOfferSelect+` WHERE o.purchase_id = $1`, purchaseID)
+}
+
+// GetByUser returns the offers the user is buying or selling in, most recently active first.
+func (r *PurchaseOffers) GetByUser(ctx context.Context, userID int64) ([]*models.PurchaseOffer, error) {
+	query := purchaseOfferSelect + `
+		WHERE o.buyer_user_id = $1 OR o.seller_user_id = $1
+		ORDER BY o.updated_at DESC, o.id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query purchase offers")
+	}
+	defer rows.Close()
+
+	offers := []*models.PurchaseOffer{}
+	for rows.Next() {
+		offer, err := scanPurchaseOffer(rows)
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, offer)
+	}
+
+	if err := r.attachRounds(ctx, offers); err != nil {
+		return nil, err
+	}
+
+	return offers, nil
+}
+
+// Counter replaces the terms of an open offer awaiting userID and hands it back to the other side.
+// Returns false if the offer is no longer open or not awaiting this user.
+func (r *PurchaseOffers) Counter(ctx context.Context, offerID, userID, awaitingUserID, quantity int64, pricePerUnit float64, message *string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE purchase_offers
+		SET quantity = $3,
+			price_per_unit = $4,
+			awaiting_user_id = $5,
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'open' AND awaiting_user_id = $2
+	`, offerID, userID, quantity, pricePerUnit, awaitingUserID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to counter purchase offer")
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get rows affected")
+	}
+	if updated == 0 {
+		return false, nil
+	}
+
+	if err := addOfferRound(ctx, tx, offerID, userID, "countered", message); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, errors.Wrap(err, "failed to commit counter offer")
+	}
+
+	return true, nil
+}
+
+// Close ends an open offer as rejected or withdrawn. A rejection must come from the user the
+// offer is awaiting, a withdrawal from the side that made the current terms. Returns false if
+// the offer is no longer open or the user may not close it that way.
+func (r *PurchaseOffers) Close(ctx context.Context, offerID, userID int64, status string, message *string) (bool, error) {
+	var awaitingClause string
+	var action string
+	switch status {
+	case "rejected":
+		awaitingClause = "awaiting_user_id = $2"
+		action = "rejected"
+	case "withdrawn":
+		awaitingClause = "awaiting_user_id <> $2 AND $2 IN (buyer_user_id, seller_user_id)"
+		action = "withdrawn"
+	default:
+		return false, errors.Errorf("cannot close offer as %s", status)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE purchase_offers
+		SET status = $3,
+			awaiting_user_id = NULL,
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'open' AND `+awaitingClause, offerID, userID, status)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to close purchase offer")
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get rows affected")
+	}
+	if updated == 0 {
+		return false, nil
+	}
+
+	if err := addOfferRound(ctx, tx, offerID, userID, action, message); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, errors.Wrap(err, "failed to commit offer close")
+	}
+
+	return true, nil
+}
+
+// Accept marks an open offer awaiting userID as accepted and links the purchase it became
+// (within transaction). Returns false if the offer is no longer open or not awaiting this user.
+func (r *PurchaseOffers) Accept(ctx context.Context, tx *sql.Tx, offerID, userID, purchaseID int64, message *string) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE purchase_offers
+		SET status = 'accepted',
+			awaiting_user_id = NULL,
+			purchase_id = $3,
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'open' AND awaiting_user_id = $2
+	`, offerID, userID, purchaseID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to accept purchase offer")
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get rows affected")
+	}
+	if updated == 0 {
+		return false, nil
+	}
+
+	if err := addOfferRound(ctx, tx, offerID, userID, "accepted", message); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// addOfferRound records a step in the offer's history at its current terms.
+func addOfferRound(ctx context.Context, tx *sql.Tx, offerID, userID int64, action string, message *string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO purchase_offer_rounds (offer_id, user_id, action, quantity, price_per_unit, message)
+		SELECT id, $2, $3, quantity, price_per_unit, $4
+		FROM purchase_offers
+		WHERE id = $1
+	`, offerID, userID, action, message)
+	if err != nil {
+		return errors.Wrap(err, "failed to record offer round")
+	}
+	return nil
+}
+
+func (r *PurchaseOffers) getOne(ctx context.Context, query string, arg int64) (*models.PurchaseOffer, error) {
+	offer, err := scanPurchaseOffer(r.db.QueryRowContext(ctx, query, arg))
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.attachRounds(ctx, []*models.PurchaseOffer{offer}); err != nil {
+		return nil, err
+	}
+
+	return offer, nil
+}
+
+func (r *PurchaseOffers) attachRounds(ctx context.Context, offers []*models.PurchaseOffer) error {
+	if len(offers) == 0 {
+		return nil
+	}
+
+	byID := map[int64]*models.PurchaseOffer{}
+	offerIDs := make([]int64, 0, len(offers))
+	for _, offer := range offers {
+		offer.Rounds = []*models.PurchaseOfferRound{}
+		byID[offer.ID] = offer
+		offerIDs = append(offerIDs, offer.ID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT offer_id, id, user_id, action, quantity, price_per_unit, message, created_at
+		FROM purchase_offer_rounds
+		WHERE offer_id = ANY($1)
+		ORDER BY offer_id, id
+	`, pq.Array(offerIDs))
+	if err != nil {
+		return errors.Wrap(err, "failed to query offer rounds")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var offerID int64
+		var round models.PurchaseOfferRound
+		err = rows.Scan(
+			&offerID,
+			&round.ID,
+			&round.UserID,
+			&round.Action,
+			&round.Quantity,
+			&round.PricePerUnit,
+			&round.Message,
+			&round.CreatedAt,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to scan offer round")
+		}
+		offer := byID[offerID]
+		offer.Rounds = append(offer.Rounds, &round)
+	}
+
+	return nil
+}
+
+func scanPurchaseOffer(row rowScanner) (*models.PurchaseOffer, error) {
+	var offer models.PurchaseOffer
+	err := row.Scan(
+		&offer.ID,
+		&offer.ForSaleItemID,
+		&offer.BuyOrderID,
+		&offer.BuyerUserID,
+		&offer.BuyerName,
+		&offer.SellerUserID,
+		&offer.SellerName,
+		&offer.TypeID,
+		&offer.TypeName,
+		&offer.LocationID,
+		&offer.LocationName,
+		&offer.Quantity,
+		&offer.PricePerUnit,
+		&offer.ListPrice,
+		&offer.Status,
+		&offer.AwaitingUserID,
+		&offer.PurchaseID,
+		&offer.CreatedAt,
+		&offer.UpdatedAt,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to scan purchase offer")
+	}
+	return &offer, nil
+}
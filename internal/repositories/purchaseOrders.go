@@ -0,0 +1,282 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+type PurchaseOrders struct {
+	db *sql.DB
+}
+
+func NewPurchaseOrders(db *sql.DB) *PurchaseOrders {
+	return &PurchaseOrders{db: db}
+}
+
+// purchaseOrderSelect derives an order's status and total from its purchases: cancelled when
+// every purchase is cancelled, expired when every purchase was cancelled or expired, disputed when any contract failed verification, completed when
+// none is still open, contract_created once any purchase has a contract, otherwise pending.
+const purchaseOrderSelect = `
+	SELECT
+		o.id,
+		o.buyer_user_id,
+		COALESCE(buyer.name, ''),
+		o.seller_user_id,
+		COALESCE(seller.name, ''),
+		o.contract_key,
+		o.notes,
+		o.created_at,
+		CASE
+			WHEN counts.total = counts.cancelled THEN 'cancelled'
+			WHEN counts.total = counts.cancelled + counts.expired THEN 'expired'
+			WHEN counts.disputed > 0 THEN 'disputed'
+			WHEN counts.pending + counts.contract_created = 0 THEN 'completed'
+			WHEN counts.contract_created > 0 THEN 'contract_created'
+			ELSE 'pending'
+		END,
+		counts.total_price
+	FROM purchase_orders o
+	LEFT JOIN users buyer ON buyer.id = o.buyer_user_id
+	LEFT JOIN users seller ON seller.id = o.seller_user_id
+	JOIN LATERAL (
+		SELECT
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE pt.status = 'pending') AS pending,
+			COUNT(*) FILTER (WHERE pt.status = 'contract_created') AS contract_created,
+			COUNT(*) FILTER (WHERE pt.status = 'cancelled') AS cancelled,
+			COUNT(*) FILTER (WHERE pt.status = 'disputed') AS disputed,
+			COUNT(*) FILTER (WHERE pt.status = 'expired') AS expired,
+			COALESCE(SUM(pt.total_price) FILTER (WHERE pt.status NOT IN ('cancelled', 'expired')), 0) AS total_price
+		FROM purchase_transactions pt
+		WHERE pt.order_id = o.id
+	) counts ON TRUE
+`
+
+// Create records a new purchase order (within transaction)
+func (r *PurchaseOrders) Create(ctx context.Context, tx *sql.Tx, order *models.PurchaseOrder) error {
+	query := `
+		INSERT INTO purchase_orders (buyer_user_id, seller_user_id, notes)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+
+	err := tx.QueryRowContext(ctx, query,
+		order.BuyerUserID,
+		order.SellerUserID,
+		order.Notes,
+	).Scan(&order.ID, &order.CreatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to create purchase order")
+	}
+
+	return nil
+}
+
+// GetByUser returns the orders the user bought or sold, newest first, with their purchases.
+func (r *PurchaseOrders) GetByUser(ctx context.Context, userID int64) ([]*models.PurchaseOrder, error) {
+	query := purchaseOrderSelect + `
+		WHERE o.buyer_user_id = $1 OR o.seller_user_id = $1
+		ORDER BY o.created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query purchase orders")
+	}
+	defer rows.Close()
+
+	orders := []*models.PurchaseOrder{}
+	for rows.Next() {
+		order, err := scanPurchaseOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	if err := r.attachPurchases(ctx, orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// GetByID returns an order with its purchases, or nil if it does not exist.
+func (r *PurchaseOrders) GetByID(ctx context.Context, orderID int64) (*models.PurchaseOrder, error) {
+	query := purchaseOrderSelect + `
+		WHERE o.id = $1
+	`
+
+	order, err := scanPurchaseOrder(r.db.QueryRowContext(ctx, query, orderID))
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.attachPurchases(ctx, []*models.PurchaseOrder{order}); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// MarkContractCreated sets the order's contract key and moves all of its pending purchases to
+// contract_created under that key. Returns the number of purchases updated.
+func (r *PurchaseOrders) MarkContractCreated(ctx context.Context, orderID int64, contractKey string) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `UPDATE purchase_orders SET contract_key = $2 WHERE id = $1`, orderID, contractKey)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to update order contract key")
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE purchase_transactions
+		SET status = 'contract_created',
+			contract_created_at = NOW(),
+			contract_key = $2
+		WHERE order_id = $1 AND status = 'pending'
+	`, orderID, contractKey)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to mark order purchases contract_created")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, errors.Wrap(err, "failed to commit order contract")
+	}
+
+	return rowsAffected, nil
+}
+
+func (r *PurchaseOrders) attachPurchases(ctx context.Context, orders []*models.PurchaseOrder) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := map[int64]*models.PurchaseOrder{}
+	orderIDs := make([]int64, 0, len(orders))
+	for _, order := range orders {
+		order.Purchases = []*models.PurchaseTransaction{}
+		byID[order.ID] = order
+		orderIDs = append(orderIDs, order.ID)
+	}
+
+	query := `
+		SELECT
+			pt.id,
+			pt.for_sale_item_id,
+			pt.buyer_user_id,
+			pt.seller_user_id,
+			pt.type_id,
+			t.type_name,
+			COALESCE(f.location_id, 0),
+			COALESCE(resolve_location_name(f.location_id), ''),
+			pt.quantity_purchased,
+			pt.price_per_unit,
+			pt.total_price,
+			pt.status,
+			pt.contract_key,
+			pt.transaction_notes,
+			pt.buy_order_id,
+			pt.order_id,
+			pt.dispute_reason,
+			pt.is_auto_fulfilled,
+			pt.purchased_at,
+			pt.blueprint_item_id,
+			pt.blueprint_is_copy,
+			pt.blueprint_runs,
+			pt.blueprint_material_efficiency,
+			pt.blueprint_time_efficiency
+		FROM purchase_transactions pt
+		JOIN asset_item_types t ON pt.type_id = t.type_id
+		LEFT JOIN for_sale_items f ON f.id = pt.for_sale_item_id
+		WHERE pt.order_id = ANY($1)
+		ORDER BY pt.order_id, pt.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(orderIDs))
+	if err != nil {
+		return errors.Wrap(err, "failed to query order purchases")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tx models.PurchaseTransaction
+		var bp blueprintColumns
+		err = rows.Scan(
+			&tx.ID,
+			&tx.ForSaleItemID,
+			&tx.BuyerUserID,
+			&tx.SellerUserID,
+			&tx.TypeID,
+			&tx.TypeName,
+			&tx.LocationID,
+			&tx.LocationName,
+			&tx.QuantityPurchased,
+			&tx.PricePerUnit,
+			&tx.TotalPrice,
+			&tx.Status,
+			&tx.ContractKey,
+			&tx.TransactionNotes,
+			&tx.BuyOrderID,
+			&tx.OrderID,
+			&tx.DisputeReason,
+			&tx.IsAutoFulfilled,
+			&tx.PurchasedAt,
+			&bp.itemID,
+			&bp.isCopy,
+			&bp.runs,
+			&bp.me,
+			&bp.te,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to scan order purchase")
+		}
+		tx.Blueprint = bp.blueprint()
+		order := byID[*tx.OrderID]
+		tx.BuyerName = order.BuyerName
+		tx.SellerName = order.SellerName
+		order.Purchases = append(order.Purchases, &tx)
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPurchaseOrder(row rowScanner) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := row.Scan(
+		&order.ID,
+		&order.BuyerUserID,
+		&order.BuyerName,
+		&order.SellerUserID,
+		&order.SellerName,
+		&order.ContractKey,
+		&order.Notes,
+		&order.CreatedAt,
+		&order.Status,
+		&order.TotalPrice,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to scan purchase order")
+	}
+	return &order, nil
+}
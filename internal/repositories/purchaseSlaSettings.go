@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type PurchaseSlaSettings struct {
+	db *sql.DB
+}
+
+func NewPurchaseSlaSettings(db *sql.DB) *PurchaseSlaSettings {
+	return &PurchaseSlaSettings{db: db}
+}
+
+// GetByUser returns a seller's SLA settings, with no timers when none are saved.
+func (r *PurchaseSlaSettings) GetByUser(ctx context.Context, userID int64) (*models.PurchaseSlaSettings, error) {
+	query := `
+		SELECT user_id, contract_hours, accept_days
+		FROM purchase_sla_settings
+		WHERE user_id = $1
+	`
+
+	settings := &models.PurchaseSlaSettings{UserID: userID}
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&settings.UserID,
+		&settings.ContractHours,
+		&settings.AcceptDays,
+	)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get purchase sla settings")
+	}
+
+	return settings, nil
+}
+
+// Upsert saves a seller's SLA settings.
+func (r *PurchaseSlaSettings) Upsert(ctx context.Context, settings *models.PurchaseSlaSettings) error {
+	query := `
+		INSERT INTO purchase_sla_settings (user_id, contract_hours, accept_days)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			contract_hours = EXCLUDED.contract_hours,
+			accept_days = EXCLUDED.accept_days,
+			updated_at = NOW()
+	`
+
+	_, err := r.db.ExecContext(ctx, query, settings.UserID, settings.ContractHours, settings.AcceptDays)
+	if err != nil {
+		return errors.Wrap(err, "failed to upsert purchase sla settings")
+	}
+
+	return nil
+}
@@ -22,11 +22,13 @@ func (r *PurchaseTransactions) Create(ctx context.Context, tx *sql.Tx, purchase
 	query := `
 		INSERT INTO purchase_transactions
 		(for_sale_item_id, buyer_user_id, seller_user_id, type_id, quantity_purchased,
-		 price_per_unit, total_price, status, transaction_notes, buy_order_id, is_auto_fulfilled, purchased_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW())
+		 price_per_unit, total_price, status, transaction_notes, buy_order_id, is_auto_fulfilled, order_id,
+		 blueprint_item_id, blueprint_is_copy, blueprint_runs, blueprint_material_efficiency, blueprint_time_efficiency, purchased_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, NOW())
 		RETURNING id, purchased_at
 	`
 
+	bpItemID, bpIsCopy, bpRuns, bpME, bpTE := blueprintArgs(purchase.Blueprint)
 	err := tx.QueryRowContext(ctx, query,
 		purchase.ForSaleItemID,
 		purchase.BuyerUserID,
@@ -39,6 +41,12 @@ func (r *PurchaseTransactions) Create(ctx context.Context, tx *sql.Tx, purchase
 		purchase.TransactionNotes,
 		purchase.BuyOrderID,
 		purchase.IsAutoFulfilled,
+		purchase.OrderID,
+		bpItemID,
+		bpIsCopy,
+		bpRuns,
+		bpME,
+		bpTE,
 	).Scan(&purchase.ID, &purchase.PurchasedAt)
 
 	if err != nil {
@@ -58,14 +66,16 @@ func (r *PurchaseTransactions) CreateAutoFulfill(ctx context.Context, tx *sql.Tx
 	query := `
 		INSERT INTO purchase_transactions
 		(for_sale_item_id, buyer_user_id, seller_user_id, type_id, quantity_purchased,
-		 price_per_unit, total_price, status, transaction_notes, buy_order_id, is_auto_fulfilled, purchased_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW())
+		 price_per_unit, total_price, status, transaction_notes, buy_order_id, is_auto_fulfilled,
+		 blueprint_item_id, blueprint_is_copy, blueprint_runs, blueprint_material_efficiency, blueprint_time_efficiency, purchased_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NOW())
 		ON CONFLICT (buy_order_id, for_sale_item_id)
 			WHERE is_auto_fulfilled = true AND status IN ('pending', 'contract_created')
 		DO NOTHING
 		RETURNING id, purchased_at
 	`
 
+	bpItemID, bpIsCopy, bpRuns, bpME, bpTE := blueprintArgs(purchase.Blueprint)
 	err := tx.QueryRowContext(ctx, query,
 		purchase.ForSaleItemID,
 		purchase.BuyerUserID,
@@ -78,6 +88,11 @@ func (r *PurchaseTransactions) CreateAutoFulfill(ctx context.Context, tx *sql.Tx
 		purchase.TransactionNotes,
 		purchase.BuyOrderID,
 		purchase.IsAutoFulfilled,
+		bpItemID,
+		bpIsCopy,
+		bpRuns,
+		bpME,
+		bpTE,
 	).Scan(&purchase.ID, &purchase.PurchasedAt)
 
 	if err == sql.ErrNoRows {
@@ -134,8 +149,15 @@ func (r *PurchaseTransactions) GetByBuyer(ctx context.Context, buyerUserID int64
 			pt.contract_key,
 			pt.transaction_notes,
 			pt.buy_order_id,
+			pt.order_id,
+			pt.dispute_reason,
 			pt.is_auto_fulfilled,
-			pt.purchased_at
+			pt.purchased_at,
+			pt.blueprint_item_id,
+			pt.blueprint_is_copy,
+			pt.blueprint_runs,
+			pt.blueprint_material_efficiency,
+			pt.blueprint_time_efficiency
 		FROM purchase_transactions pt
 		JOIN asset_item_types t ON pt.type_id = t.type_id
 		WHERE pt.buyer_user_id = $1
@@ -151,6 +173,7 @@ func (r *PurchaseTransactions) GetByBuyer(ctx context.Context, buyerUserID int64
 	transactions := []*models.PurchaseTransaction{}
 	for rows.Next() {
 		var tx models.PurchaseTransaction
+		var bp blueprintColumns
 		err = rows.Scan(
 			&tx.ID,
 			&tx.ForSaleItemID,
@@ -165,12 +188,20 @@ func (r *PurchaseTransactions) GetByBuyer(ctx context.Context, buyerUserID int64
 			&tx.ContractKey,
 			&tx.TransactionNotes,
 			&tx.BuyOrderID,
+			&tx.OrderID,
+			&tx.DisputeReason,
 			&tx.IsAutoFulfilled,
 			&tx.PurchasedAt,
+			&bp.itemID,
+			&bp.isCopy,
+			&bp.runs,
+			&bp.me,
+			&bp.te,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan purchase transaction")
 		}
+		tx.Blueprint = bp.blueprint()
 		transactions = append(transactions, &tx)
 	}
 
@@ -194,8 +225,15 @@ func (r *PurchaseTransactions) GetBySeller(ctx context.Context, sellerUserID int
 			pt.contract_key,
 			pt.transaction_notes,
 			pt.buy_order_id,
+			pt.order_id,
+			pt.dispute_reason,
 			pt.is_auto_fulfilled,
-			pt.purchased_at
+			pt.purchased_at,
+			pt.blueprint_item_id,
+			pt.blueprint_is_copy,
+			pt.blueprint_runs,
+			pt.blueprint_material_efficiency,
+			pt.blueprint_time_efficiency
 		FROM purchase_transactions pt
 		JOIN asset_item_types t ON pt.type_id = t.type_id
 		WHERE pt.seller_user_id = $1
@@ -211,6 +249,7 @@ func (r *PurchaseTransactions) GetBySeller(ctx context.Context, sellerUserID int
 	transactions := []*models.PurchaseTransaction{}
 	for rows.Next() {
 		var tx models.PurchaseTransaction
+		var bp blueprintColumns
 		err = rows.Scan(
 			&tx.ID,
 			&tx.ForSaleItemID,
@@ -225,12 +264,20 @@ func (r *PurchaseTransactions) GetBySeller(ctx context.Context, sellerUserID int
 			&tx.ContractKey,
 			&tx.TransactionNotes,
 			&tx.BuyOrderID,
+			&tx.OrderID,
+			&tx.DisputeReason,
 			&tx.IsAutoFulfilled,
 			&tx.PurchasedAt,
+			&bp.itemID,
+			&bp.isCopy,
+			&bp.runs,
+			&bp.me,
+			&bp.te,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan purchase transaction")
 		}
+		tx.Blueprint = bp.blueprint()
 		transactions = append(transactions, &tx)
 	}
 
@@ -257,8 +304,15 @@ func (r *PurchaseTransactions) GetPendingForSeller(ctx context.Context, sellerUs
 			pt.contract_key,
 			pt.transaction_notes,
 			pt.buy_order_id,
+			pt.order_id,
+			pt.dispute_reason,
 			pt.is_auto_fulfilled,
-			pt.purchased_at
+			pt.purchased_at,
+			pt.blueprint_item_id,
+			pt.blueprint_is_copy,
+			pt.blueprint_runs,
+			pt.blueprint_material_efficiency,
+			pt.blueprint_time_efficiency
 		FROM purchase_transactions pt
 		JOIN asset_item_types t ON pt.type_id = t.type_id
 		JOIN for_sale_items fsi ON pt.for_sale_item_id = fsi.id
@@ -276,6 +330,7 @@ func (r *PurchaseTransactions) GetPendingForSeller(ctx context.Context, sellerUs
 	transactions := []*models.PurchaseTransaction{}
 	for rows.Next() {
 		var tx models.PurchaseTransaction
+		var bp blueprintColumns
 		err = rows.Scan(
 			&tx.ID,
 			&tx.ForSaleItemID,
@@ -293,12 +348,20 @@ func (r *PurchaseTransactions) GetPendingForSeller(ctx context.Context, sellerUs
 			&tx.ContractKey,
 			&tx.TransactionNotes,
 			&tx.BuyOrderID,
+			&tx.OrderID,
+			&tx.DisputeReason,
 			&tx.IsAutoFulfilled,
 			&tx.PurchasedAt,
+			&bp.itemID,
+			&bp.isCopy,
+			&bp.runs,
+			&bp.me,
+			&bp.te,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan pending sale")
 		}
+		tx.Blueprint = bp.blueprint()
 		transactions = append(transactions, &tx)
 	}
 
@@ -322,14 +385,22 @@ func (r *PurchaseTransactions) GetByID(ctx context.Context, purchaseID int64) (*
 			pt.contract_key,
 			pt.transaction_notes,
 			pt.buy_order_id,
+			pt.order_id,
+			pt.dispute_reason,
 			pt.is_auto_fulfilled,
-			pt.purchased_at
+			pt.purchased_at,
+			pt.blueprint_item_id,
+			pt.blueprint_is_copy,
+			pt.blueprint_runs,
+			pt.blueprint_material_efficiency,
+			pt.blueprint_time_efficiency
 		FROM purchase_transactions pt
 		JOIN asset_item_types t ON pt.type_id = t.type_id
 		WHERE pt.id = $1
 	`
 
 	var tx models.PurchaseTransaction
+	var bp blueprintColumns
 	err := r.db.QueryRowContext(ctx, query, purchaseID).Scan(
 		&tx.ID,
 		&tx.ForSaleItemID,
@@ -344,8 +415,15 @@ func (r *PurchaseTransactions) GetByID(ctx context.Context, purchaseID int64) (*
 		&tx.ContractKey,
 		&tx.TransactionNotes,
 		&tx.BuyOrderID,
+		&tx.OrderID,
+		&tx.DisputeReason,
 		&tx.IsAutoFulfilled,
 		&tx.PurchasedAt,
+		&bp.itemID,
+		&bp.isCopy,
+		&bp.runs,
+		&bp.me,
+		&bp.te,
 	)
 
 	if err == sql.ErrNoRows {
@@ -354,6 +432,7 @@ func (r *PurchaseTransactions) GetByID(ctx context.Context, purchaseID int64) (*
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get purchase transaction")
 	}
+	tx.Blueprint = bp.blueprint()
 
 	return &tx, nil
 }
@@ -375,8 +454,15 @@ func (r *PurchaseTransactions) GetContractCreatedWithKeys(ctx context.Context) (
 			pt.contract_key,
 			pt.transaction_notes,
 			pt.buy_order_id,
+			pt.order_id,
+			pt.dispute_reason,
 			pt.is_auto_fulfilled,
-			pt.purchased_at
+			pt.purchased_at,
+			pt.blueprint_item_id,
+			pt.blueprint_is_copy,
+			pt.blueprint_runs,
+			pt.blueprint_material_efficiency,
+			pt.blueprint_time_efficiency
 		FROM purchase_transactions pt
 		JOIN asset_item_types t ON pt.type_id = t.type_id
 		WHERE pt.status = 'contract_created' AND pt.contract_key IS NOT NULL
@@ -392,6 +478,7 @@ func (r *PurchaseTransactions) GetContractCreatedWithKeys(ctx context.Context) (
 	transactions := []*models.PurchaseTransaction{}
 	for rows.Next() {
 		var tx models.PurchaseTransaction
+		var bp blueprintColumns
 		err = rows.Scan(
 			&tx.ID,
 			&tx.ForSaleItemID,
@@ -406,12 +493,20 @@ func (r *PurchaseTransactions) GetContractCreatedWithKeys(ctx context.Context) (
 			&tx.ContractKey,
 			&tx.TransactionNotes,
 			&tx.BuyOrderID,
+			&tx.OrderID,
+			&tx.DisputeReason,
 			&tx.IsAutoFulfilled,
 			&tx.PurchasedAt,
+			&bp.itemID,
+			&bp.isCopy,
+			&bp.runs,
+			&bp.me,
+			&bp.te,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan contract_created purchase")
 		}
+		tx.Blueprint = bp.blueprint()
 		transactions = append(transactions, &tx)
 	}
 
@@ -445,6 +540,176 @@ func (r *PurchaseTransactions) CompleteWithContractID(ctx context.Context, purch
 	return nil
 }
 
+// CompleteOrderWithContractID completes every contract_created purchase of an order in one
+// statement and records the EVE contract ID on each.
+func (r *PurchaseTransactions) CompleteOrderWithContractID(ctx context.Context, orderID int64, eveContractID int64) (int64, error) {
+	query := `
+		UPDATE purchase_transactions
+		SET status = 'completed',
+			completed_at = NOW(),
+			contract_key = contract_key || ' [EVE:' || $2::text || ']'
+		WHERE order_id = $1 AND status = 'contract_created'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, orderID, eveContractID)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to complete purchase order with contract ID")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return 0, errors.New("purchase order has no purchases in contract_created status")
+	}
+
+	return rowsAffected, nil
+}
+
+// DisputeWithContractID marks contract_created purchases as disputed because the EVE contract
+// matched to them did not deliver what was bought, and records the contract ID and reason.
+func (r *PurchaseTransactions) DisputeWithContractID(ctx context.Context, purchaseIDs []int64, eveContractID int64, reason string) error {
+	query := `
+		UPDATE purchase_transactions
+		SET status = 'disputed',
+			disputed_at = NOW(),
+			dispute_reason = $3,
+			contract_key = contract_key || ' [EVE:' || $2::text || ']'
+		WHERE id = ANY($1) AND status = 'contract_created'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, pq.Array(purchaseIDs), eveContractID, reason)
+	if err != nil {
+		return errors.Wrap(err, "failed to dispute purchases")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("no purchase transactions in contract_created status to dispute")
+	}
+
+	return nil
+}
+
+// GetOverdue returns purchases that missed their seller's SLA: pending longer than the seller's
+// contract_hours, or contract_created longer than their accept_days.
+func (r *PurchaseTransactions) GetOverdue(ctx context.Context) ([]*models.PurchaseTransaction, error) {
+	query := `
+		SELECT
+			pt.id,
+			pt.for_sale_item_id,
+			pt.buyer_user_id,
+			COALESCE(buyer.name, ''),
+			pt.seller_user_id,
+			COALESCE(seller.name, ''),
+			pt.type_id,
+			t.type_name,
+			pt.quantity_purchased,
+			pt.price_per_unit,
+			pt.total_price,
+			pt.status,
+			pt.contract_key,
+			pt.transaction_notes,
+			pt.buy_order_id,
+			pt.order_id,
+			pt.dispute_reason,
+			pt.is_auto_fulfilled,
+			pt.purchased_at,
+			pt.blueprint_item_id,
+			pt.blueprint_is_copy,
+			pt.blueprint_runs,
+			pt.blueprint_material_efficiency,
+			pt.blueprint_time_efficiency
+		FROM purchase_transactions pt
+		JOIN purchase_sla_settings sla ON sla.user_id = pt.seller_user_id
+		JOIN asset_item_types t ON pt.type_id = t.type_id
+		LEFT JOIN users buyer ON buyer.id = pt.buyer_user_id
+		LEFT JOIN users seller ON seller.id = pt.seller_user_id
+		WHERE (pt.status = 'pending'
+				AND sla.contract_hours IS NOT NULL
+				AND pt.purchased_at < NOW() - sla.contract_hours * INTERVAL '1 hour')
+			OR (pt.status = 'contract_created'
+				AND sla.accept_days IS NOT NULL
+				AND COALESCE(pt.contract_created_at, pt.purchased_at) < NOW() - sla.accept_days * INTERVAL '1 day')
+		ORDER BY pt.purchased_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query overdue purchases")
+	}
+	defer rows.Close()
+
+	transactions := []*models.PurchaseTransaction{}
+	for rows.Next() {
+		var tx models.PurchaseTransaction
+		var bp blueprintColumns
+		err = rows.Scan(
+			&tx.ID,
+			&tx.ForSaleItemID,
+			&tx.BuyerUserID,
+			&tx.BuyerName,
+			&tx.SellerUserID,
+			&tx.SellerName,
+			&tx.TypeID,
+			&tx.TypeName,
+			&tx.QuantityPurchased,
+			&tx.PricePerUnit,
+			&tx.TotalPrice,
+			&tx.Status,
+			&tx.ContractKey,
+			&tx.TransactionNotes,
+			&tx.BuyOrderID,
+			&tx.OrderID,
+			&tx.DisputeReason,
+			&tx.IsAutoFulfilled,
+			&tx.PurchasedAt,
+			&bp.itemID,
+			&bp.isCopy,
+			&bp.runs,
+			&bp.me,
+			&bp.te,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan overdue purchase")
+		}
+		tx.Blueprint = bp.blueprint()
+		transactions = append(transactions, &tx)
+	}
+
+	return transactions, nil
+}
+
+// Expire moves an overdue purchase to expired (within transaction), remembering the status it
+// expired from. It does nothing and returns false if the purchase has left that status meanwhile.
+func (r *PurchaseTransactions) Expire(ctx context.Context, tx *sql.Tx, purchaseID int64, fromStatus string) (bool, error) {
+	query := `
+		UPDATE purchase_transactions
+		SET status = 'expired',
+			expired_at = NOW(),
+			expired_from_status = status
+		WHERE id = $1 AND status = $2
+	`
+
+	result, err := tx.ExecContext(ctx, query, purchaseID, fromStatus)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to expire purchase")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get rows affected")
+	}
+
+	return rowsAffected > 0, nil
+}
+
 // GetPendingQuantitiesForSaleContext returns pending purchase quantities
 // grouped by type_id, scoped to a specific seller's for-sale context (owner+location+container/division).
 // Counts 'pending' purchases, 'contract_created' purchases within the last hour,
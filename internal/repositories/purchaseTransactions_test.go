@@ -725,3 +725,106 @@ func Test_PurchaseTransactions_GetPendingQuantitiesForSaleContext_ContractCreate
 	assert.Equal(t, int64(600), quantities[typeID],
 		"should count pending (100) + recent contract_created (200) + recent completed (300), not stale contract_created (150) or stale completed (400)")
 }
+
+func Test_PurchaseTransactions_GetOverdueAndExpire(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	sellerID := int64(3100)
+	buyerID := int64(3101)
+	typeID := int64(50)
+	locationID := int64(30000160)
+
+	item, err := setupPurchaseTestData(t, db, buyerID, sellerID, typeID, locationID)
+	assert.NoError(t, err)
+
+	repo := repositories.NewPurchaseTransactions(db)
+	slaRepo := repositories.NewPurchaseSlaSettings(db)
+	analyticsRepo := repositories.NewSalesAnalytics(db)
+
+	create := func(status string) *models.PurchaseTransaction {
+		tx, err := db.BeginTx(ctx, nil)
+		assert.NoError(t, err)
+		purchase := &models.PurchaseTransaction{
+			ForSaleItemID:     item.ID,
+			BuyerUserID:       buyerID,
+			SellerUserID:      sellerID,
+			TypeID:            typeID,
+			QuantityPurchased: 10,
+			PricePerUnit:      100,
+			TotalPrice:        1000,
+			Status:            status,
+		}
+		assert.NoError(t, repo.Create(ctx, tx, purchase))
+		assert.NoError(t, tx.Commit())
+		return purchase
+	}
+
+	stalePending := create("pending")
+	freshPending := create("pending")
+	staleContract := create("contract_created")
+	freshContract := create("contract_created")
+
+	_, err = db.ExecContext(ctx, `UPDATE purchase_transactions SET purchased_at = NOW() - INTERVAL '30 hours' WHERE id = $1`, stalePending.ID)
+	assert.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE purchase_transactions SET contract_created_at = NOW() - INTERVAL '8 days' WHERE id = $1`, staleContract.ID)
+	assert.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE purchase_transactions SET contract_created_at = NOW() WHERE id = $1`, freshContract.ID)
+	assert.NoError(t, err)
+
+	// No SLA settings, nothing is overdue
+	overdue, err := repo.GetOverdue(ctx)
+	assert.NoError(t, err)
+	for _, purchase := range overdue {
+		assert.NotEqual(t, sellerID, purchase.SellerUserID)
+	}
+
+	settings, err := slaRepo.GetByUser(ctx, sellerID)
+	assert.NoError(t, err)
+	assert.Nil(t, settings.ContractHours)
+
+	contractHours, acceptDays := 24, 7
+	assert.NoError(t, slaRepo.Upsert(ctx, &models.PurchaseSlaSettings{UserID: sellerID, ContractHours: &contractHours, AcceptDays: &acceptDays}))
+
+	overdue, err = repo.GetOverdue(ctx)
+	assert.NoError(t, err)
+	overdueIDs := []int64{}
+	for _, purchase := range overdue {
+		if purchase.SellerUserID == sellerID {
+			overdueIDs = append(overdueIDs, purchase.ID)
+			assert.Equal(t, "Test Buyer", purchase.BuyerName)
+		}
+	}
+	assert.ElementsMatch(t, []int64{stalePending.ID, staleContract.ID}, overdueIDs)
+	assert.NotContains(t, overdueIDs, freshPending.ID)
+	assert.NotContains(t, overdueIDs, freshContract.ID)
+
+	for _, purchase := range []*models.PurchaseTransaction{stalePending, staleContract} {
+		tx, err := db.BeginTx(ctx, nil)
+		assert.NoError(t, err)
+		expired, err := repo.Expire(ctx, tx, purchase.ID, purchase.Status)
+		assert.NoError(t, err)
+		assert.True(t, expired)
+		assert.NoError(t, tx.Commit())
+	}
+
+	// Already expired, so a second attempt does nothing
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	expired, err := repo.Expire(ctx, tx, stalePending.ID, "pending")
+	assert.NoError(t, err)
+	assert.False(t, expired)
+	assert.NoError(t, tx.Rollback())
+
+	fetched, err := repo.GetByID(ctx, staleContract.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "expired", fetched.Status)
+
+	aging, err := analyticsRepo.GetPurchaseAging(ctx, sellerID, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), aging.OpenPending)
+	assert.Equal(t, int64(1), aging.OpenContractCreated)
+	assert.Equal(t, int64(1), aging.ExpiredAwaitingContract)
+	assert.Equal(t, int64(1), aging.ExpiredAwaitingAcceptance)
+}
@@ -104,9 +104,62 @@ func (r *SalesAnalytics) GetSalesMetrics(ctx context.Context, sellerUserID int64
 	}
 	metrics.TopItems = topItems
 
+	aging, err := r.GetPurchaseAging(ctx, sellerUserID, periodDays)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get purchase aging")
+	}
+	metrics.Aging = aging
+
 	return metrics, nil
 }
 
+// GetPurchaseAging returns how long the seller's purchases wait for a contract and for the buyer
+// to accept it, how many are still open, and how many the SLA timers expired
+func (r *SalesAnalytics) GetPurchaseAging(ctx context.Context, sellerUserID int64, periodDays int) (*models.PurchaseAging, error) {
+	var startDate time.Time
+	if periodDays > 0 {
+		startDate = time.Now().AddDate(0, 0, -periodDays)
+	}
+
+	query := `
+		SELECT
+			COALESCE(AVG(EXTRACT(EPOCH FROM (contract_created_at - purchased_at)) / 3600)
+				FILTER (WHERE contract_created_at IS NOT NULL), 0)::float8 as avg_hours_to_contract,
+			COALESCE(AVG(EXTRACT(EPOCH FROM (completed_at - contract_created_at)) / 3600)
+				FILTER (WHERE completed_at IS NOT NULL AND contract_created_at IS NOT NULL), 0)::float8 as avg_hours_to_accept,
+			COUNT(*) FILTER (WHERE status = 'pending') as open_pending,
+			COUNT(*) FILTER (WHERE status = 'contract_created') as open_contract_created,
+			COALESCE(MAX(EXTRACT(EPOCH FROM (NOW() - purchased_at)) / 3600)
+				FILTER (WHERE status IN ('pending', 'contract_created')), 0)::float8 as oldest_open_hours,
+			COUNT(*) FILTER (WHERE status = 'expired' AND expired_from_status = 'pending') as expired_awaiting_contract,
+			COUNT(*) FILTER (WHERE status = 'expired' AND expired_from_status = 'contract_created') as expired_awaiting_acceptance
+		FROM purchase_transactions
+		WHERE seller_user_id = $1
+	`
+
+	args := []interface{}{sellerUserID}
+	if periodDays > 0 {
+		query += " AND purchased_at >= $2"
+		args = append(args, startDate)
+	}
+
+	aging := &models.PurchaseAging{}
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(
+		&aging.AvgHoursToContract,
+		&aging.AvgHoursToAccept,
+		&aging.OpenPending,
+		&aging.OpenContractCreated,
+		&aging.OldestOpenHours,
+		&aging.ExpiredAwaitingContract,
+		&aging.ExpiredAwaitingAcceptance,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get purchase aging")
+	}
+
+	return aging, nil
+}
+
 // GetTopItems returns the top selling items by revenue
 func (r *SalesAnalytics) GetTopItems(ctx context.Context, sellerUserID int64, periodDays int, limit int) ([]models.ItemSalesData, error) {
 	var startDate time.Time
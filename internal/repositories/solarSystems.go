@@ -128,6 +128,48 @@ func (r *SolarSystems) Search(ctx context.Context, query string, limit int) ([]*
 	return systems, nil
 }
 
+// GetJumpCandidates returns every known-space lowsec and nullsec system with
+// coordinates, the systems a cyno can be lit in. Wormhole space (IDs from
+// 31000000), Pochven (region 10000070) and Zarzakh (30100000) are left out;
+// cynos cannot be lit there.
+func (r *SolarSystems) GetJumpCandidates(ctx context.Context) ([]*models.SolarSystem, error) {
+	query := `
+		select s.solar_system_id, s.name, s.constellation_id, s.security, s.x, s.y, s.z
+		from solar_systems s
+		join constellations c on c.constellation_id = s.constellation_id
+		where s.security < 0.45
+		  and s.solar_system_id < 31000000
+		  and s.solar_system_id != 30100000
+		  and c.region_id != 10000070
+		  and s.x is not null and s.y is not null and s.z is not null
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query jump candidate systems")
+	}
+	defer rows.Close()
+
+	systems := []*models.SolarSystem{}
+	for rows.Next() {
+		var system models.SolarSystem
+		if err := rows.Scan(
+			&system.ID,
+			&system.Name,
+			&system.ConstellationID,
+			&system.Security,
+			&system.X,
+			&system.Y,
+			&system.Z,
+		); err != nil {
+			return nil, errors.Wrap(err, "failed to scan solar system")
+		}
+		systems = append(systems, &system)
+	}
+
+	return systems, nil
+}
+
 func (r *SolarSystems) GetNames(ctx context.Context, ids []int64) (map[int64]string, error) {
 	if len(ids) == 0 {
 		return map[int64]string{}, nil
@@ -65,3 +65,46 @@ func Test_SolarSystemsShouldHandleEmptyUpsert(t *testing.T) {
 	err = solarSystemsRepo.Upsert(context.Background(), nil)
 	assert.NoError(t, err)
 }
+
+func Test_SolarSystemsShouldGetJumpCandidates(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	regionsRepo := repositories.NewRegions(db)
+	constellationsRepo := repositories.NewConstellations(db)
+	solarSystemsRepo := repositories.NewSolarSystems(db)
+
+	err = regionsRepo.Upsert(context.Background(), []models.Region{
+		{ID: 10000002, Name: "The Forge"},
+		{ID: 10000070, Name: "Pochven"},
+		{ID: 10001000, Name: "Yasna Zakh"},
+	})
+	assert.NoError(t, err)
+	err = constellationsRepo.Upsert(context.Background(), []models.Constellation{
+		{ID: 20000020, Name: "Kimotoro", RegionID: 10000002},
+		{ID: 20000788, Name: "Krai Perun", RegionID: 10000070},
+		{ID: 20010000, Name: "Yasna Zakh", RegionID: 10001000},
+	})
+	assert.NoError(t, err)
+
+	x, y, z := 1.0e17, 2.0e17, 3.0e17
+	err = solarSystemsRepo.Upsert(context.Background(), []models.SolarSystem{
+		{ID: 30000142, Name: "Jita", ConstellationID: 20000020, Security: 0.9, X: &x, Y: &y, Z: &z},
+		{ID: 30002813, Name: "Tama", ConstellationID: 20000020, Security: 0.3, X: &x, Y: &y, Z: &z},
+		{ID: 30004759, Name: "1DQ1-A", ConstellationID: 20000020, Security: -0.4, X: &x, Y: &y, Z: &z},
+		{ID: 30002814, Name: "No Coords", ConstellationID: 20000020, Security: 0.2},
+		{ID: 31000005, Name: "Thera", ConstellationID: 20000020, Security: -1.0, X: &x, Y: &y, Z: &z},
+		{ID: 30000157, Name: "Otela", ConstellationID: 20000788, Security: -1.0, X: &x, Y: &y, Z: &z},
+		{ID: 30100000, Name: "Zarzakh", ConstellationID: 20010000, Security: -1.0, X: &x, Y: &y, Z: &z},
+	})
+	assert.NoError(t, err)
+
+	systems, err := solarSystemsRepo.GetJumpCandidates(context.Background())
+	assert.NoError(t, err)
+
+	ids := []int64{}
+	for _, s := range systems {
+		ids = append(ids, s.ID)
+	}
+	assert.ElementsMatch(t, []int64{30002813, 30004759}, ids)
+}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type Stargates struct {
+	db *sql.DB
+}
+
+func NewStargates(db *sql.DB) *Stargates {
+	return &Stargates{
+		db: db,
+	}
+}
+
+func (r *Stargates) Upsert(ctx context.Context, stargates []models.Stargate) error {
+	if len(stargates) == 0 {
+		return nil
+	}
+
+	upsertQuery := `
+insert into
+	stargates
+	(
+		stargate_id,
+		solar_system_id,
+		destination_stargate_id,
+		destination_solar_system_id
+	)
+	values
+		($1,$2,$3,$4)
+on conflict
+	(stargate_id)
+do update set
+	solar_system_id = EXCLUDED.solar_system_id,
+	destination_stargate_id = EXCLUDED.destination_stargate_id,
+	destination_solar_system_id = EXCLUDED.destination_solar_system_id;
+`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction for stargate upsert")
+	}
+	defer tx.Rollback()
+
+	smt, err := tx.PrepareContext(ctx, upsertQuery)
+	if err != nil {
+		return errors.Wrap(err, "failed to prepare for stargate upsert")
+	}
+
+	for _, gate := range stargates {
+		_, err := smt.ExecContext(ctx, gate.ID, gate.SolarSystemID, gate.DestinationStargateID, gate.DestinationSolarSystemID)
+		if err != nil {
+			return errors.Wrap(err, "failed to execute for stargate upsert")
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit stargates")
+	}
+	return nil
+}
+
+// GetSystemJumps returns each gate connection once per direction.
+func (r *Stargates) GetSystemJumps(ctx context.Context) ([]models.SystemJump, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		select distinct solar_system_id, destination_solar_system_id
+		from stargates
+		order by solar_system_id, destination_solar_system_id`)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query system jumps")
+	}
+	defer rows.Close()
+
+	jumps := []models.SystemJump{}
+	for rows.Next() {
+		var jump models.SystemJump
+		if err := rows.Scan(&jump.FromSystemID, &jump.ToSystemID); err != nil {
+			return nil, errors.Wrap(err, "failed to scan system jump")
+		}
+		jumps = append(jumps, jump)
+	}
+
+	return jumps, nil
+}
+
+// GetRouteSystems returns the region and security of every solar system with
+// a stargate, the nodes of the gate graph.
+func (r *Stargates) GetRouteSystems(ctx context.Context) ([]models.RouteSystem, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		select ss.solar_system_id, c.region_id, ss.security
+		from solar_systems ss
+		join constellations c on c.constellation_id = ss.constellation_id
+		where exists (select 1 from stargates g where g.solar_system_id = ss.solar_system_id)`)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query route systems")
+	}
+	defer rows.Close()
+
+	systems := []models.RouteSystem{}
+	for rows.Next() {
+		var system models.RouteSystem
+		if err := rows.Scan(&system.ID, &system.RegionID, &system.Security); err != nil {
+			return nil, errors.Wrap(err, "failed to scan route system")
+		}
+		systems = append(systems, system)
+	}
+
+	return systems, nil
+}
@@ -0,0 +1,71 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StargatesShouldUpsertAndBuildGraph(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	regionsRepo := repositories.NewRegions(db)
+	constellationsRepo := repositories.NewConstellations(db)
+	solarSystemsRepo := repositories.NewSolarSystems(db)
+	stargatesRepo := repositories.NewStargates(db)
+
+	err = regionsRepo.Upsert(ctx, []models.Region{{ID: 10000002, Name: "The Forge"}})
+	assert.NoError(t, err)
+	err = constellationsRepo.Upsert(ctx, []models.Constellation{{ID: 20000020, Name: "Kimotoro", RegionID: 10000002}})
+	assert.NoError(t, err)
+	err = solarSystemsRepo.Upsert(ctx, []models.SolarSystem{
+		{ID: 30000142, Name: "Jita", ConstellationID: 20000020, Security: 0.9},
+		{ID: 30000144, Name: "Perimeter", ConstellationID: 20000020, Security: 1.0},
+		{ID: 30000145, Name: "No Gates", ConstellationID: 20000020, Security: 0.5},
+	})
+	assert.NoError(t, err)
+
+	gates := []models.Stargate{
+		{ID: 50001248, SolarSystemID: 30000142, DestinationStargateID: 50001249, DestinationSolarSystemID: 30000144},
+		{ID: 50001249, SolarSystemID: 30000144, DestinationStargateID: 50001248, DestinationSolarSystemID: 30000142},
+	}
+	err = stargatesRepo.Upsert(ctx, gates)
+	assert.NoError(t, err)
+
+	// Upserting again updates in place
+	err = stargatesRepo.Upsert(ctx, gates)
+	assert.NoError(t, err)
+
+	jumps, err := stargatesRepo.GetSystemJumps(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.SystemJump{
+		{FromSystemID: 30000142, ToSystemID: 30000144},
+		{FromSystemID: 30000144, ToSystemID: 30000142},
+	}, jumps)
+
+	systems, err := stargatesRepo.GetRouteSystems(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, systems, 2)
+	for _, s := range systems {
+		assert.Equal(t, int64(10000002), s.RegionID)
+		assert.NotEqual(t, int64(30000145), s.ID)
+	}
+}
+
+func Test_StargatesShouldHandleEmptyUpsert(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	stargatesRepo := repositories.NewStargates(db)
+	err = stargatesRepo.Upsert(context.Background(), []models.Stargate{})
+	assert.NoError(t, err)
+
+	jumps, err := stargatesRepo.GetSystemJumps(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, jumps)
+}
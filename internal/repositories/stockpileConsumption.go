@@ -0,0 +1,200 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+// stockpileConsumptionQuery returns each marker with its held quantity, the summed day-over-day
+// drops of its snapshots and the materials of manufacturing and reaction jobs its owner started at
+// its station within the window of $1 days. Corporation jobs belong to the installer's corporation;
+// manufacturing materials apply the blueprint's ME, read from the blueprint or the queue entry that
+// started the job. Job usage is split evenly across the owner's markers of the type at the station,
+// so containers and divisions don't each count it in full. Callers append the WHERE clause on markers m.
+const stockpileConsumptionQuery = `
+	WITH asset_usage AS (
+		SELECT
+			marker_id,
+			SUM(GREATEST(previous_quantity - quantity, 0)) AS consumed,
+			MAX(snapshot_date) - MIN(snapshot_date) AS history_days
+		FROM (
+			SELECT
+				marker_id,
+				snapshot_date,
+				quantity,
+				LAG(quantity) OVER (PARTITION BY marker_id ORDER BY snapshot_date) AS previous_quantity
+			FROM stockpile_marker_snapshots
+			WHERE snapshot_date >= CURRENT_DATE - $1::int
+		) s
+		GROUP BY marker_id
+	),
+	production_usage AS (
+		SELECT
+			j.user_id,
+			CASE j.source WHEN 'corporation' THEN 'corporation' ELSE 'character' END AS owner_type,
+			CASE j.source WHEN 'corporation' THEN c.corporation_id ELSE j.installer_id END AS owner_id,
+			j.station_id,
+			mat.type_id,
+			SUM(GREATEST(j.runs, CEIL(mat.quantity::numeric * j.runs * (100 - me.level) / 100)))::bigint AS consumed
+		FROM esi_industry_jobs j
+		INNER JOIN sde_blueprint_materials mat ON (
+			mat.blueprint_type_id = j.blueprint_type_id
+			AND mat.activity = CASE j.activity_id WHEN 1 THEN 'manufacturing' WHEN 9 THEN 'reaction' END
+		)
+		LEFT JOIN characters c ON c.id = j.installer_id AND c.user_id = j.user_id
+		LEFT JOIN character_blueprints cb ON cb.item_id = j.blueprint_id
+		LEFT JOIN LATERAL (
+			SELECT q.me_level FROM industry_job_queue q WHERE q.esi_job_id = j.job_id LIMIT 1
+		) q ON TRUE
+		CROSS JOIN LATERAL (
+			SELECT CASE WHEN j.activity_id = 1 THEN COALESCE(cb.material_efficiency, q.me_level, 0) ELSE 0 END AS level
+		) me
+		WHERE j.start_date >= NOW() - make_interval(days => $1::int)
+		GROUP BY 1, 2, 3, 4, 5
+	),
+	station_markers AS (
+		SELECT
+			id AS marker_id,
+			COUNT(*) OVER (PARTITION BY user_id, owner_type, owner_id, location_id, type_id) AS markers
+		FROM stockpile_markers
+	)
+	SELECT
+		m.id,
+		m.type_id,
+		COALESCE(t.type_name, ''),
+		m.owner_type,
+		m.owner_id,
+		COALESCE(resolve_owner_name(m.owner_type, m.owner_id), ''),
+		m.location_id,
+		COALESCE(resolve_location_name(m.location_id), ''),
+		m.container_id,
+		m.division_number,
+		m.desired_quantity,
+		COALESCE(held.quantity, 0),
+		m.cover_days,
+		m.min_desired_quantity,
+		m.max_desired_quantity,
+		m.auto_adjust_enabled,
+		m.template_application_id IS NOT NULL,
+		COALESCE(au.history_days, 0),
+		COALESCE(au.consumed, 0),
+		COALESCE(pu.consumed / sm.markers, 0)
+	FROM stockpile_markers m
+	INNER JOIN station_markers sm ON sm.marker_id = m.id
+	LEFT JOIN asset_item_types t ON t.type_id = m.type_id
+	LEFT JOIN asset_usage au ON au.marker_id = m.id
+	LEFT JOIN production_usage pu ON (
+		pu.user_id = m.user_id
+		AND pu.owner_type = m.owner_type
+		AND pu.owner_id = m.owner_id
+		AND pu.station_id = m.location_id
+		AND pu.type_id = m.type_id
+	)
+	LEFT JOIN LATERAL (` + stockpileMarkerHeldQuantity + `) held ON TRUE
+`
+
+// RecordSnapshots stores today's held quantity of every stockpile marker. Repeated calls on the
+// same day overwrite the snapshot with the latest quantity.
+func (r *StockpileMarkers) RecordSnapshots(ctx context.Context) error {
+	query := `
+		INSERT INTO stockpile_marker_snapshots (marker_id, snapshot_date, quantity, recorded_at)
+		SELECT m.id, CURRENT_DATE, COALESCE(held.quantity, 0), NOW()
+		FROM stockpile_markers m
+		LEFT JOIN LATERAL (` + stockpileMarkerHeldQuantity + `) held ON TRUE
+		ON CONFLICT (marker_id, snapshot_date)
+		DO UPDATE SET
+			quantity = EXCLUDED.quantity,
+			recorded_at = EXCLUDED.recorded_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return errors.Wrap(err, "failed to record stockpile marker snapshots")
+	}
+
+	return nil
+}
+
+// GetConsumptionByUser returns the consumption of every stockpile marker of a user over the last
+// windowDays days.
+func (r *StockpileMarkers) GetConsumptionByUser(ctx context.Context, userID int64, windowDays int) ([]*models.StockpileConsumption, error) {
+	return r.queryConsumption(ctx, "m.user_id = $2", windowDays, userID)
+}
+
+// GetAutoAdjustConsumption returns the consumption of every marker with auto-adjust enabled.
+// Markers managed by a stockpile template are skipped; the template owns their quantity.
+func (r *StockpileMarkers) GetAutoAdjustConsumption(ctx context.Context, windowDays int) ([]*models.StockpileConsumption, error) {
+	return r.queryConsumption(ctx, "m.auto_adjust_enabled = TRUE AND m.template_application_id IS NULL", windowDays)
+}
+
+// SetDesiredQuantity updates the desired quantity of a single marker.
+func (r *StockpileMarkers) SetDesiredQuantity(ctx context.Context, markerID int64, quantity int64) error {
+	query := `
+		UPDATE stockpile_markers
+		SET desired_quantity = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, markerID, quantity)
+	if err != nil {
+		return errors.Wrap(err, "failed to update stockpile marker desired quantity")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rows == 0 {
+		return errors.New("stockpile marker not found")
+	}
+
+	return nil
+}
+
+func (r *StockpileMarkers) queryConsumption(ctx context.Context, filter string, windowDays int, args ...any) ([]*models.StockpileConsumption, error) {
+	query := stockpileConsumptionQuery + `
+	WHERE ` + filter + `
+	ORDER BY m.user_id, t.type_name, m.type_id, m.location_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, append([]any{windowDays}, args...)...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query stockpile consumption")
+	}
+	defer rows.Close()
+
+	consumption := []*models.StockpileConsumption{}
+	for rows.Next() {
+		c := &models.StockpileConsumption{}
+		err = rows.Scan(
+			&c.MarkerID,
+			&c.TypeID,
+			&c.TypeName,
+			&c.OwnerType,
+			&c.OwnerID,
+			&c.OwnerName,
+			&c.LocationID,
+			&c.LocationName,
+			&c.ContainerID,
+			&c.DivisionNumber,
+			&c.DesiredQuantity,
+			&c.HeldQuantity,
+			&c.CoverDays,
+			&c.MinDesiredQuantity,
+			&c.MaxDesiredQuantity,
+			&c.AutoAdjustEnabled,
+			&c.TemplateManaged,
+			&c.HistoryDays,
+			&c.AssetConsumed,
+			&c.ProductionConsumed,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan stockpile consumption")
+		}
+		consumption = append(consumption, c)
+	}
+
+	return consumption, nil
+}
@@ -0,0 +1,188 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StockpileConsumption_ShouldSumSnapshotDrops(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	setupTestUniverse(t, db)
+
+	userRepository := repositories.NewUserRepository(db)
+	playerCorpsRepository := repositories.NewPlayerCorporations(db)
+	corpAssetsRepository := repositories.NewCorporationAssets(db)
+	markersRepository := repositories.NewStockpileMarkers(db)
+
+	err = userRepository.Add(context.Background(), &repositories.User{ID: 42, Name: "Builder"})
+	assert.NoError(t, err)
+
+	err = playerCorpsRepository.Upsert(context.Background(), repositories.PlayerCorporation{
+		ID:              2001,
+		UserID:          42,
+		Name:            "Builder Corp",
+		EsiToken:        "token123",
+		EsiRefreshToken: "refresh456",
+		EsiExpiresOn:    time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	err = corpAssetsRepository.Upsert(context.Background(), 2001, 42, []*models.EveAsset{
+		{ItemID: 5000, IsSingleton: true, LocationID: 60003760, LocationType: "station", Quantity: 1, TypeID: 27, LocationFlag: "OfficeFolder"},
+		{ItemID: 5001, LocationID: 5000, LocationType: "item", Quantity: 4000, TypeID: 34, LocationFlag: "CorpSAG1"},
+	})
+	assert.NoError(t, err)
+
+	division := 1
+	coverDays := 7
+	autoAdjustEnabled := true
+	err = markersRepository.Upsert(context.Background(), &models.StockpileMarker{
+		UserID:            42,
+		TypeID:            34,
+		OwnerType:         "corporation",
+		OwnerID:           2001,
+		LocationID:        60003760,
+		DivisionNumber:    &division,
+		DesiredQuantity:   5000,
+		CoverDays:         &coverDays,
+		AutoAdjustEnabled: &autoAdjustEnabled,
+	})
+	assert.NoError(t, err)
+
+	err = markersRepository.RecordSnapshots(context.Background())
+	assert.NoError(t, err)
+
+	// Four days ago 9,000, restocked to 12,000 two days ago, 4,000 held today
+	_, err = db.Exec(`
+		INSERT INTO stockpile_marker_snapshots (marker_id, snapshot_date, quantity)
+		SELECT id, CURRENT_DATE - d.days, d.quantity
+		FROM stockpile_markers, (VALUES (4, 9000), (3, 8000), (2, 12000)) AS d(days, quantity)
+		WHERE user_id = 42
+	`)
+	assert.NoError(t, err)
+
+	consumption, err := markersRepository.GetConsumptionByUser(context.Background(), 42, 30)
+	assert.NoError(t, err)
+	assert.Len(t, consumption, 1)
+
+	c := consumption[0]
+	assert.Equal(t, int64(4000), c.HeldQuantity)
+	assert.Equal(t, 4, c.HistoryDays)
+	assert.Equal(t, int64(9000), c.AssetConsumed) // 1,000 + 8,000; the restock is ignored
+	assert.Equal(t, int64(0), c.ProductionConsumed)
+	assert.Equal(t, 7, *c.CoverDays)
+	assert.True(t, c.AutoAdjustEnabled)
+	assert.False(t, c.TemplateManaged)
+
+	autoAdjust, err := markersRepository.GetAutoAdjustConsumption(context.Background(), 30)
+	assert.NoError(t, err)
+	assert.Len(t, autoAdjust, 1)
+
+	err = markersRepository.SetDesiredQuantity(context.Background(), c.MarkerID, 15750)
+	assert.NoError(t, err)
+
+	markers, err := markersRepository.GetByUser(context.Background(), 42)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(15750), markers[0].DesiredQuantity)
+
+	// An upsert replaces the whole marker, so leaving the consumption settings out clears them
+	err = markersRepository.Upsert(context.Background(), &models.StockpileMarker{
+		UserID:          42,
+		TypeID:          34,
+		OwnerType:       "corporation",
+		OwnerID:         2001,
+		LocationID:      60003760,
+		DivisionNumber:  &division,
+		DesiredQuantity: 16000,
+	})
+	assert.NoError(t, err)
+
+	markers, err = markersRepository.GetByUser(context.Background(), 42)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(16000), markers[0].DesiredQuantity)
+	assert.Nil(t, markers[0].CoverDays)
+	assert.False(t, *markers[0].AutoAdjustEnabled)
+
+	err = markersRepository.SetDesiredQuantity(context.Background(), c.MarkerID+1000, 1)
+	assert.Error(t, err)
+}
+
+func Test_StockpileConsumption_ProductionUsageUsesOwnerAndME(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	setupTestUniverse(t, db)
+
+	userRepository := repositories.NewUserRepository(db)
+	markersRepository := repositories.NewStockpileMarkers(db)
+
+	err = userRepository.Add(context.Background(), &repositories.User{ID: 44, Name: "Industrialist"})
+	assert.NoError(t, err)
+
+	for _, ownerID := range []int64{1338, 1339} {
+		err = markersRepository.Upsert(context.Background(), &models.StockpileMarker{
+			UserID:          44,
+			TypeID:          34,
+			OwnerType:       "character",
+			OwnerID:         ownerID,
+			LocationID:      60003760,
+			DesiredQuantity: 1000,
+		})
+		assert.NoError(t, err)
+	}
+
+	// A second marker of 1338's at the same station, in a container
+	containerID := int64(9100)
+	err = markersRepository.Upsert(context.Background(), &models.StockpileMarker{
+		UserID:          44,
+		TypeID:          34,
+		OwnerType:       "character",
+		OwnerID:         1338,
+		LocationID:      60003760,
+		ContainerID:     &containerID,
+		DesiredQuantity: 500,
+	})
+	assert.NoError(t, err)
+
+	// 10 runs of 100 Tritanium at ME 10 consume 900, installed by character 1338 only
+	_, err = db.Exec(`
+		INSERT INTO sde_blueprint_materials (blueprint_type_id, activity, type_id, quantity)
+		VALUES (1000, 'manufacturing', 34, 100)
+	`)
+	assert.NoError(t, err)
+
+	_, err = db.Exec(`
+		INSERT INTO character_blueprints (item_id, user_id, owner_id, owner_type, type_id, location_id, material_efficiency)
+		VALUES (9000, 44, 1338, 'character', 1000, 60003760, 10)
+	`)
+	assert.NoError(t, err)
+
+	_, err = db.Exec(`
+		INSERT INTO esi_industry_jobs
+			(job_id, installer_id, user_id, facility_id, station_id, activity_id, blueprint_id, blueprint_type_id,
+			 blueprint_location_id, output_location_id, runs, status, duration, start_date, end_date)
+		VALUES (7000, 1338, 44, 60003760, 60003760, 1, 9000, 1000, 60003760, 60003760, 10, 'active', 3600,
+			NOW() - INTERVAL '1 day', NOW() + INTERVAL '1 day')
+	`)
+	assert.NoError(t, err)
+
+	consumption, err := markersRepository.GetConsumptionByUser(context.Background(), 44, 30)
+	assert.NoError(t, err)
+	assert.Len(t, consumption, 3)
+
+	// The job's usage is split across 1338's two markers rather than counted by each
+	for _, c := range consumption {
+		if c.OwnerID == 1338 {
+			assert.Equal(t, int64(450), c.ProductionConsumed)
+		} else {
+			assert.Equal(t, int64(0), c.ProductionConsumed)
+		}
+	}
+}
@@ -8,6 +8,46 @@ import (
 	"github.com/pkg/errors"
 )
 
+// stockpileMarkerHeldQuantity is a LATERAL subquery summing the stackable quantity a stockpile
+// marker aliased m holds in its scope: the station hangar or corporation division, or the
+// container when the marker has one.
+const stockpileMarkerHeldQuantity = `
+		SELECT SUM(q.quantity) AS quantity
+		FROM (
+			SELECT ca.quantity
+			FROM character_assets ca
+			WHERE m.owner_type = 'character'
+			  AND ca.user_id = m.user_id
+			  AND ca.character_id = m.owner_id
+			  AND ca.type_id = m.type_id
+			  AND (
+				(m.container_id IS NULL AND ca.location_id = m.location_id
+					AND ca.location_flag IN ('Hangar', 'Deliveries', 'AssetSafety'))
+				OR (m.container_id IS NOT NULL AND ca.location_id = m.container_id)
+			  )
+
+			UNION ALL
+
+			SELECT ca.quantity
+			FROM corporation_asset_locations loc
+			INNER JOIN corporation_assets ca ON (
+				ca.item_id = loc.item_id
+				AND ca.corporation_id = loc.corporation_id
+				AND ca.user_id = loc.user_id
+			)
+			WHERE m.owner_type = 'corporation'
+			  AND loc.user_id = m.user_id
+			  AND loc.corporation_id = m.owner_id
+			  AND loc.type_id = m.type_id
+			  AND (
+				(m.container_id IS NULL AND loc.station_id = m.location_id
+					AND loc.location_flag LIKE 'CorpSAG%'
+					AND (m.division_number IS NULL OR loc.division_number = m.division_number))
+				OR (m.container_id IS NOT NULL AND ca.location_id = m.container_id)
+			  )
+		) q
+`
+
 type StockpileMarkers struct {
 	db *sql.DB
 }
@@ -21,7 +61,9 @@ func (r *StockpileMarkers) GetByUser(ctx context.Context, userID int64) ([]*mode
 		SELECT user_id, type_id, owner_type, owner_id, location_id,
 		       container_id, division_number, desired_quantity, notes,
 		       price_source, price_percentage,
-		       plan_id, auto_production_parallelism, auto_production_enabled
+		       plan_id, auto_production_parallelism, auto_production_enabled,
+		       template_application_id, cover_days, min_desired_quantity,
+		       max_desired_quantity, auto_adjust_enabled
 		FROM stockpile_markers
 		WHERE user_id = $1
 		ORDER BY type_id, location_id
@@ -51,6 +93,11 @@ func (r *StockpileMarkers) GetByUser(ctx context.Context, userID int64) ([]*mode
 			&marker.PlanID,
 			&marker.AutoProductionParallelism,
 			&marker.AutoProductionEnabled,
+			&marker.TemplateApplicationID,
+			&marker.CoverDays,
+			&marker.MinDesiredQuantity,
+			&marker.MaxDesiredQuantity,
+			&marker.AutoAdjustEnabled,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan stockpile marker")
@@ -61,11 +108,14 @@ func (r *StockpileMarkers) GetByUser(ctx context.Context, userID int64) ([]*mode
 	return markers, nil
 }
 
+// Upsert creates or updates a marker. Consumption settings (cover days, min/max and
+// auto-adjust) left unset keep their stored values, so dialogs that don't edit them
+// can't switch auto-adjust off.
 func (r *StockpileMarkers) Upsert(ctx context.Context, marker *models.StockpileMarker) error {
 	query := `
 		INSERT INTO stockpile_markers
-		(user_id, type_id, owner_type, owner_id, location_id, container_id, division_number, desired_quantity, notes, price_source, price_percentage, plan_id, auto_production_parallelism, auto_production_enabled, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NOW())
+		(user_id, type_id, owner_type, owner_id, location_id, container_id, division_number, desired_quantity, notes, price_source, price_percentage, plan_id, auto_production_parallelism, auto_production_enabled, cover_days, min_desired_quantity, max_desired_quantity, auto_adjust_enabled, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, COALESCE($18::BOOLEAN, FALSE), NOW())
 		ON CONFLICT (user_id, type_id, owner_type, owner_id, location_id, COALESCE(container_id, 0::BIGINT), COALESCE(division_number, 0))
 		DO UPDATE SET
 			desired_quantity = EXCLUDED.desired_quantity,
@@ -75,6 +125,10 @@ func (r *StockpileMarkers) Upsert(ctx context.Context, marker *models.StockpileM
 			plan_id = EXCLUDED.plan_id,
 			auto_production_parallelism = EXCLUDED.auto_production_parallelism,
 			auto_production_enabled = EXCLUDED.auto_production_enabled,
+			cover_days = EXCLUDED.cover_days,
+			min_desired_quantity = EXCLUDED.min_desired_quantity,
+			max_desired_quantity = EXCLUDED.max_desired_quantity,
+			auto_adjust_enabled = EXCLUDED.auto_adjust_enabled,
 			updated_at = NOW()
 	`
 
@@ -93,6 +147,10 @@ func (r *StockpileMarkers) Upsert(ctx context.Context, marker *models.StockpileM
 		marker.PlanID,
 		marker.AutoProductionParallelism,
 		marker.AutoProductionEnabled,
+		marker.CoverDays,
+		marker.MinDesiredQuantity,
+		marker.MaxDesiredQuantity,
+		marker.AutoAdjustEnabled,
 	)
 	if err != nil {
 		return errors.Wrap(err, "failed to upsert stockpile marker")
@@ -115,7 +173,9 @@ func (r *StockpileMarkers) GetByContainerContext(
 		SELECT user_id, type_id, owner_type, owner_id, location_id,
 		       container_id, division_number, desired_quantity, notes,
 		       price_source, price_percentage,
-		       plan_id, auto_production_parallelism, auto_production_enabled
+		       plan_id, auto_production_parallelism, auto_production_enabled,
+		       template_application_id, cover_days, min_desired_quantity,
+		       max_desired_quantity, auto_adjust_enabled
 		FROM stockpile_markers
 		WHERE user_id = $1
 		  AND owner_type = $2
@@ -151,6 +211,11 @@ func (r *StockpileMarkers) GetByContainerContext(
 			&marker.PlanID,
 			&marker.AutoProductionParallelism,
 			&marker.AutoProductionEnabled,
+			&marker.TemplateApplicationID,
+			&marker.CoverDays,
+			&marker.MinDesiredQuantity,
+			&marker.MaxDesiredQuantity,
+			&marker.AutoAdjustEnabled,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan stockpile marker")
@@ -167,7 +232,9 @@ func (r *StockpileMarkers) GetAutoProductionMarkers(ctx context.Context) ([]*mod
 		SELECT user_id, type_id, owner_type, owner_id, location_id,
 		       container_id, division_number, desired_quantity, notes,
 		       price_source, price_percentage,
-		       plan_id, auto_production_parallelism, auto_production_enabled
+		       plan_id, auto_production_parallelism, auto_production_enabled,
+		       template_application_id, cover_days, min_desired_quantity,
+		       max_desired_quantity, auto_adjust_enabled
 		FROM stockpile_markers
 		WHERE auto_production_enabled = TRUE
 		  AND plan_id IS NOT NULL
@@ -198,6 +265,11 @@ func (r *StockpileMarkers) GetAutoProductionMarkers(ctx context.Context) ([]*mod
 			&marker.PlanID,
 			&marker.AutoProductionParallelism,
 			&marker.AutoProductionEnabled,
+			&marker.TemplateApplicationID,
+			&marker.CoverDays,
+			&marker.MinDesiredQuantity,
+			&marker.MaxDesiredQuantity,
+			&marker.AutoAdjustEnabled,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan auto-production marker")
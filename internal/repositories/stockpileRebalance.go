@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+// stockpilePositionsQuery returns, per owner, station and type, the stackable quantity held in
+// station hangars, corporation divisions and containers less stock reserved for plan runs and stock
+// already leaving on the owner's open transport jobs, the summed stockpile markers and the
+// quantity already on the owner's open transport jobs to the station. Only owner/type pairs with a stockpile
+// marker somewhere are returned.
+const stockpilePositionsQuery = `
+WITH held AS (
+	-- Character station hangar
+	SELECT 'character' AS owner_type, ca.character_id AS owner_id, stations.station_id, ca.type_id, ca.quantity
+	FROM character_assets ca
+	INNER JOIN stations ON stations.station_id = ca.location_id
+	WHERE ca.user_id = $1
+		AND NOT ca.is_singleton
+		AND ca.location_flag IN ('Hangar', 'Deliveries', 'AssetSafety')
+
+	UNION ALL
+
+	-- Character containers in a station hangar
+	SELECT 'character', ca.character_id, stations.station_id, ca.type_id, ca.quantity
+	FROM character_assets ca
+	INNER JOIN character_assets containers ON (
+		containers.item_id = ca.location_id
+		AND containers.user_id = ca.user_id
+		AND containers.character_id = ca.character_id
+	)
+	INNER JOIN stations ON stations.station_id = containers.location_id
+	WHERE ca.user_id = $1
+		AND NOT ca.is_singleton
+		AND ca.location_flag IN ('Unlocked', 'Locked')
+		AND containers.location_flag = 'Hangar'
+
+	UNION ALL
+
+	-- Corporation divisions and containers in a division
+	SELECT 'corporation', loc.corporation_id, loc.station_id, loc.type_id, ca.quantity
+	FROM corporation_asset_locations loc
+	INNER JOIN corporation_assets ca ON (
+		ca.item_id = loc.item_id
+		AND ca.corporation_id = loc.corporation_id
+		AND ca.user_id = loc.user_id
+	)
+	WHERE loc.user_id = $1
+		AND NOT ca.is_singleton
+		AND loc.station_id IS NOT NULL
+		AND loc.division_number IS NOT NULL
+		AND (loc.location_flag LIKE 'CorpSAG%' OR loc.location_flag IN ('Unlocked', 'Locked'))
+),
+reserved AS (
+	SELECT owner_type, owner_id, location_id AS station_id, type_id, SUM(quantity) AS quantity
+	FROM active_material_reservations
+	WHERE user_id = $1
+	GROUP BY owner_type, owner_id, location_id, type_id
+),
+-- Stock already committed to the owner's open transport jobs leaving the station
+outgoing AS (
+	SELECT j.owner_type, j.owner_id, j.origin_station_id AS station_id, i.type_id, SUM(i.quantity) AS quantity
+	FROM transport_jobs j
+	INNER JOIN transport_job_items i ON i.transport_job_id = j.id
+	WHERE j.user_id = $1
+		AND j.owner_id IS NOT NULL
+		AND j.status IN ('planned', 'in_transit', 'consolidated')
+	GROUP BY j.owner_type, j.owner_id, j.origin_station_id, i.type_id
+),
+held_totals AS (
+	SELECT h.owner_type, h.owner_id, h.station_id, h.type_id,
+		GREATEST(SUM(h.quantity) - COALESCE(MAX(r.quantity), 0) - COALESCE(MAX(o.quantity), 0), 0) AS quantity
+	FROM held h
+	LEFT JOIN reserved r ON (
+		r.owner_type = h.owner_type
+		AND r.owner_id = h.owner_id
+		AND r.station_id = h.station_id
+		AND r.type_id = h.type_id
+	)
+	LEFT JOIN outgoing o ON (
+		o.owner_type = h.owner_type
+		AND o.owner_id = h.owner_id
+		AND o.station_id = h.station_id
+		AND o.type_id = h.type_id
+	)
+	GROUP BY h.owner_type, h.owner_id, h.station_id, h.type_id
+),
+desired AS (
+	SELECT owner_type::text AS owner_type, owner_id, location_id AS station_id, type_id, SUM(desired_quantity) AS quantity
+	FROM stockpile_markers
+	WHERE user_id = $1
+	GROUP BY owner_type, owner_id, location_id, type_id
+),
+in_transit AS (
+	SELECT j.owner_type, j.owner_id, j.destination_station_id AS station_id, i.type_id, SUM(i.quantity) AS quantity
+	FROM transport_jobs j
+	INNER JOIN transport_job_items i ON i.transport_job_id = j.id
+	WHERE j.user_id = $1
+		AND j.owner_id IS NOT NULL
+		AND j.status IN ('planned', 'in_transit', 'consolidated')
+	GROUP BY j.owner_type, j.owner_id, j.destination_station_id, i.type_id
+),
+positions AS (
+	SELECT
+		COALESCE(h.owner_type, d.owner_type) AS owner_type,
+		COALESCE(h.owner_id, d.owner_id) AS owner_id,
+		COALESCE(h.station_id, d.station_id) AS station_id,
+		COALESCE(h.type_id, d.type_id) AS type_id,
+		COALESCE(h.quantity, 0) AS held,
+		COALESCE(d.quantity, 0) AS desired
+	FROM held_totals h
+	FULL OUTER JOIN desired d ON (
+		d.owner_type = h.owner_type
+		AND d.owner_id = h.owner_id
+		AND d.station_id = h.station_id
+		AND d.type_id = h.type_id
+	)
+)
+SELECT
+	p.owner_type,
+	p.owner_id,
+	COALESCE(resolve_owner_name(p.owner_type, p.owner_id), ''),
+	p.station_id,
+	COALESCE(stations.name, ''),
+	stations.solar_system_id,
+	constellations.region_id,
+	p.type_id,
+	assetTypes.type_name,
+	COALESCE(assetTypes.packaged_volume, assetTypes.volume, 0),
+	p.held,
+	p.desired,
+	CASE WHEN p.desired > 0 THEN COALESCE(transit.quantity, 0) ELSE 0 END
+FROM positions p
+INNER JOIN stations ON stations.station_id = p.station_id
+INNER JOIN solar_systems systems ON systems.solar_system_id = stations.solar_system_id
+INNER JOIN constellations ON constellations.constellation_id = systems.constellation_id
+INNER JOIN asset_item_types assetTypes ON assetTypes.type_id = p.type_id
+LEFT JOIN in_transit transit ON (
+	transit.owner_type = p.owner_type
+	AND transit.owner_id = p.owner_id
+	AND transit.station_id = p.station_id
+	AND transit.type_id = p.type_id
+)
+WHERE EXISTS (
+	SELECT 1 FROM desired d
+	WHERE d.owner_type = p.owner_type
+		AND d.owner_id = p.owner_id
+		AND d.type_id = p.type_id
+)
+ORDER BY p.owner_type, p.owner_id, p.type_id, p.station_id
+`
+
+// GetStockpilePositions returns the held, desired and in-transit quantities of every stockpiled
+// type at every station of the user's characters and corporations.
+func (r *Assets) GetStockpilePositions(ctx context.Context, user int64) ([]*models.StockpilePosition, error) {
+	rows, err := r.db.QueryContext(ctx, stockpilePositionsQuery, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query stockpile positions")
+	}
+	defer rows.Close()
+
+	positions := []*models.StockpilePosition{}
+	for rows.Next() {
+		position := &models.StockpilePosition{}
+		err = rows.Scan(
+			&position.OwnerType,
+			&position.OwnerID,
+			&position.OwnerName,
+			&position.StationID,
+			&position.StationName,
+			&position.SolarSystemID,
+			&position.RegionID,
+			&position.TypeID,
+			&position.TypeName,
+			&position.UnitVolume,
+			&position.HeldQuantity,
+			&position.DesiredQuantity,
+			&position.InTransitQuantity,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan stockpile position")
+		}
+		positions = append(positions, position)
+	}
+
+	return positions, nil
+}
@@ -0,0 +1,192 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StockpilePositions_ShouldCombineHeldDesiredAndInTransit(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	setupTestUniverse(t, db)
+
+	userRepository := repositories.NewUserRepository(db)
+	playerCorpsRepository := repositories.NewPlayerCorporations(db)
+	corpAssetsRepository := repositories.NewCorporationAssets(db)
+	markersRepository := repositories.NewStockpileMarkers(db)
+	transportJobsRepository := repositories.NewTransportJobs(db)
+	assetsRepository := repositories.NewAssets(db)
+
+	err = userRepository.Add(context.Background(), &repositories.User{ID: 42, Name: "Logistics"})
+	assert.NoError(t, err)
+
+	err = playerCorpsRepository.Upsert(context.Background(), repositories.PlayerCorporation{
+		ID:              2001,
+		UserID:          42,
+		Name:            "Logistics Corp",
+		EsiToken:        "token123",
+		EsiRefreshToken: "refresh456",
+		EsiExpiresOn:    time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	err = corpAssetsRepository.Upsert(context.Background(), 2001, 42, []*models.EveAsset{
+		{ItemID: 5000, IsSingleton: true, LocationID: 60003760, LocationType: "station", Quantity: 1, TypeID: 27, LocationFlag: "OfficeFolder"},
+		{ItemID: 5001, LocationID: 5000, LocationType: "item", Quantity: 10000, TypeID: 34, LocationFlag: "CorpSAG1"},
+		{ItemID: 5002, IsSingleton: true, LocationID: 5000, LocationType: "item", Quantity: 1, TypeID: 3293, LocationFlag: "CorpSAG2"},
+		{ItemID: 5003, LocationID: 5002, LocationType: "item", Quantity: 500, TypeID: 34, LocationFlag: "Unlocked"},
+		{ItemID: 5004, LocationID: 5000, LocationType: "item", Quantity: 300, TypeID: 35, LocationFlag: "CorpSAG1"},
+	})
+	assert.NoError(t, err)
+
+	division := 1
+	err = markersRepository.Upsert(context.Background(), &models.StockpileMarker{
+		UserID:          42,
+		TypeID:          34,
+		OwnerType:       "corporation",
+		OwnerID:         2001,
+		LocationID:      60003761,
+		DivisionNumber:  &division,
+		DesiredQuantity: 4000,
+	})
+	assert.NoError(t, err)
+
+	// Only the corporation's own job counts toward its deficit
+	for _, job := range []struct {
+		ownerType string
+		ownerID   int64
+		quantity  int
+	}{
+		{"corporation", 2001, 1000},
+		{"character", 1337, 700},
+	} {
+		_, err = transportJobsRepository.Create(context.Background(), &models.TransportJob{
+			UserID:               42,
+			OriginStationID:      60003760,
+			DestinationStationID: 60003761,
+			OriginSystemID:       30000142,
+			DestinationSystemID:  30000142,
+			TransportMethod:      "freighter",
+			RoutePreference:      "shortest",
+			FulfillmentType:      "self_haul",
+			OwnerType:            &job.ownerType,
+			OwnerID:              &job.ownerID,
+			Items:                []*models.TransportJobItem{{TypeID: 34, Quantity: job.quantity}},
+		})
+		assert.NoError(t, err)
+	}
+
+	positions, err := assetsRepository.GetStockpilePositions(context.Background(), 42)
+	assert.NoError(t, err)
+
+	// Pyerite has no marker anywhere and is not returned
+	assert.Len(t, positions, 2)
+
+	held := positions[0]
+	assert.Equal(t, int64(60003760), held.StationID)
+	assert.Equal(t, "Logistics Corp", held.OwnerName)
+	assert.Equal(t, int64(9500), held.HeldQuantity, "division and container stock are combined, less the corporation's outgoing job")
+	assert.Equal(t, int64(0), held.DesiredQuantity)
+	assert.Equal(t, int64(10000002), held.RegionID)
+
+	wanted := positions[1]
+	assert.Equal(t, int64(60003761), wanted.StationID)
+	assert.Equal(t, int64(0), wanted.HeldQuantity)
+	assert.Equal(t, int64(4000), wanted.DesiredQuantity)
+	assert.Equal(t, int64(1000), wanted.InTransitQuantity)
+}
+
+func Test_StockpilePositions_ShouldNotOfferStockAlreadyLeaving(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	setupTestUniverse(t, db)
+
+	userRepository := repositories.NewUserRepository(db)
+	playerCorpsRepository := repositories.NewPlayerCorporations(db)
+	corpAssetsRepository := repositories.NewCorporationAssets(db)
+	markersRepository := repositories.NewStockpileMarkers(db)
+	transportJobsRepository := repositories.NewTransportJobs(db)
+	assetsRepository := repositories.NewAssets(db)
+
+	err = userRepository.Add(context.Background(), &repositories.User{ID: 43, Name: "Surplus"})
+	assert.NoError(t, err)
+
+	err = playerCorpsRepository.Upsert(context.Background(), repositories.PlayerCorporation{
+		ID:              2002,
+		UserID:          43,
+		Name:            "Surplus Corp",
+		EsiToken:        "token123",
+		EsiRefreshToken: "refresh456",
+		EsiExpiresOn:    time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	err = corpAssetsRepository.Upsert(context.Background(), 2002, 43, []*models.EveAsset{
+		{ItemID: 6000, IsSingleton: true, LocationID: 60003760, LocationType: "station", Quantity: 1, TypeID: 27, LocationFlag: "OfficeFolder"},
+		{ItemID: 6001, LocationID: 6000, LocationType: "item", Quantity: 5000, TypeID: 34, LocationFlag: "CorpSAG1"},
+	})
+	assert.NoError(t, err)
+
+	division := 1
+	err = markersRepository.Upsert(context.Background(), &models.StockpileMarker{
+		UserID:          43,
+		TypeID:          34,
+		OwnerType:       "corporation",
+		OwnerID:         2002,
+		LocationID:      60003760,
+		DivisionNumber:  &division,
+		DesiredQuantity: 1000,
+	})
+	assert.NoError(t, err)
+
+	// Planned and in-transit jobs out of the station both hold stock; cancelled ones release it
+	ownerType, ownerID := "corporation", int64(2002)
+	for _, job := range []struct {
+		status   string
+		quantity int
+	}{
+		{"planned", 1500},
+		{"in_transit", 1000},
+		{"cancelled", 2000},
+	} {
+		created, err := transportJobsRepository.Create(context.Background(), &models.TransportJob{
+			UserID:               43,
+			OriginStationID:      60003760,
+			DestinationStationID: 60003761,
+			OriginSystemID:       30000142,
+			DestinationSystemID:  30000142,
+			TransportMethod:      "freighter",
+			RoutePreference:      "shortest",
+			FulfillmentType:      "self_haul",
+			OwnerType:            &ownerType,
+			OwnerID:              &ownerID,
+			Items:                []*models.TransportJobItem{{TypeID: 34, Quantity: job.quantity}},
+		})
+		assert.NoError(t, err)
+		if job.status != "planned" {
+			err = transportJobsRepository.UpdateStatus(context.Background(), created.ID, 43, job.status)
+			assert.NoError(t, err)
+		}
+	}
+
+	positions, err := assetsRepository.GetStockpilePositions(context.Background(), 43)
+	assert.NoError(t, err)
+
+	var origin *models.StockpilePosition
+	for _, position := range positions {
+		if position.StationID == 60003760 {
+			origin = position
+		}
+	}
+	if assert.NotNil(t, origin) {
+		assert.Equal(t, int64(2500), origin.HeldQuantity)
+		assert.Equal(t, int64(1000), origin.DesiredQuantity)
+	}
+}
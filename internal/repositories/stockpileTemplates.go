@@ -0,0 +1,594 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type StockpileTemplates struct {
+	db *sql.DB
+}
+
+func NewStockpileTemplates(db *sql.DB) *StockpileTemplates {
+	return &StockpileTemplates{db: db}
+}
+
+// GetByUser returns all templates of a user with their items and applications.
+func (r *StockpileTemplates) GetByUser(ctx context.Context, userID int64) ([]*models.StockpileTemplate, error) {
+	query := `
+		SELECT id, user_id, name, notes, price_source, price_percentage, created_at, updated_at
+		FROM stockpile_templates
+		WHERE user_id = $1
+		ORDER BY name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query stockpile templates")
+	}
+	defer rows.Close()
+
+	templates := []*models.StockpileTemplate{}
+	templateMap := map[int64]*models.StockpileTemplate{}
+	for rows.Next() {
+		template := &models.StockpileTemplate{
+			Items:        []*models.StockpileTemplateItem{},
+			Applications: []*models.StockpileTemplateApplication{},
+		}
+		err = rows.Scan(
+			&template.ID,
+			&template.UserID,
+			&template.Name,
+			&template.Notes,
+			&template.PriceSource,
+			&template.PricePercentage,
+			&template.CreatedAt,
+			&template.UpdatedAt,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan stockpile template")
+		}
+		templates = append(templates, template)
+		templateMap[template.ID] = template
+	}
+
+	if len(templates) == 0 {
+		return templates, nil
+	}
+
+	err = r.loadItemsAndApplications(ctx, userID, templateMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// GetByID returns a single template of the user, or nil when it does not exist.
+func (r *StockpileTemplates) GetByID(ctx context.Context, id, userID int64) (*models.StockpileTemplate, error) {
+	query := `
+		SELECT id, user_id, name, notes, price_source, price_percentage, created_at, updated_at
+		FROM stockpile_templates
+		WHERE id = $1 AND user_id = $2
+	`
+
+	template := &models.StockpileTemplate{
+		Items:        []*models.StockpileTemplateItem{},
+		Applications: []*models.StockpileTemplateApplication{},
+	}
+	err := r.db.QueryRowContext(ctx, query, id, userID).Scan(
+		&template.ID,
+		&template.UserID,
+		&template.Name,
+		&template.Notes,
+		&template.PriceSource,
+		&template.PricePercentage,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query stockpile template")
+	}
+
+	err = r.loadItemsAndApplications(ctx, userID, map[int64]*models.StockpileTemplate{template.ID: template})
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (r *StockpileTemplates) loadItemsAndApplications(ctx context.Context, userID int64, templates map[int64]*models.StockpileTemplate) error {
+	itemsQuery := `
+		SELECT i.template_id, i.type_id, COALESCE(t.type_name, ''), i.quantity
+		FROM stockpile_template_items i
+		INNER JOIN stockpile_templates st ON st.id = i.template_id
+		LEFT JOIN asset_item_types t ON t.type_id = i.type_id
+		WHERE st.user_id = $1
+		ORDER BY t.type_name, i.type_id
+	`
+
+	itemRows, err := r.db.QueryContext(ctx, itemsQuery, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to query stockpile template items")
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var templateID int64
+		item := &models.StockpileTemplateItem{}
+		err = itemRows.Scan(&templateID, &item.TypeID, &item.TypeName, &item.Quantity)
+		if err != nil {
+			return errors.Wrap(err, "failed to scan stockpile template item")
+		}
+		if template, ok := templates[templateID]; ok {
+			template.Items = append(template.Items, item)
+		}
+	}
+
+	applicationsQuery := `
+		SELECT id, template_id, user_id, owner_type, owner_id, location_id,
+		       container_id, division_number, multiplier, created_at
+		FROM stockpile_template_applications
+		WHERE user_id = $1
+		ORDER BY id
+	`
+
+	applicationRows, err := r.db.QueryContext(ctx, applicationsQuery, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to query stockpile template applications")
+	}
+	defer applicationRows.Close()
+
+	for applicationRows.Next() {
+		application := &models.StockpileTemplateApplication{}
+		err = applicationRows.Scan(
+			&application.ID,
+			&application.TemplateID,
+			&application.UserID,
+			&application.OwnerType,
+			&application.OwnerID,
+			&application.LocationID,
+			&application.ContainerID,
+			&application.DivisionNumber,
+			&application.Multiplier,
+			&application.CreatedAt,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to scan stockpile template application")
+		}
+		if template, ok := templates[application.TemplateID]; ok {
+			template.Applications = append(template.Applications, application)
+		}
+	}
+
+	skipped, err := templateSkippedTypes(ctx, r.db, userID, nil)
+	if err != nil {
+		return err
+	}
+	for _, template := range templates {
+		for _, application := range template.Applications {
+			application.SkippedTypeIDs = skipped[application.ID]
+		}
+	}
+
+	return nil
+}
+
+// Create inserts a template with its items.
+func (r *StockpileTemplates) Create(ctx context.Context, template *models.StockpileTemplate) (*models.StockpileTemplate, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO stockpile_templates (user_id, name, notes, price_source, price_percentage)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		template.UserID,
+		template.Name,
+		template.Notes,
+		template.PriceSource,
+		template.PricePercentage,
+	).Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert stockpile template")
+	}
+
+	err = insertTemplateItems(ctx, tx, template.ID, template.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "failed to commit stockpile template")
+	}
+
+	template.Applications = []*models.StockpileTemplateApplication{}
+	return template, nil
+}
+
+// Update replaces the template's settings and items and re-materialises the stockpile markers of
+// every location the template is applied to.
+func (r *StockpileTemplates) Update(ctx context.Context, template *models.StockpileTemplate) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE stockpile_templates
+		SET name = $3, notes = $4, price_source = $5, price_percentage = $6, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+	`
+
+	result, err := tx.ExecContext(ctx, query,
+		template.ID,
+		template.UserID,
+		template.Name,
+		template.Notes,
+		template.PriceSource,
+		template.PricePercentage,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update stockpile template")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return errors.New("stockpile template not found")
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM stockpile_template_items WHERE template_id = $1`, template.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to clear stockpile template items")
+	}
+
+	err = insertTemplateItems(ctx, tx, template.ID, template.Items)
+	if err != nil {
+		return err
+	}
+
+	err = syncTemplateMarkers(ctx, tx, template.ID, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit stockpile template update")
+	}
+
+	return nil
+}
+
+// Delete removes a template. Its applications and their stockpile markers are removed by cascade.
+func (r *StockpileTemplates) Delete(ctx context.Context, id, userID int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM stockpile_templates WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete stockpile template")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return errors.New("stockpile template not found")
+	}
+
+	return nil
+}
+
+// Apply places a template at a location scope and creates its stockpile markers. Types that
+// already have a marker at that scope keep it and are reported in SkippedTypeIDs.
+func (r *StockpileTemplates) Apply(ctx context.Context, application *models.StockpileTemplateApplication) (*models.StockpileTemplateApplication, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO stockpile_template_applications
+			(template_id, user_id, owner_type, owner_id, location_id, container_id, division_number, multiplier)
+		SELECT id, user_id, $3, $4, $5, $6, $7, $8
+		FROM stockpile_templates
+		WHERE id = $1 AND user_id = $2
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		application.TemplateID,
+		application.UserID,
+		application.OwnerType,
+		application.OwnerID,
+		application.LocationID,
+		application.ContainerID,
+		application.DivisionNumber,
+		application.Multiplier,
+	).Scan(&application.ID, &application.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("stockpile template not found")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert stockpile template application")
+	}
+
+	err = syncTemplateMarkers(ctx, tx, application.TemplateID, &application.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	skipped, err := templateSkippedTypes(ctx, tx, application.UserID, &application.ID)
+	if err != nil {
+		return nil, err
+	}
+	application.SkippedTypeIDs = skipped[application.ID]
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "failed to commit stockpile template application")
+	}
+
+	return application, nil
+}
+
+// UpdateApplicationMultiplier changes the multiplier of an application and rescales its markers.
+func (r *StockpileTemplates) UpdateApplicationMultiplier(ctx context.Context, applicationID, templateID, userID int64, multiplier float64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE stockpile_template_applications
+		SET multiplier = $4
+		WHERE id = $1 AND template_id = $2 AND user_id = $3
+	`, applicationID, templateID, userID, multiplier)
+	if err != nil {
+		return errors.Wrap(err, "failed to update stockpile template application")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return errors.New("stockpile template application not found")
+	}
+
+	err = syncTemplateMarkers(ctx, tx, templateID, &applicationID)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit stockpile template application update")
+	}
+
+	return nil
+}
+
+// RemoveApplication removes a template from a location. Its stockpile markers are removed by cascade.
+func (r *StockpileTemplates) RemoveApplication(ctx context.Context, applicationID, templateID, userID int64) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM stockpile_template_applications
+		WHERE id = $1 AND template_id = $2 AND user_id = $3
+	`, applicationID, templateID, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete stockpile template application")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return errors.New("stockpile template application not found")
+	}
+
+	return nil
+}
+
+// GetApplicationDeficits reports the current stock against the desired quantity of every
+// template type at every location the template is applied to. Stock reserved for plan runs is
+// not counted.
+func (r *StockpileTemplates) GetApplicationDeficits(ctx context.Context, templateID, userID int64) ([]*models.StockpileTemplateApplicationDeficits, error) {
+	template, err := r.GetByID(ctx, templateID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, nil
+	}
+
+	query := `
+		SELECT
+			m.template_application_id,
+			m.type_id,
+			COALESCE(t.type_name, ''),
+			m.desired_quantity,
+			COALESCE(held.quantity, 0) - reserved_material_quantity(m.user_id, m.owner_type, m.owner_id, m.location_id, m.container_id, m.division_number, m.type_id),
+			COALESCE(market.buy_price, 0),
+			resolve_location_name(m.location_id),
+			resolve_owner_name(m.owner_type, m.owner_id)
+		FROM stockpile_markers m
+		INNER JOIN stockpile_template_applications a ON a.id = m.template_application_id
+		LEFT JOIN asset_item_types t ON t.type_id = m.type_id
+		LEFT JOIN market_prices market ON market.type_id = m.type_id AND market.region_id = 10000002
+		LEFT JOIN LATERAL (` + stockpileMarkerHeldQuantity + `) held ON TRUE
+		WHERE a.template_id = $1 AND a.user_id = $2
+		ORDER BY t.type_name, m.type_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, templateID, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query stockpile template deficits")
+	}
+	defer rows.Close()
+
+	reports := []*models.StockpileTemplateApplicationDeficits{}
+	reportMap := map[int64]*models.StockpileTemplateApplicationDeficits{}
+	for _, application := range template.Applications {
+		report := &models.StockpileTemplateApplicationDeficits{
+			Application: application,
+			Items:       []*models.StockpileTemplateDeficitItem{},
+			IsComplete:  true,
+		}
+		reports = append(reports, report)
+		reportMap[application.ID] = report
+	}
+
+	for rows.Next() {
+		var applicationID int64
+		var buyPrice float64
+		item := &models.StockpileTemplateDeficitItem{}
+		var locationName, ownerName string
+		err = rows.Scan(
+			&applicationID,
+			&item.TypeID,
+			&item.TypeName,
+			&item.DesiredQuantity,
+			&item.CurrentQuantity,
+			&buyPrice,
+			&locationName,
+			&ownerName,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan stockpile template deficit")
+		}
+
+		report, ok := reportMap[applicationID]
+		if !ok {
+			continue
+		}
+		report.LocationName = locationName
+		report.OwnerName = ownerName
+
+		item.Delta = item.CurrentQuantity - item.DesiredQuantity
+		if item.Delta < 0 {
+			item.DeficitValue = float64(-item.Delta) * buyPrice
+			report.MissingTypes++
+			report.TotalDeficitValue += item.DeficitValue
+			report.IsComplete = false
+		}
+		report.Items = append(report.Items, item)
+	}
+
+	return reports, nil
+}
+
+func insertTemplateItems(ctx context.Context, tx *sql.Tx, templateID int64, items []*models.StockpileTemplateItem) error {
+	for _, item := range items {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO stockpile_template_items (template_id, type_id, quantity)
+			VALUES ($1, $2, $3)
+		`, templateID, item.TypeID, item.Quantity)
+		if err != nil {
+			return errors.Wrap(err, "failed to insert stockpile template item")
+		}
+	}
+	return nil
+}
+
+// syncTemplateMarkers makes the stockpile markers of a template's applications match the template:
+// markers for types no longer in the template are removed and every template type is upserted with
+// its scaled quantity and the template's price settings. When applicationID is set only that
+// application is synced. Plan and auto-production settings on existing markers are kept. Markers
+// the application does not own, such as ones the user set by hand, are left alone.
+func syncTemplateMarkers(ctx context.Context, tx *sql.Tx, templateID int64, applicationID *int64) error {
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM stockpile_markers m
+		USING stockpile_template_applications a
+		WHERE m.template_application_id = a.id
+		  AND a.template_id = $1
+		  AND ($2::BIGINT IS NULL OR a.id = $2)
+		  AND NOT EXISTS (
+			SELECT 1 FROM stockpile_template_items i
+			WHERE i.template_id = a.template_id AND i.type_id = m.type_id
+		  )
+	`, templateID, applicationID)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove stale template stockpile markers")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO stockpile_markers
+		(user_id, type_id, owner_type, owner_id, location_id, container_id, division_number,
+		 desired_quantity, notes, price_source, price_percentage, template_application_id, updated_at)
+		SELECT
+			a.user_id, i.type_id, a.owner_type, a.owner_id, a.location_id, a.container_id, a.division_number,
+			CEIL(i.quantity * a.multiplier)::BIGINT, 'Template: ' || st.name, st.price_source, st.price_percentage, a.id, NOW()
+		FROM stockpile_template_applications a
+		INNER JOIN stockpile_templates st ON st.id = a.template_id
+		INNER JOIN stockpile_template_items i ON i.template_id = a.template_id
+		WHERE a.template_id = $1
+		  AND ($2::BIGINT IS NULL OR a.id = $2)
+		ON CONFLICT (user_id, type_id, owner_type, owner_id, location_id, COALESCE(container_id, 0::BIGINT), COALESCE(division_number, 0))
+		DO UPDATE SET
+			desired_quantity = EXCLUDED.desired_quantity,
+			notes = EXCLUDED.notes,
+			price_source = EXCLUDED.price_source,
+			price_percentage = EXCLUDED.price_percentage,
+			updated_at = NOW()
+		WHERE stockpile_markers.template_application_id = EXCLUDED.template_application_id
+	`, templateID, applicationID)
+	if err != nil {
+		return errors.Wrap(err, "failed to upsert template stockpile markers")
+	}
+
+	return nil
+}
+
+type templateQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// templateSkippedTypes returns, per application, the template types whose marker at the
+// application's scope belongs to someone else: the user or another template application.
+func templateSkippedTypes(ctx context.Context, q templateQuerier, userID int64, applicationID *int64) (map[int64][]int64, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT a.id, m.type_id
+		FROM stockpile_template_applications a
+		INNER JOIN stockpile_template_items i ON i.template_id = a.template_id
+		INNER JOIN stockpile_markers m ON m.user_id = a.user_id
+			AND m.type_id = i.type_id
+			AND m.owner_type = a.owner_type
+			AND m.owner_id = a.owner_id
+			AND m.location_id = a.location_id
+			AND COALESCE(m.container_id, 0) = COALESCE(a.container_id, 0)
+			AND COALESCE(m.division_number, 0) = COALESCE(a.division_number, 0)
+		WHERE a.user_id = $1
+		  AND ($2::BIGINT IS NULL OR a.id = $2)
+		  AND m.template_application_id IS DISTINCT FROM a.id
+		ORDER BY a.id, m.type_id
+	`, userID, applicationID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query skipped template types")
+	}
+	defer rows.Close()
+
+	skipped := map[int64][]int64{}
+	for rows.Next() {
+		var id, typeID int64
+		if err := rows.Scan(&id, &typeID); err != nil {
+			return nil, errors.Wrap(err, "failed to scan skipped template type")
+		}
+		skipped[id] = append(skipped[id], typeID)
+	}
+
+	return skipped, nil
+}
@@ -0,0 +1,236 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StockpileTemplates_ApplyAndPropagateEdits(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	setupTestUniverse(t, db)
+
+	userRepository := repositories.NewUserRepository(db)
+	templatesRepository := repositories.NewStockpileTemplates(db)
+	markersRepository := repositories.NewStockpileMarkers(db)
+
+	err = userRepository.Add(context.Background(), &repositories.User{ID: 42, Name: "Stager"})
+	assert.NoError(t, err)
+
+	priceSource := "jita_sell"
+	template, err := templatesRepository.Create(context.Background(), &models.StockpileTemplate{
+		UserID:      42,
+		Name:        "Staging Kit",
+		PriceSource: &priceSource,
+		Items: []*models.StockpileTemplateItem{
+			{TypeID: 34, Quantity: 1000},
+			{TypeID: 35, Quantity: 15},
+		},
+	})
+	assert.NoError(t, err)
+
+	for _, location := range []int64{60003760, 60003761} {
+		_, err = templatesRepository.Apply(context.Background(), &models.StockpileTemplateApplication{
+			TemplateID: template.ID,
+			UserID:     42,
+			OwnerType:  "character",
+			OwnerID:    1337,
+			LocationID: location,
+			Multiplier: 1.5,
+		})
+		assert.NoError(t, err)
+	}
+
+	markers, err := markersRepository.GetByUser(context.Background(), 42)
+	assert.NoError(t, err)
+	assert.Len(t, markers, 4)
+	for _, marker := range markers {
+		assert.NotNil(t, marker.TemplateApplicationID)
+		assert.Equal(t, "jita_sell", *marker.PriceSource)
+		if marker.TypeID == 35 {
+			assert.Equal(t, int64(23), marker.DesiredQuantity, "scaled quantities round up")
+		}
+	}
+
+	// Dropping a type and changing a quantity updates every applied location
+	template.Items = []*models.StockpileTemplateItem{{TypeID: 34, Quantity: 2000}}
+	err = templatesRepository.Update(context.Background(), template)
+	assert.NoError(t, err)
+
+	markers, err = markersRepository.GetByUser(context.Background(), 42)
+	assert.NoError(t, err)
+	assert.Len(t, markers, 2)
+	for _, marker := range markers {
+		assert.Equal(t, int64(34), marker.TypeID)
+		assert.Equal(t, int64(3000), marker.DesiredQuantity)
+	}
+
+	loaded, err := templatesRepository.GetByID(context.Background(), template.ID, 42)
+	assert.NoError(t, err)
+	assert.Len(t, loaded.Items, 1)
+	assert.Len(t, loaded.Applications, 2)
+
+	err = templatesRepository.RemoveApplication(context.Background(), loaded.Applications[0].ID, template.ID, 42)
+	assert.NoError(t, err)
+
+	markers, err = markersRepository.GetByUser(context.Background(), 42)
+	assert.NoError(t, err)
+	assert.Len(t, markers, 1)
+
+	err = templatesRepository.Delete(context.Background(), template.ID, 42)
+	assert.NoError(t, err)
+
+	markers, err = markersRepository.GetByUser(context.Background(), 42)
+	assert.NoError(t, err)
+	assert.Len(t, markers, 0)
+
+	missing, err := templatesRepository.GetByID(context.Background(), template.ID, 42)
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func Test_StockpileTemplates_ApplicationDeficits(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	setupTestUniverse(t, db)
+
+	userRepository := repositories.NewUserRepository(db)
+	characterRepository := repositories.NewCharacterRepository(db)
+	characterAssetsRepository := repositories.NewCharacterAssets(db)
+	marketPricesRepository := repositories.NewMarketPrices(db)
+	templatesRepository := repositories.NewStockpileTemplates(db)
+
+	err = userRepository.Add(context.Background(), &repositories.User{ID: 42, Name: "Stager"})
+	assert.NoError(t, err)
+
+	err = characterRepository.Add(context.Background(), &repositories.Character{ID: 1337, Name: "Stager One", UserID: 42})
+	assert.NoError(t, err)
+
+	err = characterAssetsRepository.UpdateAssets(context.Background(), 1337, 42, []*models.EveAsset{
+		{ItemID: 1001, LocationID: 60003760, LocationType: "station", Quantity: 1500, TypeID: 34, LocationFlag: "Hangar"},
+		{ItemID: 1002, LocationID: 60003760, LocationType: "station", Quantity: 5, TypeID: 35, LocationFlag: "Hangar"},
+	})
+	assert.NoError(t, err)
+
+	err = marketPricesRepository.UpsertPrices(context.Background(), []models.MarketPrice{
+		{TypeID: 35, RegionID: 10000002, BuyPrice: ptrFloat64(10)},
+	})
+	assert.NoError(t, err)
+
+	template, err := templatesRepository.Create(context.Background(), &models.StockpileTemplate{
+		UserID: 42,
+		Name:   "Staging Kit",
+		Items: []*models.StockpileTemplateItem{
+			{TypeID: 34, Quantity: 1000},
+			{TypeID: 35, Quantity: 20},
+		},
+	})
+	assert.NoError(t, err)
+
+	_, err = templatesRepository.Apply(context.Background(), &models.StockpileTemplateApplication{
+		TemplateID: template.ID,
+		UserID:     42,
+		OwnerType:  "character",
+		OwnerID:    1337,
+		LocationID: 60003760,
+		Multiplier: 1,
+	})
+	assert.NoError(t, err)
+
+	reports, err := templatesRepository.GetApplicationDeficits(context.Background(), template.ID, 42)
+	assert.NoError(t, err)
+	assert.Len(t, reports, 1)
+
+	report := reports[0]
+	assert.Equal(t, "Stager One", report.OwnerName)
+	assert.False(t, report.IsComplete)
+	assert.Equal(t, 1, report.MissingTypes)
+	assert.Equal(t, 150.0, report.TotalDeficitValue)
+	assert.Len(t, report.Items, 2)
+
+	for _, item := range report.Items {
+		switch item.TypeID {
+		case 34:
+			assert.Equal(t, int64(500), item.Delta)
+		case 35:
+			assert.Equal(t, int64(-15), item.Delta)
+		}
+	}
+}
+
+func Test_StockpileTemplates_LeavesManualMarkersAlone(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	setupTestUniverse(t, db)
+
+	userRepository := repositories.NewUserRepository(db)
+	templatesRepository := repositories.NewStockpileTemplates(db)
+	markersRepository := repositories.NewStockpileMarkers(db)
+
+	err = userRepository.Add(context.Background(), &repositories.User{ID: 43, Name: "Manual Stager"})
+	assert.NoError(t, err)
+
+	notes := "my own"
+	err = markersRepository.Upsert(context.Background(), &models.StockpileMarker{
+		UserID:          43,
+		TypeID:          34,
+		OwnerType:       "character",
+		OwnerID:         1338,
+		LocationID:      60003760,
+		DesiredQuantity: 50,
+		Notes:           &notes,
+	})
+	assert.NoError(t, err)
+
+	template, err := templatesRepository.Create(context.Background(), &models.StockpileTemplate{
+		UserID: 43,
+		Name:   "Kit",
+		Items: []*models.StockpileTemplateItem{
+			{TypeID: 34, Quantity: 1000},
+			{TypeID: 35, Quantity: 10},
+		},
+	})
+	assert.NoError(t, err)
+
+	application, err := templatesRepository.Apply(context.Background(), &models.StockpileTemplateApplication{
+		TemplateID: template.ID,
+		UserID:     43,
+		OwnerType:  "character",
+		OwnerID:    1338,
+		LocationID: 60003760,
+		Multiplier: 1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{34}, application.SkippedTypeIDs)
+
+	loaded, err := templatesRepository.GetByID(context.Background(), template.ID, 43)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{34}, loaded.Applications[0].SkippedTypeIDs)
+
+	markers, err := markersRepository.GetByUser(context.Background(), 43)
+	assert.NoError(t, err)
+	assert.Len(t, markers, 2)
+	for _, marker := range markers {
+		if marker.TypeID == 34 {
+			assert.Nil(t, marker.TemplateApplicationID)
+			assert.Equal(t, int64(50), marker.DesiredQuantity)
+		}
+	}
+
+	// Removing the application keeps the manual marker
+	err = templatesRepository.RemoveApplication(context.Background(), application.ID, template.ID, 43)
+	assert.NoError(t, err)
+
+	markers, err = markersRepository.GetByUser(context.Background(), 43)
+	assert.NoError(t, err)
+	assert.Len(t, markers, 1)
+	assert.Equal(t, int64(34), markers[0].TypeID)
+	assert.Equal(t, "my own", *markers[0].Notes)
+}
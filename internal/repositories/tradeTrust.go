@@ -0,0 +1,256 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"math"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+type TradeTrust struct {
+	db *sql.DB
+}
+
+func NewTradeTrust(db *sql.DB) *TradeTrust {
+	return &TradeTrust{db: db}
+}
+
+// GetStats returns the trade record of each user, keyed by user ID. Every requested user gets an
+// entry, with zero counts and no score if they have never traded.
+//
+// Cancellations count against both sides since either may cancel. An expiry only counts against
+// the side that missed the deadline: the seller for a purchase that expired pending, the buyer
+// for one that expired waiting on contract acceptance.
+func (r *TradeTrust) GetStats(ctx context.Context, userIDs []int64) (map[int64]*models.TradeTrustStats, error) {
+	stats := map[int64]*models.TradeTrustStats{}
+	if len(userIDs) == 0 {
+		return stats, nil
+	}
+	uniqueIDs := []int64{}
+	for _, userID := range userIDs {
+		if _, ok := stats[userID]; !ok {
+			stats[userID] = &models.TradeTrustStats{UserID: userID}
+			uniqueIDs = append(uniqueIDs, userID)
+		}
+	}
+
+	query := `
+		WITH trades AS (
+			SELECT
+				u.user_id,
+				pt.status,
+				pt.total_price,
+				pt.seller_user_id = u.user_id AS as_seller,
+				pt.expired_from_status,
+				pt.purchased_at,
+				pt.contract_created_at
+			FROM unnest($1::bigint[]) AS u(user_id)
+			JOIN purchase_transactions pt ON pt.buyer_user_id = u.user_id OR pt.seller_user_id = u.user_id
+		)
+		SELECT
+			user_id,
+			COUNT(*) FILTER (WHERE status = 'completed'),
+			COUNT(*) FILTER (WHERE status = 'cancelled'),
+			COUNT(*) FILTER (WHERE status = 'expired' AND (
+				(expired_from_status = 'pending' AND as_seller) OR
+				(expired_from_status = 'contract_created' AND NOT as_seller)
+			)),
+			COUNT(*) FILTER (WHERE status = 'disputed'),
+			percentile_cont(0.5) WITHIN GROUP (
+				ORDER BY EXTRACT(EPOCH FROM (contract_created_at - purchased_at)) / 3600
+			) FILTER (WHERE as_seller AND contract_created_at IS NOT NULL),
+			COALESCE(SUM(total_price) FILTER (WHERE status = 'completed'), 0)
+		FROM trades
+		GROUP BY user_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(uniqueIDs))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query trade stats")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int64
+		var s models.TradeTrustStats
+		err = rows.Scan(
+			&userID,
+			&s.CompletedCount,
+			&s.CancelledCount,
+			&s.ExpiredCount,
+			&s.DisputedCount,
+			&s.MedianContractHours,
+			&s.TotalVolume,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan trade stats")
+		}
+		s.UserID = userID
+		stats[userID] = &s
+	}
+	rows.Close()
+
+	ratingRows, err := r.db.QueryContext(ctx, `
+		SELECT rated_user_id, COUNT(*), AVG(rating)::float8
+		FROM purchase_ratings
+		WHERE rated_user_id = ANY($1)
+		GROUP BY rated_user_id
+	`, pq.Array(uniqueIDs))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query trade ratings")
+	}
+	defer ratingRows.Close()
+
+	for ratingRows.Next() {
+		var userID int64
+		var count int64
+		var average float64
+		if err := ratingRows.Scan(&userID, &count, &average); err != nil {
+			return nil, errors.Wrap(err, "failed to scan trade ratings")
+		}
+		stats[userID].RatingCount = count
+		stats[userID].AverageRating = &average
+	}
+
+	for _, s := range stats {
+		applyTrustScore(s)
+	}
+
+	return stats, nil
+}
+
+// applyTrustScore fills the cancellation rate and a 0-100 trust score. The score is the share of
+// finished trades that completed, blended 60/40 with the average rating once there is one.
+// Disputed trades are still open and do not count either way.
+func applyTrustScore(s *models.TradeTrustStats) {
+	finished := s.CompletedCount + s.CancelledCount + s.ExpiredCount
+	if finished == 0 {
+		return
+	}
+
+	reliability := float64(s.CompletedCount) / float64(finished)
+	s.CancellationRate = 1 - reliability
+
+	score := reliability
+	if s.AverageRating != nil {
+		score = 0.6*reliability + 0.4*(*s.AverageRating-1)/4
+	}
+
+	rounded := int(math.Round(score * 100))
+	s.TrustScore = &rounded
+}
+
+// UpsertRating saves the rater's rating of the other side of a purchase, replacing an earlier one.
+func (r *TradeTrust) UpsertRating(ctx context.Context, rating *models.PurchaseRating) error {
+	query := `
+		INSERT INTO purchase_ratings (purchase_id, rater_user_id, rated_user_id, rating, note)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (purchase_id, rater_user_id)
+		DO UPDATE SET
+			rating = EXCLUDED.rating,
+			note = EXCLUDED.note,
+			updated_at = NOW()
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		rating.PurchaseID,
+		rating.RaterUserID,
+		rating.RatedUserID,
+		rating.Rating,
+		rating.Note,
+	).Scan(&rating.CreatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to upsert purchase rating")
+	}
+
+	return nil
+}
+
+// GetRatingsForUser returns the ratings a user has received, newest first.
+func (r *TradeTrust) GetRatingsForUser(ctx context.Context, ratedUserID int64) ([]*models.PurchaseRating, error) {
+	query := `
+		SELECT
+			pr.purchase_id,
+			pr.rater_user_id,
+			COALESCE(u.name, ''),
+			pr.rated_user_id,
+			pr.rating,
+			pr.note,
+			t.type_name,
+			pr.created_at
+		FROM purchase_ratings pr
+		JOIN purchase_transactions pt ON pt.id = pr.purchase_id
+		JOIN asset_item_types t ON t.type_id = pt.type_id
+		LEFT JOIN users u ON u.id = pr.rater_user_id
+		WHERE pr.rated_user_id = $1
+		ORDER BY pr.created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, ratedUserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query purchase ratings")
+	}
+	defer rows.Close()
+
+	ratings := []*models.PurchaseRating{}
+	for rows.Next() {
+		var rating models.PurchaseRating
+		err = rows.Scan(
+			&rating.PurchaseID,
+			&rating.RaterUserID,
+			&rating.RaterName,
+			&rating.RatedUserID,
+			&rating.Rating,
+			&rating.Note,
+			&rating.TypeName,
+			&rating.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan purchase rating")
+		}
+		ratings = append(ratings, &rating)
+	}
+
+	return ratings, nil
+}
+
+// GetSettings returns a user's trust settings, with no minimum when none are saved.
+func (r *TradeTrust) GetSettings(ctx context.Context, userID int64) (*models.TradeTrustSettings, error) {
+	settings := &models.TradeTrustSettings{UserID: userID}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT min_auto_fulfill_score
+		FROM trade_trust_settings
+		WHERE user_id = $1
+	`, userID).Scan(&settings.MinAutoFulfillScore)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get trade trust settings")
+	}
+
+	return settings, nil
+}
+
+// UpsertSettings saves a user's trust settings.
+func (r *TradeTrust) UpsertSettings(ctx context.Context, settings *models.TradeTrustSettings) error {
+	query := `
+		INSERT INTO trade_trust_settings (user_id, min_auto_fulfill_score)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			min_auto_fulfill_score = EXCLUDED.min_auto_fulfill_score,
+			updated_at = NOW()
+	`
+
+	_, err := r.db.ExecContext(ctx, query, settings.UserID, settings.MinAutoFulfillScore)
+	if err != nil {
+		return errors.Wrap(err, "failed to upsert trade trust settings")
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TradeTrust_StatsRatingsAndSettings(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	sellerID := int64(3200)
+	buyerID := int64(3201)
+	strangerID := int64(3202)
+	typeID := int64(51)
+
+	item, err := setupPurchaseTestData(t, db, buyerID, sellerID, typeID, 30000161)
+	assert.NoError(t, err)
+	assert.NoError(t, repositories.NewUserRepository(db).Add(ctx, &repositories.User{ID: strangerID, Name: "Stranger"}))
+
+	purchaseRepo := repositories.NewPurchaseTransactions(db)
+	repo := repositories.NewTradeTrust(db)
+
+	create := func(status string, totalPrice float64) *models.PurchaseTransaction {
+		tx, err := db.BeginTx(ctx, nil)
+		assert.NoError(t, err)
+		purchase := &models.PurchaseTransaction{
+			ForSaleItemID:     item.ID,
+			BuyerUserID:       buyerID,
+			SellerUserID:      sellerID,
+			TypeID:            typeID,
+			QuantityPurchased: 1,
+			PricePerUnit:      totalPrice,
+			TotalPrice:        totalPrice,
+			Status:            status,
+		}
+		assert.NoError(t, purchaseRepo.Create(ctx, tx, purchase))
+		assert.NoError(t, tx.Commit())
+		return purchase
+	}
+
+	completedA := create("completed", 1000)
+	completedB := create("completed", 3000)
+	completedC := create("completed", 2000)
+	create("cancelled", 500)
+	expired := create("pending", 700)
+
+	// Contracts took 2, 4 and 10 hours; the median is 4
+	for i, purchase := range []*models.PurchaseTransaction{completedA, completedB, completedC} {
+		_, err = db.ExecContext(ctx, `
+			UPDATE purchase_transactions
+			SET purchased_at = NOW() - make_interval(hours => 24),
+				contract_created_at = NOW() - make_interval(hours => 24 - $2::int)
+			WHERE id = $1
+		`, purchase.ID, []int{2, 4, 10}[i])
+		assert.NoError(t, err)
+	}
+
+	// The seller missed the contract deadline, which only counts against them
+	_, err = db.ExecContext(ctx, `UPDATE purchase_transactions SET status = 'expired', expired_from_status = 'pending' WHERE id = $1`, expired.ID)
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.UpsertRating(ctx, &models.PurchaseRating{PurchaseID: completedA.ID, RaterUserID: buyerID, RatedUserID: sellerID, Rating: 2}))
+	note := "quick and friendly"
+	assert.NoError(t, repo.UpsertRating(ctx, &models.PurchaseRating{PurchaseID: completedB.ID, RaterUserID: buyerID, RatedUserID: sellerID, Rating: 5, Note: &note}))
+	// Rating the same purchase again replaces the first rating
+	assert.NoError(t, repo.UpsertRating(ctx, &models.PurchaseRating{PurchaseID: completedA.ID, RaterUserID: buyerID, RatedUserID: sellerID, Rating: 4}))
+
+	stats, err := repo.GetStats(ctx, []int64{sellerID, buyerID, strangerID, sellerID})
+	assert.NoError(t, err)
+	assert.Len(t, stats, 3)
+
+	seller := stats[sellerID]
+	assert.Equal(t, int64(3), seller.CompletedCount)
+	assert.Equal(t, int64(1), seller.CancelledCount)
+	assert.Equal(t, int64(1), seller.ExpiredCount)
+	assert.InDelta(t, 0.4, seller.CancellationRate, 0.0001)
+	assert.InDelta(t, 4.0, *seller.MedianContractHours, 0.01)
+	assert.Equal(t, float64(6000), seller.TotalVolume)
+	assert.Equal(t, int64(2), seller.RatingCount)
+	assert.InDelta(t, 4.5, *seller.AverageRating, 0.0001)
+	// 0.6 * 3/5 + 0.4 * (4.5-1)/4 = 0.71
+	assert.Equal(t, 71, *seller.TrustScore)
+
+	buyer := stats[buyerID]
+	assert.Equal(t, int64(0), buyer.ExpiredCount)
+	assert.Nil(t, buyer.MedianContractHours)
+	assert.Nil(t, buyer.AverageRating)
+	// 3 of 4 finished trades completed
+	assert.Equal(t, 75, *buyer.TrustScore)
+
+	assert.Nil(t, stats[strangerID].TrustScore)
+
+	ratings, err := repo.GetRatingsForUser(ctx, sellerID)
+	assert.NoError(t, err)
+	assert.Len(t, ratings, 2)
+	assert.Equal(t, "Test Buyer", ratings[0].RaterName)
+	assert.Equal(t, "Test Item", ratings[0].TypeName)
+
+	settings, err := repo.GetSettings(ctx, buyerID)
+	assert.NoError(t, err)
+	assert.Nil(t, settings.MinAutoFulfillScore)
+
+	minScore := 60
+	assert.NoError(t, repo.UpsertSettings(ctx, &models.TradeTrustSettings{UserID: buyerID, MinAutoFulfillScore: &minScore}))
+	settings, err = repo.GetSettings(ctx, buyerID)
+	assert.NoError(t, err)
+	assert.Equal(t, 60, *settings.MinAutoFulfillScore)
+}
@@ -27,6 +27,9 @@ func (r *TransportJobs) GetByUser(ctx context.Context, userID int64) ([]*models.
 		       j.fulfillment_type, j.transport_profile_id,
 		       j.plan_run_id, j.plan_step_id, j.queue_entry_id,
 		       j.notes, j.created_at, j.updated_at,
+		       j.eve_contract_id, j.contract_status, j.contract_updated_at,
+		       j.consolidated_into_id, j.owner_type, j.owner_id,
+		       case when j.fulfillment_type = 'courier_contract' then 'TJ-' || j.id else '' end,
 		       COALESCE(os.name, ''), COALESCE(ds.name, ''),
 		       COALESCE(oss.name, ''), COALESCE(dss.name, ''),
 		       COALESCE(tp.name, ''), COALESCE(jr.name, '')
@@ -59,6 +62,9 @@ func (r *TransportJobs) GetByUser(ctx context.Context, userID int64) ([]*models.
 			&j.FulfillmentType, &j.TransportProfileID,
 			&j.PlanRunID, &j.PlanStepID, &j.QueueEntryID,
 			&j.Notes, &j.CreatedAt, &j.UpdatedAt,
+			&j.EveContractID, &j.ContractStatus, &j.ContractUpdatedAt,
+			&j.ConsolidatedIntoID, &j.OwnerType, &j.OwnerID,
+			&j.ContractKey,
 			&j.OriginStationName, &j.DestinationStationName,
 			&j.OriginSystemName, &j.DestinationSystemName,
 			&j.ProfileName, &j.JFRouteName,
@@ -124,6 +130,9 @@ func (r *TransportJobs) GetByID(ctx context.Context, id, userID int64) (*models.
 		       j.fulfillment_type, j.transport_profile_id,
 		       j.plan_run_id, j.plan_step_id, j.queue_entry_id,
 		       j.notes, j.created_at, j.updated_at,
+		       j.eve_contract_id, j.contract_status, j.contract_updated_at,
+		       j.consolidated_into_id, j.owner_type, j.owner_id,
+		       case when j.fulfillment_type = 'courier_contract' then 'TJ-' || j.id else '' end,
 		       COALESCE(os.name, ''), COALESCE(ds.name, ''),
 		       COALESCE(oss.name, ''), COALESCE(dss.name, ''),
 		       COALESCE(tp.name, ''), COALESCE(jr.name, '')
@@ -147,6 +156,9 @@ func (r *TransportJobs) GetByID(ctx context.Context, id, userID int64) (*models.
 		&j.FulfillmentType, &j.TransportProfileID,
 		&j.PlanRunID, &j.PlanStepID, &j.QueueEntryID,
 		&j.Notes, &j.CreatedAt, &j.UpdatedAt,
+		&j.EveContractID, &j.ContractStatus, &j.ContractUpdatedAt,
+		&j.ConsolidatedIntoID, &j.OwnerType, &j.OwnerID,
+		&j.ContractKey,
 		&j.OriginStationName, &j.DestinationStationName,
 		&j.OriginSystemName, &j.DestinationSystemName,
 		&j.ProfileName, &j.JFRouteName,
@@ -182,8 +194,8 @@ func (r *TransportJobs) Create(ctx context.Context, job *models.TransportJob) (*
 			 total_volume_m3, total_collateral, estimated_cost,
 			 jumps, distance_ly, jf_route_id,
 			 fulfillment_type, transport_profile_id,
-			 plan_run_id, plan_step_id, notes)
-		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+			 plan_run_id, plan_step_id, notes, owner_type, owner_id)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
 		returning id, user_id, origin_station_id, destination_station_id,
 		          origin_system_id, destination_system_id,
 		          transport_method, route_preference, status,
@@ -191,7 +203,8 @@ func (r *TransportJobs) Create(ctx context.Context, job *models.TransportJob) (*
 		          jumps, distance_ly, jf_route_id,
 		          fulfillment_type, transport_profile_id,
 		          plan_run_id, plan_step_id, queue_entry_id,
-		          notes, created_at, updated_at
+		          notes, created_at, updated_at, owner_type, owner_id,
+		          case when fulfillment_type = 'courier_contract' then 'TJ-' || id else '' end
 	`
 
 	var created models.TransportJob
@@ -202,7 +215,7 @@ func (r *TransportJobs) Create(ctx context.Context, job *models.TransportJob) (*
 		job.TotalVolumeM3, job.TotalCollateral, job.EstimatedCost,
 		job.Jumps, job.DistanceLY, job.JFRouteID,
 		job.FulfillmentType, job.TransportProfileID,
-		job.PlanRunID, job.PlanStepID, job.Notes,
+		job.PlanRunID, job.PlanStepID, job.Notes, job.OwnerType, job.OwnerID,
 	).Scan(
 		&created.ID, &created.UserID, &created.OriginStationID, &created.DestinationStationID,
 		&created.OriginSystemID, &created.DestinationSystemID,
@@ -211,7 +224,8 @@ func (r *TransportJobs) Create(ctx context.Context, job *models.TransportJob) (*
 		&created.Jumps, &created.DistanceLY, &created.JFRouteID,
 		&created.FulfillmentType, &created.TransportProfileID,
 		&created.PlanRunID, &created.PlanStepID, &created.QueueEntryID,
-		&created.Notes, &created.CreatedAt, &created.UpdatedAt,
+		&created.Notes, &created.CreatedAt, &created.UpdatedAt, &created.OwnerType, &created.OwnerID,
+		&created.ContractKey,
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create transport job")
@@ -264,6 +278,166 @@ func (r *TransportJobs) UpdateStatus(ctx context.Context, id, userID int64, stat
 		return errors.New("transport job not found")
 	}
 
+	// Jobs consolidated into this one travel with it
+	_, err = r.db.ExecContext(ctx, `
+		update transport_jobs
+		set status = $3, updated_at = now()
+		where consolidated_into_id = $1 and user_id = $2
+	`, id, userID, status)
+	if err != nil {
+		return errors.Wrap(err, "failed to update consolidated transport job status")
+	}
+
+	return nil
+}
+
+// GetOpenCourierJobs returns planned and in-transit courier_contract jobs of
+// every user, without items, for the courier contract sync.
+func (r *TransportJobs) GetOpenCourierJobs(ctx context.Context) ([]*models.TransportJob, error) {
+	query := `
+		select j.id, j.user_id, j.origin_station_id, j.destination_station_id,
+		       j.origin_system_id, j.destination_system_id,
+		       j.transport_method, j.status,
+		       j.total_volume_m3, j.total_collateral,
+		       j.fulfillment_type,
+		       j.eve_contract_id, j.contract_status, j.contract_updated_at,
+		       'TJ-' || j.id, j.created_at,
+		       COALESCE(oss.name, ''), COALESCE(dss.name, '')
+		from transport_jobs j
+		left join solar_systems oss on oss.solar_system_id = j.origin_system_id
+		left join solar_systems dss on dss.solar_system_id = j.destination_system_id
+		where j.fulfillment_type = 'courier_contract'
+		  and j.status in ('planned', 'in_transit')
+		order by j.user_id, j.created_at asc
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query open courier jobs")
+	}
+	defer rows.Close()
+
+	jobs := []*models.TransportJob{}
+	for rows.Next() {
+		var j models.TransportJob
+		if err := rows.Scan(
+			&j.ID, &j.UserID, &j.OriginStationID, &j.DestinationStationID,
+			&j.OriginSystemID, &j.DestinationSystemID,
+			&j.TransportMethod, &j.Status,
+			&j.TotalVolumeM3, &j.TotalCollateral,
+			&j.FulfillmentType,
+			&j.EveContractID, &j.ContractStatus, &j.ContractUpdatedAt,
+			&j.ContractKey, &j.CreatedAt,
+			&j.OriginSystemName, &j.DestinationSystemName,
+		); err != nil {
+			return nil, errors.Wrap(err, "failed to scan open courier job")
+		}
+		j.Items = []*models.TransportJobItem{}
+		jobs = append(jobs, &j)
+	}
+
+	return jobs, nil
+}
+
+// UpdateContract links a courier contract to a job and records its status,
+// moving the job to status in the same update.
+func (r *TransportJobs) UpdateContract(ctx context.Context, id, eveContractID int64, contractStatus, status string) error {
+	result, err := r.db.ExecContext(ctx, `
+		update transport_jobs
+		set eve_contract_id = $2, contract_status = $3, contract_updated_at = now(),
+		    status = $4, updated_at = now()
+		where id = $1
+	`, id, eveContractID, contractStatus, status)
+	if err != nil {
+		return errors.Wrap(err, "failed to update transport job contract")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rows == 0 {
+		return errors.New("transport job not found")
+	}
+
+	return nil
+}
+
+// Consolidate merges planned jobs into the planned host job's trip. The host
+// takes the combined cargo and the consolidated cost; the merged jobs become
+// 'consolidated' and their queue entries are cancelled.
+func (r *TransportJobs) Consolidate(ctx context.Context, userID, hostID int64, jobIDs []int64, totalVolume, totalCollateral, estimatedCost float64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction for transport job consolidation")
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		update transport_jobs
+		set total_volume_m3 = $3, total_collateral = $4, estimated_cost = $5, updated_at = now()
+		where id = $1 and user_id = $2 and status = 'planned'
+	`, hostID, userID, totalVolume, totalCollateral, estimatedCost)
+	if err != nil {
+		return errors.Wrap(err, "failed to update consolidated host job")
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rows == 0 {
+		return errors.New("host transport job not found or not planned")
+	}
+
+	result, err = tx.ExecContext(ctx, `
+		update transport_jobs
+		set status = 'consolidated', consolidated_into_id = $2, updated_at = now()
+		where id = any($1) and user_id = $3 and status = 'planned'
+	`, pq.Array(jobIDs), hostID, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to consolidate transport jobs")
+	}
+	rows, err = result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rows != int64(len(jobIDs)) {
+		return errors.New("transport jobs not found or not planned")
+	}
+
+	// Jobs previously merged into a job that is now being merged move to the new host,
+	// so they keep following a live trip
+	_, err = tx.ExecContext(ctx, `
+		update transport_jobs
+		set consolidated_into_id = $1, updated_at = now()
+		where consolidated_into_id = any($2) and user_id = $3
+	`, hostID, pq.Array(jobIDs), userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to re-parent previously consolidated transport jobs")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		update industry_job_queue
+		set estimated_cost = $2, updated_at = now()
+		where transport_job_id = $1 and status = 'planned'
+	`, hostID, estimatedCost)
+	if err != nil {
+		return errors.Wrap(err, "failed to update host queue entry")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		update industry_job_queue
+		set status = 'cancelled', updated_at = now()
+		where transport_job_id = any($1) and status in ('planned', 'active')
+	`, pq.Array(jobIDs))
+	if err != nil {
+		return errors.Wrap(err, "failed to cancel consolidated queue entries")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit transport job consolidation")
+	}
+
 	return nil
 }
 
@@ -278,7 +452,13 @@ func (r *TransportJobs) SetQueueEntryID(ctx context.Context, id int64, queueEntr
 }
 
 func (r *TransportJobs) Cancel(ctx context.Context, id, userID int64) error {
-	result, err := r.db.ExecContext(ctx, `
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction for transport job cancel")
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
 		update transport_jobs
 		set status = 'cancelled', updated_at = now()
 		where id = $1 and user_id = $2 and status in ('planned', 'in_transit')
@@ -295,6 +475,20 @@ func (r *TransportJobs) Cancel(ctx context.Context, id, userID int64) error {
 		return errors.New("transport job not found or not cancellable")
 	}
 
+	// Jobs consolidated into this one were riding on the cancelled trip
+	_, err = tx.ExecContext(ctx, `
+		update transport_jobs
+		set status = 'cancelled', updated_at = now()
+		where consolidated_into_id = $1 and user_id = $2 and status = 'consolidated'
+	`, id, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to cancel consolidated transport jobs")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit transport job cancel")
+	}
+
 	return nil
 }
 
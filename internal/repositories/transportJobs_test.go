@@ -2,6 +2,7 @@ package repositories_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/annymsMthd/industry-tool/internal/models"
@@ -326,3 +327,290 @@ func Test_TransportJobsShouldReturnEmptySlice(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, jobs, 0)
 }
+
+func Test_TransportJobsShouldTrackCourierContracts(t *testing.T) {
+	db, err := setupDatabase(t)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `INSERT INTO regions (region_id, name) VALUES (10000002, 'The Forge') ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO constellations (constellation_id, name, region_id) VALUES (20000020, 'Kimotoro', 10000002) ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO solar_systems (solar_system_id, name, constellation_id, security) VALUES (30000142, 'Jita', 20000020, 0.9) ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO stations (station_id, name, solar_system_id, corporation_id, is_npc_station) VALUES (60003760, 'Jita IV', 30000142, 1000125, true) ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+
+	userRepo := repositories.NewUserRepository(db)
+	jobsRepo := repositories.NewTransportJobs(db)
+
+	user := &repositories.User{ID: 8205, Name: "Courier Contract User"}
+	err = userRepo.Add(ctx, user)
+	require.NoError(t, err)
+
+	newJob := func(fulfillment string) *models.TransportJob {
+		return &models.TransportJob{
+			UserID:               user.ID,
+			OriginStationID:      60003760,
+			DestinationStationID: 60003760,
+			OriginSystemID:       30000142,
+			DestinationSystemID:  30000142,
+			TransportMethod:      "freighter",
+			RoutePreference:      "shortest",
+			FulfillmentType:      fulfillment,
+			Items:                []*models.TransportJobItem{},
+		}
+	}
+
+	courier, err := jobsRepo.Create(ctx, newJob("courier_contract"))
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("TJ-%d", courier.ID), courier.ContractKey)
+
+	selfHaul, err := jobsRepo.Create(ctx, newJob("self_haul"))
+	require.NoError(t, err)
+	assert.Equal(t, "", selfHaul.ContractKey)
+
+	open, err := jobsRepo.GetOpenCourierJobs(ctx)
+	require.NoError(t, err)
+	var found *models.TransportJob
+	for _, j := range open {
+		assert.Equal(t, "courier_contract", j.FulfillmentType)
+		if j.ID == courier.ID {
+			found = j
+		}
+	}
+	require.NotNil(t, found)
+	assert.Nil(t, found.EveContractID)
+	assert.Equal(t, "Jita", found.OriginSystemName)
+
+	err = jobsRepo.UpdateContract(ctx, courier.ID, 123456789, "in_progress", "in_transit")
+	require.NoError(t, err)
+
+	fetched, err := jobsRepo.GetByID(ctx, courier.ID, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "in_transit", fetched.Status)
+	require.NotNil(t, fetched.EveContractID)
+	assert.Equal(t, int64(123456789), *fetched.EveContractID)
+	require.NotNil(t, fetched.ContractStatus)
+	assert.Equal(t, "in_progress", *fetched.ContractStatus)
+	assert.NotNil(t, fetched.ContractUpdatedAt)
+
+	err = jobsRepo.UpdateContract(ctx, courier.ID, 123456789, "finished", "delivered")
+	require.NoError(t, err)
+
+	open, err = jobsRepo.GetOpenCourierJobs(ctx)
+	require.NoError(t, err)
+	for _, j := range open {
+		assert.NotEqual(t, courier.ID, j.ID)
+	}
+
+	err = jobsRepo.UpdateContract(ctx, 99999999, 1, "finished", "delivered")
+	assert.Error(t, err)
+}
+
+func Test_TransportJobsShouldConsolidate(t *testing.T) {
+	db, err := setupDatabase(t)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `INSERT INTO regions (region_id, name) VALUES (10000002, 'The Forge') ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO constellations (constellation_id, name, region_id) VALUES (20000020, 'Kimotoro', 10000002) ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO solar_systems (solar_system_id, name, constellation_id, security) VALUES (30000142, 'Jita', 20000020, 0.9) ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO stations (station_id, name, solar_system_id, corporation_id, is_npc_station) VALUES (60003760, 'Jita IV', 30000142, 1000125, true) ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+
+	userRepo := repositories.NewUserRepository(db)
+	jobsRepo := repositories.NewTransportJobs(db)
+	queueRepo := repositories.NewJobQueue(db)
+
+	user := &repositories.User{ID: 8206, Name: "Consolidation User"}
+	err = userRepo.Add(ctx, user)
+	require.NoError(t, err)
+
+	jobs := []*models.TransportJob{}
+	for _, volume := range []float64{300, 200} {
+		created, err := jobsRepo.Create(ctx, &models.TransportJob{
+			UserID:               user.ID,
+			OriginStationID:      60003760,
+			DestinationStationID: 60003760,
+			OriginSystemID:       30000142,
+			DestinationSystemID:  30000142,
+			TransportMethod:      "jump_freighter",
+			RoutePreference:      "shortest",
+			FulfillmentType:      "self_haul",
+			TotalVolumeM3:        volume,
+			TotalCollateral:      1000000,
+			EstimatedCost:        500000,
+			Items:                []*models.TransportJobItem{},
+		})
+		require.NoError(t, err)
+
+		entry, err := queueRepo.Create(ctx, &models.IndustryJobQueueEntry{
+			UserID:         user.ID,
+			Activity:       "transport",
+			EstimatedCost:  &created.EstimatedCost,
+			TransportJobID: &created.ID,
+		})
+		require.NoError(t, err)
+		require.NoError(t, jobsRepo.SetQueueEntryID(ctx, created.ID, entry.ID))
+		jobs = append(jobs, created)
+	}
+	host, merged := jobs[0], jobs[1]
+
+	err = jobsRepo.Consolidate(ctx, user.ID, host.ID, []int64{merged.ID}, 500, 2000000, 600000)
+	require.NoError(t, err)
+
+	fetched, err := jobsRepo.GetByID(ctx, host.ID, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "planned", fetched.Status)
+	assert.Equal(t, 500.0, fetched.TotalVolumeM3)
+	assert.Equal(t, 600000.0, fetched.EstimatedCost)
+	assert.Nil(t, fetched.ConsolidatedIntoID)
+
+	fetched, err = jobsRepo.GetByID(ctx, merged.ID, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "consolidated", fetched.Status)
+	require.NotNil(t, fetched.ConsolidatedIntoID)
+	assert.Equal(t, host.ID, *fetched.ConsolidatedIntoID)
+
+	// The merged job's queue entry is cancelled and drops out of the queue
+	entries, err := queueRepo.GetByUser(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, host.ID, *entries[0].TransportJobID)
+	assert.Equal(t, 600000.0, *entries[0].EstimatedCost)
+
+	// A job can only be consolidated once
+	err = jobsRepo.Consolidate(ctx, user.ID, host.ID, []int64{merged.ID}, 500, 2000000, 600000)
+	assert.Error(t, err)
+
+	// Consolidated jobs follow the host's status
+	err = jobsRepo.UpdateStatus(ctx, host.ID, user.ID, "delivered")
+	require.NoError(t, err)
+
+	fetched, err = jobsRepo.GetByID(ctx, merged.ID, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "delivered", fetched.Status)
+}
+
+func Test_TransportJobsShouldReparentWhenMergingAPriorHost(t *testing.T) {
+	db, err := setupDatabase(t)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `INSERT INTO regions (region_id, name) VALUES (10000002, 'The Forge') ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO constellations (constellation_id, name, region_id) VALUES (20000020, 'Kimotoro', 10000002) ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO solar_systems (solar_system_id, name, constellation_id, security) VALUES (30000142, 'Jita', 20000020, 0.9) ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO stations (station_id, name, solar_system_id, corporation_id, is_npc_station) VALUES (60003760, 'Jita IV', 30000142, 1000125, true) ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+
+	userRepo := repositories.NewUserRepository(db)
+	jobsRepo := repositories.NewTransportJobs(db)
+
+	user := &repositories.User{ID: 8208, Name: "Reparent Consolidated User"}
+	err = userRepo.Add(ctx, user)
+	require.NoError(t, err)
+
+	jobs := []*models.TransportJob{}
+	for i := 0; i < 3; i++ {
+		created, err := jobsRepo.Create(ctx, &models.TransportJob{
+			UserID:               user.ID,
+			OriginStationID:      60003760,
+			DestinationStationID: 60003760,
+			OriginSystemID:       30000142,
+			DestinationSystemID:  30000142,
+			TransportMethod:      "jump_freighter",
+			RoutePreference:      "shortest",
+			FulfillmentType:      "self_haul",
+			Items:                []*models.TransportJobItem{},
+		})
+		require.NoError(t, err)
+		jobs = append(jobs, created)
+	}
+	newHost, priorHost, child := jobs[0], jobs[1], jobs[2]
+
+	err = jobsRepo.Consolidate(ctx, user.ID, priorHost.ID, []int64{child.ID}, 0, 0, 0)
+	require.NoError(t, err)
+
+	// Merging the prior host moves its child to the new host
+	err = jobsRepo.Consolidate(ctx, user.ID, newHost.ID, []int64{priorHost.ID}, 0, 0, 0)
+	require.NoError(t, err)
+
+	for _, id := range []int64{priorHost.ID, child.ID} {
+		fetched, err := jobsRepo.GetByID(ctx, id, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "consolidated", fetched.Status)
+		require.NotNil(t, fetched.ConsolidatedIntoID)
+		assert.Equal(t, newHost.ID, *fetched.ConsolidatedIntoID)
+	}
+
+	// The child follows the new host's status
+	err = jobsRepo.UpdateStatus(ctx, newHost.ID, user.ID, "delivered")
+	require.NoError(t, err)
+
+	fetched, err := jobsRepo.GetByID(ctx, child.ID, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "delivered", fetched.Status)
+}
+
+func Test_TransportJobsShouldCancelConsolidatedJobs(t *testing.T) {
+	db, err := setupDatabase(t)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `INSERT INTO regions (region_id, name) VALUES (10000002, 'The Forge') ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO constellations (constellation_id, name, region_id) VALUES (20000020, 'Kimotoro', 10000002) ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO solar_systems (solar_system_id, name, constellation_id, security) VALUES (30000142, 'Jita', 20000020, 0.9) ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO stations (station_id, name, solar_system_id, corporation_id, is_npc_station) VALUES (60003760, 'Jita IV', 30000142, 1000125, true) ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+
+	userRepo := repositories.NewUserRepository(db)
+	jobsRepo := repositories.NewTransportJobs(db)
+
+	user := &repositories.User{ID: 8207, Name: "Cancel Consolidated User"}
+	err = userRepo.Add(ctx, user)
+	require.NoError(t, err)
+
+	jobs := []*models.TransportJob{}
+	for i := 0; i < 2; i++ {
+		created, err := jobsRepo.Create(ctx, &models.TransportJob{
+			UserID:               user.ID,
+			OriginStationID:      60003760,
+			DestinationStationID: 60003760,
+			OriginSystemID:       30000142,
+			DestinationSystemID:  30000142,
+			TransportMethod:      "jump_freighter",
+			RoutePreference:      "shortest",
+			FulfillmentType:      "self_haul",
+			Items:                []*models.TransportJobItem{},
+		})
+		require.NoError(t, err)
+		jobs = append(jobs, created)
+	}
+	host, merged := jobs[0], jobs[1]
+
+	err = jobsRepo.Consolidate(ctx, user.ID, host.ID, []int64{merged.ID}, 0, 0, 0)
+	require.NoError(t, err)
+
+	err = jobsRepo.Cancel(ctx, host.ID, user.ID)
+	require.NoError(t, err)
+
+	// The merged job no longer has a trip, so it is cancelled with the host
+	fetched, err := jobsRepo.GetByID(ctx, merged.ID, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", fetched.Status)
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 
 	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 )
 
@@ -22,8 +23,13 @@ func (r *TransportProfiles) GetByUser(ctx context.Context, userID int64) ([]*mod
 		       p.cargo_m3, p.rate_per_m3_per_jump, p.collateral_rate, p.collateral_price_basis,
 		       p.fuel_type_id, p.fuel_per_ly, p.fuel_conservation_level,
 		       p.route_preference, p.is_default, p.created_at,
+		       p.jump_ship_class, p.jump_drive_calibration_level, p.preferred_cyno_system_ids,
 		       COALESCE(c.name, ''),
-		       COALESCE(t.type_name, '')
+		       COALESCE(t.type_name, ''),
+		       array(select COALESCE(ss.name, u.id::text)
+		             from unnest(p.preferred_cyno_system_ids) with ordinality u(id, ord)
+		             left join solar_systems ss on ss.solar_system_id = u.id
+		             order by u.ord)
 		from transport_profiles p
 		left join characters c on c.id = p.character_id
 		left join asset_item_types t on t.type_id = p.fuel_type_id
@@ -45,7 +51,8 @@ func (r *TransportProfiles) GetByUser(ctx context.Context, userID int64) ([]*mod
 			&p.CargoM3, &p.RatePerM3PerJump, &p.CollateralRate, &p.CollateralPriceBasis,
 			&p.FuelTypeID, &p.FuelPerLY, &p.FuelConservationLevel,
 			&p.RoutePreference, &p.IsDefault, &p.CreatedAt,
-			&p.CharacterName, &p.FuelTypeName,
+			&p.JumpShipClass, &p.JumpDriveCalibrationLevel, pq.Array(&p.PreferredCynoSystemIDs),
+			&p.CharacterName, &p.FuelTypeName, pq.Array(&p.PreferredCynoSystemNames),
 		); err != nil {
 			return nil, errors.Wrap(err, "failed to scan transport profile")
 		}
@@ -61,8 +68,13 @@ func (r *TransportProfiles) GetByID(ctx context.Context, id, userID int64) (*mod
 		       p.cargo_m3, p.rate_per_m3_per_jump, p.collateral_rate, p.collateral_price_basis,
 		       p.fuel_type_id, p.fuel_per_ly, p.fuel_conservation_level,
 		       p.route_preference, p.is_default, p.created_at,
+		       p.jump_ship_class, p.jump_drive_calibration_level, p.preferred_cyno_system_ids,
 		       COALESCE(c.name, ''),
-		       COALESCE(t.type_name, '')
+		       COALESCE(t.type_name, ''),
+		       array(select COALESCE(ss.name, u.id::text)
+		             from unnest(p.preferred_cyno_system_ids) with ordinality u(id, ord)
+		             left join solar_systems ss on ss.solar_system_id = u.id
+		             order by u.ord)
 		from transport_profiles p
 		left join characters c on c.id = p.character_id
 		left join asset_item_types t on t.type_id = p.fuel_type_id
@@ -75,7 +87,8 @@ func (r *TransportProfiles) GetByID(ctx context.Context, id, userID int64) (*mod
 		&p.CargoM3, &p.RatePerM3PerJump, &p.CollateralRate, &p.CollateralPriceBasis,
 		&p.FuelTypeID, &p.FuelPerLY, &p.FuelConservationLevel,
 		&p.RoutePreference, &p.IsDefault, &p.CreatedAt,
-		&p.CharacterName, &p.FuelTypeName,
+		&p.JumpShipClass, &p.JumpDriveCalibrationLevel, pq.Array(&p.PreferredCynoSystemIDs),
+		&p.CharacterName, &p.FuelTypeName, pq.Array(&p.PreferredCynoSystemNames),
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -93,8 +106,13 @@ func (r *TransportProfiles) GetDefaultByMethod(ctx context.Context, userID int64
 		       p.cargo_m3, p.rate_per_m3_per_jump, p.collateral_rate, p.collateral_price_basis,
 		       p.fuel_type_id, p.fuel_per_ly, p.fuel_conservation_level,
 		       p.route_preference, p.is_default, p.created_at,
+		       p.jump_ship_class, p.jump_drive_calibration_level, p.preferred_cyno_system_ids,
 		       COALESCE(c.name, ''),
-		       COALESCE(t.type_name, '')
+		       COALESCE(t.type_name, ''),
+		       array(select COALESCE(ss.name, u.id::text)
+		             from unnest(p.preferred_cyno_system_ids) with ordinality u(id, ord)
+		             left join solar_systems ss on ss.solar_system_id = u.id
+		             order by u.ord)
 		from transport_profiles p
 		left join characters c on c.id = p.character_id
 		left join asset_item_types t on t.type_id = p.fuel_type_id
@@ -108,7 +126,8 @@ func (r *TransportProfiles) GetDefaultByMethod(ctx context.Context, userID int64
 		&p.CargoM3, &p.RatePerM3PerJump, &p.CollateralRate, &p.CollateralPriceBasis,
 		&p.FuelTypeID, &p.FuelPerLY, &p.FuelConservationLevel,
 		&p.RoutePreference, &p.IsDefault, &p.CreatedAt,
-		&p.CharacterName, &p.FuelTypeName,
+		&p.JumpShipClass, &p.JumpDriveCalibrationLevel, pq.Array(&p.PreferredCynoSystemIDs),
+		&p.CharacterName, &p.FuelTypeName, pq.Array(&p.PreferredCynoSystemNames),
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -143,12 +162,14 @@ func (r *TransportProfiles) Create(ctx context.Context, p *models.TransportProfi
 			(user_id, name, transport_method, character_id, cargo_m3,
 			 rate_per_m3_per_jump, collateral_rate, collateral_price_basis,
 			 fuel_type_id, fuel_per_ly, fuel_conservation_level,
-			 route_preference, is_default)
-		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			 route_preference, is_default,
+			 jump_ship_class, jump_drive_calibration_level, preferred_cyno_system_ids)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		returning id, user_id, name, transport_method, character_id,
 		          cargo_m3, rate_per_m3_per_jump, collateral_rate, collateral_price_basis,
 		          fuel_type_id, fuel_per_ly, fuel_conservation_level,
-		          route_preference, is_default, created_at
+		          route_preference, is_default, created_at,
+		          jump_ship_class, jump_drive_calibration_level, preferred_cyno_system_ids
 	`
 
 	var created models.TransportProfile
@@ -157,11 +178,13 @@ func (r *TransportProfiles) Create(ctx context.Context, p *models.TransportProfi
 		p.RatePerM3PerJump, p.CollateralRate, p.CollateralPriceBasis,
 		p.FuelTypeID, p.FuelPerLY, p.FuelConservationLevel,
 		p.RoutePreference, p.IsDefault,
+		p.JumpShipClass, p.JumpDriveCalibrationLevel, pq.Array(preferredCynoSystemIDs(p)),
 	).Scan(
 		&created.ID, &created.UserID, &created.Name, &created.TransportMethod, &created.CharacterID,
 		&created.CargoM3, &created.RatePerM3PerJump, &created.CollateralRate, &created.CollateralPriceBasis,
 		&created.FuelTypeID, &created.FuelPerLY, &created.FuelConservationLevel,
 		&created.RoutePreference, &created.IsDefault, &created.CreatedAt,
+		&created.JumpShipClass, &created.JumpDriveCalibrationLevel, pq.Array(&created.PreferredCynoSystemIDs),
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create transport profile")
@@ -197,12 +220,14 @@ func (r *TransportProfiles) Update(ctx context.Context, p *models.TransportProfi
 		set name = $3, transport_method = $4, character_id = $5, cargo_m3 = $6,
 		    rate_per_m3_per_jump = $7, collateral_rate = $8, collateral_price_basis = $9,
 		    fuel_type_id = $10, fuel_per_ly = $11, fuel_conservation_level = $12,
-		    route_preference = $13, is_default = $14
+		    route_preference = $13, is_default = $14,
+		    jump_ship_class = $15, jump_drive_calibration_level = $16, preferred_cyno_system_ids = $17
 		where id = $1 and user_id = $2
 		returning id, user_id, name, transport_method, character_id,
 		          cargo_m3, rate_per_m3_per_jump, collateral_rate, collateral_price_basis,
 		          fuel_type_id, fuel_per_ly, fuel_conservation_level,
-		          route_preference, is_default, created_at
+		          route_preference, is_default, created_at,
+		          jump_ship_class, jump_drive_calibration_level, preferred_cyno_system_ids
 	`
 
 	var updated models.TransportProfile
@@ -211,11 +236,13 @@ func (r *TransportProfiles) Update(ctx context.Context, p *models.TransportProfi
 		p.RatePerM3PerJump, p.CollateralRate, p.CollateralPriceBasis,
 		p.FuelTypeID, p.FuelPerLY, p.FuelConservationLevel,
 		p.RoutePreference, p.IsDefault,
+		p.JumpShipClass, p.JumpDriveCalibrationLevel, pq.Array(preferredCynoSystemIDs(p)),
 	).Scan(
 		&updated.ID, &updated.UserID, &updated.Name, &updated.TransportMethod, &updated.CharacterID,
 		&updated.CargoM3, &updated.RatePerM3PerJump, &updated.CollateralRate, &updated.CollateralPriceBasis,
 		&updated.FuelTypeID, &updated.FuelPerLY, &updated.FuelConservationLevel,
 		&updated.RoutePreference, &updated.IsDefault, &updated.CreatedAt,
+		&updated.JumpShipClass, &updated.JumpDriveCalibrationLevel, pq.Array(&updated.PreferredCynoSystemIDs),
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -231,6 +258,15 @@ func (r *TransportProfiles) Update(ctx context.Context, p *models.TransportProfi
 	return &updated, nil
 }
 
+// preferredCynoSystemIDs returns the profile's cyno systems, never nil, so the
+// not-null array column gets an empty array.
+func preferredCynoSystemIDs(p *models.TransportProfile) []int64 {
+	if p.PreferredCynoSystemIDs == nil {
+		return []int64{}
+	}
+	return p.PreferredCynoSystemIDs
+}
+
 func (r *TransportProfiles) Delete(ctx context.Context, id, userID int64) error {
 	result, err := r.db.ExecContext(ctx, `
 		delete from transport_profiles where id = $1 and user_id = $2
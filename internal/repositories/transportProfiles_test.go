@@ -66,17 +66,20 @@ func Test_TransportProfilesShouldGetByID(t *testing.T) {
 	fuelTypeID := int64(16274) // Nitrogen Isotopes
 
 	profile := &models.TransportProfile{
-		UserID:                user.ID,
-		Name:                  "Rhea Alt - JFC 5",
-		TransportMethod:       "jump_freighter",
-		CargoM3:               34246,
-		CollateralRate:        0.03,
-		CollateralPriceBasis:  "split",
-		FuelTypeID:            &fuelTypeID,
-		FuelPerLY:             &fuelPerLY,
-		FuelConservationLevel: 5,
-		RoutePreference:       "shortest",
-		IsDefault:             false,
+		UserID:                    user.ID,
+		Name:                      "Rhea Alt - JFC 5",
+		TransportMethod:           "jump_freighter",
+		CargoM3:                   34246,
+		CollateralRate:            0.03,
+		CollateralPriceBasis:      "split",
+		FuelTypeID:                &fuelTypeID,
+		FuelPerLY:                 &fuelPerLY,
+		FuelConservationLevel:     5,
+		RoutePreference:           "shortest",
+		IsDefault:                 false,
+		JumpShipClass:             "jump_freighter",
+		JumpDriveCalibrationLevel: 4,
+		PreferredCynoSystemIDs:    []int64{30002813, 30003504},
 	}
 
 	created, err := profilesRepo.Create(context.Background(), profile)
@@ -91,6 +94,11 @@ func Test_TransportProfilesShouldGetByID(t *testing.T) {
 	assert.Equal(t, &fuelTypeID, fetched.FuelTypeID)
 	assert.Equal(t, &fuelPerLY, fetched.FuelPerLY)
 	assert.Equal(t, 5, fetched.FuelConservationLevel)
+	assert.Equal(t, "jump_freighter", fetched.JumpShipClass)
+	assert.Equal(t, 4, fetched.JumpDriveCalibrationLevel)
+	assert.Equal(t, []int64{30002813, 30003504}, fetched.PreferredCynoSystemIDs)
+	// Systems missing from the SDE fall back to their ID
+	assert.Equal(t, []string{"30002813", "30003504"}, fetched.PreferredCynoSystemNames)
 
 	// Should not find with wrong user ID
 	notFound, err := profilesRepo.GetByID(context.Background(), created.ID, 99999)
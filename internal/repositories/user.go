@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 )
 
@@ -90,6 +92,38 @@ func (r *UserRepository) GetAssetsLastUpdated(ctx context.Context, userID int64)
 	return lastUpdated, nil
 }
 
+// GetAssetLocationFlags returns the user's asset sync allow list, falling back to
+// models.DefaultAssetLocationFlags when the user has not configured one.
+func (r *UserRepository) GetAssetLocationFlags(ctx context.Context, userID int64) (*models.AssetLocationFlagSettings, error) {
+	var flags []string
+	err := r.db.QueryRowContext(ctx, "select asset_location_flags from users where id = $1", userID).Scan(pq.Array(&flags))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get asset_location_flags")
+	}
+
+	if flags == nil {
+		defaults := make([]string, len(models.DefaultAssetLocationFlags))
+		copy(defaults, models.DefaultAssetLocationFlags)
+		return &models.AssetLocationFlagSettings{Flags: defaults, IsDefault: true}, nil
+	}
+
+	return &models.AssetLocationFlagSettings{Flags: flags}, nil
+}
+
+// SetAssetLocationFlags stores the user's asset sync allow list. A nil slice resets it to the defaults.
+func (r *UserRepository) SetAssetLocationFlags(ctx context.Context, userID int64, flags []string) error {
+	var value interface{}
+	if flags != nil {
+		value = pq.Array(flags)
+	}
+
+	_, err := r.db.ExecContext(ctx, "update users set asset_location_flags = $2 where id = $1", userID, value)
+	if err != nil {
+		return errors.Wrap(err, "failed to update asset_location_flags")
+	}
+	return nil
+}
+
 func (r *UserRepository) Add(ctx context.Context, user *User) error {
 	_, err := r.db.ExecContext(ctx, `
 insert into
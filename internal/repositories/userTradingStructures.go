@@ -20,7 +20,7 @@ func NewUserTradingStructures(db *sql.DB) *UserTradingStructures {
 // List returns all structures for a user.
 func (r *UserTradingStructures) List(ctx context.Context, userID int64) ([]*models.UserTradingStructure, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, structure_id, name, system_id, region_id, character_id, access_ok, last_scanned_at, created_at
+		SELECT id, user_id, structure_id, name, system_id, region_id, character_id, access_ok, broker_fee_pct, last_scanned_at, created_at
 		FROM user_trading_structures
 		WHERE user_id = $1
 		ORDER BY name ASC`, userID)
@@ -32,14 +32,18 @@ func (r *UserTradingStructures) List(ctx context.Context, userID int64) ([]*mode
 	structures := []*models.UserTradingStructure{}
 	for rows.Next() {
 		var s models.UserTradingStructure
+		var brokerFeePct sql.NullFloat64
 		var lastScannedAt sql.NullTime
 		var createdAt time.Time
 		if err := rows.Scan(
 			&s.ID, &s.UserID, &s.StructureID, &s.Name, &s.SystemID, &s.RegionID,
-			&s.CharacterID, &s.AccessOK, &lastScannedAt, &createdAt,
+			&s.CharacterID, &s.AccessOK, &brokerFeePct, &lastScannedAt, &createdAt,
 		); err != nil {
 			return nil, errors.Wrap(err, "failed to scan user trading structure")
 		}
+		if brokerFeePct.Valid {
+			s.BrokerFeePct = &brokerFeePct.Float64
+		}
 		if lastScannedAt.Valid {
 			ts := lastScannedAt.Time.Format(time.RFC3339)
 			s.LastScannedAt = &ts
@@ -60,9 +64,10 @@ func (r *UserTradingStructures) Upsert(ctx context.Context, s *models.UserTradin
 
 	var id int64
 	var createdAt time.Time
+	var brokerFeePct sql.NullFloat64
 	err = tx.QueryRowContext(ctx, `
-		INSERT INTO user_trading_structures (user_id, structure_id, name, system_id, region_id, character_id, access_ok, last_scanned_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		INSERT INTO user_trading_structures (user_id, structure_id, name, system_id, region_id, character_id, access_ok, last_scanned_at, broker_fee_pct, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
 		ON CONFLICT (user_id, structure_id) DO UPDATE SET
 			name = EXCLUDED.name,
 			system_id = EXCLUDED.system_id,
@@ -70,10 +75,11 @@ func (r *UserTradingStructures) Upsert(ctx context.Context, s *models.UserTradin
 			character_id = EXCLUDED.character_id,
 			access_ok = EXCLUDED.access_ok,
 			last_scanned_at = EXCLUDED.last_scanned_at,
+			broker_fee_pct = COALESCE(EXCLUDED.broker_fee_pct, user_trading_structures.broker_fee_pct),
 			updated_at = NOW()
-		RETURNING id, created_at`,
-		s.UserID, s.StructureID, s.Name, s.SystemID, s.RegionID, s.CharacterID, s.AccessOK, nil,
-	).Scan(&id, &createdAt)
+		RETURNING id, created_at, broker_fee_pct`,
+		s.UserID, s.StructureID, s.Name, s.SystemID, s.RegionID, s.CharacterID, s.AccessOK, nil, s.BrokerFeePct,
+	).Scan(&id, &createdAt, &brokerFeePct)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to upsert user trading structure")
 	}
@@ -84,9 +90,29 @@ func (r *UserTradingStructures) Upsert(ctx context.Context, s *models.UserTradin
 
 	s.ID = id
 	s.CreatedAt = createdAt.Format(time.RFC3339)
+	s.BrokerFeePct = nil
+	if brokerFeePct.Valid {
+		s.BrokerFeePct = &brokerFeePct.Float64
+	}
 	return s, nil
 }
 
+// UpdateBrokerFee sets the owner-configured broker rate of a structure by id+userID.
+// A nil rate clears it.
+func (r *UserTradingStructures) UpdateBrokerFee(ctx context.Context, id int64, userID int64, brokerFeePct *float64) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE user_trading_structures SET broker_fee_pct=$1, updated_at=NOW() WHERE id=$2 AND user_id=$3`,
+		brokerFeePct, id, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to update broker fee")
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New("user trading structure not found")
+	}
+	return nil
+}
+
 // Delete removes a structure by id+userID.
 func (r *UserTradingStructures) Delete(ctx context.Context, id int64, userID int64) error {
 	result, err := r.db.ExecContext(ctx,
@@ -204,3 +204,59 @@ func Test_UserTradingStructures_UpdateAccessStatus(t *testing.T) {
 	assert.Len(t, structures, 1)
 	assert.Equal(t, false, structures[0].AccessOK)
 }
+
+func Test_UserTradingStructures_UpdateBrokerFee(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	userRepo := repositories.NewUserRepository(db)
+	userID := int64(9750)
+	err = userRepo.Add(context.Background(), &repositories.User{ID: userID, Name: "Structures Test User 6"})
+	assert.NoError(t, err)
+
+	repo := repositories.NewUserTradingStructures(db)
+
+	created, err := repo.Upsert(context.Background(), &models.UserTradingStructure{
+		UserID:      userID,
+		StructureID: int64(1000000000040),
+		Name:        "Broker Fee Structure",
+		SystemID:    int64(30000142),
+		RegionID:    int64(10000002),
+		CharacterID: int64(200001006),
+		AccessOK:    true,
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, created.BrokerFeePct)
+
+	fee := 1.25
+	err = repo.UpdateBrokerFee(context.Background(), created.ID, userID, &fee)
+	assert.NoError(t, err)
+
+	// Re-adding the structure keeps the configured rate
+	_, err = repo.Upsert(context.Background(), &models.UserTradingStructure{
+		UserID:      userID,
+		StructureID: int64(1000000000040),
+		Name:        "Broker Fee Structure",
+		SystemID:    int64(30000142),
+		RegionID:    int64(10000002),
+		CharacterID: int64(200001006),
+		AccessOK:    true,
+	})
+	assert.NoError(t, err)
+
+	structures, err := repo.List(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Len(t, structures, 1)
+	if assert.NotNil(t, structures[0].BrokerFeePct) {
+		assert.InDelta(t, 1.25, *structures[0].BrokerFeePct, 0.0001)
+	}
+
+	err = repo.UpdateBrokerFee(context.Background(), created.ID, userID, nil)
+	assert.NoError(t, err)
+	structures, err = repo.List(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Nil(t, structures[0].BrokerFeePct)
+
+	err = repo.UpdateBrokerFee(context.Background(), int64(999999), userID, &fee)
+	assert.Error(t, err)
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/stretchr/testify/assert"
 )
@@ -178,3 +179,33 @@ func Test_UserGetAssetsLastUpdatedNonExistentUser(t *testing.T) {
 	_, err = repo.GetAssetsLastUpdated(context.Background(), 99999)
 	assert.Error(t, err)
 }
+
+func Test_UserAssetLocationFlagsDefaultAndOverride(t *testing.T) {
+	db, err := setupDatabase(t)
+	assert.NoError(t, err)
+
+	repo := repositories.NewUserRepository(db)
+
+	err = repo.Add(context.Background(), &repositories.User{ID: 42, Name: "Flags User"})
+	assert.NoError(t, err)
+
+	settings, err := repo.GetAssetLocationFlags(context.Background(), 42)
+	assert.NoError(t, err)
+	assert.True(t, settings.IsDefault)
+	assert.Equal(t, models.DefaultAssetLocationFlags, settings.Flags)
+
+	err = repo.SetAssetLocationFlags(context.Background(), 42, []string{"Hangar", "DroneBay"})
+	assert.NoError(t, err)
+
+	settings, err = repo.GetAssetLocationFlags(context.Background(), 42)
+	assert.NoError(t, err)
+	assert.False(t, settings.IsDefault)
+	assert.Equal(t, []string{"Hangar", "DroneBay"}, settings.Flags)
+
+	err = repo.SetAssetLocationFlags(context.Background(), 42, nil)
+	assert.NoError(t, err)
+
+	settings, err = repo.GetAssetLocationFlags(context.Background(), 42)
+	assert.NoError(t, err)
+	assert.True(t, settings.IsDefault)
+}
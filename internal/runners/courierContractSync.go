@@ -0,0 +1,57 @@
+package runners
+
+import (
+	"context"
+	"time"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+)
+
+type CourierContractSyncUpdater interface {
+	SyncAll(ctx context.Context) error
+}
+
+type CourierContractSyncRunner struct {
+	updater       CourierContractSyncUpdater
+	interval      time.Duration
+	tickerFactory TickerFactory
+}
+
+func NewCourierContractSyncRunner(updater CourierContractSyncUpdater, interval time.Duration) *CourierContractSyncRunner {
+	return &CourierContractSyncRunner{
+		updater:  updater,
+		interval: interval,
+		tickerFactory: func(d time.Duration) Ticker {
+			return &realTicker{time.NewTicker(d)}
+		},
+	}
+}
+
+// WithTickerFactory allows injecting a custom ticker factory for testing
+func (r *CourierContractSyncRunner) WithTickerFactory(factory TickerFactory) *CourierContractSyncRunner {
+	r.tickerFactory = factory
+	return r
+}
+
+func (r *CourierContractSyncRunner) Run(ctx context.Context) error {
+	ticker := r.tickerFactory(r.interval)
+	defer ticker.Stop()
+
+	// Run immediately on startup
+	log.Info("courier contract sync: running on startup")
+	if err := r.updater.SyncAll(ctx); err != nil {
+		log.Error("courier contract sync: failed on startup", "error", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			log.Info("courier contract sync: running (scheduled)")
+			if err := r.updater.SyncAll(ctx); err != nil {
+				log.Error("courier contract sync: failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package runners_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/runners"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_CourierContractSyncRunner_SyncsOnStartupError(t *testing.T) {
+	mockUpdater := new(MockContractSyncUpdater)
+	mockTicker := NewMockTicker()
+
+	runner := runners.NewCourierContractSyncRunner(mockUpdater, 15*time.Minute).
+		WithTickerFactory(func(d time.Duration) runners.Ticker {
+			return mockTicker
+		})
+
+	mockUpdater.On("SyncAll", mock.Anything).Return(errors.New("startup error")).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := runner.Run(ctx)
+
+	assert.NoError(t, err)
+	mockUpdater.AssertExpectations(t)
+}
+
+func Test_CourierContractSyncRunner_SyncsPeriodically(t *testing.T) {
+	mockUpdater := new(MockContractSyncUpdater)
+	mockTicker := NewMockTicker()
+
+	runner := runners.NewCourierContractSyncRunner(mockUpdater, 15*time.Minute).
+		WithTickerFactory(func(d time.Duration) runners.Ticker {
+			return mockTicker
+		})
+
+	// Expect 2 calls: 1 on startup + 1 scheduled
+	mockUpdater.On("SyncAll", mock.Anything).Return(nil).Times(2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- runner.Run(ctx)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	mockTicker.Tick()
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	err := <-done
+
+	assert.NoError(t, err)
+	mockUpdater.AssertExpectations(t)
+}
@@ -0,0 +1,57 @@
+package runners
+
+import (
+	"context"
+	"time"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+)
+
+type InvoicesUpdater interface {
+	UpdateAll(ctx context.Context) error
+}
+
+type InvoicesRunner struct {
+	updater       InvoicesUpdater
+	interval      time.Duration
+	tickerFactory TickerFactory
+}
+
+func NewInvoicesRunner(updater InvoicesUpdater, interval time.Duration) *InvoicesRunner {
+	return &InvoicesRunner{
+		updater:  updater,
+		interval: interval,
+		tickerFactory: func(d time.Duration) Ticker {
+			return &realTicker{time.NewTicker(d)}
+		},
+	}
+}
+
+// WithTickerFactory allows injecting a custom ticker factory for testing
+func (r *InvoicesRunner) WithTickerFactory(factory TickerFactory) *InvoicesRunner {
+	r.tickerFactory = factory
+	return r
+}
+
+func (r *InvoicesRunner) Run(ctx context.Context) error {
+	ticker := r.tickerFactory(r.interval)
+	defer ticker.Stop()
+
+	// Run immediately on startup
+	log.Info("invoices: running on startup")
+	if err := r.updater.UpdateAll(ctx); err != nil {
+		log.Error("invoices: failed on startup", "error", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			log.Info("invoices: running (scheduled)")
+			if err := r.updater.UpdateAll(ctx); err != nil {
+				log.Error("invoices: failed", "error", err)
+			}
+		}
+	}
+}
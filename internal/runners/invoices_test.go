@@ -0,0 +1,75 @@
+package runners_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/runners"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockInvoicesUpdater mocks the InvoicesUpdater interface
+type MockInvoicesUpdater struct {
+	mock.Mock
+}
+
+func (m *MockInvoicesUpdater) UpdateAll(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func Test_InvoicesRunner_UpdatesOnStartup(t *testing.T) {
+	mockUpdater := new(MockInvoicesUpdater)
+	mockTicker := NewMockTicker()
+
+	runner := runners.NewInvoicesRunner(mockUpdater, 15*time.Minute).
+		WithTickerFactory(func(d time.Duration) runners.Ticker {
+			return mockTicker
+		})
+
+	mockUpdater.On("UpdateAll", mock.Anything).Return(nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := runner.Run(ctx)
+
+	assert.NoError(t, err)
+	mockUpdater.AssertExpectations(t)
+}
+
+func Test_InvoicesRunner_UpdatesPeriodically(t *testing.T) {
+	mockUpdater := new(MockInvoicesUpdater)
+	mockTicker := NewMockTicker()
+
+	runner := runners.NewInvoicesRunner(mockUpdater, 15*time.Minute).
+		WithTickerFactory(func(d time.Duration) runners.Ticker {
+			return mockTicker
+		})
+
+	// Expect 3 calls: 1 on startup + 2 scheduled
+	mockUpdater.On("UpdateAll", mock.Anything).Return(nil).Times(3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- runner.Run(ctx)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	mockTicker.Tick()
+	time.Sleep(10 * time.Millisecond)
+	mockTicker.Tick()
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	err := <-done
+
+	assert.NoError(t, err)
+	mockUpdater.AssertExpectations(t)
+}
@@ -0,0 +1,55 @@
+package runners
+
+import (
+	"context"
+	"time"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+)
+
+type StockpileConsumptionUpdaterInterface interface {
+	RunAll(ctx context.Context) error
+}
+
+type StockpileConsumptionRunner struct {
+	updater       StockpileConsumptionUpdaterInterface
+	interval      time.Duration
+	tickerFactory TickerFactory
+}
+
+func NewStockpileConsumptionRunner(updater StockpileConsumptionUpdaterInterface, interval time.Duration) *StockpileConsumptionRunner {
+	return &StockpileConsumptionRunner{
+		updater:  updater,
+		interval: interval,
+		tickerFactory: func(d time.Duration) Ticker {
+			return &realTicker{time.NewTicker(d)}
+		},
+	}
+}
+
+// WithTickerFactory allows injecting a custom ticker factory for testing.
+func (r *StockpileConsumptionRunner) WithTickerFactory(factory TickerFactory) *StockpileConsumptionRunner {
+	r.tickerFactory = factory
+	return r
+}
+
+// Run waits for the first scheduled tick before running so the first snapshot is not
+// taken before asset data has been populated after startup.
+func (r *StockpileConsumptionRunner) Run(ctx context.Context) error {
+	ticker := r.tickerFactory(r.interval)
+	defer ticker.Stop()
+
+	log.Info("stockpile consumption: waiting for first scheduled tick")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			log.Info("stockpile consumption: running (scheduled)")
+			if err := r.updater.RunAll(ctx); err != nil {
+				log.Error("stockpile consumption: failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/calculator"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+// gateGraphTTL is how long a loaded stargate graph is used before it is
+// reloaded, picking up SDE updates without a restart.
+const gateGraphTTL = 24 * time.Hour
+
+// emptyGateGraphRetry is how long an empty graph is kept before the next
+// reload, so routes don't query the database while the SDE import is pending.
+const emptyGateGraphRetry = 5 * time.Minute
+
+// maxCachedRoutes bounds the route cache; it is cleared when full.
+const maxCachedRoutes = 50000
+
+// GateRouterRepository loads the stargate graph imported from the SDE.
+type GateRouterRepository interface {
+	GetRouteSystems(ctx context.Context) ([]models.RouteSystem, error)
+	GetSystemJumps(ctx context.Context) ([]models.SystemJump, error)
+}
+
+// GateRouteFallback answers plain flag routes while no stargates have been
+// imported yet, typically the ESI client.
+type GateRouteFallback interface {
+	GetRoute(ctx context.Context, origin, destination int64, flag string) ([]int32, error)
+}
+
+// GateRouter finds gate routes locally from the SDE stargate graph and caches
+// the results for every route consumer. It has the same GetRoute signature as
+// the ESI client, so it can stand in for it.
+type GateRouter struct {
+	repo     GateRouterRepository
+	fallback GateRouteFallback
+	now      func() time.Time
+
+	// loadMu serialises graph reloads so only one caller queries the
+	// database. mu guards the fields below and is never held across a
+	// reload, a route search or the fallback.
+	loadMu   sync.Mutex
+	mu       sync.Mutex
+	graph    *calculator.GateGraph
+	loadedAt time.Time
+	cache    map[string]*models.GateRoute
+}
+
+// NewGateRouter creates a router. fallback may be nil.
+func NewGateRouter(repo GateRouterRepository, fallback GateRouteFallback) *GateRouter {
+	return &GateRouter{
+		repo:     repo,
+		fallback: fallback,
+		now:      time.Now,
+		cache:    map[string]*models.GateRoute{},
+	}
+}
+
+// GetRoute returns the system IDs of a route, origin and destination
+// included. Flag is "shortest", "secure" or "insecure".
+func (r *GateRouter) GetRoute(ctx context.Context, origin, destination int64, flag string) ([]int32, error) {
+	route, err := r.FindRoute(ctx, origin, destination, &models.RouteOptions{Flag: flag})
+	if err != nil {
+		return nil, err
+	}
+	return route.Route, nil
+}
+
+// FindRoute returns the cheapest gate route under opts. Results are cached
+// until the graph is reloaded.
+func (r *GateRouter) FindRoute(ctx context.Context, origin, destination int64, opts *models.RouteOptions) (*models.GateRoute, error) {
+	if opts == nil {
+		opts = &models.RouteOptions{}
+	}
+
+	graph, err := r.currentGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if graph.Len() == 0 {
+		return r.fallbackRoute(ctx, origin, destination, opts)
+	}
+
+	key := routeCacheKey(origin, destination, opts)
+	r.mu.Lock()
+	cached, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok {
+		return copyGateRoute(cached), nil
+	}
+
+	// The graph is never modified once built, so it is searched unlocked
+	route, err := graph.FindRoute(origin, destination, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	// Routes from a graph replaced during the search are not cached
+	if r.graph == graph {
+		if len(r.cache) >= maxCachedRoutes {
+			r.cache = map[string]*models.GateRoute{}
+		}
+		r.cache[key] = route
+	}
+	r.mu.Unlock()
+	return copyGateRoute(route), nil
+}
+
+// currentGraph returns the stargate graph, (re)loading it when it is missing
+// or stale. An empty graph goes stale after emptyGateGraphRetry instead of
+// gateGraphTTL. Reloading clears the route cache.
+func (r *GateRouter) currentGraph(ctx context.Context) (*calculator.GateGraph, error) {
+	if graph := r.freshGraph(); graph != nil {
+		return graph, nil
+	}
+
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
+	// Another caller may have reloaded while this one waited
+	if graph := r.freshGraph(); graph != nil {
+		return graph, nil
+	}
+
+	systems, err := r.repo.GetRouteSystems(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load route systems")
+	}
+	jumps, err := r.repo.GetSystemJumps(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load system jumps")
+	}
+	graph := calculator.NewGateGraph(systems, jumps)
+
+	r.mu.Lock()
+	r.graph = graph
+	r.loadedAt = r.now()
+	r.cache = map[string]*models.GateRoute{}
+	r.mu.Unlock()
+	return graph, nil
+}
+
+// freshGraph returns the loaded graph, or nil when it is missing or stale.
+func (r *GateRouter) freshGraph() *calculator.GateGraph {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.graph == nil {
+		return nil
+	}
+	ttl := gateGraphTTL
+	if r.graph.Len() == 0 {
+		ttl = emptyGateGraphRetry
+	}
+	if r.now().Sub(r.loadedAt) >= ttl {
+		return nil
+	}
+	return r.graph
+}
+
+// fallbackRoute asks the fallback for a route while the graph is empty. Only
+// plain flag routes can be answered that way.
+func (r *GateRouter) fallbackRoute(ctx context.Context, origin, destination int64, opts *models.RouteOptions) (*models.GateRoute, error) {
+	constrained := len(opts.AvoidSystemIDs) > 0 || len(opts.AvoidRegionIDs) > 0 || opts.MinSecurity != nil || opts.JumpCosts != nil
+	if r.fallback == nil || constrained {
+		return nil, errors.New("stargate data has not been imported from the SDE yet")
+	}
+
+	flag := opts.Flag
+	if flag == "" {
+		flag = "shortest"
+	}
+	route, err := r.fallback.GetRoute(ctx, origin, destination, flag)
+	if err != nil {
+		return nil, err
+	}
+	jumps := 0
+	if len(route) > 0 {
+		jumps = len(route) - 1
+	}
+	return &models.GateRoute{Route: route, Jumps: jumps, Cost: float64(jumps)}, nil
+}
+
+func routeCacheKey(origin, destination int64, opts *models.RouteOptions) string {
+	avoidSystems := append([]int64{}, opts.AvoidSystemIDs...)
+	sort.Slice(avoidSystems, func(a, b int) bool { return avoidSystems[a] < avoidSystems[b] })
+	avoidRegions := append([]int64{}, opts.AvoidRegionIDs...)
+	sort.Slice(avoidRegions, func(a, b int) bool { return avoidRegions[a] < avoidRegions[b] })
+
+	minSecurity := "-"
+	if opts.MinSecurity != nil {
+		minSecurity = fmt.Sprintf("%g", *opts.MinSecurity)
+	}
+	costs := calculator.RouteJumpCostsForFlag(opts.Flag)
+	if opts.JumpCosts != nil {
+		costs = *opts.JumpCosts
+	}
+	return fmt.Sprintf("%d:%d:%v:%v:%s:%g/%g/%g", origin, destination, avoidSystems, avoidRegions, minSecurity,
+		costs.Highsec, costs.Lowsec, costs.Nullsec)
+}
+
+func copyGateRoute(route *models.GateRoute) *models.GateRoute {
+	c := *route
+	c.Route = append([]int32{}, route.Route...)
+	return &c
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockGateRouterRepository struct {
+	mock.Mock
+}
+
+func (m *MockGateRouterRepository) GetRouteSystems(ctx context.Context) ([]models.RouteSystem, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RouteSystem), args.Error(1)
+}
+
+func (m *MockGateRouterRepository) GetSystemJumps(ctx context.Context) ([]models.SystemJump, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SystemJump), args.Error(1)
+}
+
+type MockGateRouteFallback struct {
+	mock.Mock
+}
+
+func (m *MockGateRouteFallback) GetRoute(ctx context.Context, origin, destination int64, flag string) ([]int32, error) {
+	args := m.Called(ctx, origin, destination, flag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int32), args.Error(1)
+}
+
+// Jita (1) - Perimeter (2) - Niyabainen (3), with a lowsec shortcut Jita - Tama (4) - Niyabainen
+func gateRouterGraph() ([]models.RouteSystem, []models.SystemJump) {
+	systems := []models.RouteSystem{
+		{ID: 1, RegionID: 10, Security: 0.9},
+		{ID: 2, RegionID: 10, Security: 1.0},
+		{ID: 3, RegionID: 20, Security: 0.8},
+		{ID: 4, RegionID: 20, Security: 0.3},
+		{ID: 5, RegionID: 20, Security: 0.7},
+	}
+	jumps := []models.SystemJump{}
+	for _, pair := range [][2]int64{{1, 2}, {2, 5}, {5, 3}, {1, 4}, {4, 3}} {
+		jumps = append(jumps,
+			models.SystemJump{FromSystemID: pair[0], ToSystemID: pair[1]},
+			models.SystemJump{FromSystemID: pair[1], ToSystemID: pair[0]},
+		)
+	}
+	return systems, jumps
+}
+
+func Test_GateRouter_RoutesLocallyAndCaches(t *testing.T) {
+	repo := &MockGateRouterRepository{}
+	fallback := &MockGateRouteFallback{}
+	systems, jumps := gateRouterGraph()
+	repo.On("GetRouteSystems", mock.Anything).Return(systems, nil).Once()
+	repo.On("GetSystemJumps", mock.Anything).Return(jumps, nil).Once()
+
+	router := NewGateRouter(repo, fallback)
+	ctx := context.Background()
+
+	route, err := router.GetRoute(ctx, 1, 3, "shortest")
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1, 4, 3}, route)
+
+	route, err = router.GetRoute(ctx, 1, 3, "secure")
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1, 2, 5, 3}, route)
+
+	// Cached: mutating a result does not leak into the next caller
+	route[0] = 99
+	route, err = router.GetRoute(ctx, 1, 3, "secure")
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1, 2, 5, 3}, route)
+
+	minSecurity := 0.5
+	found, err := router.FindRoute(ctx, 1, 3, &models.RouteOptions{MinSecurity: &minSecurity})
+	require.NoError(t, err)
+	assert.Equal(t, 3, found.Jumps)
+
+	found, err = router.FindRoute(ctx, 1, 3, &models.RouteOptions{AvoidSystemIDs: []int64{4}, AvoidRegionIDs: []int64{}})
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1, 2, 5, 3}, found.Route)
+
+	_, err = router.FindRoute(ctx, 1, 3, &models.RouteOptions{AvoidRegionIDs: []int64{20}})
+	assert.EqualError(t, err, "no gate route from 1 to 3")
+
+	// Graph loaded once for every lookup; ESI never asked
+	repo.AssertExpectations(t)
+	fallback.AssertNotCalled(t, "GetRoute", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_GateRouter_ReloadsStaleGraph(t *testing.T) {
+	repo := &MockGateRouterRepository{}
+	systems, jumps := gateRouterGraph()
+	repo.On("GetRouteSystems", mock.Anything).Return(systems, nil).Twice()
+	repo.On("GetSystemJumps", mock.Anything).Return(jumps, nil).Twice()
+
+	now := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	router := NewGateRouter(repo, nil)
+	router.now = func() time.Time { return now }
+
+	_, err := router.GetRoute(context.Background(), 1, 3, "shortest")
+	require.NoError(t, err)
+
+	now = now.Add(25 * time.Hour)
+	_, err = router.GetRoute(context.Background(), 1, 3, "shortest")
+	require.NoError(t, err)
+
+	repo.AssertExpectations(t)
+}
+
+func Test_GateRouter_FallsBackWithoutStargates(t *testing.T) {
+	repo := &MockGateRouterRepository{}
+	fallback := &MockGateRouteFallback{}
+	repo.On("GetRouteSystems", mock.Anything).Return([]models.RouteSystem{}, nil)
+	repo.On("GetSystemJumps", mock.Anything).Return([]models.SystemJump{}, nil)
+	fallback.On("GetRoute", mock.Anything, int64(1), int64(3), "shortest").Return([]int32{1, 4, 3}, nil).Once()
+
+	router := NewGateRouter(repo, fallback)
+
+	found, err := router.FindRoute(context.Background(), 1, 3, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1, 4, 3}, found.Route)
+	assert.Equal(t, 2, found.Jumps)
+
+	// Constraints cannot be passed on to the fallback
+	minSecurity := 0.5
+	_, err = router.FindRoute(context.Background(), 1, 3, &models.RouteOptions{MinSecurity: &minSecurity})
+	assert.EqualError(t, err, "stargate data has not been imported from the SDE yet")
+
+	fallback.AssertExpectations(t)
+}
+
+func Test_GateRouter_BacksOffReloadingEmptyGraph(t *testing.T) {
+	repo := &MockGateRouterRepository{}
+	fallback := &MockGateRouteFallback{}
+	systems, jumps := gateRouterGraph()
+	repo.On("GetRouteSystems", mock.Anything).Return([]models.RouteSystem{}, nil).Once()
+	repo.On("GetSystemJumps", mock.Anything).Return([]models.SystemJump{}, nil).Once()
+	repo.On("GetRouteSystems", mock.Anything).Return(systems, nil).Once()
+	repo.On("GetSystemJumps", mock.Anything).Return(jumps, nil).Once()
+	fallback.On("GetRoute", mock.Anything, int64(1), int64(3), "shortest").Return([]int32{1, 4, 3}, nil).Twice()
+
+	now := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	router := NewGateRouter(repo, fallback)
+	router.now = func() time.Time { return now }
+
+	// The empty graph is reused instead of queried again
+	for i := 0; i < 2; i++ {
+		_, err := router.GetRoute(context.Background(), 1, 3, "shortest")
+		require.NoError(t, err)
+	}
+
+	// Once the retry window passes the imported stargates are picked up
+	now = now.Add(emptyGateGraphRetry)
+	route, err := router.GetRoute(context.Background(), 1, 3, "secure")
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1, 2, 5, 3}, route)
+
+	repo.AssertExpectations(t)
+	fallback.AssertExpectations(t)
+}
+
+func Test_GateRouter_LoadError(t *testing.T) {
+	repo := &MockGateRouterRepository{}
+	repo.On("GetRouteSystems", mock.Anything).Return(nil, assert.AnError)
+
+	router := NewGateRouter(repo, nil)
+
+	_, err := router.GetRoute(context.Background(), 1, 3, "shortest")
+	assert.ErrorContains(t, err, "failed to load route systems")
+}
+
+// blockingGateRouteFallback holds routes from origin 1 until released.
+type blockingGateRouteFallback struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (f *blockingGateRouteFallback) GetRoute(ctx context.Context, origin, destination int64, flag string) ([]int32, error) {
+	if origin == 1 {
+		close(f.entered)
+		<-f.release
+	}
+	return []int32{int32(origin), int32(destination)}, nil
+}
+
+func Test_GateRouter_SlowFallbackDoesNotBlockOtherRoutes(t *testing.T) {
+	repo := &MockGateRouterRepository{}
+	repo.On("GetRouteSystems", mock.Anything).Return([]models.RouteSystem{}, nil).Once()
+	repo.On("GetSystemJumps", mock.Anything).Return([]models.SystemJump{}, nil).Once()
+	fallback := &blockingGateRouteFallback{entered: make(chan struct{}), release: make(chan struct{})}
+
+	router := NewGateRouter(repo, fallback)
+
+	slow := make(chan error)
+	go func() {
+		_, err := router.GetRoute(context.Background(), 1, 3, "shortest")
+		slow <- err
+	}()
+	<-fallback.entered
+
+	route, err := router.GetRoute(context.Background(), 2, 3, "shortest")
+	require.NoError(t, err)
+	assert.Equal(t, []int32{2, 3}, route)
+
+	close(fallback.release)
+	require.NoError(t, <-slow)
+	repo.AssertExpectations(t)
+}
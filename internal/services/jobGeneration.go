@@ -40,6 +40,17 @@ type PendingJob struct {
 	Rig               string
 	Security          string
 	BlueprintTE       int
+	// Stock inputs of the job (materials no child step produces), taken from the
+	// source location of Step and reserved when the job is queued
+	Inputs   []*MaterialInput
+	MEFactor float64
+	Step     *models.ProductionPlanStep
+}
+
+// MaterialInput is the per-run base quantity of a material a job draws from stock.
+type MaterialInput struct {
+	TypeID   int64
+	Quantity int
 }
 
 // MergeKey identifies jobs that can be merged (same blueprint, settings).
@@ -186,11 +197,14 @@ func WalkAndMergeSteps(
 			childProductTypeIDs[child.ProductTypeID] = child
 		}
 
+		inputs := []*MaterialInput{}
 		for _, mat := range materials {
 			if childStep, ok := childProductTypeIDs[mat.TypeID]; ok {
 				// This material is produced — calculate needed quantity
 				batchQty := calculator.ComputeBatchQty(runs, mat.Quantity, meFactor)
 				walkStep(childStep, int(batchQty), depth+1)
+			} else {
+				inputs = append(inputs, &MaterialInput{TypeID: mat.TypeID, Quantity: mat.Quantity})
 			}
 		}
 
@@ -269,6 +283,9 @@ func WalkAndMergeSteps(
 			Rig:               step.Rig,
 			Security:          step.Security,
 			BlueprintTE:       step.TELevel,
+			Inputs:            inputs,
+			MEFactor:          meFactor,
+			Step:              step,
 		})
 	}
 
@@ -329,6 +346,30 @@ func WalkAndMergeSteps(
 	return wr, nil
 }
 
+// BuildMaterialReservations returns the stock inputs of runs of a pending job as reservations
+// at the source location of its step. Steps without a source owner and location reserve nothing.
+// Merged jobs reserve at the source of the first merged step.
+func BuildMaterialReservations(pj *PendingJob, runs int) []*models.MaterialReservation {
+	step := pj.Step
+	if step == nil || step.SourceOwnerType == nil || step.SourceOwnerID == nil || step.SourceLocationID == nil {
+		return nil
+	}
+
+	reservations := make([]*models.MaterialReservation, 0, len(pj.Inputs))
+	for _, input := range pj.Inputs {
+		reservations = append(reservations, &models.MaterialReservation{
+			TypeID:         input.TypeID,
+			Quantity:       calculator.ComputeBatchQty(runs, input.Quantity, pj.MEFactor),
+			OwnerType:      *step.SourceOwnerType,
+			OwnerID:        *step.SourceOwnerID,
+			LocationID:     *step.SourceLocationID,
+			ContainerID:    step.SourceContainerID,
+			DivisionNumber: step.SourceDivisionNumber,
+		})
+	}
+	return reservations
+}
+
 // SimulateAssignment distributes merged jobs across up to parallelism characters.
 // It clones capacity state so the originals are not mutated.
 // Returns the list of assigned job fragments and count of unassigned runs.
@@ -639,6 +639,13 @@ func Test_WalkAndMergeSteps(t *testing.T) {
 		assert.Equal(t, 5, rootJob.Entry.Runs)
 		assert.Equal(t, int64(200), rootJob.Entry.BlueprintTypeID)
 
+		// Only materials not produced by a child step are stock inputs
+		assert.Empty(t, rootJob.Inputs)
+		childJob := result.MergedJobs[0]
+		assert.Len(t, childJob.Inputs, 1)
+		assert.Equal(t, int64(120), childJob.Inputs[0].TypeID)
+		assert.Equal(t, 3, childJob.Inputs[0].Quantity)
+
 		// StepProduction should be populated for both steps
 		assert.Contains(t, result.StepProduction, int64(1))
 		assert.Contains(t, result.StepProduction, int64(2))
@@ -904,3 +911,49 @@ func Test_WalkAndMergeSteps(t *testing.T) {
 		sdeRepo.AssertExpectations(t)
 	})
 }
+
+// ---------------------------------------------------------------------------
+// BuildMaterialReservations tests
+// ---------------------------------------------------------------------------
+
+func Test_BuildMaterialReservations(t *testing.T) {
+	t.Run("step without source reserves nothing", func(t *testing.T) {
+		pj := &PendingJob{
+			Inputs:   []*MaterialInput{{TypeID: 34, Quantity: 100}},
+			MEFactor: 1.0,
+			Step:     makeStep(1, nil, 100, 200, "manufacturing"),
+		}
+		assert.Nil(t, BuildMaterialReservations(pj, 10))
+	})
+
+	t.Run("inputs scaled by runs at the step source", func(t *testing.T) {
+		step := makeStep(1, nil, 100, 200, "manufacturing")
+		step.SourceOwnerType = strPtr("corporation")
+		step.SourceOwnerID = intPtr(98000001)
+		step.SourceLocationID = intPtr(60003760)
+		step.SourceContainerID = intPtr(9000)
+		division := 2
+		step.SourceDivisionNumber = &division
+
+		pj := &PendingJob{
+			Inputs: []*MaterialInput{
+				{TypeID: 34, Quantity: 100},
+				{TypeID: 35, Quantity: 1},
+			},
+			MEFactor: 0.9,
+			Step:     step,
+		}
+
+		reservations := BuildMaterialReservations(pj, 10)
+		assert.Len(t, reservations, 2)
+		assert.Equal(t, int64(34), reservations[0].TypeID)
+		assert.Equal(t, int64(900), reservations[0].Quantity)
+		// one-unit materials are never reduced below one per run
+		assert.Equal(t, int64(10), reservations[1].Quantity)
+		assert.Equal(t, "corporation", reservations[0].OwnerType)
+		assert.Equal(t, int64(98000001), reservations[0].OwnerID)
+		assert.Equal(t, int64(60003760), reservations[0].LocationID)
+		assert.Equal(t, int64(9000), *reservations[0].ContainerID)
+		assert.Equal(t, 2, *reservations[0].DivisionNumber)
+	})
+}
@@ -55,6 +55,10 @@ type UserTimestampRepository interface {
 	UpdateAssetsLastUpdated(ctx context.Context, userID int64) error
 }
 
+type UserAssetSettingsRepository interface {
+	GetAssetLocationFlags(ctx context.Context, userID int64) (*models.AssetLocationFlagSettings, error)
+}
+
 type EsiClient interface {
 	GetCharacterAssets(ctx context.Context, characterID int64, token, refresh string, expire time.Time) ([]*models.EveAsset, error)
 	GetCharacterLocationNames(ctx context.Context, characterID int64, token, refresh string, expire time.Time, ids []int64) (map[int64]string, error)
@@ -88,6 +92,7 @@ type Assets struct {
 	autoSellSyncer                    AutoSellSyncer
 	autoBuySyncer                     AutoBuySyncer
 	autoFulfillSyncer                 AutoFulfillSyncer
+	assetSettingsRepository           UserAssetSettingsRepository
 	concurrency                       int
 }
 
@@ -194,6 +199,37 @@ func (u *Assets) WithAutoFulfillUpdater(syncer AutoFulfillSyncer) {
 	u.autoFulfillSyncer = syncer
 }
 
+// WithAssetSettingsRepository sets the optional repository for per-user location flag allow lists.
+// Without it every user syncs models.DefaultAssetLocationFlags.
+func (u *Assets) WithAssetSettingsRepository(repo UserAssetSettingsRepository) {
+	u.assetSettingsRepository = repo
+}
+
+// filterAssetsByLocationFlag drops assets whose location flag is not in the user's allow list
+func (u *Assets) filterAssetsByLocationFlag(ctx context.Context, userID int64, assets []*models.EveAsset) ([]*models.EveAsset, error) {
+	allowList := models.DefaultAssetLocationFlags
+	if u.assetSettingsRepository != nil {
+		settings, err := u.assetSettingsRepository.GetAssetLocationFlags(ctx, userID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get asset location flags")
+		}
+		allowList = settings.Flags
+	}
+
+	allowed := make(map[string]bool, len(allowList))
+	for _, flag := range allowList {
+		allowed[flag] = true
+	}
+
+	filtered := make([]*models.EveAsset, 0, len(assets))
+	for _, asset := range assets {
+		if allowed[asset.LocationFlag] {
+			filtered = append(filtered, asset)
+		}
+	}
+	return filtered, nil
+}
+
 // UpdateCharacterAssets updates assets for a single character
 func (u *Assets) UpdateCharacterAssets(ctx context.Context, char *repositories.Character, userID int64) error {
 	// Skip characters with revoked ESI authorization — they need user re-auth via OAuth.
@@ -235,6 +271,11 @@ func (u *Assets) UpdateCharacterAssets(ctx context.Context, char *repositories.C
 		return errors.Wrap(err, "failed to get assets from the esi client")
 	}
 
+	assets, err = u.filterAssetsByLocationFlag(ctx, userID, assets)
+	if err != nil {
+		return err
+	}
+
 	err = u.characterAssetsRepository.UpdateAssets(ctx, char.ID, char.UserID, assets)
 	if err != nil {
 		return errors.Wrap(err, "failed to update assets in repository")
@@ -309,6 +350,11 @@ func (u *Assets) UpdateCorporationAssets(ctx context.Context, corp repositories.
 		return errors.Wrap(err, "failed to get corp assets")
 	}
 
+	assets, err = u.filterAssetsByLocationFlag(ctx, userID, assets)
+	if err != nil {
+		return err
+	}
+
 	err = u.playerCorporationAssetsRepository.Upsert(ctx, corp.ID, userID, assets)
 	if err != nil {
 		return errors.Wrap(err, "failed to upsert corp assets")
@@ -146,6 +146,15 @@ func (m *mockUserTimestampRepo) UpdateAssetsLastUpdated(ctx context.Context, use
 	return m.updateErr
 }
 
+type mockUserAssetSettingsRepo struct {
+	settings *models.AssetLocationFlagSettings
+	err      error
+}
+
+func (m *mockUserAssetSettingsRepo) GetAssetLocationFlags(ctx context.Context, userID int64) (*models.AssetLocationFlagSettings, error) {
+	return m.settings, m.err
+}
+
 type mockEsiClientForAssets struct {
 	charAssets      []*models.EveAsset
 	charAssetsErr   error
@@ -214,7 +223,7 @@ func Test_Assets_UpdateCharacterAssets_Success(t *testing.T) {
 		stationIDs: []int64{60003760},
 	}
 	esiClient := &mockEsiClientForAssets{
-		charAssets: []*models.EveAsset{{TypeID: 34, Quantity: 1000}},
+		charAssets: []*models.EveAsset{{TypeID: 34, Quantity: 1000, LocationFlag: "Hangar"}},
 		charNames:  map[int64]string{100: "Box A", 200: "Box B"},
 		stations:   []models.Station{{ID: 60003760, Name: "Jita"}},
 	}
@@ -247,6 +256,68 @@ func Test_Assets_UpdateCharacterAssets_Success(t *testing.T) {
 	assert.Len(t, charAssetsRepo.updatedAssets, 1)
 }
 
+func Test_Assets_UpdateCharacterAssets_KeepsShipContentsWithDefaultFlags(t *testing.T) {
+	charAssetsRepo := &mockCharacterAssetsRepo{}
+	esiClient := &mockEsiClientForAssets{
+		charAssets: []*models.EveAsset{
+			{ItemID: 1, TypeID: 587, Quantity: 1, IsSingleton: true, LocationFlag: "Hangar"},
+			{ItemID: 2, TypeID: 2873, Quantity: 1, LocationID: 1, LocationFlag: "HiSlot0"},
+			{ItemID: 3, TypeID: 2456, Quantity: 2, LocationID: 1, LocationFlag: "DroneBay"},
+			{ItemID: 4, TypeID: 9899, Quantity: 1, LocationFlag: "Implant"},
+		},
+	}
+
+	u := newTestUpdater(&mockCharacterRepo{}, charAssetsRepo, &mockAssetStationRepo{}, &mockPlayerCorpRepo{}, &mockCorpAssetsRepo{}, esiClient, &mockUserTimestampRepo{}, 5)
+
+	char := &repositories.Character{ID: 12345, UserID: 42, EsiTokenExpiresOn: time.Now().Add(time.Hour)}
+	err := u.UpdateCharacterAssets(context.Background(), char, 42)
+
+	assert.NoError(t, err)
+	assert.Len(t, charAssetsRepo.updatedAssets, 3)
+	for _, asset := range charAssetsRepo.updatedAssets {
+		assert.NotEqual(t, "Implant", asset.LocationFlag)
+	}
+}
+
+func Test_Assets_UpdateCharacterAssets_UsesUserLocationFlags(t *testing.T) {
+	charAssetsRepo := &mockCharacterAssetsRepo{}
+	esiClient := &mockEsiClientForAssets{
+		charAssets: []*models.EveAsset{
+			{ItemID: 1, TypeID: 587, Quantity: 1, IsSingleton: true, LocationFlag: "Hangar"},
+			{ItemID: 2, TypeID: 2873, Quantity: 1, LocationID: 1, LocationFlag: "HiSlot0"},
+		},
+	}
+
+	u := newTestUpdater(&mockCharacterRepo{}, charAssetsRepo, &mockAssetStationRepo{}, &mockPlayerCorpRepo{}, &mockCorpAssetsRepo{}, esiClient, &mockUserTimestampRepo{}, 5)
+	u.WithAssetSettingsRepository(&mockUserAssetSettingsRepo{
+		settings: &models.AssetLocationFlagSettings{Flags: []string{"Hangar"}},
+	})
+
+	char := &repositories.Character{ID: 12345, UserID: 42, EsiTokenExpiresOn: time.Now().Add(time.Hour)}
+	err := u.UpdateCharacterAssets(context.Background(), char, 42)
+
+	assert.NoError(t, err)
+	assert.Len(t, charAssetsRepo.updatedAssets, 1)
+	assert.Equal(t, int64(1), charAssetsRepo.updatedAssets[0].ItemID)
+}
+
+func Test_Assets_UpdateCharacterAssets_AssetSettingsError(t *testing.T) {
+	charAssetsRepo := &mockCharacterAssetsRepo{}
+	esiClient := &mockEsiClientForAssets{
+		charAssets: []*models.EveAsset{{ItemID: 1, TypeID: 34, Quantity: 1, LocationFlag: "Hangar"}},
+	}
+
+	u := newTestUpdater(&mockCharacterRepo{}, charAssetsRepo, &mockAssetStationRepo{}, &mockPlayerCorpRepo{}, &mockCorpAssetsRepo{}, esiClient, &mockUserTimestampRepo{}, 5)
+	u.WithAssetSettingsRepository(&mockUserAssetSettingsRepo{err: fmt.Errorf("db error")})
+
+	char := &repositories.Character{ID: 12345, UserID: 42, EsiTokenExpiresOn: time.Now().Add(time.Hour)}
+	err := u.UpdateCharacterAssets(context.Background(), char, 42)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get asset location flags")
+	assert.Nil(t, charAssetsRepo.updatedAssets)
+}
+
 func Test_Assets_UpdateCharacterAssets_RefreshesExpiredToken(t *testing.T) {
 	charAssetsRepo := &mockCharacterAssetsRepo{
 		containers: []int64{},
@@ -522,7 +593,7 @@ func Test_Assets_UpdateCorporationAssets_Success(t *testing.T) {
 		stationIDs: []int64{60003760},
 	}
 	esiClient := &mockEsiClientForAssets{
-		corpAssets: []*models.EveAsset{{TypeID: 35, Quantity: 500}},
+		corpAssets: []*models.EveAsset{{TypeID: 35, Quantity: 500, LocationFlag: "CorpSAG1"}},
 		corpNames:  map[int64]string{300: "Corp Box"},
 		stations:   []models.Station{{ID: 60003760, Name: "Jita"}},
 		divisions:  &models.CorporationDivisions{},
@@ -167,7 +167,7 @@ func (u *AutoBuy) syncConfig(ctx context.Context, config *models.AutoBuyConfig)
 		price, hasPrice := prices[deficit.TypeID]
 		basePrice := (*float64)(nil)
 		if hasPrice {
-			basePrice = resolveBasePrice(price, priceSource)
+			basePrice = resolveBasePrice(price, priceSource, nil)
 		}
 		if basePrice == nil || *basePrice <= 0 {
 			// No usable price — deactivate existing order if any
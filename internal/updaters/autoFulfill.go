@@ -15,9 +15,10 @@ type AutoFulfillBuyOrdersRepository interface {
 }
 
 type AutoFulfillForSaleRepository interface {
-	GetMatchingForSaleItems(ctx context.Context, typeID int64, minPrice, maxPrice float64, excludeUserID int64) ([]*models.ForSaleItem, error)
+	GetMatchingForSaleItems(ctx context.Context, typeID int64, minPrice, maxPrice float64, buyerUserID int64) ([]*models.ForSaleItem, error)
 	UpdateQuantity(ctx context.Context, tx *sql.Tx, itemID int64, newQuantity int64) error
 	GetByID(ctx context.Context, itemID int64) (*models.ForSaleItem, error)
+	GetPriceForBuyer(ctx context.Context, item *models.ForSaleItem, buyerUserID int64) (float64, error)
 }
 
 type AutoFulfillPurchaseRepository interface {
@@ -37,14 +38,25 @@ type AutoFulfillUsersRepository interface {
 	GetUserName(ctx context.Context, userID int64) (string, error)
 }
 
+type AutoFulfillReservationsRepository interface {
+	GetUnreservedQuantity(ctx context.Context, item *models.ForSaleItem) (int64, error)
+}
+
+type AutoFulfillTrustRepository interface {
+	GetSettings(ctx context.Context, userID int64) (*models.TradeTrustSettings, error)
+	GetStats(ctx context.Context, userIDs []int64) (map[int64]*models.TradeTrustStats, error)
+}
+
 type AutoFulfill struct {
-	db              *sql.DB
-	buyOrderRepo    AutoFulfillBuyOrdersRepository
-	forSaleRepo     AutoFulfillForSaleRepository
-	purchaseRepo    AutoFulfillPurchaseRepository
-	permissionsRepo AutoFulfillPermissionsRepository
-	usersRepo       AutoFulfillUsersRepository
-	notifier        AutoFulfillNotifier
+	db               *sql.DB
+	buyOrderRepo     AutoFulfillBuyOrdersRepository
+	forSaleRepo      AutoFulfillForSaleRepository
+	purchaseRepo     AutoFulfillPurchaseRepository
+	permissionsRepo  AutoFulfillPermissionsRepository
+	usersRepo        AutoFulfillUsersRepository
+	notifier         AutoFulfillNotifier
+	reservationsRepo AutoFulfillReservationsRepository
+	trustRepo        AutoFulfillTrustRepository
 }
 
 func NewAutoFulfill(
@@ -67,6 +79,18 @@ func NewAutoFulfill(
 	}
 }
 
+// WithReservationsRepository sets the optional repository of plan run material reservations.
+// Purchases never take stock reserved for a plan run.
+func (u *AutoFulfill) WithReservationsRepository(repo AutoFulfillReservationsRepository) {
+	u.reservationsRepo = repo
+}
+
+// WithTrustRepository sets the optional repository of trade trust scores. Buyers and sellers
+// are never matched with a counterparty scoring below their minimum.
+func (u *AutoFulfill) WithTrustRepository(repo AutoFulfillTrustRepository) {
+	u.trustRepo = repo
+}
+
 // SyncForUser matches buy orders for a specific user against available for-sale items
 func (u *AutoFulfill) SyncForUser(ctx context.Context, userID int64) error {
 	orders, err := u.buyOrderRepo.GetActiveBuyOrdersForUser(ctx, userID)
@@ -120,7 +144,7 @@ func (u *AutoFulfill) matchBuyOrder(ctx context.Context, order *models.BuyOrder)
 		return nil
 	}
 
-	// Find for-sale items matching type + price range, excluding buyer's own items
+	// Find for-sale items matching type + the buyer's price range, excluding buyer's own items
 	items, err := u.forSaleRepo.GetMatchingForSaleItems(ctx, order.TypeID, order.MinPricePerUnit, order.MaxPricePerUnit, order.BuyerUserID)
 	if err != nil {
 		return errors.Wrap(err, "failed to get matching for-sale items")
@@ -169,14 +193,56 @@ func (u *AutoFulfill) matchBuyOrder(ctx context.Context, order *models.BuyOrder)
 			continue
 		}
 
+		// Both sides must trust each other enough to trade without review
+		trusted, err := u.trustsEachOther(ctx, order.BuyerUserID, item.UserID)
+		if err != nil {
+			log.Error("failed to check trade trust",
+				"buyerID", order.BuyerUserID, "sellerID", item.UserID, "error", err)
+			continue
+		}
+		if !trusted {
+			log.Info("auto-fulfill skipped counterparty below trust threshold",
+				"orderID", order.ID, "itemID", item.ID,
+				"buyerID", order.BuyerUserID, "sellerID", item.UserID)
+			continue
+		}
+
+		// Members of the seller's contact groups pay the group's price. The query already matched
+		// on it; re-read it in case the group price changed since
+		pricePerUnit, err := u.forSaleRepo.GetPriceForBuyer(ctx, item, order.BuyerUserID)
+		if err != nil {
+			log.Error("failed to get buyer price",
+				"itemID", item.ID, "buyerID", order.BuyerUserID, "error", err)
+			continue
+		}
+		if pricePerUnit > order.MaxPricePerUnit {
+			continue
+		}
+
 		// Compute quantity to purchase
 		quantity := remainingQuantity
 		if quantity > item.QuantityAvailable {
 			quantity = item.QuantityAvailable
 		}
 
+		// Never sell stock the seller has reserved for a plan run
+		if u.reservationsRepo != nil {
+			unreserved, err := u.reservationsRepo.GetUnreservedQuantity(ctx, item)
+			if err != nil {
+				log.Error("failed to get unreserved quantity",
+					"itemID", item.ID, "error", err)
+				continue
+			}
+			if quantity > unreserved {
+				quantity = unreserved
+			}
+			if quantity <= 0 {
+				continue
+			}
+		}
+
 		// Create the purchase atomically
-		err = u.createAutoFulfillPurchase(ctx, order, item, quantity)
+		err = u.createAutoFulfillPurchase(ctx, order, item, quantity, pricePerUnit)
 		if err != nil {
 			// Unique constraint violation means duplicate — skip silently
 			log.Error("failed to create auto-fulfill purchase",
@@ -190,8 +256,41 @@ func (u *AutoFulfill) matchBuyOrder(ctx context.Context, order *models.BuyOrder)
 	return nil
 }
 
+// trustsEachOther reports whether neither side has a minimum trust score the other falls below.
+// Counterparties without a score yet are not held back.
+func (u *AutoFulfill) trustsEachOther(ctx context.Context, buyerUserID, sellerUserID int64) (bool, error) {
+	if u.trustRepo == nil {
+		return true, nil
+	}
+
+	var stats map[int64]*models.TradeTrustStats
+	for _, pair := range [][2]int64{{buyerUserID, sellerUserID}, {sellerUserID, buyerUserID}} {
+		settings, err := u.trustRepo.GetSettings(ctx, pair[0])
+		if err != nil {
+			return false, errors.Wrap(err, "failed to get trust settings")
+		}
+		if settings.MinAutoFulfillScore == nil {
+			continue
+		}
+
+		if stats == nil {
+			stats, err = u.trustRepo.GetStats(ctx, []int64{buyerUserID, sellerUserID})
+			if err != nil {
+				return false, errors.Wrap(err, "failed to get trust stats")
+			}
+		}
+
+		counterparty := stats[pair[1]]
+		if counterparty != nil && counterparty.TrustScore != nil && *counterparty.TrustScore < *settings.MinAutoFulfillScore {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 // createAutoFulfillPurchase atomically creates a purchase transaction and reduces for-sale quantity
-func (u *AutoFulfill) createAutoFulfillPurchase(ctx context.Context, order *models.BuyOrder, item *models.ForSaleItem, quantity int64) error {
+func (u *AutoFulfill) createAutoFulfillPurchase(ctx context.Context, order *models.BuyOrder, item *models.ForSaleItem, quantity int64, pricePerUnit float64) error {
 	tx, err := u.db.BeginTx(ctx, nil)
 	if err != nil {
 		return errors.Wrap(err, "failed to begin transaction")
@@ -212,11 +311,12 @@ func (u *AutoFulfill) createAutoFulfillPurchase(ctx context.Context, order *mode
 		SellerUserID:      item.UserID,
 		TypeID:            order.TypeID,
 		QuantityPurchased: quantity,
-		PricePerUnit:      item.PricePerUnit,
-		TotalPrice:        float64(quantity) * item.PricePerUnit,
+		PricePerUnit:      pricePerUnit,
+		TotalPrice:        float64(quantity) * pricePerUnit,
 		Status:            "pending",
 		BuyOrderID:        &order.ID,
 		IsAutoFulfilled:   true,
+		Blueprint:         item.Blueprint,
 	}
 
 	err = u.purchaseRepo.CreateAutoFulfill(ctx, tx, purchase)
@@ -258,7 +358,7 @@ func (u *AutoFulfill) createAutoFulfillPurchase(ctx context.Context, order *mode
 		"forSaleItemID", item.ID,
 		"typeID", order.TypeID,
 		"quantity", quantity,
-		"pricePerUnit", item.PricePerUnit,
+		"pricePerUnit", pricePerUnit,
 		"buyerID", order.BuyerUserID,
 		"sellerID", item.UserID)
 
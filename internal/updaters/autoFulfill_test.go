@@ -35,6 +35,7 @@ type mockAutoFulfillForSaleRepo struct {
 	updatedItems  map[int64]int64 // itemID -> newQuantity
 	updateErr     error
 	byID          map[int64]*models.ForSaleItem
+	groupPrices   map[int64]float64 // itemID -> buyer's contact group price
 }
 
 func (m *mockAutoFulfillForSaleRepo) GetMatchingForSaleItems(ctx context.Context, typeID int64, minPrice, maxPrice float64, excludeUserID int64) ([]*models.ForSaleItem, error) {
@@ -56,6 +57,13 @@ func (m *mockAutoFulfillForSaleRepo) GetByID(ctx context.Context, itemID int64)
 	return nil, fmt.Errorf("not found")
 }
 
+func (m *mockAutoFulfillForSaleRepo) GetPriceForBuyer(ctx context.Context, item *models.ForSaleItem, buyerUserID int64) (float64, error) {
+	if price, ok := m.groupPrices[item.ID]; ok {
+		return price, nil
+	}
+	return item.PricePerUnit, nil
+}
+
 type mockAutoFulfillPurchaseRepo struct {
 	createdPurchases  []*models.PurchaseTransaction
 	createErr         error
@@ -402,6 +410,180 @@ func Test_AutoFulfill_PurchaseCappedByAvailableQuantity(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func Test_AutoFulfill_UsesBuyerGroupPrice(t *testing.T) {
+	buyOrderRepo := &mockAutoFulfillBuyOrdersRepo{
+		userOrders: []*models.BuyOrder{
+			{ID: 1, BuyerUserID: 42, TypeID: 34, QuantityDesired: 100, MinPricePerUnit: 5.0, MaxPricePerUnit: 10.0, IsActive: true},
+		},
+	}
+	forSaleRepo := &mockAutoFulfillForSaleRepo{
+		matchingItems: []*models.ForSaleItem{
+			{ID: 1, UserID: 99, TypeID: 34, QuantityAvailable: 500, PricePerUnit: 8.0, IsActive: true},
+		},
+		groupPrices: map[int64]float64{1: 6.5},
+	}
+	purchaseRepo := &mockAutoFulfillPurchaseRepo{pendingByBuyOrder: map[int64]int64{}}
+	permRepo := &mockAutoFulfillPermissionsRepo{allowed: true}
+
+	u, mock := newAutoFulfillUpdaterWithDB(t, buyOrderRepo, forSaleRepo, purchaseRepo, permRepo)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	err := u.SyncForUser(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.Len(t, purchaseRepo.createdPurchases, 1)
+	assert.Equal(t, 6.5, purchaseRepo.createdPurchases[0].PricePerUnit)
+	assert.Equal(t, 650.0, purchaseRepo.createdPurchases[0].TotalPrice)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_AutoFulfill_SkipsGroupPriceAboveMax(t *testing.T) {
+	buyOrderRepo := &mockAutoFulfillBuyOrdersRepo{
+		userOrders: []*models.BuyOrder{
+			{ID: 1, BuyerUserID: 42, TypeID: 34, QuantityDesired: 100, MinPricePerUnit: 5.0, MaxPricePerUnit: 10.0, IsActive: true},
+		},
+	}
+	forSaleRepo := &mockAutoFulfillForSaleRepo{
+		matchingItems: []*models.ForSaleItem{
+			{ID: 1, UserID: 99, TypeID: 34, QuantityAvailable: 500, PricePerUnit: 8.0, IsActive: true},
+		},
+		groupPrices: map[int64]float64{1: 12.0},
+	}
+	purchaseRepo := &mockAutoFulfillPurchaseRepo{pendingByBuyOrder: map[int64]int64{}}
+	permRepo := &mockAutoFulfillPermissionsRepo{allowed: true}
+
+	u := newAutoFulfillUpdaterNoDB(buyOrderRepo, forSaleRepo, purchaseRepo, permRepo)
+
+	err := u.SyncForUser(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.Empty(t, purchaseRepo.createdPurchases)
+}
+
+type mockAutoFulfillReservationsRepo struct {
+	unreserved map[int64]int64
+}
+
+func (m *mockAutoFulfillReservationsRepo) GetUnreservedQuantity(ctx context.Context, item *models.ForSaleItem) (int64, error) {
+	return m.unreserved[item.ID], nil
+}
+
+func Test_AutoFulfill_PurchaseCappedByReservations(t *testing.T) {
+	orderID := int64(1)
+	buyOrderRepo := &mockAutoFulfillBuyOrdersRepo{
+		userOrders: []*models.BuyOrder{
+			{ID: orderID, BuyerUserID: 42, TypeID: 34, QuantityDesired: 1000, MinPricePerUnit: 5.0, MaxPricePerUnit: 10.0, IsActive: true},
+		},
+	}
+	forSaleRepo := &mockAutoFulfillForSaleRepo{
+		matchingItems: []*models.ForSaleItem{
+			{ID: 1, UserID: 99, TypeID: 34, QuantityAvailable: 500, PricePerUnit: 8.0, IsActive: true},
+			{ID: 2, UserID: 99, TypeID: 34, QuantityAvailable: 500, PricePerUnit: 9.0, IsActive: true},
+		},
+	}
+	purchaseRepo := &mockAutoFulfillPurchaseRepo{pendingByBuyOrder: map[int64]int64{}}
+	permRepo := &mockAutoFulfillPermissionsRepo{allowed: true}
+
+	u, mock := newAutoFulfillUpdaterWithDB(t, buyOrderRepo, forSaleRepo, purchaseRepo, permRepo)
+	u.WithReservationsRepository(&mockAutoFulfillReservationsRepo{
+		unreserved: map[int64]int64{
+			1: 200, // 300 of the listing is reserved for a plan run
+			2: 0,   // everything reserved
+		},
+	})
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	err := u.SyncForUser(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.Len(t, purchaseRepo.createdPurchases, 1)
+	assert.Equal(t, int64(200), purchaseRepo.createdPurchases[0].QuantityPurchased)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+type mockAutoFulfillTrustRepo struct {
+	minScores map[int64]int
+	scores    map[int64]int
+}
+
+func (m *mockAutoFulfillTrustRepo) GetSettings(ctx context.Context, userID int64) (*models.TradeTrustSettings, error) {
+	settings := &models.TradeTrustSettings{UserID: userID}
+	if min, ok := m.minScores[userID]; ok {
+		settings.MinAutoFulfillScore = &min
+	}
+	return settings, nil
+}
+
+func (m *mockAutoFulfillTrustRepo) GetStats(ctx context.Context, userIDs []int64) (map[int64]*models.TradeTrustStats, error) {
+	stats := map[int64]*models.TradeTrustStats{}
+	for _, userID := range userIDs {
+		stats[userID] = &models.TradeTrustStats{UserID: userID}
+		if score, ok := m.scores[userID]; ok {
+			stats[userID].TrustScore = &score
+		}
+	}
+	return stats, nil
+}
+
+func Test_AutoFulfill_SkipsSellerBelowBuyerTrustThreshold(t *testing.T) {
+	buyOrderRepo := &mockAutoFulfillBuyOrdersRepo{
+		userOrders: []*models.BuyOrder{
+			{ID: 1, BuyerUserID: 42, TypeID: 34, QuantityDesired: 1000, MinPricePerUnit: 5.0, MaxPricePerUnit: 10.0, IsActive: true},
+		},
+	}
+	forSaleRepo := &mockAutoFulfillForSaleRepo{
+		matchingItems: []*models.ForSaleItem{
+			{ID: 1, UserID: 99, TypeID: 34, QuantityAvailable: 500, PricePerUnit: 8.0, IsActive: true},
+			{ID: 2, UserID: 77, TypeID: 34, QuantityAvailable: 500, PricePerUnit: 9.0, IsActive: true},
+		},
+	}
+	purchaseRepo := &mockAutoFulfillPurchaseRepo{pendingByBuyOrder: map[int64]int64{}}
+	permRepo := &mockAutoFulfillPermissionsRepo{allowed: true}
+
+	u, mock := newAutoFulfillUpdaterWithDB(t, buyOrderRepo, forSaleRepo, purchaseRepo, permRepo)
+	u.WithTrustRepository(&mockAutoFulfillTrustRepo{
+		minScores: map[int64]int{42: 80},
+		scores:    map[int64]int{99: 60}, // 77 has no trade history yet
+	})
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	err := u.SyncForUser(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.Len(t, purchaseRepo.createdPurchases, 1)
+	assert.Equal(t, int64(77), purchaseRepo.createdPurchases[0].SellerUserID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_AutoFulfill_SkipsBuyerBelowSellerTrustThreshold(t *testing.T) {
+	buyOrderRepo := &mockAutoFulfillBuyOrdersRepo{
+		userOrders: []*models.BuyOrder{
+			{ID: 1, BuyerUserID: 42, TypeID: 34, QuantityDesired: 1000, MinPricePerUnit: 5.0, MaxPricePerUnit: 10.0, IsActive: true},
+		},
+	}
+	forSaleRepo := &mockAutoFulfillForSaleRepo{
+		matchingItems: []*models.ForSaleItem{
+			{ID: 1, UserID: 99, TypeID: 34, QuantityAvailable: 500, PricePerUnit: 8.0, IsActive: true},
+		},
+	}
+	purchaseRepo := &mockAutoFulfillPurchaseRepo{pendingByBuyOrder: map[int64]int64{}}
+	permRepo := &mockAutoFulfillPermissionsRepo{allowed: true}
+
+	u := newAutoFulfillUpdaterNoDB(buyOrderRepo, forSaleRepo, purchaseRepo, permRepo)
+	u.WithTrustRepository(&mockAutoFulfillTrustRepo{
+		minScores: map[int64]int{99: 50},
+		scores:    map[int64]int{42: 49},
+	})
+
+	err := u.SyncForUser(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.Empty(t, purchaseRepo.createdPurchases)
+}
+
 // --- SyncForAllUsers Tests ---
 
 func Test_AutoFulfill_SyncForAllUsers_NoOrders(t *testing.T) {
@@ -288,6 +288,7 @@ func (u *AutoProductionUpdater) processGroup(ctx context.Context, group *autoPro
 				EstimatedCost:     estimatedCost,
 				EstimatedDuration: &aj.DurationSec,
 				Notes:             &entryNote,
+				Reservations:      services.BuildMaterialReservations(orig, aj.Runs),
 			}
 
 			_, err := u.queueRepo.Create(ctx, newEntry)
@@ -301,6 +302,7 @@ func (u *AutoProductionUpdater) processGroup(ctx context.Context, group *autoPro
 			pj.Entry.UserID = group.userID
 			pj.Entry.Notes = &note
 			pj.Entry.PlanRunID = &run.ID
+			pj.Entry.Reservations = services.BuildMaterialReservations(pj, pj.Entry.Runs)
 
 			_, err := u.queueRepo.Create(ctx, pj.Entry)
 			if err != nil {
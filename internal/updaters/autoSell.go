@@ -3,6 +3,7 @@ package updaters
 import (
 	"context"
 
+	"github.com/annymsMthd/industry-tool/internal/calculator"
 	log "github.com/annymsMthd/industry-tool/internal/logging"
 	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/pkg/errors"
@@ -33,12 +34,22 @@ type AutoSellPurchaseRepository interface {
 	GetPendingQuantitiesForSaleContext(ctx context.Context, sellerUserID int64, ownerType string, ownerID, locationID int64, containerID *int64, divisionNumber *int) (map[int64]int64, error)
 }
 
+type AutoSellReservationsRepository interface {
+	GetReservedQuantitiesForContext(ctx context.Context, userID int64, ownerType string, ownerID, locationID int64, containerID *int64, divisionNumber *int) (map[int64]int64, error)
+}
+
+type AutoSellTradeSkillsRepository interface {
+	GetSkillsForUser(ctx context.Context, userID int64) ([]*models.CharacterSkill, error)
+}
+
 type AutoSell struct {
-	autoSellRepo  AutoSellContainersRepository
-	forSaleRepo   AutoSellForSaleRepository
-	marketRepo    AutoSellMarketPricesRepository
-	stockpileRepo AutoSellStockpileRepository
-	purchaseRepo  AutoSellPurchaseRepository
+	autoSellRepo     AutoSellContainersRepository
+	forSaleRepo      AutoSellForSaleRepository
+	marketRepo       AutoSellMarketPricesRepository
+	stockpileRepo    AutoSellStockpileRepository
+	purchaseRepo     AutoSellPurchaseRepository
+	reservationsRepo AutoSellReservationsRepository
+	tradeSkillsRepo  AutoSellTradeSkillsRepository
 }
 
 func NewAutoSell(
@@ -57,6 +68,18 @@ func NewAutoSell(
 	}
 }
 
+// WithReservationsRepository sets the optional repository of plan run material reservations.
+// Reserved quantities are never listed.
+func (u *AutoSell) WithReservationsRepository(repo AutoSellReservationsRepository) {
+	u.reservationsRepo = repo
+}
+
+// WithTradeSkillsRepository sets the optional repository of character skills used by the
+// "jita_sell_net" price source. Without it the base sales tax and broker fee apply.
+func (u *AutoSell) WithTradeSkillsRepository(repo AutoSellTradeSkillsRepository) {
+	u.tradeSkillsRepo = repo
+}
+
 // SyncForUser syncs auto-sell listings for a specific user after asset refresh
 func (u *AutoSell) SyncForUser(ctx context.Context, userID int64) error {
 	containers, err := u.autoSellRepo.GetByUser(ctx, userID)
@@ -112,10 +135,18 @@ func (u *AutoSell) SyncForAllUsers(ctx context.Context) error {
 }
 
 // resolveBasePrice returns the appropriate base price for the given price source.
-func resolveBasePrice(price *models.MarketPrice, priceSource string) *float64 {
+// "jita_sell_net" is what the seller would keep from a Jita sell order after fees.
+func resolveBasePrice(price *models.MarketPrice, priceSource string, fees *models.TradeFees) *float64 {
 	switch priceSource {
 	case "jita_sell":
 		return price.SellPrice
+	case "jita_sell_net":
+		if price.SellPrice == nil || fees == nil {
+			return nil
+		}
+		salesTax, brokerFee := calculator.SellOrderFees(*price.SellPrice, fees)
+		net := *price.SellPrice - salesTax - brokerFee
+		return &net
 	case "jita_split":
 		if price.BuyPrice != nil && price.SellPrice != nil {
 			split := (*price.BuyPrice + *price.SellPrice) / 2.0
@@ -162,6 +193,18 @@ func (u *AutoSell) syncContainer(ctx context.Context, container *models.AutoSell
 		return errors.Wrap(err, "failed to get pending purchase quantities")
 	}
 
+	// Get materials reserved for plan runs so their inputs are never sold
+	reservedQuantities := map[int64]int64{}
+	if u.reservationsRepo != nil {
+		reservedQuantities, err = u.reservationsRepo.GetReservedQuantitiesForContext(
+			ctx, container.UserID, container.OwnerType, container.OwnerID,
+			container.LocationID, container.ContainerID, container.DivisionNumber,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to get reserved quantities")
+		}
+	}
+
 	// Collect type IDs for market price lookup
 	typeIDs := make([]int64, 0, len(items))
 	for _, item := range items {
@@ -177,6 +220,11 @@ func (u *AutoSell) syncContainer(ctx context.Context, container *models.AutoSell
 		}
 	}
 
+	fees, err := u.sellerTradeFees(ctx, container)
+	if err != nil {
+		return errors.Wrap(err, "failed to get trade fees")
+	}
+
 	// Get existing auto-sell listings for this container
 	existingListings, err := u.forSaleRepo.GetActiveAutoSellListings(ctx, container.ID)
 	if err != nil {
@@ -194,7 +242,7 @@ func (u *AutoSell) syncContainer(ctx context.Context, container *models.AutoSell
 
 	// For each item in the container, upsert a for-sale listing
 	for _, item := range items {
-		// Compute sellable quantity: subtract stockpile reservation and pending purchases
+		// Compute sellable quantity: subtract stockpile reservation, plan run reservations and pending purchases
 		sellableQuantity := item.Quantity
 		if marker, ok := stockpileMarkers[item.TypeID]; ok {
 			sellableQuantity = item.Quantity - marker.DesiredQuantity
@@ -202,6 +250,7 @@ func (u *AutoSell) syncContainer(ctx context.Context, container *models.AutoSell
 		if pending, ok := pendingQuantities[item.TypeID]; ok {
 			sellableQuantity -= pending
 		}
+		sellableQuantity -= reservedQuantities[item.TypeID]
 		if sellableQuantity <= 0 {
 			// Entire quantity reserved by stockpile — deactivate existing listing if any
 			if existing, ok := existingByType[item.TypeID]; ok {
@@ -217,7 +266,7 @@ func (u *AutoSell) syncContainer(ctx context.Context, container *models.AutoSell
 		price, hasPrice := prices[item.TypeID]
 		basePrice := (*float64)(nil)
 		if hasPrice {
-			basePrice = resolveBasePrice(price, container.PriceSource)
+			basePrice = resolveBasePrice(price, container.PriceSource, fees)
 		}
 		if basePrice == nil || *basePrice <= 0 {
 			// No usable price — deactivate existing listing if any
@@ -267,3 +316,21 @@ func (u *AutoSell) syncContainer(ctx context.Context, container *models.AutoSell
 
 	return nil
 }
+
+// sellerTradeFees returns the Jita sell-order fees of the container's owning character, or
+// of the user's character with the lowest fees for corporation containers.
+func (u *AutoSell) sellerTradeFees(ctx context.Context, container *models.AutoSellContainer) (*models.TradeFees, error) {
+	if container.PriceSource != "jita_sell_net" || u.tradeSkillsRepo == nil {
+		return calculator.ComputeTradeFees(map[int64]int{}, nil, nil), nil
+	}
+
+	skills, err := u.tradeSkillsRepo.GetSkillsForUser(ctx, container.UserID)
+	if err != nil {
+		return nil, err
+	}
+	byCharacter := calculator.TradeSkillsByCharacter(skills)
+	if container.OwnerType == "character" {
+		return calculator.ComputeTradeFees(byCharacter[container.OwnerID], nil, nil), nil
+	}
+	return calculator.BestTradeFees(byCharacter, nil, nil), nil
+}
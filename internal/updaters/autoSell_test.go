@@ -1337,3 +1337,162 @@ func Test_AutoSell_PurchaseRepoError_FailsContainer(t *testing.T) {
 	assert.NoError(t, err) // SyncForUser logs per-container errors but returns nil
 	assert.Len(t, forSaleRepo.upsertedItems, 0)
 }
+
+type mockAutoSellReservationsRepo struct {
+	reserved map[int64]int64
+}
+
+func (m *mockAutoSellReservationsRepo) GetReservedQuantitiesForContext(ctx context.Context, userID int64, ownerType string, ownerID, locationID int64, containerID *int64, divisionNumber *int) (map[int64]int64, error) {
+	return m.reserved, nil
+}
+
+func Test_AutoSell_PlanRunReservationsReduceQuantity(t *testing.T) {
+	buyPrice := 100.0
+
+	autoSellRepo := &mockAutoSellContainersRepo{
+		byUserContainers: []*models.AutoSellContainer{
+			{
+				ID:              1,
+				UserID:          42,
+				OwnerType:       "character",
+				OwnerID:         12345,
+				LocationID:      60003760,
+				ContainerID:     int64Ptr(9000),
+				PricePercentage: 90.0,
+				PriceSource:     "jita_buy",
+			},
+		},
+		containerItems: []*models.ContainerItem{
+			{TypeID: 34, Quantity: 1000},
+			{TypeID: 35, Quantity: 500},
+		},
+	}
+
+	forSaleRepo := &mockAutoSellForSaleRepo{
+		activeListings: []*models.ForSaleItem{},
+	}
+
+	marketRepo := &mockAutoSellMarketRepo{
+		prices: map[int64]*models.MarketPrice{
+			34: {TypeID: 34, RegionID: 10000002, BuyPrice: &buyPrice},
+			35: {TypeID: 35, RegionID: 10000002, BuyPrice: &buyPrice},
+		},
+	}
+
+	stockpileRepo := &mockAutoSellStockpileRepo{
+		markers: map[int64]*models.StockpileMarker{
+			34: {TypeID: 34, DesiredQuantity: 100},
+		},
+	}
+
+	u := newAutoSellUpdaterWithStockpile(autoSellRepo, forSaleRepo, marketRepo, stockpileRepo)
+	u.WithReservationsRepository(&mockAutoSellReservationsRepo{
+		reserved: map[int64]int64{
+			34: 600, // earmarked for a plan run
+			35: 500, // everything earmarked
+		},
+	})
+	err := u.SyncForUser(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.Len(t, forSaleRepo.upsertedItems, 1)
+	// 1000 - 100 (stockpile) - 600 (reserved) = 300
+	assert.Equal(t, int64(34), forSaleRepo.upsertedItems[0].TypeID)
+	assert.Equal(t, int64(300), forSaleRepo.upsertedItems[0].QuantityAvailable)
+}
+
+type mockAutoSellTradeSkillsRepo struct {
+	skills []*models.CharacterSkill
+}
+
+func (m *mockAutoSellTradeSkillsRepo) GetSkillsForUser(ctx context.Context, userID int64) ([]*models.CharacterSkill, error) {
+	return m.skills, nil
+}
+
+func Test_AutoSell_JitaSellNetPricing_OwnerSkills(t *testing.T) {
+	sellPrice := 100.0
+
+	autoSellRepo := &mockAutoSellContainersRepo{
+		byUserContainers: []*models.AutoSellContainer{
+			{
+				ID:              1,
+				UserID:          42,
+				OwnerType:       "character",
+				OwnerID:         12345,
+				LocationID:      60003760,
+				ContainerID:     int64Ptr(9000),
+				PricePercentage: 90.0,
+				PriceSource:     "jita_sell_net",
+			},
+		},
+		containerItems: []*models.ContainerItem{
+			{TypeID: 34, Quantity: 1000},
+		},
+	}
+
+	forSaleRepo := &mockAutoSellForSaleRepo{
+		activeListings: []*models.ForSaleItem{},
+	}
+
+	marketRepo := &mockAutoSellMarketRepo{
+		prices: map[int64]*models.MarketPrice{
+			34: {TypeID: 34, RegionID: 10000002, SellPrice: &sellPrice},
+		},
+	}
+
+	u := newAutoSellUpdater(autoSellRepo, forSaleRepo, marketRepo)
+	u.WithTradeSkillsRepository(&mockAutoSellTradeSkillsRepo{
+		skills: []*models.CharacterSkill{
+			{CharacterID: 12345, SkillID: 16622, ActiveLevel: 5},
+			{CharacterID: 12345, SkillID: 3446, ActiveLevel: 5},
+			// Another character's skills must not be used
+			{CharacterID: 99999, SkillID: 16622, ActiveLevel: 0},
+		},
+	})
+	err := u.SyncForUser(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.Len(t, forSaleRepo.upsertedItems, 1)
+	// 100 - 3.375 (tax) - 1.5 (broker) = 95.125, * 90 / 100
+	assert.InDelta(t, 85.6125, forSaleRepo.upsertedItems[0].PricePerUnit, 0.0001)
+}
+
+func Test_AutoSell_JitaSellNetPricing_BaseFeesWithoutSkills(t *testing.T) {
+	sellPrice := 100.0
+
+	autoSellRepo := &mockAutoSellContainersRepo{
+		byUserContainers: []*models.AutoSellContainer{
+			{
+				ID:              1,
+				UserID:          42,
+				OwnerType:       "character",
+				OwnerID:         12345,
+				LocationID:      60003760,
+				ContainerID:     int64Ptr(9000),
+				PricePercentage: 100.0,
+				PriceSource:     "jita_sell_net",
+			},
+		},
+		containerItems: []*models.ContainerItem{
+			{TypeID: 34, Quantity: 1000},
+		},
+	}
+
+	forSaleRepo := &mockAutoSellForSaleRepo{
+		activeListings: []*models.ForSaleItem{},
+	}
+
+	marketRepo := &mockAutoSellMarketRepo{
+		prices: map[int64]*models.MarketPrice{
+			34: {TypeID: 34, RegionID: 10000002, SellPrice: &sellPrice},
+		},
+	}
+
+	u := newAutoSellUpdater(autoSellRepo, forSaleRepo, marketRepo)
+	err := u.SyncForUser(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.Len(t, forSaleRepo.upsertedItems, 1)
+	// 100 - 7.5 (tax) - 3 (broker)
+	assert.InDelta(t, 89.5, forSaleRepo.upsertedItems[0].PricePerUnit, 0.0001)
+}
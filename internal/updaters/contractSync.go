@@ -2,6 +2,10 @@ package updaters
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -13,9 +17,15 @@ import (
 	"github.com/pkg/errors"
 )
 
+// contractKeyPattern splits a contract title into whole tokens so a key only matches itself:
+// a contract titled "PO-12" never matches the key "PO-1".
+var contractKeyPattern = regexp.MustCompile(`[\w-]+`)
+
 type ContractSyncPurchaseRepository interface {
 	GetContractCreatedWithKeys(ctx context.Context) ([]*models.PurchaseTransaction, error)
 	CompleteWithContractID(ctx context.Context, purchaseID int64, eveContractID int64) error
+	CompleteOrderWithContractID(ctx context.Context, orderID int64, eveContractID int64) (int64, error)
+	DisputeWithContractID(ctx context.Context, purchaseIDs []int64, eveContractID int64, reason string) error
 }
 
 type ContractSyncCharacterRepository interface {
@@ -31,14 +41,35 @@ type ContractSyncCorporationRepository interface {
 type ContractSyncEsiClient interface {
 	GetCharacterContracts(ctx context.Context, characterID int64, token, refresh string, expire time.Time) ([]*client.EsiContract, error)
 	GetCorporationContracts(ctx context.Context, corporationID int64, token, refresh string, expire time.Time) ([]*client.EsiContract, error)
+	GetCharacterContractItems(ctx context.Context, characterID, contractID int64, token string) ([]*client.EsiContractItem, error)
+	GetCorporationContractItems(ctx context.Context, corporationID, contractID int64, token string) ([]*client.EsiContractItem, error)
 	RefreshAccessToken(ctx context.Context, refreshToken string) (*client.RefreshedToken, error)
 }
 
+// ContractDisputeNotifier is notified when a matched contract does not deliver its purchases
+type ContractDisputeNotifier interface {
+	NotifyContractDisputed(ctx context.Context, dispute *ContractDispute)
+}
+
+// ContractDispute describes a finished contract that did not match the purchases it was made for
+type ContractDispute struct {
+	ContractKey   string
+	EveContractID int64
+	BuyerUserID   int64
+	SellerUserID  int64
+	Purchases     []*models.PurchaseTransaction
+	Reasons       []string
+}
+
+// contractItemsFetcher loads the items of a contract with the token it was listed under
+type contractItemsFetcher func(ctx context.Context, contractID int64) ([]*client.EsiContractItem, error)
+
 type ContractSync struct {
-	purchaseRepo  ContractSyncPurchaseRepository
-	characterRepo ContractSyncCharacterRepository
-	corpRepo      ContractSyncCorporationRepository
-	esiClient     ContractSyncEsiClient
+	purchaseRepo    ContractSyncPurchaseRepository
+	characterRepo   ContractSyncCharacterRepository
+	corpRepo        ContractSyncCorporationRepository
+	esiClient       ContractSyncEsiClient
+	disputeNotifier ContractDisputeNotifier
 }
 
 func NewContractSync(
@@ -55,7 +86,14 @@ func NewContractSync(
 	}
 }
 
-// SyncAll checks ESI contracts for all buyers with contract_created purchases and auto-completes matches.
+// WithDisputeNotifier sets the optional notifier told about disputed contracts.
+func (u *ContractSync) WithDisputeNotifier(notifier ContractDisputeNotifier) {
+	u.disputeNotifier = notifier
+}
+
+// SyncAll checks ESI contracts for all buyers with contract_created purchases. Matching contracts
+// that deliver the purchased items at the agreed price to the buyer complete them; any other
+// match disputes them.
 func (u *ContractSync) SyncAll(ctx context.Context) error {
 	purchases, err := u.purchaseRepo.GetContractCreatedWithKeys(ctx)
 	if err != nil {
@@ -66,26 +104,25 @@ func (u *ContractSync) SyncAll(ctx context.Context) error {
 		return nil
 	}
 
-	// Group purchases by buyer_user_id and build contract_key lookup
+	// Group purchases by buyer_user_id
 	buyerPurchases := make(map[int64][]*models.PurchaseTransaction)
-	keyToPurchases := make(map[string][]*models.PurchaseTransaction)
 	for _, p := range purchases {
 		buyerPurchases[p.BuyerUserID] = append(buyerPurchases[p.BuyerUserID], p)
-		if p.ContractKey != nil {
-			keyToPurchases[*p.ContractKey] = append(keyToPurchases[*p.ContractKey], p)
-		}
-	}
-
-	// Collect all known contract keys for matching
-	allKeys := make([]string, 0, len(keyToPurchases))
-	for key := range keyToPurchases {
-		allKeys = append(allKeys, key)
 	}
 
 	log.Info("contract sync: checking purchases", "pendingCount", len(purchases), "buyerCount", len(buyerPurchases))
 
-	for buyerUserID := range buyerPurchases {
-		if err := u.syncBuyer(ctx, buyerUserID, allKeys, keyToPurchases); err != nil {
+	for buyerUserID, pending := range buyerPurchases {
+		// Only the buyer's own keys are looked up in the buyer's contracts, so a contract naming
+		// another buyer's key can neither complete nor dispute that buyer's purchases
+		keyToPurchases := make(map[string][]*models.PurchaseTransaction)
+		for _, p := range pending {
+			if p.ContractKey != nil {
+				keyToPurchases[*p.ContractKey] = append(keyToPurchases[*p.ContractKey], p)
+			}
+		}
+
+		if err := u.syncBuyer(ctx, buyerUserID, keyToPurchases); err != nil {
 			log.Error("contract sync: failed for buyer", "buyerUserID", buyerUserID, "error", err)
 		}
 	}
@@ -93,35 +130,44 @@ func (u *ContractSync) SyncAll(ctx context.Context) error {
 	return nil
 }
 
-func (u *ContractSync) syncBuyer(ctx context.Context, buyerUserID int64, allKeys []string, keyToPurchases map[string][]*models.PurchaseTransaction) error {
+func (u *ContractSync) syncBuyer(ctx context.Context, buyerUserID int64, keyToPurchases map[string][]*models.PurchaseTransaction) error {
 	characters, err := u.characterRepo.GetAll(ctx, buyerUserID)
 	if err != nil {
 		return errors.Wrap(err, "failed to get buyer characters")
 	}
 
+	corps, err := u.corpRepo.Get(ctx, buyerUserID)
+	if err != nil {
+		log.Error("contract sync: failed to get buyer corporations", "buyerUserID", buyerUserID, "error", err)
+		corps = nil
+	}
+
+	// A contract delivers to the buyer when it is assigned to any of their characters or corporations
+	buyerIDs := map[int64]bool{}
+	for _, char := range characters {
+		buyerIDs[char.ID] = true
+	}
+	for _, corp := range corps {
+		buyerIDs[corp.ID] = true
+	}
+
 	for _, char := range characters {
 		if !strings.Contains(char.EsiScopes, "esi-contracts.read_character_contracts.v1") {
 			continue
 		}
 
-		if err := u.syncCharacterContracts(ctx, char, buyerUserID, allKeys, keyToPurchases); err != nil {
+		if err := u.syncCharacterContracts(ctx, char, buyerUserID, buyerIDs, keyToPurchases); err != nil {
 			log.Error("contract sync: failed for character",
 				"characterID", char.ID, "buyerUserID", buyerUserID, "error", err)
 		}
 	}
 
-	corps, err := u.corpRepo.Get(ctx, buyerUserID)
-	if err != nil {
-		log.Error("contract sync: failed to get buyer corporations", "buyerUserID", buyerUserID, "error", err)
-		return nil
-	}
-
 	for i := range corps {
 		if !strings.Contains(corps[i].EsiScopes, "esi-contracts.read_corporation_contracts.v1") {
 			continue
 		}
 
-		if err := u.syncCorporationContracts(ctx, &corps[i], buyerUserID, allKeys, keyToPurchases); err != nil {
+		if err := u.syncCorporationContracts(ctx, &corps[i], buyerUserID, buyerIDs, keyToPurchases); err != nil {
 			log.Error("contract sync: failed for corporation",
 				"corporationID", corps[i].ID, "buyerUserID", buyerUserID, "error", err)
 		}
@@ -134,7 +180,7 @@ func (u *ContractSync) syncCharacterContracts(
 	ctx context.Context,
 	char *repositories.Character,
 	userID int64,
-	allKeys []string,
+	buyerIDs map[int64]bool,
 	keyToPurchases map[string][]*models.PurchaseTransaction,
 ) error {
 	token, refresh, expire := char.EsiToken, char.EsiRefreshToken, char.EsiTokenExpiresOn
@@ -158,7 +204,10 @@ func (u *ContractSync) syncCharacterContracts(
 		return errors.Wrapf(err, "failed to get contracts for character %d", char.ID)
 	}
 
-	u.matchContracts(ctx, contracts, allKeys, keyToPurchases)
+	fetchItems := func(ctx context.Context, contractID int64) ([]*client.EsiContractItem, error) {
+		return u.esiClient.GetCharacterContractItems(ctx, char.ID, contractID, token)
+	}
+	u.matchContracts(ctx, contracts, fetchItems, buyerIDs, keyToPurchases)
 
 	return nil
 }
@@ -167,7 +216,7 @@ func (u *ContractSync) syncCorporationContracts(
 	ctx context.Context,
 	corp *repositories.PlayerCorporation,
 	userID int64,
-	allKeys []string,
+	buyerIDs map[int64]bool,
 	keyToPurchases map[string][]*models.PurchaseTransaction,
 ) error {
 	token, refresh, expire := corp.EsiToken, corp.EsiRefreshToken, corp.EsiExpiresOn
@@ -191,28 +240,168 @@ func (u *ContractSync) syncCorporationContracts(
 		return errors.Wrapf(err, "failed to get contracts for corporation %d", corp.ID)
 	}
 
-	u.matchContracts(ctx, contracts, allKeys, keyToPurchases)
+	fetchItems := func(ctx context.Context, contractID int64) ([]*client.EsiContractItem, error) {
+		return u.esiClient.GetCorporationContractItems(ctx, corp.ID, contractID, token)
+	}
+	u.matchContracts(ctx, contracts, fetchItems, buyerIDs, keyToPurchases)
 
 	return nil
 }
 
-func (u *ContractSync) matchContracts(ctx context.Context, contracts []*client.EsiContract, allKeys []string, keyToPurchases map[string][]*models.PurchaseTransaction) {
+func (u *ContractSync) matchContracts(
+	ctx context.Context,
+	contracts []*client.EsiContract,
+	fetchItems contractItemsFetcher,
+	buyerIDs map[int64]bool,
+	keyToPurchases map[string][]*models.PurchaseTransaction,
+) {
 	for _, contract := range contracts {
 		if contract.Status != "finished" || contract.Type != "item_exchange" {
 			continue
 		}
 
-		for _, key := range allKeys {
-			if strings.Contains(contract.Title, key) {
-				u.completePurchases(ctx, keyToPurchases[key], contract.ContractID)
+		for _, key := range contractKeyPattern.FindAllString(contract.Title, -1) {
+			if _, ok := keyToPurchases[key]; !ok {
+				continue
+			}
+
+			purchases := keyToPurchases[key]
+			items, err := fetchItems(ctx, contract.ContractID)
+			if err != nil {
+				// Leave the purchases for the next sync rather than judging the contract blind
+				log.Error("contract sync: failed to get contract items",
+					"eveContractID", contract.ContractID, "contractKey", key, "error", err)
 				break
 			}
+
+			reasons := verifyContract(contract, items, purchases, buyerIDs)
+			if len(reasons) > 0 {
+				u.disputePurchases(ctx, key, purchases, contract.ContractID, reasons)
+			} else {
+				u.completePurchases(ctx, purchases, contract.ContractID)
+			}
+			break
 		}
 	}
 }
 
+// contractLine is one kind of item a contract must include. Blueprint copies are kept apart from
+// originals of the same type so a copy cannot stand in for an original or the other way round.
+type contractLine struct {
+	typeID int64
+	isCopy bool
+}
+
+// verifyContract compares a finished contract with the purchases it was made for and returns why
+// it does not match them, or nothing if it does. ESI does not report the runs or research levels
+// of contract items, so blueprint purchases are only checked for being a copy or an original.
+func verifyContract(contract *client.EsiContract, items []*client.EsiContractItem, purchases []*models.PurchaseTransaction, buyerIDs map[int64]bool) []string {
+	expected := map[contractLine]int64{}
+	names := map[contractLine]string{}
+	expectedPrice := 0.0
+	for _, purchase := range purchases {
+		line := contractLine{typeID: purchase.TypeID, isCopy: purchase.Blueprint != nil && purchase.Blueprint.IsCopy}
+		expected[line] += purchase.QuantityPurchased
+		names[line] = purchase.TypeName
+		if line.isCopy {
+			names[line] += " (copy)"
+		}
+		expectedPrice += purchase.TotalPrice
+	}
+
+	received := map[contractLine]int64{}
+	for _, item := range items {
+		if item.IsIncluded {
+			line := contractLine{typeID: item.TypeID, isCopy: item.RawQuantity != nil && *item.RawQuantity == -2}
+			received[line] += item.Quantity
+		}
+	}
+
+	lines := make([]contractLine, 0, len(expected))
+	for line := range expected {
+		lines = append(lines, line)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].typeID != lines[j].typeID {
+			return lines[i].typeID < lines[j].typeID
+		}
+		return !lines[i].isCopy && lines[j].isCopy
+	})
+
+	reasons := []string{}
+	for _, line := range lines {
+		want, got := expected[line], received[line]
+		switch {
+		case got == 0 && want > 0:
+			reasons = append(reasons, fmt.Sprintf("missing %d × %s", want, names[line]))
+		case got < want:
+			reasons = append(reasons, fmt.Sprintf("short %d × %s (received %d of %d)", want-got, names[line], got, want))
+		}
+	}
+
+	if math.Abs(contract.Price-expectedPrice) >= 1 {
+		reasons = append(reasons, fmt.Sprintf("price %s does not match agreed %s", formatISK(contract.Price), formatISK(expectedPrice)))
+	}
+
+	if !buyerIDs[contract.AssigneeID] {
+		reasons = append(reasons, fmt.Sprintf("assigned to %d instead of the buyer", contract.AssigneeID))
+	}
+
+	return reasons
+}
+
+// disputePurchases marks the purchases matched to a contract as disputed and tells both parties.
+func (u *ContractSync) disputePurchases(ctx context.Context, key string, purchases []*models.PurchaseTransaction, eveContractID int64, reasons []string) {
+	purchaseIDs := make([]int64, 0, len(purchases))
+	for _, purchase := range purchases {
+		purchaseIDs = append(purchaseIDs, purchase.ID)
+	}
+
+	if err := u.purchaseRepo.DisputeWithContractID(ctx, purchaseIDs, eveContractID, strings.Join(reasons, "; ")); err != nil {
+		log.Error("contract sync: failed to dispute purchases",
+			"contractKey", key, "eveContractID", eveContractID, "error", err)
+		return
+	}
+
+	log.Info("contract sync: disputed contract",
+		"contractKey", key, "eveContractID", eveContractID, "purchases", len(purchaseIDs), "reasons", reasons)
+
+	if u.disputeNotifier != nil {
+		u.disputeNotifier.NotifyContractDisputed(ctx, &ContractDispute{
+			ContractKey:   key,
+			EveContractID: eveContractID,
+			BuyerUserID:   purchases[0].BuyerUserID,
+			SellerUserID:  purchases[0].SellerUserID,
+			Purchases:     purchases,
+			Reasons:       reasons,
+		})
+	}
+}
+
+// completePurchases completes the purchases matched to a contract. Purchases checked out as an
+// order share one contract and are completed together in one step.
 func (u *ContractSync) completePurchases(ctx context.Context, purchases []*models.PurchaseTransaction, eveContractID int64) {
+	completedOrders := map[int64]bool{}
 	for _, purchase := range purchases {
+		if purchase.OrderID != nil {
+			orderID := *purchase.OrderID
+			if completedOrders[orderID] {
+				continue
+			}
+			completedOrders[orderID] = true
+
+			completed, err := u.purchaseRepo.CompleteOrderWithContractID(ctx, orderID, eveContractID)
+			if err != nil {
+				log.Error("contract sync: failed to auto-complete order",
+					"orderID", orderID, "eveContractID", eveContractID, "error", err)
+			} else {
+				log.Info("contract sync: auto-completed order",
+					"orderID", orderID, "purchases", completed, "eveContractID", eveContractID,
+					"buyerUserID", purchase.BuyerUserID, "contractKey", *purchase.ContractKey)
+			}
+			continue
+		}
+
 		if err := u.purchaseRepo.CompleteWithContractID(ctx, purchase.ID, eveContractID); err != nil {
 			log.Error("contract sync: failed to auto-complete purchase",
 				"purchaseID", purchase.ID, "eveContractID", eveContractID, "error", err)
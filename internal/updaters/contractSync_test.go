@@ -20,6 +20,14 @@ type mockContractSyncPurchaseRepo struct {
 	contractCreatedErr error
 	completedCalls     []completeCall
 	completeErr        error
+	completedOrders    []completeCall
+	disputedCalls      []disputeCall
+}
+
+type disputeCall struct {
+	PurchaseIDs   []int64
+	EveContractID int64
+	Reason        string
 }
 
 type completeCall struct {
@@ -36,6 +44,16 @@ func (m *mockContractSyncPurchaseRepo) CompleteWithContractID(ctx context.Contex
 	return m.completeErr
 }
 
+func (m *mockContractSyncPurchaseRepo) CompleteOrderWithContractID(ctx context.Context, orderID int64, eveContractID int64) (int64, error) {
+	m.completedOrders = append(m.completedOrders, completeCall{PurchaseID: orderID, EveContractID: eveContractID})
+	return 1, m.completeErr
+}
+
+func (m *mockContractSyncPurchaseRepo) DisputeWithContractID(ctx context.Context, purchaseIDs []int64, eveContractID int64, reason string) error {
+	m.disputedCalls = append(m.disputedCalls, disputeCall{PurchaseIDs: purchaseIDs, EveContractID: eveContractID, Reason: reason})
+	return nil
+}
+
 type mockContractSyncCharRepo struct {
 	charactersByUser map[int64][]*repositories.Character
 	getErr           error
@@ -77,6 +95,8 @@ type mockContractSyncEsiClient struct {
 	corpContractErr error
 	refreshedToken  *client.RefreshedToken
 	refreshErr      error
+	itemsByContract map[int64][]*client.EsiContractItem
+	itemsErr        error
 }
 
 func (m *mockContractSyncEsiClient) GetCharacterContracts(ctx context.Context, characterID int64, token, refresh string, expire time.Time) ([]*client.EsiContract, error) {
@@ -93,6 +113,28 @@ func (m *mockContractSyncEsiClient) GetCorporationContracts(ctx context.Context,
 	return m.contractsByCorp[corporationID], nil
 }
 
+func (m *mockContractSyncEsiClient) GetCharacterContractItems(ctx context.Context, characterID, contractID int64, token string) ([]*client.EsiContractItem, error) {
+	if m.itemsErr != nil {
+		return nil, m.itemsErr
+	}
+	return m.itemsByContract[contractID], nil
+}
+
+func (m *mockContractSyncEsiClient) GetCorporationContractItems(ctx context.Context, corporationID, contractID int64, token string) ([]*client.EsiContractItem, error) {
+	if m.itemsErr != nil {
+		return nil, m.itemsErr
+	}
+	return m.itemsByContract[contractID], nil
+}
+
+type mockContractDisputeNotifier struct {
+	disputes []*updaters.ContractDispute
+}
+
+func (m *mockContractDisputeNotifier) NotifyContractDisputed(ctx context.Context, dispute *updaters.ContractDispute) {
+	m.disputes = append(m.disputes, dispute)
+}
+
 func (m *mockContractSyncEsiClient) RefreshAccessToken(ctx context.Context, refreshToken string) (*client.RefreshedToken, error) {
 	if m.refreshErr != nil {
 		return nil, m.refreshErr
@@ -131,7 +173,7 @@ func Test_ContractSync_AutoCompletesMatchingContract(t *testing.T) {
 	esiClient := &mockContractSyncEsiClient{
 		contractsByChar: map[int64][]*client.EsiContract{
 			2001: {
-				{ContractID: 99999, Type: "item_exchange", Status: "finished", Title: "Items for PT-42"},
+				{ContractID: 99999, Type: "item_exchange", Status: "finished", AssigneeID: 2001, Title: "Items for PT-42"},
 			},
 		},
 	}
@@ -181,7 +223,7 @@ func Test_ContractSync_SkipsCharacterWithoutScope(t *testing.T) {
 	esiClient := &mockContractSyncEsiClient{
 		contractsByChar: map[int64][]*client.EsiContract{
 			2001: {
-				{ContractID: 99999, Type: "item_exchange", Status: "finished", Title: "PT-10 delivery"},
+				{ContractID: 99999, Type: "item_exchange", Status: "finished", AssigneeID: 2001, Title: "PT-10 delivery"},
 			},
 		},
 	}
@@ -249,7 +291,7 @@ func Test_ContractSync_MultiplePurchasesSameKeyAllCompleted(t *testing.T) {
 	esiClient := &mockContractSyncEsiClient{
 		contractsByChar: map[int64][]*client.EsiContract{
 			2001: {
-				{ContractID: 55555, Type: "item_exchange", Status: "finished", Title: "Corp delivery BATCH-1"},
+				{ContractID: 55555, Type: "item_exchange", Status: "finished", AssigneeID: 2001, Title: "Corp delivery BATCH-1"},
 			},
 		},
 	}
@@ -267,6 +309,79 @@ func Test_ContractSync_MultiplePurchasesSameKeyAllCompleted(t *testing.T) {
 	}
 }
 
+func Test_ContractSync_OrderCompletedInOneStep(t *testing.T) {
+	key := "PO-7"
+	orderID := int64(7)
+	purchaseRepo := &mockContractSyncPurchaseRepo{
+		contractCreated: []*models.PurchaseTransaction{
+			{ID: 20, BuyerUserID: 100, ContractKey: &key, OrderID: &orderID},
+			{ID: 21, BuyerUserID: 100, ContractKey: &key, OrderID: &orderID},
+			{ID: 22, BuyerUserID: 100, ContractKey: &key, OrderID: &orderID},
+		},
+	}
+
+	charRepo := &mockContractSyncCharRepo{
+		charactersByUser: map[int64][]*repositories.Character{
+			100: {
+				{ID: 2001, UserID: 100, EsiToken: "tok", EsiRefreshToken: "ref",
+					EsiTokenExpiresOn: time.Now().Add(1 * time.Hour),
+					EsiScopes:         "esi-contracts.read_character_contracts.v1"},
+			},
+		},
+	}
+
+	esiClient := &mockContractSyncEsiClient{
+		contractsByChar: map[int64][]*client.EsiContract{
+			2001: {
+				{ContractID: 77777, Type: "item_exchange", Status: "finished", AssigneeID: 2001, Title: "Cart PO-7"},
+			},
+		},
+	}
+
+	syncer := updaters.NewContractSync(purchaseRepo, charRepo, emptyCorpRepo(), esiClient)
+	err := syncer.SyncAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, purchaseRepo.completedCalls)
+	assert.Equal(t, []completeCall{{PurchaseID: 7, EveContractID: 77777}}, purchaseRepo.completedOrders)
+}
+
+func Test_ContractSync_MatchesWholeContractKey(t *testing.T) {
+	key1, key12 := "PO-1", "PO-12"
+	order1, order12 := int64(1), int64(12)
+	purchaseRepo := &mockContractSyncPurchaseRepo{
+		contractCreated: []*models.PurchaseTransaction{
+			{ID: 20, BuyerUserID: 100, ContractKey: &key1, OrderID: &order1},
+			{ID: 21, BuyerUserID: 100, ContractKey: &key12, OrderID: &order12},
+		},
+	}
+
+	charRepo := &mockContractSyncCharRepo{
+		charactersByUser: map[int64][]*repositories.Character{
+			100: {
+				{ID: 2001, UserID: 100, EsiToken: "tok", EsiRefreshToken: "ref",
+					EsiTokenExpiresOn: time.Now().Add(1 * time.Hour),
+					EsiScopes:         "esi-contracts.read_character_contracts.v1"},
+			},
+		},
+	}
+
+	esiClient := &mockContractSyncEsiClient{
+		contractsByChar: map[int64][]*client.EsiContract{
+			2001: {
+				{ContractID: 77777, Type: "item_exchange", Status: "finished", AssigneeID: 2001, Title: "Cart PO-12"},
+				{ContractID: 88888, Type: "item_exchange", Status: "finished", AssigneeID: 2001, Title: "PO-123"},
+			},
+		},
+	}
+
+	syncer := updaters.NewContractSync(purchaseRepo, charRepo, emptyCorpRepo(), esiClient)
+	err := syncer.SyncAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []completeCall{{PurchaseID: 12, EveContractID: 77777}}, purchaseRepo.completedOrders)
+}
+
 func Test_ContractSync_IgnoresNonFinishedContracts(t *testing.T) {
 	key := "PT-1"
 	purchaseRepo := &mockContractSyncPurchaseRepo{
@@ -366,7 +481,7 @@ func Test_ContractSync_RefreshesExpiredToken(t *testing.T) {
 		},
 		contractsByChar: map[int64][]*client.EsiContract{
 			2001: {
-				{ContractID: 77777, Type: "item_exchange", Status: "finished", Title: "PT-5 minerals"},
+				{ContractID: 77777, Type: "item_exchange", Status: "finished", AssigneeID: 2001, Title: "PT-5 minerals"},
 			},
 		},
 	}
@@ -394,6 +509,205 @@ func Test_ContractSync_GetPurchasesError(t *testing.T) {
 	assert.Contains(t, err.Error(), "contract_created purchases")
 }
 
+// --- Contract Verification Tests ---
+
+func newVerifiedContractSync(purchases []*models.PurchaseTransaction, contract *client.EsiContract, items []*client.EsiContractItem, itemsErr error) (*updaters.ContractSync, *mockContractSyncPurchaseRepo, *mockContractDisputeNotifier) {
+	purchaseRepo := &mockContractSyncPurchaseRepo{contractCreated: purchases}
+
+	charRepo := &mockContractSyncCharRepo{
+		charactersByUser: map[int64][]*repositories.Character{
+			100: {
+				{ID: 2001, UserID: 100, EsiToken: "tok", EsiRefreshToken: "ref",
+					EsiTokenExpiresOn: time.Now().Add(1 * time.Hour),
+					EsiScopes:         "esi-contracts.read_character_contracts.v1"},
+			},
+		},
+	}
+
+	esiClient := &mockContractSyncEsiClient{
+		contractsByChar: map[int64][]*client.EsiContract{2001: {contract}},
+		itemsByContract: map[int64][]*client.EsiContractItem{contract.ContractID: items},
+		itemsErr:        itemsErr,
+	}
+
+	notifier := &mockContractDisputeNotifier{}
+	syncer := updaters.NewContractSync(purchaseRepo, charRepo, emptyCorpRepo(), esiClient)
+	syncer.WithDisputeNotifier(notifier)
+
+	return syncer, purchaseRepo, notifier
+}
+
+func verificationPurchases(key *string) []*models.PurchaseTransaction {
+	return []*models.PurchaseTransaction{
+		{ID: 30, BuyerUserID: 100, SellerUserID: 300, ContractKey: key, TypeID: 34, TypeName: "Tritanium", QuantityPurchased: 1000, TotalPrice: 5000},
+		{ID: 31, BuyerUserID: 100, SellerUserID: 300, ContractKey: key, TypeID: 35, TypeName: "Pyerite", QuantityPurchased: 500, TotalPrice: 5000},
+	}
+}
+
+func Test_ContractSync_CompletesWhenContractMatchesPurchases(t *testing.T) {
+	key := "PT-30"
+	contract := &client.EsiContract{ContractID: 30303, Type: "item_exchange", Status: "finished", Title: "PT-30", AssigneeID: 2001, Price: 10000}
+	items := []*client.EsiContractItem{
+		{RecordID: 1, TypeID: 34, Quantity: 600, IsIncluded: true},
+		{RecordID: 2, TypeID: 34, Quantity: 400, IsIncluded: true}, // split stack
+		{RecordID: 3, TypeID: 35, Quantity: 500, IsIncluded: true},
+	}
+
+	syncer, purchaseRepo, notifier := newVerifiedContractSync(verificationPurchases(&key), contract, items, nil)
+	err := syncer.SyncAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, purchaseRepo.completedCalls, 2)
+	assert.Empty(t, purchaseRepo.disputedCalls)
+	assert.Empty(t, notifier.disputes)
+}
+
+func Test_ContractSync_DisputesShortQuantity(t *testing.T) {
+	key := "PT-30"
+	contract := &client.EsiContract{ContractID: 30303, Type: "item_exchange", Status: "finished", Title: "PT-30", AssigneeID: 2001, Price: 10000}
+	items := []*client.EsiContractItem{
+		{RecordID: 1, TypeID: 34, Quantity: 600, IsIncluded: true},
+		{RecordID: 2, TypeID: 35, Quantity: 500, IsIncluded: false}, // asked for, not given
+	}
+
+	syncer, purchaseRepo, notifier := newVerifiedContractSync(verificationPurchases(&key), contract, items, nil)
+	err := syncer.SyncAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, purchaseRepo.completedCalls)
+	assert.Len(t, purchaseRepo.disputedCalls, 1)
+	assert.Equal(t, []int64{30, 31}, purchaseRepo.disputedCalls[0].PurchaseIDs)
+	assert.Equal(t, int64(30303), purchaseRepo.disputedCalls[0].EveContractID)
+	assert.Contains(t, purchaseRepo.disputedCalls[0].Reason, "short 400 × Tritanium (received 600 of 1000)")
+	assert.Contains(t, purchaseRepo.disputedCalls[0].Reason, "missing 500 × Pyerite")
+
+	assert.Len(t, notifier.disputes, 1)
+	assert.Equal(t, int64(100), notifier.disputes[0].BuyerUserID)
+	assert.Equal(t, int64(300), notifier.disputes[0].SellerUserID)
+	assert.Equal(t, "PT-30", notifier.disputes[0].ContractKey)
+	assert.Len(t, notifier.disputes[0].Reasons, 2)
+}
+
+func Test_ContractSync_DoesNotDisputeOrderWithPrefixKey(t *testing.T) {
+	key1, key12 := "PO-1", "PO-12"
+	order1, order12 := int64(1), int64(12)
+	purchases := []*models.PurchaseTransaction{
+		{ID: 40, BuyerUserID: 100, SellerUserID: 300, ContractKey: &key1, OrderID: &order1, TypeID: 34, TypeName: "Tritanium", QuantityPurchased: 1000, TotalPrice: 5000},
+		{ID: 41, BuyerUserID: 100, SellerUserID: 300, ContractKey: &key12, OrderID: &order12, TypeID: 35, TypeName: "Pyerite", QuantityPurchased: 500, TotalPrice: 10000},
+	}
+	contract := &client.EsiContract{ContractID: 12121, Type: "item_exchange", Status: "finished", Title: "PO-12", AssigneeID: 2001, Price: 10000}
+	items := []*client.EsiContractItem{
+		{RecordID: 1, TypeID: 35, Quantity: 500, IsIncluded: true},
+	}
+
+	syncer, purchaseRepo, notifier := newVerifiedContractSync(purchases, contract, items, nil)
+	err := syncer.SyncAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, purchaseRepo.disputedCalls)
+	assert.Empty(t, notifier.disputes)
+	assert.Equal(t, []completeCall{{PurchaseID: 12, EveContractID: 12121}}, purchaseRepo.completedOrders)
+}
+
+func Test_ContractSync_IgnoresOtherBuyersKeys(t *testing.T) {
+	ownKey, otherKey := "PT-1", "PT-30"
+	purchases := []*models.PurchaseTransaction{
+		{ID: 1, BuyerUserID: 100, SellerUserID: 300, ContractKey: &ownKey, TypeID: 34, TypeName: "Tritanium", QuantityPurchased: 10, TotalPrice: 50},
+		{ID: 30, BuyerUserID: 200, SellerUserID: 300, ContractKey: &otherKey, TypeID: 35, TypeName: "Pyerite", QuantityPurchased: 500, TotalPrice: 5000},
+	}
+	// Buyer 100 sees a contract naming buyer 200's key
+	contract := &client.EsiContract{ContractID: 30303, Type: "item_exchange", Status: "finished", Title: "PT-30", AssigneeID: 2001, Price: 1}
+	items := []*client.EsiContractItem{
+		{RecordID: 1, TypeID: 34, Quantity: 1, IsIncluded: true},
+	}
+
+	syncer, purchaseRepo, notifier := newVerifiedContractSync(purchases, contract, items, nil)
+	err := syncer.SyncAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, purchaseRepo.completedCalls)
+	assert.Empty(t, purchaseRepo.disputedCalls)
+	assert.Empty(t, notifier.disputes)
+}
+
+func Test_ContractSync_DisputesOriginalInPlaceOfBlueprintCopy(t *testing.T) {
+	key := "PT-30"
+	contract := &client.EsiContract{ContractID: 30303, Type: "item_exchange", Status: "finished", Title: "PT-30", AssigneeID: 2001, Price: 20000}
+	purchases := []*models.PurchaseTransaction{
+		{ID: 30, BuyerUserID: 100, SellerUserID: 300, ContractKey: &key, TypeID: 1000, TypeName: "Rifter Blueprint", QuantityPurchased: 1, TotalPrice: 20000,
+			Blueprint: &models.ListingBlueprint{ItemID: 9001, IsCopy: true, Runs: 10, MaterialEfficiency: 10, TimeEfficiency: 20}},
+	}
+	original := int64(-1)
+	items := []*client.EsiContractItem{
+		{RecordID: 1, TypeID: 1000, Quantity: 1, IsIncluded: true, IsSingleton: true, RawQuantity: &original},
+	}
+
+	syncer, purchaseRepo, _ := newVerifiedContractSync(purchases, contract, items, nil)
+	err := syncer.SyncAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, purchaseRepo.completedCalls)
+	assert.Len(t, purchaseRepo.disputedCalls, 1)
+	assert.Equal(t, "missing 1 × Rifter Blueprint (copy)", purchaseRepo.disputedCalls[0].Reason)
+
+	// The same contract with the copy in it completes
+	copied := int64(-2)
+	items[0].RawQuantity = &copied
+	syncer, purchaseRepo, _ = newVerifiedContractSync(purchases, contract, items, nil)
+	assert.NoError(t, syncer.SyncAll(context.Background()))
+	assert.Len(t, purchaseRepo.completedCalls, 1)
+	assert.Empty(t, purchaseRepo.disputedCalls)
+}
+
+func Test_ContractSync_DisputesWrongPrice(t *testing.T) {
+	key := "PT-30"
+	contract := &client.EsiContract{ContractID: 30303, Type: "item_exchange", Status: "finished", Title: "PT-30", AssigneeID: 2001, Price: 15000}
+	items := []*client.EsiContractItem{
+		{RecordID: 1, TypeID: 34, Quantity: 1000, IsIncluded: true},
+		{RecordID: 2, TypeID: 35, Quantity: 500, IsIncluded: true},
+	}
+
+	syncer, purchaseRepo, notifier := newVerifiedContractSync(verificationPurchases(&key), contract, items, nil)
+	err := syncer.SyncAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, purchaseRepo.completedCalls)
+	assert.Len(t, purchaseRepo.disputedCalls, 1)
+	assert.Equal(t, "price 15,000.00 ISK does not match agreed 10,000.00 ISK", purchaseRepo.disputedCalls[0].Reason)
+	assert.Len(t, notifier.disputes, 1)
+}
+
+func Test_ContractSync_DisputesWrongAssignee(t *testing.T) {
+	key := "PT-30"
+	contract := &client.EsiContract{ContractID: 30303, Type: "item_exchange", Status: "finished", Title: "PT-30", AssigneeID: 9999, Price: 10000}
+	items := []*client.EsiContractItem{
+		{RecordID: 1, TypeID: 34, Quantity: 1000, IsIncluded: true},
+		{RecordID: 2, TypeID: 35, Quantity: 500, IsIncluded: true},
+	}
+
+	syncer, purchaseRepo, _ := newVerifiedContractSync(verificationPurchases(&key), contract, items, nil)
+	err := syncer.SyncAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, purchaseRepo.completedCalls)
+	assert.Len(t, purchaseRepo.disputedCalls, 1)
+	assert.Equal(t, "assigned to 9999 instead of the buyer", purchaseRepo.disputedCalls[0].Reason)
+}
+
+func Test_ContractSync_LeavesPurchasesWhenItemsUnavailable(t *testing.T) {
+	key := "PT-30"
+	contract := &client.EsiContract{ContractID: 30303, Type: "item_exchange", Status: "finished", Title: "PT-30", AssigneeID: 2001, Price: 10000}
+
+	syncer, purchaseRepo, notifier := newVerifiedContractSync(verificationPurchases(&key), contract, nil, errors.New("ESI unavailable"))
+	err := syncer.SyncAll(context.Background())
+
+	// Left for the next sync rather than completed or disputed blind
+	assert.NoError(t, err)
+	assert.Empty(t, purchaseRepo.completedCalls)
+	assert.Empty(t, purchaseRepo.disputedCalls)
+	assert.Empty(t, notifier.disputes)
+}
+
 // --- Corporation Contract Tests ---
 
 func Test_ContractSync_CorpContractMatchesAndCompletes(t *testing.T) {
@@ -431,7 +745,7 @@ func Test_ContractSync_CorpContractMatchesAndCompletes(t *testing.T) {
 		},
 		contractsByCorp: map[int64][]*client.EsiContract{
 			5001: {
-				{ContractID: 88888, Type: "item_exchange", Status: "finished", Title: "Corp delivery PT-50"},
+				{ContractID: 88888, Type: "item_exchange", Status: "finished", AssigneeID: 5001, Title: "Corp delivery PT-50"},
 			},
 		},
 	}
@@ -470,7 +784,7 @@ func Test_ContractSync_SkipsCorporationWithoutScope(t *testing.T) {
 	esiClient := &mockContractSyncEsiClient{
 		contractsByCorp: map[int64][]*client.EsiContract{
 			5001: {
-				{ContractID: 99999, Type: "item_exchange", Status: "finished", Title: "PT-60 delivery"},
+				{ContractID: 99999, Type: "item_exchange", Status: "finished", AssigneeID: 5001, Title: "PT-60 delivery"},
 			},
 		},
 	}
@@ -512,7 +826,7 @@ func Test_ContractSync_CorpTokenRefresh(t *testing.T) {
 		},
 		contractsByCorp: map[int64][]*client.EsiContract{
 			5001: {
-				{ContractID: 77777, Type: "item_exchange", Status: "finished", Title: "PT-70 materials"},
+				{ContractID: 77777, Type: "item_exchange", Status: "finished", AssigneeID: 5001, Title: "PT-70 materials"},
 			},
 		},
 	}
@@ -579,7 +893,7 @@ func Test_ContractSync_NoCorporationsForBuyer(t *testing.T) {
 	esiClient := &mockContractSyncEsiClient{
 		contractsByChar: map[int64][]*client.EsiContract{
 			2001: {
-				{ContractID: 11111, Type: "item_exchange", Status: "finished", Title: "PT-90 delivery"},
+				{ContractID: 11111, Type: "item_exchange", Status: "finished", AssigneeID: 2001, Title: "PT-90 delivery"},
 			},
 		},
 	}
@@ -0,0 +1,401 @@
+package updaters
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/client"
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+
+	"github.com/pkg/errors"
+)
+
+// courierMatchTolerance is how far a courier contract's volume and collateral may
+// differ from the job's before a route match is rejected.
+const courierMatchTolerance = 0.05
+
+// courierJobKeyPattern finds a transport job reference such as "TJ-42" in a contract title.
+var courierJobKeyPattern = regexp.MustCompile(`\bTJ-(\d+)\b`)
+
+type CourierContractJobsRepository interface {
+	GetOpenCourierJobs(ctx context.Context) ([]*models.TransportJob, error)
+	UpdateContract(ctx context.Context, id, eveContractID int64, contractStatus, status string) error
+}
+
+type CourierContractEsiClient interface {
+	GetCharacterContracts(ctx context.Context, characterID int64, token, refresh string, expire time.Time) ([]*client.EsiContract, error)
+	GetCorporationContracts(ctx context.Context, corporationID int64, token, refresh string, expire time.Time) ([]*client.EsiContract, error)
+	RefreshAccessToken(ctx context.Context, refreshToken string) (*client.RefreshedToken, error)
+}
+
+// CourierContractNotifier is notified when a courier contract linked to a transport job fails or expires
+type CourierContractNotifier interface {
+	NotifyCourierContractAlert(ctx context.Context, alert *CourierContractAlert)
+}
+
+// CourierContractAlert describes a transport job whose courier contract failed or expired
+type CourierContractAlert struct {
+	Job            *models.TransportJob
+	EveContractID  int64
+	ContractStatus string
+}
+
+// CourierContractSync links courier_contract transport jobs to the courier contracts
+// their owners issue in game, and moves the jobs along as the contracts are accepted
+// and delivered.
+type CourierContractSync struct {
+	jobsRepo      CourierContractJobsRepository
+	characterRepo ContractSyncCharacterRepository
+	corpRepo      ContractSyncCorporationRepository
+	esiClient     CourierContractEsiClient
+	notifier      CourierContractNotifier
+	now           func() time.Time
+}
+
+func NewCourierContractSync(
+	jobsRepo CourierContractJobsRepository,
+	characterRepo ContractSyncCharacterRepository,
+	corpRepo ContractSyncCorporationRepository,
+	esiClient CourierContractEsiClient,
+) *CourierContractSync {
+	return &CourierContractSync{
+		jobsRepo:      jobsRepo,
+		characterRepo: characterRepo,
+		corpRepo:      corpRepo,
+		esiClient:     esiClient,
+		now:           time.Now,
+	}
+}
+
+// WithNotifier sets the optional notifier told about failed and expired contracts.
+func (u *CourierContractSync) WithNotifier(notifier CourierContractNotifier) {
+	u.notifier = notifier
+}
+
+// SyncAll matches the open courier_contract jobs of every user against the courier
+// contracts issued by their characters and corporations.
+func (u *CourierContractSync) SyncAll(ctx context.Context) error {
+	jobs, err := u.jobsRepo.GetOpenCourierJobs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get open courier jobs")
+	}
+
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	userJobs := map[int64][]*models.TransportJob{}
+	for _, job := range jobs {
+		userJobs[job.UserID] = append(userJobs[job.UserID], job)
+	}
+
+	log.Info("courier contract sync: checking transport jobs", "jobCount", len(jobs), "userCount", len(userJobs))
+
+	for userID, jobs := range userJobs {
+		if err := u.syncUser(ctx, userID, jobs); err != nil {
+			log.Error("courier contract sync: failed for user", "userID", userID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (u *CourierContractSync) syncUser(ctx context.Context, userID int64, jobs []*models.TransportJob) error {
+	characters, err := u.characterRepo.GetAll(ctx, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get user characters")
+	}
+
+	corps, err := u.corpRepo.Get(ctx, userID)
+	if err != nil {
+		log.Error("courier contract sync: failed to get user corporations", "userID", userID, "error", err)
+		corps = nil
+	}
+
+	issuerIDs := map[int64]bool{}
+	for _, char := range characters {
+		issuerIDs[char.ID] = true
+	}
+	for _, corp := range corps {
+		issuerIDs[corp.ID] = true
+	}
+
+	// Characters and their corporation can both list the same contract
+	contracts := map[int64]*client.EsiContract{}
+	add := func(list []*client.EsiContract) {
+		for _, contract := range list {
+			if contract.Type != "courier" {
+				continue
+			}
+			if !issuerIDs[contract.IssuerID] && !(contract.ForCorporation && issuerIDs[contract.IssuerCorporationID]) {
+				continue
+			}
+			contracts[contract.ContractID] = contract
+		}
+	}
+
+	for _, char := range characters {
+		if !strings.Contains(char.EsiScopes, "esi-contracts.read_character_contracts.v1") {
+			continue
+		}
+
+		list, err := u.characterContracts(ctx, char, userID)
+		if err != nil {
+			log.Error("courier contract sync: failed for character",
+				"characterID", char.ID, "userID", userID, "error", err)
+			continue
+		}
+		add(list)
+	}
+
+	for i := range corps {
+		if !strings.Contains(corps[i].EsiScopes, "esi-contracts.read_corporation_contracts.v1") {
+			continue
+		}
+
+		list, err := u.corporationContracts(ctx, &corps[i], userID)
+		if err != nil {
+			log.Error("courier contract sync: failed for corporation",
+				"corporationID", corps[i].ID, "userID", userID, "error", err)
+			continue
+		}
+		add(list)
+	}
+
+	if len(contracts) == 0 {
+		return nil
+	}
+
+	for job, contract := range u.matchJobs(jobs, contracts) {
+		u.applyContract(ctx, job, contract)
+	}
+
+	return nil
+}
+
+func (u *CourierContractSync) characterContracts(ctx context.Context, char *repositories.Character, userID int64) ([]*client.EsiContract, error) {
+	token, refresh, expire := char.EsiToken, char.EsiRefreshToken, char.EsiTokenExpiresOn
+
+	if time.Now().After(expire) {
+		refreshed, err := u.esiClient.RefreshAccessToken(ctx, refresh)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to refresh token for character %d", char.ID)
+		}
+		token = refreshed.AccessToken
+		refresh = refreshed.RefreshToken
+		expire = refreshed.Expiry
+
+		if err := u.characterRepo.UpdateTokens(ctx, char.ID, userID, token, refresh, expire); err != nil {
+			log.Error("courier contract sync: failed to persist refreshed token", "characterID", char.ID, "error", err)
+		}
+	}
+
+	contracts, err := u.esiClient.GetCharacterContracts(ctx, char.ID, token, refresh, expire)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get contracts for character %d", char.ID)
+	}
+	return contracts, nil
+}
+
+func (u *CourierContractSync) corporationContracts(ctx context.Context, corp *repositories.PlayerCorporation, userID int64) ([]*client.EsiContract, error) {
+	token, refresh, expire := corp.EsiToken, corp.EsiRefreshToken, corp.EsiExpiresOn
+
+	if time.Now().After(expire) {
+		refreshed, err := u.esiClient.RefreshAccessToken(ctx, refresh)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to refresh token for corporation %d", corp.ID)
+		}
+		token = refreshed.AccessToken
+		refresh = refreshed.RefreshToken
+		expire = refreshed.Expiry
+
+		if err := u.corpRepo.UpdateTokens(ctx, corp.ID, userID, token, refresh, expire); err != nil {
+			log.Error("courier contract sync: failed to persist refreshed token", "corporationID", corp.ID, "error", err)
+		}
+	}
+
+	contracts, err := u.esiClient.GetCorporationContracts(ctx, corp.ID, token, refresh, expire)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get contracts for corporation %d", corp.ID)
+	}
+	return contracts, nil
+}
+
+// matchJobs pairs jobs with contracts, each contract going to at most one job.
+// A job keeps its linked contract unless that contract is dead and a new one
+// matches. New links are made first by a TJ-<id> reference in the title, then
+// by route, volume and collateral for contracts issued after the job was
+// created.
+func (u *CourierContractSync) matchJobs(jobs []*models.TransportJob, contracts map[int64]*client.EsiContract) map[*models.TransportJob]*client.EsiContract {
+	now := u.now()
+	matches := map[*models.TransportJob]*client.EsiContract{}
+	claimed := map[int64]bool{}
+
+	// Newest first, so a reissued contract wins over the one it replaces
+	candidates := make([]*client.EsiContract, 0, len(contracts))
+	for _, contract := range contracts {
+		candidates = append(candidates, contract)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].ContractID > candidates[j].ContractID })
+
+	for _, job := range jobs {
+		if job.EveContractID == nil {
+			continue
+		}
+		if contract, ok := contracts[*job.EveContractID]; ok {
+			matches[job] = contract
+			claimed[contract.ContractID] = true
+		}
+	}
+
+	needsContract := func(job *models.TransportJob) bool {
+		linked, ok := matches[job]
+		return !ok || courierContractDead(courierContractStatus(linked, now))
+	}
+
+	for _, contract := range candidates {
+		if claimed[contract.ContractID] {
+			continue
+		}
+		for _, ref := range courierJobKeyPattern.FindAllStringSubmatch(contract.Title, -1) {
+			jobID, err := strconv.ParseInt(ref[1], 10, 64)
+			if err != nil {
+				continue
+			}
+			for _, job := range jobs {
+				if job.ID != jobID || !needsContract(job) {
+					continue
+				}
+				if _, linked := matches[job]; linked && courierContractDead(courierContractStatus(contract, now)) {
+					continue
+				}
+				matches[job] = contract
+				claimed[contract.ContractID] = true
+			}
+			if claimed[contract.ContractID] {
+				break
+			}
+		}
+	}
+
+	for _, job := range jobs {
+		if !needsContract(job) {
+			continue
+		}
+		for _, contract := range candidates {
+			if claimed[contract.ContractID] || courierContractDead(courierContractStatus(contract, now)) {
+				continue
+			}
+			if !courierRouteMatches(job, contract) {
+				continue
+			}
+			matches[job] = contract
+			claimed[contract.ContractID] = true
+			break
+		}
+	}
+
+	return matches
+}
+
+// courierRouteMatches reports whether a contract hauls the job's cargo between its
+// stations: same stations, volume and collateral within tolerance, issued after
+// the job was planned.
+func courierRouteMatches(job *models.TransportJob, contract *client.EsiContract) bool {
+	if contract.StartLocationID != job.OriginStationID || contract.EndLocationID != job.DestinationStationID {
+		return false
+	}
+	if issued, err := time.Parse(time.RFC3339, contract.DateIssued); err == nil && !job.CreatedAt.IsZero() && issued.Before(job.CreatedAt) {
+		return false
+	}
+	return withinTolerance(contract.Volume, job.TotalVolumeM3) && withinTolerance(contract.Collateral, job.TotalCollateral)
+}
+
+// withinTolerance compares a contract value against the job's; a job value of
+// zero is unknown and matches anything.
+func withinTolerance(actual, expected float64) bool {
+	if expected <= 0 {
+		return true
+	}
+	return math.Abs(actual-expected) <= expected*courierMatchTolerance
+}
+
+// courierContractStatus returns the contract's ESI status, or "expired" for an
+// outstanding contract past its expiry date, which ESI does not report itself.
+func courierContractStatus(contract *client.EsiContract, now time.Time) string {
+	if contract.Status == "outstanding" {
+		if expires, err := time.Parse(time.RFC3339, contract.DateExpired); err == nil && now.After(expires) {
+			return "expired"
+		}
+	}
+	return contract.Status
+}
+
+// courierContractDead reports whether a contract will never be delivered and
+// the job may be linked to a reissued one.
+func courierContractDead(status string) bool {
+	switch status {
+	case "expired", "deleted", "rejected", "cancelled", "failed":
+		return true
+	}
+	return false
+}
+
+// courierJobStatus returns the job status implied by a contract status. Jobs only
+// move forward, so a contract going back to outstanding leaves the job as is.
+func courierJobStatus(current, contractStatus string) string {
+	next := current
+	switch contractStatus {
+	case "in_progress":
+		next = "in_transit"
+	case "finished", "finished_issuer", "finished_contractor":
+		next = "delivered"
+	}
+
+	rank := map[string]int{"planned": 0, "in_transit": 1, "delivered": 2}
+	if rank[next] < rank[current] {
+		return current
+	}
+	return next
+}
+
+// applyContract records a matched contract on its job and alerts once when the
+// contract fails or expires.
+func (u *CourierContractSync) applyContract(ctx context.Context, job *models.TransportJob, contract *client.EsiContract) {
+	contractStatus := courierContractStatus(contract, u.now())
+	jobStatus := courierJobStatus(job.Status, contractStatus)
+
+	sameContract := job.EveContractID != nil && *job.EveContractID == contract.ContractID
+	sameStatus := job.ContractStatus != nil && *job.ContractStatus == contractStatus
+	if sameContract && sameStatus && jobStatus == job.Status {
+		return
+	}
+
+	if err := u.jobsRepo.UpdateContract(ctx, job.ID, contract.ContractID, contractStatus, jobStatus); err != nil {
+		log.Error("courier contract sync: failed to update transport job",
+			"jobID", job.ID, "eveContractID", contract.ContractID, "error", err)
+		return
+	}
+
+	log.Info("courier contract sync: updated transport job",
+		"jobID", job.ID, "eveContractID", contract.ContractID, "contractStatus", contractStatus, "status", jobStatus)
+
+	if u.notifier == nil || (contractStatus != "failed" && contractStatus != "expired") {
+		return
+	}
+	if sameContract && sameStatus {
+		return
+	}
+	u.notifier.NotifyCourierContractAlert(ctx, &CourierContractAlert{
+		Job:            job,
+		EveContractID:  contract.ContractID,
+		ContractStatus: contractStatus,
+	})
+}
@@ -0,0 +1,287 @@
+package updaters_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/client"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/updaters"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Mocks ---
+
+type courierContractUpdate struct {
+	JobID          int64
+	EveContractID  int64
+	ContractStatus string
+	Status         string
+}
+
+type mockCourierContractJobsRepo struct {
+	jobs      []*models.TransportJob
+	jobsErr   error
+	updates   []courierContractUpdate
+	updateErr error
+}
+
+func (m *mockCourierContractJobsRepo) GetOpenCourierJobs(ctx context.Context) ([]*models.TransportJob, error) {
+	return m.jobs, m.jobsErr
+}
+
+func (m *mockCourierContractJobsRepo) UpdateContract(ctx context.Context, id, eveContractID int64, contractStatus, status string) error {
+	m.updates = append(m.updates, courierContractUpdate{JobID: id, EveContractID: eveContractID, ContractStatus: contractStatus, Status: status})
+	return m.updateErr
+}
+
+type mockCourierContractNotifier struct {
+	alerts []*updaters.CourierContractAlert
+}
+
+func (m *mockCourierContractNotifier) NotifyCourierContractAlert(ctx context.Context, alert *updaters.CourierContractAlert) {
+	m.alerts = append(m.alerts, alert)
+}
+
+// --- Helpers ---
+
+func courierCharRepo() *mockContractSyncCharRepo {
+	return &mockContractSyncCharRepo{
+		charactersByUser: map[int64][]*repositories.Character{
+			100: {
+				{ID: 2001, UserID: 100, EsiToken: "tok", EsiRefreshToken: "ref",
+					EsiTokenExpiresOn: time.Now().Add(1 * time.Hour),
+					EsiScopes:         "esi-contracts.read_character_contracts.v1"},
+			},
+		},
+	}
+}
+
+func courierJob(id int64) *models.TransportJob {
+	return &models.TransportJob{
+		ID:                   id,
+		UserID:               100,
+		OriginStationID:      60003760,
+		DestinationStationID: 60008494,
+		Status:               "planned",
+		TotalVolumeM3:        300000,
+		TotalCollateral:      2000000000,
+		FulfillmentType:      "courier_contract",
+		CreatedAt:            time.Now().Add(-2 * time.Hour),
+	}
+}
+
+func courierContract(id int64, status string) *client.EsiContract {
+	return &client.EsiContract{
+		ContractID:      id,
+		IssuerID:        2001,
+		Type:            "courier",
+		Status:          status,
+		DateIssued:      time.Now().Add(-1 * time.Hour).Format(time.RFC3339),
+		DateExpired:     time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+		StartLocationID: 60003760,
+		EndLocationID:   60008494,
+		Volume:          300000,
+		Collateral:      2000000000,
+	}
+}
+
+func syncCourierContracts(t *testing.T, jobsRepo *mockCourierContractJobsRepo, contracts ...*client.EsiContract) *mockCourierContractNotifier {
+	t.Helper()
+
+	esiClient := &mockContractSyncEsiClient{
+		contractsByChar: map[int64][]*client.EsiContract{2001: contracts},
+	}
+	notifier := &mockCourierContractNotifier{}
+
+	syncer := updaters.NewCourierContractSync(jobsRepo, courierCharRepo(), emptyCorpRepo(), esiClient)
+	syncer.WithNotifier(notifier)
+	require.NoError(t, syncer.SyncAll(context.Background()))
+
+	return notifier
+}
+
+// --- Tests ---
+
+func Test_CourierContractSync_LinksByTitleReference(t *testing.T) {
+	jobsRepo := &mockCourierContractJobsRepo{jobs: []*models.TransportJob{courierJob(42), courierJob(4)}}
+
+	contract := courierContract(9001, "outstanding")
+	contract.Title = "Minerals TJ-42"
+	contract.Volume = 1000
+
+	syncCourierContracts(t, jobsRepo, contract)
+
+	require.Len(t, jobsRepo.updates, 1)
+	assert.Equal(t, courierContractUpdate{JobID: 42, EveContractID: 9001, ContractStatus: "outstanding", Status: "planned"}, jobsRepo.updates[0])
+}
+
+func Test_CourierContractSync_LinksByRouteVolumeAndCollateral(t *testing.T) {
+	jobsRepo := &mockCourierContractJobsRepo{jobs: []*models.TransportJob{courierJob(1)}}
+
+	contract := courierContract(9001, "in_progress")
+	contract.Volume = 295000
+
+	syncCourierContracts(t, jobsRepo, contract)
+
+	require.Len(t, jobsRepo.updates, 1)
+	assert.Equal(t, courierContractUpdate{JobID: 1, EveContractID: 9001, ContractStatus: "in_progress", Status: "in_transit"}, jobsRepo.updates[0])
+}
+
+func Test_CourierContractSync_IgnoresRouteMismatches(t *testing.T) {
+	jobsRepo := &mockCourierContractJobsRepo{jobs: []*models.TransportJob{courierJob(1)}}
+
+	otherStation := courierContract(9001, "outstanding")
+	otherStation.EndLocationID = 60011866
+	wrongCollateral := courierContract(9002, "outstanding")
+	wrongCollateral.Collateral = 500000000
+	issuedBeforeJob := courierContract(9003, "finished")
+	issuedBeforeJob.DateIssued = time.Now().Add(-48 * time.Hour).Format(time.RFC3339)
+	notCourier := courierContract(9004, "outstanding")
+	notCourier.Type = "item_exchange"
+	otherIssuer := courierContract(9005, "outstanding")
+	otherIssuer.IssuerID = 3003
+
+	syncCourierContracts(t, jobsRepo, otherStation, wrongCollateral, issuedBeforeJob, notCourier, otherIssuer)
+
+	assert.Empty(t, jobsRepo.updates)
+}
+
+func Test_CourierContractSync_AdvancesLinkedJobToDelivered(t *testing.T) {
+	job := courierJob(1)
+	job.Status = "in_transit"
+	contractID := int64(9001)
+	job.EveContractID = &contractID
+	job.ContractStatus = strPtr("in_progress")
+	jobsRepo := &mockCourierContractJobsRepo{jobs: []*models.TransportJob{job}}
+
+	// The linked contract wins over a newer one on the same route
+	syncCourierContracts(t, jobsRepo, courierContract(9001, "finished"), courierContract(9002, "outstanding"))
+
+	require.Len(t, jobsRepo.updates, 1)
+	assert.Equal(t, courierContractUpdate{JobID: 1, EveContractID: 9001, ContractStatus: "finished", Status: "delivered"}, jobsRepo.updates[0])
+}
+
+func Test_CourierContractSync_SkipsUnchangedJobs(t *testing.T) {
+	job := courierJob(1)
+	contractID := int64(9001)
+	job.EveContractID = &contractID
+	job.ContractStatus = strPtr("outstanding")
+	jobsRepo := &mockCourierContractJobsRepo{jobs: []*models.TransportJob{job}}
+
+	notifier := syncCourierContracts(t, jobsRepo, courierContract(9001, "outstanding"))
+
+	assert.Empty(t, jobsRepo.updates)
+	assert.Empty(t, notifier.alerts)
+}
+
+func Test_CourierContractSync_AlertsOnceWhenContractFails(t *testing.T) {
+	job := courierJob(1)
+	job.Status = "in_transit"
+	contractID := int64(9001)
+	job.EveContractID = &contractID
+	job.ContractStatus = strPtr("in_progress")
+	jobsRepo := &mockCourierContractJobsRepo{jobs: []*models.TransportJob{job}}
+
+	notifier := syncCourierContracts(t, jobsRepo, courierContract(9001, "failed"))
+
+	require.Len(t, jobsRepo.updates, 1)
+	assert.Equal(t, courierContractUpdate{JobID: 1, EveContractID: 9001, ContractStatus: "failed", Status: "in_transit"}, jobsRepo.updates[0])
+	require.Len(t, notifier.alerts, 1)
+	assert.Equal(t, "failed", notifier.alerts[0].ContractStatus)
+	assert.Equal(t, int64(1), notifier.alerts[0].Job.ID)
+
+	// Already recorded as failed: no second alert
+	job.ContractStatus = strPtr("failed")
+	jobsRepo.updates = nil
+	notifier = syncCourierContracts(t, jobsRepo, courierContract(9001, "failed"))
+
+	assert.Empty(t, jobsRepo.updates)
+	assert.Empty(t, notifier.alerts)
+}
+
+func Test_CourierContractSync_AlertsWhenOutstandingContractExpires(t *testing.T) {
+	job := courierJob(1)
+	contractID := int64(9001)
+	job.EveContractID = &contractID
+	job.ContractStatus = strPtr("outstanding")
+	jobsRepo := &mockCourierContractJobsRepo{jobs: []*models.TransportJob{job}}
+
+	contract := courierContract(9001, "outstanding")
+	contract.DateExpired = time.Now().Add(-1 * time.Hour).Format(time.RFC3339)
+
+	notifier := syncCourierContracts(t, jobsRepo, contract)
+
+	require.Len(t, jobsRepo.updates, 1)
+	assert.Equal(t, "expired", jobsRepo.updates[0].ContractStatus)
+	assert.Equal(t, "planned", jobsRepo.updates[0].Status)
+	require.Len(t, notifier.alerts, 1)
+	assert.Equal(t, "expired", notifier.alerts[0].ContractStatus)
+}
+
+func Test_CourierContractSync_RelinksReissuedContract(t *testing.T) {
+	job := courierJob(1)
+	contractID := int64(9001)
+	job.EveContractID = &contractID
+	job.ContractStatus = strPtr("expired")
+	jobsRepo := &mockCourierContractJobsRepo{jobs: []*models.TransportJob{job}}
+
+	expired := courierContract(9001, "outstanding")
+	expired.DateExpired = time.Now().Add(-1 * time.Hour).Format(time.RFC3339)
+
+	notifier := syncCourierContracts(t, jobsRepo, expired, courierContract(9002, "outstanding"))
+
+	require.Len(t, jobsRepo.updates, 1)
+	assert.Equal(t, courierContractUpdate{JobID: 1, EveContractID: 9002, ContractStatus: "outstanding", Status: "planned"}, jobsRepo.updates[0])
+	assert.Empty(t, notifier.alerts)
+}
+
+func Test_CourierContractSync_ClaimsEachContractOnce(t *testing.T) {
+	jobsRepo := &mockCourierContractJobsRepo{jobs: []*models.TransportJob{courierJob(1), courierJob(2)}}
+
+	syncCourierContracts(t, jobsRepo, courierContract(9001, "outstanding"))
+
+	require.Len(t, jobsRepo.updates, 1)
+	assert.Equal(t, int64(9001), jobsRepo.updates[0].EveContractID)
+}
+
+func Test_CourierContractSync_MatchesCorporationContracts(t *testing.T) {
+	jobsRepo := &mockCourierContractJobsRepo{jobs: []*models.TransportJob{courierJob(1)}}
+
+	corpRepo := &mockContractSyncCorpRepo{
+		corpsByUser: map[int64][]repositories.PlayerCorporation{
+			100: {
+				{ID: 5001, EsiToken: "tok", EsiRefreshToken: "ref",
+					EsiExpiresOn: time.Now().Add(1 * time.Hour),
+					EsiScopes:    "esi-contracts.read_corporation_contracts.v1"},
+			},
+		},
+	}
+	contract := courierContract(9001, "in_progress")
+	contract.IssuerID = 7777
+	contract.IssuerCorporationID = 5001
+	contract.ForCorporation = true
+	esiClient := &mockContractSyncEsiClient{
+		contractsByCorp: map[int64][]*client.EsiContract{5001: {contract}},
+	}
+
+	syncer := updaters.NewCourierContractSync(jobsRepo, &mockContractSyncCharRepo{}, corpRepo, esiClient)
+	require.NoError(t, syncer.SyncAll(context.Background()))
+
+	require.Len(t, jobsRepo.updates, 1)
+	assert.Equal(t, "in_transit", jobsRepo.updates[0].Status)
+}
+
+func Test_CourierContractSync_ReturnsJobsError(t *testing.T) {
+	jobsRepo := &mockCourierContractJobsRepo{jobsErr: errors.New("db down")}
+
+	syncer := updaters.NewCourierContractSync(jobsRepo, &mockContractSyncCharRepo{}, emptyCorpRepo(), &mockContractSyncEsiClient{})
+	err := syncer.SyncAll(context.Background())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get open courier jobs")
+}
@@ -0,0 +1,290 @@
+package updaters
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/calculator"
+	"github.com/annymsMthd/industry-tool/internal/client"
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/pkg/errors"
+)
+
+type InvoicesRepository interface {
+	GetOpen(ctx context.Context) ([]*models.Invoice, error)
+	RecordPayment(ctx context.Context, payment *models.InvoicePayment) (float64, bool, error)
+	UpdateStatus(ctx context.Context, invoiceID int64, status string, paidAt *time.Time) error
+	MarkReminded(ctx context.Context, invoiceID int64, remindedAt time.Time) error
+}
+
+type InvoicesCharacterRepository interface {
+	GetAll(ctx context.Context, userID int64) ([]*repositories.Character, error)
+	UpdateTokens(ctx context.Context, id, userID int64, token, refreshToken string, expiresOn time.Time) error
+}
+
+type InvoicesEsiClient interface {
+	GetCharacterWalletJournal(ctx context.Context, characterID int64, token string) ([]*client.WalletJournalEntry, error)
+	RefreshAccessToken(ctx context.Context, refreshToken string) (*client.RefreshedToken, error)
+}
+
+// InvoiceNotifier is told when an invoice is paid, becomes overdue or needs a reminder
+type InvoiceNotifier interface {
+	NotifyInvoice(ctx context.Context, invoice *models.Invoice, event string)
+}
+
+// invoicePaymentRefTypes are the journal entry types that move ISK from one
+// player to another: a direct transfer, or a transfer out of a corp wallet.
+var invoicePaymentRefTypes = map[string]bool{
+	"player_donation":                true,
+	"corporation_account_withdrawal": true,
+}
+
+// overdueReminderInterval is how often the payer of an overdue invoice is reminded again.
+const overdueReminderInterval = 24 * time.Hour
+
+// InvoicesUpdater matches incoming ISK in the issuers' wallet journals to open
+// invoices, moves them between unpaid, partial, paid and overdue, and sends reminders.
+type InvoicesUpdater struct {
+	invoiceRepo InvoicesRepository
+	charRepo    InvoicesCharacterRepository
+	esiClient   InvoicesEsiClient
+	notifier    InvoiceNotifier
+}
+
+func NewInvoices(
+	invoiceRepo InvoicesRepository,
+	charRepo InvoicesCharacterRepository,
+	esiClient InvoicesEsiClient,
+) *InvoicesUpdater {
+	return &InvoicesUpdater{
+		invoiceRepo: invoiceRepo,
+		charRepo:    charRepo,
+		esiClient:   esiClient,
+	}
+}
+
+// WithNotifier sets the optional notifier for paid, overdue and reminder events.
+func (u *InvoicesUpdater) WithNotifier(notifier InvoiceNotifier) {
+	u.notifier = notifier
+}
+
+// UpdateAll processes every open invoice, grouped by issuer so each issuer's
+// wallet journals are fetched once.
+func (u *InvoicesUpdater) UpdateAll(ctx context.Context) error {
+	invoices, err := u.invoiceRepo.GetOpen(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get open invoices")
+	}
+
+	if len(invoices) == 0 {
+		return nil
+	}
+
+	byIssuer := map[int64][]*models.Invoice{}
+	issuers := []int64{}
+	for _, invoice := range invoices {
+		if _, ok := byIssuer[invoice.IssuerUserID]; !ok {
+			issuers = append(issuers, invoice.IssuerUserID)
+		}
+		byIssuer[invoice.IssuerUserID] = append(byIssuer[invoice.IssuerUserID], invoice)
+	}
+
+	for _, issuerUserID := range issuers {
+		if err := u.updateIssuer(ctx, issuerUserID, byIssuer[issuerUserID]); err != nil {
+			log.Error("invoices: failed to update invoices for issuer", "userID", issuerUserID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (u *InvoicesUpdater) updateIssuer(ctx context.Context, issuerUserID int64, invoices []*models.Invoice) error {
+	characters, err := u.charRepo.GetAll(ctx, issuerUserID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get issuer characters")
+	}
+
+	payerCharacters := map[int64]map[int64]bool{}
+	for _, invoice := range invoices {
+		if _, ok := payerCharacters[invoice.PayerUserID]; ok {
+			continue
+		}
+		chars, err := u.charRepo.GetAll(ctx, invoice.PayerUserID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get payer characters")
+		}
+		ids := map[int64]bool{}
+		for _, c := range chars {
+			ids[c.ID] = true
+		}
+		payerCharacters[invoice.PayerUserID] = ids
+	}
+
+	for _, char := range characters {
+		if !strings.Contains(char.EsiScopes, "esi-wallet.read_character_wallet.v1") {
+			continue
+		}
+
+		token := char.EsiToken
+		if time.Now().After(char.EsiTokenExpiresOn) {
+			refreshed, err := u.esiClient.RefreshAccessToken(ctx, char.EsiRefreshToken)
+			if err != nil {
+				log.Error("invoices: failed to refresh token for character", "characterID", char.ID, "error", err)
+				continue
+			}
+			token = refreshed.AccessToken
+			if err := u.charRepo.UpdateTokens(ctx, char.ID, char.UserID, refreshed.AccessToken, refreshed.RefreshToken, refreshed.Expiry); err != nil {
+				log.Error("invoices: failed to persist refreshed token for character", "characterID", char.ID, "error", err)
+			}
+		}
+
+		entries, err := u.esiClient.GetCharacterWalletJournal(ctx, char.ID, token)
+		if err != nil {
+			log.Error("invoices: failed to get character wallet journal", "characterID", char.ID, "error", err)
+			continue
+		}
+
+		for _, entry := range entries {
+			u.applyJournalEntry(ctx, char.ID, entry, invoices, payerCharacters)
+		}
+	}
+
+	now := time.Now()
+	for _, invoice := range invoices {
+		u.updateStatus(ctx, invoice, now)
+	}
+
+	return nil
+}
+
+// applyJournalEntry records an incoming transfer against the invoice it pays.
+// A transfer matches the invoice whose reference code is in its reason. A
+// transfer with no reference code matches when it comes from one of the
+// payer's characters for exactly the outstanding amount of a single invoice.
+func (u *InvoicesUpdater) applyJournalEntry(
+	ctx context.Context,
+	characterID int64,
+	entry *client.WalletJournalEntry,
+	invoices []*models.Invoice,
+	payerCharacters map[int64]map[int64]bool,
+) {
+	if entry.Amount == nil || *entry.Amount <= 0 || !invoicePaymentRefTypes[entry.RefType] {
+		return
+	}
+
+	date, err := time.Parse(time.RFC3339, entry.Date)
+	if err != nil {
+		log.Error("invoices: failed to parse wallet journal date", "journalRefID", entry.ID, "error", err)
+		return
+	}
+
+	var match *models.Invoice
+	for _, invoice := range invoices {
+		if calculator.ReasonHasReference(entry.Reason, invoice.ReferenceCode) {
+			match = invoice
+			break
+		}
+	}
+
+	// A reason naming some other invoice is never matched on amount alone
+	otherReference := strings.Contains(strings.ToUpper(entry.Reason), calculator.InvoiceReferencePrefix)
+	if match == nil && entry.FirstPartyID != nil && !otherReference {
+		candidates := []*models.Invoice{}
+		for _, invoice := range invoices {
+			if !payerCharacters[invoice.PayerUserID][*entry.FirstPartyID] {
+				continue
+			}
+			outstanding := invoice.Amount - invoice.AmountPaid
+			if math.Abs(outstanding-*entry.Amount) <= calculator.InvoicePaidTolerance {
+				candidates = append(candidates, invoice)
+			}
+		}
+		if len(candidates) == 1 {
+			match = candidates[0]
+		}
+	}
+
+	if match == nil || date.Before(match.CreatedAt) {
+		return
+	}
+
+	amountPaid, recorded, err := u.invoiceRepo.RecordPayment(ctx, &models.InvoicePayment{
+		InvoiceID:    match.ID,
+		JournalRefID: entry.ID,
+		CharacterID:  characterID,
+		Amount:       *entry.Amount,
+		PaidAt:       date,
+	})
+	if err != nil {
+		log.Error("invoices: failed to record payment", "invoiceID", match.ID, "journalRefID", entry.ID, "error", err)
+		return
+	}
+	if !recorded {
+		return
+	}
+
+	match.AmountPaid = amountPaid
+	log.Info("invoices: recorded payment",
+		"invoiceID", match.ID, "journalRefID", entry.ID, "amount", *entry.Amount, "amountPaid", amountPaid)
+}
+
+// updateStatus stores an invoice's new status and sends the matching
+// notification: paid, newly overdue, a daily reminder while overdue, or a
+// single reminder shortly before the due date.
+func (u *InvoicesUpdater) updateStatus(ctx context.Context, invoice *models.Invoice, now time.Time) {
+	status := calculator.InvoiceStatus(invoice.Amount, invoice.AmountPaid, invoice.DueAt, now)
+
+	if status != invoice.Status {
+		var paidAt *time.Time
+		if status == "paid" {
+			paidAt = &now
+		}
+		if err := u.invoiceRepo.UpdateStatus(ctx, invoice.ID, status, paidAt); err != nil {
+			log.Error("invoices: failed to update status", "invoiceID", invoice.ID, "status", status, "error", err)
+			return
+		}
+		log.Info("invoices: status changed", "invoiceID", invoice.ID, "from", invoice.Status, "to", status)
+		invoice.Status = status
+		invoice.PaidAt = paidAt
+
+		switch status {
+		case "paid":
+			u.notify(ctx, invoice, "paid")
+			return
+		case "overdue":
+			u.notify(ctx, invoice, "overdue")
+			u.markReminded(ctx, invoice, now)
+			return
+		}
+	}
+
+	remind := false
+	switch status {
+	case "overdue":
+		remind = invoice.RemindedAt == nil || now.Sub(*invoice.RemindedAt) >= overdueReminderInterval
+	case "unpaid", "partial":
+		remind = invoice.RemindedAt == nil && invoice.DueAt.Sub(now) <= calculator.InvoiceReminderLead
+	}
+	if remind {
+		u.notify(ctx, invoice, "reminder")
+		u.markReminded(ctx, invoice, now)
+	}
+}
+
+func (u *InvoicesUpdater) markReminded(ctx context.Context, invoice *models.Invoice, now time.Time) {
+	if err := u.invoiceRepo.MarkReminded(ctx, invoice.ID, now); err != nil {
+		log.Error("invoices: failed to mark reminded", "invoiceID", invoice.ID, "error", err)
+		return
+	}
+	invoice.RemindedAt = &now
+}
+
+func (u *InvoicesUpdater) notify(ctx context.Context, invoice *models.Invoice, event string) {
+	if u.notifier != nil {
+		u.notifier.NotifyInvoice(ctx, invoice, event)
+	}
+}
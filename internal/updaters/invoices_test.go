@@ -0,0 +1,289 @@
+package updaters_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/client"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/updaters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mocks for invoices ---
+
+type MockInvoicesRepo struct {
+	mock.Mock
+}
+
+func (m *MockInvoicesRepo) GetOpen(ctx context.Context) ([]*models.Invoice, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Invoice), args.Error(1)
+}
+
+func (m *MockInvoicesRepo) RecordPayment(ctx context.Context, payment *models.InvoicePayment) (float64, bool, error) {
+	args := m.Called(ctx, payment)
+	return args.Get(0).(float64), args.Bool(1), args.Error(2)
+}
+
+func (m *MockInvoicesRepo) UpdateStatus(ctx context.Context, invoiceID int64, status string, paidAt *time.Time) error {
+	args := m.Called(ctx, invoiceID, status, paidAt)
+	return args.Error(0)
+}
+
+func (m *MockInvoicesRepo) MarkReminded(ctx context.Context, invoiceID int64, remindedAt time.Time) error {
+	args := m.Called(ctx, invoiceID, remindedAt)
+	return args.Error(0)
+}
+
+type MockInvoicesEsiClient struct {
+	mock.Mock
+}
+
+func (m *MockInvoicesEsiClient) GetCharacterWalletJournal(ctx context.Context, characterID int64, token string) ([]*client.WalletJournalEntry, error) {
+	args := m.Called(ctx, characterID, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*client.WalletJournalEntry), args.Error(1)
+}
+
+func (m *MockInvoicesEsiClient) RefreshAccessToken(ctx context.Context, refreshToken string) (*client.RefreshedToken, error) {
+	args := m.Called(ctx, refreshToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*client.RefreshedToken), args.Error(1)
+}
+
+type MockInvoiceNotifier struct {
+	mock.Mock
+}
+
+func (m *MockInvoiceNotifier) NotifyInvoice(ctx context.Context, invoice *models.Invoice, event string) {
+	m.Called(ctx, invoice, event)
+}
+
+func setupInvoicesUpdater() (*updaters.InvoicesUpdater, *MockInvoicesRepo, *MockHaulingWalletTxCharacterRepo, *MockInvoicesEsiClient, *MockInvoiceNotifier) {
+	repo := new(MockInvoicesRepo)
+	charRepo := new(MockHaulingWalletTxCharacterRepo)
+	esiClient := new(MockInvoicesEsiClient)
+	notifier := new(MockInvoiceNotifier)
+
+	updater := updaters.NewInvoices(repo, charRepo, esiClient)
+	updater.WithNotifier(notifier)
+	return updater, repo, charRepo, esiClient, notifier
+}
+
+func journalDonation(id int64, amount float64, fromCharID int64, reason string, date time.Time) *client.WalletJournalEntry {
+	return &client.WalletJournalEntry{
+		ID:           id,
+		Date:         date.UTC().Format(time.RFC3339),
+		RefType:      "player_donation",
+		Amount:       &amount,
+		FirstPartyID: &fromCharID,
+		Reason:       reason,
+	}
+}
+
+const (
+	invoiceIssuerUserID = int64(100)
+	invoiceIssuerCharID = int64(1001)
+	invoicePayerUserID  = int64(200)
+	invoicePayerCharID  = int64(2001)
+)
+
+func makeOpenInvoice(id int64, reference string, amount float64, dueAt time.Time) *models.Invoice {
+	return &models.Invoice{
+		ID:            id,
+		IssuerUserID:  invoiceIssuerUserID,
+		PayerUserID:   invoicePayerUserID,
+		ReferenceCode: reference,
+		Amount:        amount,
+		DueAt:         dueAt,
+		Status:        "unpaid",
+		CreatedAt:     time.Now().Add(-48 * time.Hour),
+	}
+}
+
+func expectInvoiceCharacters(charRepo *MockHaulingWalletTxCharacterRepo) {
+	charRepo.On("GetAll", mock.Anything, invoiceIssuerUserID).Return([]*repositories.Character{
+		makeCharWithWalletScope(invoiceIssuerCharID, invoiceIssuerUserID),
+	}, nil)
+	charRepo.On("GetAll", mock.Anything, invoicePayerUserID).Return([]*repositories.Character{
+		{ID: invoicePayerCharID, UserID: invoicePayerUserID},
+	}, nil)
+}
+
+// --- Tests ---
+
+func Test_Invoices_UpdateAll_NoOpenInvoices(t *testing.T) {
+	updater, repo, charRepo, _, _ := setupInvoicesUpdater()
+
+	repo.On("GetOpen", mock.Anything).Return([]*models.Invoice{}, nil)
+
+	err := updater.UpdateAll(context.Background())
+	assert.NoError(t, err)
+	charRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+}
+
+func Test_Invoices_UpdateAll_ReferencePaymentsAddUpToPaid(t *testing.T) {
+	updater, repo, charRepo, esiClient, notifier := setupInvoicesUpdater()
+
+	invoice := makeOpenInvoice(1, "INV-7KQ2MX", 100_000_000, time.Now().Add(72*time.Hour))
+	repo.On("GetOpen", mock.Anything).Return([]*models.Invoice{invoice}, nil)
+	expectInvoiceCharacters(charRepo)
+
+	esiClient.On("GetCharacterWalletJournal", mock.Anything, invoiceIssuerCharID, "valid-token").Return([]*client.WalletJournalEntry{
+		journalDonation(501, 60_000_000, 9999, "slots inv-7kq2mx part 1", time.Now().Add(-2*time.Hour)),
+		journalDonation(502, 40_000_000, 9999, "INV-7KQ2MX rest", time.Now().Add(-time.Hour)),
+	}, nil)
+
+	repo.On("RecordPayment", mock.Anything, mock.MatchedBy(func(p *models.InvoicePayment) bool {
+		return p.JournalRefID == 501 && p.InvoiceID == 1 && p.Amount == 60_000_000 && p.CharacterID == invoiceIssuerCharID
+	})).Return(60_000_000.0, true, nil)
+	repo.On("RecordPayment", mock.Anything, mock.MatchedBy(func(p *models.InvoicePayment) bool {
+		return p.JournalRefID == 502
+	})).Return(100_000_000.0, true, nil)
+	repo.On("UpdateStatus", mock.Anything, int64(1), "paid", mock.AnythingOfType("*time.Time")).Return(nil)
+	notifier.On("NotifyInvoice", mock.Anything, invoice, "paid").Return()
+
+	err := updater.UpdateAll(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "paid", invoice.Status)
+	assert.NotNil(t, invoice.PaidAt)
+	repo.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
+
+func Test_Invoices_UpdateAll_AlreadyRecordedPaymentKeepsPartial(t *testing.T) {
+	updater, repo, charRepo, esiClient, notifier := setupInvoicesUpdater()
+
+	invoice := makeOpenInvoice(1, "INV-7KQ2MX", 100_000_000, time.Now().Add(72*time.Hour))
+	invoice.Status = "partial"
+	invoice.AmountPaid = 60_000_000
+	repo.On("GetOpen", mock.Anything).Return([]*models.Invoice{invoice}, nil)
+	expectInvoiceCharacters(charRepo)
+
+	esiClient.On("GetCharacterWalletJournal", mock.Anything, invoiceIssuerCharID, "valid-token").Return([]*client.WalletJournalEntry{
+		journalDonation(501, 60_000_000, 9999, "INV-7KQ2MX", time.Now().Add(-2*time.Hour)),
+	}, nil)
+	repo.On("RecordPayment", mock.Anything, mock.Anything).Return(0.0, false, nil)
+
+	err := updater.UpdateAll(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, 60_000_000.0, invoice.AmountPaid)
+	repo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	notifier.AssertNotCalled(t, "NotifyInvoice", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_Invoices_UpdateAll_MatchesExactAmountFromPayerWithoutReference(t *testing.T) {
+	updater, repo, charRepo, esiClient, notifier := setupInvoicesUpdater()
+
+	invoice := makeOpenInvoice(1, "INV-7KQ2MX", 25_000_000, time.Now().Add(72*time.Hour))
+	repo.On("GetOpen", mock.Anything).Return([]*models.Invoice{invoice}, nil)
+	expectInvoiceCharacters(charRepo)
+
+	esiClient.On("GetCharacterWalletJournal", mock.Anything, invoiceIssuerCharID, "valid-token").Return([]*client.WalletJournalEntry{
+		// Right amount but from a stranger
+		journalDonation(601, 25_000_000, 9999, "", time.Now().Add(-3*time.Hour)),
+		// From the payer but names another invoice
+		journalDonation(602, 25_000_000, invoicePayerCharID, "INV-AAAAAA", time.Now().Add(-2*time.Hour)),
+		// From the payer for the outstanding amount
+		journalDonation(603, 25_000_000, invoicePayerCharID, "thanks!", time.Now().Add(-time.Hour)),
+	}, nil)
+
+	repo.On("RecordPayment", mock.Anything, mock.MatchedBy(func(p *models.InvoicePayment) bool {
+		return p.JournalRefID == 603
+	})).Return(25_000_000.0, true, nil)
+	repo.On("UpdateStatus", mock.Anything, int64(1), "paid", mock.AnythingOfType("*time.Time")).Return(nil)
+	notifier.On("NotifyInvoice", mock.Anything, invoice, "paid").Return()
+
+	err := updater.UpdateAll(context.Background())
+	assert.NoError(t, err)
+
+	repo.AssertExpectations(t)
+	repo.AssertNumberOfCalls(t, "RecordPayment", 1)
+}
+
+func Test_Invoices_UpdateAll_IgnoresOutgoingAndEarlierTransfers(t *testing.T) {
+	updater, repo, charRepo, esiClient, _ := setupInvoicesUpdater()
+
+	invoice := makeOpenInvoice(1, "INV-7KQ2MX", 25_000_000, time.Now().Add(72*time.Hour))
+	repo.On("GetOpen", mock.Anything).Return([]*models.Invoice{invoice}, nil)
+	expectInvoiceCharacters(charRepo)
+
+	outgoing := journalDonation(701, -25_000_000, invoiceIssuerCharID, "INV-7KQ2MX", time.Now().Add(-time.Hour))
+	bounty := journalDonation(702, 25_000_000, invoicePayerCharID, "INV-7KQ2MX", time.Now().Add(-time.Hour))
+	bounty.RefType = "bounty_prizes"
+	earlier := journalDonation(703, 25_000_000, invoicePayerCharID, "INV-7KQ2MX", time.Now().Add(-72*time.Hour))
+
+	esiClient.On("GetCharacterWalletJournal", mock.Anything, invoiceIssuerCharID, "valid-token").Return([]*client.WalletJournalEntry{
+		outgoing, bounty, earlier,
+	}, nil)
+
+	err := updater.UpdateAll(context.Background())
+	assert.NoError(t, err)
+
+	repo.AssertNotCalled(t, "RecordPayment", mock.Anything, mock.Anything)
+}
+
+func Test_Invoices_UpdateAll_OverdueNotifiesOnceThenRemindsDaily(t *testing.T) {
+	updater, repo, charRepo, esiClient, notifier := setupInvoicesUpdater()
+
+	newlyOverdue := makeOpenInvoice(1, "INV-AAAAAA", 10_000_000, time.Now().Add(-time.Hour))
+	stillOverdue := makeOpenInvoice(2, "INV-BBBBBB", 10_000_000, time.Now().Add(-72*time.Hour))
+	stillOverdue.Status = "overdue"
+	recently := time.Now().Add(-2 * time.Hour)
+	stillOverdue.RemindedAt = &recently
+	remindAgain := makeOpenInvoice(3, "INV-CCCCCC", 10_000_000, time.Now().Add(-72*time.Hour))
+	remindAgain.Status = "overdue"
+	yesterday := time.Now().Add(-25 * time.Hour)
+	remindAgain.RemindedAt = &yesterday
+
+	repo.On("GetOpen", mock.Anything).Return([]*models.Invoice{newlyOverdue, stillOverdue, remindAgain}, nil)
+	expectInvoiceCharacters(charRepo)
+	esiClient.On("GetCharacterWalletJournal", mock.Anything, invoiceIssuerCharID, "valid-token").Return([]*client.WalletJournalEntry{}, nil)
+
+	repo.On("UpdateStatus", mock.Anything, int64(1), "overdue", (*time.Time)(nil)).Return(nil)
+	repo.On("MarkReminded", mock.Anything, int64(1), mock.Anything).Return(nil)
+	repo.On("MarkReminded", mock.Anything, int64(3), mock.Anything).Return(nil)
+	notifier.On("NotifyInvoice", mock.Anything, newlyOverdue, "overdue").Return()
+	notifier.On("NotifyInvoice", mock.Anything, remindAgain, "reminder").Return()
+
+	err := updater.UpdateAll(context.Background())
+	assert.NoError(t, err)
+
+	repo.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+	notifier.AssertNumberOfCalls(t, "NotifyInvoice", 2)
+}
+
+func Test_Invoices_UpdateAll_RemindsOnceBeforeDue(t *testing.T) {
+	updater, repo, charRepo, esiClient, notifier := setupInvoicesUpdater()
+
+	dueSoon := makeOpenInvoice(1, "INV-AAAAAA", 10_000_000, time.Now().Add(6*time.Hour))
+	dueLater := makeOpenInvoice(2, "INV-BBBBBB", 10_000_000, time.Now().Add(72*time.Hour))
+
+	repo.On("GetOpen", mock.Anything).Return([]*models.Invoice{dueSoon, dueLater}, nil)
+	expectInvoiceCharacters(charRepo)
+	esiClient.On("GetCharacterWalletJournal", mock.Anything, invoiceIssuerCharID, "valid-token").Return([]*client.WalletJournalEntry{}, nil)
+
+	repo.On("MarkReminded", mock.Anything, int64(1), mock.Anything).Return(nil)
+	notifier.On("NotifyInvoice", mock.Anything, dueSoon, "reminder").Return()
+
+	err := updater.UpdateAll(context.Background())
+	assert.NoError(t, err)
+
+	repo.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+	notifier.AssertNumberOfCalls(t, "NotifyInvoice", 1)
+}
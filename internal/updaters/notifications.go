@@ -3,6 +3,8 @@ package updaters
 import (
 	"context"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/annymsMthd/industry-tool/internal/client"
@@ -22,6 +24,12 @@ type ContractCreatedNotifier interface {
 	NotifyContractCreated(ctx context.Context, purchase *models.PurchaseTransaction)
 }
 
+// PurchaseOrderNotifier is the interface used by the purchase cart controller
+type PurchaseOrderNotifier interface {
+	NotifyPurchaseOrder(ctx context.Context, order *models.PurchaseOrder)
+	NotifyOrderContractCreated(ctx context.Context, order *models.PurchaseOrder)
+}
+
 // PiStallNotifier is the interface used by the PI updater
 type PiStallNotifier interface {
 	NotifyPiStalls(ctx context.Context, userID int64, alerts []*PiStallAlert)
@@ -148,6 +156,97 @@ func (u *NotificationsUpdater) NotifyContractCreated(ctx context.Context, purcha
 	}
 }
 
+// NotifyPurchaseOrder sends notifications to the seller when a buyer checks out an order
+func (u *NotificationsUpdater) NotifyPurchaseOrder(ctx context.Context, order *models.PurchaseOrder) {
+	u.notifyTargets(ctx, order.SellerUserID, "purchase_created", buildPurchaseOrderEmbed(order))
+}
+
+// NotifyOrderContractCreated sends notifications to the buyer when a seller creates the contract
+// for an order
+func (u *NotificationsUpdater) NotifyOrderContractCreated(ctx context.Context, order *models.PurchaseOrder) {
+	u.notifyTargets(ctx, order.BuyerUserID, "contract_created", buildOrderContractCreatedEmbed(order))
+}
+
+// NotifyContractDisputed sends notifications to both the buyer and the seller when a finished
+// contract does not match what was bought
+func (u *NotificationsUpdater) NotifyContractDisputed(ctx context.Context, dispute *ContractDispute) {
+	embed := buildContractDisputedEmbed(dispute)
+	u.notifyTargets(ctx, dispute.BuyerUserID, "contract_disputed", embed)
+	u.notifyTargets(ctx, dispute.SellerUserID, "contract_disputed", embed)
+}
+
+// NotifyPurchaseExpired sends notifications to both the buyer and the seller when a purchase
+// misses the seller's SLA and is expired
+func (u *NotificationsUpdater) NotifyPurchaseExpired(ctx context.Context, purchase *models.PurchaseTransaction, expiredFrom string) {
+	embed := buildPurchaseExpiredEmbed(purchase, expiredFrom)
+	u.notifyTargets(ctx, purchase.BuyerUserID, "purchase_expired", embed)
+	u.notifyTargets(ctx, purchase.SellerUserID, "purchase_expired", embed)
+}
+
+// NotifyPurchaseOffer tells the other side of an offer that it was proposed, countered,
+// accepted, rejected or withdrawn
+func (u *NotificationsUpdater) NotifyPurchaseOffer(ctx context.Context, offer *models.PurchaseOffer, actorUserID int64, action string) {
+	recipientUserID := offer.SellerUserID
+	if actorUserID == offer.SellerUserID {
+		recipientUserID = offer.BuyerUserID
+	}
+
+	embed := buildPurchaseOfferEmbed(offer, actorUserID, action)
+	u.notifyTargets(ctx, recipientUserID, "purchase_offer", embed)
+}
+
+// NotifyInvoice sends invoice events: paid goes to both sides, overdue goes to
+// both sides, and reminders go to the payer only
+func (u *NotificationsUpdater) NotifyInvoice(ctx context.Context, invoice *models.Invoice, event string) {
+	embed := buildInvoiceEmbed(invoice, event)
+	eventType := "invoice_" + event
+	u.notifyTargets(ctx, invoice.PayerUserID, eventType, embed)
+	if event != "reminder" {
+		u.notifyTargets(ctx, invoice.IssuerUserID, eventType, embed)
+	}
+}
+
+// NotifyCourierContractAlert tells a transport job's owner that its courier contract
+// failed or expired
+func (u *NotificationsUpdater) NotifyCourierContractAlert(ctx context.Context, alert *CourierContractAlert) {
+	u.notifyTargets(ctx, alert.Job.UserID, "courier_contract_alert", buildCourierContractAlertEmbed(alert))
+}
+
+// notifyTargets sends an embed to every active target of a user for an event
+func (u *NotificationsUpdater) notifyTargets(ctx context.Context, userID int64, eventType string, embed *client.DiscordEmbed) {
+	targets, err := u.repo.GetActiveTargetsForEvent(ctx, userID, eventType)
+	if err != nil {
+		log.Error("failed to get notification targets", "user_id", userID, "event", eventType, "error", err)
+		return
+	}
+
+	for _, target := range targets {
+		var sendErr error
+		switch target.TargetType {
+		case "dm":
+			link, err := u.repo.GetLinkByUser(ctx, target.UserID)
+			if err != nil || link == nil {
+				log.Error("failed to get discord link for DM target", "user_id", target.UserID, "error", err)
+				continue
+			}
+			sendErr = u.discordClient.SendDM(ctx, link.DiscordUserID, embed)
+		case "channel":
+			if target.ChannelID == nil {
+				log.Error("channel target has no channel_id", "target_id", target.ID)
+				continue
+			}
+			sendErr = u.discordClient.SendChannelMessage(ctx, *target.ChannelID, embed)
+		default:
+			log.Error("unknown target type", "target_type", target.TargetType, "target_id", target.ID)
+			continue
+		}
+
+		if sendErr != nil {
+			log.Error("failed to send notification", "target_id", target.ID, "target_type", target.TargetType, "event", eventType, "error", sendErr)
+		}
+	}
+}
+
 // SendTestNotification sends a test notification to verify a target is configured correctly
 func (u *NotificationsUpdater) SendTestNotification(ctx context.Context, target *models.DiscordNotificationTarget, discordLink *models.DiscordLink) error {
 	embed := &client.DiscordEmbed{
@@ -325,3 +424,321 @@ func buildPurchaseEmbed(purchase *models.PurchaseTransaction) *client.DiscordEmb
 		},
 	}
 }
+
+// orderItemsField lists the items of an order, one per line, trimmed to Discord's field limit
+func orderItemsField(order *models.PurchaseOrder) client.DiscordEmbedField {
+	lines := make([]string, 0, len(order.Purchases))
+	for _, purchase := range order.Purchases {
+		lines = append(lines, iskPrinter.Sprintf("%d × %s", purchase.QuantityPurchased, purchase.TypeName))
+	}
+
+	value := ""
+	for i, line := range lines {
+		if len(value)+len(line)+1 > 1000 {
+			value += fmt.Sprintf("…and %d more", len(lines)-i)
+			break
+		}
+		value += line + "\n"
+	}
+
+	return client.DiscordEmbedField{
+		Name:   fmt.Sprintf("Items (%d)", len(order.Purchases)),
+		Value:  value,
+		Inline: false,
+	}
+}
+
+func buildPurchaseOrderEmbed(order *models.PurchaseOrder) *client.DiscordEmbed {
+	fields := []client.DiscordEmbedField{orderItemsField(order)}
+	fields = append(fields, client.DiscordEmbedField{
+		Name:   "Total",
+		Value:  formatISK(order.TotalPrice),
+		Inline: true,
+	})
+
+	return &client.DiscordEmbed{
+		Title:       "New Order",
+		Description: fmt.Sprintf("**%s** checked out an order from your listings", order.BuyerName),
+		Color:       0x10b981, // Green for success/revenue
+		Fields:      fields,
+		Footer: &client.DiscordEmbedFooter{
+			Text: fmt.Sprintf("Pinky.Tools • %s", order.CreatedAt.UTC().Format("Jan 2, 2006 15:04 UTC")),
+		},
+	}
+}
+
+func buildOrderContractCreatedEmbed(order *models.PurchaseOrder) *client.DiscordEmbed {
+	fields := []client.DiscordEmbedField{orderItemsField(order)}
+	fields = append(fields, client.DiscordEmbedField{
+		Name:   "Total",
+		Value:  formatISK(order.TotalPrice),
+		Inline: true,
+	})
+
+	if order.ContractKey != nil && *order.ContractKey != "" {
+		fields = append(fields, client.DiscordEmbedField{
+			Name:   "Contract Key",
+			Value:  fmt.Sprintf("`%s`", *order.ContractKey),
+			Inline: false,
+		})
+	}
+
+	return &client.DiscordEmbed{
+		Title:       "Contract Created",
+		Description: fmt.Sprintf("**%s** has created a contract for your order", order.SellerName),
+		Color:       0x3b82f6, // Primary blue
+		Fields:      fields,
+		Footer: &client.DiscordEmbedFooter{
+			Text: fmt.Sprintf("Pinky.Tools • %s", time.Now().UTC().Format("Jan 2, 2006 15:04 UTC")),
+		},
+	}
+}
+
+func buildContractDisputedEmbed(dispute *ContractDispute) *client.DiscordEmbed {
+	order := &models.PurchaseOrder{Purchases: dispute.Purchases}
+	fields := []client.DiscordEmbedField{
+		{
+			Name:   "Problems",
+			Value:  "• " + strings.Join(dispute.Reasons, "\n• "),
+			Inline: false,
+		},
+		orderItemsField(order),
+		{
+			Name:   "Contract Key",
+			Value:  fmt.Sprintf("`%s`", dispute.ContractKey),
+			Inline: true,
+		},
+		{
+			Name:   "EVE Contract",
+			Value:  fmt.Sprintf("%d", dispute.EveContractID),
+			Inline: true,
+		},
+	}
+
+	return &client.DiscordEmbed{
+		Title:       "Contract Disputed",
+		Description: "A finished contract does not match the purchase it was made for",
+		Color:       0xef4444, // Red for alert
+		Fields:      fields,
+		Footer: &client.DiscordEmbedFooter{
+			Text: fmt.Sprintf("Pinky.Tools • %s", time.Now().UTC().Format("Jan 2, 2006 15:04 UTC")),
+		},
+	}
+}
+
+func buildPurchaseExpiredEmbed(purchase *models.PurchaseTransaction, expiredFrom string) *client.DiscordEmbed {
+	description := fmt.Sprintf("**%s** did not create a contract in time for **%s**'s purchase", purchase.SellerName, purchase.BuyerName)
+	if expiredFrom == "contract_created" {
+		description = fmt.Sprintf("**%s** did not accept **%s**'s contract in time", purchase.BuyerName, purchase.SellerName)
+	}
+
+	fields := []client.DiscordEmbedField{
+		{
+			Name:   "Item",
+			Value:  purchase.TypeName,
+			Inline: true,
+		},
+		{
+			Name:   "Quantity",
+			Value:  iskPrinter.Sprintf("%d", purchase.QuantityPurchased),
+			Inline: true,
+		},
+		{
+			Name:   "Total",
+			Value:  formatISK(purchase.TotalPrice),
+			Inline: true,
+		},
+	}
+
+	return &client.DiscordEmbed{
+		Title:       "Purchase Expired",
+		Description: description + ". The quantity has been returned to the listing.",
+		Color:       0xf59e0b, // Amber for warning
+		Fields:      fields,
+		Footer: &client.DiscordEmbedFooter{
+			Text: fmt.Sprintf("Pinky.Tools • %s", time.Now().UTC().Format("Jan 2, 2006 15:04 UTC")),
+		},
+	}
+}
+
+var purchaseOfferTitles = map[string]string{
+	"proposed":  "New Offer",
+	"countered": "Counter-Offer",
+	"accepted":  "Offer Accepted",
+	"rejected":  "Offer Rejected",
+	"withdrawn": "Offer Withdrawn",
+}
+
+func buildPurchaseOfferEmbed(offer *models.PurchaseOffer, actorUserID int64, action string) *client.DiscordEmbed {
+	actorName := offer.BuyerName
+	if actorUserID == offer.SellerUserID {
+		actorName = offer.SellerName
+	}
+
+	description := fmt.Sprintf("**%s** %s an offer", actorName, action)
+	switch action {
+	case "proposed":
+		description = fmt.Sprintf("**%s** made an offer", actorName)
+	case "accepted":
+		description = fmt.Sprintf("**%s** accepted the offer. It is now a pending purchase", actorName)
+	}
+
+	fields := []client.DiscordEmbedField{
+		{
+			Name:   "Item",
+			Value:  offer.TypeName,
+			Inline: true,
+		},
+		{
+			Name:   "Quantity",
+			Value:  iskPrinter.Sprintf("%d", offer.Quantity),
+			Inline: true,
+		},
+		{
+			Name:   "Price / Unit",
+			Value:  formatISK(offer.PricePerUnit),
+			Inline: true,
+		},
+		{
+			Name:   "Listed At",
+			Value:  formatISK(offer.ListPrice),
+			Inline: true,
+		},
+		{
+			Name:   "Total",
+			Value:  formatISK(float64(offer.Quantity) * offer.PricePerUnit),
+			Inline: true,
+		},
+	}
+
+	if len(offer.Rounds) > 0 {
+		if message := offer.Rounds[len(offer.Rounds)-1].Message; message != nil {
+			fields = append(fields, client.DiscordEmbedField{
+				Name:  "Message",
+				Value: *message,
+			})
+		}
+	}
+
+	color := 0x3b82f6 // Blue for an open negotiation
+	switch action {
+	case "accepted":
+		color = 0x10b981 // Green for a deal
+	case "rejected", "withdrawn":
+		color = 0x6b7280 // Gray for a closed negotiation
+	}
+
+	return &client.DiscordEmbed{
+		Title:       purchaseOfferTitles[action],
+		Description: description + ".",
+		Color:       color,
+		Fields:      fields,
+		Footer: &client.DiscordEmbedFooter{
+			Text: fmt.Sprintf("Pinky.Tools • %s", time.Now().UTC().Format("Jan 2, 2006 15:04 UTC")),
+		},
+	}
+}
+
+func buildInvoiceEmbed(invoice *models.Invoice, event string) *client.DiscordEmbed {
+	title := "Invoice Reminder"
+	description := fmt.Sprintf("**%s** has an invoice from **%s** due %s", invoice.PayerName, invoice.IssuerName, invoice.DueAt.UTC().Format("Jan 2, 2006 15:04 UTC"))
+	color := 0x3b82f6 // Primary blue
+	switch event {
+	case "paid":
+		title = "Invoice Paid"
+		description = fmt.Sprintf("**%s** paid **%s**'s invoice in full", invoice.PayerName, invoice.IssuerName)
+		color = 0x10b981 // Green for success
+	case "overdue":
+		title = "Invoice Overdue"
+		description = fmt.Sprintf("**%s**'s invoice from **%s** is past due", invoice.PayerName, invoice.IssuerName)
+		color = 0xf59e0b // Amber for warning
+	}
+
+	fields := []client.DiscordEmbedField{
+		{
+			Name:   "Reference",
+			Value:  invoice.ReferenceCode,
+			Inline: true,
+		},
+		{
+			Name:   "Amount",
+			Value:  formatISK(invoice.Amount),
+			Inline: true,
+		},
+		{
+			Name:   "Outstanding",
+			Value:  formatISK(math.Max(invoice.Amount-invoice.AmountPaid, 0)),
+			Inline: true,
+		},
+		{
+			Name:   "For",
+			Value:  invoice.Description,
+			Inline: false,
+		},
+	}
+
+	if event != "paid" {
+		description += fmt.Sprintf(". Send the outstanding ISK to any of %s's characters with `%s` as the reason", invoice.IssuerName, invoice.ReferenceCode)
+	}
+
+	return &client.DiscordEmbed{
+		Title:       title,
+		Description: description + ".",
+		Color:       color,
+		Fields:      fields,
+		Footer: &client.DiscordEmbedFooter{
+			Text: fmt.Sprintf("Pinky.Tools • %s", time.Now().UTC().Format("Jan 2, 2006 15:04 UTC")),
+		},
+	}
+}
+
+func buildCourierContractAlertEmbed(alert *CourierContractAlert) *client.DiscordEmbed {
+	job := alert.Job
+	title := "Courier Contract Expired"
+	description := "Nobody accepted the courier contract before it expired. Reissue it to keep the job moving."
+	if alert.ContractStatus == "failed" {
+		title = "Courier Contract Failed"
+		description = "The hauler failed to deliver the courier contract. The collateral is owed to you."
+	}
+
+	origin, destination := job.OriginSystemName, job.DestinationSystemName
+	if origin == "" {
+		origin = fmt.Sprintf("%d", job.OriginSystemID)
+	}
+	if destination == "" {
+		destination = fmt.Sprintf("%d", job.DestinationSystemID)
+	}
+
+	fields := []client.DiscordEmbedField{
+		{
+			Name:   "Route",
+			Value:  fmt.Sprintf("%s → %s", origin, destination),
+			Inline: false,
+		},
+		{
+			Name:   "Transport Job",
+			Value:  fmt.Sprintf("`%s`", job.ContractKey),
+			Inline: true,
+		},
+		{
+			Name:   "EVE Contract",
+			Value:  fmt.Sprintf("%d", alert.EveContractID),
+			Inline: true,
+		},
+		{
+			Name:   "Collateral",
+			Value:  formatISK(job.TotalCollateral),
+			Inline: true,
+		},
+	}
+
+	return &client.DiscordEmbed{
+		Title:       title,
+		Description: description,
+		Color:       0xef4444, // Red for alert
+		Fields:      fields,
+		Footer: &client.DiscordEmbedFooter{
+			Text: fmt.Sprintf("Pinky.Tools • %s", time.Now().UTC().Format("Jan 2, 2006 15:04 UTC")),
+		},
+	}
+}
@@ -3,6 +3,7 @@ package updaters_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -487,3 +488,204 @@ func Test_NotifyPiStalls_NoURL_WhenFrontendURLEmpty(t *testing.T) {
 	assert.Empty(t, capturedEmbed.Fields)
 	assert.NotContains(t, capturedEmbed.Description, "View PI")
 }
+
+func Test_NotifyPurchaseOrder_SendsChannelMessage(t *testing.T) {
+	mockRepo := new(MockNotificationsDiscordRepo)
+	mockClient := new(MockDiscordClient)
+
+	notifier := updaters.NewNotifications(mockRepo, mockClient, "")
+
+	channelID := "channel-123"
+	order := &models.PurchaseOrder{
+		ID:           1,
+		BuyerUserID:  100,
+		BuyerName:    "Alice",
+		SellerUserID: 200,
+		TotalPrice:   7000.0,
+		CreatedAt:    time.Now(),
+		Purchases: []*models.PurchaseTransaction{
+			{TypeName: "Tritanium", QuantityPurchased: 1000, TotalPrice: 5000.0},
+			{TypeName: "Pyerite", QuantityPurchased: 200, TotalPrice: 2000.0},
+		},
+	}
+
+	targets := []*models.DiscordNotificationTarget{
+		{ID: 1, UserID: 200, TargetType: "channel", ChannelID: &channelID, IsActive: true},
+	}
+
+	mockRepo.On("GetActiveTargetsForEvent", mock.Anything, int64(200), "purchase_created").Return(targets, nil)
+	mockClient.On("SendChannelMessage", mock.Anything, "channel-123", mock.MatchedBy(func(embed *client.DiscordEmbed) bool {
+		return embed.Title == "New Order" &&
+			embed.Fields[0].Name == "Items (2)" &&
+			embed.Fields[0].Value == "1,000 × Tritanium\n200 × Pyerite\n"
+	})).Return(nil)
+
+	notifier.NotifyPurchaseOrder(context.Background(), order)
+
+	mockRepo.AssertExpectations(t)
+	mockClient.AssertExpectations(t)
+}
+
+func Test_NotifyOrderContractCreated_NoTargets(t *testing.T) {
+	mockRepo := new(MockNotificationsDiscordRepo)
+	mockClient := new(MockDiscordClient)
+
+	notifier := updaters.NewNotifications(mockRepo, mockClient, "")
+
+	mockRepo.On("GetActiveTargetsForEvent", mock.Anything, int64(100), "contract_created").Return([]*models.DiscordNotificationTarget{}, nil)
+
+	notifier.NotifyOrderContractCreated(context.Background(), &models.PurchaseOrder{BuyerUserID: 100, SellerUserID: 200})
+
+	mockRepo.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "SendDM")
+	mockClient.AssertNotCalled(t, "SendChannelMessage")
+}
+
+func Test_NotifyContractDisputed_NotifiesBuyerAndSeller(t *testing.T) {
+	mockRepo := new(MockNotificationsDiscordRepo)
+	mockClient := new(MockDiscordClient)
+
+	notifier := updaters.NewNotifications(mockRepo, mockClient, "")
+
+	buyerChannel := "buyer-channel"
+	sellerChannel := "seller-channel"
+	dispute := &updaters.ContractDispute{
+		ContractKey:   "PT-30",
+		EveContractID: 30303,
+		BuyerUserID:   100,
+		SellerUserID:  200,
+		Purchases: []*models.PurchaseTransaction{
+			{TypeName: "Tritanium", QuantityPurchased: 1000},
+		},
+		Reasons: []string{"short 400 × Tritanium (received 600 of 1000)"},
+	}
+
+	mockRepo.On("GetActiveTargetsForEvent", mock.Anything, int64(100), "contract_disputed").Return([]*models.DiscordNotificationTarget{
+		{ID: 1, UserID: 100, TargetType: "channel", ChannelID: &buyerChannel, IsActive: true},
+	}, nil)
+	mockRepo.On("GetActiveTargetsForEvent", mock.Anything, int64(200), "contract_disputed").Return([]*models.DiscordNotificationTarget{
+		{ID: 2, UserID: 200, TargetType: "channel", ChannelID: &sellerChannel, IsActive: true},
+	}, nil)
+
+	matchesEmbed := mock.MatchedBy(func(embed *client.DiscordEmbed) bool {
+		return embed.Title == "Contract Disputed" &&
+			embed.Fields[0].Value == "• short 400 × Tritanium (received 600 of 1000)"
+	})
+	mockClient.On("SendChannelMessage", mock.Anything, "buyer-channel", matchesEmbed).Return(nil)
+	mockClient.On("SendChannelMessage", mock.Anything, "seller-channel", matchesEmbed).Return(nil)
+
+	notifier.NotifyContractDisputed(context.Background(), dispute)
+
+	mockRepo.AssertExpectations(t)
+	mockClient.AssertExpectations(t)
+}
+
+func Test_NotifyPurchaseExpired_BlamesBuyerForMissedAcceptance(t *testing.T) {
+	mockRepo := new(MockNotificationsDiscordRepo)
+	mockClient := new(MockDiscordClient)
+
+	notifier := updaters.NewNotifications(mockRepo, mockClient, "")
+
+	sellerChannel := "seller-channel"
+	purchase := &models.PurchaseTransaction{
+		ID:                1,
+		BuyerUserID:       100,
+		BuyerName:         "Alice",
+		SellerUserID:      200,
+		SellerName:        "Bob",
+		TypeName:          "Tritanium",
+		QuantityPurchased: 1000,
+		TotalPrice:        5000.0,
+	}
+
+	mockRepo.On("GetActiveTargetsForEvent", mock.Anything, int64(100), "purchase_expired").Return([]*models.DiscordNotificationTarget{}, nil)
+	mockRepo.On("GetActiveTargetsForEvent", mock.Anything, int64(200), "purchase_expired").Return([]*models.DiscordNotificationTarget{
+		{ID: 2, UserID: 200, TargetType: "channel", ChannelID: &sellerChannel, IsActive: true},
+	}, nil)
+	mockClient.On("SendChannelMessage", mock.Anything, "seller-channel", mock.MatchedBy(func(embed *client.DiscordEmbed) bool {
+		return embed.Title == "Purchase Expired" &&
+			strings.HasPrefix(embed.Description, "**Alice** did not accept **Bob**'s contract in time")
+	})).Return(nil)
+
+	notifier.NotifyPurchaseExpired(context.Background(), purchase, "contract_created")
+
+	mockRepo.AssertExpectations(t)
+	mockClient.AssertExpectations(t)
+}
+
+func Test_NotifyPurchaseOffer_SendsCounterToOtherSide(t *testing.T) {
+	mockRepo := new(MockNotificationsDiscordRepo)
+	mockClient := new(MockDiscordClient)
+
+	notifier := updaters.NewNotifications(mockRepo, mockClient, "")
+
+	buyerChannel := "buyer-channel"
+	message := "best I can do"
+	offer := &models.PurchaseOffer{
+		ID:           1,
+		BuyerUserID:  100,
+		BuyerName:    "Alice",
+		SellerUserID: 200,
+		SellerName:   "Bob",
+		TypeName:     "Tritanium",
+		Quantity:     1000,
+		PricePerUnit: 4.5,
+		ListPrice:    5,
+		Status:       "open",
+		Rounds: []*models.PurchaseOfferRound{
+			{UserID: 100, Action: "proposed", Quantity: 1000, PricePerUnit: 4},
+			{UserID: 200, Action: "countered", Quantity: 1000, PricePerUnit: 4.5, Message: &message},
+		},
+	}
+
+	// Only the buyer hears about the seller's counter
+	mockRepo.On("GetActiveTargetsForEvent", mock.Anything, int64(100), "purchase_offer").Return([]*models.DiscordNotificationTarget{
+		{ID: 1, UserID: 100, TargetType: "channel", ChannelID: &buyerChannel, IsActive: true},
+	}, nil)
+	mockClient.On("SendChannelMessage", mock.Anything, "buyer-channel", mock.MatchedBy(func(embed *client.DiscordEmbed) bool {
+		last := embed.Fields[len(embed.Fields)-1]
+		return embed.Title == "Counter-Offer" &&
+			strings.HasPrefix(embed.Description, "**Bob** countered") &&
+			last.Name == "Message" && last.Value == message
+	})).Return(nil)
+
+	notifier.NotifyPurchaseOffer(context.Background(), offer, 200, "countered")
+
+	mockRepo.AssertExpectations(t)
+	mockClient.AssertExpectations(t)
+}
+
+func Test_NotifyCourierContractAlert_SendsFailedContract(t *testing.T) {
+	mockRepo := new(MockNotificationsDiscordRepo)
+	mockClient := new(MockDiscordClient)
+
+	notifier := updaters.NewNotifications(mockRepo, mockClient, "")
+
+	channel := "hauling-channel"
+	alert := &updaters.CourierContractAlert{
+		Job: &models.TransportJob{
+			ID:                    7,
+			UserID:                100,
+			ContractKey:           "TJ-7",
+			OriginSystemName:      "Jita",
+			DestinationSystemName: "Amarr",
+			TotalCollateral:       1000000000,
+		},
+		EveContractID:  70707,
+		ContractStatus: "failed",
+	}
+
+	mockRepo.On("GetActiveTargetsForEvent", mock.Anything, int64(100), "courier_contract_alert").Return([]*models.DiscordNotificationTarget{
+		{ID: 1, UserID: 100, TargetType: "channel", ChannelID: &channel, IsActive: true},
+	}, nil)
+	mockClient.On("SendChannelMessage", mock.Anything, "hauling-channel", mock.MatchedBy(func(embed *client.DiscordEmbed) bool {
+		return embed.Title == "Courier Contract Failed" &&
+			embed.Fields[0].Value == "Jita → Amarr" &&
+			embed.Fields[1].Value == "`TJ-7`"
+	})).Return(nil)
+
+	notifier.NotifyCourierContractAlert(context.Background(), alert)
+
+	mockRepo.AssertExpectations(t)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,119 @@
+package updaters
+
+import (
+	"context"
+	"database/sql"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type PurchaseExpiryPurchaseRepository interface {
+	GetOverdue(ctx context.Context) ([]*models.PurchaseTransaction, error)
+	Expire(ctx context.Context, tx *sql.Tx, purchaseID int64, fromStatus string) (bool, error)
+}
+
+type PurchaseExpiryForSaleRepository interface {
+	GetByID(ctx context.Context, itemID int64) (*models.ForSaleItem, error)
+	UpdateQuantity(ctx context.Context, tx *sql.Tx, itemID int64, newQuantity int64) error
+}
+
+// PurchaseExpiredNotifier is notified when a purchase misses its seller's SLA
+type PurchaseExpiredNotifier interface {
+	NotifyPurchaseExpired(ctx context.Context, purchase *models.PurchaseTransaction, expiredFrom string)
+}
+
+type PurchaseExpiry struct {
+	db           *sql.DB
+	purchaseRepo PurchaseExpiryPurchaseRepository
+	forSaleRepo  PurchaseExpiryForSaleRepository
+	notifier     PurchaseExpiredNotifier
+}
+
+func NewPurchaseExpiry(
+	db *sql.DB,
+	purchaseRepo PurchaseExpiryPurchaseRepository,
+	forSaleRepo PurchaseExpiryForSaleRepository,
+) *PurchaseExpiry {
+	return &PurchaseExpiry{
+		db:           db,
+		purchaseRepo: purchaseRepo,
+		forSaleRepo:  forSaleRepo,
+	}
+}
+
+// WithNotifier sets the optional notifier told about expired purchases.
+func (u *PurchaseExpiry) WithNotifier(notifier PurchaseExpiredNotifier) {
+	u.notifier = notifier
+}
+
+// ExpireAll expires every purchase past its seller's SLA, restoring the purchased quantity to
+// the listing the way a cancel does, and notifies buyer and seller.
+func (u *PurchaseExpiry) ExpireAll(ctx context.Context) error {
+	purchases, err := u.purchaseRepo.GetOverdue(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get overdue purchases")
+	}
+
+	if len(purchases) == 0 {
+		return nil
+	}
+
+	log.Info("purchase expiry: expiring overdue purchases", "count", len(purchases))
+
+	for _, purchase := range purchases {
+		expiredFrom := purchase.Status
+		expired, err := u.expirePurchase(ctx, purchase)
+		if err != nil {
+			log.Error("purchase expiry: failed to expire purchase", "purchaseID", purchase.ID, "error", err)
+			continue
+		}
+		if !expired {
+			continue
+		}
+
+		purchase.Status = "expired"
+		log.Info("purchase expiry: expired purchase",
+			"purchaseID", purchase.ID, "expiredFrom", expiredFrom,
+			"buyerUserID", purchase.BuyerUserID, "sellerUserID", purchase.SellerUserID)
+
+		if u.notifier != nil {
+			u.notifier.NotifyPurchaseExpired(ctx, purchase, expiredFrom)
+		}
+	}
+
+	return nil
+}
+
+func (u *PurchaseExpiry) expirePurchase(ctx context.Context, purchase *models.PurchaseTransaction) (bool, error) {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	expired, err := u.purchaseRepo.Expire(ctx, tx, purchase.ID, purchase.Status)
+	if err != nil {
+		return false, err
+	}
+	if !expired {
+		// Completed or cancelled since it was found overdue
+		return false, nil
+	}
+
+	// Restore the quantity if the listing still exists and is active
+	item, err := u.forSaleRepo.GetByID(ctx, purchase.ForSaleItemID)
+	if err == nil && item.IsActive {
+		err = u.forSaleRepo.UpdateQuantity(ctx, tx, item.ID, item.QuantityAvailable+purchase.QuantityPurchased)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to restore quantity")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, errors.Wrap(err, "failed to commit expiry")
+	}
+
+	return true, nil
+}
@@ -0,0 +1,141 @@
+package updaters_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/updaters"
+	"github.com/stretchr/testify/assert"
+)
+
+// --- Mocks ---
+
+type mockPurchaseExpiryPurchaseRepo struct {
+	overdue    []*models.PurchaseTransaction
+	overdueErr error
+	expired    map[int64]string // purchaseID -> status expired from
+	moved      map[int64]bool   // purchases that left their status before expiry
+}
+
+func (m *mockPurchaseExpiryPurchaseRepo) GetOverdue(ctx context.Context) ([]*models.PurchaseTransaction, error) {
+	return m.overdue, m.overdueErr
+}
+
+func (m *mockPurchaseExpiryPurchaseRepo) Expire(ctx context.Context, tx *sql.Tx, purchaseID int64, fromStatus string) (bool, error) {
+	if m.moved[purchaseID] {
+		return false, nil
+	}
+	if m.expired == nil {
+		m.expired = map[int64]string{}
+	}
+	m.expired[purchaseID] = fromStatus
+	return true, nil
+}
+
+type mockPurchaseExpiryForSaleRepo struct {
+	byID    map[int64]*models.ForSaleItem
+	updated map[int64]int64 // itemID -> newQuantity
+}
+
+func (m *mockPurchaseExpiryForSaleRepo) GetByID(ctx context.Context, itemID int64) (*models.ForSaleItem, error) {
+	if item, ok := m.byID[itemID]; ok {
+		return item, nil
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+func (m *mockPurchaseExpiryForSaleRepo) UpdateQuantity(ctx context.Context, tx *sql.Tx, itemID int64, newQuantity int64) error {
+	if m.updated == nil {
+		m.updated = map[int64]int64{}
+	}
+	m.updated[itemID] = newQuantity
+	return nil
+}
+
+type expiredNotification struct {
+	PurchaseID  int64
+	ExpiredFrom string
+}
+
+type mockPurchaseExpiredNotifier struct {
+	notified []expiredNotification
+}
+
+func (m *mockPurchaseExpiredNotifier) NotifyPurchaseExpired(ctx context.Context, purchase *models.PurchaseTransaction, expiredFrom string) {
+	m.notified = append(m.notified, expiredNotification{PurchaseID: purchase.ID, ExpiredFrom: expiredFrom})
+}
+
+// --- Tests ---
+
+func Test_PurchaseExpiry_ExpiresAndRestoresQuantity(t *testing.T) {
+	db, dbMock := newMockDB(t, 2)
+	defer db.Close()
+
+	purchaseRepo := &mockPurchaseExpiryPurchaseRepo{
+		overdue: []*models.PurchaseTransaction{
+			{ID: 1, ForSaleItemID: 10, BuyerUserID: 100, SellerUserID: 200, QuantityPurchased: 50, Status: "pending"},
+			{ID: 2, ForSaleItemID: 11, BuyerUserID: 100, SellerUserID: 200, QuantityPurchased: 5, Status: "contract_created"},
+		},
+	}
+	forSaleRepo := &mockPurchaseExpiryForSaleRepo{
+		byID: map[int64]*models.ForSaleItem{
+			10: {ID: 10, QuantityAvailable: 100, IsActive: true},
+			11: {ID: 11, QuantityAvailable: 0, IsActive: false},
+		},
+	}
+	notifier := &mockPurchaseExpiredNotifier{}
+
+	u := updaters.NewPurchaseExpiry(db, purchaseRepo, forSaleRepo)
+	u.WithNotifier(notifier)
+
+	err := u.ExpireAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]string{1: "pending", 2: "contract_created"}, purchaseRepo.expired)
+	// Active listing gets its quantity back, inactive listing is left alone like a cancel
+	assert.Equal(t, map[int64]int64{10: 150}, forSaleRepo.updated)
+	assert.Equal(t, []expiredNotification{
+		{PurchaseID: 1, ExpiredFrom: "pending"},
+		{PurchaseID: 2, ExpiredFrom: "contract_created"},
+	}, notifier.notified)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func Test_PurchaseExpiry_SkipsPurchaseThatMovedOn(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	dbMock.ExpectBegin()
+	dbMock.ExpectRollback()
+
+	purchaseRepo := &mockPurchaseExpiryPurchaseRepo{
+		overdue: []*models.PurchaseTransaction{
+			{ID: 1, ForSaleItemID: 10, QuantityPurchased: 50, Status: "pending"},
+		},
+		moved: map[int64]bool{1: true},
+	}
+	forSaleRepo := &mockPurchaseExpiryForSaleRepo{
+		byID: map[int64]*models.ForSaleItem{10: {ID: 10, QuantityAvailable: 100, IsActive: true}},
+	}
+	notifier := &mockPurchaseExpiredNotifier{}
+
+	u := updaters.NewPurchaseExpiry(db, purchaseRepo, forSaleRepo)
+	u.WithNotifier(notifier)
+
+	assert.NoError(t, u.ExpireAll(context.Background()))
+	assert.Empty(t, forSaleRepo.updated)
+	assert.Empty(t, notifier.notified)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func Test_PurchaseExpiry_GetOverdueError(t *testing.T) {
+	purchaseRepo := &mockPurchaseExpiryPurchaseRepo{overdueErr: fmt.Errorf("db error")}
+
+	u := updaters.NewPurchaseExpiry(nil, purchaseRepo, &mockPurchaseExpiryForSaleRepo{})
+
+	assert.Error(t, u.ExpireAll(context.Background()))
+}
@@ -48,6 +48,10 @@ type SdeSolarSystemRepository interface {
 	Upsert(ctx context.Context, systems []models.SolarSystem) error
 }
 
+type SdeStargateRepository interface {
+	Upsert(ctx context.Context, stargates []models.Stargate) error
+}
+
 type SdeStationRepository interface {
 	Upsert(ctx context.Context, stations []models.Station) error
 	GetStationsWithEmptyNames(ctx context.Context) ([]int64, error)
@@ -67,6 +71,7 @@ type Sde struct {
 	constellationRepository SdeConstellationRepository
 	solarSystemRepository   SdeSolarSystemRepository
 	stationRepository       SdeStationRepository
+	stargateRepository      SdeStargateRepository
 }
 
 func NewSde(
@@ -78,6 +83,7 @@ func NewSde(
 	constellationRepository SdeConstellationRepository,
 	solarSystemRepository SdeSolarSystemRepository,
 	stationRepository SdeStationRepository,
+	stargateRepository SdeStargateRepository,
 ) *Sde {
 	return &Sde{
 		client:                  client,
@@ -88,6 +94,7 @@ func NewSde(
 		constellationRepository: constellationRepository,
 		solarSystemRepository:   solarSystemRepository,
 		stationRepository:       stationRepository,
+		stargateRepository:      stargateRepository,
 	}
 }
 
@@ -149,6 +156,10 @@ func (u *Sde) Update(ctx context.Context) error {
 		return errors.Wrap(err, "failed to upsert stations")
 	}
 
+	if err := u.stargateRepository.Upsert(ctx, data.Stargates); err != nil {
+		return errors.Wrap(err, "failed to upsert stargates")
+	}
+
 	// Resolve empty NPC station names from ESI
 	emptyNameIDs, err := u.stationRepository.GetStationsWithEmptyNames(ctx)
 	if err != nil {
@@ -140,6 +140,16 @@ func (m *mockStationRepo) UpdateNames(ctx context.Context, names map[int64]strin
 	return nil
 }
 
+type mockStargateRepo struct {
+	err       error
+	stargates []models.Stargate
+}
+
+func (m *mockStargateRepo) Upsert(ctx context.Context, stargates []models.Stargate) error {
+	m.stargates = stargates
+	return m.err
+}
+
 type mockSdeEsiClient struct{}
 
 func (m *mockSdeEsiClient) GetUniverseNames(ctx context.Context, ids []int64) (map[int64]string, error) {
@@ -158,7 +168,7 @@ func Test_SdeUpdater_FullUpdateWhenChecksumDiffers(t *testing.T) {
 	}
 	repo := newMockSdeDataRepo()
 
-	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{})
+	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{}, &mockStargateRepo{})
 
 	err := u.Update(context.Background())
 	assert.NoError(t, err)
@@ -174,7 +184,7 @@ func Test_SdeUpdater_SkipsWhenChecksumMatches(t *testing.T) {
 	repo := newMockSdeDataRepo()
 	repo.metadata["checksum"] = &models.SdeMetadata{Key: "checksum", Value: "existing-checksum"}
 
-	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{})
+	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{}, &mockStargateRepo{})
 
 	err := u.Update(context.Background())
 	assert.NoError(t, err)
@@ -189,7 +199,7 @@ func Test_SdeUpdater_ErrorGettingChecksum(t *testing.T) {
 	}
 	repo := newMockSdeDataRepo()
 
-	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{})
+	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{}, &mockStargateRepo{})
 
 	err := u.Update(context.Background())
 	assert.Error(t, err)
@@ -203,7 +213,7 @@ func Test_SdeUpdater_ErrorGettingStoredMetadata(t *testing.T) {
 	repo := newMockSdeDataRepo()
 	repo.getMetaErr = fmt.Errorf("db error")
 
-	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{})
+	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{}, &mockStargateRepo{})
 
 	err := u.Update(context.Background())
 	assert.Error(t, err)
@@ -217,7 +227,7 @@ func Test_SdeUpdater_ErrorDownloading(t *testing.T) {
 	}
 	repo := newMockSdeDataRepo()
 
-	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{})
+	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{}, &mockStargateRepo{})
 
 	err := u.Update(context.Background())
 	assert.Error(t, err)
@@ -232,7 +242,7 @@ func Test_SdeUpdater_ErrorParsing(t *testing.T) {
 	}
 	repo := newMockSdeDataRepo()
 
-	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{})
+	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{}, &mockStargateRepo{})
 
 	err := u.Update(context.Background())
 	assert.Error(t, err)
@@ -247,13 +257,48 @@ func Test_SdeUpdater_ErrorUpsertingRegions(t *testing.T) {
 	}
 	repo := newMockSdeDataRepo()
 
-	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{err: fmt.Errorf("db error")}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{})
+	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{err: fmt.Errorf("db error")}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{}, &mockStargateRepo{})
 
 	err := u.Update(context.Background())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to upsert regions")
 }
 
+func Test_SdeUpdater_UpsertsStargates(t *testing.T) {
+	data := emptySdeData()
+	data.Stargates = []models.Stargate{
+		{ID: 50001248, SolarSystemID: 30000142, DestinationStargateID: 50001249, DestinationSolarSystemID: 30000144},
+	}
+	sdeClient := &mockSdeClient{
+		checksumResult: "new-checksum",
+		downloadResult: "/tmp/fake.zip",
+		parseResult:    data,
+	}
+	repo := newMockSdeDataRepo()
+	stargateRepo := &mockStargateRepo{}
+
+	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{}, stargateRepo)
+
+	err := u.Update(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, data.Stargates, stargateRepo.stargates)
+}
+
+func Test_SdeUpdater_ErrorUpsertingStargates(t *testing.T) {
+	sdeClient := &mockSdeClient{
+		checksumResult: "new-checksum",
+		downloadResult: "/tmp/fake.zip",
+		parseResult:    emptySdeData(),
+	}
+	repo := newMockSdeDataRepo()
+
+	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{}, &mockStargateRepo{err: fmt.Errorf("db error")})
+
+	err := u.Update(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to upsert stargates")
+}
+
 func Test_SdeUpdater_ErrorUpsertingItemTypes(t *testing.T) {
 	sdeClient := &mockSdeClient{
 		checksumResult: "new-checksum",
@@ -262,7 +307,7 @@ func Test_SdeUpdater_ErrorUpsertingItemTypes(t *testing.T) {
 	}
 	repo := newMockSdeDataRepo()
 
-	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{err: fmt.Errorf("db error")}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{})
+	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{err: fmt.Errorf("db error")}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{}, &mockStargateRepo{})
 
 	err := u.Update(context.Background())
 	assert.Error(t, err)
@@ -278,7 +323,7 @@ func Test_SdeUpdater_ErrorSettingChecksum(t *testing.T) {
 	repo := newMockSdeDataRepo()
 	repo.setMetaErr = fmt.Errorf("db error")
 
-	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{})
+	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{}, &mockStargateRepo{})
 
 	err := u.Update(context.Background())
 	assert.Error(t, err)
@@ -294,7 +339,7 @@ func Test_SdeUpdater_FirstRunWithNoStoredChecksum(t *testing.T) {
 	repo := newMockSdeDataRepo()
 	// No stored checksum — nil metadata
 
-	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{})
+	u := updaters.NewSde(sdeClient, &mockSdeEsiClient{}, repo, &mockItemTypeRepo{}, &mockRegionRepo{}, &mockConstellationRepo{}, &mockSolarSystemRepo{}, &mockStationRepo{}, &mockStargateRepo{})
 
 	err := u.Update(context.Background())
 	assert.NoError(t, err)
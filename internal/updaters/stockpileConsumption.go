@@ -0,0 +1,67 @@
+package updaters
+
+import (
+	"context"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/calculator"
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type StockpileConsumptionRepository interface {
+	RecordSnapshots(ctx context.Context) error
+	GetAutoAdjustConsumption(ctx context.Context, windowDays int) ([]*models.StockpileConsumption, error)
+	SetDesiredQuantity(ctx context.Context, markerID int64, quantity int64) error
+}
+
+// StockpileConsumptionUpdater snapshots the held quantity of every stockpile marker and moves the
+// desired quantity of auto-adjusting markers to their suggested cover, so auto-buy and
+// auto-production see a deficit before the stock runs out.
+type StockpileConsumptionUpdater struct {
+	repo StockpileConsumptionRepository
+	now  func() time.Time
+}
+
+func NewStockpileConsumptionUpdater(repo StockpileConsumptionRepository) *StockpileConsumptionUpdater {
+	return &StockpileConsumptionUpdater{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+func (u *StockpileConsumptionUpdater) RunAll(ctx context.Context) error {
+	if err := u.repo.RecordSnapshots(ctx); err != nil {
+		return errors.Wrap(err, "failed to record stockpile snapshots")
+	}
+
+	consumption, err := u.repo.GetAutoAdjustConsumption(ctx, calculator.StockpileConsumptionWindowDays)
+	if err != nil {
+		return errors.Wrap(err, "failed to get auto-adjust stockpile consumption")
+	}
+
+	now := u.now()
+	adjusted := 0
+	for _, c := range consumption {
+		calculator.CalculateStockpileConsumption(c, calculator.StockpileConsumptionWindowDays, calculator.DefaultStockpileCoverDays, now)
+		if c.SuggestedDesiredQuantity == nil || *c.SuggestedDesiredQuantity == c.DesiredQuantity {
+			continue
+		}
+
+		if err := u.repo.SetDesiredQuantity(ctx, c.MarkerID, *c.SuggestedDesiredQuantity); err != nil {
+			log.Error("stockpile consumption: failed to adjust marker", "marker_id", c.MarkerID, "error", err)
+			continue
+		}
+		log.Info("stockpile consumption: adjusted marker",
+			"marker_id", c.MarkerID,
+			"type_id", c.TypeID,
+			"from", c.DesiredQuantity,
+			"to", *c.SuggestedDesiredQuantity,
+			"burn_rate", c.BurnRate)
+		adjusted++
+	}
+
+	log.Info("stockpile consumption: run complete", "markers", len(consumption), "adjusted", adjusted)
+	return nil
+}
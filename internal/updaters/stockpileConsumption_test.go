@@ -0,0 +1,61 @@
+package updaters_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/updaters"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockStockpileConsumptionRepo struct {
+	snapshotErr error
+	consumption []*models.StockpileConsumption
+	adjusted    map[int64]int64
+}
+
+func (m *mockStockpileConsumptionRepo) RecordSnapshots(ctx context.Context) error {
+	return m.snapshotErr
+}
+
+func (m *mockStockpileConsumptionRepo) GetAutoAdjustConsumption(ctx context.Context, windowDays int) ([]*models.StockpileConsumption, error) {
+	return m.consumption, nil
+}
+
+func (m *mockStockpileConsumptionRepo) SetDesiredQuantity(ctx context.Context, markerID int64, quantity int64) error {
+	if m.adjusted == nil {
+		m.adjusted = map[int64]int64{}
+	}
+	m.adjusted[markerID] = quantity
+	return nil
+}
+
+func Test_StockpileConsumption_AdjustsMarkers(t *testing.T) {
+	coverDays := 10
+	repo := &mockStockpileConsumptionRepo{
+		consumption: []*models.StockpileConsumption{
+			// 500/day for 10 days of cover
+			{MarkerID: 1, DesiredQuantity: 1000, HistoryDays: 4, AssetConsumed: 2000, CoverDays: &coverDays},
+			// already at its suggestion
+			{MarkerID: 2, DesiredQuantity: 5000, HistoryDays: 4, AssetConsumed: 2000, CoverDays: &coverDays},
+			// not enough history to suggest anything
+			{MarkerID: 3, DesiredQuantity: 700, HistoryDays: 1, AssetConsumed: 100},
+		},
+	}
+
+	err := updaters.NewStockpileConsumptionUpdater(repo).RunAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int64{1: 5000}, repo.adjusted)
+}
+
+func Test_StockpileConsumption_SnapshotError(t *testing.T) {
+	repo := &mockStockpileConsumptionRepo{snapshotErr: fmt.Errorf("db down")}
+
+	err := updaters.NewStockpileConsumptionUpdater(repo).RunAll(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, repo.adjusted)
+}